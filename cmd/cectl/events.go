@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"sigs.k8s.io/yaml"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	workpayload "open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
+)
+
+// loadEvent loads a cloudevent from a YAML file, the file has the structured format of the cloudevents, e.g.
+//
+//	type: io.open-cluster-management.works.v1alpha1.manifestbundles.spec.create_request
+//	clustername: cluster1
+//	resourceid: 1a2b3c
+//	resourceversion: 1
+//	data:
+//	  manifests: []
+//
+// The specversion, id, source and datacontenttype are set if they are absent, the source is the ID of the client.
+func loadEvent(path, source string) (*cloudevents.Event, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the event file %s, %v", path, err)
+	}
+
+	return parseEvent(data, source)
+}
+
+func parseEvent(data []byte, source string) (*cloudevents.Event, error) {
+	raw := map[string]any{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the event, %v", err)
+	}
+
+	setDefault(raw, "specversion", cloudevents.VersionV1)
+	setDefault(raw, "id", uuid.New().String())
+	setDefault(raw, "source", source)
+	setDefault(raw, "datacontenttype", cloudevents.ApplicationJSON)
+
+	if eventType, ok := raw["type"].(string); ok {
+		if _, err := types.ParseCloudEventsType(eventType); err != nil {
+			return nil, err
+		}
+	}
+
+	eventJSON, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	evt := cloudevents.NewEvent()
+	if err := evt.UnmarshalJSON(eventJSON); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the event, %v", err)
+	}
+
+	if err := evt.Validate(); err != nil {
+		return nil, fmt.Errorf("the event is invalid, %v", err)
+	}
+
+	return &evt, nil
+}
+
+// eventSchemas returns the schemas of the events of the data types that are supported by this SDK.
+func eventSchemas() *schema.Registry {
+	return schema.NewRegistry(workpayload.ManifestEventSchema, workpayload.ManifestBundleEventSchema)
+}
+
+func setDefault(raw map[string]any, key string, val any) {
+	if _, ok := raw[key]; !ok {
+		raw[key] = val
+	}
+}
+
+// printEvent returns a receiver that prints the received events to the writer with the indented JSON.
+func printEvent(w io.Writer) func(ctx context.Context, evt cloudevents.Event) {
+	return func(ctx context.Context, evt cloudevents.Event) {
+		data, err := json.MarshalIndent(evt, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to print the event %s, %v\n", evt.ID(), err)
+			return
+		}
+
+		fmt.Fprintf(w, "%s\n", data)
+	}
+}
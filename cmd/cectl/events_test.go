@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+func TestParseEvent(t *testing.T) {
+	cases := []struct {
+		name           string
+		data           string
+		expectedErr    bool
+		expectedID     string
+		expectedSource string
+	}{
+		{
+			name: "defaults are set",
+			data: `
+type: io.open-cluster-management.works.v1alpha1.manifestbundles.spec.create_request
+clustername: cluster1
+resourceid: test
+resourceversion: 1
+data:
+  manifests: []
+`,
+			expectedSource: "cectl",
+		},
+		{
+			name: "the id and source are kept",
+			data: `
+id: "1"
+source: source1
+type: io.open-cluster-management.works.v1alpha1.manifestbundles.spec.create_request
+`,
+			expectedID:     "1",
+			expectedSource: "source1",
+		},
+		{
+			name: "invalid event type",
+			data: `
+type: test
+`,
+			expectedErr: true,
+		},
+		{
+			name:        "no event type",
+			data:        `clustername: cluster1`,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evt, err := parseEvent([]byte(c.data), "cectl")
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if len(evt.ID()) == 0 {
+				t.Errorf("expected id, but got empty")
+			}
+			if len(c.expectedID) != 0 && evt.ID() != c.expectedID {
+				t.Errorf("expected %s, but got %s", c.expectedID, evt.ID())
+			}
+			if evt.Source() != c.expectedSource {
+				t.Errorf("expected %s, but got %s", c.expectedSource, evt.Source())
+			}
+			if evt.DataContentType() != cloudevents.ApplicationJSON {
+				t.Errorf("expected %s, but got %s", cloudevents.ApplicationJSON, evt.DataContentType())
+			}
+		})
+	}
+}
+
+func TestPrintEvent(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	evt.SetID("1")
+	evt.SetSource("source1")
+	evt.SetType("test")
+
+	out := &bytes.Buffer{}
+	printEvent(out)(context.Background(), evt)
+
+	if !strings.Contains(out.String(), `"source": "source1"`) {
+		t.Errorf("expected the source in the output, but got %s", out.String())
+	}
+}
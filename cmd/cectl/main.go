@@ -0,0 +1,405 @@
+// cectl is a command line tool to publish and subscribe the cloudevents with the source/agent options of this SDK,
+// it helps to debug the broker ACLs and the codec issues.
+//
+// Usage:
+//
+//	cectl publish --config-type mqtt --config mqtt.yaml --source-id source1 --file event.yaml
+//	cectl subscribe --config-type mqtt --config mqtt.yaml --cluster-name cluster1
+//	cectl resync --config-type grpc --config grpc.yaml --source-id source1 --data-type io.open-cluster-management.works.v1alpha1.manifestbundles
+//	cectl schema --output-dir schemas
+//	cectl validate --file event.yaml
+//	cectl conformance --verify-dir events
+//	cectl acl --config-type mqtt --config mqtt.yaml --source-ids source1 --cluster-names cluster1,cluster2 --format emqx
+//	cectl bridge --bridge-id region1 --from-config-type mqtt --from-config global.yaml --config-type grpc --config region.yaml --source-id source1 --sub-resource spec
+//
+// The client is a source if the --source-id is set, otherwise it is an agent of the cluster that is set by the
+// --cluster-name.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/conformance"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/bridge"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/acl"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work"
+)
+
+const usage = `Usage: cectl <command> [flags]
+
+Commands:
+  publish      publish a cloudevent from a YAML file
+  subscribe    subscribe the cloudevents and print them
+  resync       send a resync request
+  schema       write the JSON schemas of the events
+  validate     validate a cloudevent from a YAML file with the event schemas
+  conformance  write the golden events or verify the events of another implementation with them
+  bridge       relay the cloudevents from one broker to another
+  acl          generate the broker ACLs or the gRPC authorization policy of the sources and agents
+
+Run 'cectl <command> --help' for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var err error
+	switch command, args := os.Args[1], os.Args[2:]; command {
+	case "publish":
+		err = runPublish(ctx, args)
+	case "subscribe":
+		err = runSubscribe(ctx, args)
+	case "resync":
+		err = runResync(ctx, args)
+	case "schema":
+		err = runSchema(args)
+	case "validate":
+		err = runValidate(args)
+	case "conformance":
+		err = runConformance(args)
+	case "bridge":
+		err = runBridge(ctx, args)
+	case "acl":
+		err = runACL(args)
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runPublish(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("publish", flag.ExitOnError)
+	o := newClientOptions(flags)
+	file := flags.String("file", "", "The YAML file of the cloudevent to publish.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*file) == 0 {
+		return fmt.Errorf("the --file is required")
+	}
+
+	cloudEventsOptions, clientID, err := o.cloudEventsOptions()
+	if err != nil {
+		return err
+	}
+
+	evt, err := loadEvent(*file, clientID)
+	if err != nil {
+		return err
+	}
+
+	cloudEventsClient, err := cloudEventsOptions.Client(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect, %v", err)
+	}
+
+	sendingCtx, err := cloudEventsOptions.WithContext(ctx, evt.Context)
+	if err != nil {
+		return err
+	}
+
+	if result := cloudEventsClient.Send(sendingCtx, *evt); cloudevents.IsUndelivered(result) {
+		return fmt.Errorf("failed to publish event %s, %v", evt.ID(), result)
+	}
+
+	fmt.Fprintf(os.Stdout, "event %s is published\n", evt.ID())
+	return nil
+}
+
+func runSubscribe(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("subscribe", flag.ExitOnError)
+	o := newClientOptions(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cloudEventsOptions, _, err := o.cloudEventsOptions()
+	if err != nil {
+		return err
+	}
+
+	cloudEventsClient, err := cloudEventsOptions.Client(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect, %v", err)
+	}
+
+	receiverCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	go func() {
+		select {
+		case err := <-cloudEventsOptions.ErrorChan():
+			errs <- fmt.Errorf("the connection is closed, %v", err)
+			cancel()
+		case <-receiverCtx.Done():
+		}
+	}()
+
+	fmt.Fprintln(os.Stderr, "waiting for events, press Ctrl+C to exit")
+	if err := cloudEventsClient.StartReceiver(receiverCtx, printEvent(os.Stdout)); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return nil
+	}
+}
+
+func runResync(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("resync", flag.ExitOnError)
+	o := newClientOptions(flags)
+	dataType := flags.String("data-type", "", "The cloudevents data type of the resync request, "+
+		"e.g. io.open-cluster-management.works.v1alpha1.manifestbundles.")
+	target := flags.String("target", "", "The cluster name for a source or the source ID for an agent to send "+
+		"the resync request to, by default, the request is sent to all the clusters or sources.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*dataType) == 0 {
+		return fmt.Errorf("the --data-type is required")
+	}
+
+	if err := o.resync(ctx, *dataType, *target); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stdout, "the resync request is sent")
+	return nil
+}
+
+func runSchema(args []string) error {
+	flags := flag.NewFlagSet("schema", flag.ExitOnError)
+	outputDir := flags.String("output-dir", "", "The directory to write the JSON schema files of the events.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*outputDir) == 0 {
+		return fmt.Errorf("the --output-dir is required")
+	}
+
+	if err := eventSchemas().WriteFiles(*outputDir); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "the schemas are written to %s\n", *outputDir)
+	return nil
+}
+
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	file := flags.String("file", "", "The YAML file of the cloudevent to validate.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*file) == 0 {
+		return fmt.Errorf("the --file is required")
+	}
+
+	evt, err := loadEvent(*file, "cectl")
+	if err != nil {
+		return err
+	}
+
+	if err := eventSchemas().Validate(*evt); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "event %s is valid\n", evt.ID())
+	return nil
+}
+
+func runConformance(args []string) error {
+	flags := flag.NewFlagSet("conformance", flag.ExitOnError)
+	writeDir := flags.String("write-dir", "", "The directory to write the golden event files.")
+	verifyDir := flags.String("verify-dir", "", "The directory of the event files that are produced by another "+
+		"implementation from the golden event files, the files have the same names as the golden files.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*writeDir) == 0 && len(*verifyDir) == 0 {
+		return fmt.Errorf("either the --write-dir or the --verify-dir is required")
+	}
+
+	if len(*writeDir) != 0 {
+		if err := conformance.WriteGoldenFiles(*writeDir); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "the golden events are written to %s\n", *writeDir)
+	}
+
+	if len(*verifyDir) != 0 {
+		verifier, err := conformance.NewVerifier()
+		if err != nil {
+			return err
+		}
+		if err := verifier.VerifyDir(*verifyDir); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "the events in %s are verified\n", *verifyDir)
+	}
+
+	return nil
+}
+
+func runBridge(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("bridge", flag.ExitOnError)
+	o := newClientOptions(flags)
+	bridgeID := flags.String("bridge-id", "", "The ID of the bridge, the bridges of both directions between two "+
+		"brokers should use the same ID.")
+	fromConfigType := flags.String("from-config-type", work.ConfigTypeMQTT, "The type of the options file of the "+
+		"broker to receive the events from, mqtt or grpc.")
+	fromConfig := flags.String("from-config", "", "The path of the options file of the broker to receive the events "+
+		"from, the events of all sources and clusters are received. The --config is the options file of the broker "+
+		"to publish the events to as the source of the --source-id or the agent of the --cluster-name.")
+	subResource := flags.String("sub-resource", "", "Only relay the events of the subresource, spec or status, by "+
+		"default, all the events are relayed.")
+	topic := flags.String("topic", "", "The topic template to publish the events to, the {source}, {clustername} "+
+		"and {originalsource} are replaced with the attributes of the event, by default, the topics of the --config "+
+		"are used.")
+	maxHops := flags.Int("max-hops", bridge.DefaultMaxHops, "The number of the bridges that an event can be "+
+		"relayed by.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*bridgeID) == 0 {
+		return fmt.Errorf("the --bridge-id is required")
+	}
+
+	if len(*fromConfig) == 0 {
+		return fmt.Errorf("the --from-config is required")
+	}
+
+	from, err := observerOptions(*fromConfigType, *fromConfig, o.id()+"-observer")
+	if err != nil {
+		return err
+	}
+
+	to, _, err := o.cloudEventsOptions()
+	if err != nil {
+		return err
+	}
+
+	b := bridge.NewBridge(*bridgeID, from, to).WithMaxHops(*maxHops)
+	switch types.EventSubResource(*subResource) {
+	case "":
+	case types.SubResourceSpec, types.SubResourceStatus:
+		b = b.WithFilter(bridge.SubResourceFilter(types.EventSubResource(*subResource)))
+	default:
+		return fmt.Errorf("unsupported subresource %q, it should be spec or status", *subResource)
+	}
+	if len(*topic) != 0 {
+		b = b.WithRouter(bridge.TopicRouter(*topic))
+	}
+
+	fmt.Fprintf(os.Stderr, "relaying events with the bridge %s, press Ctrl+C to exit\n", *bridgeID)
+	b.Run(ctx)
+
+	stats := b.Stats()
+	fmt.Fprintf(os.Stdout, "received %d, relayed %d, filtered %d, looped %d, failed %d events\n",
+		stats.Received, stats.Relayed, stats.Filtered, stats.Looped, stats.Failed)
+	return nil
+}
+
+func runACL(args []string) error {
+	flags := flag.NewFlagSet("acl", flag.ExitOnError)
+	configType := flags.String("config-type", work.ConfigTypeMQTT, "The type of the options file, mqtt or grpc.")
+	configPath := flags.String("config", "", "The path of the mqtt or grpc options file, the topics and the tenant "+
+		"of the options are used to generate the rules.")
+	sourceIDs := flags.String("source-ids", "", "The comma separated source IDs to generate the rules for.")
+	clusterNames := flags.String("cluster-names", "", "The comma separated cluster names to generate the rules of "+
+		"their agents for.")
+	format := flags.String("format", "", "The format of the rules, mosquitto or emqx for mqtt, and policy for grpc, "+
+		"by default, emqx is used for mqtt and policy is used for grpc.")
+	sourceSubject := flags.String("source-subject", "", "The format of the broker subjects of the sources, e.g. "+
+		"%s-controller, by default, the source IDs are used.")
+	agentSubject := flags.String("agent-subject", "", "The format of the broker subjects of the agents, e.g. "+
+		"%s-work-agent, by default, the cluster names are used.")
+	clusterClaim := flags.Bool("cluster-claim", false, "Allow the agents to claim their clusters, only for mqtt.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*configPath) == 0 {
+		return fmt.Errorf("the --config is required")
+	}
+
+	if len(*sourceIDs) == 0 && len(*clusterNames) == 0 {
+		return fmt.Errorf("at least one of the --source-ids and the --cluster-names is required")
+	}
+
+	rules, defaultFormat, err := aclRules(*configType, *configPath,
+		splitList(*sourceIDs), splitList(*clusterNames), *clusterClaim)
+	if err != nil {
+		return err
+	}
+
+	if len(*format) == 0 {
+		*format = defaultFormat
+	}
+
+	// the mosquitto and emqx formats are for mqtt, the policy format is for grpc
+	if (*format == "policy") != (defaultFormat == "policy") {
+		return fmt.Errorf("unsupported format %q for the config type %s", *format, *configType)
+	}
+
+	subjects := acl.Subjects{SourceFormat: *sourceSubject, AgentFormat: *agentSubject}
+	var data []byte
+	switch *format {
+	case "mosquitto":
+		data = acl.Mosquitto(rules, subjects)
+	case "emqx":
+		data = acl.EMQX(rules, subjects)
+	case "policy":
+		if data, err = acl.NewPolicy(rules, subjects).YAML(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported format %q, it should be mosquitto, emqx or policy", *format)
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// splitList splits a comma separated list, the empty items are removed.
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); len(item) != 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/acl"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work"
+)
+
+// clientOptions are the flags to build the source/agent options.
+type clientOptions struct {
+	configType  *string
+	configPath  *string
+	sourceID    *string
+	clusterName *string
+	clientID    *string
+}
+
+func newClientOptions(flags *flag.FlagSet) *clientOptions {
+	return &clientOptions{
+		configType:  flags.String("config-type", work.ConfigTypeMQTT, "The type of the options file, mqtt or grpc."),
+		configPath:  flags.String("config", "", "The path of the mqtt or grpc options file."),
+		sourceID:    flags.String("source-id", "", "The source ID, the client is a source if it is set."),
+		clusterName: flags.String("cluster-name", "", "The cluster name, the client is an agent if it is set."),
+		clientID:    flags.String("client-id", "", "The client ID, by default, a random ID is used."),
+	}
+}
+
+func (o *clientOptions) validate() error {
+	if len(*o.configPath) == 0 {
+		return fmt.Errorf("the --config is required")
+	}
+
+	if len(*o.sourceID) == 0 && len(*o.clusterName) == 0 {
+		return fmt.Errorf("one of the --source-id and the --cluster-name is required")
+	}
+
+	if len(*o.sourceID) != 0 && len(*o.clusterName) != 0 {
+		return fmt.Errorf("only one of the --source-id and the --cluster-name can be set")
+	}
+
+	return nil
+}
+
+func (o *clientOptions) isSource() bool {
+	return len(*o.sourceID) != 0
+}
+
+func (o *clientOptions) id() string {
+	if len(*o.clientID) != 0 {
+		return *o.clientID
+	}
+
+	return fmt.Sprintf("cectl-%s", uuid.New().String())
+}
+
+// cloudEventsOptions returns the cloudevents options of a source or an agent and the ID of the client.
+func (o *clientOptions) cloudEventsOptions() (options.CloudEventsOptions, string, error) {
+	if o.isSource() {
+		sourceOptions, err := o.sourceOptions()
+		if err != nil {
+			return nil, "", err
+		}
+		return sourceOptions.CloudEventsOptions, sourceOptions.SourceID, nil
+	}
+
+	agentOptions, err := o.agentOptions()
+	if err != nil {
+		return nil, "", err
+	}
+	return agentOptions.CloudEventsOptions, agentOptions.AgentID, nil
+}
+
+func (o *clientOptions) sourceOptions() (*options.CloudEventsSourceOptions, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	_, config, err := work.NewConfigLoader(*o.configType, *o.configPath).LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	switch config := config.(type) {
+	case *mqtt.MQTTOptions:
+		return mqtt.NewSourceOptions(config, o.id(), *o.sourceID), nil
+	case *grpc.GRPCOptions:
+		return grpc.NewSourceOptions(config, *o.sourceID), nil
+	default:
+		return nil, fmt.Errorf("unsupported config type %s", *o.configType)
+	}
+}
+
+func (o *clientOptions) agentOptions() (*options.CloudEventsAgentOptions, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	_, config, err := work.NewConfigLoader(*o.configType, *o.configPath).LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	switch config := config.(type) {
+	case *mqtt.MQTTOptions:
+		return mqtt.NewAgentOptions(config, *o.clusterName, o.id()), nil
+	case *grpc.GRPCOptions:
+		return grpc.NewAgentOptions(config, *o.clusterName, o.id()), nil
+	default:
+		return nil, fmt.Errorf("unsupported config type %s", *o.configType)
+	}
+}
+
+// resync sends a resync request of the given data type, the client does not have any resources, so all the
+// resources of the data type are sent back.
+func (o *clientOptions) resync(ctx context.Context, dataType, target string) error {
+	eventDataType, err := types.ParseCloudEventsDataType(dataType)
+	if err != nil {
+		return err
+	}
+
+	gvr := schema.GroupVersionResource{
+		Group:    eventDataType.Group,
+		Version:  eventDataType.Version,
+		Resource: eventDataType.Resource,
+	}
+
+	if o.isSource() {
+		sourceOptions, err := o.sourceOptions()
+		if err != nil {
+			return err
+		}
+		go drainErrors(ctx, sourceOptions.CloudEventsOptions)
+
+		client, err := generic.NewUnstructuredClient(ctx, sourceOptions, gvr)
+		if err != nil {
+			return err
+		}
+		defer client.Close(ctx)
+
+		return client.Resync(ctx, target)
+	}
+
+	agentOptions, err := o.agentOptions()
+	if err != nil {
+		return err
+	}
+	go drainErrors(ctx, agentOptions.CloudEventsOptions)
+
+	lister := generic.NewMemoryResourceStore[*unstructured.Unstructured](generic.UnstructuredClusterName, nil)
+	client, err := generic.NewUnstructuredAgentClient(ctx, agentOptions, lister, gvr)
+	if err != nil {
+		return err
+	}
+	defer client.Close(ctx)
+
+	return client.Resync(ctx, target)
+}
+
+// drainErrors prints the connection errors of the cloudevents options until the context is done.
+func drainErrors(ctx context.Context, cloudEventsOptions options.CloudEventsOptions) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-cloudEventsOptions.ErrorChan():
+			if !ok {
+				return
+			}
+			fmt.Fprintf(os.Stderr, "the connection is closed, %v\n", err)
+		}
+	}
+}
+
+// observerOptions returns the cloudevents options of an observer that receives the events of all sources and clusters
+// with the given options file.
+func observerOptions(configType, configPath, clientID string) (options.CloudEventsOptions, error) {
+	_, config, err := work.NewConfigLoader(configType, configPath).LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	switch config := config.(type) {
+	case *mqtt.MQTTOptions:
+		return mqtt.NewObserverOptions(config, clientID).CloudEventsOptions, nil
+	case *grpc.GRPCOptions:
+		return grpc.NewObserverOptions(config, clientID).CloudEventsOptions, nil
+	default:
+		return nil, fmt.Errorf("unsupported config type %s", configType)
+	}
+}
+
+// aclRules returns the access rules of the sources and the agents with the topics of the given options file and the
+// default format to render the rules, emqx for mqtt and policy for grpc.
+func aclRules(configType, configPath string, sourceIDs, clusterNames []string, clusterClaim bool) ([]acl.Rule, string, error) {
+	_, config, err := work.NewConfigLoader(configType, configPath).LoadConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch config := config.(type) {
+	case *mqtt.MQTTOptions:
+		rules, err := mqtt.ACLRules(config, sourceIDs, clusterNames, clusterClaim)
+		return rules, "emqx", err
+	case *grpc.GRPCOptions:
+		return grpc.AuthorizationRules(config, sourceIDs, clusterNames), "policy", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported config type %s", configType)
+	}
+}
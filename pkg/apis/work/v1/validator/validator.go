@@ -62,3 +62,28 @@ func validateManifest(manifest []byte) error {
 
 	return nil
 }
+
+// ValidateExecutor validates the executor of a ManifestWork, a nil executor is valid and indicates that the work agent
+// applies the manifests with its own identity, otherwise the executor subject must be a ServiceAccount with the
+// namespace and name.
+func ValidateExecutor(executor *workv1.ManifestWorkExecutor) error {
+	if executor == nil {
+		return nil
+	}
+
+	if executor.Subject.Type != workv1.ExecutorSubjectTypeServiceAccount {
+		return fmt.Errorf("executor subject type %q is not supported, only %q is supported",
+			executor.Subject.Type, workv1.ExecutorSubjectTypeServiceAccount)
+	}
+
+	serviceAccount := executor.Subject.ServiceAccount
+	if serviceAccount == nil {
+		return fmt.Errorf("executor subject serviceAccount must be set")
+	}
+
+	if serviceAccount.Namespace == "" || serviceAccount.Name == "" {
+		return fmt.Errorf("executor subject serviceAccount namespace and name must be set")
+	}
+
+	return nil
+}
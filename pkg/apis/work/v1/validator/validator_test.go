@@ -59,3 +59,53 @@ func Test_Validator(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateExecutor(t *testing.T) {
+	cases := []struct {
+		name          string
+		executor      *workv1.ManifestWorkExecutor
+		expectedError bool
+	}{
+		{
+			name: "no executor",
+		},
+		{
+			name: "service account",
+			executor: &workv1.ManifestWorkExecutor{Subject: workv1.ManifestWorkExecutorSubject{
+				Type:           workv1.ExecutorSubjectTypeServiceAccount,
+				ServiceAccount: &workv1.ManifestWorkSubjectServiceAccount{Namespace: "default", Name: "test"},
+			}},
+		},
+		{
+			name: "unsupported subject type",
+			executor: &workv1.ManifestWorkExecutor{Subject: workv1.ManifestWorkExecutorSubject{
+				Type: "User",
+			}},
+			expectedError: true,
+		},
+		{
+			name: "no service account",
+			executor: &workv1.ManifestWorkExecutor{Subject: workv1.ManifestWorkExecutorSubject{
+				Type: workv1.ExecutorSubjectTypeServiceAccount,
+			}},
+			expectedError: true,
+		},
+		{
+			name: "no service account namespace",
+			executor: &workv1.ManifestWorkExecutor{Subject: workv1.ManifestWorkExecutorSubject{
+				Type:           workv1.ExecutorSubjectTypeServiceAccount,
+				ServiceAccount: &workv1.ManifestWorkSubjectServiceAccount{Name: "test"},
+			}},
+			expectedError: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateExecutor(c.executor)
+			if c.expectedError != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedError, err)
+			}
+		})
+	}
+}
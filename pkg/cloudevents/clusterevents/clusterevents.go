@@ -0,0 +1,208 @@
+// Package clusterevents forwards the selected Kubernetes events of the managed clusters from the agents to the sources
+// with the cloud events, so the hub operators can see the failure events without accessing the managed clusters.
+//
+// The forwarded events of a cluster are the status of a ClusterEvents resource, each status update is one event. The
+// agent forwards the events that match a Filter with a Forwarder, the forwarding rate is limited, and the source keeps
+// the recent events of the clusters with a Collector. There is no spec of the ClusterEvents, so the sources never
+// publish the spec events of the ClusterEventDataType.
+package clusterevents
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+var ClusterEventDataType = types.CloudEventsDataType{
+	Group:    "io.open-cluster-management.events",
+	Version:  "v1alpha1",
+	Resource: "clusterevents",
+}
+
+// ClusterEventSchema describes the events of the forwarded cluster events.
+var ClusterEventSchema = schema.EventSchema{
+	DataType: ClusterEventDataType,
+	Status:   schema.For[EventRecord](),
+}
+
+// ClusterEventStatusEventType is the event type of the cluster events that are forwarded by the agents.
+var ClusterEventStatusEventType = types.CloudEventsType{
+	CloudEventsDataType: ClusterEventDataType,
+	SubResource:         types.SubResourceStatus,
+	Action:              "update_request",
+}
+
+// WarningFilter selects the warning events of all namespaces.
+var WarningFilter = Filter{Types: []string{corev1.EventTypeWarning}}
+
+// clusterEventsNamespace is the namespace of the resource IDs of the cluster events.
+var clusterEventsNamespace = uuid.NewSHA1(uuid.NameSpaceOID, []byte(ClusterEventDataType.String()))
+
+// EventRecord is the data of a cluster event, it is the forwarded part of a Kubernetes event.
+type EventRecord struct {
+	// Namespace is the namespace of the event.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the event.
+	Name string `json:"name"`
+
+	// InvolvedObject is the object that the event is about.
+	InvolvedObject corev1.ObjectReference `json:"involvedObject"`
+
+	// Type is the type of the event, e.g. Normal or Warning.
+	Type string `json:"type,omitempty"`
+
+	// Reason is the short, machine understandable reason of the event.
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the human readable description of the event.
+	Message string `json:"message,omitempty"`
+
+	// Count is the number of the times the event has occurred.
+	Count int32 `json:"count,omitempty"`
+
+	// FirstTimestamp is the time when the event was first recorded.
+	FirstTimestamp metav1.Time `json:"firstTimestamp,omitempty"`
+
+	// LastTimestamp is the time when the most recent occurrence of the event was recorded.
+	LastTimestamp metav1.Time `json:"lastTimestamp,omitempty"`
+
+	// ReportingComponent is the component that reports the event, e.g. kubelet.
+	ReportingComponent string `json:"reportingComponent,omitempty"`
+}
+
+// NewEventRecord returns the EventRecord of a Kubernetes event. The event time and the series of the events.k8s.io
+// events are used if the timestamps or the count of the event are not set.
+func NewEventRecord(event *corev1.Event) EventRecord {
+	record := EventRecord{
+		Namespace:          event.Namespace,
+		Name:               event.Name,
+		InvolvedObject:     event.InvolvedObject,
+		Type:               event.Type,
+		Reason:             event.Reason,
+		Message:            event.Message,
+		Count:              event.Count,
+		FirstTimestamp:     event.FirstTimestamp,
+		LastTimestamp:      event.LastTimestamp,
+		ReportingComponent: event.ReportingController,
+	}
+
+	if len(record.ReportingComponent) == 0 {
+		record.ReportingComponent = event.Source.Component
+	}
+
+	if record.FirstTimestamp.IsZero() {
+		record.FirstTimestamp = metav1.NewTime(event.EventTime.Time)
+	}
+
+	if record.LastTimestamp.IsZero() {
+		record.LastTimestamp = record.FirstTimestamp
+		if event.Series != nil {
+			record.LastTimestamp = metav1.NewTime(event.Series.LastObservedTime.Time)
+		}
+	}
+
+	if record.Count == 0 {
+		record.Count = 1
+		if event.Series != nil {
+			record.Count = event.Series.Count
+		}
+	}
+
+	return record
+}
+
+// Filter selects the Kubernetes events that are forwarded, an empty list of a field matches all the values.
+type Filter struct {
+	// Namespaces are the namespaces of the events.
+	Namespaces []string
+
+	// Reasons are the reasons of the events, e.g. FailedScheduling.
+	Reasons []string
+
+	// Types are the types of the events, e.g. Warning.
+	Types []string
+}
+
+// Matches returns true if the event is selected by the filter.
+func (f Filter) Matches(event *corev1.Event) bool {
+	return f.match(event.Namespace, event.Reason, event.Type)
+}
+
+func (f Filter) match(namespace, reason, eventType string) bool {
+	return matches(f.Namespaces, namespace) && matches(f.Reasons, reason) && matches(f.Types, eventType)
+}
+
+func matches(values []string, value string) bool {
+	return len(values) == 0 || slices.Contains(values, value)
+}
+
+// ClusterEvents is the resource object of the forwarded events of a managed cluster, its status is the latest event.
+type ClusterEvents struct {
+	// UID is the resource ID of the cluster events, see ClusterEventsID.
+	UID kubetypes.UID
+
+	// ResourceVersion is the last timestamp of the event in seconds.
+	ResourceVersion string
+
+	// ClusterName is the name of the managed cluster.
+	ClusterName string
+
+	// OriginalSource is the source that the events are forwarded to.
+	OriginalSource string
+
+	// Event is the latest forwarded event of the cluster.
+	Event EventRecord
+}
+
+// NewClusterEvents returns the ClusterEvents of a cluster with the given event, the event is forwarded to the given
+// source.
+func NewClusterEvents(clusterName, source string, event EventRecord) *ClusterEvents {
+	return &ClusterEvents{
+		UID:             ClusterEventsID(clusterName),
+		ResourceVersion: strconv.FormatInt(event.LastTimestamp.Unix(), 10),
+		ClusterName:     clusterName,
+		OriginalSource:  source,
+		Event:           event,
+	}
+}
+
+// ClusterEventsID returns the resource ID of the events of a cluster, the ID is derived from the cluster name, so the
+// agents and the sources agree on it without exchanging it.
+func ClusterEventsID(clusterName string) kubetypes.UID {
+	return kubetypes.UID(uuid.NewSHA1(clusterEventsNamespace, []byte(clusterName)).String())
+}
+
+func (e *ClusterEvents) GetUID() kubetypes.UID {
+	return e.UID
+}
+
+func (e *ClusterEvents) GetResourceVersion() string {
+	return e.ResourceVersion
+}
+
+// GetDeletionTimestamp always returns nil, the cluster events are not deleted by the sources.
+func (e *ClusterEvents) GetDeletionTimestamp() *metav1.Time {
+	return nil
+}
+
+// StatusHash returns the hash of the forwarded event, it is the StatusHashGetter of the cluster events.
+func StatusHash(e *ClusterEvents) (string, error) {
+	data, err := json.Marshal(e.Event)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the event of the cluster %s, %v", e.ClusterName, err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
@@ -0,0 +1,276 @@
+package clusterevents
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const testSource = "hub1"
+
+func newEvent(namespace, name, eventType, reason string, timestamp time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: namespace,
+			Name:      "pod1",
+		},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        "test message",
+		Count:          2,
+		FirstTimestamp: metav1.NewTime(timestamp.Truncate(time.Second)),
+		LastTimestamp:  metav1.NewTime(timestamp.Truncate(time.Second)),
+		Source:         corev1.EventSource{Component: "kubelet"},
+	}
+}
+
+func TestNewEventRecord(t *testing.T) {
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+
+	cases := []struct {
+		name     string
+		event    *corev1.Event
+		expected EventRecord
+	}{
+		{
+			name:  "core event",
+			event: newEvent("ns1", "event1", corev1.EventTypeWarning, "BackOff", now.Time),
+			expected: EventRecord{
+				Namespace:          "ns1",
+				Name:               "event1",
+				InvolvedObject:     corev1.ObjectReference{Kind: "Pod", Namespace: "ns1", Name: "pod1"},
+				Type:               corev1.EventTypeWarning,
+				Reason:             "BackOff",
+				Message:            "test message",
+				Count:              2,
+				FirstTimestamp:     now,
+				LastTimestamp:      now,
+				ReportingComponent: "kubelet",
+			},
+		},
+		{
+			name: "event with series",
+			event: &corev1.Event{
+				ObjectMeta:          metav1.ObjectMeta{Namespace: "ns1", Name: "event2"},
+				Type:                corev1.EventTypeWarning,
+				Reason:              "FailedScheduling",
+				EventTime:           metav1.NewMicroTime(now.Add(-time.Minute)),
+				Series:              &corev1.EventSeries{Count: 3, LastObservedTime: metav1.NewMicroTime(now.Time)},
+				ReportingController: "default-scheduler",
+			},
+			expected: EventRecord{
+				Namespace:          "ns1",
+				Name:               "event2",
+				Type:               corev1.EventTypeWarning,
+				Reason:             "FailedScheduling",
+				Count:              3,
+				FirstTimestamp:     metav1.NewTime(now.Add(-time.Minute)),
+				LastTimestamp:      now,
+				ReportingComponent: "default-scheduler",
+			},
+		},
+		{
+			name: "event without series",
+			event: &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "event3"},
+				Type:       corev1.EventTypeNormal,
+				EventTime:  metav1.NewMicroTime(now.Time),
+			},
+			expected: EventRecord{
+				Namespace:      "ns1",
+				Name:           "event3",
+				Type:           corev1.EventTypeNormal,
+				Count:          1,
+				FirstTimestamp: now,
+				LastTimestamp:  now,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			record := NewEventRecord(c.event)
+			if !reflect.DeepEqual(c.expected, record) {
+				t.Errorf("expected %v, but got %v", c.expected, record)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	event := newEvent("ns1", "event1", corev1.EventTypeWarning, "BackOff", time.Now())
+
+	cases := []struct {
+		name     string
+		filter   Filter
+		expected bool
+	}{
+		{
+			name:     "empty filter",
+			expected: true,
+		},
+		{
+			name:     "warning filter",
+			filter:   WarningFilter,
+			expected: true,
+		},
+		{
+			name:     "matched filter",
+			filter:   Filter{Namespaces: []string{"ns1", "ns2"}, Reasons: []string{"BackOff"}},
+			expected: true,
+		},
+		{
+			name:   "unmatched namespace",
+			filter: Filter{Namespaces: []string{"ns2"}},
+		},
+		{
+			name:   "unmatched reason",
+			filter: Filter{Reasons: []string{"FailedScheduling"}},
+		},
+		{
+			name:   "unmatched type",
+			filter: Filter{Types: []string{corev1.EventTypeNormal}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if matched := c.filter.Matches(event); matched != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, matched)
+			}
+		})
+	}
+}
+
+func TestCodec(t *testing.T) {
+	codec := NewCodec()
+	events := NewClusterEvents("cluster1", testSource,
+		NewEventRecord(newEvent("ns1", "event1", corev1.EventTypeWarning, "BackOff", time.Now())))
+
+	evt, err := codec.Encode("cluster1-agent", ClusterEventStatusEventType, events)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := schema.NewRegistry(ClusterEventSchema).Validate(*evt); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	decoded, err := codec.Decode(evt)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !reflect.DeepEqual(events, decoded) {
+		t.Errorf("expected %v, but got %v", events, decoded)
+	}
+
+	specEventType := types.CloudEventsType{
+		CloudEventsDataType: ClusterEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "update_request",
+	}
+	if _, err := codec.Encode(testSource, specEventType, events); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+}
+
+func TestForwardAndCollect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	forwarder := NewForwarder("cluster1", testSource, WarningFilter, options.EventRateLimit{QPS: 0.001, Burst: 3})
+
+	agentClient := fake.NewCloudEventsFakeClient()
+	agent, err := generic.NewCloudEventAgentClient[*ClusterEvents](ctx,
+		fake.NewAgentOptions(agentClient, "cluster1", "cluster1-agent"), forwarder, StatusHash, NewCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	now := time.Now()
+	for i, event := range []*corev1.Event{
+		newEvent("ns1", "event1", corev1.EventTypeWarning, "BackOff", now),
+		newEvent("ns1", "event2", corev1.EventTypeNormal, "Pulled", now),
+		newEvent("ns2", "event3", corev1.EventTypeWarning, "FailedMount", now.Add(time.Second)),
+		newEvent("ns1", "event4", corev1.EventTypeWarning, "BackOff", now.Add(2*time.Second)),
+		newEvent("ns1", "event5", corev1.EventTypeWarning, "BackOff", now.Add(3*time.Second)),
+	} {
+		forwarded, err := forwarder.Forward(ctx, agent, event)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		// the normal event is filtered and the last event exceeds the burst
+		if expected := i != 1 && i != 4; forwarded != expected {
+			t.Errorf("expected the event %s is forwarded %v, but got %v", event.Name, expected, forwarded)
+		}
+	}
+
+	if dropped := forwarder.DroppedEvents(); dropped != 1 {
+		t.Errorf("expected 1 dropped event, but got %d", dropped)
+	}
+
+	if events, _ := forwarder.List(types.ListOptions{Source: testSource}); len(events) != 1 || events[0].Event.Name != "event4" {
+		t.Errorf("expected the latest event, but got %v", events)
+	}
+
+	sentEvents := agentClient.GetSentEvents()
+	if len(sentEvents) != 3 {
+		t.Fatalf("expected 3 sent events, but got %d", len(sentEvents))
+	}
+
+	collector := NewCollector(2)
+	source, err := generic.NewCloudEventSourceClient[*ClusterEvents](ctx,
+		fake.NewSourceOptions(fake.NewCloudEventsFakeClient(sentEvents...), testSource),
+		collector, StatusHash, NewCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	source.Subscribe(ctx, collector.Handle)
+
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			events, _ := collector.List(types.ListOptions{ClusterName: "cluster1"})
+			return events[0].Event.Name == "event4", nil
+		}); err != nil {
+		t.Fatalf("expected the latest event is collected, %v", err)
+	}
+
+	// the collector keeps the recent 2 events
+	names := eventNames(collector.Events("cluster1", Filter{}))
+	if !reflect.DeepEqual(names, []string{"event3", "event4"}) {
+		t.Errorf("expected %v, but got %v", []string{"event3", "event4"}, names)
+	}
+
+	names = eventNames(collector.Events("cluster1", Filter{Reasons: []string{"BackOff"}}))
+	if !reflect.DeepEqual(names, []string{"event4"}) {
+		t.Errorf("expected %v, but got %v", []string{"event4"}, names)
+	}
+
+	collector.Forget("cluster1")
+	if records := collector.Events("cluster1", Filter{}); len(records) != 0 {
+		t.Errorf("expected no events, but got %v", records)
+	}
+}
+
+func eventNames(records []EventRecord) []string {
+	names := []string{}
+	for _, record := range records {
+		names = append(names, record.Name)
+	}
+	return names
+}
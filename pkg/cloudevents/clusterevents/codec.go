@@ -0,0 +1,100 @@
+package clusterevents
+
+import (
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// Codec is a codec to encode/decode the ClusterEvents/cloudevent with EventRecord, it is used by both the agents
+// and the sources, only the status events are supported.
+type Codec struct{}
+
+func NewCodec() *Codec {
+	return &Codec{}
+}
+
+// EventDataType always returns the event data type `io.open-cluster-management.events.v1alpha1.clusterevents`.
+func (c *Codec) EventDataType() types.CloudEventsDataType {
+	return ClusterEventDataType
+}
+
+// Encode the event of a ClusterEvents to a cloudevent.
+func (c *Codec) Encode(source string, eventType types.CloudEventsType, events *ClusterEvents) (*cloudevents.Event, error) {
+	if eventType.CloudEventsDataType != ClusterEventDataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	if eventType.SubResource != types.SubResourceStatus {
+		return nil, fmt.Errorf("unsupported subresource %s", eventType.SubResource)
+	}
+
+	evt := types.NewEventBuilder(source, eventType).
+		WithResourceID(string(events.UID)).
+		WithStringResourceVersion(events.ResourceVersion).
+		WithClusterName(events.ClusterName).
+		WithOriginalSource(events.OriginalSource).
+		NewEvent()
+
+	if err := evt.SetData(cloudevents.ApplicationJSON, events.Event); err != nil {
+		return nil, fmt.Errorf("failed to encode the event of the cluster %s to a cloudevent: %v", events.ClusterName, err)
+	}
+
+	return &evt, nil
+}
+
+// Decode a cloudevent whose data is EventRecord to a ClusterEvents.
+func (c *Codec) Decode(evt *cloudevents.Event) (*ClusterEvents, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cloud event type %s, %v", evt.Type(), err)
+	}
+
+	if eventType.CloudEventsDataType != ClusterEventDataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	if eventType.SubResource != types.SubResourceStatus {
+		return nil, fmt.Errorf("unsupported subresource %s", eventType.SubResource)
+	}
+
+	evtExtensions := evt.Context.GetExtensions()
+
+	resourceID, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionResourceID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resourceid extension: %v", err)
+	}
+
+	resourceVersion, err := types.GetResourceVersion(*evt)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterName, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionClusterName])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clustername extension: %v", err)
+	}
+
+	originalSource, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionOriginalSource])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get originalsource extension: %v", err)
+	}
+
+	events := &ClusterEvents{
+		UID:             kubetypes.UID(resourceID),
+		ResourceVersion: resourceVersion,
+		ClusterName:     clusterName,
+		OriginalSource:  originalSource,
+	}
+
+	if err := evt.DataAs(&events.Event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event data %s, %v", string(evt.Data()), err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,108 @@
+package clusterevents
+
+import (
+	"sync"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// DefaultCollectorSize is the default number of the recent events that are kept for a cluster by a Collector.
+const DefaultCollectorSize = 100
+
+// Collector keeps the recent forwarded events of the clusters on a source. The collector is the Lister of the source
+// client and its Handle is the resource handler of the subscription, e.g.
+//
+//	collector := clusterevents.NewCollector(clusterevents.DefaultCollectorSize)
+//	client, err := generic.NewCloudEventSourceClient[*clusterevents.ClusterEvents](
+//		ctx, sourceOptions, collector, clusterevents.StatusHash, clusterevents.NewCodec())
+//	client.Subscribe(ctx, collector.Handle)
+type Collector struct {
+	sync.RWMutex
+	size   int
+	latest map[string]*ClusterEvents
+	events map[string][]EventRecord
+}
+
+// NewCollector returns a Collector that keeps the given number of the recent events for a cluster. If the size is less
+// than or equal to zero, the DefaultCollectorSize is used.
+func NewCollector(size int) *Collector {
+	if size <= 0 {
+		size = DefaultCollectorSize
+	}
+
+	return &Collector{
+		size:   size,
+		latest: map[string]*ClusterEvents{},
+		events: map[string][]EventRecord{},
+	}
+}
+
+// List returns the latest forwarded events of the clusters. If a cluster is specified and it has not forwarded an
+// event, the cluster events without an event is returned, so the first event of the cluster is handled by the source
+// client.
+func (c *Collector) List(options types.ListOptions) ([]*ClusterEvents, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	if options.ClusterName != types.ClusterAll {
+		if events, ok := c.latest[options.ClusterName]; ok {
+			return []*ClusterEvents{events}, nil
+		}
+
+		return []*ClusterEvents{{
+			UID:            ClusterEventsID(options.ClusterName),
+			ClusterName:    options.ClusterName,
+			OriginalSource: options.Source,
+		}}, nil
+	}
+
+	events := make([]*ClusterEvents, 0, len(c.latest))
+	for _, e := range c.latest {
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// Handle keeps the received event of a cluster, the oldest event of the cluster is removed if the number of the kept
+// events exceeds the size of the collector.
+func (c *Collector) Handle(action types.ResourceAction, events *ClusterEvents) error {
+	if action != types.StatusModified {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.latest[events.ClusterName] = events
+
+	records := append(c.events[events.ClusterName], events.Event)
+	if len(records) > c.size {
+		records = records[len(records)-c.size:]
+	}
+	c.events[events.ClusterName] = records
+	return nil
+}
+
+// Events returns the recent events of a cluster that match the filter, the events are ordered by the time they are
+// received.
+func (c *Collector) Events(clusterName string, filter Filter) []EventRecord {
+	c.RLock()
+	defer c.RUnlock()
+
+	records := []EventRecord{}
+	for _, record := range c.events[clusterName] {
+		if filter.match(record.Namespace, record.Reason, record.Type) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// Forget removes the events of a cluster, e.g. the cluster is detached from the fleet.
+func (c *Collector) Forget(clusterName string) {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.latest, clusterName)
+	delete(c.events, clusterName)
+}
@@ -0,0 +1,90 @@
+package clusterevents
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// Forwarder forwards the Kubernetes events of a cluster to a source with an agent client. The events that do not match
+// the filter are ignored, and the events that exceed the rate limit are dropped instead of being delayed, so a burst of
+// the events does not block the caller, e.g. the event handler of an informer. The forwarder keeps the latest forwarded
+// event, it is the Lister of the agent client.
+type Forwarder struct {
+	sync.RWMutex
+	clusterName string
+	source      string
+	filter      Filter
+	rateLimiter flowcontrol.RateLimiter
+	dropped     atomic.Int64
+	latest      *ClusterEvents
+}
+
+// NewForwarder returns a Forwarder that forwards the events of the given cluster that match the filter to the given
+// source, the forwarding rate is limited by the given rate limit, see generic.NewRateLimiter for its defaults.
+func NewForwarder(clusterName, source string, filter Filter, limit options.EventRateLimit) *Forwarder {
+	return &Forwarder{
+		clusterName: clusterName,
+		source:      source,
+		filter:      filter,
+		rateLimiter: generic.NewRateLimiter(limit),
+	}
+}
+
+// List returns the latest forwarded event of the cluster if there is one.
+func (f *Forwarder) List(options types.ListOptions) ([]*ClusterEvents, error) {
+	f.RLock()
+	defer f.RUnlock()
+
+	if f.latest == nil {
+		return nil, nil
+	}
+
+	if options.ClusterName != types.ClusterAll && options.ClusterName != f.clusterName {
+		return nil, nil
+	}
+
+	if options.Source != types.SourceAll && options.Source != f.source {
+		return nil, nil
+	}
+
+	return []*ClusterEvents{f.latest}, nil
+}
+
+// Forward publishes a Kubernetes event with the agent client if the event matches the filter and the rate limit is not
+// exceeded, it returns true if the event is forwarded.
+func (f *Forwarder) Forward(ctx context.Context, client generic.CloudEventsClient[*ClusterEvents], event *corev1.Event) (bool, error) {
+	if !f.filter.Matches(event) {
+		return false, nil
+	}
+
+	if !f.rateLimiter.TryAccept() {
+		f.dropped.Add(1)
+		klog.V(4).Infof("the event %s/%s of the cluster %s exceeds the rate limit, drop",
+			event.Namespace, event.Name, f.clusterName)
+		return false, nil
+	}
+
+	events := NewClusterEvents(f.clusterName, f.source, NewEventRecord(event))
+	if err := client.Publish(ctx, ClusterEventStatusEventType, events); err != nil {
+		return false, err
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	f.latest = events
+	return true, nil
+}
+
+// DroppedEvents returns the number of the matched events that are dropped because they exceed the rate limit.
+func (f *Forwarder) DroppedEvents() int64 {
+	return f.dropped.Load()
+}
@@ -0,0 +1,162 @@
+package conformance
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+const goldenDir = "golden"
+
+func TestGoldenFiles(t *testing.T) {
+	if *update {
+		if err := WriteGoldenFiles(goldenDir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vectors, err := Vectors()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, vector := range vectors {
+		t.Run(vector.Name, func(t *testing.T) {
+			expected, err := marshalGolden(vector.Event)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := os.ReadFile(filepath.Join(goldenDir, vector.Name+".json"))
+			if err != nil {
+				t.Fatalf("failed to read the golden file, run the test with -update to write it, %v", err)
+			}
+
+			if string(expected) != string(actual) {
+				t.Errorf("the wire format of the vector is changed, expected %s, but got %s", string(actual), string(expected))
+			}
+		})
+	}
+}
+
+func TestVerifyDir(t *testing.T) {
+	verifier, err := NewVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := verifier.VerifyDir(goldenDir); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := verifier.VerifyDir(t.TempDir()); err == nil {
+		t.Errorf("expected error for the directory without vectors, but got nil")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	cases := []struct {
+		name        string
+		vector      string
+		mutate      func(evt map[string]any)
+		expectedErr string
+	}{
+		{
+			name:   "the volatile attributes are ignored",
+			vector: "manifestbundles-status-update",
+			mutate: func(evt map[string]any) {
+				evt["id"] = "another"
+				evt["time"] = "2025-01-01T00:00:00Z"
+				evt["sequenceid"] = "100"
+			},
+		},
+		{
+			name:   "the resource version is a string",
+			vector: "manifestbundles-spec-create",
+			mutate: func(evt map[string]any) {
+				evt["resourceversion"] = "1"
+			},
+		},
+		{
+			name:        "unknown vector",
+			vector:      "unknown",
+			expectedErr: "not found",
+		},
+		{
+			name:   "missing extension",
+			vector: "manifestbundles-status-update",
+			mutate: func(evt map[string]any) {
+				delete(evt, "originalsource")
+			},
+			expectedErr: "invalid",
+		},
+		{
+			name:   "different extension",
+			vector: "manifestbundles-spec-create",
+			mutate: func(evt map[string]any) {
+				evt["clustername"] = "cluster2"
+			},
+			expectedErr: "clustername",
+		},
+		{
+			name:   "different data",
+			vector: "manifests-status-update",
+			mutate: func(evt map[string]any) {
+				evt["data"].(map[string]any)["conditions"] = []any{}
+			},
+			expectedErr: "expected the data",
+		},
+		{
+			name:   "undecodable data",
+			vector: "manifests-spec-create",
+			mutate: func(evt map[string]any) {
+				evt["data"] = map[string]any{"manifest": map[string]any{"kind": "ConfigMap"}}
+			},
+			expectedErr: "failed to decode",
+		},
+	}
+
+	verifier, err := NewVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data := []byte("{}")
+			if c.mutate != nil {
+				golden, err := os.ReadFile(filepath.Join(goldenDir, c.vector+".json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				evt := map[string]any{}
+				if err := json.Unmarshal(golden, &evt); err != nil {
+					t.Fatal(err)
+				}
+				c.mutate(evt)
+
+				if data, err = json.Marshal(evt); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := verifier.Verify(c.vector, data)
+			if c.expectedErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), c.expectedErr) {
+				t.Errorf("expected error %q, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,486 @@
+// Package conformance provides the test vectors of the wire format of the cloudevents that are exchanged by the sources
+// and agents of this SDK, so the implementations in other languages can check their wire-compatibility with this SDK.
+//
+// Each vector is an event that is encoded by a codec of this SDK for a data type, the vectors are written as the
+// golden files of the structured JSON events, see WriteGoldenFiles. An implementation in another language decodes the
+// golden files and produces the same events, which are checked by a Verifier, e.g. with `cectl conformance`.
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	agentcodec "open-cluster-management.io/sdk-go/pkg/cloudevents/work/agent/codec"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
+	workpayload "open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
+	sourcecodec "open-cluster-management.io/sdk-go/pkg/cloudevents/work/source/codec"
+)
+
+const (
+	// GoldenSource is the source of the spec events and the resync requests of the sources in the vectors.
+	GoldenSource = "source1"
+
+	// GoldenClusterName is the cluster name of the events in the vectors.
+	GoldenClusterName = "cluster1"
+
+	// GoldenAgentID is the source of the status events and the requests of the agents in the vectors.
+	GoldenAgentID = "cluster1-work-agent"
+
+	// goldenResourceID is the resource ID of the resources in the vectors.
+	goldenResourceID = "5a8c8f0e-3b1e-4c6e-9a5e-2f0b2c7d1e42"
+
+	// goldenSequenceID is the status update sequence ID of the status events in the vectors.
+	goldenSequenceID = "1"
+)
+
+// GoldenTime is the time of the events in the vectors, it is also the timestamp of the conditions and the deletion.
+var GoldenTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// VolatileExtensions are the extensions that differ between two encodings of the same resource, they are not
+// compared by the Verifier. The id and time attributes of the events are not compared either.
+var VolatileExtensions = []string{types.ExtensionStatusUpdateSequenceID}
+
+// Vector is a test vector of the wire format of an event.
+type Vector struct {
+	// Name is the name of the vector, its golden file is named <name>.json.
+	Name string
+
+	// Description describes the event of the vector.
+	Description string
+
+	// Event is the event that is encoded by this SDK, its id is the name of the vector, and its time is the GoldenTime.
+	Event cloudevents.Event
+
+	// decode decodes an event of the vector with the codec of this SDK that receives the event.
+	decode func(evt *cloudevents.Event) error
+}
+
+// Vectors returns the test vectors of the events of all the codecs and data types of this SDK.
+func Vectors() ([]Vector, error) {
+	builders := []func() (Vector, error){
+		manifestBundleSpecVector,
+		manifestBundleSpecUpdateVector,
+		manifestBundleDeletionVector,
+		manifestBundleStatusVector,
+		manifestSpecVector,
+		manifestStatusVector,
+		specResyncRequestVector,
+		statusResyncRequestVector,
+		registrationRequestVector,
+		registrationResponseVector,
+	}
+
+	vectors := make([]Vector, 0, len(builders))
+	for _, build := range builders {
+		vector, err := build()
+		if err != nil {
+			return nil, err
+		}
+
+		vector.Event.SetID(vector.Name)
+		vector.Event.SetTime(GoldenTime)
+		if _, ok := vector.Event.Extensions()[types.ExtensionStatusUpdateSequenceID]; ok {
+			vector.Event.SetExtension(types.ExtensionStatusUpdateSequenceID, goldenSequenceID)
+		}
+		vectors = append(vectors, vector)
+	}
+
+	return vectors, nil
+}
+
+// WriteGoldenFiles writes the events of the vectors as the indented structured JSON events to the <name>.json files of
+// the directory, the attributes, extensions and data fields of the events are sorted by their names.
+func WriteGoldenFiles(dir string) error {
+	vectors, err := Vectors()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create the golden directory %s, %v", dir, err)
+	}
+
+	for _, vector := range vectors {
+		data, err := marshalGolden(vector.Event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the vector %s, %v", vector.Name, err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, vector.Name+".json"), data, 0o644); err != nil {
+			return fmt.Errorf("failed to write the vector %s, %v", vector.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// marshalGolden marshals the event with the sorted attributes and extensions, so the golden files are stable.
+func marshalGolden(evt cloudevents.Event) ([]byte, error) {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	structured := map[string]any{}
+	if err := json.Unmarshal(data, &structured); err != nil {
+		return nil, err
+	}
+
+	data, err = json.MarshalIndent(structured, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func goldenWork() *workv1.ManifestWork {
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "work1",
+			Namespace:       GoldenClusterName,
+			UID:             kubetypes.UID(goldenResourceID),
+			Generation:      1,
+			ResourceVersion: "1",
+			Labels: map[string]string{
+				common.CloudEventsOriginalSourceLabelKey: GoldenSource,
+			},
+		},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: []byte(
+					`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test","namespace":"default"},` +
+						`"data":{"test":"test"}}`)}}},
+			},
+			DeleteOption: &workv1.DeleteOption{PropagationPolicy: workv1.DeletePropagationPolicyTypeForeground},
+		},
+		Status: workv1.ManifestWorkStatus{
+			Conditions: []metav1.Condition{{
+				Type:               workv1.WorkApplied,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 1,
+				LastTransitionTime: metav1.NewTime(GoldenTime),
+				Reason:             "AppliedManifestWorkComplete",
+				Message:            "Apply manifest work complete",
+			}},
+			ResourceStatus: workv1.ManifestResourceStatus{
+				Manifests: []workv1.ManifestCondition{{
+					ResourceMeta: workv1.ManifestResourceMeta{
+						Ordinal:   0,
+						Version:   "v1",
+						Kind:      "ConfigMap",
+						Resource:  "configmaps",
+						Name:      "test",
+						Namespace: "default",
+					},
+					Conditions: []metav1.Condition{{
+						Type:               workv1.ManifestApplied,
+						Status:             metav1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(GoldenTime),
+						Reason:             "AppliedManifestComplete",
+						Message:            "Apply manifest complete",
+					}},
+				}},
+			},
+		},
+	}
+}
+
+func decodeWith(decode func(evt *cloudevents.Event) (*workv1.ManifestWork, error)) func(evt *cloudevents.Event) error {
+	return func(evt *cloudevents.Event) error {
+		work, err := decode(evt)
+		if err != nil {
+			return err
+		}
+		if string(work.UID) != goldenResourceID {
+			return fmt.Errorf("expected the resource %s, but got %s", goldenResourceID, work.UID)
+		}
+		return nil
+	}
+}
+
+func manifestBundleSpecVector() (Vector, error) {
+	evt, err := sourcecodec.NewManifestBundleCodec().Encode(GoldenSource, types.CloudEventsType{
+		CloudEventsDataType: workpayload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}, goldenWork())
+	if err != nil {
+		return Vector{}, err
+	}
+
+	return Vector{
+		Name:        "manifestbundles-spec-create",
+		Description: "a source creates a manifest bundle on an agent",
+		Event:       *evt,
+		decode:      decodeWith(agentcodec.NewManifestBundleCodec().Decode),
+	}, nil
+}
+
+func manifestBundleSpecUpdateVector() (Vector, error) {
+	work := goldenWork()
+	work.Generation = 2
+	work.Spec.DeleteOption = &workv1.DeleteOption{
+		PropagationPolicy: workv1.DeletePropagationPolicyTypeSelectivelyOrphan,
+		SelectivelyOrphan: &workv1.SelectivelyOrphan{
+			OrphaningRules: []workv1.OrphaningRule{{Resource: "configmaps", Name: "test", Namespace: "default"}},
+		},
+	}
+	work.Spec.ManifestConfigs = []workv1.ManifestConfigOption{{
+		ResourceIdentifier: workv1.ResourceIdentifier{Resource: "configmaps", Name: "test", Namespace: "default"},
+		FeedbackRules: []workv1.FeedbackRule{{
+			Type:      workv1.JSONPathsType,
+			JsonPaths: []workv1.JsonPath{{Name: "test", Path: ".data.test"}},
+		}},
+		UpdateStrategy: &workv1.UpdateStrategy{
+			Type:            workv1.UpdateStrategyTypeServerSideApply,
+			ServerSideApply: &workv1.ServerSideApplyConfig{Force: true, FieldManager: "work-agent"},
+		},
+	}}
+	work.Spec.Executor = &workv1.ManifestWorkExecutor{
+		Subject: workv1.ManifestWorkExecutorSubject{
+			Type: workv1.ExecutorSubjectTypeServiceAccount,
+			ServiceAccount: &workv1.ManifestWorkSubjectServiceAccount{
+				Namespace: "default",
+				Name:      "work-executor",
+			},
+		},
+	}
+
+	evt, err := sourcecodec.NewManifestBundleCodec().Encode(GoldenSource, types.CloudEventsType{
+		CloudEventsDataType: workpayload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "update_request",
+	}, work)
+	if err != nil {
+		return Vector{}, err
+	}
+
+	decode := agentcodec.NewManifestBundleCodec().Decode
+	return Vector{
+		Name: "manifestbundles-spec-update",
+		Description: "a source updates a manifest bundle on an agent with the delete option, the manifest configs " +
+			"and the executor, the agent receives the whole spec",
+		Event: *evt,
+		decode: func(evt *cloudevents.Event) error {
+			decoded, err := decode(evt)
+			if err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(decoded.Spec.DeleteOption, work.Spec.DeleteOption) {
+				return fmt.Errorf("expected the delete option %v, but got %v",
+					work.Spec.DeleteOption, decoded.Spec.DeleteOption)
+			}
+			if !reflect.DeepEqual(decoded.Spec.ManifestConfigs, work.Spec.ManifestConfigs) {
+				return fmt.Errorf("expected the manifest configs %v, but got %v",
+					work.Spec.ManifestConfigs, decoded.Spec.ManifestConfigs)
+			}
+			if !reflect.DeepEqual(decoded.Spec.Executor, work.Spec.Executor) {
+				return fmt.Errorf("expected the executor %v, but got %v", work.Spec.Executor, decoded.Spec.Executor)
+			}
+			return decodeWith(decode)(evt)
+		},
+	}, nil
+}
+
+func manifestBundleDeletionVector() (Vector, error) {
+	work := goldenWork()
+	work.DeletionTimestamp = &metav1.Time{Time: GoldenTime}
+
+	evt, err := sourcecodec.NewManifestBundleCodec().Encode(GoldenSource, types.CloudEventsType{
+		CloudEventsDataType: workpayload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "delete_request",
+	}, work)
+	if err != nil {
+		return Vector{}, err
+	}
+
+	return Vector{
+		Name:        "manifestbundles-spec-delete",
+		Description: "a source deletes a manifest bundle from an agent, the event has no data",
+		Event:       *evt,
+		decode:      decodeWith(agentcodec.NewManifestBundleCodec().Decode),
+	}, nil
+}
+
+func manifestBundleStatusVector() (Vector, error) {
+	evt, err := agentcodec.NewManifestBundleCodec().Encode(GoldenAgentID, types.CloudEventsType{
+		CloudEventsDataType: workpayload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "update_request",
+	}, goldenWork())
+	if err != nil {
+		return Vector{}, err
+	}
+
+	return Vector{
+		Name:        "manifestbundles-status-update",
+		Description: "an agent updates the status of a manifest bundle to a source",
+		Event:       *evt,
+		decode:      decodeWith(sourcecodec.NewManifestBundleCodec().Decode),
+	}, nil
+}
+
+func manifestSpecVector() (Vector, error) {
+	// there is no source codec of the single manifests, the event is built as a source does
+	work := goldenWork()
+	evt := types.NewEventBuilder(GoldenSource, types.CloudEventsType{
+		CloudEventsDataType: workpayload.ManifestEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}).WithClusterName(work.Namespace).
+		WithResourceID(string(work.UID)).
+		WithResourceVersion(work.Generation).
+		NewEvent()
+
+	manifest := &workpayload.Manifest{DeleteOption: work.Spec.DeleteOption}
+	if err := manifest.Manifest.UnmarshalJSON(work.Spec.Workload.Manifests[0].Raw); err != nil {
+		return Vector{}, err
+	}
+	if err := evt.SetData(cloudevents.ApplicationJSON, manifest); err != nil {
+		return Vector{}, err
+	}
+
+	return Vector{
+		Name:        "manifests-spec-create",
+		Description: "a source creates a single manifest on an agent",
+		Event:       evt,
+		decode:      decodeWith(agentcodec.NewManifestCodec(nil).Decode),
+	}, nil
+}
+
+func manifestStatusVector() (Vector, error) {
+	work := goldenWork()
+	evt, err := agentcodec.NewManifestCodec(nil).Encode(GoldenAgentID, types.CloudEventsType{
+		CloudEventsDataType: workpayload.ManifestEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "update_request",
+	}, work)
+	if err != nil {
+		return Vector{}, err
+	}
+
+	return Vector{
+		Name:        "manifests-status-update",
+		Description: "an agent updates the status of a single manifest to a source",
+		Event:       *evt,
+		// there is no source codec of the single manifests, the status is decoded as a source does
+		decode: func(evt *cloudevents.Event) error {
+			return evt.DataAs(&workpayload.ManifestStatus{})
+		},
+	}, nil
+}
+
+func specResyncRequestVector() (Vector, error) {
+	evt := types.NewEventBuilder(GoldenAgentID, types.CloudEventsType{
+		CloudEventsDataType: workpayload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncRequestAction,
+	}).WithOriginalSource(types.SourceAll).
+		WithClusterName(GoldenClusterName).
+		NewEvent()
+	if err := evt.SetData(cloudevents.ApplicationJSON, &payload.ResourceVersionList{
+		Versions: []payload.ResourceVersion{{ResourceID: goldenResourceID, ResourceVersion: 1}},
+	}); err != nil {
+		return Vector{}, err
+	}
+
+	return Vector{
+		Name:        "manifestbundles-spec-resync-request",
+		Description: "an agent requests the sources to resync the spec of its manifest bundles",
+		Event:       evt,
+		decode: func(evt *cloudevents.Event) error {
+			_, err := payload.DecodeSpecResyncRequest(*evt)
+			return err
+		},
+	}, nil
+}
+
+func statusResyncRequestVector() (Vector, error) {
+	evt := types.NewEventBuilder(GoldenSource, types.CloudEventsType{
+		CloudEventsDataType: workpayload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              types.ResyncRequestAction,
+	}).WithClusterName(GoldenClusterName).
+		NewEvent()
+	if err := evt.SetData(cloudevents.ApplicationJSON, &payload.ResourceStatusHashList{
+		Hashes: []payload.ResourceStatusHash{{ResourceID: goldenResourceID, StatusHash: "f0e1d2c3"}},
+	}); err != nil {
+		return Vector{}, err
+	}
+
+	return Vector{
+		Name:        "manifestbundles-status-resync-request",
+		Description: "a source requests an agent to resync the status of the manifest bundles",
+		Event:       evt,
+		decode: func(evt *cloudevents.Event) error {
+			_, err := payload.DecodeStatusResyncRequest(*evt)
+			return err
+		},
+	}, nil
+}
+
+func registrationRequestVector() (Vector, error) {
+	evt := types.NewEventBuilder(GoldenAgentID, types.CloudEventsType{
+		CloudEventsDataType: types.RegistrationDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.RegisterRequestAction,
+	}).WithOriginalSource(types.SourceAll).
+		WithClusterName(GoldenClusterName).
+		NewEvent()
+	if err := evt.SetData(cloudevents.ApplicationJSON, &payload.AgentRegistration{
+		ClusterName:  GoldenClusterName,
+		AgentID:      GoldenAgentID,
+		AgentVersion: "v1.0.0",
+		Capabilities: []string{workpayload.ManifestBundleEventDataType.String()},
+	}); err != nil {
+		return Vector{}, err
+	}
+
+	return Vector{
+		Name:        "registration-request",
+		Description: "an agent registers itself to the sources",
+		Event:       evt,
+		decode: func(evt *cloudevents.Event) error {
+			_, err := payload.DecodeAgentRegistration(*evt)
+			return err
+		},
+	}, nil
+}
+
+func registrationResponseVector() (Vector, error) {
+	evt := types.NewEventBuilder(GoldenSource, types.CloudEventsType{
+		CloudEventsDataType: types.RegistrationDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.RegisterResponseAction,
+	}).WithClusterName(GoldenClusterName).
+		NewEvent()
+	if err := evt.SetData(cloudevents.ApplicationJSON, &payload.AgentBootstrap{
+		RequestID: "registration-request",
+		Accepted:  true,
+	}); err != nil {
+		return Vector{}, err
+	}
+
+	return Vector{
+		Name:        "registration-response",
+		Description: "a source accepts the registration of an agent",
+		Event:       evt,
+		decode: func(evt *cloudevents.Event) error {
+			_, err := payload.DecodeAgentBootstrap(*evt)
+			return err
+		},
+	}, nil
+}
@@ -0,0 +1,189 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	workpayload "open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
+)
+
+// Verifier verifies the events that are produced by other implementations with the vectors.
+type Verifier struct {
+	vectors map[string]Vector
+	schemas *schema.Registry
+}
+
+// NewVerifier returns a Verifier of the vectors of this SDK.
+func NewVerifier() (*Verifier, error) {
+	vectors, err := Vectors()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Verifier{
+		vectors: make(map[string]Vector, len(vectors)),
+		schemas: schema.NewRegistry(workpayload.ManifestEventSchema, workpayload.ManifestBundleEventSchema),
+	}
+	for _, vector := range vectors {
+		v.vectors[vector.Name] = vector
+	}
+	return v, nil
+}
+
+// Verify checks the structured JSON event of the named vector, the event is valid with the event schemas, it is
+// decoded by the codec of this SDK, and its attributes, extensions and data are equal to the event of the vector. The
+// id, the time and the VolatileExtensions of the event are not compared, and the extensions are compared by their
+// canonical string values, e.g. the resourceversion can be either a number or a string.
+func (v *Verifier) Verify(name string, data []byte) error {
+	vector, ok := v.vectors[name]
+	if !ok {
+		return fmt.Errorf("the vector %s is not found", name)
+	}
+
+	evt := cloudevents.NewEvent()
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return fmt.Errorf("failed to unmarshal the event of the vector %s, %v", name, err)
+	}
+
+	if err := v.schemas.Validate(evt); err != nil {
+		return fmt.Errorf("the event of the vector %s is invalid, %v", name, err)
+	}
+
+	if err := vector.decode(&evt); err != nil {
+		return fmt.Errorf("failed to decode the event of the vector %s, %v", name, err)
+	}
+
+	if errs := compare(vector.Event, evt); len(errs) != 0 {
+		return fmt.Errorf("the event of the vector %s is not compatible, %v", name, utilerrors.NewAggregate(errs))
+	}
+
+	return nil
+}
+
+// VerifyDir verifies the <name>.json files of the directory with the vectors of the names, the files of the unknown
+// vectors are ignored. It returns an aggregated error of the invalid files, or an error if no vector is verified.
+func (v *Verifier) VerifyDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read the directory %s, %v", dir, err)
+	}
+
+	verified := 0
+	errs := []error{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if _, ok := v.vectors[name]; !ok {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		verified++
+		errs = append(errs, v.Verify(name, data))
+	}
+
+	if verified == 0 && len(errs) == 0 {
+		return fmt.Errorf("no vector is found in the directory %s", dir)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// compare returns the differences between the expected and the actual events.
+func compare(expected, actual cloudevents.Event) []error {
+	errs := []error{}
+	for _, attr := range []struct {
+		name             string
+		expected, actual string
+	}{
+		{"specversion", expected.SpecVersion(), actual.SpecVersion()},
+		{"type", expected.Type(), actual.Type()},
+		{"source", expected.Source(), actual.Source()},
+		{"datacontenttype", expected.DataMediaType(), actual.DataMediaType()},
+	} {
+		if attr.expected != attr.actual {
+			errs = append(errs, fmt.Errorf("expected the %s %q, but got %q", attr.name, attr.expected, attr.actual))
+		}
+	}
+
+	names := []string{}
+	for name := range expected.Extensions() {
+		names = append(names, name)
+	}
+	for name := range actual.Extensions() {
+		if _, ok := expected.Extensions()[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if slices.Contains(VolatileExtensions, name) {
+			continue
+		}
+
+		expectedValue, expectedOk := formatExtension(expected, name)
+		actualValue, actualOk := formatExtension(actual, name)
+		switch {
+		case !actualOk:
+			errs = append(errs, fmt.Errorf("expected the extension %s %q, but it is not found", name, expectedValue))
+		case !expectedOk:
+			errs = append(errs, fmt.Errorf("unexpected extension %s %q", name, actualValue))
+		case expectedValue != actualValue:
+			errs = append(errs, fmt.Errorf("expected the extension %s %q, but got %q", name, expectedValue, actualValue))
+		}
+	}
+
+	var expectedData, actualData any
+	if len(expected.Data()) != 0 {
+		if err := json.Unmarshal(expected.Data(), &expectedData); err != nil {
+			return append(errs, fmt.Errorf("failed to unmarshal the expected data, %v", err))
+		}
+	}
+	if len(actual.Data()) != 0 {
+		if err := json.Unmarshal(actual.Data(), &actualData); err != nil {
+			return append(errs, fmt.Errorf("failed to unmarshal the data, %v", err))
+		}
+	}
+	if !reflect.DeepEqual(expectedData, actualData) {
+		errs = append(errs, fmt.Errorf("expected the data %s, but got %s", string(expected.Data()), string(actual.Data())))
+	}
+
+	return errs
+}
+
+// formatExtension returns the canonical string of the extension value, e.g. the time is formatted with RFC3339.
+func formatExtension(evt cloudevents.Event, name string) (string, bool) {
+	value, ok := evt.Extensions()[name]
+	if !ok {
+		return "", false
+	}
+
+	str, err := cloudeventstypes.Format(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value), true
+	}
+
+	if t, err := cloudeventstypes.ToTime(str); err == nil {
+		return cloudeventstypes.FormatTime(t), true
+	}
+	return str, true
+}
@@ -0,0 +1,123 @@
+package controllerruntime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// Object is a resource object that can be reconciled by a controller-runtime controller, e.g. a ManifestWork.
+type Object interface {
+	generic.ResourceObject
+	client.Object
+}
+
+// Source is a controller-runtime source.Source of the resource objects that are received by a cloudevents client, the
+// received objects that pass the predicates are sent to the event handler of the controller as the generic events, so
+// the controllers built on controller-runtime can reconcile on the incoming spec/status events, e.g.
+//
+//	src := controllerruntime.Subscribe[*workv1.ManifestWork](ctx, cloudEventsClient, 100)
+//	err := ctrl.NewControllerManagedBy(mgr).
+//		Named("manifestworks").
+//		WatchesRawSource(src, &handler.EnqueueRequestForObject{}).
+//		Complete(reconciler)
+//
+// The source can be started only once.
+type Source[T Object] struct {
+	sync.Mutex
+	events     chan event.GenericEvent
+	predicates []predicate.Predicate
+	started    bool
+}
+
+// NewSource returns a Source whose received objects are buffered with the given size until they are sent to the event
+// handler, the handling of the cloudevents client is blocked if the buffer is full. The objects are only sent if they
+// pass all the predicates.
+func NewSource[T Object](bufferSize int, predicates ...predicate.Predicate) *Source[T] {
+	return &Source[T]{
+		events:     make(chan event.GenericEvent, bufferSize),
+		predicates: predicates,
+	}
+}
+
+// Subscribe subscribes the given cloudevents client with the handler of a new Source and returns the Source, the
+// subscription is stopped when the context is done.
+func Subscribe[T Object](ctx context.Context, cloudEventsClient generic.CloudEventsClient[T],
+	bufferSize int, predicates ...predicate.Predicate) *Source[T] {
+	src := NewSource[T](bufferSize, predicates...)
+	cloudEventsClient.Subscribe(ctx, src.Handler(ctx))
+	return src
+}
+
+// Handler returns a resource handler that sends the received objects to the Source, it stops sending the objects after
+// the context is done.
+func (s *Source[T]) Handler(ctx context.Context) generic.ResourceHandler[T] {
+	return func(action types.ResourceAction, obj T) error {
+		evt := event.GenericEvent{Object: obj}
+		if !filter(evt, s.predicates) {
+			return nil
+		}
+
+		select {
+		case s.events <- evt:
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("failed to send the %s object %s to the source, %v", action, obj.GetUID(), ctx.Err())
+		}
+	}
+}
+
+// Start implements the controller-runtime source.Source, it sends the received objects that pass the given predicates
+// to the event handler until the context is done.
+func (s *Source[T]) Start(ctx context.Context, eventHandler handler.EventHandler,
+	queue workqueue.RateLimitingInterface, predicates ...predicate.Predicate) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.started {
+		return fmt.Errorf("the source %s has already started", s)
+	}
+	s.started = true
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt := <-s.events:
+				if !filter(evt, predicates) {
+					klog.V(4).Infof("the object %s is filtered out by the predicates", evt.Object.GetUID())
+					continue
+				}
+
+				eventHandler.Generic(ctx, evt, queue)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *Source[T]) String() string {
+	var obj T
+	return fmt.Sprintf("cloudevents source: %T", obj)
+}
+
+func filter(evt event.GenericEvent, predicates []predicate.Predicate) bool {
+	for _, p := range predicates {
+		if !p.Generic(evt) {
+			return false
+		}
+	}
+	return true
+}
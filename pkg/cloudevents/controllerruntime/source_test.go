@@ -0,0 +1,96 @@
+package controllerruntime
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestSource(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the works of cluster2 are filtered out by the source predicates, and the works whose names are test2 are filtered
+	// out by the controller predicates
+	src := NewSource[*workv1.ManifestWork](10, predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == "cluster1"
+	}))
+
+	works := []*workv1.ManifestWork{
+		{ObjectMeta: metav1.ObjectMeta{Name: "test1", Namespace: "cluster1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test1", Namespace: "cluster2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test2", Namespace: "cluster1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test3", Namespace: "cluster1"}},
+	}
+
+	receive := src.Handler(ctx)
+	for _, work := range works {
+		if err := receive(types.Modified, work); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	var lock sync.Mutex
+	reconciled := []string{}
+	eventHandler := handler.Funcs{
+		GenericFunc: func(ctx context.Context, evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+			lock.Lock()
+			defer lock.Unlock()
+			reconciled = append(reconciled, evt.Object.GetNamespace()+"/"+evt.Object.GetName())
+		},
+	}
+
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+
+	if err := src.Start(ctx, eventHandler, queue, predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() != "test2"
+	})); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := src.Start(ctx, eventHandler, queue); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+
+	if err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(reconciled) == 2, nil
+		}); err != nil {
+		t.Fatalf("expected 2 reconciled works, but got %v", reconciled)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if reconciled[0] != "cluster1/test1" || reconciled[1] != "cluster1/test3" {
+		t.Errorf("expected [cluster1/test1 cluster1/test3], but got %v", reconciled)
+	}
+}
+
+func TestSourceHandlerStopped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	src := NewSource[*workv1.ManifestWork](0)
+	receive := src.Handler(ctx)
+	cancel()
+
+	// the source is not started, the handler returns an error after the context is done
+	work := &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: "test1", Namespace: "cluster1"}}
+	if err := receive(types.Added, work); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+}
@@ -0,0 +1,162 @@
+package edge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// Handler handles a received resource of an edge agent, the action is one of the spec actions of the
+// types.ResourceAction, e.g. the resource should be applied to the target for the types.Added and types.Modified, and
+// cleaned up from the target for the types.Deleted.
+type Handler[S, T any] func(action types.ResourceAction, resource *Resource[S, T]) error
+
+// Agent is an edge agent that receives the resources of a data type from the sources and reports their statuses, the
+// received resources are kept in memory, so the resources are resynced from the sources after the agent restarts.
+type Agent[S, T any] struct {
+	client   *generic.CloudEventAgentClient[*Resource[S, T]]
+	store    *store[S, T]
+	dataType types.CloudEventsDataType
+}
+
+// NewAgent returns an edge agent of the given data type, the agentOptions provides the cluster name and the agent ID
+// of the agent and the cloudevents clients of the event protocol. The status hash of a resource is the SHA256 checksum
+// of its status.
+func NewAgent[S, T any](ctx context.Context,
+	agentOptions *options.CloudEventsAgentOptions, dataType types.CloudEventsDataType) (*Agent[S, T], error) {
+	store := newStore[S, T]()
+	client, err := generic.NewCloudEventAgentClient[*Resource[S, T]](ctx, agentOptions, store,
+		generic.NewStructuredStatusHashGetter(func(resource *Resource[S, T]) any { return resource.Status }),
+		NewCodec[S, T](dataType))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Agent[S, T]{
+		client:   client,
+		store:    store,
+		dataType: dataType,
+	}, nil
+}
+
+// Client returns the underlying agent client, e.g. to wait for the initial spec resync or to get the diagnostics.
+func (a *Agent[S, T]) Client() *generic.CloudEventAgentClient[*Resource[S, T]] {
+	return a.client
+}
+
+// Start subscribes the spec events and handles the received resources with the handler, the resources are saved
+// before they are handled and the deleted resources are removed, then it resyncs the resources from all the sources.
+func (a *Agent[S, T]) Start(ctx context.Context, handler Handler[S, T]) error {
+	a.client.Subscribe(ctx, func(action types.ResourceAction, resource *Resource[S, T]) error {
+		switch action {
+		case types.Added, types.Modified, types.Claimed:
+			a.store.upsertSpec(resource)
+		case types.Deleted:
+			a.store.delete(resource.ID)
+		}
+
+		return handler(action, resource)
+	})
+
+	return a.client.Resync(ctx, types.SourceAll)
+}
+
+// Get returns the received resource with the given ID.
+func (a *Agent[S, T]) Get(resourceID string) (*Resource[S, T], bool) {
+	return a.store.get(resourceID)
+}
+
+// List returns all the received resources.
+func (a *Agent[S, T]) List() []*Resource[S, T] {
+	resources, _ := a.store.List(types.ListOptions{})
+	return resources
+}
+
+// UpdateStatus updates the status of a received resource and reports it to the source of the resource.
+func (a *Agent[S, T]) UpdateStatus(ctx context.Context, resourceID string, status T) error {
+	updated, ok := a.store.updateStatus(resourceID, status)
+	if !ok {
+		return fmt.Errorf("the resource %s is not found", resourceID)
+	}
+
+	return a.client.Publish(ctx, types.CloudEventsType{
+		CloudEventsDataType: a.dataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "update_request",
+	}, updated)
+}
+
+// store keeps the received resources of an edge agent, it is the lister of the agent client.
+type store[S, T any] struct {
+	sync.RWMutex
+	resources map[string]*Resource[S, T]
+}
+
+var _ generic.Lister[*Resource[any, any]] = &store[any, any]{}
+
+func newStore[S, T any]() *store[S, T] {
+	return &store[S, T]{resources: map[string]*Resource[S, T]{}}
+}
+
+// upsertSpec saves a received resource, the status of the resource is maintained by the agent, so the last status is
+// kept.
+func (s *store[S, T]) upsertSpec(resource *Resource[S, T]) {
+	s.Lock()
+	defer s.Unlock()
+
+	if last, ok := s.resources[resource.ID]; ok {
+		resource.Status = last.Status
+	}
+	s.resources[resource.ID] = resource
+}
+
+// updateStatus replaces the resource with a copy that has the given status, so the resources that are handled by the
+// client are not changed.
+func (s *store[S, T]) updateStatus(resourceID string, status T) (*Resource[S, T], bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	last, ok := s.resources[resourceID]
+	if !ok {
+		return nil, false
+	}
+
+	updated := *last
+	updated.Status = status
+	s.resources[resourceID] = &updated
+	return &updated, true
+}
+
+func (s *store[S, T]) delete(resourceID string) {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.resources, resourceID)
+}
+
+func (s *store[S, T]) get(resourceID string) (*Resource[S, T], bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	resource, ok := s.resources[resourceID]
+	return resource, ok
+}
+
+func (s *store[S, T]) List(opts types.ListOptions) ([]*Resource[S, T], error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	resources := []*Resource[S, T]{}
+	for _, resource := range s.resources {
+		if opts.Source != types.SourceAll && resource.Source != opts.Source {
+			continue
+		}
+
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
@@ -0,0 +1,164 @@
+// Package edge is a slim agent profile for the agents of the non-Kubernetes targets, e.g. the IoT or edge devices. The
+// resources of an edge agent are the plain Go types of their specs and statuses, the agent receives the spec events
+// and publishes the status events without the open-cluster-management API types, the Kubernetes clients and informers,
+// only the Kubernetes meta types are required to implement the generic.ResourceObject.
+//
+// The spec of a resource is decoded from the data of the spec event, and the status of a resource is encoded as the
+// data of the status event, so a source publishes the specs of an edge agent with a codec that encodes the same spec
+// type.
+package edge
+
+import (
+	"fmt"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// Resource is a resource of an edge agent, the spec is sent by the source and the status is reported by the agent.
+type Resource[S, T any] struct {
+	// ID is the unique identifier of the resource.
+	ID string
+
+	// Version is the resource version of the spec.
+	Version string
+
+	// ClusterName is the cluster name of the agent that the resource is sent to.
+	ClusterName string
+
+	// Source is the source that the resource is sent from, the status of the resource is sent back to it.
+	Source string
+
+	// DeletionTimestamp is the time the resource is deleted from the source, the agent needs to clean up the resource
+	// from the target if it is set.
+	DeletionTimestamp *time.Time
+
+	Spec   S
+	Status T
+}
+
+var _ generic.ResourceObject = &Resource[any, any]{}
+
+func (r *Resource[S, T]) GetUID() kubetypes.UID {
+	return kubetypes.UID(r.ID)
+}
+
+func (r *Resource[S, T]) GetResourceVersion() string {
+	return r.Version
+}
+
+func (r *Resource[S, T]) GetDeletionTimestamp() *metav1.Time {
+	if r.DeletionTimestamp == nil {
+		return nil
+	}
+
+	return &metav1.Time{Time: *r.DeletionTimestamp}
+}
+
+// Deleting returns true if the resource is deleted from the source.
+func (r *Resource[S, T]) Deleting() bool {
+	return r.DeletionTimestamp != nil
+}
+
+// Codec is a codec to decode the spec events of a data type to the resources and encode the statuses of the resources
+// to the status events.
+type Codec[S, T any] struct {
+	dataType types.CloudEventsDataType
+}
+
+var _ generic.Codec[*Resource[any, any]] = &Codec[any, any]{}
+
+func NewCodec[S, T any](dataType types.CloudEventsDataType) *Codec[S, T] {
+	return &Codec[S, T]{dataType: dataType}
+}
+
+func (c *Codec[S, T]) EventDataType() types.CloudEventsDataType {
+	return c.dataType
+}
+
+// Encode the status of a resource to a status event, the spec of a resource is not encoded.
+func (c *Codec[S, T]) Encode(
+	source string, eventType types.CloudEventsType, resource *Resource[S, T]) (*cloudevents.Event, error) {
+	if eventType.CloudEventsDataType != c.dataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	if eventType.SubResource != types.SubResourceStatus {
+		return nil, fmt.Errorf("unsupported subresource %s", eventType.SubResource)
+	}
+
+	evt := types.NewEventBuilder(source, eventType).
+		WithResourceID(resource.ID).
+		WithStringResourceVersion(resource.Version).
+		WithClusterName(resource.ClusterName).
+		WithOriginalSource(resource.Source).
+		NewEvent()
+
+	if err := evt.SetData(cloudevents.ApplicationJSON, resource.Status); err != nil {
+		return nil, fmt.Errorf("failed to encode the status of the resource %s to a cloudevent: %v", resource.ID, err)
+	}
+
+	return &evt, nil
+}
+
+// Decode a spec event to a resource, the spec of a deleting resource is empty.
+func (c *Codec[S, T]) Decode(evt *cloudevents.Event) (*Resource[S, T], error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cloud event type %s, %v", evt.Type(), err)
+	}
+
+	if eventType.CloudEventsDataType != c.dataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	if eventType.SubResource != types.SubResourceSpec {
+		return nil, fmt.Errorf("unsupported subresource %s", eventType.SubResource)
+	}
+
+	evtExtensions := evt.Context.GetExtensions()
+
+	resourceID, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionResourceID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resourceid extension: %v", err)
+	}
+
+	resourceVersion, err := types.GetResourceVersion(*evt)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterName, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionClusterName])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clustername extension: %v", err)
+	}
+
+	resource := &Resource[S, T]{
+		ID:          resourceID,
+		Version:     resourceVersion,
+		ClusterName: clusterName,
+		Source:      evt.Source(),
+	}
+
+	if deletionTimestamp, ok := evtExtensions[types.ExtensionDeletionTimestamp]; ok {
+		timestamp, err := cloudeventstypes.ToTime(deletionTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deletiontimestamp extension: %v", err)
+		}
+		resource.DeletionTimestamp = &timestamp
+		return resource, nil
+	}
+
+	if err := evt.DataAs(&resource.Spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event data %s, %v", string(evt.Data()), err)
+	}
+
+	return resource, nil
+}
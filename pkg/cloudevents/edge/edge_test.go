@@ -0,0 +1,111 @@
+package edge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+var testDataType = types.CloudEventsDataType{
+	Group:    "io.open-cluster-management.edge",
+	Version:  "v1alpha1",
+	Resource: "firmwares",
+}
+
+type firmwareSpec struct {
+	Image string `json:"image"`
+}
+
+type firmwareStatus struct {
+	Installed string `json:"installed"`
+}
+
+func newSpecEvent(t *testing.T, resourceID string, version int64, spec *firmwareSpec) cloudevents.Event {
+	builder := types.NewEventBuilder("source1", types.CloudEventsType{
+		CloudEventsDataType: testDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}).WithResourceID(resourceID).WithResourceVersion(version).WithClusterName("device1")
+	if spec == nil {
+		evt := builder.WithDeletionTimestamp(time.Now()).NewEvent()
+		return evt
+	}
+
+	evt := builder.NewEvent()
+	if err := evt.SetData(cloudevents.ApplicationJSON, spec); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return evt
+}
+
+func TestAgent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakeClient := fake.NewCloudEventsFakeClient(
+		newSpecEvent(t, "firmware1", 1, &firmwareSpec{Image: "v1"}),
+		newSpecEvent(t, "firmware2", 1, &firmwareSpec{Image: "v1"}),
+		newSpecEvent(t, "firmware2", 2, nil),
+	)
+
+	agent, err := NewAgent[firmwareSpec, firmwareStatus](ctx,
+		fake.NewAgentOptions(fakeClient, "device1", "device1-agent"), testDataType)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	handled := make(chan types.ResourceAction, 3)
+	if err := agent.Start(ctx, func(action types.ResourceAction, resource *Resource[firmwareSpec, firmwareStatus]) error {
+		handled <- action
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, expected := range []types.ResourceAction{types.Added, types.Added, types.Deleted} {
+		select {
+		case action := <-handled:
+			if action != expected {
+				t.Errorf("expected %s, but got %s", expected, action)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout to handle the %s resource", expected)
+		}
+	}
+
+	resource, ok := agent.Get("firmware1")
+	if !ok {
+		t.Fatalf("expected the resource firmware1")
+	}
+	if resource.Spec.Image != "v1" || resource.Source != "source1" || resource.Deleting() {
+		t.Errorf("unexpected resource %v", resource)
+	}
+	if resources := agent.List(); len(resources) != 1 {
+		t.Errorf("expected 1 resource, but got %v", resources)
+	}
+
+	if err := agent.UpdateStatus(ctx, "firmware2", firmwareStatus{Installed: "v1"}); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+	if err := agent.UpdateStatus(ctx, "firmware1", firmwareStatus{Installed: "v1"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	sent := fakeClient.GetSentEvents()
+	evt := sent[len(sent)-1]
+	status := &firmwareStatus{}
+	if err := evt.DataAs(status); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if status.Installed != "v1" || evt.Extensions()[types.ExtensionOriginalSource] != "source1" {
+		t.Errorf("unexpected status event %v", evt)
+	}
+	if resource, _ := agent.Get("firmware1"); resource.Status.Installed != "v1" {
+		t.Errorf("expected the status is updated, but got %v", resource)
+	}
+}
@@ -3,12 +3,13 @@ package generic
 import (
 	"context"
 	"fmt"
-	"strconv"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
 
 	"k8s.io/klog/v2"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/features"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
@@ -21,8 +22,18 @@ import (
 type CloudEventAgentClient[T ResourceObject] struct {
 	*baseClient
 	lister           Lister[T]
-	codecs           map[types.CloudEventsDataType]Codec[T]
+	codecs           *CodecRegistry[T]
 	statusHashGetter StatusHashGetter[T]
+	versionTracker   *resourceVersionTracker
+	specs            *specCache[T]
+	subscribers      *subscriberRegistry[T]
+	registrations    *registrationTracker
+	handovers        *handoverTracker
+	statusCoalescer  *coalescer[T]
+	specSync         *specSyncTracker
+	clusterClaim     *clusterClaim
+	hashAlgorithm    string
+	resyncedAction   bool
 	agentID          string
 	clusterName      string
 }
@@ -41,29 +52,73 @@ func NewCloudEventAgentClient[T ResourceObject](
 	statusHashGetter StatusHashGetter[T],
 	codecs ...Codec[T],
 ) (*CloudEventAgentClient[T], error) {
-	baseClient := &baseClient{
-		cloudEventsOptions:     agentOptions.CloudEventsOptions,
-		cloudEventsRateLimiter: NewRateLimiter(agentOptions.EventRateLimit),
-		reconnectedChan:        make(chan struct{}),
+	baseClient, err := newBaseClient(agentOptions.CloudEventsOptions, agentOptions.EventRateLimit, agentOptions.CloudEventsClientOptions)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := baseClient.connect(ctx); err != nil {
-		return nil, err
+	baseClient.switchChan = make(chan options.CloudEventsOptions)
+
+	// the cluster is claimed before the client is connected, so the agent does not receive the resources of a cluster
+	// that is claimed by another agent
+	var claim *clusterClaim
+	if agentOptions.ClusterClaim != nil {
+		if agentOptions.ClusterClaim.Claimer == nil {
+			return nil, fmt.Errorf("the claimer of the cluster claim is required")
+		}
+
+		claim = newClusterClaim(agentOptions.ClusterClaim, agentOptions.ClusterName, agentOptions.AgentID)
+		if err := claim.acquire(ctx); err != nil {
+			return nil, err
+		}
 	}
 
-	evtCodes := make(map[types.CloudEventsDataType]Codec[T])
-	for _, codec := range codecs {
-		evtCodes[codec.EventDataType()] = codec
+	// the claim is released if the client fails to start after the cluster is claimed, so the agent can be restarted
+	// without waiting for the claim to expire
+	started := false
+	defer func() {
+		if claim != nil && !started {
+			claim.release(context.Background())
+		}
+	}()
+
+	if err := baseClient.connect(ctx); err != nil {
+		return nil, err
 	}
 
-	return &CloudEventAgentClient[T]{
+	client := &CloudEventAgentClient[T]{
 		baseClient:       baseClient,
 		lister:           lister,
-		codecs:           evtCodes,
+		codecs:           NewCodecRegistry(codecs...),
 		statusHashGetter: statusHashGetter,
+		versionTracker:   newResourceVersionTracker(versionComparator(agentOptions.VersionComparator), baseClient.clock),
+		specs:            newSpecCache[T](),
+		subscribers:      &subscriberRegistry[T]{},
+		registrations:    newRegistrationTracker(),
+		handovers:        newHandoverTracker(),
+		specSync:         newSpecSyncTracker(versionComparator(agentOptions.VersionComparator)),
 		agentID:          agentOptions.AgentID,
 		clusterName:      agentOptions.ClusterName,
-	}, nil
+		clusterClaim:     claim,
+		hashAlgorithm:    statusHashAlgorithm(agentOptions.StatusHashAlgorithm),
+		resyncedAction:   agentOptions.SpecResyncedAction,
+	}
+
+	if agentOptions.StatusCoalesceWindow > 0 {
+		client.statusCoalescer = newCoalescer(agentOptions.StatusCoalesceWindow, baseClient.clock, statusHashGetter,
+			func(ctx context.Context, eventType types.CloudEventsType, obj T) error {
+				_, err := client.publishObject(ctx, eventType, obj)
+				return err
+			}, agentOptions.StatusCoalesceErrorHandler)
+		baseClient.flushers = append(baseClient.flushers, client.statusCoalescer.flushAll)
+	}
+
+	if client.clusterClaim != nil {
+		client.startClusterClaim(ctx)
+	}
+
+	started = true
+	return client, nil
 }
 
 // ReconnectedChan returns a chan which indicates the source/agent client is reconnected.
@@ -72,8 +127,55 @@ func (c *CloudEventAgentClient[T]) ReconnectedChan() <-chan struct{} {
 	return c.reconnectedChan
 }
 
+// RegisterCodec registers a codec to the client, the client will encode/decode the events with the event data type of
+// the codec after the registration.
+func (c *CloudEventAgentClient[T]) RegisterCodec(codec Codec[T]) error {
+	return c.codecs.Register(codec)
+}
+
+// EventDataTypes returns the event data types that are registered in the client.
+func (c *CloudEventAgentClient[T]) EventDataTypes() []types.CloudEventsDataType {
+	return c.codecs.EventDataTypes()
+}
+
+// SwitchTransport switches the client to another transport at runtime, e.g. to migrate the agents from one broker to
+// another without restarting them. The subscriptions are stopped and the connection of the current transport is closed,
+// then the client is reconnected with the new transport, and the reconnection is notified with the ReconnectedChan to
+// force a resync, so the resources are resynced with the sources on the new broker without being recreated.
+//
+// The cluster name, the agent ID and the tenant of the client cannot be changed, the given options must be built for
+// the same agent, e.g. with mqtt.NewAgentOptions or grpc.NewAgentOptions. It returns once the client starts to switch.
+func (c *CloudEventAgentClient[T]) SwitchTransport(ctx context.Context, agentOptions *options.CloudEventsAgentOptions) error {
+	if agentOptions.ClusterName != c.clusterName || agentOptions.AgentID != c.agentID {
+		return fmt.Errorf("failed to switch the transport, the options are for the agent %s of the cluster %s",
+			agentOptions.AgentID, agentOptions.ClusterName)
+	}
+
+	if agentOptions.TenantID != c.tenantID {
+		return fmt.Errorf("failed to switch the transport, the options are for the tenant %s", agentOptions.TenantID)
+	}
+
+	return c.switchTransport(ctx, agentOptions.CloudEventsOptions)
+}
+
 // Resync the resources spec by sending a spec resync request from the current to the given source.
-func (c *CloudEventAgentClient[T]) Resync(ctx context.Context, source string) error {
+func (c *CloudEventAgentClient[T]) Resync(ctx context.Context, source string) (err error) {
+	if err := c.checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := c.clusterClaim.check(); err != nil {
+		return err
+	}
+
+	done, err := c.startResync(source)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		done(err)
+	}()
+
 	// list the resource objects that are maintained by the current agent with the given source
 	objs, err := c.lister.List(types.ListOptions{Source: source, ClusterName: c.clusterName})
 	if err != nil {
@@ -82,19 +184,114 @@ func (c *CloudEventAgentClient[T]) Resync(ctx context.Context, source string) er
 
 	resources := &payload.ResourceVersionList{Versions: make([]payload.ResourceVersion, len(objs))}
 	for i, obj := range objs {
-		resourceVersion, err := strconv.ParseInt(obj.GetResourceVersion(), 10, 64)
-		if err != nil {
+		resources.Versions[i] = payload.NewResourceVersion(string(obj.GetUID()), obj.GetResourceVersion())
+	}
+
+	// only resync the resources whose event data type is registered
+	for _, eventDataType := range c.codecs.EventDataTypes() {
+		eventType := types.CloudEventsType{
+			CloudEventsDataType: eventDataType,
+			SubResource:         types.SubResourceSpec,
+			Action:              types.ResyncRequestAction,
+		}
+
+		evt := types.NewEventBuilder(c.agentID, eventType).
+			WithOriginalSource(source).
+			WithClusterName(c.clusterName).
+			WithCapabilities(c.featureNegotiator.Local().String()).
+			NewEvent()
+		if err := evt.SetData(cloudevents.ApplicationJSON, resources); err != nil {
+			return fmt.Errorf("%w: failed to set data to cloud event: %w", ErrEncode, err)
+		}
+
+		// the response of the request may be received before it is published
+		c.specSync.request(evt.ID())
+
+		if err := c.publish(ctx, evt); err != nil {
 			return err
 		}
+	}
+
+	return nil
+}
+
+// WaitForSpecSync blocks until the initial spec resync of the agent is completed or the context is done, it returns
+// the error of the context if the context is done first. The spec resync is completed once a source responds to one of
+// the spec resync requests of the agent with a resync complete event and the agent has all the resources that the
+// source maintains for the cluster, so the agent reconcilers should wait for it before they delete the local resources
+// that they believe are orphaned. The agent and the sources must enable the features.ResyncBarrier capability, and the
+// agent must subscribe and resync before waiting. If the spec resync request is broadcast to all the sources, the spec
+// is synced once the first source completes its resync response.
+func (c *CloudEventAgentClient[T]) WaitForSpecSync(ctx context.Context) error {
+	if c.HasSpecSynced() {
+		return nil
+	}
 
-		resources.Versions[i] = payload.ResourceVersion{
-			ResourceID:      string(obj.GetUID()),
-			ResourceVersion: resourceVersion,
+	ticker := c.clock.NewTicker(specSyncCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.specSync.synced:
+			return nil
+		case <-ticker.C():
+			if c.HasSpecSynced() {
+				return nil
+			}
 		}
 	}
+}
 
-	// only resync the resources whose event data type is registered
-	for eventDataType := range c.codecs {
+// HasSpecSynced returns true if the initial spec resync of the agent is completed, it can be used as the
+// cache.InformerSynced of the agent controllers.
+func (c *CloudEventAgentClient[T]) HasSpecSynced() bool {
+	if c.specSync.hasSynced() {
+		return true
+	}
+
+	objs, err := c.lister.List(types.ListOptions{Source: types.SourceAll, ClusterName: c.clusterName})
+	if err != nil {
+		klog.Warningf("failed to list the resources of the cluster %s, %v", c.clusterName, err)
+		return false
+	}
+
+	versions := make([]payload.ResourceVersion, len(objs))
+	for i, obj := range objs {
+		versions[i] = payload.NewResourceVersion(string(obj.GetUID()), obj.GetResourceVersion())
+	}
+
+	c.specSync.check(versions)
+	return c.specSync.hasSynced()
+}
+
+// ResyncResource resyncs the spec of a single resource by sending a targeted spec resync request to the sources, so a
+// suspicious resource is resynced without resyncing all the resources of the agent. The cluster name must be the
+// cluster of the agent. The request is broadcast to all the sources, the source that maintains the resource resends its
+// spec, a resource that is no longer maintained by any source is deleted by the next Resync.
+func (c *CloudEventAgentClient[T]) ResyncResource(ctx context.Context, clusterName, resourceID string) error {
+	if clusterName != c.clusterName {
+		return fmt.Errorf("the resource %s of the cluster %s cannot be resynced by the agent of the cluster %s",
+			resourceID, clusterName, c.clusterName)
+	}
+
+	if err := c.checkContext(ctx); err != nil {
+		return err
+	}
+
+	objs, err := c.lister.List(types.ListOptions{Source: types.SourceAll, ClusterName: c.clusterName})
+	if err != nil {
+		return err
+	}
+
+	resources := &payload.ResourceVersionList{Versions: []payload.ResourceVersion{}}
+	if obj, exists := getObj(resourceID, objs); exists {
+		resources.Versions = append(resources.Versions,
+			payload.NewResourceVersion(string(obj.GetUID()), obj.GetResourceVersion()))
+	}
+
+	for _, eventDataType := range c.codecs.EventDataTypes() {
 		eventType := types.CloudEventsType{
 			CloudEventsDataType: eventDataType,
 			SubResource:         types.SubResourceSpec,
@@ -102,11 +299,13 @@ func (c *CloudEventAgentClient[T]) Resync(ctx context.Context, source string) er
 		}
 
 		evt := types.NewEventBuilder(c.agentID, eventType).
-			WithOriginalSource(source).
+			WithResourceID(resourceID).
+			WithOriginalSource(types.SourceAll).
 			WithClusterName(c.clusterName).
+			WithCapabilities(c.featureNegotiator.Local().String()).
 			NewEvent()
 		if err := evt.SetData(cloudevents.ApplicationJSON, resources); err != nil {
-			return fmt.Errorf("failed to set data to cloud event: %v", err)
+			return fmt.Errorf("%w: failed to set data to cloud event: %w", ErrEncode, err)
 		}
 
 		if err := c.publish(ctx, evt); err != nil {
@@ -117,47 +316,189 @@ func (c *CloudEventAgentClient[T]) Resync(ctx context.Context, source string) er
 	return nil
 }
 
-// Publish a resource status from an agent to a source.
+// Register announces the agent to the sources with the given registration metadata and waits for the registration
+// response of a source until the context is done, the cluster name, the agent ID and the capabilities of the metadata
+// default to the ones of the client. The client must subscribe before the registration to receive the response, and the
+// agent is expected to handle the resource spec after it is registered. It returns ErrRegistrationRejected if the agent
+// is rejected.
+func (c *CloudEventAgentClient[T]) Register(
+	ctx context.Context, metadata payload.AgentRegistration) (*payload.AgentBootstrap, error) {
+	if metadata.ClusterName == "" {
+		metadata.ClusterName = c.clusterName
+	}
+	if metadata.AgentID == "" {
+		metadata.AgentID = c.agentID
+	}
+	if metadata.Capabilities == nil {
+		metadata.Capabilities = c.featureNegotiator.Local().List()
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: types.RegistrationDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.RegisterRequestAction,
+	}
+
+	evt := types.NewEventBuilder(c.agentID, eventType).
+		WithOriginalSource(types.SourceAll).
+		WithClusterName(c.clusterName).
+		NewEvent()
+	if err := evt.SetData(cloudevents.ApplicationJSON, metadata); err != nil {
+		return nil, fmt.Errorf("%w: failed to set data to cloud event: %w", ErrEncode, err)
+	}
+
+	responseChan := c.registrations.add(evt.ID())
+	defer c.registrations.remove(evt.ID())
+
+	if err := c.publish(ctx, evt); err != nil {
+		return nil, err
+	}
+
+	select {
+	case bootstrap := <-responseChan:
+		if !bootstrap.Accepted {
+			return bootstrap, fmt.Errorf("%w: %s", ErrRegistrationRejected, bootstrap.Message)
+		}
+		return bootstrap, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to wait for the registration response, %w", ctx.Err())
+	}
+}
+
+// Call sends a call request to the given source and waits for the call response, the request is sent as the call
+// request of its data type, see the Call of the CloudEventSourceClient.
+func (c *CloudEventAgentClient[T]) Call(ctx context.Context, sourceID string, request cloudevents.Event) (cloudevents.Event, error) {
+	return c.call(ctx, request, c.agentID, types.SubResourceStatus, c.clusterName, sourceID, c.clusterName)
+}
+
+// respondCallRequest responds the call request of a source, the response is sent to the source.
+func (c *CloudEventAgentClient[T]) respondCallRequest(ctx context.Context, evt cloudevents.Event) error {
+	sourceID, err := replyTo(evt)
+	if err != nil {
+		return err
+	}
+
+	return c.respondCall(ctx, evt, c.agentID, types.SubResourceStatus, c.clusterName, sourceID)
+}
+
+// Publish a resource status from an agent to a source. If the status coalescing is enabled, the status is published
+// asynchronously at the end of the coalescing window of the resource, see the StatusCoalesceWindow of the options, the
+// errors of the coalesced status are passed to the StatusCoalesceErrorHandler of the options.
 func (c *CloudEventAgentClient[T]) Publish(ctx context.Context, eventType types.CloudEventsType, obj T) error {
-	codec, ok := c.codecs[eventType.CloudEventsDataType]
+	if err := c.checkContext(ctx); err != nil {
+		return err
+	}
+
+	if c.statusCoalescer != nil && eventType.SubResource == types.SubResourceStatus {
+		if _, ok := c.codecs.Get(eventType.CloudEventsDataType); !ok {
+			return fmt.Errorf("%w: failed to find a codec for event %s", ErrUnsupportedType, eventType.CloudEventsDataType)
+		}
+		return c.statusCoalescer.add(ctx, eventType, obj)
+	}
+
+	_, err := c.publishObject(ctx, eventType, obj)
+	return err
+}
+
+// publishObject encodes the resource object to an event and publishes it, the published event is returned.
+func (c *CloudEventAgentClient[T]) publishObject(
+	ctx context.Context, eventType types.CloudEventsType, obj T) (*cloudevents.Event, error) {
+	codec, ok := c.codecs.Get(eventType.CloudEventsDataType)
 	if !ok {
-		return fmt.Errorf("failed to find a codec for event %s", eventType.CloudEventsDataType)
+		return nil, fmt.Errorf("%w: failed to find a codec for event %s", ErrUnsupportedType, eventType.CloudEventsDataType)
 	}
 
 	if eventType.SubResource != types.SubResourceStatus {
-		return fmt.Errorf("unsupported event eventType %s", eventType)
+		return nil, fmt.Errorf("%w: unsupported event eventType %s", ErrUnsupportedType, eventType)
+	}
+
+	if err := c.clusterClaim.check(); err != nil {
+		return nil, err
 	}
 
 	evt, err := codec.Encode(c.agentID, eventType, obj)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("%w: failed to encode the resource %s, %w", ErrEncode, obj.GetUID(), err)
 	}
 
 	if err := c.publish(ctx, *evt); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return evt, nil
 }
 
 // Subscribe the events that are from the source status resync request or source resource spec request.
 // For status resync request, agent publish the current resources status back as response.
 // For resource spec request, agent receives resource spec and handles the spec with resource handlers.
-func (c *CloudEventAgentClient[T]) Subscribe(ctx context.Context, handlers ...ResourceHandler[T]) {
-	c.subscribe(ctx, func(ctx context.Context, evt cloudevents.Event) {
-		c.receive(ctx, evt, handlers...)
-	})
+//
+// Subscribe can be called multiple times, the resource spec is handled by the handlers of all the subscriptions.
+// A subscription is removed when its context is done or it is unsubscribed, and the client keeps receiving events
+// until it is closed or the context that it is created with is done.
+func (c *CloudEventAgentClient[T]) Subscribe(ctx context.Context, handlers ...ResourceHandler[T]) Subscription {
+	return c.subscribers.add(ctx, nil, handlers, c.startReceiving())
+}
+
+// SubscribeWithEvents is same as Subscribe, but the received spec events are passed to the event handlers alongside
+// the resource objects that are decoded from them.
+func (c *CloudEventAgentClient[T]) SubscribeWithEvents(ctx context.Context, handlers ...EventHandler[T]) Subscription {
+	return c.subscribers.addEventHandlers(ctx, nil, handlers, c.startReceiving())
+}
+
+// startReceiving returns the func that starts receiving the events for the first subscription, the events are received
+// until the client is closed or its context is done, so the other subscriptions are not stopped with the first one.
+func (c *CloudEventAgentClient[T]) startReceiving() func() {
+	return func() {
+		c.subscribe(c.ctx, func(ctx context.Context, evt cloudevents.Event) {
+			c.receive(ctx, evt, c.subscribers.handlers(ctx, evt)...)
+		})
+	}
 }
 
 func (c *CloudEventAgentClient[T]) receive(ctx context.Context, evt cloudevents.Event, handlers ...ResourceHandler[T]) {
 	klog.V(4).Infof("Received event:\n%s", evt)
 
+	// a standby agent discards the received events before they are recorded by the dedupe cache, so the events can be
+	// handled once they are resent after the agent acquires the claim
+	if err := c.clusterClaim.check(); err != nil {
+		c.discard(evt, err.Error())
+		return
+	}
+
+	if c.discardDuplicated(evt) {
+		return
+	}
+
 	eventType, err := types.ParseCloudEventsType(evt.Type())
 	if err != nil {
 		klog.Errorf("failed to parse cloud event type %s, %v", evt.Type(), err)
 		return
 	}
 
+	if eventType.Action == types.CallResponseAction {
+		if err := c.calls.complete(evt); err != nil {
+			klog.V(4).Infof("ignore the call response %s, %v", evt.ID(), err)
+		}
+
+		return
+	}
+
+	if eventType.Action == types.CallRequestAction {
+		if err := c.respondCallRequest(ctx, evt); err != nil {
+			klog.Errorf("failed to respond the call request %s, %v", evt.ID(), err)
+		}
+
+		return
+	}
+
+	if eventType.Action == types.RegisterResponseAction {
+		if err := c.registrations.complete(evt); err != nil {
+			klog.V(4).Infof("ignore the registration response %s, %v", evt.ID(), err)
+		}
+
+		return
+	}
+
 	if eventType.Action == types.ResyncRequestAction {
 		if eventType.SubResource != types.SubResourceStatus {
 			klog.Warningf("unsupported resync event type %s, ignore", eventType)
@@ -176,22 +517,79 @@ func (c *CloudEventAgentClient[T]) receive(ctx context.Context, evt cloudevents.
 		return
 	}
 
-	codec, ok := c.codecs[eventType.CloudEventsDataType]
+	if eventType.Action == types.ResyncCompleteAction {
+		c.completeSpecSync(evt)
+		return
+	}
+
+	if c.discardExpired(evt) {
+		return
+	}
+
+	evt = c.rescopeBroadcast(evt)
+
+	codec, ok := c.codecs.Get(eventType.CloudEventsDataType)
 	if !ok {
 		klog.Warningf("failed to find the codec for event %s, ignore", eventType.CloudEventsDataType)
+		c.sendDeliveryReceipt(ctx, evt, eventType.CloudEventsDataType, "", "",
+			fmt.Sprintf("unsupported event data type %s", eventType.CloudEventsDataType))
 		return
 	}
 
 	obj, err := codec.Decode(&evt)
 	if err != nil {
 		klog.Errorf("failed to decode spec, %v", err)
+		c.sendDeliveryReceipt(ctx, evt, eventType.CloudEventsDataType, "", "",
+			fmt.Sprintf("failed to decode spec, %v", err))
 		return
 	}
 
-	action, err := c.specAction(evt.Source(), obj)
-	if err != nil {
-		klog.Errorf("failed to generate spec action %s, %v", evt, err)
+	resourceID := string(obj.GetUID())
+	reject := func(reason string) {
+		c.discard(evt, reason)
+		c.sendDeliveryReceipt(ctx, evt, eventType.CloudEventsDataType, resourceID, obj.GetResourceVersion(), reason)
+	}
+
+	switch eventType.Action {
+	case types.HandoverRequestAction:
+		if err := c.handover(evt, resourceID); err != nil {
+			reject(err.Error())
+			return
+		}
+		c.sendDeliveryReceipt(ctx, evt, eventType.CloudEventsDataType, resourceID, obj.GetResourceVersion(), "")
 		return
+	case types.ClaimRequestAction:
+		if err := c.claim(evt, resourceID); err != nil {
+			reject(err.Error())
+			return
+		}
+	default:
+		if owner, claimed := c.handovers.owner(resourceID); claimed && owner != evt.Source() {
+			reject(fmt.Sprintf("the resource %s is claimed by the source %s", resourceID, owner))
+			return
+		}
+	}
+
+	if outOfOrder, lastVersion := c.versionTracker.isOutOfOrder(resourceID, obj.GetResourceVersion()); outOfOrder {
+		// the event may be redelivered or reordered by the broker, drop it to avoid regressing the resource spec
+		reject(fmt.Sprintf("the resource version %s is older than the last processed resource version %s",
+			obj.GetResourceVersion(), lastVersion))
+		return
+	}
+
+	// the spec is delivered, acknowledge it before it is applied by the handlers
+	c.sendDeliveryReceipt(ctx, evt, eventType.CloudEventsDataType, resourceID, obj.GetResourceVersion(), "")
+
+	// keep the last received spec, so the agent reconcilers can get the intended state of the resource
+	c.specs.update(obj)
+
+	action := types.Claimed
+	if eventType.Action != types.ClaimRequestAction {
+		action, err = c.specAction(evt.Source(), eventType.Action, obj)
+		if err != nil {
+			klog.Errorf("failed to generate spec action %s, %v", evt, err)
+			return
+		}
 	}
 
 	if len(action) == 0 {
@@ -199,31 +597,157 @@ func (c *CloudEventAgentClient[T]) receive(ctx context.Context, evt cloudevents.
 		return
 	}
 
-	for _, handler := range handlers {
-		if err := handler(action, obj); err != nil {
-			klog.Errorf("failed to handle spec event %s, %v", evt, err)
+	// the resource version is processed once the handlers succeed, a failed spec is not processed, so it does not
+	// prevent the redelivered spec of the resource from being handled
+	processed := func() {
+		if action != types.Deleted {
+			c.versionTracker.processed(resourceID, obj.GetResourceVersion())
+		}
+	}
+
+	succeeded := true
+	for index, handler := range handlers {
+		handler := handler
+		if !c.handlerInvoker.invoke(ctx, evt, fmt.Sprintf("%s/%d", resourceID, index), func() error {
+			return handler(action, obj)
+		}, processed) {
+			succeeded = false
 		}
 	}
+
+	if action == types.Deleted {
+		// keep the resource version of the deleted resource, so a delayed update does not recreate the resource
+		c.versionTracker.deleted(resourceID, obj.GetResourceVersion())
+		c.handovers.forget(resourceID)
+		return
+	}
+
+	if succeeded {
+		processed()
+	}
+}
+
+// completeSpecSync records the resources of a resync complete event if it completes a spec resync request of the
+// agent, and checks whether the spec is synced.
+func (c *CloudEventAgentClient[T]) completeSpecSync(evt cloudevents.Event) {
+	requestID, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionCorrelationID])
+	if err != nil {
+		klog.Warningf("failed to get the request ID of the resync complete event %s, %v", evt.ID(), err)
+		return
+	}
+
+	resources, err := payload.DecodeSpecResyncRequest(evt)
+	if err != nil {
+		klog.Errorf("failed to decode the resync complete event %s, %v", evt.ID(), err)
+		return
+	}
+
+	if !c.specSync.complete(requestID, resources.Versions) {
+		klog.V(4).Infof("ignore the resync complete event %s of the request %s", evt.ID(), requestID)
+		return
+	}
+
+	c.HasSpecSynced()
+}
+
+// handover marks a resource as migrating to the target source of a handover request, the resource must belong to the
+// source of the request.
+func (c *CloudEventAgentClient[T]) handover(evt cloudevents.Event, resourceID string) error {
+	target, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionHandoverTarget])
+	if err != nil {
+		return fmt.Errorf("failed to get the handover target, %v", err)
+	}
+
+	objs, err := c.lister.List(types.ListOptions{ClusterName: c.clusterName, Source: evt.Source()})
+	if err != nil {
+		return err
+	}
+
+	if _, exists := getObj(resourceID, objs); !exists {
+		return fmt.Errorf("the resource %s does not belong to the source %s", resourceID, evt.Source())
+	}
+
+	c.handovers.mark(resourceID, evt.Source(), target)
+	return nil
+}
+
+// claim transfers a migrating resource to the source of a claim request, the resource versions of the claiming source
+// are not related to the ones of the previous source, so the processed resource version of the resource is reset.
+func (c *CloudEventAgentClient[T]) claim(evt cloudevents.Event, resourceID string) error {
+	from, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionHandoverSource])
+	if err != nil {
+		return fmt.Errorf("failed to get the handover source, %v", err)
+	}
+
+	if err := c.handovers.claim(resourceID, from, evt.Source()); err != nil {
+		return err
+	}
+
+	c.versionTracker.forget(resourceID)
+	return nil
+}
+
+// OutOfOrderEvents returns the number of the received spec events that are dropped because their resource versions
+// are older than the last processed resource versions.
+func (c *CloudEventAgentClient[T]) OutOfOrderEvents() int64 {
+	return c.versionTracker.outOfOrderEvents()
+}
+
+// GetByResourceID returns the last received spec of the resource with the given resource ID, it returns false if the
+// spec of the resource is not received or the resource is deleted.
+func (c *CloudEventAgentClient[T]) GetByResourceID(resourceID string) (T, bool) {
+	return c.specs.get(resourceID)
+}
+
+// ResourceVersions returns the IDs and the resource versions of all the resources whose specs are received by the
+// agent, the deleted resources are not included.
+func (c *CloudEventAgentClient[T]) ResourceVersions() []payload.ResourceVersion {
+	return c.specs.versions()
 }
 
 // Upon receiving the status resync event, the agent responds by sending resource status events to the broker as
 // follows:
+//   - If the event has the resource ID, the agent returns the status of the resource with the ID if it maintains it.
 //   - If the event payload is empty, the agent returns the status of all resources it maintains.
+//   - If the status hashes of the event payload are calculated by another algorithm, the agent returns the status of
+//     all resources it maintains.
 //   - If the event payload is not empty, the agent retrieves the resource with the specified ID and compares the
 //     received resource status hash with the current resource status hash. If they are not equal, the agent sends the
 //     resource status message.
 func (c *CloudEventAgentClient[T]) respondResyncStatusRequest(
 	ctx context.Context, eventDataType types.CloudEventsDataType, evt cloudevents.Event) error {
+	recorder := newResyncRecorder(eventDataType, evt)
+
 	objs, err := c.lister.List(types.ListOptions{ClusterName: c.clusterName, Source: evt.Source()})
 	if err != nil {
 		return err
 	}
 
+	// the source advertises its features with the resync request
+	c.featureNegotiator.Observe(evt.Source(), features.FromEvent(evt))
+
+	recorder.report.Source = evt.Source()
+	recorder.report.Resources = len(objs)
+
 	statusHashes, err := payload.DecodeStatusResyncRequest(evt)
 	if err != nil {
 		return err
 	}
 
+	if resourceID := resyncResourceID(evt); len(resourceID) != 0 {
+		// the targeted resync request always resends the status of the resource
+		objs = filterResource(resourceID, objs)
+		recorder.report.Resources = len(objs)
+		statusHashes.Hashes = nil
+	}
+
+	if algorithm := resyncStatusHashAlgorithm(evt); algorithm != c.hashAlgorithm {
+		// the status hashes of another algorithm are not comparable
+		klog.Warningf("the status hashes of the source %s are calculated by %s rather than %s, resend all the status",
+			evt.Source(), algorithm, c.hashAlgorithm)
+		statusHashes.Hashes = nil
+	}
+
 	eventType := types.CloudEventsType{
 		CloudEventsDataType: eventDataType,
 		SubResource:         types.SubResourceStatus,
@@ -233,11 +757,14 @@ func (c *CloudEventAgentClient[T]) respondResyncStatusRequest(
 	if len(statusHashes.Hashes) == 0 {
 		// publish all resources status
 		for _, obj := range objs {
-			if err := c.Publish(ctx, eventType, obj); err != nil {
+			resentEvt, err := c.publishObject(ctx, eventType, obj)
+			if err != nil {
 				return err
 			}
+			recorder.resent(resentEvt)
 		}
 
+		recorder.done(c.resyncReporter)
 		return nil
 	}
 
@@ -259,15 +786,23 @@ func (c *CloudEventAgentClient[T]) respondResyncStatusRequest(
 			continue
 		}
 
-		if err := c.Publish(ctx, eventType, obj); err != nil {
+		resentEvt, err := c.publishObject(ctx, eventType, obj)
+		if err != nil {
 			return err
 		}
+		recorder.resent(resentEvt)
 	}
 
+	recorder.done(c.resyncReporter)
 	return nil
 }
 
-func (c *CloudEventAgentClient[T]) specAction(source string, obj T) (evt types.ResourceAction, err error) {
+// specAction returns the action of a received spec with the last resource of the agent, the spec of a resource that
+// the agent does not have is ignored if it is deleting, and the spec that has the same resource version as the last
+// resource is ignored unless it is resent by a resync response and the SpecResyncedAction of the options is
+// enabled.
+func (c *CloudEventAgentClient[T]) specAction(
+	source string, eventAction types.EventAction, obj T) (evt types.ResourceAction, err error) {
 	objs, err := c.lister.List(types.ListOptions{ClusterName: c.clusterName, Source: source})
 	if err != nil {
 		return evt, err
@@ -275,6 +810,10 @@ func (c *CloudEventAgentClient[T]) specAction(source string, obj T) (evt types.R
 
 	lastObj, exists := getObj(string(obj.GetUID()), objs)
 	if !exists {
+		if !obj.GetDeletionTimestamp().IsZero() {
+			// the resource is already deleted from the agent
+			return evt, nil
+		}
 		return types.Added, nil
 	}
 
@@ -283,6 +822,9 @@ func (c *CloudEventAgentClient[T]) specAction(source string, obj T) (evt types.R
 	}
 
 	if obj.GetResourceVersion() == lastObj.GetResourceVersion() {
+		if c.resyncedAction && eventAction == types.ResyncResponseAction {
+			return types.SpecResynced, nil
+		}
 		return evt, nil
 	}
 
@@ -2,7 +2,10 @@ package generic
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,7 +14,10 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	testingclock "k8s.io/utils/clock/testing"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
@@ -157,6 +163,273 @@ func TestAgentPublish(t *testing.T) {
 	}
 }
 
+func TestAgentStatusCoalescing(t *testing.T) {
+	client := fake.NewCloudEventsFakeClient()
+	agentOptions := fake.NewAgentOptions(client, "cluster1", testAgentName)
+	agentOptions.StatusCoalesceWindow = 100 * time.Millisecond
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	agentOptions.Clock = fakeClock
+	agent, err := NewCloudEventAgentClient[*mockResource](
+		context.TODO(), agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}
+
+	publish := func(resourceVersion, status string, deleting bool) {
+		resource := &mockResource{UID: "1234", ResourceVersion: resourceVersion, Status: status, Namespace: "cluster1"}
+		if deleting {
+			resource.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		if err := agent.Publish(context.TODO(), eventType, resource); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	sentVersions := func() []string {
+		versions := []string{}
+		for _, evt := range client.GetSentEvents() {
+			versions = append(versions, fmt.Sprintf("%v", evt.Extensions()[types.ExtensionResourceVersion]))
+		}
+		return versions
+	}
+
+	// the flapping status is coalesced into the latest status
+	publish("1", "progressing", false)
+	publish("1", "available", false)
+	publish("1", "progressing", false)
+	if versions := sentVersions(); len(versions) != 0 {
+		t.Errorf("expected no sent events within the window, but got %v", versions)
+	}
+
+	fakeClock.Step(300 * time.Millisecond)
+	if versions := sentVersions(); !reflect.DeepEqual(versions, []string{"1"}) {
+		t.Errorf("expected %v, but got %v", []string{"1"}, versions)
+	}
+
+	// the status is flapped back to the published status, it is not published
+	publish("1", "available", false)
+	publish("1", "progressing", false)
+	fakeClock.Step(300 * time.Millisecond)
+	if versions := sentVersions(); !reflect.DeepEqual(versions, []string{"1"}) {
+		t.Errorf("expected %v, but got %v", []string{"1"}, versions)
+	}
+
+	// the status of the deleting resource is published immediately, the pending status is dropped
+	publish("2", "available", false)
+	publish("3", "deleted", true)
+	if versions := sentVersions(); !reflect.DeepEqual(versions, []string{"1", "3"}) {
+		t.Errorf("expected %v, but got %v", []string{"1", "3"}, versions)
+	}
+
+	fakeClock.Step(300 * time.Millisecond)
+	if versions := sentVersions(); !reflect.DeepEqual(versions, []string{"1", "3"}) {
+		t.Errorf("expected %v, but got %v", []string{"1", "3"}, versions)
+	}
+}
+
+func TestAgentStatusCoalescingClose(t *testing.T) {
+	client := fake.NewCloudEventsFakeClient()
+	agentOptions := fake.NewAgentOptions(client, "cluster1", testAgentName)
+	agentOptions.StatusCoalesceWindow = time.Hour
+	agent, err := NewCloudEventAgentClient[*mockResource](
+		context.TODO(), agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}
+
+	resource := &mockResource{UID: "1234", ResourceVersion: "1", Status: "available", Namespace: "cluster1"}
+	if err := agent.Publish(context.TODO(), eventType, resource); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the pending status is published when the client is closed, e.g. on a rolling restart
+	if err := agent.Close(context.TODO()); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(client.GetSentEvents()) != 1 {
+		t.Errorf("expected the pending status is published, but got %v", client.GetSentEvents())
+	}
+
+	if err := agent.Publish(context.TODO(), eventType, resource); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected %v, but got %v", ErrClientClosed, err)
+	}
+}
+
+func TestAgentSwitchTransport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	oldClient := fake.NewCloudEventsFakeClient()
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx, fake.NewAgentOptions(oldClient, "cluster1", testAgentName),
+		newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newClient := fake.NewCloudEventsFakeClient()
+	if err := agent.SwitchTransport(ctx, fake.NewAgentOptions(newClient, "cluster2", testAgentName)); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+
+	if err := agent.SwitchTransport(ctx, fake.NewAgentOptions(newClient, "cluster1", testAgentName)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the resync is forced after the client is switched
+	select {
+	case <-agent.ReconnectedChan():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the reconnected signal after the transport is switched")
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}
+	res := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"}
+	if err := agent.Publish(ctx, eventType, res); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(oldClient.GetSentEvents()) != 0 {
+		t.Errorf("expected no event is sent with the old transport, but got %v", oldClient.GetSentEvents())
+	}
+	if len(newClient.GetSentEvents()) != 1 {
+		t.Errorf("expected 1 event is sent with the new transport, but got %v", newClient.GetSentEvents())
+	}
+}
+
+func TestAgentConnectionEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan string, 10)
+	agentOptions := fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster1", testAgentName)
+	agentOptions.ConnectionEventHandlers = options.ConnectionEventHandlers{
+		OnConnect: func(event options.ConnectionEvent) {
+			events <- fmt.Sprintf("connect reconnected=%v", event.Reconnected)
+		},
+		OnDisconnect: func(event options.ConnectionEvent) {
+			events <- fmt.Sprintf("disconnect err=%v", event.Err)
+		},
+		OnResubscribe: func(event options.ConnectionEvent) {
+			events <- fmt.Sprintf("resubscribe resumed=%v", event.Resumed)
+		},
+	}
+
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx, agentOptions,
+		newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := agent.SwitchTransport(ctx,
+		fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster1", testAgentName)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"connect reconnected=false",
+		"disconnect err=<nil>",
+		"connect reconnected=true",
+		"resubscribe resumed=false",
+	} {
+		select {
+		case event := <-events:
+			if event != expected {
+				t.Errorf("expected %q, but got %q", expected, event)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout to wait for the connection event %q", expected)
+		}
+	}
+}
+
+// reconnectingOptions connects once, then the connection is lost and all the reconnects fail.
+type reconnectingOptions struct {
+	*fake.CloudEventsFakeOptions
+	errorChan chan error
+	lock      sync.Mutex
+	connects  int
+}
+
+func (o *reconnectingOptions) Client(ctx context.Context) (cloudevents.Client, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.connects++
+	if o.connects > 1 {
+		return nil, fmt.Errorf("the broker is down")
+	}
+	return o.CloudEventsFakeOptions.Client(ctx)
+}
+
+func (o *reconnectingOptions) ErrorChan() <-chan error {
+	return o.errorChan
+}
+
+func (o *reconnectingOptions) getConnects() int {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.connects
+}
+
+func TestAgentCloseWhileReconnecting(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	agentOptions := fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster1", testAgentName)
+	transport := &reconnectingOptions{
+		CloudEventsFakeOptions: agentOptions.CloudEventsOptions.(*fake.CloudEventsFakeOptions),
+		errorChan:              make(chan error),
+	}
+	agentOptions.CloudEventsOptions = transport
+	agentOptions.Clock = fakeClock
+
+	agent, err := NewCloudEventAgentClient[*mockResource](context.Background(), agentOptions,
+		newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the connection is lost and the reconnects keep failing
+	transport.errorChan <- fmt.Errorf("connection lost")
+	if err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			fakeClock.Step(time.Minute)
+			return transport.getConnects() >= 3, nil
+		}); err != nil {
+		t.Fatalf("expected the client keeps reconnecting, %v", err)
+	}
+
+	if err := agent.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the client stops reconnecting once it is closed
+	time.Sleep(100 * time.Millisecond)
+	connects := transport.getConnects()
+	for i := 0; i < 5; i++ {
+		fakeClock.Step(time.Minute)
+		time.Sleep(20 * time.Millisecond)
+	}
+	if transport.getConnects() != connects {
+		t.Errorf("expected no reconnect after the client is closed, but got %d reconnects",
+			transport.getConnects()-connects)
+	}
+}
+
 func TestStatusResyncResponse(t *testing.T) {
 	cases := []struct {
 		name         string
@@ -261,6 +534,67 @@ func TestStatusResyncResponse(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:        "resync status with the hashes of another algorithm",
+			clusterName: "cluster1",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceStatus,
+					Action:              types.ResyncRequestAction,
+				}
+
+				statusHashes := &payload.ResourceStatusHashList{
+					Hashes: []payload.ResourceStatusHash{
+						{ResourceID: "test1", StatusHash: "test1"},
+						{ResourceID: "test2", StatusHash: "test2"},
+					},
+				}
+
+				evt := cloudevents.NewEvent()
+				evt.SetType(eventType.String())
+				evt.SetExtension(types.ExtensionStatusHashAlgorithm, StatusHashAlgorithmSHA512)
+				if err := evt.SetData(cloudevents.ApplicationJSON, statusHashes); err != nil {
+					t.Fatal(err)
+				}
+				return evt
+			}(),
+			resources: []*mockResource{
+				{UID: kubetypes.UID("test1"), ResourceVersion: "2", Status: "test1"},
+				{UID: kubetypes.UID("test2"), ResourceVersion: "3", Status: "test2"},
+			},
+			validate: func(pubEvents []cloudevents.Event) {
+				if len(pubEvents) != 2 {
+					t.Errorf("expected all publish events, but got %v", pubEvents)
+				}
+			},
+		},
+		{
+			name:        "resync status - invalid status",
+			clusterName: "cluster1",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceStatus,
+					Action:              types.ResyncRequestAction,
+				}
+
+				evt := cloudevents.NewEvent()
+				evt.SetType(eventType.String())
+				if err := evt.SetData(cloudevents.ApplicationJSON, &payload.ResourceStatusHashList{}); err != nil {
+					t.Fatal(err)
+				}
+				return evt
+			}(),
+			resources: []*mockResource{
+				{UID: kubetypes.UID("test1"), Status: "test1"},
+			},
+			validate: func(pubEvents []cloudevents.Event) {
+				if len(pubEvents) != 0 {
+					t.Errorf("expected the status without a resource version is not published, but got %v", pubEvents)
+				}
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -282,11 +616,12 @@ func TestStatusResyncResponse(t *testing.T) {
 
 func TestReceiveResourceSpec(t *testing.T) {
 	cases := []struct {
-		name         string
-		clusterName  string
-		requestEvent cloudevents.Event
-		resources    []*mockResource
-		validate     func(event types.ResourceAction, resource *mockResource)
+		name           string
+		clusterName    string
+		requestEvent   cloudevents.Event
+		resources      []*mockResource
+		resyncedAction bool
+		validate       func(event types.ResourceAction, resource *mockResource)
 	}{
 		{
 			name:        "unsupported sub resource",
@@ -346,6 +681,26 @@ func TestReceiveResourceSpec(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:        "expired spec event",
+			clusterName: "cluster1",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              "test_create_request",
+				}
+
+				evt, _ := newMockResourceCodec().Encode(testAgentName, eventType, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1"})
+				evt.SetExtension(types.ExtensionExpirationTime, time.Now().Add(-time.Minute))
+				return *evt
+			}(),
+			validate: func(event types.ResourceAction, resource *mockResource) {
+				if len(event) != 0 {
+					t.Errorf("should not be invoked")
+				}
+			},
+		},
 		{
 			name:        "update a resource",
 			clusterName: "cluster1",
@@ -401,6 +756,73 @@ func TestReceiveResourceSpec(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:        "delete a resource that does not exist",
+			clusterName: "cluster1",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              "test_delete_request",
+				}
+				now := metav1.Now()
+				evt, _ := newMockResourceCodec().Encode(testAgentName, eventType, &mockResource{UID: kubetypes.UID("test3"), ResourceVersion: "2", DeletionTimestamp: &now})
+				return *evt
+			}(),
+			resources: []*mockResource{
+				{UID: kubetypes.UID("test1"), ResourceVersion: "1"},
+			},
+			validate: func(event types.ResourceAction, resource *mockResource) {
+				if len(event) != 0 {
+					t.Errorf("expected no change, but get %s", event)
+				}
+			},
+		},
+		{
+			name:        "resync a resource",
+			clusterName: "cluster1",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              types.ResyncResponseAction,
+				}
+
+				evt, _ := newMockResourceCodec().Encode(testAgentName, eventType, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2"})
+				return *evt
+			}(),
+			resources: []*mockResource{
+				{UID: kubetypes.UID("test1"), ResourceVersion: "2"},
+			},
+			resyncedAction: true,
+			validate: func(event types.ResourceAction, resource *mockResource) {
+				if event != types.SpecResynced {
+					t.Errorf("expected spec resynced, but get %s", event)
+				}
+			},
+		},
+		{
+			name:        "resync a resource without the resynced action",
+			clusterName: "cluster1",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              types.ResyncResponseAction,
+				}
+
+				evt, _ := newMockResourceCodec().Encode(testAgentName, eventType, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2"})
+				return *evt
+			}(),
+			resources: []*mockResource{
+				{UID: kubetypes.UID("test1"), ResourceVersion: "2"},
+			},
+			validate: func(event types.ResourceAction, resource *mockResource) {
+				if len(event) != 0 {
+					t.Errorf("expected no change, but get %s", event)
+				}
+			},
+		},
 		{
 			name:        "no change resource",
 			clusterName: "cluster1",
@@ -430,6 +852,7 @@ func TestReceiveResourceSpec(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			client := fake.NewCloudEventsFakeClient(c.requestEvent)
 			agentOptions := fake.NewAgentOptions(client, c.clusterName, testAgentName)
+			agentOptions.SpecResyncedAction = c.resyncedAction
 			lister := newMockResourceLister(c.resources...)
 			agent, err := NewCloudEventAgentClient[*mockResource](context.TODO(), agentOptions, lister, statusHash, newMockResourceCodec())
 			if err != nil {
@@ -449,6 +872,220 @@ func TestReceiveResourceSpec(t *testing.T) {
 	}
 }
 
+func TestAgentSpecCache(t *testing.T) {
+	agentOptions := fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster1", testAgentName)
+	lister := newMockResourceLister()
+	agent, err := NewCloudEventAgentClient[*mockResource](
+		context.TODO(), agentOptions, lister, statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	receive := func(res *mockResource) {
+		evt, err := newMockResourceCodec().Encode(testSourceName, eventType, res)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		agent.receive(context.TODO(), *evt)
+	}
+
+	if _, ok := agent.GetByResourceID("test1"); ok {
+		t.Errorf("expected the spec is not found")
+	}
+
+	receive(&mockResource{UID: kubetypes.UID("test2"), ResourceVersion: "1", Status: "test2"})
+	receive(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "test1"})
+	receive(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2", Status: "test1-updated"})
+
+	// the out of order spec is not cached
+	receive(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "test1"})
+
+	res, ok := agent.GetByResourceID("test1")
+	if !ok {
+		t.Fatalf("expected the spec is found")
+	}
+	if res.Status != "test1-updated" {
+		t.Errorf("expected test1-updated, but got %s", res.Status)
+	}
+
+	expectedVersions := []payload.ResourceVersion{
+		{ResourceID: "test1", ResourceVersion: 2},
+		{ResourceID: "test2", ResourceVersion: 1},
+	}
+	if versions := agent.ResourceVersions(); !reflect.DeepEqual(versions, expectedVersions) {
+		t.Errorf("expected %v, but got %v", expectedVersions, versions)
+	}
+
+	// the deleted resource is removed from the cache
+	lister.resources = []*mockResource{{UID: kubetypes.UID("test2"), ResourceVersion: "1"}}
+	now := metav1.Now()
+	receive(&mockResource{UID: kubetypes.UID("test2"), ResourceVersion: "2", DeletionTimestamp: &now})
+
+	if _, ok := agent.GetByResourceID("test2"); ok {
+		t.Errorf("expected the deleted spec is not found")
+	}
+	if versions := agent.ResourceVersions(); len(versions) != 1 {
+		t.Errorf("expected 1 resource version, but got %v", versions)
+	}
+}
+
+func TestAgentFailedSpecNotProcessed(t *testing.T) {
+	agentOptions := fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster1", testAgentName)
+	agent, err := NewCloudEventAgentClient[*mockResource](
+		context.TODO(), agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	receive := func(res *mockResource, handlerErr error) {
+		evt, err := newMockResourceCodec().Encode(testSourceName, eventType, res)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		agent.receive(context.TODO(), *evt, func(action types.ResourceAction, resource *mockResource) error {
+			return handlerErr
+		})
+	}
+
+	// the failed spec is not processed
+	receive(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2"}, fmt.Errorf("failed"))
+	if outOfOrder, lastVersion := agent.versionTracker.isOutOfOrder("test1", "1"); outOfOrder {
+		t.Errorf("expected the failed spec is not processed, but got the last version %s", lastVersion)
+	}
+
+	receive(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2"}, nil)
+	if outOfOrder, _ := agent.versionTracker.isOutOfOrder("test1", "1"); !outOfOrder {
+		t.Errorf("expected the spec is processed")
+	}
+}
+
+func TestAgentStaleSpecAfterDelete(t *testing.T) {
+	lister := newMockResourceLister(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2"})
+	agent, err := NewCloudEventAgentClient[*mockResource](context.TODO(),
+		fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster1", testAgentName),
+		lister, statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+	actions := []types.ResourceAction{}
+	receive := func(res *mockResource) {
+		evt, err := newMockResourceCodec().Encode(testSourceName, eventType, res)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		agent.receive(context.TODO(), *evt, func(action types.ResourceAction, resource *mockResource) error {
+			actions = append(actions, action)
+			return nil
+		})
+	}
+
+	// the resource is deleted, then a delayed update of the resource is received
+	receive(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "3",
+		DeletionTimestamp: &metav1.Time{Time: time.Now()}})
+	lister.resources = nil
+	receive(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2"})
+	if !reflect.DeepEqual(actions, []types.ResourceAction{types.Deleted}) {
+		t.Errorf("expected the delayed update is dropped after the resource is deleted, but got %v", actions)
+	}
+
+	// the resource is recreated with a newer resource version
+	receive(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "4"})
+	if !reflect.DeepEqual(actions, []types.ResourceAction{types.Deleted, types.Added}) {
+		t.Errorf("expected the recreated resource is added, but got %v", actions)
+	}
+}
+
+func TestAgentHandover(t *testing.T) {
+	var discarded []string
+	agentOptions := fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster1", testAgentName)
+	agentOptions.DiscardedEventHandler = func(evt cloudevents.Event, reason string) {
+		discarded = append(discarded, reason)
+	}
+	lister := newMockResourceLister(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "5", Namespace: "cluster1"})
+	agent, err := NewCloudEventAgentClient[*mockResource](
+		context.TODO(), agentOptions, lister, statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	startSource := func(sourceID string) (*CloudEventSourceClient[*mockResource], *fake.CloudEventsFakeClient) {
+		fakeClient := fake.NewCloudEventsFakeClient()
+		source, err := NewCloudEventSourceClient[*mockResource](context.TODO(),
+			fake.NewSourceOptions(fakeClient, sourceID), lister, statusHash, newMockResourceCodec())
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		return source, fakeClient
+	}
+	oldSource, oldClient := startSource("source1")
+	newSource, newClient := startSource("source2")
+
+	var actions []types.ResourceAction
+	handler := func(action types.ResourceAction, obj *mockResource) error {
+		actions = append(actions, action)
+		return nil
+	}
+	lastSent := func(fakeClient *fake.CloudEventsFakeClient) cloudevents.Event {
+		sent := fakeClient.GetSentEvents()
+		return sent[len(sent)-1]
+	}
+
+	// the claim is rejected before the resource is handed over
+	claimed := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"}
+	if err := newSource.Claim(context.TODO(), mockEventDataType, claimed, "source1"); err != nil {
+		t.Fatal(err)
+	}
+	agent.receive(context.TODO(), lastSent(newClient), handler)
+	if len(actions) != 0 || len(discarded) != 1 {
+		t.Fatalf("expected the claim is discarded, but got actions %v", actions)
+	}
+
+	if err := oldSource.Handover(context.TODO(), mockEventDataType, lister.resources[0], "source2"); err != nil {
+		t.Fatal(err)
+	}
+	agent.receive(context.TODO(), lastSent(oldClient), handler)
+	if len(actions) != 0 {
+		t.Errorf("expected the handover is not handled, but got %v", actions)
+	}
+
+	// the resource version of the claiming source is lower than the one of the previous source
+	agent.receive(context.TODO(), lastSent(newClient), handler)
+	if !reflect.DeepEqual(actions, []types.ResourceAction{types.Claimed}) {
+		t.Errorf("expected the resource is claimed, but got %v", actions)
+	}
+
+	// the spec events of the previous source are discarded after the resource is claimed
+	now := metav1.Now()
+	deleted := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "6", Namespace: "cluster1", DeletionTimestamp: &now}
+	if err := oldSource.Publish(context.TODO(), types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_delete_request",
+	}, deleted); err != nil {
+		t.Fatal(err)
+	}
+	agent.receive(context.TODO(), lastSent(oldClient), handler)
+	if len(actions) != 1 || len(discarded) != 2 {
+		t.Errorf("expected the spec of the previous source is discarded, but got actions %v", actions)
+	}
+}
+
 type mockResource struct {
 	UID               kubetypes.UID `json:"uid"`
 	ResourceVersion   string        `json:"resourceVersion"`
@@ -480,8 +1117,23 @@ func newMockResourceLister(resources ...*mockResource) *mockResourceLister {
 	}
 }
 
+// List returns the resources, a resource without a namespace is listed in the namespace of the requested cluster, like
+// the resources that a real lister lists from the cluster namespace, so the published events are labeled with it.
 func (l *mockResourceLister) List(opt types.ListOptions) ([]*mockResource, error) {
-	return l.resources, nil
+	if len(opt.ClusterName) == 0 || opt.ClusterName == types.ClusterAll {
+		return l.resources, nil
+	}
+
+	resources := make([]*mockResource, 0, len(l.resources))
+	for _, resource := range l.resources {
+		if len(resource.Namespace) == 0 {
+			listed := *resource
+			listed.Namespace = opt.ClusterName
+			resource = &listed
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
 }
 
 func statusHash(r *mockResource) (string, error) {
@@ -507,6 +1159,9 @@ func (c *mockResourceCodec) Encode(source string, eventType types.CloudEventsTyp
 	evt.SetExtension("resourceid", string(obj.UID))
 	evt.SetExtension("resourceversion", obj.ResourceVersion)
 	evt.SetExtension("clustername", obj.Namespace)
+	if eventType.SubResource == types.SubResourceStatus {
+		evt.SetExtension("originalsource", testSourceName)
+	}
 	if obj.GetDeletionTimestamp() != nil {
 		evt.SetExtension("deletiontimestamp", obj.DeletionTimestamp.Time)
 	}
@@ -544,3 +1199,82 @@ func (c *mockResourceCodec) Decode(evt *cloudevents.Event) (*mockResource, error
 
 	return res, nil
 }
+
+func TestAgentRegister(t *testing.T) {
+	cases := []struct {
+		name        string
+		bootstrap   *payload.AgentBootstrap
+		expectedErr error
+	}{
+		{
+			name:      "accepted",
+			bootstrap: &payload.AgentBootstrap{Accepted: true, Config: []byte(`{"interval":"10s"}`)},
+		},
+		{
+			name:        "rejected",
+			bootstrap:   &payload.AgentBootstrap{Message: "the cluster is not allowed"},
+			expectedErr: ErrRegistrationRejected,
+		},
+		{
+			name:        "no response",
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := fake.NewCloudEventsFakeClient()
+			agentOptions := fake.NewAgentOptions(client, "cluster1", testAgentName)
+			agent, err := NewCloudEventAgentClient[*mockResource](
+				context.TODO(), agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// respond the registration request once it is sent
+			go func() {
+				if c.bootstrap == nil {
+					return
+				}
+
+				for len(client.GetSentEvents()) == 0 {
+					time.Sleep(10 * time.Millisecond)
+				}
+
+				request := client.GetSentEvents()[0]
+				bootstrap := *c.bootstrap
+				bootstrap.RequestID = request.ID()
+
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: types.RegistrationDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              types.RegisterResponseAction,
+				}
+				evt := types.NewEventBuilder(testSourceName, eventType).WithClusterName("cluster1").NewEvent()
+				if err := evt.SetData(cloudevents.ApplicationJSON, bootstrap); err != nil {
+					t.Error(err)
+				}
+				agent.receive(context.TODO(), evt)
+			}()
+
+			ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+			defer cancel()
+
+			bootstrap, err := agent.Register(ctx, payload.AgentRegistration{AgentVersion: "v1.0.0"})
+			if !errors.Is(err, c.expectedErr) {
+				t.Errorf("expected %v, but got %v", c.expectedErr, err)
+			}
+			if c.expectedErr == nil && string(bootstrap.Config) != `{"interval":"10s"}` {
+				t.Errorf("unexpected bootstrap %v", bootstrap)
+			}
+
+			registration, err := payload.DecodeAgentRegistration(client.GetSentEvents()[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if registration.ClusterName != "cluster1" || registration.AgentID != testAgentName {
+				t.Errorf("unexpected registration %v", registration)
+			}
+		})
+	}
+}
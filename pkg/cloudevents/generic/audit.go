@@ -0,0 +1,71 @@
+package generic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// audit records a sent or received resource event to the audit sink of the client, the failure of the recording is
+// logged and does not fail the event.
+func (c *baseClient) audit(ctx context.Context, direction options.AuditDirection, evt cloudevents.Event) {
+	if c.auditSink == nil {
+		return
+	}
+
+	record, ok := newAuditRecord(direction, evt)
+	if !ok {
+		return
+	}
+
+	if err := c.auditSink.Record(ctx, record); err != nil {
+		klog.Errorf("failed to record the audit of event %s, %v", evt.ID(), err)
+	}
+}
+
+// newAuditRecord returns the audit record of a resource event, it returns false if the event is not a resource event,
+// e.g. the resync request.
+func newAuditRecord(direction options.AuditDirection, evt cloudevents.Event) (options.AuditRecord, bool) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return options.AuditRecord{}, false
+	}
+
+	extensions := evt.Extensions()
+	resourceID, ok := extensions[types.ExtensionResourceID]
+	if !ok {
+		return options.AuditRecord{}, false
+	}
+
+	hash := sha256.Sum256(evt.Data())
+	record := options.AuditRecord{
+		Time:        time.Now(),
+		Direction:   direction,
+		EventID:     evt.ID(),
+		Source:      evt.Source(),
+		ResourceID:  fmt.Sprintf("%v", resourceID),
+		DataType:    eventType.CloudEventsDataType.String(),
+		SubResource: eventType.SubResource,
+		Action:      eventType.Action,
+		Hash:        hex.EncodeToString(hash[:]),
+	}
+
+	if resourceVersion, ok := extensions[types.ExtensionResourceVersion]; ok {
+		record.ResourceVersion = fmt.Sprintf("%v", resourceVersion)
+	}
+
+	if clusterName, ok := extensions[types.ExtensionClusterName]; ok {
+		record.ClusterName = fmt.Sprintf("%v", clusterName)
+	}
+
+	return record, true
+}
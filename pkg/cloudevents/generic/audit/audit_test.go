@@ -0,0 +1,138 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func newRecord(resourceID string) options.AuditRecord {
+	return options.AuditRecord{
+		Time:        time.Now(),
+		Direction:   options.AuditSent,
+		EventID:     "event-" + resourceID,
+		Source:      "source1",
+		ClusterName: "cluster1",
+		ResourceID:  resourceID,
+		SubResource: types.SubResourceSpec,
+		Action:      "create_request",
+		Hash:        "hash",
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, resourceID := range []string{"1", "2"} {
+		if err := sink.Record(context.TODO(), newRecord(resourceID)); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, but got %v", lines)
+	}
+
+	record := options.AuditRecord{}
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if record.ResourceID != "2" {
+		t.Errorf("expected 2, but got %v", record.ResourceID)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		expectErr  bool
+	}{
+		{
+			name:       "posted",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "rejected",
+			statusCode: http.StatusInternalServerError,
+			expectErr:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			received := []options.AuditRecord{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				record := options.AuditRecord{}
+				if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				received = append(received, record)
+				w.WriteHeader(c.statusCode)
+			}))
+			defer server.Close()
+
+			err := NewWebhookSink(server.URL, nil).Record(context.TODO(), newRecord("1"))
+			if c.expectErr && err == nil {
+				t.Errorf("expected error, but got nil")
+			}
+			if !c.expectErr && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+
+			if len(received) != 1 || received[0].ResourceID != "1" {
+				t.Errorf("expected the record is posted, but got %v", received)
+			}
+		})
+	}
+}
+
+func TestCloudEventsSink(t *testing.T) {
+	fakeClient := fake.NewCloudEventsFakeClient()
+	if err := NewCloudEventsSink("source1", fakeClient).Record(context.TODO(), newRecord("1")); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	evts := fakeClient.GetSentEvents()
+	if len(evts) != 1 {
+		t.Fatalf("expected 1 event, but got %v", evts)
+	}
+
+	if evts[0].Type() != AuditEventType || evts[0].DataContentType() != cloudevents.ApplicationJSON {
+		t.Errorf("unexpected audit event %v", evts[0])
+	}
+
+	record := options.AuditRecord{}
+	if err := evts[0].DataAs(&record); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if record.ResourceID != "1" {
+		t.Errorf("expected 1, but got %v", record.ResourceID)
+	}
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// AuditEventType is the cloud event type of the audit record events.
+const AuditEventType = "io.open-cluster-management.audit.record"
+
+// CloudEventsSink sends the audit records as cloud events, the data of the event is the JSON of the record.
+type CloudEventsSink struct {
+	source string
+	client cloudevents.Client
+}
+
+var _ options.AuditSink = &CloudEventsSink{}
+
+// NewCloudEventsSink returns a CloudEventsSink that sends the audit records with the given client, the client should
+// send the events to the audit topic, e.g. an MQTT client that is built with the audit topic as its publish topic.
+func NewCloudEventsSink(source string, client cloudevents.Client) *CloudEventsSink {
+	return &CloudEventsSink{source: source, client: client}
+}
+
+func (s *CloudEventsSink) Record(ctx context.Context, record options.AuditRecord) error {
+	evt := cloudevents.NewEvent()
+	evt.SetID(uuid.New().String())
+	evt.SetSource(s.source)
+	evt.SetType(AuditEventType)
+	evt.SetTime(record.Time)
+	if err := evt.SetData(cloudevents.ApplicationJSON, record); err != nil {
+		return fmt.Errorf("failed to encode the audit record, %v", err)
+	}
+
+	if result := s.client.Send(ctx, evt); cloudevents.IsUndelivered(result) {
+		return fmt.Errorf("failed to send the audit event %s, %v", evt.ID(), result)
+	}
+
+	return nil
+}
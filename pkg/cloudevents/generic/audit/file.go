@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// FileSink appends the audit records to a file, each record is written as one JSON line.
+type FileSink struct {
+	sync.Mutex
+	file *os.File
+}
+
+var _ options.AuditSink = &FileSink{}
+
+// NewFileSink opens the file of the given path to append the audit records, the file is created if it does not exist.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the audit file %s, %v", path, err)
+	}
+
+	return &FileSink{file: file}, nil
+}
+
+func (s *FileSink) Record(ctx context.Context, record options.AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the audit file.
+func (s *FileSink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.file.Close()
+}
@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+// WebhookSink posts the audit records to a webhook, each record is posted as a JSON body.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+var _ options.AuditSink = &WebhookSink{}
+
+// NewWebhookSink returns a WebhookSink that posts the audit records to the given URL with the client, if the client is
+// nil, a client with 10s timeout will be used.
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	if client == nil {
+		client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+
+	return &WebhookSink{url: url, client: client}
+}
+
+func (s *WebhookSink) Record(ctx context.Context, record options.AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post the audit record to %s, %v", s.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to post the audit record to %s, status code %d", s.url, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -2,19 +2,25 @@ package generic
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/features"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
 const (
@@ -26,19 +32,127 @@ type receiveFn func(ctx context.Context, evt cloudevents.Event)
 
 type baseClient struct {
 	sync.RWMutex
-	cloudEventsOptions     options.CloudEventsOptions
-	cloudEventsClient      cloudevents.Client
-	cloudEventsRateLimiter flowcontrol.RateLimiter
-	receiverChan           chan int
-	reconnectedChan        chan struct{}
+	cloudEventsOptions      options.CloudEventsOptions
+	cloudEventsClient       cloudevents.Client
+	cloudEventsRateLimiter  flowcontrol.RateLimiter
+	receiverChan            chan int
+	receiverDone            chan struct{}
+	reconnectedChan         chan struct{}
+	switchChan              chan options.CloudEventsOptions
+	discardedEventHandler   options.DiscardedEventHandler
+	dedupeCache             *dedupeCache
+	resyncReporter          options.ResyncReporter
+	handlerInvoker          *handlerInvoker
+	dispatcher              *eventDispatcher
+	receiveBuffer           *receiveBuffer
+	claimCheck              *claimCheck
+	auditSink               options.AuditSink
+	maxEventSize            int
+	clock                   clock.WithTickerAndDelayedExecution
+	jitter                  options.Jitter
+	payloadSizes            payloadSizes
+	payloadSizeObserver     options.PayloadSizeObserver
+	extensionHook           options.EventExtensionHook
+	featureNegotiator       *features.Negotiator
+	tenantID                string
+	eventSchemas            *schema.Registry
+	resyncCompleteHandler   options.ResyncCompleteHandler
+	connectionEventHandlers options.ConnectionEventHandlers
+	calls                   *callTracker
+	callHandler             options.CallHandler
+	callTimeout             time.Duration
+	resyncLock              sync.Mutex
+	resyncTargets           map[string]*options.ResyncTargetStatus
+	lastResyncTime          time.Time
+	draining                bool
+	// closing is true once the client starts draining, the new publishes are rejected while the pending publishes
+	// are flushed by the flushers, e.g. the coalesced updates, before the client is draining
+	closing       bool
+	flushers      []func(ctx context.Context) error
+	inflight      sync.WaitGroup
+	inflightCount atomic.Int32
+	status        connectionStatus
+	// ctx is the context that the client is connected with, the client is stopped once it is done
+	ctx      context.Context
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// newBaseClient builds the base client of a source/agent client with the options that are shared by them, the client
+// is not connected yet.
+func newBaseClient(
+	cloudEventsOptions options.CloudEventsOptions,
+	eventRateLimit options.EventRateLimit,
+	clientOptions options.CloudEventsClientOptions,
+) (*baseClient, error) {
+	baseClient := &baseClient{
+		cloudEventsOptions:      cloudEventsOptions,
+		cloudEventsRateLimiter:  NewRateLimiter(eventRateLimit),
+		reconnectedChan:         make(chan struct{}),
+		stopChan:                make(chan struct{}),
+		discardedEventHandler:   clientOptions.DiscardedEventHandler,
+		resyncReporter:          clientOptions.ResyncReporter,
+		resyncCompleteHandler:   clientOptions.OnResyncComplete,
+		connectionEventHandlers: clientOptions.ConnectionEventHandlers,
+		auditSink:               clientOptions.AuditSink,
+		maxEventSize:            clientOptions.MaxEventSize,
+		payloadSizeObserver:     clientOptions.PayloadSizeObserver,
+		clock:                   clockOrDefault(clientOptions.Clock),
+		jitter:                  clientOptions.Jitter,
+		extensionHook:           clientOptions.EventExtensionHook,
+		featureNegotiator:       features.NewNegotiator(clientOptions.Capabilities),
+		tenantID:                clientOptions.TenantID,
+		eventSchemas:            clientOptions.EventSchemas,
+		calls:                   newCallTracker(),
+		callHandler:             clientOptions.CallHandler,
+		callTimeout:             clientOptions.CallTimeout,
+	}
+
+	if baseClient.callTimeout <= 0 {
+		baseClient.callTimeout = options.DefaultCallTimeout
+	}
+
+	baseClient.handlerInvoker = newHandlerInvoker(clientOptions.HandlerErrorPolicy, baseClient.clock, baseClient.stopChan)
+
+	if clientOptions.ClaimCheck != nil {
+		claimCheck, err := newClaimCheck(clientOptions.ClaimCheck)
+		if err != nil {
+			return nil, err
+		}
+		baseClient.claimCheck = claimCheck
+	}
+
+	if clientOptions.ReceiveBufferSize > 0 {
+		baseClient.receiveBuffer = newReceiveBuffer(
+			clientOptions.ReceiveBufferSize, clientOptions.ReceiveOverflowPolicy, baseClient.stopChan)
+	}
+
+	if clientOptions.ReceiveWorkers > 0 {
+		baseClient.dispatcher = newEventDispatcher(clientOptions.ReceiveWorkers, clientOptions.ReceivePriorityLanes,
+			baseClient.received, baseClient.stopChan)
+	}
+
+	if clientOptions.DedupeCacheSize > 0 {
+		baseClient.dedupeCache = newDedupeCache(clientOptions.DedupeCacheSize)
+	}
+
+	return baseClient, nil
 }
 
 func (c *baseClient) connect(ctx context.Context) error {
+	c.ctx = ctx
+
+	// the connection of a transport is closed with its context when the client is switched to another transport
+	transportCtx, cancelTransport := context.WithCancel(ctx)
+
 	var err error
-	c.cloudEventsClient, err = c.cloudEventsOptions.Client(ctx)
+	c.cloudEventsClient, err = c.cloudEventsOptions.Client(transportCtx)
 	if err != nil {
+		cancelTransport()
 		return err
 	}
+	c.status.connect()
+	c.onConnect(false)
 
 	// start a go routine to handle cloudevents client connection errors
 	go func() {
@@ -50,20 +164,25 @@ func (c *baseClient) connect(ctx context.Context) error {
 			Cap:      1 * time.Minute,
 			Steps:    12, // now a required argument
 			Factor:   5.0,
-			Jitter:   1.0,
-		}.DelayWithReset(&clock.RealClock{}, 10*time.Minute)
+			Jitter:   reconnectJitter(c.jitter),
+		}.DelayWithReset(c.clock, 10*time.Minute)
 		cloudEventsClient := c.cloudEventsClient
+		switched := false
 
 		for {
 			if cloudEventsClient == nil {
 				klog.V(4).Infof("reconnecting the cloudevents client")
-				cloudEventsClient, err = c.cloudEventsOptions.Client(ctx)
+				cloudEventsClient, err = c.transport().Client(transportCtx)
 				// TODO enhance the cloudevents SKD to avoid wrapping the error type to distinguish the net connection
 				// errors
 				if err != nil {
 					// failed to reconnect, try agin
 					runtime.HandleError(fmt.Errorf("the cloudevents client reconnect failed, %v", err))
-					<-wait.RealTimer(delayFn()).C()
+					c.status.connectFailed(err)
+					if !c.waitReconnect(ctx, delayFn()) {
+						cancelTransport()
+						return
+					}
 					continue
 				}
 
@@ -71,20 +190,58 @@ func (c *baseClient) connect(ctx context.Context) error {
 				// receiver restart signal
 				klog.V(4).Infof("the cloudevents client is reconnected")
 				c.resetClient(cloudEventsClient)
+				c.status.connect()
+				c.onConnect(true)
 				c.sendReceiverSignal(restartReceiverSignal)
-				c.sendReconnectedSignal()
+
+				if resumable, ok := c.transport().(options.ResumableOptions); ok && resumable.Resumable() && !switched {
+					// the subscriptions are resumed from the last received events, the resync is not required
+					klog.V(4).Infof("the cloudevents subscriptions are resumed")
+					c.onResubscribe(true)
+				} else {
+					c.onResubscribe(false)
+					// the resync is always required after the client is switched to another transport
+					switched = false
+					c.sendReconnectedSignalWithJitter()
+				}
 			}
 
 			select {
 			case <-ctx.Done():
+				cancelTransport()
+				c.onDisconnect(nil)
+				return
+			case <-c.stopChan:
+				cancelTransport()
+				c.onDisconnect(nil)
 				return
-			case err, ok := <-c.cloudEventsOptions.ErrorChan():
+			case cloudEventsOptions := <-c.switchChan:
+				klog.Infof("switch the cloudevents client to a new transport")
+
+				// stop the receiver and close the connection of the current transport, then reconnect the client with
+				// the new transport immediately
+				c.sendReceiverSignal(stopReceiverSignal)
+				cancelTransport()
+				c.status.disconnect(nil)
+				c.onDisconnect(nil)
+				transportCtx, cancelTransport = context.WithCancel(ctx)
+
+				c.Lock()
+				c.cloudEventsOptions = cloudEventsOptions
+				c.cloudEventsClient = nil
+				c.Unlock()
+
+				cloudEventsClient = nil
+				switched = true
+			case err, ok := <-c.transport().ErrorChan():
 				if !ok {
 					// error channel is closed, do nothing
 					return
 				}
 
 				runtime.HandleError(fmt.Errorf("the cloudevents client is disconnected, %v", err))
+				c.status.disconnect(err)
+				c.onDisconnect(err)
 
 				// the cloudevents client network connection is closed, send the receiver stop signal, set the current
 				// client to nil and retry
@@ -93,7 +250,10 @@ func (c *baseClient) connect(ctx context.Context) error {
 				cloudEventsClient = nil
 				c.resetClient(cloudEventsClient)
 
-				<-wait.RealTimer(delayFn()).C()
+				if !c.waitReconnect(ctx, delayFn()) {
+					cancelTransport()
+					return
+				}
 			}
 		}
 	}()
@@ -101,38 +261,119 @@ func (c *baseClient) connect(ctx context.Context) error {
 	return nil
 }
 
+// waitReconnect waits for the delay of the next reconnect, it returns false if the client is closed or the given
+// context is done before the delay, then the client should stop reconnecting.
+func (c *baseClient) waitReconnect(ctx context.Context, delay time.Duration) bool {
+	timer := c.clock.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return true
+	case <-ctx.Done():
+		return false
+	case <-c.stopChan:
+		return false
+	}
+}
+
 func (c *baseClient) publish(ctx context.Context, evt cloudevents.Event) error {
-	now := time.Now()
+	if !c.acquire() {
+		return fmt.Errorf("%w: failed to send event %s, the client is draining", ErrClientClosed, evt.ID())
+	}
+	defer c.release()
 
-	if err := c.cloudEventsRateLimiter.Wait(ctx); err != nil {
-		return fmt.Errorf("client rate limiter Wait returned an error: %w", err)
+	if err := contextError(ctx); err != nil {
+		return fmt.Errorf("failed to send event %s, %w", evt.ID(), err)
 	}
 
-	latency := time.Since(now)
-	if latency > longThrottleLatency {
-		klog.Warningf(fmt.Sprintf("Waited for %v due to client-side throttling, not priority and fairness, request: %s",
-			latency, evt))
+	// the events in the dry-run mode are not sent, so they do not wait for the rate limiter
+	dryRun := isDryRun(ctx)
+	if !dryRun {
+		if err := c.waitRateLimiter(ctx, evt); err != nil {
+			return err
+		}
 	}
 
-	sendingCtx, err := c.cloudEventsOptions.WithContext(ctx, evt.Context)
+	evt, err := c.enrich(ctx, evt)
 	if err != nil {
 		return err
 	}
 
-	klog.V(4).Infof("Sent event: %v\n%s", ctx, evt)
+	if evt, err = c.labelTenant(evt); err != nil {
+		return err
+	}
+
+	evt = stampUserAgent(evt)
+
+	if c.claimCheck != nil {
+		if evt, err = c.claimCheck.checkIn(ctx, evt); err != nil {
+			return err
+		}
+	}
 
-	// make sure the current client is the newest
+	err = validateEvent(evt, c.maxEventSize)
+	if !dryRun {
+		c.observePayloadSize(options.AuditSent, evt, errors.Is(err, ErrPayloadTooLarge))
+	}
+	if err != nil {
+		return err
+	}
+
+	// make sure the current client is the newest, and the event is sent with the context of its transport
 	c.RLock()
 	defer c.RUnlock()
 
+	sendingCtx, err := c.cloudEventsOptions.WithContext(ctx, evt.Context)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		klog.V(4).Infof("Dry-run event: %v\n%s", ctx, evt)
+		return nil
+	}
+
+	klog.V(4).Infof("Sent event: %v\n%s", ctx, evt)
+
 	if c.cloudEventsClient == nil {
-		return fmt.Errorf("the cloudevents client is not ready")
+		err := fmt.Errorf("%w: the cloudevents client is not ready", ErrNotConnected)
+		c.status.publishFailed(err, false)
+		return err
 	}
 
 	if result := c.cloudEventsClient.Send(sendingCtx, evt); cloudevents.IsUndelivered(result) {
-		return fmt.Errorf("failed to send event %s, %v", evt, result)
+		timeout := isTimeout(sendingCtx, result)
+		c.status.publishFailed(result, timeout)
+		if timeout {
+			return fmt.Errorf("%w: failed to send event %s, %w", ErrPublishTimeout, evt, result)
+		}
+		return fmt.Errorf("failed to send event %s, %w", evt, result)
+	}
+
+	c.audit(ctx, options.AuditSent, evt)
+	return nil
+}
+
+// waitRateLimiter waits until the event is allowed to send by the rate limiter of the client.
+func (c *baseClient) waitRateLimiter(ctx context.Context, evt cloudevents.Event) error {
+	now := time.Now()
+
+	if err := c.cloudEventsRateLimiter.Wait(ctx); err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return fmt.Errorf("client rate limiter Wait returned an error: %w", err)
+		}
+
+		// the rate limiter returns an error if the context deadline is exceeded or would be exceeded before the event
+		// is allowed to send
+		return fmt.Errorf("%w: client rate limiter Wait returned an error: %w", ErrPublishTimeout, err)
 	}
 
+	latency := time.Since(now)
+	if latency > longThrottleLatency {
+		klog.Warningf(fmt.Sprintf("Waited for %v due to client-side throttling, not priority and fairness, request: %s",
+			latency, evt))
+	}
 	return nil
 }
 
@@ -147,27 +388,83 @@ func (c *baseClient) subscribe(ctx context.Context, receive receiveFn) {
 	}
 
 	c.receiverChan = make(chan int)
+	c.receiverDone = make(chan struct{})
+
+	// observe the sizes of the received events, discard the events of the other tenants, fetch the offloaded data of
+	// the received events, validate them with the event schemas, audit them and pass them to the extension hook before
+	// they are handled
+	handle := receive
+	receive = func(ctx context.Context, evt cloudevents.Event) {
+		c.observePayloadSize(options.AuditReceived, evt, false)
+
+		if err := c.checkTenant(evt); err != nil {
+			c.discard(evt, err.Error())
+			return
+		}
+
+		evt, err := c.checkOut(ctx, evt)
+		if err != nil {
+			c.discard(evt, err.Error())
+			return
+		}
+
+		if c.eventSchemas != nil {
+			if err := c.eventSchemas.Validate(evt); err != nil {
+				c.discard(evt, err.Error())
+				return
+			}
+		}
+
+		c.audit(ctx, options.AuditReceived, evt)
+		handle(c.extensionsReceived(ctx, evt), evt)
+	}
 
 	// start a go routine to handle cloudevents subscription
 	go func() {
-		receiverCtx, receiverCancel := context.WithCancel(context.TODO())
+		// the receiverChan is never closed, the connect go routine may still send the signals to it, the signals are
+		// dropped once the receiverDone is closed
+		defer close(c.receiverDone)
+
+		// the receiver is stopped once the context of the subscription is done
+		receiverCtx, receiverCancel := context.WithCancel(ctx)
 		cloudEventsClient := c.cloudEventsClient
 
 		for {
 			if cloudEventsClient != nil {
-				go func() {
+				// the receiver is started with the current client and context, they are replaced on the signals
+				go func(cloudEventsClient cloudevents.Client, receiverCtx context.Context) {
 					if err := cloudEventsClient.StartReceiver(receiverCtx, func(evt cloudevents.Event) {
+						if !c.acquire() {
+							c.discard(evt, "the client is draining")
+							return
+						}
+
+						if c.receiveBuffer != nil && !c.receiveBuffer.reserve() {
+							c.release()
+							c.discard(evt, "the receive buffer is full")
+							return
+						}
+
+						if c.dispatcher != nil {
+							c.dispatcher.dispatch(receiverCtx, evt, receive)
+							return
+						}
+
+						defer c.received()
 						receive(receiverCtx, evt)
 					}); err != nil {
 						runtime.HandleError(fmt.Errorf("failed to receive cloudevents, %v", err))
 					}
-				}()
+				}(cloudEventsClient, receiverCtx)
 			}
 
 			select {
 			case <-ctx.Done():
 				receiverCancel()
-				close(c.receiverChan)
+				return
+			case <-c.stopChan:
+				// the client is closed, stop the receiver to close the subscriptions
+				receiverCancel()
 				return
 			case signal, ok := <-c.receiverChan:
 				if !ok {
@@ -185,7 +482,7 @@ func (c *baseClient) subscribe(ctx context.Context, receive receiveFn) {
 					c.RUnlock()
 
 					// rebuild the receiver context
-					receiverCtx, receiverCancel = context.WithCancel(context.TODO())
+					receiverCtx, receiverCancel = context.WithCancel(ctx)
 				case stopReceiverSignal:
 					klog.V(4).Infof("stop the cloudevents receiver")
 					receiverCancel()
@@ -198,6 +495,80 @@ func (c *baseClient) subscribe(ctx context.Context, receive receiveFn) {
 	}()
 }
 
+// discardDuplicated discards the received event if it has been received, it returns true if the event is discarded.
+func (c *baseClient) discardDuplicated(evt cloudevents.Event) bool {
+	if c.dedupeCache == nil || !c.dedupeCache.seen(evt) {
+		return false
+	}
+
+	c.discard(evt, "the event is duplicated")
+	return true
+}
+
+// discardExpired discards the received event if it is expired, it returns true if the event is discarded.
+func (c *baseClient) discardExpired(evt cloudevents.Event) bool {
+	expired, err := types.IsExpired(evt, time.Now())
+	if err != nil {
+		klog.Errorf("failed to check the expiration of event %s, %v", evt.ID(), err)
+		return false
+	}
+
+	if !expired {
+		return false
+	}
+
+	c.discard(evt, "the event is expired")
+	return true
+}
+
+// discard reports a received event that is discarded without handling.
+func (c *baseClient) discard(evt cloudevents.Event, reason string) {
+	klog.V(4).Infof("Discard event %s, %s", evt.ID(), reason)
+
+	if c.discardedEventHandler != nil {
+		c.discardedEventHandler(evt, reason)
+	}
+}
+
+// checkOut fetches the offloaded data of a received event with its claim-check reference.
+func (c *baseClient) checkOut(ctx context.Context, evt cloudevents.Event) (cloudevents.Event, error) {
+	if c.claimCheck != nil {
+		return c.claimCheck.checkOut(ctx, evt)
+	}
+
+	if _, _, _, ok, _ := claimCheckReference(evt); ok {
+		return evt, fmt.Errorf("the claim-check is not configured to fetch the data of event %s", evt.ID())
+	}
+
+	return evt, nil
+}
+
+// transport returns the current cloudevents options of the client.
+func (c *baseClient) transport() options.CloudEventsOptions {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.cloudEventsOptions
+}
+
+// switchTransport reconnects the client with the given cloudevents options, it returns once the client starts to
+// switch, and the reconnection is notified with the reconnected signal after the client is connected to the new
+// transport.
+func (c *baseClient) switchTransport(ctx context.Context, cloudEventsOptions options.CloudEventsOptions) error {
+	if cloudEventsOptions == nil {
+		return fmt.Errorf("the cloudevents options are required")
+	}
+
+	select {
+	case c.switchChan <- cloudEventsOptions:
+		return nil
+	case <-c.stopChan:
+		return fmt.Errorf("failed to switch the transport, the client is closed")
+	case <-ctx.Done():
+		return fmt.Errorf("failed to switch the transport, %w", ctx.Err())
+	}
+}
+
 func (c *baseClient) resetClient(client cloudevents.Client) {
 	c.Lock()
 	defer c.Unlock()
@@ -210,12 +581,213 @@ func (c *baseClient) sendReceiverSignal(signal int) {
 	defer c.RUnlock()
 
 	if c.receiverChan != nil {
-		c.receiverChan <- signal
+		select {
+		case c.receiverChan <- signal:
+		case <-c.receiverDone:
+		case <-c.stopChan:
+		}
+	}
+}
+
+// startResync marks the resync request of the given cluster or source is in progress, it returns ErrResyncInProgress
+// if the previous resync request of the cluster or source is not finished. The returned func must be called with the
+// result after the resync request is sent.
+func (c *baseClient) startResync(target string) (func(error), error) {
+	c.resyncLock.Lock()
+	defer c.resyncLock.Unlock()
+
+	if c.resyncTargets == nil {
+		c.resyncTargets = map[string]*options.ResyncTargetStatus{}
+	}
+
+	status, ok := c.resyncTargets[target]
+	if !ok {
+		status = &options.ResyncTargetStatus{}
+		c.resyncTargets[target] = status
+	}
+
+	if status.InProgress {
+		return nil, fmt.Errorf("%w: the resync of %q is not finished", ErrResyncInProgress, target)
+	}
+
+	status.InProgress = true
+	status.LastAttemptTime = time.Now()
+	return func(err error) {
+		c.finishResync(target, err)
+	}, nil
+}
+
+func (c *baseClient) finishResync(target string, err error) {
+	c.resyncLock.Lock()
+	status := c.resyncTargets[target]
+	status.InProgress = false
+	status.LastError = err
+	if err != nil {
+		status.ConsecutiveFailures++
+	} else {
+		status.ConsecutiveFailures = 0
+		status.LastSuccessTime = time.Now()
+		c.lastResyncTime = status.LastSuccessTime
+	}
+	c.resyncLock.Unlock()
+
+	if c.resyncCompleteHandler != nil {
+		c.resyncCompleteHandler(target, err)
+	}
+}
+
+// ResyncStatus returns the resync state of the client, it includes the time of the last successful resync and the
+// resync progress of each cluster or source that the client has resynced.
+func (c *baseClient) ResyncStatus() options.ResyncStatus {
+	c.resyncLock.Lock()
+	defer c.resyncLock.Unlock()
+
+	status := options.ResyncStatus{
+		LastResyncTime: c.lastResyncTime,
+		Targets:        make(map[string]options.ResyncTargetStatus, len(c.resyncTargets)),
+	}
+	for target, targetStatus := range c.resyncTargets {
+		status.Targets[target] = *targetStatus
+	}
+	return status
+}
+
+// Features returns the feature negotiator of the client, it tells whether a protocol feature can be used with a cluster
+// on a source client or with a source on an agent client.
+func (c *baseClient) Features() *features.Negotiator {
+	return c.featureNegotiator
+}
+
+// sendReconnectedSignalWithJitter sends the reconnected signal after a random delay of the resync jitter, so the clients
+// that are reconnected at the same time do not resync at the same time.
+func (c *baseClient) sendReconnectedSignalWithJitter() {
+	delay := randomDelay(c.jitter.Resync)
+	if delay == 0 {
+		c.sendReconnectedSignal()
+		return
 	}
+
+	klog.V(4).Infof("send the reconnected signal after %v", delay)
+	timer := c.clock.NewTimer(delay)
+	go func() {
+		select {
+		case <-timer.C():
+		case <-c.stopChan:
+			timer.Stop()
+			return
+		}
+
+		select {
+		case <-c.stopChan:
+			// the client is stopped while the timer is firing
+		default:
+			c.sendReconnectedSignal()
+		}
+	}()
 }
 
 func (c *baseClient) sendReconnectedSignal() {
+	if c.reconnectedChan == nil {
+		// the client does not resync, e.g. an observer
+		return
+	}
+
+	// do not hold the lock while the signal is sent, the resync of the signal receiver may publish with the lock, and
+	// a transport switch may be waiting for the lock
+	c.RLock()
+	reconnectedChan, stopChan := c.reconnectedChan, c.stopChan
+	c.RUnlock()
+
+	select {
+	case reconnectedChan <- struct{}{}:
+	case <-stopChan:
+	}
+}
+
+// Drain stops accepting new publishes and received events, publishes the pending coalesced updates, and waits for the
+// in-flight publishes and the handlers of the received events to finish. The received events are discarded after the
+// client is draining. The returned error aggregates the errors of the pending updates that fail to be published.
+func (c *baseClient) Drain(ctx context.Context) error {
+	c.Lock()
+	c.closing = true
+	flushers := c.flushers
+	c.Unlock()
+
+	errs := []error{}
+	for _, flush := range flushers {
+		if err := flush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	c.Lock()
+	c.draining = true
+	c.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("failed to drain the client, %v", ctx.Err()))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// Close drains the client, then stops the receiver and the reconnection of the client. Stopping the receiver closes
+// the subscriptions to the broker, e.g. the MQTT client is disconnected and the gRPC subscription streams are closed.
+func (c *baseClient) Close(ctx context.Context) error {
+	err := c.Drain(ctx)
+
+	c.stopOnce.Do(func() {
+		close(c.stopChan)
+	})
+
+	return err
+}
+
+// checkContext returns ErrClientClosed if the client is draining or closed, or the error of the context if it is done,
+// so the operations that do not publish at once, e.g. the coalesced publishes and the resyncs, fail fast.
+func (c *baseClient) checkContext(ctx context.Context) error {
+	c.RLock()
+	closing := c.closing
+	c.RUnlock()
+
+	if closing {
+		return fmt.Errorf("%w: the client is draining", ErrClientClosed)
+	}
+
+	return contextError(ctx)
+}
+
+// acquire records an in-flight publish or received event, it returns false if the client is draining.
+func (c *baseClient) acquire() bool {
 	c.RLock()
 	defer c.RUnlock()
-	c.reconnectedChan <- struct{}{}
+
+	if c.draining {
+		return false
+	}
+
+	c.inflight.Add(1)
+	c.inflightCount.Add(1)
+	return true
+}
+
+// release is called after an in-flight publish or received event is done.
+func (c *baseClient) release() {
+	c.inflightCount.Add(-1)
+	c.inflight.Done()
+}
+
+// received is called after a received event is processed.
+func (c *baseClient) received() {
+	if c.receiveBuffer != nil {
+		c.receiveBuffer.release()
+	}
+	c.release()
 }
@@ -0,0 +1,304 @@
+// Package bridge relays the cloud events from one broker to another, e.g. from an MQTT broker to a gRPC server, for
+// the hierarchical hub-of-hubs topologies, where the regional hubs relay the events between the global hub and the
+// managed clusters.
+//
+// A Bridge relays the events in one direction, it receives the events with the cloudevents options of one transport,
+// e.g. the observer options that subscribe to the events of all sources and clusters, and publishes them with the
+// cloudevents options of another transport, e.g. the source options for the spec events. The bridges of both
+// directions between two brokers should use the same ID, so an event that is relayed by one of them is never relayed
+// back by the other.
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventscontext "github.com/cloudevents/sdk-go/v2/context"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	// DefaultMaxHops is the default number of the bridges that an event can be relayed by.
+	DefaultMaxHops = 4
+
+	// DefaultRetryInterval is the default interval to reconnect the transports after they are disconnected.
+	DefaultRetryInterval = 5 * time.Second
+)
+
+// Filter returns true if the event is relayed by the bridge.
+type Filter func(evt cloudevents.Event) bool
+
+// Mapper remaps an event before it is relayed, e.g. prefixes the source of the spec events, it returns an error if
+// the event cannot be relayed.
+type Mapper func(evt cloudevents.Event) (cloudevents.Event, error)
+
+// Router returns the context to publish an event, e.g. with the topic of the event on the target broker, by default,
+// the WithContext of the target cloudevents options is used.
+type Router func(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error)
+
+// Stats are the counters of the events of a bridge.
+type Stats struct {
+	// Received is the number of the received events.
+	Received int64
+
+	// Relayed is the number of the events that are published to the target broker.
+	Relayed int64
+
+	// Filtered is the number of the events that are not selected by the filter.
+	Filtered int64
+
+	// Looped is the number of the events that are dropped by the loop prevention.
+	Looped int64
+
+	// Failed is the number of the events that are failed to remap or publish.
+	Failed int64
+}
+
+// Bridge relays the events from a broker to another broker.
+type Bridge struct {
+	id            string
+	from          options.CloudEventsOptions
+	to            options.CloudEventsOptions
+	filter        Filter
+	mapper        Mapper
+	router        Router
+	maxHops       int
+	retryInterval time.Duration
+	clock         clock.Clock
+
+	received atomic.Int64
+	relayed  atomic.Int64
+	filtered atomic.Int64
+	looped   atomic.Int64
+	failed   atomic.Int64
+}
+
+// NewBridge returns a Bridge with the given ID that receives the events with the from options and publishes them with
+// the to options.
+func NewBridge(id string, from, to options.CloudEventsOptions) *Bridge {
+	return &Bridge{
+		id:            id,
+		from:          from,
+		to:            to,
+		maxHops:       DefaultMaxHops,
+		retryInterval: DefaultRetryInterval,
+		clock:         clock.RealClock{},
+	}
+}
+
+// WithFilter sets the filter of the relayed events, by default, all the received events are relayed.
+func (b *Bridge) WithFilter(filter Filter) *Bridge {
+	b.filter = filter
+	return b
+}
+
+// WithMapper sets the mapper to remap the events before they are relayed.
+func (b *Bridge) WithMapper(mapper Mapper) *Bridge {
+	b.mapper = mapper
+	return b
+}
+
+// WithRouter sets the router to publish the events to the target broker, e.g. a TopicRouter to remap the topics.
+func (b *Bridge) WithRouter(router Router) *Bridge {
+	b.router = router
+	return b
+}
+
+// WithMaxHops sets the number of the bridges that an event can be relayed by, the events that have been relayed by
+// more bridges are dropped.
+func (b *Bridge) WithMaxHops(maxHops int) *Bridge {
+	b.maxHops = maxHops
+	return b
+}
+
+// WithRetryInterval sets the interval to reconnect the transports after they are disconnected.
+func (b *Bridge) WithRetryInterval(interval time.Duration) *Bridge {
+	b.retryInterval = interval
+	return b
+}
+
+// WithClock sets the clock of the reconnect timer, e.g. a fake clock to fast-forward the reconnects in the tests.
+func (b *Bridge) WithClock(clock clock.Clock) *Bridge {
+	b.clock = clock
+	return b
+}
+
+// Stats returns the counters of the events of the bridge.
+func (b *Bridge) Stats() Stats {
+	return Stats{
+		Received: b.received.Load(),
+		Relayed:  b.relayed.Load(),
+		Filtered: b.filtered.Load(),
+		Looped:   b.looped.Load(),
+		Failed:   b.failed.Load(),
+	}
+}
+
+// Run relays the events until the context is done, the transports are reconnected after the retry interval if any
+// of them is disconnected.
+func (b *Bridge) Run(ctx context.Context) {
+	for {
+		err := b.run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		klog.Errorf("the bridge %s is disconnected, reconnecting after %v, %v", b.id, b.retryInterval, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.clock.After(b.retryInterval):
+		}
+	}
+}
+
+func (b *Bridge) run(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sender, err := b.to.Client(runCtx)
+	if err != nil {
+		return fmt.Errorf("failed to connect the target broker, %v", err)
+	}
+
+	receiver, err := b.from.Client(runCtx)
+	if err != nil {
+		return fmt.Errorf("failed to connect the source broker, %v", err)
+	}
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- receiver.StartReceiver(runCtx, func(evt cloudevents.Event) {
+			b.relay(runCtx, sender, evt)
+		})
+	}()
+
+	klog.V(4).Infof("the bridge %s is connected", b.id)
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-b.from.ErrorChan():
+		return fmt.Errorf("the source broker is disconnected, %v", err)
+	case err := <-b.to.ErrorChan():
+		return fmt.Errorf("the target broker is disconnected, %v", err)
+	case err := <-stopped:
+		return fmt.Errorf("the receiver is stopped, %v", err)
+	}
+}
+
+// relay publishes the event to the target broker with the ID of this bridge in its hops, the event is dropped if it
+// has been relayed by this bridge or by too many bridges.
+func (b *Bridge) relay(ctx context.Context, sender cloudevents.Client, evt cloudevents.Event) {
+	b.received.Add(1)
+
+	hops := Hops(evt)
+	if slices.Contains(hops, b.id) || len(hops) >= b.maxHops {
+		klog.V(4).Infof("drop the event %s that has been relayed by the bridges %v", evt.ID(), hops)
+		b.looped.Add(1)
+		return
+	}
+
+	if b.filter != nil && !b.filter(evt) {
+		b.filtered.Add(1)
+		return
+	}
+
+	relayed := evt.Clone()
+	if b.mapper != nil {
+		mapped, err := b.mapper(relayed)
+		if err != nil {
+			klog.Errorf("failed to remap the event %s, %v", evt.ID(), err)
+			b.failed.Add(1)
+			return
+		}
+		relayed = mapped
+	}
+	relayed.SetExtension(types.ExtensionBridgeHops, strings.Join(append(hops, b.id), ","))
+
+	route := b.to.WithContext
+	if b.router != nil {
+		route = b.router
+	}
+
+	sendCtx, err := route(ctx, relayed.Context)
+	if err != nil {
+		klog.Errorf("failed to route the event %s, %v", evt.ID(), err)
+		b.failed.Add(1)
+		return
+	}
+
+	if result := sender.Send(sendCtx, relayed); cloudevents.IsUndelivered(result) {
+		klog.Errorf("failed to relay the event %s, %v", evt.ID(), result)
+		b.failed.Add(1)
+		return
+	}
+
+	b.relayed.Add(1)
+}
+
+// Hops returns the IDs of the bridges that have relayed the event in order.
+func Hops(evt cloudevents.Event) []string {
+	val, ok := evt.Extensions()[types.ExtensionBridgeHops]
+	if !ok {
+		return nil
+	}
+
+	hops, err := cloudeventstypes.ToString(val)
+	if err != nil || len(hops) == 0 {
+		return nil
+	}
+
+	return strings.Split(hops, ",")
+}
+
+// SubResourceFilter returns a filter that selects the events of the given subresource, e.g. the spec events are
+// relayed from the global hub to the managed clusters and the status events are relayed back.
+func SubResourceFilter(subResource types.EventSubResource) Filter {
+	return func(evt cloudevents.Event) bool {
+		eventType, err := types.ParseCloudEventsType(evt.Type())
+		if err != nil {
+			return false
+		}
+		return eventType.SubResource == subResource
+	}
+}
+
+// TopicRouter returns a router that publishes the events to the topics of the template, the {source}, {clustername}
+// and {originalsource} in the template are replaced with the source, the cluster name and the original source of the
+// event, e.g. `sources/{originalsource}/clusters/{clustername}/agentevents` remaps the status events of the clusters
+// to the topics of their original sources.
+func TopicRouter(template string) Router {
+	return func(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+		topic := strings.ReplaceAll(template, "{source}", evtCtx.GetSource())
+		for _, extension := range []string{types.ExtensionClusterName, types.ExtensionOriginalSource} {
+			placeholder := "{" + extension + "}"
+			if !strings.Contains(topic, placeholder) {
+				continue
+			}
+
+			val, err := evtCtx.GetExtension(extension)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the %s of the event, %v", extension, err)
+			}
+
+			str, err := cloudeventstypes.ToString(val)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the %s of the event, %v", extension, err)
+			}
+			topic = strings.ReplaceAll(topic, placeholder, str)
+		}
+
+		return cloudeventscontext.WithTopic(ctx, topic), nil
+	}
+}
@@ -0,0 +1,118 @@
+package bridge
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventscontext "github.com/cloudevents/sdk-go/v2/context"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func newEvent(id string, subResource types.EventSubResource, hops string) cloudevents.Event {
+	evt := types.NewEventBuilder("source1", types.CloudEventsType{
+		CloudEventsDataType: types.CloudEventsDataType{Group: "test", Version: "v1", Resource: "tests"},
+		SubResource:         subResource,
+		Action:              "update_request",
+	}).WithID(id).WithClusterName("cluster1").WithOriginalSource("source1").NewEvent()
+	if len(hops) != 0 {
+		evt.SetExtension(types.ExtensionBridgeHops, hops)
+	}
+	return evt
+}
+
+func TestRelay(t *testing.T) {
+	sender := fake.NewCloudEventsFakeClient()
+	bridge := NewBridge("bridge1", nil, fake.NewSourceOptions(sender, "source1").CloudEventsOptions).
+		WithFilter(SubResourceFilter(types.SubResourceSpec)).
+		WithMaxHops(2)
+
+	for _, evt := range []cloudevents.Event{
+		newEvent("spec", types.SubResourceSpec, ""),
+		newEvent("relayed", types.SubResourceSpec, "bridge0"),
+		newEvent("looped", types.SubResourceSpec, "bridge0,bridge1"),
+		newEvent("too-many-hops", types.SubResourceSpec, "bridge2,bridge3"),
+		newEvent("status", types.SubResourceStatus, ""),
+	} {
+		bridge.relay(context.TODO(), sender, evt)
+	}
+
+	sent := sender.GetSentEvents()
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 relayed events, but got %v", sent)
+	}
+	if hops := Hops(sent[0]); !reflect.DeepEqual(hops, []string{"bridge1"}) {
+		t.Errorf("expected [bridge1], but got %v", hops)
+	}
+	if hops := Hops(sent[1]); !reflect.DeepEqual(hops, []string{"bridge0", "bridge1"}) {
+		t.Errorf("expected [bridge0 bridge1], but got %v", hops)
+	}
+
+	expected := Stats{Received: 5, Relayed: 2, Filtered: 1, Looped: 2}
+	if stats := bridge.Stats(); stats != expected {
+		t.Errorf("expected %v, but got %v", expected, stats)
+	}
+}
+
+func TestTopicRouter(t *testing.T) {
+	evt := newEvent("status", types.SubResourceStatus, "")
+
+	ctx, err := TopicRouter("sources/{originalsource}/clusters/{clustername}/agentevents")(context.TODO(), evt.Context)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if topic := cloudeventscontext.TopicFrom(ctx); topic != "sources/source1/clusters/cluster1/agentevents" {
+		t.Errorf("expected sources/source1/clusters/cluster1/agentevents, but got %s", topic)
+	}
+
+	evt.SetExtension(types.ExtensionClusterName, nil)
+	if _, err := TopicRouter("clusters/{clustername}")(context.TODO(), evt.Context); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	receiver := fake.NewCloudEventsFakeClient(newEvent("spec", types.SubResourceSpec, ""))
+	sender := fake.NewCloudEventsFakeClient()
+	bridge := NewBridge("bridge1",
+		fake.NewAgentOptions(receiver, "cluster1", "agent1").CloudEventsOptions,
+		fake.NewSourceOptions(sender, "source1").CloudEventsOptions).
+		WithMapper(func(evt cloudevents.Event) (cloudevents.Event, error) {
+			evt.SetSource("global/" + evt.Source())
+			return evt, nil
+		}).
+		WithRetryInterval(time.Hour)
+
+	done := make(chan struct{})
+	go func() {
+		bridge.Run(ctx)
+		close(done)
+	}()
+
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			return len(sender.GetSentEvents()) == 1, nil
+		}); err != nil {
+		t.Fatalf("the event is not relayed, %v", err)
+	}
+
+	if source := sender.GetSentEvents()[0].Source(); source != "global/source1" {
+		t.Errorf("expected global/source1, but got %s", source)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("the bridge is not stopped")
+	}
+}
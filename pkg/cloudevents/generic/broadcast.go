@@ -0,0 +1,98 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// PublishToClusters publishes a resource spec from a source to the agents of the given clusters. The resource is
+// encoded once, and the event is published to each cluster with the `clustername` extension of the cluster, so the
+// callers do not copy the resource and change its namespace for each cluster. The spec is published to the other
+// clusters if it fails to be published to a cluster, the returned error aggregates the errors of the failed clusters,
+// and the clusters that are not published yet are skipped once the context is done.
+func (c *CloudEventSourceClient[T]) PublishToClusters(
+	ctx context.Context, eventType types.CloudEventsType, obj T, clusterNames []string) error {
+	if eventType.SubResource != types.SubResourceSpec {
+		return fmt.Errorf("%w: unsupported event eventType %s", ErrUnsupportedType, eventType)
+	}
+
+	if err := c.checkContext(ctx); err != nil {
+		return err
+	}
+
+	evt, err := c.encodeObject(eventType, obj)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for i, clusterName := range clusterNames {
+		if clusterName == types.ClusterAll {
+			errs = append(errs, fmt.Errorf("the cluster name must not be empty, use Broadcast to publish to all clusters"))
+			continue
+		}
+
+		if err := contextError(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("the resource %s is not published to %d clusters, %w",
+				obj.GetUID(), len(clusterNames)-i, err))
+			break
+		}
+
+		clusterEvt := clusterEvent(evt, clusterName)
+		if err := c.publishEncoded(ctx, &clusterEvt, obj, nil); err != nil {
+			errs = append(errs, fmt.Errorf("failed to publish the resource %s to the cluster %s, %w",
+				obj.GetUID(), clusterName, err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// Broadcast publishes a resource spec from a source to the agents of all clusters with a single event, the event has
+// the `broadcast` extension and the `clustername` extension of types.ClusterAll, it is delivered to all the agents by
+// the broker, e.g. on the source broadcast topic of MQTT or the shared topic of Pub/Sub, then each agent handles it as
+// a spec of its own cluster. The transports that cannot broadcast the specs, e.g. gRPC, return an error, use
+// PublishToClusters instead. The resources per cluster quota is not checked for the broadcast specs.
+func (c *CloudEventSourceClient[T]) Broadcast(ctx context.Context, eventType types.CloudEventsType, obj T) error {
+	if eventType.SubResource != types.SubResourceSpec {
+		return fmt.Errorf("%w: unsupported event eventType %s", ErrUnsupportedType, eventType)
+	}
+
+	if err := c.checkContext(ctx); err != nil {
+		return err
+	}
+
+	evt, err := c.encodeObject(eventType, obj)
+	if err != nil {
+		return err
+	}
+
+	broadcastEvt := clusterEvent(evt, types.ClusterAll)
+	return c.publishEncoded(ctx, &broadcastEvt, obj, map[string]any{types.ExtensionBroadcast: true})
+}
+
+// clusterEvent returns a copy of the event that is sent to the given cluster, the copy has its own ID.
+func clusterEvent(evt *cloudevents.Event, clusterName string) cloudevents.Event {
+	clusterEvt := evt.Clone()
+	clusterEvt.SetID(uuid.New().String())
+	clusterEvt.SetExtension(types.ExtensionClusterName, clusterName)
+	return clusterEvt
+}
+
+// rescopeBroadcast returns the spec event that is broadcast to all clusters as an event of the cluster of the agent.
+func (c *CloudEventAgentClient[T]) rescopeBroadcast(evt cloudevents.Event) cloudevents.Event {
+	if !types.IsBroadcast(evt) {
+		return evt
+	}
+
+	evt = evt.Clone()
+	evt.SetExtension(types.ExtensionClusterName, c.clusterName)
+	return evt
+}
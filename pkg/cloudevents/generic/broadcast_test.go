@@ -0,0 +1,130 @@
+package generic
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestPublishToClusters(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	resource := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"}
+
+	cases := []struct {
+		name             string
+		clusterNames     []string
+		expectedClusters []string
+		expectedErr      bool
+	}{
+		{
+			name:             "publish to clusters",
+			clusterNames:     []string{"cluster1", "cluster2", "cluster3"},
+			expectedClusters: []string{"cluster1", "cluster2", "cluster3"},
+		},
+		{
+			name:             "empty cluster name",
+			clusterNames:     []string{"cluster1", types.ClusterAll, "cluster2"},
+			expectedClusters: []string{"cluster1", "cluster2"},
+			expectedErr:      true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fakeClient := fake.NewCloudEventsFakeClient()
+			source, err := NewCloudEventSourceClient[*mockResource](context.TODO(),
+				fake.NewSourceOptions(fakeClient, testSourceName), newMockResourceLister(), statusHash, newMockResourceCodec())
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			err = source.PublishToClusters(context.TODO(), eventType, resource, c.clusterNames)
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+
+			sentEvents := fakeClient.GetSentEvents()
+			if len(sentEvents) != len(c.expectedClusters) {
+				t.Fatalf("expected %d events, but got %d", len(c.expectedClusters), len(sentEvents))
+			}
+
+			ids := sets.New[string]()
+			for i, evt := range sentEvents {
+				clusterName, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if clusterName != c.expectedClusters[i] {
+					t.Errorf("expected %s, but got %s", c.expectedClusters[i], clusterName)
+				}
+				ids.Insert(evt.ID())
+			}
+
+			if ids.Len() != len(sentEvents) {
+				t.Errorf("expected the events have their own IDs, but got %v", ids.UnsortedList())
+			}
+		})
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	resource := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"}
+
+	fakeClient := fake.NewCloudEventsFakeClient()
+	source, err := NewCloudEventSourceClient[*mockResource](context.TODO(),
+		fake.NewSourceOptions(fakeClient, testSourceName), newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := source.Broadcast(context.TODO(), eventType, resource); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	sentEvents := fakeClient.GetSentEvents()
+	if len(sentEvents) != 1 {
+		t.Fatalf("expected one event, but got %d", len(sentEvents))
+	}
+
+	clusterName, err := cloudeventstypes.ToString(sentEvents[0].Extensions()[types.ExtensionClusterName])
+	if err != nil || clusterName != types.ClusterAll || !types.IsBroadcast(sentEvents[0]) {
+		t.Errorf("expected the event is broadcast to all clusters, but got %q, %v", clusterName, err)
+	}
+
+	// the agent handles the broadcast spec as a spec of its cluster
+	agent, err := NewCloudEventAgentClient[*mockResource](context.TODO(),
+		fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster2", testAgentName),
+		newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	rescoped := agent.rescopeBroadcast(sentEvents[0])
+	if clusterName, _ := cloudeventstypes.ToString(rescoped.Extensions()[types.ExtensionClusterName]); clusterName != "cluster2" {
+		t.Errorf("expected cluster2, but got %s", clusterName)
+	}
+
+	// the spec of a cluster is not rescoped
+	evt := cloudevents.NewEvent()
+	evt.SetExtension(types.ExtensionClusterName, "cluster3")
+	if clusterName, _ := cloudeventstypes.ToString(agent.rescopeBroadcast(evt).Extensions()[types.ExtensionClusterName]); clusterName != "cluster3" {
+		t.Errorf("expected cluster3, but got %s", clusterName)
+	}
+}
@@ -0,0 +1,175 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/uuid"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// callTracker tracks the call requests that are waiting for the call responses, a call request is completed by the
+// first response that is correlated with it.
+type callTracker struct {
+	sync.Mutex
+	pending map[string]chan cloudevents.Event
+}
+
+func newCallTracker() *callTracker {
+	return &callTracker{pending: map[string]chan cloudevents.Event{}}
+}
+
+// add starts waiting for the response of the call request with the given event ID, it must be called before the
+// request is sent, so the response that is received before the request is sent returns is not missed.
+func (t *callTracker) add(requestID string) <-chan cloudevents.Event {
+	t.Lock()
+	defer t.Unlock()
+
+	responseChan := make(chan cloudevents.Event, 1)
+	t.pending[requestID] = responseChan
+	return responseChan
+}
+
+func (t *callTracker) remove(requestID string) {
+	t.Lock()
+	defer t.Unlock()
+
+	delete(t.pending, requestID)
+}
+
+// len returns the number of the call requests that are waiting for their responses.
+func (t *callTracker) len() int {
+	t.Lock()
+	defer t.Unlock()
+
+	return len(t.pending)
+}
+
+// complete delivers a call response to the request that it is correlated with, the responses of the requests that are
+// not pending, e.g. the requests that are timed out, are ignored.
+func (t *callTracker) complete(evt cloudevents.Event) error {
+	requestID, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionCorrelationID])
+	if err != nil {
+		return fmt.Errorf("failed to get correlationid extension: %v", err)
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	responseChan, ok := t.pending[requestID]
+	if !ok {
+		return fmt.Errorf("the call request %s is not pending", requestID)
+	}
+
+	select {
+	case responseChan <- evt:
+	default:
+		// the request is already responded
+	}
+	return nil
+}
+
+// call sends a request as a call request of its data type and waits for the correlated response. The request is sent
+// from the given source with the given routing extensions, and the response is expected to be sent to the replyTo.
+func (c *baseClient) call(ctx context.Context, request cloudevents.Event, source string,
+	subResource types.EventSubResource, clusterName, originalSource, replyTo string) (cloudevents.Event, error) {
+	eventType, err := types.ParseCloudEventsType(request.Type())
+	if err != nil {
+		return cloudevents.Event{}, fmt.Errorf("%w: failed to parse the type of the call request, %w",
+			ErrUnsupportedType, err)
+	}
+	eventType.SubResource = subResource
+	eventType.Action = types.CallRequestAction
+
+	request = request.Clone()
+	if len(request.ID()) == 0 {
+		request.SetID(uuid.New().String())
+	}
+	if request.Time().IsZero() {
+		request.SetTime(time.Now())
+	}
+	request.SetSource(source)
+	request.SetType(eventType.String())
+	request.SetExtension(types.ExtensionClusterName, clusterName)
+	request.SetExtension(types.ExtensionOriginalSource, originalSource)
+	request.SetExtension(types.ExtensionReplyTo, replyTo)
+
+	ctx, cancel := context.WithTimeout(ctx, c.callTimeout)
+	defer cancel()
+
+	responseChan := c.calls.add(request.ID())
+	defer c.calls.remove(request.ID())
+
+	if err := c.publish(ctx, request); err != nil {
+		return cloudevents.Event{}, err
+	}
+
+	select {
+	case response := <-responseChan:
+		if callErr, ok := response.Extensions()[types.ExtensionCallError]; ok {
+			return response, fmt.Errorf("%w: %v", ErrCallFailed, callErr)
+		}
+		return response, nil
+	case <-c.stopChan:
+		return cloudevents.Event{}, fmt.Errorf("%w: failed to wait for the response of the call %s",
+			ErrClientClosed, request.ID())
+	case <-ctx.Done():
+		if isTimeout(ctx, ctx.Err()) {
+			return cloudevents.Event{}, fmt.Errorf("%w: failed to wait for the response of the call %s, %w",
+				ErrCallTimeout, request.ID(), ctx.Err())
+		}
+		return cloudevents.Event{}, fmt.Errorf("failed to wait for the response of the call %s, %w",
+			request.ID(), ctx.Err())
+	}
+}
+
+// respondCall responds a call request with the call handler, the response is sent from the given source with the given
+// routing extensions. If the handler is not set or it returns an error, the response carries the error.
+func (c *baseClient) respondCall(ctx context.Context, request cloudevents.Event, source string,
+	subResource types.EventSubResource, clusterName, originalSource string) error {
+	eventType, err := types.ParseCloudEventsType(request.Type())
+	if err != nil {
+		return err
+	}
+	eventType.SubResource = subResource
+	eventType.Action = types.CallResponseAction
+
+	var response *cloudevents.Event
+	if c.callHandler == nil {
+		err = fmt.Errorf("the call handler is not set")
+	} else {
+		response, err = c.callHandler(ctx, request)
+	}
+
+	responseEvt := cloudevents.NewEvent()
+	if response != nil && err == nil {
+		responseEvt = response.Clone()
+	}
+	responseEvt.SetID(uuid.New().String())
+	responseEvt.SetTime(time.Now())
+	responseEvt.SetSource(source)
+	responseEvt.SetType(eventType.String())
+	responseEvt.SetExtension(types.ExtensionClusterName, clusterName)
+	responseEvt.SetExtension(types.ExtensionOriginalSource, originalSource)
+	responseEvt.SetExtension(types.ExtensionCorrelationID, request.ID())
+	if err != nil {
+		responseEvt.SetExtension(types.ExtensionCallError, err.Error())
+	}
+
+	return c.publish(ctx, responseEvt)
+}
+
+// replyTo returns the replyto extension of a call request.
+func replyTo(request cloudevents.Event) (string, error) {
+	replyTo, err := cloudeventstypes.ToString(request.Extensions()[types.ExtensionReplyTo])
+	if err != nil {
+		return "", fmt.Errorf("failed to get replyto extension: %v", err)
+	}
+
+	return replyTo, nil
+}
@@ -0,0 +1,170 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func newCallRequest(data string) cloudevents.Event {
+	request := cloudevents.NewEvent()
+	request.SetType("io.open-cluster-management.mustgather.v1alpha1.bundles.spec.collect")
+	if err := request.SetData(cloudevents.ApplicationJSON, map[string]string{"name": data}); err != nil {
+		panic(err)
+	}
+	return request
+}
+
+// relay delivers the events that are sent by a client to the receive func until the context is done.
+func relay(ctx context.Context, client *fake.CloudEventsFakeClient, receive func(context.Context, cloudevents.Event)) {
+	relayed := 0
+	for ctx.Err() == nil {
+		events := client.GetSentEvents()
+		for ; relayed < len(events); relayed++ {
+			receive(ctx, events[relayed])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCall(t *testing.T) {
+	echoHandler := func(ctx context.Context, request cloudevents.Event) (*cloudevents.Event, error) {
+		data := map[string]string{}
+		if err := request.DataAs(&data); err != nil {
+			return nil, err
+		}
+		if data["name"] == "bad" {
+			return nil, fmt.Errorf("the bundle %s is not found", data["name"])
+		}
+
+		response := cloudevents.NewEvent()
+		if err := response.SetData(cloudevents.ApplicationJSON, map[string]string{"collected": data["name"]}); err != nil {
+			return nil, err
+		}
+		return &response, nil
+	}
+
+	cases := []struct {
+		name         string
+		fromAgent    bool
+		handler      options.CallHandler
+		relay        bool
+		request      cloudevents.Event
+		expectedErr  error
+		expectedData string
+	}{
+		{
+			name:         "call an agent",
+			handler:      echoHandler,
+			relay:        true,
+			request:      newCallRequest("bundle1"),
+			expectedData: `{"collected":"bundle1"}`,
+		},
+		{
+			name:         "call a source",
+			fromAgent:    true,
+			handler:      echoHandler,
+			relay:        true,
+			request:      newCallRequest("bundle1"),
+			expectedData: `{"collected":"bundle1"}`,
+		},
+		{
+			name:        "the handler fails",
+			handler:     echoHandler,
+			relay:       true,
+			request:     newCallRequest("bad"),
+			expectedErr: ErrCallFailed,
+		},
+		{
+			name:        "no call handler",
+			relay:       true,
+			request:     newCallRequest("bundle1"),
+			expectedErr: ErrCallFailed,
+		},
+		{
+			name:        "no response",
+			handler:     echoHandler,
+			request:     newCallRequest("bundle1"),
+			expectedErr: ErrCallTimeout,
+		},
+		{
+			name:        "invalid request type",
+			handler:     echoHandler,
+			request:     cloudevents.NewEvent(),
+			expectedErr: ErrUnsupportedType,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sourceFakeClient := fake.NewCloudEventsFakeClient()
+			sourceOptions := fake.NewSourceOptions(sourceFakeClient, testSourceName)
+			sourceOptions.CallTimeout = 200 * time.Millisecond
+			agentFakeClient := fake.NewCloudEventsFakeClient()
+			agentOptions := fake.NewAgentOptions(agentFakeClient, "cluster1", testAgentName)
+			agentOptions.CallTimeout = 200 * time.Millisecond
+			if c.fromAgent {
+				sourceOptions.CallHandler = c.handler
+			} else {
+				agentOptions.CallHandler = c.handler
+			}
+
+			source, err := NewCloudEventSourceClient[*mockResource](
+				ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+			if err != nil {
+				t.Fatal(err)
+			}
+			agent, err := NewCloudEventAgentClient[*mockResource](
+				ctx, agentOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if c.relay {
+				go relay(ctx, sourceFakeClient, func(ctx context.Context, evt cloudevents.Event) {
+					agent.receive(ctx, evt)
+				})
+				go relay(ctx, agentFakeClient, func(ctx context.Context, evt cloudevents.Event) {
+					source.receive(ctx, evt)
+				})
+			}
+
+			var response cloudevents.Event
+			if c.fromAgent {
+				response, err = agent.Call(ctx, testSourceName, c.request)
+			} else {
+				response, err = source.Call(ctx, "cluster1", c.request)
+			}
+
+			if !errors.Is(err, c.expectedErr) {
+				t.Fatalf("expected %v, but got %v", c.expectedErr, err)
+			}
+			if c.expectedErr != nil {
+				return
+			}
+
+			if string(response.Data()) != c.expectedData {
+				t.Errorf("expected %s, but got %s", c.expectedData, string(response.Data()))
+			}
+
+			eventType, err := types.ParseCloudEventsType(response.Type())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if eventType.Action != types.CallResponseAction {
+				t.Errorf("expected %s, but got %s", types.CallResponseAction, eventType.Action)
+			}
+		})
+	}
+}
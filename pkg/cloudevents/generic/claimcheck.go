@@ -0,0 +1,120 @@
+package generic
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// claimCheck offloads the event data that is larger than a threshold to an object store before the event is sent,
+// and fetches the offloaded data back after the event is received.
+type claimCheck struct {
+	store      options.ObjectStore
+	threshold  int
+	signingKey []byte
+}
+
+func newClaimCheck(opts *options.ClaimCheck) (*claimCheck, error) {
+	if opts.Store == nil {
+		return nil, fmt.Errorf("the object store of the claim-check is required")
+	}
+
+	if len(opts.SigningKey) == 0 {
+		return nil, fmt.Errorf("the signing key of the claim-check is required")
+	}
+
+	return &claimCheck{
+		store:      opts.Store,
+		threshold:  opts.Threshold,
+		signingKey: opts.SigningKey,
+	}, nil
+}
+
+// checkIn uploads the data of the event to the object store if the data is larger than the threshold, the returned
+// event carries the signed reference of the data instead of the data.
+func (c *claimCheck) checkIn(ctx context.Context, evt cloudevents.Event) (cloudevents.Event, error) {
+	data := evt.Data()
+	if len(data) <= c.threshold {
+		return evt, nil
+	}
+
+	// the data of the events in the dry-run mode is not uploaded, but the events are checked with the references
+	key := fmt.Sprintf("%s/%s", evt.Source(), evt.ID())
+	if !isDryRun(ctx) {
+		if err := c.store.Put(ctx, key, data); err != nil {
+			return evt, fmt.Errorf("failed to upload the data of event %s, %v", evt.ID(), err)
+		}
+	}
+
+	digest := sha256.Sum256(data)
+	digestHex := hex.EncodeToString(digest[:])
+
+	// clone the event to keep the data of the original event
+	checkedEvt := evt.Clone()
+	checkedEvt.DataEncoded = nil
+	checkedEvt.SetExtension(types.ExtensionClaimCheckKey, key)
+	checkedEvt.SetExtension(types.ExtensionClaimCheckDigest, digestHex)
+	checkedEvt.SetExtension(types.ExtensionClaimCheckSignature, c.sign(key, digestHex))
+	return checkedEvt, nil
+}
+
+// checkOut fetches the offloaded data of a received event from the object store, the reference and the data are
+// verified with the signature and the digest. The event without a claim-check reference is returned directly.
+func (c *claimCheck) checkOut(ctx context.Context, evt cloudevents.Event) (cloudevents.Event, error) {
+	key, digest, signature, ok, err := claimCheckReference(evt)
+	if err != nil || !ok {
+		return evt, err
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(c.sign(key, digest))) {
+		return evt, fmt.Errorf("the claim-check reference of event %s has an invalid signature", evt.ID())
+	}
+
+	data, err := c.store.Get(ctx, key)
+	if err != nil {
+		return evt, fmt.Errorf("failed to download the data of event %s, %v", evt.ID(), err)
+	}
+
+	actualDigest := sha256.Sum256(data)
+	if hex.EncodeToString(actualDigest[:]) != digest {
+		return evt, fmt.Errorf("the data of event %s does not match its digest", evt.ID())
+	}
+
+	evt.DataEncoded = data
+	return evt, nil
+}
+
+func (c *claimCheck) sign(key, digest string) string {
+	mac := hmac.New(sha256.New, c.signingKey)
+	mac.Write([]byte(key + "/" + digest))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// claimCheckReference returns the claim-check reference of an event, it returns false if the event does not carry
+// one.
+func claimCheckReference(evt cloudevents.Event) (key, digest, signature string, ok bool, err error) {
+	extensions := evt.Extensions()
+	if _, found := extensions[types.ExtensionClaimCheckKey]; !found {
+		return "", "", "", false, nil
+	}
+
+	values := []string{}
+	for _, name := range []string{
+		types.ExtensionClaimCheckKey, types.ExtensionClaimCheckDigest, types.ExtensionClaimCheckSignature} {
+		value, err := cloudeventstypes.ToString(extensions[name])
+		if err != nil {
+			return "", "", "", false, fmt.Errorf("failed to get the extension %s of event %s, %v", name, evt.ID(), err)
+		}
+		values = append(values, value)
+	}
+
+	return values[0], values[1], values[2], true, nil
+}
@@ -0,0 +1,132 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type memoryObjectStore struct {
+	sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *memoryObjectStore) Put(ctx context.Context, key string, data []byte) error {
+	s.Lock()
+	defer s.Unlock()
+	s.objects[key] = data
+	return nil
+}
+
+func (s *memoryObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("object %s is not found", key)
+	}
+	return data, nil
+}
+
+func TestClaimCheck(t *testing.T) {
+	cases := []struct {
+		name        string
+		data        string
+		mutate      func(store *memoryObjectStore, evt *cloudevents.Event)
+		expectCheck bool
+		expectErr   bool
+	}{
+		{
+			name: "small data is not offloaded",
+			data: "small",
+		},
+		{
+			name:        "large data is offloaded",
+			data:        "large data",
+			expectCheck: true,
+		},
+		{
+			name:        "invalid signature",
+			data:        "large data",
+			expectCheck: true,
+			mutate: func(store *memoryObjectStore, evt *cloudevents.Event) {
+				evt.SetExtension(types.ExtensionClaimCheckKey, "other")
+			},
+			expectErr: true,
+		},
+		{
+			name:        "tampered data",
+			data:        "large data",
+			expectCheck: true,
+			mutate: func(store *memoryObjectStore, evt *cloudevents.Event) {
+				for key := range store.objects {
+					store.objects[key] = []byte("tampered data")
+				}
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store := &memoryObjectStore{objects: map[string][]byte{}}
+			check, err := newClaimCheck(&options.ClaimCheck{Store: store, Threshold: 5, SigningKey: []byte("key")})
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			evt := cloudevents.NewEvent()
+			evt.SetID("test")
+			evt.SetSource("source1")
+			evt.SetType("test")
+			if err := evt.SetData(cloudevents.TextPlain, []byte(c.data)); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			checkedEvt, err := check.checkIn(context.TODO(), evt)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if string(evt.Data()) != c.data {
+				t.Errorf("expected the original event is not changed, but got %s", evt.Data())
+			}
+
+			_, _, _, checked, err := claimCheckReference(checkedEvt)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if checked != c.expectCheck {
+				t.Errorf("expected %v, but got %v", c.expectCheck, checked)
+			}
+			if checked && len(checkedEvt.Data()) != 0 {
+				t.Errorf("expected the data is offloaded, but got %s", checkedEvt.Data())
+			}
+
+			if c.mutate != nil {
+				c.mutate(store, &checkedEvt)
+			}
+
+			receivedEvt, err := check.checkOut(context.TODO(), checkedEvt)
+			if c.expectErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if string(receivedEvt.Data()) != c.data {
+				t.Errorf("expected %s, but got %s", c.data, receivedEvt.Data())
+			}
+		})
+	}
+}
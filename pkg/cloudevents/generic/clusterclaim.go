@@ -0,0 +1,121 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// clusterClaim holds the claim of the cluster of an agent, the claim is renewed periodically, and the agent is a
+// standby while the claim is held by another agent.
+type clusterClaim struct {
+	options.ClusterClaim
+	clusterName string
+	agentID     string
+	claimed     atomic.Bool
+}
+
+func newClusterClaim(claim *options.ClusterClaim, clusterName, agentID string) *clusterClaim {
+	c := &clusterClaim{ClusterClaim: *claim, clusterName: clusterName, agentID: agentID}
+	if c.RenewInterval <= 0 {
+		c.RenewInterval = options.DefaultClusterClaimRenewInterval
+	}
+	return c
+}
+
+// acquire claims the cluster when the agent is started, it returns ErrClusterClaimed if the cluster is claimed by
+// another agent and the agent is not a standby.
+func (c *clusterClaim) acquire(ctx context.Context) error {
+	err := c.Claimer.Claim(ctx, c.clusterName, c.agentID)
+	if err == nil {
+		klog.Infof("the cluster %s is claimed by the agent %s", c.clusterName, c.agentID)
+		c.claimed.Store(true)
+		return nil
+	}
+
+	var claimedErr *options.ClusterClaimedError
+	if !errors.As(err, &claimedErr) {
+		return fmt.Errorf("failed to claim the cluster %s, %v", c.clusterName, err)
+	}
+
+	if !c.Standby {
+		return fmt.Errorf("%w: %v", ErrClusterClaimed, err)
+	}
+
+	klog.Infof("the agent %s is a standby, %v", c.agentID, err)
+	return nil
+}
+
+// renew renews the claim of the cluster, it returns true if the claim is acquired by the renewal. The claim is kept
+// if the claimer fails to renew it with an error other than the ClusterClaimedError, e.g. the broker is not
+// reachable, because the other agents cannot claim the cluster either.
+func (c *clusterClaim) renew(ctx context.Context) bool {
+	err := c.Claimer.Claim(ctx, c.clusterName, c.agentID)
+	if err == nil {
+		if acquired := !c.claimed.Swap(true); acquired {
+			klog.Infof("the cluster %s is claimed by the agent %s", c.clusterName, c.agentID)
+			return true
+		}
+		return false
+	}
+
+	var claimedErr *options.ClusterClaimedError
+	if !errors.As(err, &claimedErr) {
+		klog.Errorf("failed to renew the claim of the cluster %s, %v", c.clusterName, err)
+		return false
+	}
+
+	if c.claimed.Swap(false) {
+		klog.Warningf("the agent %s becomes a standby, %v", c.agentID, err)
+	}
+	return false
+}
+
+// release releases the claim of the cluster if it is held by the agent.
+func (c *clusterClaim) release(ctx context.Context) {
+	if !c.claimed.Swap(false) {
+		return
+	}
+
+	if err := c.Claimer.Release(ctx, c.clusterName, c.agentID); err != nil {
+		klog.Errorf("failed to release the claim of the cluster %s, %v", c.clusterName, err)
+	}
+}
+
+// check returns ErrClusterClaimed if the claim of the cluster is not held by the agent.
+func (c *clusterClaim) check() error {
+	if c == nil || c.claimed.Load() {
+		return nil
+	}
+
+	return fmt.Errorf("%w: the cluster %s is claimed by another agent", ErrClusterClaimed, c.clusterName)
+}
+
+// startClusterClaim renews the claim of the cluster until the client is stopped, the reconnected signal is sent once
+// the claim is acquired by a standby agent, so the agent resyncs the resources, and the claim is released after the
+// client is stopped.
+func (c *CloudEventAgentClient[T]) startClusterClaim(ctx context.Context) {
+	ticker := c.clock.NewTicker(c.clusterClaim.RenewInterval)
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.stopChan:
+				c.clusterClaim.release(context.Background())
+				return
+			case <-ticker.C():
+				if c.clusterClaim.renew(ctx) {
+					c.sendReconnectedSignal()
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,159 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type fakeClusterClaimer struct {
+	sync.Mutex
+	holders map[string]string
+}
+
+func (c *fakeClusterClaimer) Claim(ctx context.Context, clusterName, agentID string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if holder, ok := c.holders[clusterName]; ok && holder != agentID {
+		return &options.ClusterClaimedError{ClusterName: clusterName, Holder: holder}
+	}
+
+	c.holders[clusterName] = agentID
+	return nil
+}
+
+func (c *fakeClusterClaimer) Release(ctx context.Context, clusterName, agentID string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.holders[clusterName] == agentID {
+		delete(c.holders, clusterName)
+	}
+	return nil
+}
+
+func TestAgentClusterClaim(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	claimer := &fakeClusterClaimer{holders: map[string]string{}}
+	newAgent := func(agentID string, standby bool, clock *testingclock.FakeClock) (*CloudEventAgentClient[*mockResource], error) {
+		agentOptions := fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster1", agentID)
+		agentOptions.Clock = clock
+		agentOptions.ClusterClaim = &options.ClusterClaim{Claimer: claimer, RenewInterval: time.Second, Standby: standby}
+		return NewCloudEventAgentClient[*mockResource](ctx, agentOptions,
+			newMockResourceLister(), statusHash, newMockResourceCodec())
+	}
+
+	agent1, err := newAgent("agent1", false, testingclock.NewFakeClock(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// another agent cannot be started for the claimed cluster
+	if _, err := newAgent("agent2", false, testingclock.NewFakeClock(time.Now())); !errors.Is(err, ErrClusterClaimed) {
+		t.Errorf("expected %v, but got %v", ErrClusterClaimed, err)
+	}
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	standby, err := newAgent("agent2", true, fakeClock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}
+	res := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"}
+	if err := standby.Publish(ctx, eventType, res); !errors.Is(err, ErrClusterClaimed) {
+		t.Errorf("expected %v, but got %v", ErrClusterClaimed, err)
+	}
+	if err := standby.Resync(ctx, types.SourceAll); !errors.Is(err, ErrClusterClaimed) {
+		t.Errorf("expected %v, but got %v", ErrClusterClaimed, err)
+	}
+
+	// the claim is released once the agent is closed, then the standby agent acquires the claim and resyncs
+	if err := agent1.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			claimer.Lock()
+			defer claimer.Unlock()
+			_, ok := claimer.holders["cluster1"]
+			return !ok && fakeClock.HasWaiters(), nil
+		}); err != nil {
+		t.Fatalf("expected the claim is released, %v", err)
+	}
+
+	fakeClock.Step(time.Second)
+	select {
+	case <-standby.ReconnectedChan():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the reconnected signal after the claim is acquired")
+	}
+
+	if err := standby.Publish(ctx, eventType, res); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+// failingOptions fails to connect the client.
+type failingOptions struct {
+	*fake.CloudEventsFakeOptions
+}
+
+func (o *failingOptions) Client(ctx context.Context) (cloudevents.Client, error) {
+	return nil, fmt.Errorf("the broker is down")
+}
+
+func TestAgentClusterClaimReleasedOnError(t *testing.T) {
+	claimer := &fakeClusterClaimer{holders: map[string]string{}}
+	newAgentOptions := func(failing bool) *options.CloudEventsAgentOptions {
+		agentOptions := fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster1", "agent1")
+		if failing {
+			agentOptions.CloudEventsOptions = &failingOptions{
+				CloudEventsFakeOptions: agentOptions.CloudEventsOptions.(*fake.CloudEventsFakeOptions),
+			}
+		}
+		agentOptions.ClusterClaim = &options.ClusterClaim{Claimer: claimer}
+		return agentOptions
+	}
+
+	// the claim is released once the agent fails to connect
+	if _, err := NewCloudEventAgentClient[*mockResource](context.Background(), newAgentOptions(true),
+		newMockResourceLister(), statusHash, newMockResourceCodec()); err == nil {
+		t.Fatalf("expected error, but got nil")
+	}
+	if len(claimer.holders) != 0 {
+		t.Errorf("expected the claim is released, but got %v", claimer.holders)
+	}
+
+	// the agent claims the cluster again on the retry
+	agent, err := NewCloudEventAgentClient[*mockResource](context.Background(), newAgentOptions(false),
+		newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer agent.Close(context.Background())
+
+	if claimer.holders["cluster1"] != "agent1" {
+		t.Errorf("expected the cluster is claimed by agent1, but got %v", claimer.holders)
+	}
+}
@@ -0,0 +1,315 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// maxCoalesceRetries is the number of the windows that a failed update of a resource is retried in.
+const maxCoalesceRetries = 3
+
+// pendingUpdate is the latest update of a resource that is waiting for the end of the coalescing window.
+type pendingUpdate[T ResourceObject] struct {
+	ctx       context.Context
+	eventType types.CloudEventsType
+	obj       T
+	// retries is the number of the windows that the update has failed to be published in
+	retries int
+}
+
+// coalescer debounces the updates of the resources, the updates of a resource within the window are coalesced into its
+// latest update, which is published at the end of the window. It suppresses the events of the resources that are
+// changed rapidly, e.g. the status of a flapping deployment or the spec of a tight reconcile loop.
+//
+// If the hashGetter is set, the latest update is only published if its hash is changed since the last published update
+// of the resource. An update that fails to be published is retried at the end of the next window unless it is replaced
+// by a newer update, the update that cannot be published is passed to the errorHandler.
+type coalescer[T ResourceObject] struct {
+	sync.Mutex
+	window       time.Duration
+	clock        clock.WithDelayedExecution
+	hashGetter   func(T) (string, error)
+	publish      func(ctx context.Context, eventType types.CloudEventsType, obj T) error
+	errorHandler func(eventType types.CloudEventsType, resourceID string, err error)
+	// closed is true after the pending updates are flushed by closing the client, the new updates are rejected
+	closed bool
+	// pending is the latest updates of the resources that are in the window, keyed by the resource ID
+	pending map[string]*pendingUpdate[T]
+	// published is the hashes of the last published updates of the resources, keyed by the resource ID
+	published map[string]string
+	// inflight is the resources whose pending updates are being published, keyed by the resource ID, the channel is
+	// closed once the update is published, so the delete of the resource is published after it
+	inflight map[string]chan struct{}
+}
+
+func newCoalescer[T ResourceObject](
+	window time.Duration,
+	clock clock.WithDelayedExecution,
+	hashGetter func(T) (string, error),
+	publish func(ctx context.Context, eventType types.CloudEventsType, obj T) error,
+	errorHandler func(eventType types.CloudEventsType, resourceID string, err error),
+) *coalescer[T] {
+	return &coalescer[T]{
+		window:       window,
+		clock:        clock,
+		hashGetter:   hashGetter,
+		publish:      publish,
+		errorHandler: errorHandler,
+		pending:      map[string]*pendingUpdate[T]{},
+		published:    map[string]string{},
+		inflight:     map[string]chan struct{}{},
+	}
+}
+
+// add queues the update of a resource, the update replaces the pending update of the resource if there is one,
+// otherwise a window is started for the resource. The deleting resources are published immediately after the update
+// of the resource that is being published, the pending update and the last published hash of the resource are
+// forgotten. The updates are rejected with ErrClientClosed after the pending updates are flushed.
+func (c *coalescer[T]) add(ctx context.Context, eventType types.CloudEventsType, obj T) error {
+	resourceID := string(obj.GetUID())
+
+	if obj.GetDeletionTimestamp() != nil {
+		if err := c.forget(ctx, resourceID); err != nil {
+			return err
+		}
+		return c.publish(ctx, eventType, obj)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.closed {
+		return fmt.Errorf("%w: failed to publish the resource %s, the client is closing", ErrClientClosed, resourceID)
+	}
+
+	if p, ok := c.pending[resourceID]; ok {
+		p.ctx, p.eventType, p.obj, p.retries = ctx, eventType, obj, 0
+		return nil
+	}
+
+	c.pending[resourceID] = &pendingUpdate[T]{ctx: ctx, eventType: eventType, obj: obj}
+	c.clock.AfterFunc(c.window, func() {
+		c.flush(resourceID)
+	})
+	return nil
+}
+
+// flush publishes the pending update of a resource at the end of its window, the failed update is retried at the end
+// of the next window if the error is retriable.
+func (c *coalescer[T]) flush(resourceID string) {
+	c.Lock()
+	if _, publishing := c.inflight[resourceID]; publishing {
+		// the previous update of the resource is still being published, e.g. it is flushed by closing the client,
+		// publish the pending update in the next window
+		c.clock.AfterFunc(c.window, func() {
+			c.flush(resourceID)
+		})
+		c.Unlock()
+		return
+	}
+
+	p, ok := c.pending[resourceID]
+	delete(c.pending, resourceID)
+	if ok {
+		c.startPublishing(resourceID)
+	}
+	c.Unlock()
+
+	if !ok {
+		return
+	}
+	// the failed update is requeued before the resource is released, so a waiting delete forgets it
+	defer c.donePublishing(resourceID)
+
+	// the window may outlive the context of the publisher, e.g. a reconcile request
+	err := c.publishPending(context.WithoutCancel(p.ctx), resourceID, p)
+	if err == nil {
+		return
+	}
+
+	if retriable(err) && p.retries < maxCoalesceRetries && c.requeue(resourceID, p) {
+		klog.Warningf("failed to publish the resource %s, retry it in %v, %v", resourceID, c.window, err)
+		return
+	}
+
+	c.failed(p.eventType, resourceID, err)
+}
+
+// flushAll publishes all the pending updates at once and rejects the new updates, it is called when the client is
+// closed, so the pending updates are not lost, e.g. on a rolling restart. It returns the errors of the failed updates.
+func (c *coalescer[T]) flushAll(ctx context.Context) error {
+	c.Lock()
+	c.closed = true
+	pending := c.pending
+	c.pending = map[string]*pendingUpdate[T]{}
+	c.Unlock()
+
+	errs := []error{}
+	for resourceID, p := range pending {
+		if err := c.flushPending(ctx, resourceID, p); err != nil {
+			c.failed(p.eventType, resourceID, err)
+			errs = append(errs, fmt.Errorf("failed to publish the resource %s, %w", resourceID, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// flushPending publishes a pending update that is taken by flushAll after the update of the resource that is being
+// published by its window.
+func (c *coalescer[T]) flushPending(ctx context.Context, resourceID string, p *pendingUpdate[T]) error {
+	c.Lock()
+	err := c.waitPublishing(ctx, resourceID)
+	if err == nil {
+		c.startPublishing(resourceID)
+	}
+	c.Unlock()
+
+	if err != nil {
+		return err
+	}
+	defer c.donePublishing(resourceID)
+
+	return c.publishPending(ctx, resourceID, p)
+}
+
+// startPublishing marks a resource as being published, it must be called with the lock held and the resource must not
+// be being published.
+func (c *coalescer[T]) startPublishing(resourceID string) {
+	c.inflight[resourceID] = make(chan struct{})
+}
+
+// donePublishing releases a resource that is being published.
+func (c *coalescer[T]) donePublishing(resourceID string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if done, ok := c.inflight[resourceID]; ok {
+		close(done)
+		delete(c.inflight, resourceID)
+	}
+}
+
+// waitPublishing waits until a resource is not being published, it must be called with the lock held, the lock is
+// released while it waits.
+func (c *coalescer[T]) waitPublishing(ctx context.Context, resourceID string) error {
+	for {
+		done, ok := c.inflight[resourceID]
+		if !ok {
+			return nil
+		}
+
+		c.Unlock()
+		select {
+		case <-done:
+			c.Lock()
+		case <-ctx.Done():
+			c.Lock()
+			return ctx.Err()
+		}
+	}
+}
+
+// publishPending publishes a pending update if its hash is changed since the last published update of the resource.
+func (c *coalescer[T]) publishPending(ctx context.Context, resourceID string, p *pendingUpdate[T]) error {
+	c.Lock()
+	lastHash, published := c.published[resourceID]
+	c.Unlock()
+
+	hash, hashErr := "", error(nil)
+	if c.hashGetter != nil {
+		hash, hashErr = c.hashGetter(p.obj)
+		if hashErr != nil {
+			klog.Errorf("failed to get the hash of the resource %s, %v", resourceID, hashErr)
+		}
+		if hashErr == nil && published && hash == lastHash {
+			klog.V(4).Infof("the resource %s is not changed, ignore", resourceID)
+			return nil
+		}
+	}
+
+	if err := c.publish(ctx, p.eventType, p.obj); err != nil {
+		return err
+	}
+
+	if c.hashGetter != nil && hashErr == nil {
+		c.Lock()
+		c.published[resourceID] = hash
+		c.Unlock()
+	}
+	return nil
+}
+
+// requeue queues a failed update for the next window, it returns false if the update is not queued, e.g. the client
+// is closing. A newer update of the resource that is added after the failed update was taken supersedes it.
+func (c *coalescer[T]) requeue(resourceID string, p *pendingUpdate[T]) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.closed {
+		return false
+	}
+
+	if _, ok := c.pending[resourceID]; ok {
+		return true
+	}
+
+	p.retries++
+	c.pending[resourceID] = p
+	c.clock.AfterFunc(c.window, func() {
+		c.flush(resourceID)
+	})
+	return true
+}
+
+// failed reports an update that cannot be published.
+func (c *coalescer[T]) failed(eventType types.CloudEventsType, resourceID string, err error) {
+	klog.Errorf("failed to publish the resource %s, %v", resourceID, err)
+	if c.errorHandler != nil {
+		c.errorHandler(eventType, resourceID, err)
+	}
+}
+
+// forget removes the pending update and the last published hash of a resource after the update of the resource that
+// is being published is published.
+func (c *coalescer[T]) forget(ctx context.Context, resourceID string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := c.waitPublishing(ctx, resourceID); err != nil {
+		return err
+	}
+
+	delete(c.pending, resourceID)
+	delete(c.published, resourceID)
+	return nil
+}
+
+// len returns the number of the resources whose updates are waiting for the end of their windows.
+func (c *coalescer[T]) len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return len(c.pending)
+}
+
+// retriable returns true if a publish may succeed later, e.g. the broker is disconnected or does not acknowledge the
+// event in time, the updates that are rejected by the client, e.g. by the quota or the validation, are not retried.
+func retriable(err error) bool {
+	var validationErr *EventValidationError
+	switch {
+	case errors.Is(err, ErrClientClosed), errors.Is(err, ErrQuotaExceeded), errors.Is(err, ErrNotLeader),
+		errors.Is(err, ErrEncode), errors.Is(err, ErrUnsupportedType), errors.As(err, &validationErr):
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,242 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type coalescerRecorder struct {
+	sync.Mutex
+	errs      []error
+	published []string
+	failed    []string
+}
+
+func (r *coalescerRecorder) publish(ctx context.Context, eventType types.CloudEventsType, obj *mockResource) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if len(r.errs) != 0 {
+		err := r.errs[0]
+		r.errs = r.errs[1:]
+		if err != nil {
+			return err
+		}
+	}
+
+	r.published = append(r.published, fmt.Sprintf("%s/%s", obj.UID, obj.ResourceVersion))
+	return nil
+}
+
+func (r *coalescerRecorder) onError(eventType types.CloudEventsType, resourceID string, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.failed = append(r.failed, resourceID)
+}
+
+func (r *coalescerRecorder) results() ([]string, []string) {
+	r.Lock()
+	defer r.Unlock()
+
+	return append([]string{}, r.published...), append([]string{}, r.failed...)
+}
+
+// waitForCoalescer waits until the published and the failed updates are recorded.
+func waitForCoalescer(recorder *coalescerRecorder, published, failed int) {
+	_ = wait.PollUntilContextTimeout(context.TODO(), 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			p, f := recorder.results()
+			return len(p) >= published && len(f) >= failed, nil
+		})
+}
+
+func TestCoalescerRetry(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+
+	cases := []struct {
+		name              string
+		errs              []error
+		expectedPublished []string
+		expectedFailed    []string
+	}{
+		{
+			name:              "retried in the next window",
+			errs:              []error{fmt.Errorf("%w: the broker is down", ErrNotConnected)},
+			expectedPublished: []string{"test1/1"},
+			expectedFailed:    []string{},
+		},
+		{
+			name: "failed after the retries",
+			errs: []error{
+				ErrNotConnected, ErrNotConnected, ErrNotConnected, ErrNotConnected,
+			},
+			expectedPublished: []string{},
+			expectedFailed:    []string{"test1"},
+		},
+		{
+			name:              "not retriable",
+			errs:              []error{fmt.Errorf("%w: rejected", ErrQuotaExceeded)},
+			expectedPublished: []string{},
+			expectedFailed:    []string{"test1"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recorder := &coalescerRecorder{errs: c.errs}
+			coalescer := newCoalescer[*mockResource](
+				10*time.Millisecond, clock.RealClock{}, nil, recorder.publish, recorder.onError)
+
+			if err := coalescer.add(context.TODO(), eventType, &mockResource{UID: "test1", ResourceVersion: "1"}); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			waitForCoalescer(recorder, len(c.expectedPublished), len(c.expectedFailed))
+			// no more updates are published or failed
+			time.Sleep(100 * time.Millisecond)
+
+			published, failed := recorder.results()
+			if !reflect.DeepEqual(published, c.expectedPublished) {
+				t.Errorf("expected published %v, but got %v", c.expectedPublished, published)
+			}
+			if !reflect.DeepEqual(failed, c.expectedFailed) {
+				t.Errorf("expected failed %v, but got %v", c.expectedFailed, failed)
+			}
+		})
+	}
+}
+
+func TestCoalescerRetrySuperseded(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+
+	recorder := &coalescerRecorder{}
+	var coalescer *coalescer[*mockResource]
+	coalescer = newCoalescer[*mockResource](10*time.Millisecond, clock.RealClock{}, nil,
+		func(ctx context.Context, eventType types.CloudEventsType, obj *mockResource) error {
+			if obj.ResourceVersion == "1" {
+				// a newer update is added while the failed update is being published
+				if err := coalescer.add(ctx, eventType, &mockResource{UID: obj.UID, ResourceVersion: "2"}); err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				return ErrNotConnected
+			}
+			return recorder.publish(ctx, eventType, obj)
+		}, recorder.onError)
+
+	if err := coalescer.add(context.TODO(), eventType, &mockResource{UID: "test1", ResourceVersion: "1"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	waitForCoalescer(recorder, 1, 0)
+	time.Sleep(100 * time.Millisecond)
+
+	if published, failed := recorder.results(); !reflect.DeepEqual(published, []string{"test1/2"}) || len(failed) != 0 {
+		t.Errorf("expected the newer update is published only, but got %v, %v", published, failed)
+	}
+}
+
+func TestCoalescerDeleteAfterInflightUpdate(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+
+	recorder := &coalescerRecorder{}
+	publishing := make(chan struct{})
+	release := make(chan struct{})
+	coalescer := newCoalescer[*mockResource](10*time.Millisecond, clock.RealClock{}, nil,
+		func(ctx context.Context, eventType types.CloudEventsType, obj *mockResource) error {
+			if obj.DeletionTimestamp == nil {
+				// the coalesced update is in flight until it is released
+				close(publishing)
+				<-release
+			}
+			return recorder.publish(ctx, eventType, obj)
+		}, recorder.onError)
+
+	if err := coalescer.add(context.TODO(), eventType, &mockResource{UID: "test1", ResourceVersion: "1"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	select {
+	case <-publishing:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the coalesced update is not published")
+	}
+
+	// the delete waits for the in-flight update of the resource
+	deleted := make(chan error)
+	go func() {
+		deleted <- coalescer.add(context.TODO(), eventType, &mockResource{UID: "test1", ResourceVersion: "2",
+			DeletionTimestamp: &metav1.Time{Time: time.Now()}})
+	}()
+
+	select {
+	case err := <-deleted:
+		t.Fatalf("expected the delete waits for the in-flight update, but got %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-deleted; err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if published, _ := recorder.results(); !reflect.DeepEqual(published, []string{"test1/1", "test1/2"}) {
+		t.Errorf("expected the delete is published after the update, but got %v", published)
+	}
+}
+
+func TestCoalescerFlushAll(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+
+	recorder := &coalescerRecorder{errs: []error{nil, fmt.Errorf("%w: rejected", ErrQuotaExceeded)}}
+	coalescer := newCoalescer[*mockResource](time.Minute, clock.RealClock{}, nil, recorder.publish, recorder.onError)
+
+	for _, uid := range []kubetypes.UID{"test1", "test2"} {
+		if err := coalescer.add(context.TODO(), eventType, &mockResource{UID: uid, ResourceVersion: "1"}); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	// the pending updates are published before their windows end, and the error of the failed update is returned
+	if err := coalescer.flushAll(context.TODO()); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected %v, but got %v", ErrQuotaExceeded, err)
+	}
+
+	published, failed := recorder.results()
+	if len(published) != 1 || len(failed) != 1 || coalescer.len() != 0 {
+		t.Errorf("expected the pending updates are flushed, but got %v, %v", published, failed)
+	}
+
+	err := coalescer.add(context.TODO(), eventType, &mockResource{UID: "test3", ResourceVersion: "1"})
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected %v, but got %v", ErrClientClosed, err)
+	}
+}
@@ -0,0 +1,94 @@
+package generic
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// CodecRegistry holds the codecs of a source/agent client with their event data types, it dispatches the encoding and
+// decoding to the codec that is registered with the cloudevents data type of the event.
+type CodecRegistry[T ResourceObject] struct {
+	sync.RWMutex
+	codecs map[types.CloudEventsDataType]Codec[T]
+}
+
+// NewCodecRegistry returns a CodecRegistry with the given codecs. If there are multiple codecs for one event data type,
+// the last one will be used.
+func NewCodecRegistry[T ResourceObject](codecs ...Codec[T]) *CodecRegistry[T] {
+	r := &CodecRegistry[T]{codecs: make(map[types.CloudEventsDataType]Codec[T])}
+	for _, codec := range codecs {
+		r.codecs[codec.EventDataType()] = codec
+	}
+	return r
+}
+
+// Register adds a codec to the registry, it returns an error if a codec has been registered with the same event data
+// type.
+func (r *CodecRegistry[T]) Register(codec Codec[T]) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.codecs[codec.EventDataType()]; ok {
+		return fmt.Errorf("the codec for event data type %s is already registered", codec.EventDataType())
+	}
+
+	r.codecs[codec.EventDataType()] = codec
+	return nil
+}
+
+// Get returns the codec of the given event data type.
+func (r *CodecRegistry[T]) Get(eventDataType types.CloudEventsDataType) (Codec[T], bool) {
+	r.RLock()
+	defer r.RUnlock()
+
+	codec, ok := r.codecs[eventDataType]
+	return codec, ok
+}
+
+// EventDataTypes returns the registered event data types, the types are sorted by their string format.
+func (r *CodecRegistry[T]) EventDataTypes() []types.CloudEventsDataType {
+	r.RLock()
+	defer r.RUnlock()
+
+	dataTypes := make([]types.CloudEventsDataType, 0, len(r.codecs))
+	for dataType := range r.codecs {
+		dataTypes = append(dataTypes, dataType)
+	}
+
+	sort.Slice(dataTypes, func(i, j int) bool {
+		return dataTypes[i].String() < dataTypes[j].String()
+	})
+	return dataTypes
+}
+
+// Encode a resource object to a cloudevent with the codec of the given event type.
+func (r *CodecRegistry[T]) Encode(source string, eventType types.CloudEventsType, obj T) (*cloudevents.Event, error) {
+	codec, ok := r.Get(eventType.CloudEventsDataType)
+	if !ok {
+		return nil, fmt.Errorf("%w: failed to find the codec for event %s", ErrUnsupportedType, eventType.CloudEventsDataType)
+	}
+
+	return codec.Encode(source, eventType, obj)
+}
+
+// Decode a cloudevent with the codec that is registered with the data type of the event type.
+func (r *CodecRegistry[T]) Decode(evt *cloudevents.Event) (T, error) {
+	var obj T
+
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return obj, fmt.Errorf("%w: failed to parse cloud event type %s, %w", ErrUnsupportedType, evt.Type(), err)
+	}
+
+	codec, ok := r.Get(eventType.CloudEventsDataType)
+	if !ok {
+		return obj, fmt.Errorf("%w: failed to find the codec for event %s", ErrUnsupportedType, eventType.CloudEventsDataType)
+	}
+
+	return codec.Decode(evt)
+}
@@ -0,0 +1,51 @@
+package generic
+
+import (
+	"testing"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestCodecRegistry(t *testing.T) {
+	registry := NewCodecRegistry[*mockResource](newMockResourceCodec())
+
+	if err := registry.Register(newMockResourceCodec()); err == nil {
+		t.Errorf("expected error for the duplicated codec, but got nil")
+	}
+
+	dataTypes := registry.EventDataTypes()
+	if len(dataTypes) != 1 || dataTypes[0] != mockEventDataType {
+		t.Errorf("expected %v, but got %v", mockEventDataType, dataTypes)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}
+
+	evt, err := registry.Encode("test", eventType, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	res, err := registry.Decode(evt)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if res.UID != "test1" {
+		t.Errorf("expected test1, but got %v", res.UID)
+	}
+
+	evt.SetType(types.CloudEventsType{
+		CloudEventsDataType: types.CloudEventsDataType{Group: "unknown", Version: "v1", Resource: "unknowns"},
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}.String())
+	if _, err := registry.Decode(evt); err == nil {
+		t.Errorf("expected error for the unknown event data type, but got nil")
+	}
+}
@@ -0,0 +1,110 @@
+// Package conditions manipulates the conditions of the resource status that are carried by the cloud events, so the
+// sources and the agents set and read the conditions consistently.
+//
+// The conditions are aware of the generation of the resource spec that they are observed with. A condition that is
+// observed with an older generation than the existing condition of the same type is stale, e.g. it is reported by an
+// agent that has not handled the latest spec yet, so it never overwrites the existing condition.
+package conditions
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Set adds the condition to the conditions or updates the existing condition of the same type, it returns true if the
+// conditions are changed. The LastTransitionTime of the condition is only changed when its status is changed, and it
+// is set to now if the given condition does not have one. The condition is ignored if it is observed with an older
+// generation than the existing condition.
+func Set(conditions *[]metav1.Condition, condition metav1.Condition) bool {
+	if conditions == nil {
+		return false
+	}
+
+	existing := Get(*conditions, condition.Type)
+	if existing == nil {
+		if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = metav1.NewTime(time.Now())
+		}
+		*conditions = append(*conditions, condition)
+		return true
+	}
+
+	if condition.ObservedGeneration < existing.ObservedGeneration {
+		// the condition is stale
+		return false
+	}
+
+	changed := false
+	if existing.Status != condition.Status {
+		existing.Status = condition.Status
+		existing.LastTransitionTime = condition.LastTransitionTime
+		if existing.LastTransitionTime.IsZero() {
+			existing.LastTransitionTime = metav1.NewTime(time.Now())
+		}
+		changed = true
+	}
+
+	if existing.Reason != condition.Reason {
+		existing.Reason = condition.Reason
+		changed = true
+	}
+
+	if existing.Message != condition.Message {
+		existing.Message = condition.Message
+		changed = true
+	}
+
+	if existing.ObservedGeneration != condition.ObservedGeneration {
+		existing.ObservedGeneration = condition.ObservedGeneration
+		changed = true
+	}
+
+	return changed
+}
+
+// Get returns the condition of the given type, or nil if it is not found.
+func Get(conditions []metav1.Condition, conditionType string) *metav1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// Remove removes the condition of the given type from the conditions, it returns true if the condition is removed.
+func Remove(conditions *[]metav1.Condition, conditionType string) bool {
+	if conditions == nil {
+		return false
+	}
+
+	for i := range *conditions {
+		if (*conditions)[i].Type == conditionType {
+			*conditions = append((*conditions)[:i], (*conditions)[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTrue returns true if the condition of the given type is true.
+func IsTrue(conditions []metav1.Condition, conditionType string) bool {
+	condition := Get(conditions, conditionType)
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
+
+// IsFalse returns true if the condition of the given type is false.
+func IsFalse(conditions []metav1.Condition, conditionType string) bool {
+	condition := Get(conditions, conditionType)
+	return condition != nil && condition.Status == metav1.ConditionFalse
+}
+
+// IsObservedTrue returns true if the condition of the given type is true and it is observed with the given generation
+// or a later generation, e.g. to check whether the latest spec of a resource is applied by the agent.
+func IsObservedTrue(conditions []metav1.Condition, conditionType string, generation int64) bool {
+	condition := Get(conditions, conditionType)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.ObservedGeneration >= generation
+}
@@ -0,0 +1,167 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSet(t *testing.T) {
+	lastTransitionTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+
+	cases := []struct {
+		name                  string
+		conditions            []metav1.Condition
+		condition             metav1.Condition
+		expectedChanged       bool
+		expectedCondition     metav1.Condition
+		expectedTransitioned  bool
+		expectedConditionsLen int
+	}{
+		{
+			name:                  "add a condition",
+			condition:             metav1.Condition{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Applied", ObservedGeneration: 1},
+			expectedChanged:       true,
+			expectedCondition:     metav1.Condition{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Applied", ObservedGeneration: 1},
+			expectedTransitioned:  true,
+			expectedConditionsLen: 1,
+		},
+		{
+			name: "update the reason",
+			conditions: []metav1.Condition{
+				{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Applied", ObservedGeneration: 1, LastTransitionTime: lastTransitionTime},
+			},
+			condition:             metav1.Condition{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Reapplied", ObservedGeneration: 2},
+			expectedChanged:       true,
+			expectedCondition:     metav1.Condition{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Reapplied", ObservedGeneration: 2},
+			expectedConditionsLen: 1,
+		},
+		{
+			name: "update the status",
+			conditions: []metav1.Condition{
+				{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Applied", ObservedGeneration: 1, LastTransitionTime: lastTransitionTime},
+				{Type: "Available", Status: metav1.ConditionTrue, Reason: "Available", ObservedGeneration: 1},
+			},
+			condition:             metav1.Condition{Type: "Applied", Status: metav1.ConditionFalse, Reason: "Failed", ObservedGeneration: 1},
+			expectedChanged:       true,
+			expectedCondition:     metav1.Condition{Type: "Applied", Status: metav1.ConditionFalse, Reason: "Failed", ObservedGeneration: 1},
+			expectedTransitioned:  true,
+			expectedConditionsLen: 2,
+		},
+		{
+			name: "no change",
+			conditions: []metav1.Condition{
+				{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Applied", ObservedGeneration: 1, LastTransitionTime: lastTransitionTime},
+			},
+			condition:             metav1.Condition{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Applied", ObservedGeneration: 1},
+			expectedCondition:     metav1.Condition{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Applied", ObservedGeneration: 1},
+			expectedConditionsLen: 1,
+		},
+		{
+			name: "stale condition",
+			conditions: []metav1.Condition{
+				{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Applied", ObservedGeneration: 2, LastTransitionTime: lastTransitionTime},
+			},
+			condition:             metav1.Condition{Type: "Applied", Status: metav1.ConditionFalse, Reason: "Failed", ObservedGeneration: 1},
+			expectedCondition:     metav1.Condition{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Applied", ObservedGeneration: 2},
+			expectedConditionsLen: 1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			changed := Set(&c.conditions, c.condition)
+			if changed != c.expectedChanged {
+				t.Errorf("expected %v, but got %v", c.expectedChanged, changed)
+			}
+
+			if len(c.conditions) != c.expectedConditionsLen {
+				t.Errorf("expected %d conditions, but got %v", c.expectedConditionsLen, c.conditions)
+			}
+
+			actual := Get(c.conditions, c.condition.Type)
+			if actual == nil {
+				t.Fatalf("expected the condition %s, but it is not found", c.condition.Type)
+			}
+
+			if actual.Status != c.expectedCondition.Status || actual.Reason != c.expectedCondition.Reason ||
+				actual.ObservedGeneration != c.expectedCondition.ObservedGeneration {
+				t.Errorf("expected %v, but got %v", c.expectedCondition, *actual)
+			}
+
+			transitioned := !actual.LastTransitionTime.Equal(&lastTransitionTime)
+			if transitioned != c.expectedTransitioned {
+				t.Errorf("expected transitioned %v, but got %v", c.expectedTransitioned, actual.LastTransitionTime)
+			}
+		})
+	}
+}
+
+func TestIsTrue(t *testing.T) {
+	conditions := []metav1.Condition{
+		{Type: "Applied", Status: metav1.ConditionTrue, ObservedGeneration: 2},
+		{Type: "Available", Status: metav1.ConditionFalse, ObservedGeneration: 2},
+	}
+
+	cases := []struct {
+		name                 string
+		conditionType        string
+		generation           int64
+		expectedTrue         bool
+		expectedFalse        bool
+		expectedObservedTrue bool
+	}{
+		{
+			name:                 "true condition observed with the generation",
+			conditionType:        "Applied",
+			generation:           2,
+			expectedTrue:         true,
+			expectedObservedTrue: true,
+		},
+		{
+			name:          "true condition observed with an older generation",
+			conditionType: "Applied",
+			generation:    3,
+			expectedTrue:  true,
+		},
+		{
+			name:          "false condition",
+			conditionType: "Available",
+			generation:    1,
+			expectedFalse: true,
+		},
+		{
+			name:          "unknown condition",
+			conditionType: "Degraded",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := IsTrue(conditions, c.conditionType); actual != c.expectedTrue {
+				t.Errorf("expected %v, but got %v", c.expectedTrue, actual)
+			}
+			if actual := IsFalse(conditions, c.conditionType); actual != c.expectedFalse {
+				t.Errorf("expected %v, but got %v", c.expectedFalse, actual)
+			}
+			if actual := IsObservedTrue(conditions, c.conditionType, c.generation); actual != c.expectedObservedTrue {
+				t.Errorf("expected %v, but got %v", c.expectedObservedTrue, actual)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	conditions := []metav1.Condition{{Type: "Applied"}, {Type: "Available"}}
+
+	if !Remove(&conditions, "Applied") {
+		t.Errorf("expected the condition is removed")
+	}
+	if Remove(&conditions, "Applied") {
+		t.Errorf("expected the condition is not found")
+	}
+	if len(conditions) != 1 || conditions[0].Type != "Available" {
+		t.Errorf("expected %v, but got %v", []metav1.Condition{{Type: "Available"}}, conditions)
+	}
+}
@@ -0,0 +1,51 @@
+package generic
+
+import (
+	"time"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// onConnect calls the OnConnect callback after the client is connected, the reconnected is false for the first
+// connection of the client.
+func (c *baseClient) onConnect(reconnected bool) {
+	if c.connectionEventHandlers.OnConnect == nil {
+		return
+	}
+
+	event := c.connectionEvent(nil)
+	event.Reconnected = reconnected
+	c.connectionEventHandlers.OnConnect(event)
+}
+
+// onDisconnect calls the OnDisconnect callback after the client is disconnected, the error is nil if the client is
+// disconnected on purpose.
+func (c *baseClient) onDisconnect(err error) {
+	if c.connectionEventHandlers.OnDisconnect == nil {
+		return
+	}
+
+	c.connectionEventHandlers.OnDisconnect(c.connectionEvent(err))
+}
+
+// onResubscribe calls the OnResubscribe callback after the receiver is restarted on a reconnected connection, the
+// resumed is true if the subscriptions are resumed from the last received events.
+func (c *baseClient) onResubscribe(resumed bool) {
+	if c.connectionEventHandlers.OnResubscribe == nil {
+		return
+	}
+
+	event := c.connectionEvent(nil)
+	event.Reconnected = true
+	event.Resumed = resumed
+	c.connectionEventHandlers.OnResubscribe(event)
+}
+
+func (c *baseClient) connectionEvent(err error) options.ConnectionEvent {
+	event := options.ConnectionEvent{Time: time.Now(), Err: err}
+	if diagnosable, ok := c.transport().(options.DiagnosableOptions); ok {
+		event.Transport = diagnosable.Diagnostics()
+	}
+
+	return event
+}
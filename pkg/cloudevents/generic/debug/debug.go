@@ -0,0 +1,251 @@
+// Package debug serves the internal state of the cloudevents clients and the runtime profiles of the process on an
+// HTTP endpoint, it is mounted on the mux of the consumer to diagnose the stuck event processing in production.
+//
+// The handlers are installed under a prefix, e.g. /debug/cloudevents:
+//   - <prefix>/state returns the DebugState of the registered clients and the goroutine counts of the SDK components
+//     in JSON.
+//   - <prefix>/pprof/ lists the runtime profiles, <prefix>/pprof/<name> returns a profile, e.g. goroutine or heap, it
+//     is in the text format if the debug query parameter is greater than zero.
+//   - <prefix>/pprof/profile returns the CPU profile of the duration in the seconds query parameter.
+//
+// Unlike net/http/pprof, the package does not register any handler on the http.DefaultServeMux.
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+)
+
+const (
+	// sdkPackagePrefix is the prefix of the functions of the SDK on the goroutine stacks.
+	sdkPackagePrefix = "open-cluster-management.io/sdk-go/pkg/"
+
+	defaultCPUProfileDuration = 30 * time.Second
+	maxCPUProfileDuration     = 5 * time.Minute
+)
+
+// StateProvider provides the internal state of a client, it is implemented by the source, agent and observer clients
+// of the generic package.
+type StateProvider interface {
+	DebugState() generic.DebugState
+}
+
+// State is the internal state that is served by the Server.
+type State struct {
+	// Goroutines is the number of the goroutines of the process.
+	Goroutines int `json:"goroutines"`
+
+	// SDKGoroutines is the number of the goroutines of each SDK component, it is keyed by the package of the innermost
+	// SDK function on the goroutine stack, e.g. cloudevents/generic or cloudevents/generic/options/mqtt.
+	SDKGoroutines map[string]int `json:"sdkGoroutines"`
+
+	// Clients is the internal state of the registered clients, it is keyed by the client names.
+	Clients map[string]generic.DebugState `json:"clients"`
+}
+
+// Server serves the internal state of the registered clients and the runtime profiles of the process.
+type Server struct {
+	sync.RWMutex
+	clients map[string]StateProvider
+}
+
+// NewServer returns a Server without any registered client.
+func NewServer() *Server {
+	return &Server{clients: map[string]StateProvider{}}
+}
+
+// Register adds a client with the given name, the client that is registered with the same name is replaced.
+func (s *Server) Register(name string, client StateProvider) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.clients[name] = client
+}
+
+// Unregister removes the client with the given name, e.g. after the client is closed.
+func (s *Server) Unregister(name string) {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.clients, name)
+}
+
+// State returns the internal state of the registered clients and the goroutine counts.
+func (s *Server) State() State {
+	s.RLock()
+	defer s.RUnlock()
+
+	state := State{
+		Goroutines:    runtime.NumGoroutine(),
+		SDKGoroutines: sdkGoroutines(),
+		Clients:       make(map[string]generic.DebugState, len(s.clients)),
+	}
+	for name, client := range s.clients {
+		state.Clients[name] = client.DebugState()
+	}
+	return state
+}
+
+// Install registers the handlers of the server on the mux under the given prefix, e.g. /debug/cloudevents.
+func (s *Server) Install(mux *http.ServeMux, prefix string) {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	mux.HandleFunc(prefix+"/state", s.serveState)
+	mux.Handle(prefix+"/pprof/", http.StripPrefix(prefix+"/pprof/", http.HandlerFunc(serveProfile)))
+}
+
+func (s *Server) serveState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := json.MarshalIndent(s.State(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		klog.Errorf("failed to write the debug state, %v", err)
+	}
+}
+
+// serveProfile serves the runtime profiles, the path of the request is the name of a profile.
+func serveProfile(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Path
+	switch name {
+	case "":
+		serveProfileIndex(w)
+		return
+	case "profile":
+		serveCPUProfile(w, r)
+		return
+	}
+
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		http.Error(w, fmt.Sprintf("unknown profile %q", name), http.StatusNotFound)
+		return
+	}
+
+	debug, _ := strconv.Atoi(r.URL.Query().Get("debug"))
+	if debug > 0 {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	}
+
+	if err := profile.WriteTo(w, debug); err != nil {
+		klog.Errorf("failed to write the profile %s, %v", name, err)
+	}
+}
+
+func serveProfileIndex(w http.ResponseWriter) {
+	profiles := pprof.Profiles()
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name() < profiles[j].Name()
+	})
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, profile := range profiles {
+		fmt.Fprintf(w, "%d\t%s\n", profile.Count(), profile.Name())
+	}
+	fmt.Fprintf(w, "-\tprofile\n")
+}
+
+func serveCPUProfile(w http.ResponseWriter, r *http.Request) {
+	duration := defaultCPUProfileDuration
+	if seconds := r.URL.Query().Get("seconds"); len(seconds) != 0 {
+		s, err := strconv.Atoi(seconds)
+		if err != nil || s <= 0 {
+			http.Error(w, fmt.Sprintf("invalid seconds %q", seconds), http.StatusBadRequest)
+			return
+		}
+		duration = time.Duration(s) * time.Second
+	}
+
+	if duration > maxCPUProfileDuration {
+		http.Error(w, fmt.Sprintf("the duration can not be longer than %v", maxCPUProfileDuration),
+			http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="profile"`)
+	if err := pprof.StartCPUProfile(w); err != nil {
+		// the CPU profiling is already enabled, the headers are not written yet
+		w.Header().Del("Content-Disposition")
+		http.Error(w, fmt.Sprintf("failed to start the CPU profile, %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	select {
+	case <-time.After(duration):
+	case <-r.Context().Done():
+	}
+	pprof.StopCPUProfile()
+}
+
+// sdkGoroutines counts the goroutines of the SDK components, the component of a goroutine is the package of the
+// innermost SDK function on its stack, the goroutines without SDK functions are not counted.
+func sdkGoroutines() map[string]int {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	counts := map[string]int{}
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		for _, line := range strings.Split(stack, "\n") {
+			// the function lines are followed by the file lines that are indented, and the "created by" lines are
+			// not the frames of the goroutine
+			if component, ok := sdkComponent(line); ok {
+				counts[component]++
+				break
+			}
+		}
+	}
+	return counts
+}
+
+// sdkComponent returns the package of the SDK function of a stack line, e.g. cloudevents/generic for the line
+// open-cluster-management.io/sdk-go/pkg/cloudevents/generic.(*baseClient).connect.func1().
+func sdkComponent(line string) (string, bool) {
+	function, ok := strings.CutPrefix(line, sdkPackagePrefix)
+	if !ok {
+		return "", false
+	}
+
+	// the package path ends at the first dot after its last slash, the slashes of the arguments are ignored
+	name := function
+	if i := strings.Index(name, "("); i >= 0 {
+		name = name[:i]
+	}
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", false
+	}
+	return name[:slash+1+dot], true
+}
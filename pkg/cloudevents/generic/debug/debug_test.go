@@ -0,0 +1,151 @@
+package debug
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+type fakeClient struct {
+	state generic.DebugState
+}
+
+func (c *fakeClient) DebugState() generic.DebugState {
+	return c.state
+}
+
+func TestSDKComponent(t *testing.T) {
+	cases := []struct {
+		name              string
+		line              string
+		expectedComponent string
+		expectedOK        bool
+	}{
+		{
+			name:              "method",
+			line:              "open-cluster-management.io/sdk-go/pkg/cloudevents/generic.(*baseClient).connect.func1()",
+			expectedComponent: "cloudevents/generic",
+			expectedOK:        true,
+		},
+		{
+			name: "generic method",
+			line: "open-cluster-management.io/sdk-go/pkg/cloudevents/generic.(*CloudEventAgentClient[...]).receive" +
+				"(0xc000010000, {0x1a2b3c, 0xc000020000})",
+			expectedComponent: "cloudevents/generic",
+			expectedOK:        true,
+		},
+		{
+			name:              "function",
+			line:              "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt.newSessionConn(...)",
+			expectedComponent: "cloudevents/generic/options/mqtt",
+			expectedOK:        true,
+		},
+		{
+			name: "created by",
+			line: "created by open-cluster-management.io/sdk-go/pkg/cloudevents/generic.(*baseClient).connect in " +
+				"goroutine 1",
+		},
+		{
+			name: "other packages",
+			line: "github.com/eclipse/paho.golang/paho.(*Client).incoming(0xc000010000)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			component, ok := sdkComponent(c.line)
+			if ok != c.expectedOK {
+				t.Errorf("expected %v, but got %v", c.expectedOK, ok)
+			}
+			if component != c.expectedComponent {
+				t.Errorf("expected %s, but got %s", c.expectedComponent, component)
+			}
+		})
+	}
+}
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	server.Register("agent", &fakeClient{state: generic.DebugState{
+		Diagnostics: options.Diagnostics{State: options.ConnectionStateConnected},
+		Dispatcher:  &generic.DispatcherState{Workers: 2, Lanes: []int{3}, Pending: 5},
+	}})
+	server.Register("removed", &fakeClient{})
+	server.Unregister("removed")
+
+	mux := http.NewServeMux()
+	server.Install(mux, "/debug/cloudevents/")
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get("/debug/cloudevents/state")
+	if code != http.StatusOK {
+		t.Fatalf("expected %d, but got %d", http.StatusOK, code)
+	}
+
+	state := State{}
+	if err := json.Unmarshal([]byte(body), &state); err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Clients) != 1 {
+		t.Errorf("expected 1 client, but got %v", state.Clients)
+	}
+	agent := state.Clients["agent"]
+	if agent.Diagnostics.State != options.ConnectionStateConnected {
+		t.Errorf("expected %s, but got %s", options.ConnectionStateConnected, agent.Diagnostics.State)
+	}
+	if agent.Dispatcher == nil || agent.Dispatcher.Pending != 5 {
+		t.Errorf("expected 5 pending events, but got %v", agent.Dispatcher)
+	}
+	if state.Goroutines == 0 {
+		t.Errorf("expected the goroutines are counted")
+	}
+	// the test itself runs on a goroutine of this package
+	if state.SDKGoroutines["cloudevents/generic/debug"] == 0 {
+		t.Errorf("expected the goroutines of the debug package are counted, but got %v", state.SDKGoroutines)
+	}
+
+	code, body = get("/debug/cloudevents/pprof/")
+	if code != http.StatusOK {
+		t.Errorf("expected %d, but got %d", http.StatusOK, code)
+	}
+	if !strings.Contains(body, "goroutine") {
+		t.Errorf("expected the goroutine profile is listed, but got %s", body)
+	}
+
+	code, body = get("/debug/cloudevents/pprof/goroutine?debug=1")
+	if code != http.StatusOK {
+		t.Errorf("expected %d, but got %d", http.StatusOK, code)
+	}
+	if !strings.Contains(body, "TestServer") {
+		t.Errorf("expected the goroutine of the test in the profile, but got %s", body)
+	}
+
+	if code, _ := get("/debug/cloudevents/pprof/unknown"); code != http.StatusNotFound {
+		t.Errorf("expected %d, but got %d", http.StatusNotFound, code)
+	}
+
+	if code, _ := get("/debug/cloudevents/pprof/profile?seconds=0"); code != http.StatusBadRequest {
+		t.Errorf("expected %d, but got %d", http.StatusBadRequest, code)
+	}
+}
@@ -0,0 +1,124 @@
+package generic
+
+import (
+	"time"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// DebugState is a snapshot of the internal state of a client, e.g. the depths of its queues and the sizes of its caches,
+// it is used to diagnose the stuck event processing, see the debug package to serve it on an HTTP endpoint.
+type DebugState struct {
+	// Diagnostics is the diagnostics of the client.
+	Diagnostics options.Diagnostics `json:"diagnostics"`
+
+	// Dispatcher is the state of the workers that process the received events, it is nil if the events are processed
+	// on the receiving goroutine of the transport.
+	Dispatcher *DispatcherState `json:"dispatcher,omitempty"`
+
+	// ReceiveBufferSize is the maximum number of the received events that are held by the receive buffer, it is zero
+	// if the receive buffer is not enabled.
+	ReceiveBufferSize int `json:"receiveBufferSize,omitempty"`
+
+	// DedupeCacheEntries is the number of the events that are remembered by the dedupe cache.
+	DedupeCacheEntries int `json:"dedupeCacheEntries"`
+
+	// SpecCacheEntries is the number of the resource specs that are cached by an agent client.
+	SpecCacheEntries int `json:"specCacheEntries"`
+
+	// CoalescingResources is the number of the resources whose updates are waiting for the end of their coalescing
+	// windows.
+	CoalescingResources int `json:"coalescingResources"`
+
+	// Resync is the resync timers of the clusters on a source client or of the sources on an agent client, it is keyed
+	// in the same way as the Targets of the ResyncStatus.
+	Resync map[string]ResyncTimer `json:"resync,omitempty"`
+}
+
+// DispatcherState is the state of the workers that process the received events.
+type DispatcherState struct {
+	// Workers is the number of the workers.
+	Workers int `json:"workers"`
+
+	// Lanes is the number of the resources that are queued in each priority lane, from the lowest to the highest lane.
+	Lanes []int `json:"lanes"`
+
+	// Pending is the number of the received events that are waiting for the workers.
+	Pending int `json:"pending"`
+
+	// Processing is the number of the resources whose events are being processed by the workers.
+	Processing int `json:"processing"`
+}
+
+// ResyncTimer is the timing of the resync of a cluster or a source.
+type ResyncTimer struct {
+	// InProgress is true if a resync request is being sent.
+	InProgress bool `json:"inProgress"`
+
+	// LastAttemptTime is the time when the last resync request was started.
+	LastAttemptTime time.Time `json:"lastAttemptTime"`
+
+	// LastSuccessTime is the time when the last resync request was sent successfully.
+	LastSuccessTime time.Time `json:"lastSuccessTime"`
+
+	// ConsecutiveFailures is the number of the resync requests that failed in a row.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+
+	// LastError is the error of the last resync request, it is empty if the last resync request was sent successfully.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// DebugState returns a snapshot of the internal state of the client.
+func (c *baseClient) DebugState() DebugState {
+	state := DebugState{
+		Diagnostics: c.Diagnostics(),
+		Resync:      map[string]ResyncTimer{},
+	}
+
+	if c.dispatcher != nil {
+		dispatcherState := c.dispatcher.state()
+		state.Dispatcher = &dispatcherState
+	}
+	if c.receiveBuffer != nil {
+		state.ReceiveBufferSize = c.receiveBuffer.size
+	}
+	if c.dedupeCache != nil {
+		state.DedupeCacheEntries = c.dedupeCache.len()
+	}
+
+	for target, status := range c.ResyncStatus().Targets {
+		timer := ResyncTimer{
+			InProgress:          status.InProgress,
+			LastAttemptTime:     status.LastAttemptTime,
+			LastSuccessTime:     status.LastSuccessTime,
+			ConsecutiveFailures: status.ConsecutiveFailures,
+		}
+		if status.LastError != nil {
+			timer.LastError = status.LastError.Error()
+		}
+		state.Resync[target] = timer
+	}
+
+	return state
+}
+
+// DebugState returns a snapshot of the internal state of the source client, it includes the number of the resources
+// whose spec updates are being coalesced.
+func (c *CloudEventSourceClient[T]) DebugState() DebugState {
+	state := c.baseClient.DebugState()
+	if c.specCoalescer != nil {
+		state.CoalescingResources = c.specCoalescer.len()
+	}
+	return state
+}
+
+// DebugState returns a snapshot of the internal state of the agent client, it includes the number of the cached specs
+// and the number of the resources whose status updates are being coalesced.
+func (c *CloudEventAgentClient[T]) DebugState() DebugState {
+	state := c.baseClient.DebugState()
+	state.SpecCacheEntries = c.specs.len()
+	if c.statusCoalescer != nil {
+		state.CoalescingResources = c.statusCoalescer.len()
+	}
+	return state
+}
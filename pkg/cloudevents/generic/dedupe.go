@@ -0,0 +1,61 @@
+package generic
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// dedupeCache remembers the recently received events with their event ID and resource version, it is used to
+// suppress the events that are redelivered by the broker, e.g. the MQTT QoS 1 messages. The cache evicts the oldest
+// event when it is full.
+type dedupeCache struct {
+	sync.Mutex
+	size   int
+	keys   map[string]*list.Element
+	events *list.List
+}
+
+func newDedupeCache(size int) *dedupeCache {
+	return &dedupeCache{
+		size:   size,
+		keys:   map[string]*list.Element{},
+		events: list.New(),
+	}
+}
+
+// seen returns true if the event has been received, otherwise the event is recorded and false is returned.
+func (c *dedupeCache) seen(evt cloudevents.Event) bool {
+	key := evt.ID()
+	if resourceVersion, ok := evt.Extensions()[types.ExtensionResourceVersion]; ok {
+		key = fmt.Sprintf("%s/%v", key, resourceVersion)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.keys[key]; ok {
+		return true
+	}
+
+	c.keys[key] = c.events.PushFront(key)
+	if c.events.Len() > c.size {
+		oldest := c.events.Back()
+		c.events.Remove(oldest)
+		delete(c.keys, oldest.Value.(string))
+	}
+
+	return false
+}
+
+// len returns the number of the events in the cache.
+func (c *dedupeCache) len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.events.Len()
+}
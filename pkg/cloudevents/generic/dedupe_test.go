@@ -0,0 +1,41 @@
+package generic
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestDedupeCache(t *testing.T) {
+	cache := newDedupeCache(2)
+
+	if cache.seen(newDedupeEvent("1", "1")) {
+		t.Errorf("expected the first event is not seen")
+	}
+
+	if !cache.seen(newDedupeEvent("1", "1")) {
+		t.Errorf("expected the redelivered event is seen")
+	}
+
+	if cache.seen(newDedupeEvent("1", "2")) {
+		t.Errorf("expected the event with a new resource version is not seen")
+	}
+
+	// the event 1/1 is evicted
+	if cache.seen(newDedupeEvent("2", "1")) {
+		t.Errorf("expected the event with a new ID is not seen")
+	}
+
+	if cache.seen(newDedupeEvent("1", "1")) {
+		t.Errorf("expected the evicted event is not seen")
+	}
+}
+
+func newDedupeEvent(id, resourceVersion string) cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetID(id)
+	evt.SetExtension(types.ExtensionResourceVersion, resourceVersion)
+	return evt
+}
@@ -0,0 +1,145 @@
+package generic
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// connectionStatus records the connection state of a client and the time of its last connection changes and errors.
+type connectionStatus struct {
+	sync.RWMutex
+	connected            bool
+	lastConnectedTime    time.Time
+	lastDisconnectedTime time.Time
+	lastConnectErrorTime time.Time
+	lastPublishErrorTime time.Time
+	lastError            error
+	publishTimeouts      int64
+	quotaRejections      int64
+}
+
+func (s *connectionStatus) connect() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.connected = true
+	s.lastConnectedTime = time.Now()
+}
+
+// disconnect records the client is disconnected, the error is nil if the client is disconnected on purpose, e.g. the
+// client is switched to another transport.
+func (s *connectionStatus) disconnect(err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.connected = false
+	s.lastDisconnectedTime = time.Now()
+	if err != nil {
+		s.lastError = err
+	}
+}
+
+func (s *connectionStatus) connectFailed(err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.lastConnectErrorTime = time.Now()
+	s.lastError = err
+}
+
+// publishFailed records the error of a publish, the timeout is true if the event is not acknowledged in time.
+func (s *connectionStatus) publishFailed(err error, timeout bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.lastPublishErrorTime = time.Now()
+	s.lastError = err
+	if timeout {
+		s.publishTimeouts++
+	}
+}
+
+// quotaExceeded records a publish that is rejected by the quota of the client.
+func (s *connectionStatus) quotaExceeded() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.quotaRejections++
+}
+
+// Diagnostics returns a snapshot of the state of the client, e.g. the connection state, the transport diagnostics, the
+// number of the in-flight events and the payload sizes, it is used to troubleshoot the client.
+func (c *baseClient) Diagnostics() options.Diagnostics {
+	c.status.RLock()
+	diagnostics := options.Diagnostics{
+		State:                options.ConnectionStateDisconnected,
+		InFlight:             int(c.inflightCount.Load()),
+		LastConnectedTime:    c.status.lastConnectedTime,
+		LastDisconnectedTime: c.status.lastDisconnectedTime,
+		LastConnectErrorTime: c.status.lastConnectErrorTime,
+		LastPublishErrorTime: c.status.lastPublishErrorTime,
+		PublishTimeouts:      c.status.publishTimeouts,
+		QuotaRejections:      c.status.quotaRejections,
+	}
+	if c.status.connected {
+		diagnostics.State = options.ConnectionStateConnected
+	}
+	if c.status.lastError != nil {
+		diagnostics.LastError = c.status.lastError.Error()
+	}
+	c.status.RUnlock()
+
+	select {
+	case <-c.stopChan:
+		diagnostics.State = options.ConnectionStateClosed
+	default:
+	}
+
+	transport := c.transport()
+	if diagnosable, ok := transport.(options.DiagnosableOptions); ok {
+		diagnostics.Transport = diagnosable.Diagnostics()
+	}
+	if resumable, ok := transport.(options.ResumableOptions); ok {
+		diagnostics.Resumable = resumable.Resumable()
+	}
+
+	if c.receiveBuffer != nil {
+		diagnostics.Buffered = c.receiveBuffer.len()
+	}
+	if c.calls != nil {
+		diagnostics.PendingCalls = c.calls.len()
+	}
+	diagnostics.PayloadSizes = c.payloadSizes.snapshot()
+
+	return diagnostics
+}
+
+// DiagnosticsHandler returns an HTTP handler that serves the diagnostics of a client in JSON, e.g. it is registered on
+// the debug endpoint of a controller with the Diagnostics of its source/agent client, so the diagnostics can be
+// collected in the support bundles.
+func DiagnosticsHandler(diagnostics func() options.Diagnostics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		data, err := json.MarshalIndent(diagnostics(), "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(data); err != nil {
+			klog.Errorf("failed to write the diagnostics, %v", err)
+		}
+	})
+}
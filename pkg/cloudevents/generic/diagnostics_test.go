@@ -0,0 +1,139 @@
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestDiagnostics(t *testing.T) {
+	sourceOptions := fake.NewSourceOptions(fake.NewCloudEventsFakeClient(), testSourceName)
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	diagnostics := source.Diagnostics()
+	if diagnostics.State != options.ConnectionStateConnected {
+		t.Errorf("expected %s, but got %s", options.ConnectionStateConnected, diagnostics.State)
+	}
+	if diagnostics.LastConnectedTime.IsZero() {
+		t.Errorf("expected the last connected time is set")
+	}
+	if len(diagnostics.LastError) != 0 {
+		t.Errorf("expected no error, but got %s", diagnostics.LastError)
+	}
+
+	// an in-flight publish is counted until it is done
+	if !source.acquire() {
+		t.Fatalf("expected the client is not draining")
+	}
+	if diagnostics := source.Diagnostics(); diagnostics.InFlight != 1 {
+		t.Errorf("expected 1 in-flight event, but got %d", diagnostics.InFlight)
+	}
+	source.release()
+
+	if err := source.Close(context.TODO()); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if diagnostics := source.Diagnostics(); diagnostics.State != options.ConnectionStateClosed {
+		t.Errorf("expected %s, but got %s", options.ConnectionStateClosed, diagnostics.State)
+	}
+
+	// the publish fails when the client is not connected
+	client := &baseClient{
+		cloudEventsOptions:     sourceOptions.CloudEventsOptions,
+		cloudEventsRateLimiter: NewRateLimiter(options.EventRateLimit{}),
+	}
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	resource := &mockResource{UID: kubetypes.UID("1234"), ResourceVersion: "1", Namespace: "cluster1"}
+	evt, err := newMockResourceCodec().Encode(testSourceName, eventType, resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.publish(context.TODO(), *evt); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+
+	diagnostics = client.Diagnostics()
+	if diagnostics.State != options.ConnectionStateDisconnected {
+		t.Errorf("expected %s, but got %s", options.ConnectionStateDisconnected, diagnostics.State)
+	}
+	if diagnostics.InFlight != 0 {
+		t.Errorf("expected no in-flight event, but got %d", diagnostics.InFlight)
+	}
+	if diagnostics.LastPublishErrorTime.IsZero() {
+		t.Errorf("expected the last publish error time is set")
+	}
+	if len(diagnostics.LastError) == 0 {
+		t.Errorf("expected the last error is set")
+	}
+}
+
+func TestDiagnosticsHandler(t *testing.T) {
+	handler := DiagnosticsHandler(func() options.Diagnostics {
+		return options.Diagnostics{
+			State:     options.ConnectionStateConnected,
+			Transport: options.TransportDiagnostics{BrokerAddress: "127.0.0.1:1883"},
+			InFlight:  2,
+		}
+	})
+
+	cases := []struct {
+		name           string
+		method         string
+		expectedStatus int
+	}{
+		{
+			name:           "get",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "post",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(c.method, "/debug/cloudevents", nil))
+
+			if recorder.Code != c.expectedStatus {
+				t.Errorf("expected %d, but got %d", c.expectedStatus, recorder.Code)
+			}
+			if c.expectedStatus != http.StatusOK {
+				return
+			}
+
+			diagnostics := options.Diagnostics{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &diagnostics); err != nil {
+				t.Fatal(err)
+			}
+			if diagnostics.State != options.ConnectionStateConnected {
+				t.Errorf("expected %s, but got %s", options.ConnectionStateConnected, diagnostics.State)
+			}
+			if diagnostics.Transport.BrokerAddress != "127.0.0.1:1883" {
+				t.Errorf("expected 127.0.0.1:1883, but got %s", diagnostics.Transport.BrokerAddress)
+			}
+			if diagnostics.InFlight != 2 {
+				t.Errorf("expected 2, but got %d", diagnostics.InFlight)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type dispatchedEvent struct {
+	ctx     context.Context
+	evt     cloudevents.Event
+	receive receiveFn
+}
+
+// eventDispatcher dispatches the received events to a number of workers. The events are keyed by their resource IDs,
+// the events of one resource are processed one by one in the order they are received.
+//
+// The keys are queued in a number of priority lanes, the workers process the keys of a higher lane ahead of the keys
+// of the lower lanes, e.g. the delete events and the resync responses are processed ahead of the bulk spec updates
+// after an agent has been offline for a long time. A key is queued in the lane of the highest priority of its pending
+// events, so the order of the events of one resource is kept.
+type eventDispatcher struct {
+	sync.Mutex
+	cond *sync.Cond
+	// lanes are the queued keys of each priority lane, a key may be left in a lower lane after it is moved to a higher
+	// lane, it is skipped if its lane is changed.
+	lanes      [][]string
+	queued     map[string]int
+	processing map[string]bool
+	pending    map[string][]dispatchedEvent
+	shutdown   bool
+	done       func()
+	workers    int
+}
+
+// newEventDispatcher returns an eventDispatcher with the given number of workers and priority lanes, the done
+// function is called after each event is processed. The workers are stopped after the stopCh is closed.
+func newEventDispatcher(workers, lanes int, done func(), stopCh <-chan struct{}) *eventDispatcher {
+	if lanes < 1 {
+		lanes = 1
+	}
+
+	d := &eventDispatcher{
+		lanes:      make([][]string, lanes),
+		queued:     map[string]int{},
+		processing: map[string]bool{},
+		pending:    map[string][]dispatchedEvent{},
+		done:       done,
+		workers:    workers,
+	}
+	d.cond = sync.NewCond(&d.Mutex)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for d.processNextKey() {
+			}
+		}()
+	}
+
+	go func() {
+		<-stopCh
+		d.Lock()
+		d.shutdown = true
+		d.Unlock()
+		d.cond.Broadcast()
+	}()
+
+	return d
+}
+
+// dispatch queues a received event to be processed by the workers.
+func (d *eventDispatcher) dispatch(ctx context.Context, evt cloudevents.Event, receive receiveFn) {
+	key := evt.ID()
+	if resourceID, ok := evt.Extensions()[types.ExtensionResourceID]; ok {
+		key = fmt.Sprintf("%v", resourceID)
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.pending[key] = append(d.pending[key], dispatchedEvent{ctx: ctx, evt: evt, receive: receive})
+
+	// a key is not processed by multiple workers concurrently, if the key is being processed, it will be queued after
+	// it is done.
+	if d.processing[key] {
+		return
+	}
+
+	d.enqueue(key, d.lane(evt))
+}
+
+// enqueue queues the key in the given lane, the key is moved if it is queued in a lower lane. It must be called with
+// the lock held.
+func (d *eventDispatcher) enqueue(key string, lane int) {
+	if queuedLane, ok := d.queued[key]; ok && queuedLane >= lane {
+		return
+	}
+
+	d.queued[key] = lane
+	d.lanes[lane] = append(d.lanes[lane], key)
+	d.cond.Signal()
+}
+
+// lane returns the priority lane of an event, the priority that is higher than the highest lane is processed in the
+// highest lane.
+func (d *eventDispatcher) lane(evt cloudevents.Event) int {
+	if len(d.lanes) == 1 {
+		return 0
+	}
+
+	priority, err := types.GetPriority(evt)
+	if err != nil {
+		klog.Warningf("failed to get the priority of event %s, %v", evt.ID(), err)
+	}
+
+	switch {
+	case priority < 0:
+		return 0
+	case priority >= len(d.lanes):
+		return len(d.lanes) - 1
+	default:
+		return priority
+	}
+}
+
+// next returns the next key from the highest lane, it blocks until a key is queued or the dispatcher is shut down.
+// It must be called with the lock held.
+func (d *eventDispatcher) next() (string, bool) {
+	for {
+		if d.shutdown {
+			return "", false
+		}
+
+		for lane := len(d.lanes) - 1; lane >= 0; lane-- {
+			for len(d.lanes[lane]) > 0 {
+				key := d.lanes[lane][0]
+				d.lanes[lane] = d.lanes[lane][1:]
+
+				if queuedLane, ok := d.queued[key]; ok && queuedLane == lane {
+					delete(d.queued, key)
+					return key, true
+				}
+			}
+		}
+
+		d.cond.Wait()
+	}
+}
+
+func (d *eventDispatcher) processNextKey() bool {
+	d.Lock()
+	key, ok := d.next()
+	if !ok {
+		d.Unlock()
+		return false
+	}
+
+	evts := d.pending[key]
+	delete(d.pending, key)
+	d.processing[key] = true
+	d.Unlock()
+
+	for _, e := range evts {
+		e.receive(e.ctx, e.evt)
+		d.done()
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	delete(d.processing, key)
+
+	// queue the key again if there are new events of the key received during the processing
+	lane := -1
+	for _, e := range d.pending[key] {
+		if l := d.lane(e.evt); l > lane {
+			lane = l
+		}
+	}
+	if lane >= 0 {
+		d.enqueue(key, lane)
+	}
+
+	return true
+}
+
+// state returns the number of the workers, the number of the queued keys in each lane, the number of the pending
+// events and the number of the keys that are being processed.
+func (d *eventDispatcher) state() DispatcherState {
+	d.Lock()
+	defer d.Unlock()
+
+	state := DispatcherState{
+		Workers:    d.workers,
+		Lanes:      make([]int, len(d.lanes)),
+		Processing: len(d.processing),
+	}
+	// the keys that are left in the lower lanes are not counted
+	for _, lane := range d.queued {
+		state.Lanes[lane]++
+	}
+	for _, evts := range d.pending {
+		state.Pending += len(evts)
+	}
+	return state
+}
@@ -0,0 +1,184 @@
+package generic
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestEventDispatcher(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	var wg sync.WaitGroup
+	dispatcher := newEventDispatcher(2, 1, wg.Done, stopCh)
+
+	var lock sync.Mutex
+	received := map[string][]string{}
+	blocked := make(chan struct{})
+	receive := func(ctx context.Context, evt cloudevents.Event) {
+		resourceID := evt.Extensions()[types.ExtensionResourceID].(string)
+		if resourceID == "slow" {
+			<-blocked
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		received[resourceID] = append(received[resourceID], evt.ID())
+	}
+
+	events := []struct{ id, resourceID string }{
+		{"1", "slow"},
+		{"2", "test1"},
+		{"3", "test1"},
+		{"4", "test1"},
+	}
+	for _, e := range events {
+		evt := cloudevents.NewEvent()
+		evt.SetID(e.id)
+		evt.SetExtension(types.ExtensionResourceID, e.resourceID)
+
+		wg.Add(1)
+		dispatcher.dispatch(context.TODO(), evt, receive)
+	}
+
+	// the slow resource does not block the events of other resources
+	if err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(received["test1"]) == 3, nil
+		}); err != nil {
+		t.Fatalf("the events of test1 are not processed")
+	}
+
+	close(blocked)
+	wg.Wait()
+
+	lock.Lock()
+	defer lock.Unlock()
+	if ids := received["test1"]; ids[0] != "2" || ids[1] != "3" || ids[2] != "4" {
+		t.Errorf("expected the events are processed in order, but got %v", ids)
+	}
+	if len(received["slow"]) != 1 {
+		t.Errorf("expected 1 event of slow, but got %v", received["slow"])
+	}
+}
+
+func TestEventDispatcherPriorityLanes(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	var wg sync.WaitGroup
+	dispatcher := newEventDispatcher(1, 2, wg.Done, stopCh)
+
+	var lock sync.Mutex
+	received := []string{}
+	blocked := make(chan struct{})
+	receive := func(ctx context.Context, evt cloudevents.Event) {
+		if evt.ID() == "blocker" {
+			<-blocked
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		received = append(received, evt.ID())
+	}
+
+	events := []struct {
+		id, resourceID string
+		priority       int
+	}{
+		{"blocker", "blocker", types.PriorityNormal},
+		{"update1", "test1", types.PriorityNormal},
+		{"update2", "test2", types.PriorityNormal},
+		{"delete2", "test2", types.PriorityHigh},
+		{"resync", "test3", types.PriorityHigh},
+	}
+	for i, e := range events {
+		evt := cloudevents.NewEvent()
+		evt.SetID(e.id)
+		evt.SetExtension(types.ExtensionResourceID, e.resourceID)
+		evt.SetExtension(types.ExtensionPriority, e.priority)
+
+		wg.Add(1)
+		dispatcher.dispatch(context.TODO(), evt, receive)
+
+		if i == 0 {
+			// wait until the worker is blocked by the first event
+			if err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+				func(ctx context.Context) (bool, error) {
+					dispatcher.Lock()
+					defer dispatcher.Unlock()
+					return dispatcher.processing["blocker"], nil
+				}); err != nil {
+				t.Fatalf("the blocker is not processed")
+			}
+		}
+	}
+
+	close(blocked)
+	wg.Wait()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	// the events of test2 are processed in order ahead of the events of test1
+	expected := []string{"blocker", "update2", "delete2", "resync", "update1"}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected %v, but got %v", expected, received)
+	}
+}
+
+func TestEventDispatcherState(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	blocked := make(chan struct{})
+	dispatcher := newEventDispatcher(1, 2, func() {}, stopCh)
+	receive := func(ctx context.Context, evt cloudevents.Event) {
+		<-blocked
+	}
+
+	dispatch := func(id, resourceID string) {
+		evt := cloudevents.NewEvent()
+		evt.SetID(id)
+		evt.SetExtension(types.ExtensionResourceID, resourceID)
+		dispatcher.dispatch(context.TODO(), evt, receive)
+	}
+
+	// the worker is blocked by the first event of test1
+	dispatch("1", "test1")
+	if err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			return dispatcher.state().Processing == 1, nil
+		}); err != nil {
+		t.Fatalf("the event of test1 is not processed")
+	}
+
+	// the event of test1 is pending until the first event is processed, and the event of test2 is queued
+	dispatch("2", "test1")
+	dispatch("3", "test2")
+
+	expected := DispatcherState{Workers: 1, Lanes: []int{1, 0}, Pending: 2, Processing: 1}
+	if state := dispatcher.state(); !reflect.DeepEqual(expected, state) {
+		t.Errorf("expected %v, but got %v", expected, state)
+	}
+
+	close(blocked)
+	if err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			state := dispatcher.state()
+			return state.Pending == 0 && state.Processing == 0, nil
+		}); err != nil {
+		t.Errorf("the events are not processed, %v", dispatcher.state())
+	}
+}
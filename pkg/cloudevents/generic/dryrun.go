@@ -0,0 +1,20 @@
+package generic
+
+import "context"
+
+type dryRunKey struct{}
+
+// WithDryRun returns back a new context that publishes the events in the dry-run mode, the events are encoded, enriched
+// by the extension hook, checked with the claim-check threshold, validated with the size limit and the required
+// extensions, and routed to the topics of the transport as usual, but they are not sent. The publishes in the dry-run
+// mode do not consume the rate limiter and the publish quota, and do not upload the data to the claim-check store, so
+// they can be used in CI to check the resources against the limits of the protocols without a broker.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// isDryRun returns true if the events are published in the dry-run mode with the given context.
+func isDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
@@ -0,0 +1,91 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestPublishDryRun(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+
+	enriched := 0
+	observed := 0
+	store := &memoryObjectStore{objects: map[string][]byte{}}
+	fakeClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	sourceOptions.MaxEventSize = 2048
+	sourceOptions.Quota = options.SourceQuota{MaxPublishQPS: 0.001, MaxPublishBurst: 1}
+	sourceOptions.ClaimCheck = &options.ClaimCheck{Store: store, Threshold: 1024, SigningKey: []byte("key")}
+	sourceOptions.EventExtensionHook = options.EventExtensionHook{
+		Enrich: func(ctx context.Context, evt cloudevents.Event) (map[string]any, error) {
+			enriched++
+			return nil, nil
+		},
+	}
+	sourceOptions.PayloadSizeObserver = func(direction options.AuditDirection, evt cloudevents.Event, size int) {
+		observed++
+	}
+
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	dryRunCtx := WithDryRun(context.TODO())
+	for i := 0; i < 3; i++ {
+		// the publish quota is not consumed by the dry-run publishes
+		if err := source.Publish(dryRunCtx, eventType, &mockResource{
+			UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"}); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	// the large data is checked with the claim-check reference, but it is not uploaded
+	if err := source.Publish(dryRunCtx, eventType, &mockResource{
+		UID: kubetypes.UID("test2"), ResourceVersion: "1", Status: strings.Repeat("a", 4096), Namespace: "cluster1"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the event without the cluster name is rejected by the validation
+	err = source.Publish(dryRunCtx, eventType, &mockResource{UID: kubetypes.UID("test3"), ResourceVersion: "1"})
+	var validationErr *EventValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("expected validation error, but got %v", err)
+	}
+
+	if len(fakeClient.GetSentEvents()) != 0 {
+		t.Errorf("expected no sent events, but got %v", fakeClient.GetSentEvents())
+	}
+	if len(store.objects) != 0 {
+		t.Errorf("expected no uploaded data, but got %d", len(store.objects))
+	}
+	if enriched != 5 {
+		t.Errorf("expected the extension hook is invoked 5 times, but got %d", enriched)
+	}
+	if observed != 0 {
+		t.Errorf("expected the payload sizes are not observed, but got %d", observed)
+	}
+
+	// the event is sent without the dry-run mode
+	if err := source.Publish(context.TODO(), eventType, &mockResource{
+		UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(fakeClient.GetSentEvents()) != 1 {
+		t.Errorf("expected one sent event, but got %v", fakeClient.GetSentEvents())
+	}
+}
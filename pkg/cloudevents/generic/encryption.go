@@ -0,0 +1,173 @@
+package generic
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const encryptedDataContentType = "application/octet-stream"
+
+// KeyProvider provides the AES keys to encrypt and decrypt the event data, the key must be 16, 24 or 32 bytes to
+// select AES-128, AES-192 or AES-256.
+type KeyProvider interface {
+	// EncryptionKey returns the key and its ID to encrypt the data of the given event, e.g. a provider can return a
+	// key for each cluster with the `clustername` extension of the event.
+	EncryptionKey(evt *cloudevents.Event) (keyID string, key []byte, err error)
+
+	// DecryptionKey returns the key of the given key ID.
+	DecryptionKey(keyID string) ([]byte, error)
+}
+
+// StaticKeyProvider is a KeyProvider that encrypts the data of all events with one key, it can decrypt the data with
+// the previous keys to rotate the key.
+type StaticKeyProvider struct {
+	keyID string
+	keys  map[string][]byte
+}
+
+var _ KeyProvider = &StaticKeyProvider{}
+
+// NewStaticKeyProvider returns a StaticKeyProvider that encrypts the data with the key of the given key ID, the keys
+// contain all the keys that can be used to decrypt the data.
+func NewStaticKeyProvider(keyID string, keys map[string][]byte) (*StaticKeyProvider, error) {
+	if _, ok := keys[keyID]; !ok {
+		return nil, fmt.Errorf("the key %s is not found", keyID)
+	}
+
+	return &StaticKeyProvider{keyID: keyID, keys: keys}, nil
+}
+
+func (p *StaticKeyProvider) EncryptionKey(evt *cloudevents.Event) (string, []byte, error) {
+	return p.keyID, p.keys[p.keyID], nil
+}
+
+func (p *StaticKeyProvider) DecryptionKey(keyID string) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("the key %s is not found", keyID)
+	}
+	return key, nil
+}
+
+// EncryptingCodec wraps a codec to encrypt the event data with AES-GCM after the event is encoded and to decrypt the
+// event data before the event is decoded, so the data is not readable by the broker.
+type EncryptingCodec[T ResourceObject] struct {
+	codec       Codec[T]
+	keyProvider KeyProvider
+}
+
+var _ Codec[ResourceObject] = &EncryptingCodec[ResourceObject]{}
+
+// NewEncryptingCodec returns an EncryptingCodec that wraps the given codec.
+func NewEncryptingCodec[T ResourceObject](codec Codec[T], keyProvider KeyProvider) *EncryptingCodec[T] {
+	return &EncryptingCodec[T]{
+		codec:       codec,
+		keyProvider: keyProvider,
+	}
+}
+
+func (c *EncryptingCodec[T]) EventDataType() types.CloudEventsDataType {
+	return c.codec.EventDataType()
+}
+
+func (c *EncryptingCodec[T]) Encode(source string, eventType types.CloudEventsType, obj T) (*cloudevents.Event, error) {
+	evt, err := c.codec.Encode(source, eventType, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, key, err := c.keyProvider.EncryptionKey(evt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the encryption key of event %s, %v", evt.ID(), err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate the nonce, %v", err)
+	}
+
+	evt.SetExtension(types.ExtensionEncryptionKeyID, keyID)
+	evt.SetExtension(types.ExtensionEncryptedDataContentType, evt.DataContentType())
+	evt.DataEncoded = gcm.Seal(nonce, nonce, evt.Data(), additionalData(evt))
+	evt.DataBase64 = true
+	evt.SetDataContentType(encryptedDataContentType)
+	return evt, nil
+}
+
+func (c *EncryptingCodec[T]) Decode(evt *cloudevents.Event) (T, error) {
+	var obj T
+
+	extensions := evt.Extensions()
+	if _, ok := extensions[types.ExtensionEncryptionKeyID]; !ok {
+		return obj, fmt.Errorf("the data of event %s is not encrypted", evt.ID())
+	}
+
+	keyID, err := cloudeventstypes.ToString(extensions[types.ExtensionEncryptionKeyID])
+	if err != nil {
+		return obj, fmt.Errorf("failed to get the encryption key ID of event %s, %v", evt.ID(), err)
+	}
+
+	contentType, err := cloudeventstypes.ToString(extensions[types.ExtensionEncryptedDataContentType])
+	if err != nil {
+		return obj, fmt.Errorf("failed to get the data content type of event %s, %v", evt.ID(), err)
+	}
+
+	key, err := c.keyProvider.DecryptionKey(keyID)
+	if err != nil {
+		return obj, fmt.Errorf("failed to get the decryption key of event %s, %v", evt.ID(), err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return obj, err
+	}
+
+	data := evt.Data()
+	if len(data) < gcm.NonceSize() {
+		return obj, fmt.Errorf("the encrypted data of event %s is too short", evt.ID())
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], additionalData(evt))
+	if err != nil {
+		return obj, fmt.Errorf("failed to decrypt the data of event %s, %v", evt.ID(), err)
+	}
+
+	// decode a copy of the event, the received event keeps the encrypted data
+	decryptedEvt := evt.Clone()
+	decryptedEvt.DataEncoded = plaintext
+	decryptedEvt.DataBase64 = false
+	decryptedEvt.SetDataContentType(contentType)
+	return c.codec.Decode(&decryptedEvt)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the cipher, %v", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the GCM, %v", err)
+	}
+
+	return gcm, nil
+}
+
+// additionalData binds the encrypted data to the event type and the resource, so the encrypted data cannot be replayed
+// in another event.
+func additionalData(evt *cloudevents.Event) []byte {
+	return []byte(fmt.Sprintf("%s/%v", evt.Type(), evt.Extensions()[types.ExtensionResourceID]))
+}
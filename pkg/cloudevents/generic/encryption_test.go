@@ -0,0 +1,68 @@
+package generic
+
+import (
+	"bytes"
+	"testing"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestEncryptingCodec(t *testing.T) {
+	keys := map[string][]byte{
+		"key1": bytes.Repeat([]byte("1"), 32),
+		"key2": bytes.Repeat([]byte("2"), 32),
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+
+	oldProvider, err := NewStaticKeyProvider("key1", keys)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	evt, err := NewEncryptingCodec[*mockResource](newMockResourceCodec(), oldProvider).Encode(
+		testSourceName, eventType, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if bytes.Contains(evt.Data(), []byte("secret")) {
+		t.Errorf("expected the data is encrypted, but got %s", evt.Data())
+	}
+
+	// the key is rotated, the data encrypted with the old key can still be decrypted
+	provider, err := NewStaticKeyProvider("key2", keys)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	codec := NewEncryptingCodec[*mockResource](newMockResourceCodec(), provider)
+
+	res, err := codec.Decode(evt)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if res.UID != "test1" || res.Status != "secret" {
+		t.Errorf("expected the decrypted resource, but got %v", res)
+	}
+
+	// the encrypted data cannot be replayed for another resource
+	replayedEvt := evt.Clone()
+	replayedEvt.SetExtension(types.ExtensionResourceID, "test2")
+	if _, err := codec.Decode(&replayedEvt); err == nil {
+		t.Errorf("expected error for the replayed data, but got nil")
+	}
+
+	unknownProvider, err := NewStaticKeyProvider("key3", map[string][]byte{"key3": bytes.Repeat([]byte("3"), 32)})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := NewEncryptingCodec[*mockResource](newMockResourceCodec(), unknownProvider).Decode(evt); err == nil {
+		t.Errorf("expected error for the unknown key, but got nil")
+	}
+}
@@ -0,0 +1,190 @@
+package generic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+const (
+	DefaultRetryBackoff = 100 * time.Millisecond
+
+	maxRetryBackoff = 5 * time.Minute
+)
+
+// handlerRetry is a failed handler that is requeued to retry.
+type handlerRetry struct {
+	evt       cloudevents.Event
+	handle    func() error
+	succeeded func()
+}
+
+// handlerInvoker invokes the resource handlers and handles their errors with a HandlerErrorPolicy.
+type handlerInvoker struct {
+	policy options.HandlerErrorPolicy
+	clock  clock.WithTickerAndDelayedExecution
+	queue  workqueue.RateLimitingInterface
+
+	// retries keeps the latest requeued retry of each key, a retry is dropped once a newer event of the same key is
+	// invoked. The lock only guards the retries, it is not held while a retry is handled, the result of a retry that
+	// is superseded while it is being handled is discarded, so it is neither requeued nor reported.
+	lock    sync.Mutex
+	retries map[string]*handlerRetry
+}
+
+func newHandlerInvoker(
+	policy options.HandlerErrorPolicy, clock clock.WithTickerAndDelayedExecution, stopCh <-chan struct{}) *handlerInvoker {
+	if policy.RetryBackoff <= 0 {
+		policy.RetryBackoff = DefaultRetryBackoff
+	}
+
+	invoker := &handlerInvoker{policy: policy, clock: clock, retries: map[string]*handlerRetry{}}
+
+	if policy.Requeue && policy.MaxRetries > 0 {
+		invoker.queue = workqueue.NewRateLimitingQueueWithConfig(
+			workqueue.NewItemExponentialFailureRateLimiter(policy.RetryBackoff, maxRetryBackoff),
+			workqueue.RateLimitingQueueConfig{Clock: clock})
+
+		go func() {
+			<-stopCh
+			invoker.queue.ShutDown()
+		}()
+
+		go func() {
+			for invoker.processNextRetry() {
+			}
+		}()
+	}
+
+	return invoker
+}
+
+// invoke calls the handle function of a received event, if the function returns an error, it will be retried with
+// the policy. The key identifies the resource and the handler of the event, e.g. the resource ID and the handler
+// index, the requeued retry of an older event with the same key is dropped. It returns true if the function succeeded
+// before it returns, the succeeded function is called when a requeued retry succeeds later.
+func (i *handlerInvoker) invoke(ctx context.Context, evt cloudevents.Event, key string,
+	handle func() error, succeeded func()) bool {
+	i.supersede(key)
+
+	err := handle()
+	if err == nil {
+		return true
+	}
+
+	if i.policy.MaxRetries <= 0 {
+		i.failed(evt, err)
+		return false
+	}
+
+	if i.queue != nil {
+		i.lock.Lock()
+		i.retries[key] = &handlerRetry{evt: evt, handle: handle, succeeded: succeeded}
+		i.lock.Unlock()
+
+		i.queue.AddRateLimited(key)
+		return false
+	}
+
+	backoff := wait.Backoff{
+		Duration: i.policy.RetryBackoff,
+		Factor:   2,
+		Steps:    i.policy.MaxRetries,
+		Cap:      maxRetryBackoff,
+	}
+	for retries := 0; retries < i.policy.MaxRetries; retries++ {
+		select {
+		case <-ctx.Done():
+			i.failed(evt, err)
+			return false
+		case <-i.clock.After(backoff.Step()):
+		}
+
+		if err = handle(); err == nil {
+			return true
+		}
+	}
+
+	i.failed(evt, err)
+	return false
+}
+
+// supersede drops the requeued retry of the given key.
+func (i *handlerInvoker) supersede(key string) {
+	if i.queue == nil {
+		return
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if retry, ok := i.retries[key]; ok {
+		klog.V(4).Infof("the retry of the event %s is superseded by a newer event", retry.evt.ID())
+		delete(i.retries, key)
+		i.queue.Forget(key)
+	}
+}
+
+func (i *handlerInvoker) processNextRetry() bool {
+	item, shutdown := i.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer i.queue.Done(item)
+
+	key := item.(string)
+
+	i.lock.Lock()
+	retry, ok := i.retries[key]
+	i.lock.Unlock()
+	if !ok {
+		// the retry is superseded
+		i.queue.Forget(key)
+		return true
+	}
+
+	err := retry.handle()
+
+	i.lock.Lock()
+	if i.retries[key] != retry {
+		// the retry is superseded by a newer event while it is being handled, the newer event is handled by itself
+		i.lock.Unlock()
+		return true
+	}
+
+	if err != nil && i.queue.NumRequeues(key) < i.policy.MaxRetries {
+		i.lock.Unlock()
+		i.queue.AddRateLimited(key)
+		return true
+	}
+
+	delete(i.retries, key)
+	i.lock.Unlock()
+	i.queue.Forget(key)
+
+	if err != nil {
+		i.failed(retry.evt, err)
+		return true
+	}
+
+	if retry.succeeded != nil {
+		retry.succeeded()
+	}
+	return true
+}
+func (i *handlerInvoker) failed(evt cloudevents.Event, err error) {
+	klog.Errorf("failed to handle event %s, %v", evt.ID(), err)
+
+	if i.policy.ErrorHandler != nil {
+		i.policy.ErrorHandler(evt, err)
+	}
+}
@@ -0,0 +1,222 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+func TestHandlerInvoker(t *testing.T) {
+	cases := []struct {
+		name             string
+		policy           options.HandlerErrorPolicy
+		failures         int
+		expectedAttempts int
+		expectedFailed   bool
+	}{
+		{
+			name:             "no retries",
+			failures:         1,
+			expectedAttempts: 1,
+			expectedFailed:   true,
+		},
+		{
+			name:             "retry until succeeded",
+			policy:           options.HandlerErrorPolicy{MaxRetries: 3, RetryBackoff: time.Millisecond},
+			failures:         2,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "retries are exhausted",
+			policy:           options.HandlerErrorPolicy{MaxRetries: 2, RetryBackoff: time.Millisecond},
+			failures:         5,
+			expectedAttempts: 3,
+			expectedFailed:   true,
+		},
+		{
+			name:             "requeue until succeeded",
+			policy:           options.HandlerErrorPolicy{MaxRetries: 3, RetryBackoff: time.Millisecond, Requeue: true},
+			failures:         2,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "requeue retries are exhausted",
+			policy:           options.HandlerErrorPolicy{MaxRetries: 2, RetryBackoff: time.Millisecond, Requeue: true},
+			failures:         5,
+			expectedAttempts: 3,
+			expectedFailed:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			done := make(chan bool, 1)
+			c.policy.ErrorHandler = func(evt cloudevents.Event, err error) {
+				done <- true
+			}
+
+			attempts := make(chan int, 10)
+			count := 0
+			invoker := newHandlerInvoker(c.policy, clock.RealClock{}, stopCh)
+			invoker.invoke(context.TODO(), cloudevents.NewEvent(), "test", func() error {
+				count++
+				attempts <- count
+				if count <= c.failures {
+					return fmt.Errorf("failed")
+				}
+
+				done <- false
+				return nil
+			}, nil)
+
+			select {
+			case failed := <-done:
+				if failed != c.expectedFailed {
+					t.Errorf("expected failed %v, but got %v", c.expectedFailed, failed)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("the handler is not finished")
+			}
+
+			if len(attempts) != c.expectedAttempts {
+				t.Errorf("expected %d attempts, but got %d", c.expectedAttempts, len(attempts))
+			}
+		})
+	}
+}
+
+func TestHandlerInvokerRequeueSuperseded(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	failed := make(chan cloudevents.Event, 10)
+	policy := options.HandlerErrorPolicy{
+		MaxRetries:   3,
+		RetryBackoff: 50 * time.Millisecond,
+		Requeue:      true,
+		ErrorHandler: func(evt cloudevents.Event, err error) { failed <- evt },
+	}
+	invoker := newHandlerInvoker(policy, clock.RealClock{}, stopCh)
+
+	var lock sync.Mutex
+	handled := []string{}
+	handle := func(version string, err error) func() error {
+		return func() error {
+			lock.Lock()
+			defer lock.Unlock()
+			handled = append(handled, version)
+			return err
+		}
+	}
+
+	// the failed event of version 1 is requeued, then it is superseded by version 2 of the same resource
+	if invoker.invoke(context.TODO(), cloudevents.NewEvent(), "test1/0", handle("1", fmt.Errorf("failed")), nil) {
+		t.Errorf("expected the event is not handled")
+	}
+	if !invoker.invoke(context.TODO(), cloudevents.NewEvent(), "test1/0", handle("2", nil), nil) {
+		t.Errorf("expected the event is handled")
+	}
+
+	// the failed event of the other resource is retried until it succeeds
+	attempts := 0
+	succeeded := make(chan struct{})
+	if invoker.invoke(context.TODO(), cloudevents.NewEvent(), "test2/0", func() error {
+		attempts++
+		if attempts < 2 {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	}, func() { close(succeeded) }) {
+		t.Errorf("expected the event is not handled")
+	}
+
+	select {
+	case <-succeeded:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the retry is not succeeded")
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	if !reflect.DeepEqual(handled, []string{"1", "2"}) {
+		t.Errorf("expected the superseded retry is dropped, but got %v", handled)
+	}
+	if len(failed) != 0 {
+		t.Errorf("expected no failed events, but got %d", len(failed))
+	}
+}
+
+func TestHandlerInvokerRetryNotBlocking(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	failed := make(chan cloudevents.Event, 10)
+	policy := options.HandlerErrorPolicy{
+		MaxRetries:   3,
+		RetryBackoff: 10 * time.Millisecond,
+		Requeue:      true,
+		ErrorHandler: func(evt cloudevents.Event, err error) { failed <- evt },
+	}
+	invoker := newHandlerInvoker(policy, clock.RealClock{}, stopCh)
+
+	// the retry of the event blocks until it is released
+	retrying := make(chan struct{})
+	release := make(chan struct{})
+	attempts := 0
+	if invoker.invoke(context.TODO(), cloudevents.NewEvent(), "test1/0", func() error {
+		attempts++
+		if attempts > 1 {
+			close(retrying)
+			<-release
+		}
+		return fmt.Errorf("failed")
+	}, nil) {
+		t.Errorf("expected the event is not handled")
+	}
+
+	select {
+	case <-retrying:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the event is not retried")
+	}
+
+	// the events of the other resources and the newer event of the same resource are handled while the retry blocks
+	invoked := make(chan struct{})
+	go func() {
+		defer close(invoked)
+		if !invoker.invoke(context.TODO(), cloudevents.NewEvent(), "test2/0", func() error { return nil }, nil) {
+			t.Errorf("expected the event is handled")
+		}
+		if !invoker.invoke(context.TODO(), cloudevents.NewEvent(), "test1/0", func() error { return nil }, nil) {
+			t.Errorf("expected the event is handled")
+		}
+	}()
+
+	select {
+	case <-invoked:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the events are blocked by the retry")
+	}
+
+	// the superseded retry is neither requeued nor reported after it returns
+	close(release)
+	time.Sleep(200 * time.Millisecond)
+	if attempts != 2 {
+		t.Errorf("expected the superseded retry is not requeued, but got %d attempts", attempts)
+	}
+	if len(failed) != 0 {
+		t.Errorf("expected no failed events, but got %d", len(failed))
+	}
+}
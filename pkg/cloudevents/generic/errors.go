@@ -0,0 +1,81 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// The errors that are returned by the source/agent clients wrap one of the following errors, so the callers can check
+// the errors with errors.Is instead of matching the error messages, e.g.
+//
+//	if errors.Is(err, generic.ErrNotConnected) {
+//		// retry later
+//	}
+//
+// The invalid events are rejected with an EventValidationError before they are published.
+var (
+	// ErrNotConnected is returned when an event is published before the cloudevents client is connected.
+	ErrNotConnected = errors.New("not connected")
+
+	// ErrClientClosed is returned when the client publishes, resyncs or calls after it is drained or closed.
+	ErrClientClosed = errors.New("client closed")
+
+	// ErrEncode is returned when a resource object or a resync request cannot be encoded to an event.
+	ErrEncode = errors.New("encode error")
+
+	// ErrUnsupportedType is returned when the event type is not supported by the client, e.g. the codec of the event
+	// data type is not registered.
+	ErrUnsupportedType = errors.New("unsupported type")
+
+	// ErrPublishTimeout is returned when an event is not published before the context deadline, e.g. the client is
+	// throttled or the transport does not respond in time.
+	ErrPublishTimeout = errors.New("publish timeout")
+
+	// ErrResyncInProgress is returned when a resync request is sent while the previous resync request of the same
+	// cluster or source is not finished.
+	ErrResyncInProgress = errors.New("resync in progress")
+
+	// ErrRegistrationRejected is returned when the registration request of an agent is rejected by the source.
+	ErrRegistrationRejected = errors.New("registration rejected")
+
+	// ErrNotLeader is returned when a standby replica of a source publishes the resources or resyncs the clusters.
+	ErrNotLeader = errors.New("not leader")
+
+	// ErrQuotaExceeded is returned when a source publishes a resource over its quota, the error also wraps the
+	// options.QuotaExceededError of the resource.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+
+	// ErrClusterClaimed is returned when an agent is started or publishes the resources while another live agent holds
+	// the claim of its cluster.
+	ErrClusterClaimed = errors.New("cluster claimed")
+
+	// ErrCallFailed is returned when a call request is responded with an error by the call handler of the peer.
+	ErrCallFailed = errors.New("call failed")
+
+	// ErrCallTimeout is returned when the response of a call request is not received before the call timeout.
+	ErrCallTimeout = errors.New("call timeout")
+
+	// ErrPayloadTooLarge is returned when the encoded event is larger than the MaxEventSize of the client, the error
+	// also wraps the EventValidationError of the event.
+	ErrPayloadTooLarge = errors.New("payload too large")
+)
+
+// contextError returns the error of the done context, it wraps the ErrPublishTimeout if the context deadline is
+// exceeded, so the callers can tell the timeouts from the cancellations.
+func contextError(ctx context.Context) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+
+	if isTimeout(ctx, err) {
+		return fmt.Errorf("%w: %w", ErrPublishTimeout, err)
+	}
+	return err
+}
+
+// isTimeout returns true if the error or the context is caused by the exceeded context deadline.
+func isTimeout(ctx context.Context, err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
@@ -0,0 +1,157 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestPublishErrors(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	resource := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"}
+
+	cases := []struct {
+		name        string
+		publish     func(t *testing.T) error
+		expectedErr error
+	}{
+		{
+			name: "not connected",
+			publish: func(t *testing.T) error {
+				client := &baseClient{
+					cloudEventsOptions:     fake.NewSourceOptions(fake.NewCloudEventsFakeClient(), testSourceName).CloudEventsOptions,
+					cloudEventsRateLimiter: NewRateLimiter(options.EventRateLimit{}),
+				}
+				evt, err := newMockResourceCodec().Encode(testSourceName, eventType, resource)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return client.publish(context.TODO(), *evt)
+			},
+			expectedErr: ErrNotConnected,
+		},
+		{
+			name: "unsupported type",
+			publish: func(t *testing.T) error {
+				source := newTestSourceClient(t, options.EventRateLimit{})
+				unsupportedType := eventType
+				unsupportedType.CloudEventsDataType = types.CloudEventsDataType{Group: "test", Version: "v1", Resource: "unknown"}
+				return source.Publish(context.TODO(), unsupportedType, resource)
+			},
+			expectedErr: ErrUnsupportedType,
+		},
+		{
+			name: "publish timeout",
+			publish: func(t *testing.T) error {
+				source := newTestSourceClient(t, options.EventRateLimit{QPS: 0.1, Burst: 1})
+				if err := source.Publish(context.TODO(), eventType, resource); err != nil {
+					t.Fatal(err)
+				}
+
+				// the second event is throttled until the context deadline is exceeded
+				ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+				defer cancel()
+				return source.Publish(ctx, eventType, resource)
+			},
+			expectedErr: ErrPublishTimeout,
+		},
+		{
+			name: "canceled context",
+			publish: func(t *testing.T) error {
+				source := newTestSourceClient(t, options.EventRateLimit{})
+				ctx, cancel := context.WithCancel(context.TODO())
+				cancel()
+				return source.Publish(ctx, eventType, resource)
+			},
+			expectedErr: context.Canceled,
+		},
+		{
+			name: "resync with the exceeded deadline",
+			publish: func(t *testing.T) error {
+				source := newTestSourceClient(t, options.EventRateLimit{})
+				ctx, cancel := context.WithDeadline(context.TODO(), time.Now().Add(-time.Second))
+				defer cancel()
+				return source.Resync(ctx, "cluster1")
+			},
+			expectedErr: ErrPublishTimeout,
+		},
+		{
+			name: "publish after the client is closed",
+			publish: func(t *testing.T) error {
+				source := newTestSourceClient(t, options.EventRateLimit{})
+				if err := source.Close(context.TODO()); err != nil {
+					t.Fatal(err)
+				}
+				return source.Publish(context.TODO(), eventType, resource)
+			},
+			expectedErr: ErrClientClosed,
+		},
+		{
+			name: "resync after the client is closed",
+			publish: func(t *testing.T) error {
+				source := newTestSourceClient(t, options.EventRateLimit{})
+				if err := source.Close(context.TODO()); err != nil {
+					t.Fatal(err)
+				}
+				return source.Resync(context.TODO(), "cluster1")
+			},
+			expectedErr: ErrClientClosed,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.publish(t); !errors.Is(err, c.expectedErr) {
+				t.Errorf("expected %v, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestResyncInProgress(t *testing.T) {
+	client := &baseClient{}
+
+	done, err := client.startResync("cluster1")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, err := client.startResync("cluster1"); !errors.Is(err, ErrResyncInProgress) {
+		t.Errorf("expected %v, but got %v", ErrResyncInProgress, err)
+	}
+
+	// the resync of another cluster is not blocked
+	anotherDone, err := client.startResync("cluster2")
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	anotherDone(nil)
+
+	done(nil)
+	if _, err := client.startResync("cluster1"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func newTestSourceClient(t *testing.T, limit options.EventRateLimit) *CloudEventSourceClient[*mockResource] {
+	sourceOptions := fake.NewSourceOptions(fake.NewCloudEventsFakeClient(), testSourceName)
+	sourceOptions.EventRateLimit = limit
+
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return source
+}
@@ -0,0 +1,67 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// enrich adds the custom extensions of the extension hook to an event that is being sent, the extensions that are
+// already set on the event cannot be overridden.
+func (c *baseClient) enrich(ctx context.Context, evt cloudevents.Event) (cloudevents.Event, error) {
+	if c.extensionHook.Enrich == nil {
+		return evt, nil
+	}
+
+	extensions, err := c.extensionHook.Enrich(ctx, evt)
+	if err != nil {
+		return evt, fmt.Errorf("failed to enrich the extensions of event %s, %v", evt.ID(), err)
+	}
+
+	if len(extensions) == 0 {
+		return evt, nil
+	}
+
+	existing := evt.Extensions()
+	enrichedEvt := evt.Clone()
+	for name, value := range extensions {
+		if _, ok := existing[name]; ok {
+			return evt, fmt.Errorf("failed to enrich event %s, the extension %s is already set", evt.ID(), name)
+		}
+
+		if err := enrichedEvt.Context.SetExtension(name, value); err != nil {
+			return evt, fmt.Errorf("failed to set the extension %s of event %s, %v", name, evt.ID(), err)
+		}
+	}
+
+	return enrichedEvt, nil
+}
+
+// stampUserAgent sets the user agent extension to an event that is being sent, the user agent that is already set on
+// the event, e.g. by a bridge that relays the event, is kept.
+func stampUserAgent(evt cloudevents.Event) cloudevents.Event {
+	if _, ok := evt.Extensions()[types.ExtensionUserAgent]; ok {
+		return evt
+	}
+
+	stampedEvt := evt.Clone()
+	stampedEvt.SetExtension(types.ExtensionUserAgent, options.UserAgent())
+	return stampedEvt
+}
+
+// extensionsReceived passes a received event to the extension hook, it returns the context to handle the event.
+func (c *baseClient) extensionsReceived(ctx context.Context, evt cloudevents.Event) context.Context {
+	if c.extensionHook.Received == nil {
+		return ctx
+	}
+
+	if receivedCtx := c.extensionHook.Received(ctx, evt); receivedCtx != nil {
+		return receivedCtx
+	}
+
+	return ctx
+}
@@ -0,0 +1,158 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type tenantKey struct{}
+
+func TestEnrich(t *testing.T) {
+	cases := []struct {
+		name               string
+		enrich             func(ctx context.Context, evt cloudevents.Event) (map[string]any, error)
+		expectedErr        bool
+		expectedExtensions map[string]string
+	}{
+		{
+			name:               "no enrich hook",
+			expectedExtensions: map[string]string{},
+		},
+		{
+			name: "add custom extensions",
+			enrich: func(ctx context.Context, evt cloudevents.Event) (map[string]any, error) {
+				return map[string]any{"tenantid": ctx.Value(tenantKey{}), "priority": 1}, nil
+			},
+			expectedExtensions: map[string]string{"tenantid": "tenant1", "priority": "1"},
+		},
+		{
+			name: "override a built-in extension",
+			enrich: func(ctx context.Context, evt cloudevents.Event) (map[string]any, error) {
+				return map[string]any{types.ExtensionResourceID: "test"}, nil
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid extension name",
+			enrich: func(ctx context.Context, evt cloudevents.Event) (map[string]any, error) {
+				return map[string]any{"Tenant-ID": "tenant1"}, nil
+			},
+			expectedErr: true,
+		},
+		{
+			name: "enrich failed",
+			enrich: func(ctx context.Context, evt cloudevents.Event) (map[string]any, error) {
+				return nil, fmt.Errorf("failed")
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &baseClient{extensionHook: options.EventExtensionHook{Enrich: c.enrich}}
+
+			evt := cloudevents.NewEvent()
+			evt.SetExtension(types.ExtensionResourceID, "1234")
+
+			ctx := context.WithValue(context.TODO(), tenantKey{}, "tenant1")
+			enrichedEvt, err := client.enrich(ctx, evt)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			for name, value := range c.expectedExtensions {
+				actual, err := cloudeventstypes.Format(enrichedEvt.Extensions()[name])
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if actual != value {
+					t.Errorf("expected %s=%s, but got %s", name, value, actual)
+				}
+			}
+
+			// the original event is not changed
+			if len(evt.Extensions()) != 1 {
+				t.Errorf("expected 1 extension, but got %v", evt.Extensions())
+			}
+		})
+	}
+}
+
+func TestPublishWithExtensionHook(t *testing.T) {
+	fakeClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	sourceOptions.EventExtensionHook = options.EventExtensionHook{
+		Enrich: func(ctx context.Context, evt cloudevents.Event) (map[string]any, error) {
+			return map[string]any{"tenantid": "tenant1"}, nil
+		},
+	}
+
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	if err := source.Publish(context.TODO(), eventType, &mockResource{
+		UID: kubetypes.UID("1234"), ResourceVersion: "2", Namespace: "cluster1"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the resync request is enriched too
+	if err := source.Resync(context.TODO(), "cluster1"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, evt := range fakeClient.GetSentEvents() {
+		if tenantID := evt.Extensions()["tenantid"]; tenantID != "tenant1" {
+			t.Errorf("expected tenant1, but got %v", tenantID)
+		}
+
+		// the user agent is stamped on all the sent events
+		if userAgent := evt.Extensions()[types.ExtensionUserAgent]; userAgent != options.UserAgent() {
+			t.Errorf("expected %s, but got %v", options.UserAgent(), userAgent)
+		}
+	}
+}
+
+func TestExtensionsReceived(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	evt.SetExtension("tenantid", "tenant1")
+
+	client := &baseClient{}
+	ctx := client.extensionsReceived(context.TODO(), evt)
+	if ctx != context.TODO() {
+		t.Errorf("expected the original context, but got %v", ctx)
+	}
+
+	client.extensionHook = options.EventExtensionHook{
+		Received: func(ctx context.Context, evt cloudevents.Event) context.Context {
+			return context.WithValue(ctx, tenantKey{}, evt.Extensions()["tenantid"])
+		},
+	}
+	ctx = client.extensionsReceived(context.TODO(), evt)
+	if tenantID := ctx.Value(tenantKey{}); tenantID != "tenant1" {
+		t.Errorf("expected tenant1, but got %v", tenantID)
+	}
+}
@@ -0,0 +1,162 @@
+// Package features manages the protocol features that are supported by the sources and the agents.
+//
+// A client advertises the features that it supports with the capabilities extension of its resync requests, and a
+// feature is only used with a peer when both the client and the peer support it. So a new protocol feature can be
+// rolled out across a fleet that runs the mixed versions of the clients, the clients that do not advertise the feature
+// keep receiving the events without it.
+package features
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// Feature is a protocol feature that a client may support.
+type Feature string
+
+const (
+	// Chunking splits a large event data into the chunks that are sent with multiple events.
+	Chunking Feature = "chunking"
+
+	// Compression compresses the event data.
+	Compression Feature = "compression"
+
+	// DeltaResync only resyncs the resources that are changed since the last resync.
+	DeltaResync Feature = "deltaresync"
+
+	// ResyncBarrier marks the end of the resync response of a source with a resync complete event, so an agent knows
+	// when its initial spec resync is completed.
+	ResyncBarrier Feature = "resyncbarrier"
+)
+
+// Set is a set of features.
+type Set map[Feature]struct{}
+
+// NewSet returns a set of the given features.
+func NewSet(features ...Feature) Set {
+	s := make(Set, len(features))
+	for _, f := range features {
+		s[f] = struct{}{}
+	}
+	return s
+}
+
+// Parse parses a comma separated list of features, the empty items are ignored.
+func Parse(value string) Set {
+	s := Set{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			s[Feature(item)] = struct{}{}
+		}
+	}
+	return s
+}
+
+// FromEvent returns the features that are advertised with the capabilities extension of the event, it is empty if the
+// event does not have the extension, e.g. the event is sent by a client that does not support any features.
+func FromEvent(evt cloudevents.Event) Set {
+	value, ok := evt.Extensions()[types.ExtensionCapabilities]
+	if !ok {
+		return Set{}
+	}
+
+	str, err := cloudeventstypes.ToString(value)
+	if err != nil {
+		return Set{}
+	}
+	return Parse(str)
+}
+
+// Has returns true if the set contains the feature.
+func (s Set) Has(f Feature) bool {
+	_, ok := s[f]
+	return ok
+}
+
+// Intersect returns the features that are in both sets.
+func (s Set) Intersect(other Set) Set {
+	result := Set{}
+	for f := range s {
+		if other.Has(f) {
+			result[f] = struct{}{}
+		}
+	}
+	return result
+}
+
+// List returns the sorted features of the set.
+func (s Set) List() []string {
+	list := make([]string, 0, len(s))
+	for f := range s {
+		list = append(list, string(f))
+	}
+	sort.Strings(list)
+	return list
+}
+
+// String returns the comma separated list of the sorted features, it is the value of the capabilities extension.
+func (s Set) String() string {
+	return strings.Join(s.List(), ",")
+}
+
+// Negotiator negotiates the features between a client and its peers, the peers are the clusters of a source or the
+// sources of an agent. The local features act as the feature gates of the client, a feature that is not enabled
+// locally is never negotiated.
+type Negotiator struct {
+	sync.RWMutex
+	local Set
+	peers map[string]Set
+}
+
+// NewNegotiator returns a negotiator with the features that are enabled on the client.
+func NewNegotiator(local Set) *Negotiator {
+	if local == nil {
+		local = Set{}
+	}
+	return &Negotiator{local: local, peers: map[string]Set{}}
+}
+
+// Local returns the features that are enabled on the client.
+func (n *Negotiator) Local() Set {
+	return n.local
+}
+
+// Observe records the features that are advertised by a peer, it replaces the previously advertised features of the
+// peer, so a peer that is downgraded stops using the features that it no longer supports.
+func (n *Negotiator) Observe(peer string, features Set) {
+	n.Lock()
+	defer n.Unlock()
+
+	n.peers[peer] = features
+}
+
+// Forget removes the advertised features of a peer.
+func (n *Negotiator) Forget(peer string) {
+	n.Lock()
+	defer n.Unlock()
+
+	delete(n.peers, peer)
+}
+
+// Negotiated returns the features that are supported by both the client and the peer, it is empty if the peer has not
+// advertised its features.
+func (n *Negotiator) Negotiated(peer string) Set {
+	n.RLock()
+	defer n.RUnlock()
+
+	return n.local.Intersect(n.peers[peer])
+}
+
+// Enabled returns true if the feature is supported by both the client and the peer.
+func (n *Negotiator) Enabled(peer string, f Feature) bool {
+	n.RLock()
+	defer n.RUnlock()
+
+	return n.local.Has(f) && n.peers[peer].Has(f)
+}
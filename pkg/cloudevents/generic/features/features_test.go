@@ -0,0 +1,88 @@
+package features
+
+import (
+	"reflect"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{
+			name:     "empty",
+			value:    "",
+			expected: []string{},
+		},
+		{
+			name:     "features",
+			value:    "deltaresync, chunking,,compression",
+			expected: []string{"chunking", "compression", "deltaresync"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := Parse(c.value).List()
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected %v, but got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFromEvent(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	if len(FromEvent(evt)) != 0 {
+		t.Errorf("expected no features, but got %v", FromEvent(evt))
+	}
+
+	evt.SetExtension(types.ExtensionCapabilities, NewSet(Compression, Chunking).String())
+	if actual := FromEvent(evt).String(); actual != "chunking,compression" {
+		t.Errorf("expected %s, but got %s", "chunking,compression", actual)
+	}
+}
+
+func TestNegotiator(t *testing.T) {
+	negotiator := NewNegotiator(NewSet(Compression, DeltaResync))
+
+	if negotiator.Enabled("cluster1", Compression) {
+		t.Errorf("expected the feature is not enabled before the peer advertises it")
+	}
+
+	negotiator.Observe("cluster1", NewSet(Compression, Chunking))
+	cases := map[Feature]bool{
+		Compression: true,
+		// the feature is not enabled locally
+		Chunking: false,
+		// the feature is not supported by the peer
+		DeltaResync: false,
+	}
+	for feature, expected := range cases {
+		if actual := negotiator.Enabled("cluster1", feature); actual != expected {
+			t.Errorf("expected %s enabled %v, but got %v", feature, expected, actual)
+		}
+	}
+
+	if actual := negotiator.Negotiated("cluster1").String(); actual != "compression" {
+		t.Errorf("expected %s, but got %s", "compression", actual)
+	}
+
+	// the peer is downgraded
+	negotiator.Observe("cluster1", Set{})
+	if negotiator.Enabled("cluster1", Compression) {
+		t.Errorf("expected the feature is disabled after the peer is downgraded")
+	}
+
+	negotiator.Observe("cluster2", NewSet(DeltaResync))
+	negotiator.Forget("cluster2")
+	if negotiator.Enabled("cluster2", DeltaResync) {
+		t.Errorf("expected the feature is disabled after the peer is forgotten")
+	}
+}
@@ -0,0 +1,98 @@
+package generic
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// GenerationGetter is implemented by the resource objects that carry the generation of their spec, e.g. the objects
+// that embed the metav1.ObjectMeta. The generation is carried by the generation extension of the spec events.
+type GenerationGetter interface {
+	GetGeneration() int64
+}
+
+// ObservedGenerationGetter is implemented by the resource objects whose status carries the generation of the spec that
+// the status is observed with. The observed generation is carried by the observedgeneration extension of the status
+// events.
+type ObservedGenerationGetter interface {
+	GetObservedGeneration() int64
+}
+
+// IsStatusCurrent returns true if the status of the resource is observed with the latest generation of its spec. The
+// status is always current if the resource does not carry the generation or the observed generation, the unstructured
+// objects carry them with the metadata.generation and the status.observedGeneration.
+func IsStatusCurrent(obj ResourceObject) bool {
+	generation, ok := getGeneration(obj)
+	if !ok {
+		return true
+	}
+
+	observedGeneration, ok := getObservedGeneration(obj)
+	if !ok {
+		return true
+	}
+
+	return observedGeneration >= generation
+}
+
+// IsStatusCurrent returns true if the received status of the resource is observed with the generation of the latest
+// spec that is published by the source, the latest spec is got from the store or the lister of the client. The status
+// is always current if the resource does not carry the generations.
+func (c *CloudEventSourceClient[T]) IsStatusCurrent(obj T) (bool, error) {
+	observedGeneration, ok := getObservedGeneration(obj)
+	if !ok {
+		return true, nil
+	}
+
+	var last T
+	var exists bool
+	if c.store != nil {
+		var err error
+		if last, exists, err = c.store.Get(string(obj.GetUID())); err != nil {
+			return false, err
+		}
+	} else {
+		objs, err := c.lister.List(types.ListOptions{ClusterName: types.ClusterAll, Source: c.sourceID})
+		if err != nil {
+			return false, err
+		}
+		last, exists = getObj(string(obj.GetUID()), objs)
+	}
+
+	if !exists {
+		return false, fmt.Errorf("the resource %s is not found", obj.GetUID())
+	}
+
+	generation, ok := getGeneration(last)
+	if !ok {
+		return true, nil
+	}
+
+	return observedGeneration >= generation, nil
+}
+
+func getGeneration(obj ResourceObject) (int64, bool) {
+	getter, ok := obj.(GenerationGetter)
+	if !ok {
+		return 0, false
+	}
+
+	generation := getter.GetGeneration()
+	return generation, generation > 0
+}
+
+func getObservedGeneration(obj ResourceObject) (int64, bool) {
+	if getter, ok := obj.(ObservedGenerationGetter); ok {
+		return getter.GetObservedGeneration(), true
+	}
+
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		observedGeneration, found, err := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+		return observedGeneration, found && err == nil
+	}
+
+	return 0, false
+}
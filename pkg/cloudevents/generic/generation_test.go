@@ -0,0 +1,136 @@
+package generic
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func newGenerationWidget(generation int64, observedGeneration *int64) *unstructured.Unstructured {
+	obj := newWidget("1")
+	obj.SetGeneration(generation)
+	if observedGeneration != nil {
+		if err := unstructured.SetNestedField(obj.Object, *observedGeneration, "status", "observedGeneration"); err != nil {
+			panic(err)
+		}
+	}
+	return obj
+}
+
+func TestIsStatusCurrent(t *testing.T) {
+	one, two := int64(1), int64(2)
+
+	cases := []struct {
+		name     string
+		obj      ResourceObject
+		expected bool
+	}{
+		{
+			name:     "status is observed with the generation",
+			obj:      newGenerationWidget(2, &two),
+			expected: true,
+		},
+		{
+			name:     "status is observed with an older generation",
+			obj:      newGenerationWidget(2, &one),
+			expected: false,
+		},
+		{
+			name:     "no observed generation",
+			obj:      newGenerationWidget(2, nil),
+			expected: true,
+		},
+		{
+			name:     "no generations",
+			obj:      &mockResource{UID: "test1", ResourceVersion: "1"},
+			expected: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := IsStatusCurrent(c.obj); actual != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUnstructuredCodecGenerations(t *testing.T) {
+	codec := NewUnstructuredCodec(testGVR)
+	one := int64(1)
+
+	specEvt, err := codec.Encode(testSourceName, types.CloudEventsType{
+		CloudEventsDataType: UnstructuredEventDataType(testGVR),
+		SubResource:         types.SubResourceSpec,
+		Action:              "update_request",
+	}, newGenerationWidget(2, nil))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if generation, err := types.GetGeneration(*specEvt); err != nil || generation != 2 {
+		t.Errorf("expected the generation 2, but got %d, %v", generation, err)
+	}
+
+	statusEvt, err := codec.Encode("cluster1-agent", types.CloudEventsType{
+		CloudEventsDataType: UnstructuredEventDataType(testGVR),
+		SubResource:         types.SubResourceStatus,
+		Action:              "update_request",
+	}, newGenerationWidget(2, &one))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if observedGeneration, err := types.GetObservedGeneration(*statusEvt); err != nil || observedGeneration != 1 {
+		t.Errorf("expected the observed generation 1, but got %d, %v", observedGeneration, err)
+	}
+}
+
+func TestSourceIsStatusCurrent(t *testing.T) {
+	source, err := NewUnstructuredClient(context.TODO(),
+		fake.NewSourceOptions(fake.NewCloudEventsFakeClient(), testSourceName), testGVR)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	source.Store().(*MemoryResourceStore[*unstructured.Unstructured]).Upsert(newGenerationWidget(3, nil))
+
+	one, three := int64(1), int64(3)
+	cases := []struct {
+		name     string
+		status   *unstructured.Unstructured
+		expected bool
+	}{
+		{
+			name:     "status of the latest spec",
+			status:   newGenerationWidget(0, &three),
+			expected: true,
+		},
+		{
+			name:     "status of an older spec",
+			status:   newGenerationWidget(0, &one),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			current, err := source.IsStatusCurrent(c.status)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if current != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, current)
+			}
+		})
+	}
+
+	unknown := newGenerationWidget(0, &one)
+	unknown.SetUID("unknown")
+	if _, err := source.IsStatusCurrent(unknown); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+}
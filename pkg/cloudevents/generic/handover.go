@@ -0,0 +1,82 @@
+package generic
+
+import (
+	"fmt"
+	"sync"
+)
+
+// handover represents a resource that is migrating from its current source to a target source.
+type handover struct {
+	from string
+	to   string
+}
+
+// handoverTracker tracks the resources that are handed over between the sources on an agent. A resource is migrating
+// once its current source hands it over to a target source, and it is owned by the target source once the target
+// source claims it, then the spec events of the previous source for the resource are discarded, so the previous source
+// cannot delete or recreate the resource after it forgets the resource.
+type handoverTracker struct {
+	sync.Mutex
+	migrating map[string]handover
+	owners    map[string]string
+}
+
+func newHandoverTracker() *handoverTracker {
+	return &handoverTracker{
+		migrating: map[string]handover{},
+		owners:    map[string]string{},
+	}
+}
+
+// mark marks the resource as migrating from the given source to the target source, an empty target cancels the
+// handover of the resource.
+func (h *handoverTracker) mark(resourceID, from, to string) {
+	h.Lock()
+	defer h.Unlock()
+
+	if to == "" {
+		delete(h.migrating, resourceID)
+		return
+	}
+
+	h.migrating[resourceID] = handover{from: from, to: to}
+}
+
+// claim transfers the ownership of a migrating resource to the claiming source, it returns an error if the resource is
+// not handed over from the given source to the claiming source.
+func (h *handoverTracker) claim(resourceID, from, to string) error {
+	h.Lock()
+	defer h.Unlock()
+
+	migrating, ok := h.migrating[resourceID]
+	if !ok {
+		return fmt.Errorf("the resource %s is not handed over", resourceID)
+	}
+
+	if migrating.from != from || migrating.to != to {
+		return fmt.Errorf("the resource %s is handed over from %s to %s instead of from %s to %s",
+			resourceID, migrating.from, migrating.to, from, to)
+	}
+
+	delete(h.migrating, resourceID)
+	h.owners[resourceID] = to
+	return nil
+}
+
+// owner returns the source that claimed the resource, it returns false if the resource is not claimed.
+func (h *handoverTracker) owner(resourceID string) (string, bool) {
+	h.Lock()
+	defer h.Unlock()
+
+	owner, ok := h.owners[resourceID]
+	return owner, ok
+}
+
+// forget removes the resource once it is deleted.
+func (h *handoverTracker) forget(resourceID string) {
+	h.Lock()
+	defer h.Unlock()
+
+	delete(h.migrating, resourceID)
+	delete(h.owners, resourceID)
+}
@@ -8,12 +8,21 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubetypes "k8s.io/apimachinery/pkg/types"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
 // ResourceHandler handles the received resource object.
 type ResourceHandler[T ResourceObject] func(action types.ResourceAction, obj T) error
 
+// EventHandler handles the received resource object with the event that the object is decoded from, e.g. to read the
+// custom extensions, the time and the source of the event without decoding the event again.
+type EventHandler[T ResourceObject] func(ctx context.Context, evt cloudevents.Event, action types.ResourceAction, obj T) error
+
+// ObserveHandler handles a resource spec or status event that is observed by an observer client, the resource object
+// is decoded from the event with the codec of its event data type and subresource.
+type ObserveHandler[T ResourceObject] func(ctx context.Context, evt cloudevents.Event, obj T) error
+
 // StatusHashGetter gets the status hash of one resource object.
 type StatusHashGetter[T ResourceObject] func(obj T) (string, error)
 
@@ -22,9 +31,10 @@ type ResourceObject interface {
 	// The source should ensure its uniqueness and consistency.
 	GetUID() kubetypes.UID
 
-	// GetResourceVersion returns the resource version of this object. The resource version is a required int64 sequence
-	// number property that must be incremented by the source whenever this resource changes.
-	// The source should guarantee its incremental nature.
+	// GetResourceVersion returns the resource version of this object. The resource version is a required property that
+	// must be changed by the source whenever this resource changes, by default, it is an int64 sequence number and the
+	// source should guarantee its incremental nature. The sources that use other versions, e.g. the semantic versions or
+	// the content hashes, should set the VersionComparator of the client options, see VersionComparator.
 	GetResourceVersion() string
 
 	// GetDeletionTimestamp returns the deletion timestamp of this object. The deletiontimestamp is an optional
@@ -52,6 +62,11 @@ type Codec[T ResourceObject] interface {
 	Decode(event *cloudevents.Event) (T, error)
 }
 
+// CloudEventsClient is the client of a source/agent. The context of each method bounds the method, the Resync,
+// ResyncResource, Publish and Call return the error of the context once it is done, the error wraps ErrPublishTimeout
+// if the deadline of the context is exceeded, and they return ErrClientClosed after the client is drained or closed.
+// The context of a Subscribe bounds the subscription rather than the call, the subscription is unsubscribed once the
+// context is done, and the client keeps receiving the events until it is closed or its own context is done.
 type CloudEventsClient[T ResourceObject] interface {
 	// Resync the resources of one source/agent by sending resync request.
 	// The second parameter is used to specify cluster name/source ID for a source/agent.
@@ -61,14 +76,47 @@ type CloudEventsClient[T ResourceObject] interface {
 	//     If setting this parameter to `types.SourceAll`, the agent will broadcast the resync request to all sources.
 	Resync(context.Context, string) error
 
+	// ResyncResource resyncs a single resource of a cluster by sending a targeted resync request, e.g. a controller
+	// detects a gap of a resource and resyncs it without resyncing all the resources of the cluster.
+	//   - A source sends the resource status resync request to the cluster with the given cluster name.
+	//   - An agent sends the resource spec resync request to all the sources, the cluster name must be its own cluster.
+	ResyncResource(ctx context.Context, clusterName, resourceID string) error
+
+	// ResyncStatus returns the resync state of the source/agent client, e.g. the time of the last successful resync and
+	// the resync progress and failures of each cluster/source.
+	ResyncStatus() options.ResyncStatus
+
+	// Diagnostics returns a snapshot of the state of the source/agent client for troubleshooting, e.g. the connection
+	// state, the transport diagnostics, the in-flight counts and the time of the last errors.
+	Diagnostics() options.Diagnostics
+
 	// Publish the resources spec/status event to the broker.
 	Publish(ctx context.Context, eventType types.CloudEventsType, obj T) error
 
 	// Subscribe the resources status/spec event to the broker to receive the resources status/spec and use
-	// ResourceHandler to handle them.
-	Subscribe(ctx context.Context, handlers ...ResourceHandler[T])
+	// ResourceHandler to handle them. It can be called multiple times, the returned subscription is used to stop
+	// handling the events with the given handlers.
+	Subscribe(ctx context.Context, handlers ...ResourceHandler[T]) Subscription
+
+	// SubscribeWithEvents is same as Subscribe, but the received events are passed to the EventHandler alongside the
+	// resource objects that are decoded from them.
+	SubscribeWithEvents(ctx context.Context, handlers ...EventHandler[T]) Subscription
+
+	// Call sends a call request to an agent/source and waits for the call response, the call request is correlated with
+	// its response by the request ID, so the callers can build the command-style interactions on the transport.
+	//   - A source sends the call request to the agent of the cluster with the given cluster name.
+	//   - An agent sends the call request to the source with the given source ID.
+	// The call requests are responded by the CallHandler of the options of the agent/source.
+	Call(ctx context.Context, target string, request cloudevents.Event) (cloudevents.Event, error)
 
 	// ReconnectedChan returns a chan which indicates the source/agent client is reconnected.
 	// The source/agent client callers should consider sending a resync request when receiving this signal.
 	ReconnectedChan() <-chan struct{}
+
+	// Drain stops accepting new publishes and received events, and waits for the in-flight publishes and the handlers
+	// of the received events to finish until the context is done.
+	Drain(ctx context.Context) error
+
+	// Close drains the client and closes the subscriptions to the broker.
+	Close(ctx context.Context) error
 }
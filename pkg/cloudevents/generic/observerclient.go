@@ -0,0 +1,114 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// CloudEventObserverClient is a read-only client that observes the resource spec and status events between all the
+// sources and agents, e.g. to build a fleet-wide dashboard of the spec and status traffic.
+//
+// An observer never sends events, so it does not participate in the resync, and the resync requests and the
+// registrations, the calls and the delivery receipts that it receives are ignored.
+type CloudEventObserverClient[T ResourceObject] struct {
+	*baseClient
+	specCodecs   *CodecRegistry[T]
+	statusCodecs *CodecRegistry[T]
+}
+
+// NewCloudEventObserverClient returns an instance for CloudEventObserverClient. The following arguments are required
+// to create a client.
+//   - observerOptions provides the observerID and the cloudevents clients that subscribe to the events of all sources
+//     and clusters, e.g. mqtt.NewObserverOptions or grpc.NewObserverOptions.
+//   - specCodecs decode the resource objects from the spec events that are sent by the sources.
+//   - statusCodecs decode the resource objects from the status events that are sent by the agents.
+func NewCloudEventObserverClient[T ResourceObject](
+	ctx context.Context,
+	observerOptions *options.CloudEventsObserverOptions,
+	specCodecs []Codec[T],
+	statusCodecs []Codec[T],
+) (*CloudEventObserverClient[T], error) {
+	baseClient := &baseClient{
+		cloudEventsOptions:     observerOptions.CloudEventsOptions,
+		cloudEventsRateLimiter: NewRateLimiter(options.EventRateLimit{}),
+		stopChan:               make(chan struct{}),
+		discardedEventHandler:  observerOptions.DiscardedEventHandler,
+		tenantID:               observerOptions.TenantID,
+		clock:                  clockOrDefault(observerOptions.Clock),
+	}
+
+	baseClient.handlerInvoker = newHandlerInvoker(options.HandlerErrorPolicy{}, baseClient.clock, baseClient.stopChan)
+
+	if err := baseClient.connect(ctx); err != nil {
+		return nil, err
+	}
+
+	return &CloudEventObserverClient[T]{
+		baseClient:   baseClient,
+		specCodecs:   NewCodecRegistry(specCodecs...),
+		statusCodecs: NewCodecRegistry(statusCodecs...),
+	}, nil
+}
+
+// Observe starts to receive the spec and status events and handles the decoded resource objects with the handlers,
+// the client stops receiving events when the context is done.
+func (c *CloudEventObserverClient[T]) Observe(ctx context.Context, handlers ...ObserveHandler[T]) {
+	c.subscribe(ctx, func(ctx context.Context, evt cloudevents.Event) {
+		c.receive(ctx, evt, handlers...)
+	})
+}
+
+func (c *CloudEventObserverClient[T]) receive(ctx context.Context, evt cloudevents.Event, handlers ...ObserveHandler[T]) {
+	klog.V(4).Infof("Observed event:\n%s", evt)
+
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		c.discard(evt, fmt.Sprintf("failed to parse cloud event type %s, %v", evt.Type(), err))
+		return
+	}
+
+	switch eventType.Action {
+	case types.ResyncRequestAction, types.RegisterRequestAction, types.RegisterResponseAction,
+		types.CallRequestAction, types.CallResponseAction, types.DeliveryReceiptAction, types.ResyncCompleteAction:
+		// the observer does not participate in the resync, the registration, the calls and the delivery receipts
+		klog.V(4).Infof("ignore the %s event %s", eventType.Action, evt.ID())
+		return
+	}
+
+	var codecs *CodecRegistry[T]
+	switch eventType.SubResource {
+	case types.SubResourceSpec:
+		codecs = c.specCodecs
+	case types.SubResourceStatus:
+		codecs = c.statusCodecs
+	default:
+		c.discard(evt, fmt.Sprintf("unsupported event type %s", eventType))
+		return
+	}
+
+	codec, ok := codecs.Get(eventType.CloudEventsDataType)
+	if !ok {
+		c.discard(evt, fmt.Sprintf("no codec for the %s of %s", eventType.SubResource, eventType.CloudEventsDataType))
+		return
+	}
+
+	obj, err := codec.Decode(&evt)
+	if err != nil {
+		c.discard(evt, fmt.Sprintf("failed to decode the %s, %v", eventType.SubResource, err))
+		return
+	}
+
+	for index, handler := range handlers {
+		handler := handler
+		c.handlerInvoker.invoke(ctx, evt, fmt.Sprintf("%s/%d", obj.GetUID(), index), func() error {
+			return handler(ctx, evt, obj)
+		}, nil)
+	}
+}
@@ -0,0 +1,91 @@
+package generic
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestObserverReceive(t *testing.T) {
+	encode := func(source string, subResource types.EventSubResource, action types.EventAction) cloudevents.Event {
+		eventType := types.CloudEventsType{
+			CloudEventsDataType: mockEventDataType,
+			SubResource:         subResource,
+			Action:              action,
+		}
+		evt, err := newMockResourceCodec().Encode(source, eventType,
+			&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *evt
+	}
+
+	cases := []struct {
+		name              string
+		event             cloudevents.Event
+		expectedObserved  bool
+		expectedDiscarded bool
+	}{
+		{
+			name:             "spec event",
+			event:            encode(testSourceName, types.SubResourceSpec, "test_create_request"),
+			expectedObserved: true,
+		},
+		{
+			name:             "status event",
+			event:            encode(testAgentName, types.SubResourceStatus, "test_update_request"),
+			expectedObserved: true,
+		},
+		{
+			name:  "resync request is ignored",
+			event: encode(testAgentName, types.SubResourceSpec, types.ResyncRequestAction),
+		},
+		{
+			name: "unknown event data type",
+			event: func() cloudevents.Event {
+				evt := encode(testSourceName, types.SubResourceSpec, "test_create_request")
+				evt.SetType("io.open-cluster-management.unknowns.v1.unknowns.spec.test_create_request")
+				return evt
+			}(),
+			expectedDiscarded: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			discarded := false
+			observerOptions := &options.CloudEventsObserverOptions{
+				CloudEventsOptions: fake.NewSourceOptions(fake.NewCloudEventsFakeClient(), "observer").CloudEventsOptions,
+				ObserverID:         "observer",
+				DiscardedEventHandler: func(evt cloudevents.Event, reason string) {
+					discarded = true
+				},
+			}
+			observer, err := NewCloudEventObserverClient[*mockResource](context.TODO(), observerOptions,
+				[]Codec[*mockResource]{newMockResourceCodec()}, []Codec[*mockResource]{newMockResourceCodec()})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var observed *mockResource
+			observer.receive(context.TODO(), c.event, func(ctx context.Context, evt cloudevents.Event, obj *mockResource) error {
+				observed = obj
+				return nil
+			})
+
+			if c.expectedObserved != (observed != nil) {
+				t.Errorf("expected observed %v, but got %v", c.expectedObserved, observed)
+			}
+			if c.expectedDiscarded != discarded {
+				t.Errorf("expected discarded %v, but got %v", c.expectedDiscarded, discarded)
+			}
+		})
+	}
+}
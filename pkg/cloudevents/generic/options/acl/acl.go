@@ -0,0 +1,238 @@
+// Package acl generates the broker access control rules of the sources and the agents from the topic layout of the
+// cloudevents clients, so the brokers can be provisioned with the least privilege, e.g. a source can only publish the
+// specs with its source ID and an agent can only receive the specs of its own cluster. The rules are built by the
+// transports, e.g. mqtt.ACLRules and grpc.AuthorizationRules, and rendered to the formats of the brokers.
+package acl
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Role is the role of a client of the broker.
+type Role string
+
+const (
+	// RoleSource is the role of a source client, its name is the source ID.
+	RoleSource Role = "source"
+
+	// RoleAgent is the role of an agent client, its name is the cluster name of the agent.
+	RoleAgent Role = "agent"
+)
+
+// Access is the access of a client to a topic.
+type Access string
+
+const (
+	AccessPublish   Access = "publish"
+	AccessSubscribe Access = "subscribe"
+)
+
+// Rule allows a source or an agent to publish to or subscribe a topic, the topic may contain the MQTT wildcards.
+type Rule struct {
+	Role   Role
+	Name   string
+	Access Access
+	Topic  string
+}
+
+// Subjects maps the sources and the agents to the subjects of the brokers, e.g. the usernames of MQTT or the common
+// names of the client certificates, the formats take the source ID or the cluster name as the only argument, e.g.
+// `%s-work-agent`, by default, the source ID and the cluster name are used as the subjects.
+type Subjects struct {
+	SourceFormat string
+	AgentFormat  string
+}
+
+func (s Subjects) subject(rule Rule) string {
+	format := s.SourceFormat
+	if rule.Role == RoleAgent {
+		format = s.AgentFormat
+	}
+
+	if len(format) == 0 {
+		return rule.Name
+	}
+
+	return fmt.Sprintf(format, rule.Name)
+}
+
+// subjectRules are the published and subscribed topics of a subject.
+type subjectRules struct {
+	subject   string
+	publish   []string
+	subscribe []string
+}
+
+// groupBySubject groups the rules by their subjects, the subjects and the topics keep the order of the rules and the
+// duplicated topics are removed.
+func groupBySubject(rules []Rule, subjects Subjects) []*subjectRules {
+	grouped := []*subjectRules{}
+	index := map[string]*subjectRules{}
+	seen := map[Rule]bool{}
+	for _, rule := range rules {
+		subject := subjects.subject(rule)
+		key := Rule{Name: subject, Access: rule.Access, Topic: rule.Topic}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		s, ok := index[subject]
+		if !ok {
+			s = &subjectRules{subject: subject}
+			index[subject] = s
+			grouped = append(grouped, s)
+		}
+
+		switch rule.Access {
+		case AccessPublish:
+			s.publish = append(s.publish, rule.Topic)
+		case AccessSubscribe:
+			s.subscribe = append(s.subscribe, rule.Topic)
+		}
+	}
+	return grouped
+}
+
+// Mosquitto renders the rules in the format of the Mosquitto acl_file, a topic that is published and subscribed by a
+// subject is granted with `readwrite`.
+func Mosquitto(rules []Rule, subjects Subjects) []byte {
+	var buf bytes.Buffer
+	for i, s := range groupBySubject(rules, subjects) {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "user %s\n", s.subject)
+
+		subscribed := map[string]bool{}
+		for _, topic := range s.subscribe {
+			subscribed[topic] = true
+		}
+
+		published := map[string]bool{}
+		for _, topic := range s.publish {
+			published[topic] = true
+			if subscribed[topic] {
+				fmt.Fprintf(&buf, "topic readwrite %s\n", topic)
+				continue
+			}
+			fmt.Fprintf(&buf, "topic write %s\n", topic)
+		}
+
+		for _, topic := range s.subscribe {
+			if published[topic] {
+				continue
+			}
+			fmt.Fprintf(&buf, "topic read %s\n", topic)
+		}
+	}
+	return buf.Bytes()
+}
+
+// EMQX renders the rules in the format of the EMQX file authorizer (acl.conf), the rules allow the subjects by their
+// usernames, and the last rule denies all the other accesses.
+func EMQX(rules []Rule, subjects Subjects) []byte {
+	var buf bytes.Buffer
+	for _, s := range groupBySubject(rules, subjects) {
+		if len(s.publish) != 0 {
+			fmt.Fprintf(&buf, "{allow, {username, %q}, publish, [%s]}.\n", s.subject, quoteTopics(s.publish))
+		}
+		if len(s.subscribe) != 0 {
+			fmt.Fprintf(&buf, "{allow, {username, %q}, subscribe, [%s]}.\n", s.subject, quoteTopics(s.subscribe))
+		}
+	}
+	buf.WriteString("{deny, all}.\n")
+	return buf.Bytes()
+}
+
+func quoteTopics(topics []string) string {
+	var buf bytes.Buffer
+	for i, topic := range topics {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "%q", topic)
+	}
+	return buf.String()
+}
+
+// Policy is the authorization policy of the gRPC server, it lists the topics that each subject is allowed to publish
+// to and subscribe, a request that is not allowed by the policy should be denied.
+type Policy struct {
+	Rules []PolicyRule `json:"rules" yaml:"rules"`
+}
+
+// PolicyRule is the published and subscribed topics of a subject in a gRPC authorization policy.
+type PolicyRule struct {
+	Subject   string   `json:"subject" yaml:"subject"`
+	Publish   []string `json:"publish,omitempty" yaml:"publish,omitempty"`
+	Subscribe []string `json:"subscribe,omitempty" yaml:"subscribe,omitempty"`
+}
+
+// NewPolicy builds the gRPC authorization policy of the rules, the rules of the policy are sorted by their subjects.
+func NewPolicy(rules []Rule, subjects Subjects) *Policy {
+	policy := &Policy{Rules: []PolicyRule{}}
+	for _, s := range groupBySubject(rules, subjects) {
+		policy.Rules = append(policy.Rules, PolicyRule{Subject: s.subject, Publish: s.publish, Subscribe: s.subscribe})
+	}
+
+	sort.SliceStable(policy.Rules, func(i, j int) bool {
+		return policy.Rules[i].Subject < policy.Rules[j].Subject
+	})
+	return policy
+}
+
+// Allowed returns true if the subject is allowed to access the topic by the policy, the topics of the policy are
+// matched with the MQTT wildcards, the subscribed topic must be the same as a topic of the policy.
+func (p *Policy) Allowed(subject string, access Access, topic string) bool {
+	for _, rule := range p.Rules {
+		if rule.Subject != subject {
+			continue
+		}
+
+		switch access {
+		case AccessPublish:
+			for _, filter := range rule.Publish {
+				if MatchTopic(filter, topic) {
+					return true
+				}
+			}
+		case AccessSubscribe:
+			for _, filter := range rule.Subscribe {
+				if filter == topic {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// YAML renders the policy in YAML.
+func (p *Policy) YAML() ([]byte, error) {
+	return yaml.Marshal(p)
+}
+
+// MatchTopic returns true if the topic matches the topic filter, the `+` of the filter matches a level of the topic
+// and the `#` matches the remaining levels.
+func MatchTopic(filter, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
@@ -0,0 +1,129 @@
+package acl
+
+import (
+	"testing"
+)
+
+var testRules = []Rule{
+	{Role: RoleSource, Name: "source1", Access: AccessPublish, Topic: "sources/source1/clusters/+/sourceevents"},
+	{Role: RoleSource, Name: "source1", Access: AccessSubscribe, Topic: "sources/source1/clusters/+/agentevents"},
+	{Role: RoleAgent, Name: "cluster1", Access: AccessPublish, Topic: "sources/+/clusters/cluster1/agentevents"},
+	{Role: RoleAgent, Name: "cluster1", Access: AccessPublish, Topic: "clusters/cluster1/claim"},
+	{Role: RoleAgent, Name: "cluster1", Access: AccessSubscribe, Topic: "sources/+/clusters/cluster1/sourceevents"},
+	{Role: RoleAgent, Name: "cluster1", Access: AccessSubscribe, Topic: "clusters/cluster1/claim"},
+	// duplicated rule
+	{Role: RoleAgent, Name: "cluster1", Access: AccessSubscribe, Topic: "clusters/cluster1/claim"},
+}
+
+func TestMosquitto(t *testing.T) {
+	expected := `user source1
+topic write sources/source1/clusters/+/sourceevents
+topic read sources/source1/clusters/+/agentevents
+
+user cluster1-work-agent
+topic write sources/+/clusters/cluster1/agentevents
+topic readwrite clusters/cluster1/claim
+topic read sources/+/clusters/cluster1/sourceevents
+`
+
+	if aclFile := string(Mosquitto(testRules, Subjects{AgentFormat: "%s-work-agent"})); aclFile != expected {
+		t.Errorf("expected %s, but got %s", expected, aclFile)
+	}
+}
+
+func TestEMQX(t *testing.T) {
+	expected := `{allow, {username, "source1"}, publish, ["sources/source1/clusters/+/sourceevents"]}.
+{allow, {username, "source1"}, subscribe, ["sources/source1/clusters/+/agentevents"]}.
+{allow, {username, "cluster1"}, publish, ["sources/+/clusters/cluster1/agentevents", "clusters/cluster1/claim"]}.
+{allow, {username, "cluster1"}, subscribe, ["sources/+/clusters/cluster1/sourceevents", "clusters/cluster1/claim"]}.
+{deny, all}.
+`
+
+	if aclFile := string(EMQX(testRules, Subjects{})); aclFile != expected {
+		t.Errorf("expected %s, but got %s", expected, aclFile)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	policy := NewPolicy(testRules, Subjects{SourceFormat: "%s-controller"})
+	if len(policy.Rules) != 2 || policy.Rules[0].Subject != "cluster1" || policy.Rules[1].Subject != "source1-controller" {
+		t.Fatalf("expected the rules are sorted by the subjects, but got %v", policy.Rules)
+	}
+
+	cases := []struct {
+		name     string
+		subject  string
+		access   Access
+		topic    string
+		expected bool
+	}{
+		{
+			name:     "publish to a cluster",
+			subject:  "source1-controller",
+			access:   AccessPublish,
+			topic:    "sources/source1/clusters/cluster2/sourceevents",
+			expected: true,
+		},
+		{
+			name:    "publish with another source",
+			subject: "source1-controller",
+			access:  AccessPublish,
+			topic:   "sources/source2/clusters/cluster2/sourceevents",
+		},
+		{
+			name:     "subscribe the same topic",
+			subject:  "cluster1",
+			access:   AccessSubscribe,
+			topic:    "sources/+/clusters/cluster1/sourceevents",
+			expected: true,
+		},
+		{
+			name:    "subscribe a topic that is matched by a rule",
+			subject: "cluster1",
+			access:  AccessSubscribe,
+			topic:   "sources/source1/clusters/cluster1/sourceevents",
+		},
+		{
+			name:    "unknown subject",
+			subject: "cluster2",
+			access:  AccessSubscribe,
+			topic:   "sources/+/clusters/cluster1/sourceevents",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if allowed := policy.Allowed(c.subject, c.access, c.topic); allowed != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, allowed)
+			}
+		})
+	}
+
+	data, err := policy.YAML()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(data) == 0 {
+		t.Errorf("expected the policy is rendered")
+	}
+}
+
+func TestMatchTopic(t *testing.T) {
+	cases := []struct {
+		filter   string
+		topic    string
+		expected bool
+	}{
+		{filter: "sources/+/clusters/+/spec", topic: "sources/source1/clusters/cluster1/spec", expected: true},
+		{filter: "sources/+/clusters/+/spec", topic: "sources/source1/clusters/cluster1/spec/res1", expected: false},
+		{filter: "sources/source1/#", topic: "sources/source1/clusters/cluster1/spec", expected: true},
+		{filter: "sources/source1/clusters/+/spec", topic: "sources/source2/clusters/cluster1/spec", expected: false},
+		{filter: "sources/source1/clusters/+/spec", topic: "sources/source1/clusters", expected: false},
+	}
+
+	for _, c := range cases {
+		if matched := MatchTopic(c.filter, c.topic); matched != c.expected {
+			t.Errorf("expected %s matches %s %v, but got %v", c.filter, c.topic, c.expected, matched)
+		}
+	}
+}
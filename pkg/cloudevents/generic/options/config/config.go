@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/errors"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+)
+
+// TransportType is the type of the transport that is used to send/receive the cloudevents.
+type TransportType string
+
+const (
+	TransportTypeMQTT  TransportType = "mqtt"
+	TransportTypeGRPC  TransportType = "grpc"
+	TransportTypeKafka TransportType = "kafka"
+)
+
+// TransportConfig is the unified config of the transports, the type determines which transport section is used, e.g.
+//
+//	type: mqtt
+//	mqtt:
+//	  brokerHost: broker.example.com:1883
+//	  topics:
+//	    sourceEvents: sources/hub1/clusters/+/sourceevents
+//	    agentEvents: sources/hub1/clusters/+/agentevents
+type TransportConfig struct {
+	// Type is the type of the transport, it must be one of mqtt, grpc and kafka.
+	Type TransportType `json:"type" yaml:"type"`
+
+	// MQTT is the config of the MQTT transport, it is required when the type is mqtt.
+	MQTT *mqtt.MQTTConfig `json:"mqtt,omitempty" yaml:"mqtt,omitempty"`
+
+	// GRPC is the config of the gRPC transport, it is required when the type is grpc.
+	GRPC *grpc.GRPCConfig `json:"grpc,omitempty" yaml:"grpc,omitempty"`
+
+	// Kafka is the config of the Kafka transport, it is required when the type is kafka.
+	Kafka *KafkaConfig `json:"kafka,omitempty" yaml:"kafka,omitempty"`
+}
+
+// KafkaConfig holds the information needed to connect to Kafka brokers.
+type KafkaConfig struct {
+	// BootstrapServer is the host of the Kafka bootstrap server (hostname:port).
+	BootstrapServer string `json:"bootstrapServer" yaml:"bootstrapServer"`
+	// GroupID is the ID of the consumer group.
+	GroupID string `json:"groupID,omitempty" yaml:"groupID,omitempty"`
+
+	// CAFile is the file path to a cert file for the Kafka broker certificate authority.
+	CAFile string `json:"caFile,omitempty" yaml:"caFile,omitempty"`
+	// ClientCertFile is the file path to a client cert file for TLS.
+	ClientCertFile string `json:"clientCertFile,omitempty" yaml:"clientCertFile,omitempty"`
+	// ClientKeyFile is the file path to a client key file for TLS.
+	ClientKeyFile string `json:"clientKeyFile,omitempty" yaml:"clientKeyFile,omitempty"`
+}
+
+// LoadTransportConfig loads the transport config from the given file, the unknown fields are not allowed, and the
+// config is validated after it is loaded.
+func LoadTransportConfig(configPath string) (*TransportConfig, error) {
+	configData, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &TransportConfig{}
+	if err := yaml.UnmarshalStrict(configData, config); err != nil {
+		return nil, fmt.Errorf("failed to parse transport config %s, %v", configPath, err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid transport config %s, %v", configPath, err)
+	}
+
+	return config, nil
+}
+
+// Validate checks that the config has a supported type and only the section of the type is set.
+func (c *TransportConfig) Validate() error {
+	sections := map[TransportType]bool{
+		TransportTypeMQTT:  c.MQTT != nil,
+		TransportTypeGRPC:  c.GRPC != nil,
+		TransportTypeKafka: c.Kafka != nil,
+	}
+
+	set, ok := sections[c.Type]
+	if !ok {
+		return fmt.Errorf("unsupported type %q, it must be one of %s, %s and %s",
+			c.Type, TransportTypeMQTT, TransportTypeGRPC, TransportTypeKafka)
+	}
+
+	var errs []error
+	if !set {
+		errs = append(errs, fmt.Errorf("the %s section is required for type %s", c.Type, c.Type))
+	}
+
+	for _, transportType := range []TransportType{TransportTypeMQTT, TransportTypeGRPC, TransportTypeKafka} {
+		if transportType != c.Type && sections[transportType] {
+			errs = append(errs, fmt.Errorf("the %s section must not be set for type %s", transportType, c.Type))
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.NewAggregate(errs)
+	}
+
+	switch c.Type {
+	case TransportTypeMQTT:
+		if _, err := mqtt.BuildMQTTOptionsFromConfig(c.MQTT); err != nil {
+			return fmt.Errorf("invalid mqtt section, %v", err)
+		}
+	case TransportTypeGRPC:
+		if _, err := grpc.BuildGRPCOptionsFromConfig(c.GRPC); err != nil {
+			return fmt.Errorf("invalid grpc section, %v", err)
+		}
+	case TransportTypeKafka:
+		if c.Kafka.BootstrapServer == "" {
+			return fmt.Errorf("invalid kafka section, bootstrapServer is required")
+		}
+	}
+
+	return nil
+}
+
+// BuildOptions builds the transport options from the config, the options can be used to build the cloudevents
+// clients, e.g. it returns a *mqtt.MQTTOptions for the mqtt type.
+func (c *TransportConfig) BuildOptions() (any, error) {
+	switch c.Type {
+	case TransportTypeMQTT:
+		return mqtt.BuildMQTTOptionsFromConfig(c.MQTT)
+	case TransportTypeGRPC:
+		return grpc.BuildGRPCOptionsFromConfig(c.GRPC)
+	case TransportTypeKafka:
+		return nil, fmt.Errorf("the kafka transport is not supported yet")
+	}
+
+	return nil, fmt.Errorf("unsupported type %q", c.Type)
+}
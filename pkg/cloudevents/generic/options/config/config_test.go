@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+)
+
+func TestLoadTransportConfig(t *testing.T) {
+	cases := []struct {
+		name             string
+		config           string
+		expectedErrorMsg string
+		validate         func(options any)
+	}{
+		{
+			name:             "without type",
+			config:           "grpc:\n  url: test",
+			expectedErrorMsg: `unsupported type "", it must be one of mqtt, grpc and kafka`,
+		},
+		{
+			name:             "unknown type",
+			config:           "type: amqp",
+			expectedErrorMsg: `unsupported type "amqp"`,
+		},
+		{
+			name:             "unknown field",
+			config:           "type: grpc\ngrpc:\n  url: test\n  token: test",
+			expectedErrorMsg: "field token not found",
+		},
+		{
+			name:             "without section",
+			config:           "type: grpc",
+			expectedErrorMsg: "the grpc section is required for type grpc",
+		},
+		{
+			name:             "with other section",
+			config:           "type: grpc\ngrpc:\n  url: test\nmqtt:\n  brokerHost: test",
+			expectedErrorMsg: "the mqtt section must not be set for type grpc",
+		},
+		{
+			name:             "invalid section",
+			config:           "type: mqtt\nmqtt:\n  brokerHost: test",
+			expectedErrorMsg: "invalid mqtt section, the topics must be set",
+		},
+		{
+			name: "mqtt config",
+			config: `
+type: mqtt
+mqtt:
+  brokerHost: test
+  topics:
+    sourceEvents: sources/hub1/clusters/+/sourceevents
+    agentEvents: sources/hub1/clusters/+/agentevents
+`,
+			validate: func(options any) {
+				mqttOptions, ok := options.(*mqtt.MQTTOptions)
+				if !ok || mqttOptions.BrokerHost != "test" {
+					t.Errorf("unexpected options %v", options)
+				}
+			},
+		},
+		{
+			name:   "grpc config",
+			config: "type: grpc\ngrpc:\n  url: test",
+			validate: func(options any) {
+				grpcOptions, ok := options.(*grpc.GRPCOptions)
+				if !ok || grpcOptions.URL != "test" {
+					t.Errorf("unexpected options %v", options)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(c.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadTransportConfig(configPath)
+			if len(c.expectedErrorMsg) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.expectedErrorMsg) {
+					t.Errorf("expected error %q, but got %v", c.expectedErrorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			options, err := config.BuildOptions()
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			c.validate(options)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package fake
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/event"
@@ -43,6 +44,7 @@ func (o *CloudEventsFakeOptions) ErrorChan() <-chan error {
 }
 
 type CloudEventsFakeClient struct {
+	sync.Mutex
 	sentEvents     []cloudevents.Event
 	receivedEvents []cloudevents.Event
 }
@@ -55,6 +57,9 @@ func NewCloudEventsFakeClient(receivedEvents ...cloudevents.Event) *CloudEventsF
 }
 
 func (c *CloudEventsFakeClient) Send(ctx context.Context, event cloudevents.Event) protocol.Result {
+	c.Lock()
+	defer c.Unlock()
+
 	c.sentEvents = append(c.sentEvents, event)
 	return nil
 }
@@ -76,5 +81,8 @@ func (c *CloudEventsFakeClient) StartReceiver(ctx context.Context, fn interface{
 }
 
 func (c *CloudEventsFakeClient) GetSentEvents() []cloudevents.Event {
+	c.Lock()
+	defer c.Unlock()
+
 	return c.sentEvents
 }
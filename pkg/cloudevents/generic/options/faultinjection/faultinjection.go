@@ -0,0 +1,283 @@
+// Package faultinjection provides a CloudEventsOptions wrapper that injects the faults of an adverse broker, e.g. the
+// dropped, delayed and duplicated events and the disconnections, so the resync and the dedupe behaviors of the
+// source/agent clients can be validated in the tests and the soak environments, e.g.
+//
+//	injector, err := faultinjection.NewFaultInjector(sourceOptions.CloudEventsOptions, faultinjection.Faults{
+//		DropRate:           0.1,
+//		DuplicateRate:      0.1,
+//		Delay:              100 * time.Millisecond,
+//		Jitter:             100 * time.Millisecond,
+//		DisconnectInterval: time.Minute,
+//	})
+//	sourceOptions.CloudEventsOptions = injector
+package faultinjection
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// Faults are the faults that are injected to the sent and received events, the zero value does not inject any fault.
+type Faults struct {
+	// DropRate is the probability in [0, 1] to drop an event, a dropped event that is sent is reported as delivered.
+	DropRate float64
+
+	// DuplicateRate is the probability in [0, 1] to send or receive an event twice.
+	DuplicateRate float64
+
+	// Delay is the delay before an event is sent or handled.
+	Delay time.Duration
+
+	// Jitter is the maximum random duration that is added to the Delay.
+	Jitter time.Duration
+
+	// DisconnectInterval is the interval to force the client to disconnect, the client is not disconnected if it is
+	// zero.
+	DisconnectInterval time.Duration
+}
+
+// Validate returns an error if the faults are invalid.
+func (f Faults) Validate() error {
+	if f.DropRate < 0 || f.DropRate > 1 {
+		return fmt.Errorf("the drop rate %v must be in [0, 1]", f.DropRate)
+	}
+
+	if f.DuplicateRate < 0 || f.DuplicateRate > 1 {
+		return fmt.Errorf("the duplicate rate %v must be in [0, 1]", f.DuplicateRate)
+	}
+
+	if f.Delay < 0 || f.Jitter < 0 || f.DisconnectInterval < 0 {
+		return fmt.Errorf("the delay, jitter and disconnect interval must not be negative")
+	}
+
+	return nil
+}
+
+// Stats are the numbers of the injected faults.
+type Stats struct {
+	Dropped     int
+	Duplicated  int
+	Delayed     int
+	Disconnects int
+}
+
+// FaultInjector wraps a CloudEventsOptions and injects the faults to the events that are sent or received by its
+// clients.
+type FaultInjector struct {
+	options.CloudEventsOptions
+
+	faults    Faults
+	errorChan chan error
+	startOnce sync.Once
+
+	mu    sync.Mutex
+	rand  *rand.Rand
+	stats Stats
+}
+
+var _ options.CloudEventsOptions = &FaultInjector{}
+
+// NewFaultInjector returns a FaultInjector that injects the given faults to the clients of the cloudevents options.
+func NewFaultInjector(cloudEventsOptions options.CloudEventsOptions, faults Faults) (*FaultInjector, error) {
+	if err := faults.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &FaultInjector{
+		CloudEventsOptions: cloudEventsOptions,
+		faults:             faults,
+		errorChan:          make(chan error),
+		rand:               rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// WithSeed sets the seed of the random faults, so the injected faults are reproducible.
+func (f *FaultInjector) WithSeed(seed int64) *FaultInjector {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rand = rand.New(rand.NewSource(seed))
+	return f
+}
+
+// Client returns a cloudevents client that injects the faults to the client of the wrapped cloudevents options, the
+// forced disconnections start when the first client is created and stop when the context is done.
+func (f *FaultInjector) Client(ctx context.Context) (cloudevents.Client, error) {
+	f.startOnce.Do(func() {
+		go f.forwardErrors(ctx)
+	})
+
+	client, err := f.CloudEventsOptions.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &faultyClient{Client: client, injector: f}, nil
+}
+
+// ErrorChan returns the connection errors of the wrapped cloudevents options and the forced disconnections.
+func (f *FaultInjector) ErrorChan() <-chan error {
+	return f.errorChan
+}
+
+// Resumable returns false, the subscriptions cannot be resumed with the dropped events, so the client always resyncs
+// after it is reconnected.
+func (f *FaultInjector) Resumable() bool {
+	return false
+}
+
+// Stats returns the numbers of the injected faults.
+func (f *FaultInjector) Stats() Stats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+func (f *FaultInjector) forwardErrors(ctx context.Context) {
+	var disconnect <-chan time.Time
+	if f.faults.DisconnectInterval > 0 {
+		ticker := time.NewTicker(f.faults.DisconnectInterval)
+		defer ticker.Stop()
+		disconnect = ticker.C
+	}
+
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-f.CloudEventsOptions.ErrorChan():
+			if !ok {
+				return
+			}
+			err = e
+		case <-disconnect:
+			f.mu.Lock()
+			f.stats.Disconnects++
+			f.mu.Unlock()
+			err = fmt.Errorf("the connection is closed by the fault injector")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case f.errorChan <- err:
+		}
+	}
+}
+
+// drop returns true if the event should be dropped.
+func (f *FaultInjector) drop(evt cloudevents.Event) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.faults.DropRate == 0 || f.rand.Float64() >= f.faults.DropRate {
+		return false
+	}
+
+	klog.V(4).Infof("the fault injector drops the event %s", evt.ID())
+	f.stats.Dropped++
+	return true
+}
+
+// duplicate returns true if the event should be duplicated.
+func (f *FaultInjector) duplicate(evt cloudevents.Event) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.faults.DuplicateRate == 0 || f.rand.Float64() >= f.faults.DuplicateRate {
+		return false
+	}
+
+	klog.V(4).Infof("the fault injector duplicates the event %s", evt.ID())
+	f.stats.Duplicated++
+	return true
+}
+
+// delay waits for the delay and a random jitter, it returns false if the context is done.
+func (f *FaultInjector) delay(ctx context.Context) bool {
+	f.mu.Lock()
+	delay := f.faults.Delay
+	if f.faults.Jitter > 0 {
+		delay += time.Duration(f.rand.Int63n(int64(f.faults.Jitter)))
+	}
+	if delay > 0 {
+		f.stats.Delayed++
+	}
+	f.mu.Unlock()
+
+	if delay == 0 {
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
+
+// faultyClient is a cloudevents client that injects the faults to the sent and received events.
+type faultyClient struct {
+	cloudevents.Client
+	injector *FaultInjector
+}
+
+func (c *faultyClient) Send(ctx context.Context, evt cloudevents.Event) protocol.Result {
+	if c.injector.drop(evt) {
+		return nil
+	}
+
+	if !c.injector.delay(ctx) {
+		return ctx.Err()
+	}
+
+	result := c.Client.Send(ctx, evt)
+	if cloudevents.IsUndelivered(result) || !c.injector.duplicate(evt) {
+		return result
+	}
+
+	return c.Client.Send(ctx, evt)
+}
+
+func (c *faultyClient) StartReceiver(ctx context.Context, fn interface{}) error {
+	switch receiver := fn.(type) {
+	case func(evt cloudevents.Event):
+		return c.Client.StartReceiver(ctx, func(evt cloudevents.Event) {
+			c.receive(ctx, evt, func(ctx context.Context, evt cloudevents.Event) { receiver(evt) })
+		})
+	case func(ctx context.Context, evt cloudevents.Event):
+		return c.Client.StartReceiver(ctx, func(receiverCtx context.Context, evt cloudevents.Event) {
+			c.receive(receiverCtx, evt, receiver)
+		})
+	default:
+		return fmt.Errorf("unsupported receiver %T", fn)
+	}
+}
+
+func (c *faultyClient) receive(ctx context.Context, evt cloudevents.Event,
+	receiver func(ctx context.Context, evt cloudevents.Event)) {
+	if c.injector.drop(evt) {
+		return
+	}
+
+	if !c.injector.delay(ctx) {
+		return
+	}
+
+	receiver(ctx, evt)
+
+	if c.injector.duplicate(evt) {
+		receiver(ctx, evt)
+	}
+}
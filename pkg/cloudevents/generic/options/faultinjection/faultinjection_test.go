@@ -0,0 +1,173 @@
+package faultinjection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestFaultsValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		faults      Faults
+		expectedErr bool
+	}{
+		{
+			name:   "no faults",
+			faults: Faults{},
+		},
+		{
+			name:   "valid faults",
+			faults: Faults{DropRate: 0.5, DuplicateRate: 1, Delay: time.Second, DisconnectInterval: time.Minute},
+		},
+		{
+			name:        "invalid drop rate",
+			faults:      Faults{DropRate: 1.5},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid duplicate rate",
+			faults:      Faults{DuplicateRate: -1},
+			expectedErr: true,
+		},
+		{
+			name:        "negative delay",
+			faults:      Faults{Delay: -time.Second},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.faults.Validate()
+			if c.expectedErr && err == nil {
+				t.Errorf("expected error, but got nil")
+			}
+			if !c.expectedErr && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestFaultInjector(t *testing.T) {
+	cases := []struct {
+		name             string
+		faults           Faults
+		expectedSent     int
+		expectedReceived int
+		expectedStats    Stats
+	}{
+		{
+			name:             "no faults",
+			faults:           Faults{},
+			expectedSent:     2,
+			expectedReceived: 2,
+		},
+		{
+			name:             "drop all events",
+			faults:           Faults{DropRate: 1},
+			expectedSent:     0,
+			expectedReceived: 0,
+			expectedStats:    Stats{Dropped: 4},
+		},
+		{
+			name:             "duplicate all events",
+			faults:           Faults{DuplicateRate: 1},
+			expectedSent:     4,
+			expectedReceived: 4,
+			expectedStats:    Stats{Duplicated: 4},
+		},
+		{
+			name:             "delay all events",
+			faults:           Faults{Delay: time.Millisecond, Jitter: time.Millisecond},
+			expectedSent:     2,
+			expectedReceived: 2,
+			expectedStats:    Stats{Delayed: 4},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			fakeClient := fake.NewCloudEventsFakeClient(newEvent("test1"), newEvent("test2"))
+			injector, err := NewFaultInjector(fake.NewSourceOptions(fakeClient, "source1").CloudEventsOptions, c.faults)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			client, err := injector.WithSeed(1).Client(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			for _, evt := range []cloudevents.Event{newEvent("test1"), newEvent("test2")} {
+				if result := client.Send(ctx, evt); result != nil {
+					t.Fatalf("unexpected error %v", result)
+				}
+			}
+
+			received := 0
+			if err := client.StartReceiver(ctx, func(evt cloudevents.Event) {
+				received++
+			}); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if len(fakeClient.GetSentEvents()) != c.expectedSent {
+				t.Errorf("expected %d sent events, but got %d", c.expectedSent, len(fakeClient.GetSentEvents()))
+			}
+			if received != c.expectedReceived {
+				t.Errorf("expected %d received events, but got %d", c.expectedReceived, received)
+			}
+			if injector.Stats() != c.expectedStats {
+				t.Errorf("expected %v, but got %v", c.expectedStats, injector.Stats())
+			}
+		})
+	}
+}
+
+func TestFaultInjectorDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	injector, err := NewFaultInjector(
+		fake.NewSourceOptions(fake.NewCloudEventsFakeClient(), "source1").CloudEventsOptions,
+		Faults{DisconnectInterval: 10 * time.Millisecond},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, err := injector.Client(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	select {
+	case err := <-injector.ErrorChan():
+		if err == nil {
+			t.Errorf("expected error, but got nil")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected a disconnection, but got none")
+	}
+
+	if injector.Stats().Disconnects == 0 {
+		t.Errorf("expected disconnects, but got %v", injector.Stats())
+	}
+}
+
+func newEvent(resourceID string) cloudevents.Event {
+	return types.NewEventBuilder("source1", types.CloudEventsType{
+		CloudEventsDataType: types.CloudEventsDataType{Group: "test", Version: "v1", Resource: "tests"},
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}).WithResourceID(resourceID).WithClusterName("cluster1").NewEvent()
+}
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"strings"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/acl"
+)
+
+// AuthorizationRules returns the authorization rules of the given sources and the agents of the given clusters on the
+// gRPC server, the topics are the same as the topics that the source and agent clients publish to and subscribe:
+//   - a source publishes the specs to its spec topic of each cluster and its status resync topic, and subscribes its
+//     status topic and the spec resync topic.
+//   - an agent publishes the status to its status topic of each source and its spec resync topic, and subscribes the
+//     spec topic of its cluster and the status resync topic.
+//
+// The topics of a source are published with `+` if the clusters are not given, and the topics of an agent are published
+// with `+` if the sources are not given. The rules can be rendered to a policy with acl.NewPolicy.
+func AuthorizationRules(grpcOptions *GRPCOptions, sourceIDs, clusterNames []string) []acl.Rule {
+	publishedSources := sourceIDs
+	if len(publishedSources) == 0 {
+		publishedSources = []string{"+"}
+	}
+
+	publishedClusters := clusterNames
+	if len(publishedClusters) == 0 {
+		publishedClusters = []string{"+"}
+	}
+
+	rules := []acl.Rule{}
+	for _, sourceID := range sourceIDs {
+		rule := func(access acl.Access, topic string) {
+			rules = append(rules, acl.Rule{
+				Role: acl.RoleSource, Name: sourceID, Access: access, Topic: grpcOptions.topic(topic),
+			})
+		}
+
+		for _, clusterName := range publishedClusters {
+			specTopic := strings.Replace(SpecTopic, "+", sourceID, 1)
+			rule(acl.AccessPublish, strings.Replace(specTopic, "+", clusterName, -1))
+		}
+		rule(acl.AccessPublish, strings.Replace(StatusResyncTopic, "+", sourceID, -1))
+		rule(acl.AccessSubscribe, strings.Replace(StatusTopic, "+", sourceID, 1))
+		rule(acl.AccessSubscribe, SpecResyncTopic)
+	}
+
+	for _, clusterName := range clusterNames {
+		rule := func(access acl.Access, topic string) {
+			rules = append(rules, acl.Rule{
+				Role: acl.RoleAgent, Name: clusterName, Access: access, Topic: grpcOptions.topic(topic),
+			})
+		}
+
+		for _, sourceID := range publishedSources {
+			statusTopic := strings.Replace(StatusTopic, "+", sourceID, 1)
+			rule(acl.AccessPublish, strings.Replace(statusTopic, "+", clusterName, -1))
+		}
+		rule(acl.AccessPublish, strings.Replace(SpecResyncTopic, "+", clusterName, -1))
+		rule(acl.AccessSubscribe, replaceNth(SpecTopic, "+", clusterName, 2))
+		rule(acl.AccessSubscribe, StatusResyncTopic)
+	}
+
+	return rules
+}
@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventscontext "github.com/cloudevents/sdk-go/v2/context"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/acl"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestAuthorizationRules(t *testing.T) {
+	grpcOptions := &GRPCOptions{TenantID: "tenant1"}
+	policy := acl.NewPolicy(AuthorizationRules(grpcOptions, []string{"source1"}, []string{"cluster1"}),
+		acl.Subjects{AgentFormat: "%s-work-agent"})
+
+	newEvent := func(subResource types.EventSubResource, action types.EventAction) cloudevents.Event {
+		evt := cloudevents.NewEvent()
+		evt.SetType(types.CloudEventsType{
+			CloudEventsDataType: mockEventDataType,
+			SubResource:         subResource,
+			Action:              action,
+		}.String())
+		evt.SetExtension(types.ExtensionClusterName, "cluster1")
+		evt.SetExtension(types.ExtensionOriginalSource, "source1")
+		return evt
+	}
+
+	publishedTopic := func(options interface {
+		WithContext(context.Context, cloudevents.EventContext) (context.Context, error)
+	}, evt cloudevents.Event) string {
+		ctx, err := options.WithContext(context.TODO(), evt.Context)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		return cloudeventscontext.TopicFrom(ctx)
+	}
+
+	source := NewSourceOptions(grpcOptions, "source1").CloudEventsOptions.(*gRPCSourceOptions)
+	for _, topic := range []string{
+		publishedTopic(source, newEvent(types.SubResourceSpec, "create_request")),
+		publishedTopic(source, newEvent(types.SubResourceStatus, types.ResyncRequestAction)),
+	} {
+		if !policy.Allowed("source1", acl.AccessPublish, topic) {
+			t.Errorf("expected the source is allowed to publish to %s", topic)
+		}
+		if policy.Allowed("cluster1-work-agent", acl.AccessPublish, topic) {
+			t.Errorf("expected the agent is not allowed to publish to %s", topic)
+		}
+	}
+	for _, topic := range source.subscribeTopics() {
+		if !policy.Allowed("source1", acl.AccessSubscribe, topic) {
+			t.Errorf("expected the source is allowed to subscribe %s", topic)
+		}
+	}
+
+	agent := NewAgentOptions(grpcOptions, "cluster1", "agent1").CloudEventsOptions.(*grpcAgentOptions)
+	for _, topic := range []string{
+		publishedTopic(agent, newEvent(types.SubResourceStatus, "update_request")),
+		publishedTopic(agent, newEvent(types.SubResourceSpec, types.ResyncRequestAction)),
+	} {
+		if !policy.Allowed("cluster1-work-agent", acl.AccessPublish, topic) {
+			t.Errorf("expected the agent is allowed to publish to %s", topic)
+		}
+		if policy.Allowed("source1", acl.AccessPublish, topic) {
+			t.Errorf("expected the source is not allowed to publish to %s", topic)
+		}
+	}
+	for _, topic := range agent.subscribeTopics() {
+		if !policy.Allowed("cluster1-work-agent", acl.AccessSubscribe, topic) {
+			t.Errorf("expected the agent is allowed to subscribe %s", topic)
+		}
+	}
+
+	// the agent of another cluster cannot receive the specs of the cluster1
+	if policy.Allowed("cluster2-work-agent", acl.AccessSubscribe, "tenants/tenant1/sources/+/clusters/cluster1/spec") {
+		t.Errorf("expected the agent of cluster2 is not allowed")
+	}
+}
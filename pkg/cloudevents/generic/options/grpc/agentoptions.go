@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
@@ -14,9 +15,11 @@ import (
 )
 
 type grpcAgentOptions struct {
+	sync.RWMutex
 	GRPCOptions
 	errorChan   chan error // grpc client connection doesn't have error channel, it will handle reconnecting automatically
 	clusterName string
+	offsets     *protocol.SubscriptionOffsets
 }
 
 func NewAgentOptions(grpcOptions *GRPCOptions, clusterName, agentID string) *options.CloudEventsAgentOptions {
@@ -25,22 +28,27 @@ func NewAgentOptions(grpcOptions *GRPCOptions, clusterName, agentID string) *opt
 			GRPCOptions: *grpcOptions,
 			errorChan:   make(chan error),
 			clusterName: clusterName,
+			offsets:     protocol.NewSubscriptionOffsets(),
 		},
-		AgentID:     agentID,
-		ClusterName: clusterName,
+		AgentID:                  agentID,
+		ClusterName:              clusterName,
+		CloudEventsClientOptions: options.CloudEventsClientOptions{TenantID: grpcOptions.TenantID},
 	}
 }
 
 func (o *grpcAgentOptions) WithContext(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+	ctx = options.WithContentMode(ctx, o.grpcOptions().ContentMode)
+
 	eventType, err := types.ParseCloudEventsType(evtCtx.GetType())
 	if err != nil {
 		return nil, fmt.Errorf("unsupported event type %s, %v", eventType, err)
 	}
 
-	if eventType.Action == types.ResyncRequestAction {
-		// agent publishes event to spec resync topic to request to get resources spec from all sources
+	if eventType.Action == types.ResyncRequestAction || eventType.Action == types.RegisterRequestAction {
+		// agent publishes event to spec resync topic to request to get resources spec from all sources or to register
+		// to all sources
 		topic := strings.Replace(SpecResyncTopic, "+", o.clusterName, -1)
-		return cecontext.WithTopic(ctx, topic), nil
+		return cecontext.WithTopic(ctx, o.grpcOptions().topic(topic)), nil
 	}
 
 	// agent publishes event to status topic to send the resource status from a specified cluster
@@ -51,20 +59,26 @@ func (o *grpcAgentOptions) WithContext(ctx context.Context, evtCtx cloudevents.E
 
 	statusTopic := strings.Replace(StatusTopic, "+", fmt.Sprintf("%s", originalSource), 1)
 	statusTopic = strings.Replace(statusTopic, "+", o.clusterName, -1)
-	return cecontext.WithTopic(ctx, statusTopic), nil
+	return cecontext.WithTopic(ctx, o.grpcOptions().topic(statusTopic)), nil
 }
 
 func (o *grpcAgentOptions) Client(ctx context.Context) (cloudevents.Client, error) {
-	receiver, err := o.GetCloudEventsClient(
+	grpcOptions := o.grpcOptions()
+
+	receiver, err := grpcOptions.GetCloudEventsClient(
 		ctx,
 		func(err error) {
 			o.errorChan <- err
 		},
 		protocol.WithPublishOption(&protocol.PublishOption{}),
 		protocol.WithSubscribeOption(&protocol.SubscribeOption{
-			Topics: []string{
-				replaceNth(SpecTopic, "+", o.clusterName, 2), // receiving the resources spec from sources with spec topic
-				StatusResyncTopic, // receiving the resources status resync request from sources with status resync topic
+			Topics:  o.subscribeTopics(),
+			Offsets: o.offsets,
+			ResumeFailedHandler: func(err error) {
+				// reconnect to resubscribe the topics without the offsets
+				go func() {
+					o.errorChan <- err
+				}()
 			},
 		}),
 	)
@@ -77,3 +91,57 @@ func (o *grpcAgentOptions) Client(ctx context.Context) (cloudevents.Client, erro
 func (o *grpcAgentOptions) ErrorChan() <-chan error {
 	return o.errorChan
 }
+
+// Reload rebuilds the gRPC options from the given config file, the cloudevents client is reconnected with the new
+// options.
+func (o *grpcAgentOptions) Reload(configPath string) error {
+	grpcOptions, err := BuildGRPCOptionsFromFlags(configPath)
+	if err != nil {
+		return err
+	}
+
+	o.Lock()
+	// the dial options and the connection are not from the config file, keep them with the reloaded options
+	grpcOptions.DialOptions = o.GRPCOptions.DialOptions
+	grpcOptions.ClientConn = o.GRPCOptions.ClientConn
+	// the tenant of a client can not be changed by reloading
+	grpcOptions.TenantID = o.GRPCOptions.TenantID
+	o.GRPCOptions = *grpcOptions
+	o.Unlock()
+
+	// notify the cloudevents client to reconnect with the new options
+	go func() {
+		o.errorChan <- fmt.Errorf("the gRPC options are reloaded from %s", configPath)
+	}()
+
+	return nil
+}
+
+func (o *grpcAgentOptions) grpcOptions() GRPCOptions {
+	o.RLock()
+	defer o.RUnlock()
+	return o.GRPCOptions
+}
+
+// Resumable returns true if the subscriptions can be resumed from the offsets of the last received events after the
+// client is reconnected, so the resync is not required.
+func (o *grpcAgentOptions) Resumable() bool {
+	return o.offsets.Resumable(o.subscribeTopics())
+}
+
+// Diagnostics returns the address of the gRPC server and the subscribed topics.
+func (o *grpcAgentOptions) Diagnostics() options.TransportDiagnostics {
+	return options.TransportDiagnostics{
+		BrokerAddress: o.grpcOptions().URL,
+		Subscriptions: o.subscribeTopics(),
+	}
+}
+
+func (o *grpcAgentOptions) subscribeTopics() []string {
+	return []string{
+		// receiving the resources spec from sources with spec topic
+		o.grpcOptions().topic(replaceNth(SpecTopic, "+", o.clusterName, 2)),
+		// receiving the resources status resync request from sources with status resync topic
+		o.grpcOptions().topic(StatusResyncTopic),
+	}
+}
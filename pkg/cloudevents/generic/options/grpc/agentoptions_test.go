@@ -57,6 +57,27 @@ func TestAgentContext(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "register",
+			event: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: types.RegistrationDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              types.RegisterRequestAction,
+				}
+
+				evt := cloudevents.NewEvent()
+				evt.SetType(eventType.String())
+				evt.SetExtension("clustername", "cluster1")
+				return evt
+			}(),
+			expectedTopic: "sources/clusters/cluster1/specresync",
+			assertError: func(err error) {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+			},
+		},
 		{
 			name: "send status no original source",
 			event: func() cloudevents.Event {
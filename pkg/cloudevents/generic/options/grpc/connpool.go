@@ -0,0 +1,115 @@
+package grpc
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"k8s.io/klog/v2"
+)
+
+type clusterNameKey struct{}
+
+// withClusterName returns a context with the cluster name of the event that is being sent, the connection pool sends
+// the events of one cluster on the same connection.
+func withClusterName(ctx context.Context, clusterName string) context.Context {
+	return context.WithValue(ctx, clusterNameKey{}, clusterName)
+}
+
+func clusterNameFrom(ctx context.Context) (string, bool) {
+	clusterName, ok := ctx.Value(clusterNameKey{}).(string)
+	return clusterName, ok
+}
+
+// connPool is a pool of the connections to the same gRPC server. The requests for a cluster are distributed across the
+// connections by hashing the cluster name, so a slow cluster only blocks the clusters that share its connection. The
+// requests without a cluster name, e.g. the subscription and the broadcast resync requests, are sent on the first
+// connection, which is watched by the client to reconnect. The other connections are evicted and redialed once they
+// become unhealthy.
+type connPool struct {
+	sync.RWMutex
+	dial  func() (*grpc.ClientConn, error)
+	conns []*grpc.ClientConn
+}
+
+var _ grpc.ClientConnInterface = &connPool{}
+
+func newConnPool(size int, dial func() (*grpc.ClientConn, error)) (*connPool, error) {
+	pool := &connPool{dial: dial, conns: make([]*grpc.ClientConn, 0, size)}
+	for i := 0; i < size; i++ {
+		conn, err := dial()
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		pool.conns = append(pool.conns, conn)
+	}
+	return pool, nil
+}
+
+func (p *connPool) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	return p.pick(ctx).Invoke(ctx, method, args, reply, opts...)
+}
+
+func (p *connPool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string,
+	opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return p.pick(ctx).NewStream(ctx, desc, method, opts...)
+}
+
+// primary returns the first connection of the pool, it is never evicted.
+func (p *connPool) primary() *grpc.ClientConn {
+	return p.conns[0]
+}
+
+func (p *connPool) pick(ctx context.Context) *grpc.ClientConn {
+	p.RLock()
+	defer p.RUnlock()
+
+	clusterName, ok := clusterNameFrom(ctx)
+	if !ok {
+		return p.conns[0]
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(clusterName))
+	return p.conns[h.Sum32()%uint32(len(p.conns))]
+}
+
+// evictUnhealthy replaces the connections that are in the transient failure or shutdown state with new connections,
+// the primary connection is not evicted.
+func (p *connPool) evictUnhealthy() {
+	for i := 1; i < len(p.conns); i++ {
+		p.RLock()
+		conn := p.conns[i]
+		p.RUnlock()
+
+		state := conn.GetState()
+		if state != connectivity.TransientFailure && state != connectivity.Shutdown {
+			continue
+		}
+
+		newConn, err := p.dial()
+		if err != nil {
+			klog.Warningf("failed to redial the grpc connection %d, %v", i, err)
+			continue
+		}
+
+		klog.V(4).Infof("the grpc connection %d is %s, replace it with a new connection", i, state)
+		p.Lock()
+		p.conns[i] = newConn
+		p.Unlock()
+
+		conn.Close()
+	}
+}
+
+func (p *connPool) Close() {
+	p.Lock()
+	defer p.Unlock()
+
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+}
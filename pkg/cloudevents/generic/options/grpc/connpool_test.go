@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func newTestConnPool(t *testing.T, size int) *connPool {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	pool, err := newConnPool(size, func() (*grpc.ClientConn, error) {
+		return grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestConnPoolPick(t *testing.T) {
+	pool := newTestConnPool(t, 4)
+
+	if pool.pick(context.TODO()) != pool.primary() {
+		t.Errorf("expected the requests without cluster name are sent on the primary connection")
+	}
+
+	picked := map[*grpc.ClientConn]bool{}
+	for i := 0; i < 100; i++ {
+		ctx := withClusterName(context.TODO(), fmt.Sprintf("cluster%d", i))
+		conn := pool.pick(ctx)
+		if pool.pick(ctx) != conn {
+			t.Errorf("expected the requests of cluster%d are sent on the same connection", i)
+		}
+		picked[conn] = true
+	}
+
+	if len(picked) != 4 {
+		t.Errorf("expected the clusters are distributed across %d connections, but got %d", 4, len(picked))
+	}
+}
+
+func TestConnPoolEvictUnhealthy(t *testing.T) {
+	pool := newTestConnPool(t, 3)
+
+	primary := pool.primary()
+	unhealthy := pool.conns[1]
+	healthy := pool.conns[2]
+	unhealthy.Close()
+
+	pool.evictUnhealthy()
+
+	if pool.primary() != primary {
+		t.Errorf("expected the primary connection is not evicted")
+	}
+	if pool.conns[1] == unhealthy {
+		t.Errorf("expected the unhealthy connection is evicted")
+	}
+	if pool.conns[1].GetState() == connectivity.Shutdown {
+		t.Errorf("expected the evicted connection is replaced with a new connection")
+	}
+	if pool.conns[2] != healthy {
+		t.Errorf("expected the healthy connection is kept")
+	}
+}
@@ -0,0 +1,333 @@
+package logstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	// DefaultMaxBytes is the default maximum bytes of a log stream on an agent.
+	DefaultMaxBytes int64 = 1024 * 1024
+
+	// DefaultMaxDuration is the default maximum duration of a log stream on an agent.
+	DefaultMaxDuration = 5 * time.Minute
+)
+
+// LogOpener opens the log stream of a log request, the stream is closed when it is finished or the context is done.
+type LogOpener func(ctx context.Context, req *LogRequest) (io.ReadCloser, error)
+
+// Authorizer authorizes the log request of a source, the request is rejected if an error is returned. The source is
+// the source attribute of the request event, it is not authenticated by the agent, so the broker must authorize the
+// sources to publish to the LogRequestTopic.
+type Authorizer func(ctx context.Context, sourceID string, req *LogRequest) error
+
+// DenyAll rejects all the log requests, it is the default Authorizer of an agent.
+func DenyAll(ctx context.Context, sourceID string, req *LogRequest) error {
+	return fmt.Errorf("the log request of the source %s is not authorized", sourceID)
+}
+
+// PodLogOpener returns a LogOpener that opens the logs of the pods with the given kube client.
+func PodLogOpener(kubeClient kubernetes.Interface) LogOpener {
+	return func(ctx context.Context, req *LogRequest) (io.ReadCloser, error) {
+		options := &corev1.PodLogOptions{
+			Container: req.Container,
+			Follow:    req.Follow,
+		}
+		if req.TailLines > 0 {
+			options.TailLines = &req.TailLines
+		}
+		if req.SinceSeconds > 0 {
+			options.SinceSeconds = &req.SinceSeconds
+		}
+		if req.LimitBytes > 0 {
+			options.LimitBytes = &req.LimitBytes
+		}
+
+		return kubeClient.CoreV1().Pods(req.Namespace).GetLogs(req.PodName, options).Stream(ctx)
+	}
+}
+
+// Agent responds the log requests of the sources on a managed cluster, the logs are opened by a LogOpener and streamed
+// back to the sources in chunks.
+type Agent struct {
+	sync.Mutex
+	conn        grpc.ClientConnInterface
+	clusterName string
+	tenantID    string
+	opener      LogOpener
+	authorizer  Authorizer
+	maxBytes    int64
+	maxDuration time.Duration
+	client      cloudevents.Client
+	streams     map[string]*agentStream
+}
+
+// agentStream is a log stream that is being sent by an agent.
+type agentStream struct {
+	sync.Mutex
+	acked  int64
+	acks   chan struct{}
+	cancel context.CancelFunc
+}
+
+// NewAgent returns an Agent of the given cluster on the given gRPC connection, the logs are opened by the given
+// opener. All the requests are rejected until an Authorizer is set with WithAuthorizer.
+func NewAgent(conn grpc.ClientConnInterface, clusterName string, opener LogOpener) *Agent {
+	return &Agent{
+		conn:        conn,
+		clusterName: clusterName,
+		opener:      opener,
+		authorizer:  DenyAll,
+		maxBytes:    DefaultMaxBytes,
+		maxDuration: DefaultMaxDuration,
+		streams:     map[string]*agentStream{},
+	}
+}
+
+// WithTenantID sets the tenant of the agent, the topics are prefixed with the tenant segment.
+func (a *Agent) WithTenantID(tenantID string) *Agent {
+	a.tenantID = tenantID
+	return a
+}
+
+// WithAuthorizer sets the Authorizer of the log requests.
+func (a *Agent) WithAuthorizer(authorizer Authorizer) *Agent {
+	a.authorizer = authorizer
+	return a
+}
+
+// WithMaxBytes sets the maximum bytes of a log stream, the limit bytes of a request is bounded by it.
+func (a *Agent) WithMaxBytes(maxBytes int64) *Agent {
+	a.maxBytes = maxBytes
+	return a
+}
+
+// WithMaxDuration sets the maximum duration of a log stream, the stream is finished after the duration even if it
+// follows the logs.
+func (a *Agent) WithMaxDuration(maxDuration time.Duration) *Agent {
+	a.maxDuration = maxDuration
+	return a
+}
+
+// Run subscribes to the log requests of the cluster and responds them until the context is done.
+func (a *Agent) Run(ctx context.Context) error {
+	client, err := newClient(a.conn, topic(a.tenantID, LogRequestTopic, "+", a.clusterName))
+	if err != nil {
+		return err
+	}
+
+	a.Lock()
+	a.client = client
+	a.Unlock()
+
+	return client.StartReceiver(ctx, func(evt cloudevents.Event) {
+		a.receive(ctx, evt)
+	})
+}
+
+func (a *Agent) receive(ctx context.Context, evt cloudevents.Event) {
+	action, err := parseEvent(evt)
+	if err != nil {
+		klog.Warningf("ignore the log stream event %s, %v", evt.ID(), err)
+		return
+	}
+
+	switch action {
+	case LogRequestAction:
+		req := &LogRequest{}
+		if err := evt.DataAs(req); err != nil {
+			klog.Errorf("failed to unmarshal the log request %s, %v", evt.ID(), err)
+			return
+		}
+		a.handleRequest(ctx, evt.Source(), req)
+	case LogAckAction, LogCancelAction:
+		control := &LogControl{}
+		if err := evt.DataAs(control); err != nil {
+			klog.Errorf("failed to unmarshal the log %s %s, %v", action, evt.ID(), err)
+			return
+		}
+		a.handleControl(action, control)
+	default:
+		klog.Warningf("unsupported log stream action %s, ignore", action)
+	}
+}
+
+func (a *Agent) handleRequest(ctx context.Context, sourceID string, req *LogRequest) {
+	if err := a.authorizer(ctx, sourceID, req); err != nil {
+		klog.Warningf("the log request %s of the source %s is rejected, %v", req.RequestID, sourceID, err)
+		a.sendChunk(ctx, sourceID, &LogChunk{RequestID: req.RequestID, Sequence: 1, EOF: true, Error: err.Error()})
+		return
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	if _, ok := a.streams[req.RequestID]; ok {
+		klog.V(4).Infof("the log request %s is being responded, ignore", req.RequestID)
+		return
+	}
+
+	if req.LimitBytes <= 0 || req.LimitBytes > a.maxBytes {
+		req.LimitBytes = a.maxBytes
+	}
+	if req.Window <= 0 {
+		req.Window = DefaultWindow
+	}
+	req.Window = min(req.Window, MaxWindow)
+
+	streamCtx, cancel := context.WithTimeout(ctx, a.maxDuration)
+	stream := &agentStream{acks: make(chan struct{}, 1), cancel: cancel}
+	a.streams[req.RequestID] = stream
+
+	go func() {
+		defer func() {
+			cancel()
+
+			a.Lock()
+			delete(a.streams, req.RequestID)
+			a.Unlock()
+		}()
+
+		a.stream(ctx, streamCtx, sourceID, req, stream)
+	}()
+}
+
+func (a *Agent) handleControl(action types.EventAction, control *LogControl) {
+	a.Lock()
+	stream, ok := a.streams[control.RequestID]
+	a.Unlock()
+
+	if !ok {
+		klog.V(4).Infof("the log stream %s is not found, ignore the %s", control.RequestID, action)
+		return
+	}
+
+	if action == LogCancelAction {
+		stream.cancel()
+		return
+	}
+
+	stream.ack(control.Sequence)
+}
+
+// stream sends the logs of a request in chunks, the stream waits for the acknowledgements if the window of the
+// request is full. The final chunk is sent with the parent context, so it is sent after the stream is timed out.
+func (a *Agent) stream(ctx, streamCtx context.Context, sourceID string, req *LogRequest, stream *agentStream) {
+	reader, err := a.opener(streamCtx, req)
+	if err != nil {
+		a.sendChunk(ctx, sourceID, &LogChunk{RequestID: req.RequestID, Sequence: 1, EOF: true, Error: err.Error()})
+		return
+	}
+	defer reader.Close()
+
+	limited := io.LimitReader(reader, req.LimitBytes)
+	buf := make([]byte, chunkSize)
+	var sequence int64
+	for {
+		n, readErr := limited.Read(buf)
+		if n > 0 {
+			sequence++
+			if err := stream.wait(streamCtx, sequence-int64(req.Window)); err != nil {
+				a.finish(ctx, streamCtx, sourceID, req.RequestID, sequence)
+				return
+			}
+
+			if err := a.sendChunk(streamCtx, sourceID, &LogChunk{
+				RequestID: req.RequestID,
+				Sequence:  sequence,
+				Data:      buf[:n],
+			}); err != nil {
+				return
+			}
+		}
+
+		switch {
+		case readErr == nil:
+			continue
+		case errors.Is(readErr, io.EOF):
+			a.sendChunk(ctx, sourceID, &LogChunk{RequestID: req.RequestID, Sequence: sequence + 1, EOF: true})
+		case streamCtx.Err() != nil:
+			a.finish(ctx, streamCtx, sourceID, req.RequestID, sequence+1)
+		default:
+			a.sendChunk(ctx, sourceID, &LogChunk{
+				RequestID: req.RequestID,
+				Sequence:  sequence + 1,
+				EOF:       true,
+				Error:     readErr.Error(),
+			})
+		}
+		return
+	}
+}
+
+// finish sends the final chunk of a stream whose context is done, nothing is sent if the stream is canceled by the
+// source, and the stream is finished without an error if the max duration is reached.
+func (a *Agent) finish(ctx, streamCtx context.Context, sourceID, requestID string, sequence int64) {
+	if !errors.Is(streamCtx.Err(), context.DeadlineExceeded) {
+		return
+	}
+
+	a.sendChunk(ctx, sourceID, &LogChunk{RequestID: requestID, Sequence: sequence, EOF: true})
+}
+
+func (a *Agent) sendChunk(ctx context.Context, sourceID string, chunk *LogChunk) error {
+	evt, err := newEvent(a.clusterName, LogChunkAction, sourceID, a.clusterName, chunk.RequestID, chunk)
+	if err != nil {
+		klog.Errorf("failed to encode the log chunk, %v", err)
+		return err
+	}
+
+	a.Lock()
+	client := a.client
+	a.Unlock()
+
+	if err := send(ctx, client, topic(a.tenantID, LogResponseTopic, sourceID, a.clusterName), evt); err != nil {
+		klog.Errorf("failed to send the log chunk %d of %s, %v", chunk.Sequence, chunk.RequestID, err)
+		return err
+	}
+
+	return nil
+}
+
+// ack records the acknowledged sequence of the stream and wakes up the stream if it is waiting.
+func (s *agentStream) ack(sequence int64) {
+	s.Lock()
+	s.acked = max(s.acked, sequence)
+	s.Unlock()
+
+	select {
+	case s.acks <- struct{}{}:
+	default:
+	}
+}
+
+// wait blocks until the given sequence is acknowledged or the context is done.
+func (s *agentStream) wait(ctx context.Context, sequence int64) error {
+	for {
+		s.Lock()
+		acked := s.acked
+		s.Unlock()
+
+		if acked >= sequence {
+			return nil
+		}
+
+		select {
+		case <-s.acks:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package logstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// ErrClosed is returned when a log stream is read after it is closed.
+var ErrClosed = errors.New("the log stream is closed")
+
+// Client requests the log streams of the managed clusters for a source, e.g.
+//
+//	client := logstream.NewClient(conn, sourceID)
+//	go client.Run(ctx)
+//	stream, err := client.Stream(ctx, clusterName, logstream.LogRequest{Namespace: "ns", PodName: "pod", TailLines: 100})
+//	defer stream.Close()
+//	io.Copy(os.Stdout, stream)
+type Client struct {
+	sync.Mutex
+	conn     grpc.ClientConnInterface
+	sourceID string
+	tenantID string
+	client   cloudevents.Client
+	ready    chan struct{}
+	streams  map[string]*LogStream
+}
+
+// NewClient returns a Client of the given source on the given gRPC connection.
+func NewClient(conn grpc.ClientConnInterface, sourceID string) *Client {
+	return &Client{
+		conn:     conn,
+		sourceID: sourceID,
+		ready:    make(chan struct{}),
+		streams:  map[string]*LogStream{},
+	}
+}
+
+// WithTenantID sets the tenant of the client, the topics are prefixed with the tenant segment.
+func (c *Client) WithTenantID(tenantID string) *Client {
+	c.tenantID = tenantID
+	return c
+}
+
+// Run subscribes to the log chunks of the source and dispatches them to the log streams until the context is done.
+func (c *Client) Run(ctx context.Context) error {
+	client, err := newClient(c.conn, topic(c.tenantID, LogResponseTopic, c.sourceID, "+"))
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	c.client = client
+	close(c.ready)
+	c.Unlock()
+
+	return client.StartReceiver(ctx, c.receive)
+}
+
+// Stream requests a log stream from the given cluster, the returned LogStream must be closed by the caller. The
+// stream is not bounded by the client, so the context should have a deadline if the agent may be unavailable.
+func (c *Client) Stream(ctx context.Context, clusterName string, req LogRequest) (*LogStream, error) {
+	select {
+	case <-c.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if len(req.RequestID) == 0 {
+		req.RequestID = uuid.New().String()
+	}
+	if req.Window <= 0 {
+		req.Window = DefaultWindow
+	}
+	req.Window = min(req.Window, MaxWindow)
+
+	stream := &LogStream{
+		ctx:         ctx,
+		client:      c,
+		clusterName: clusterName,
+		requestID:   req.RequestID,
+		// the final chunk is sent without waiting for the window
+		chunks: make(chan *LogChunk, req.Window+1),
+		done:   make(chan struct{}),
+	}
+
+	c.Lock()
+	if _, ok := c.streams[req.RequestID]; ok {
+		c.Unlock()
+		return nil, fmt.Errorf("the log stream %s already exists", req.RequestID)
+	}
+	c.streams[req.RequestID] = stream
+	c.Unlock()
+
+	if err := c.send(ctx, LogRequestAction, clusterName, req.RequestID, req); err != nil {
+		c.forget(req.RequestID)
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+func (c *Client) receive(evt cloudevents.Event) {
+	action, err := parseEvent(evt)
+	if err != nil {
+		klog.Warningf("ignore the log stream event %s, %v", evt.ID(), err)
+		return
+	}
+
+	if action != LogChunkAction {
+		klog.Warningf("unsupported log stream action %s, ignore", action)
+		return
+	}
+
+	chunk := &LogChunk{}
+	if err := evt.DataAs(chunk); err != nil {
+		klog.Errorf("failed to unmarshal the log chunk %s, %v", evt.ID(), err)
+		return
+	}
+
+	c.Lock()
+	stream, ok := c.streams[chunk.RequestID]
+	c.Unlock()
+
+	if !ok {
+		klog.V(4).Infof("the log stream %s is not found, ignore the chunk %d", chunk.RequestID, chunk.Sequence)
+		return
+	}
+
+	select {
+	case stream.chunks <- chunk:
+	case <-stream.done:
+	default:
+		// the agent does not respect the window of the stream
+		klog.Warningf("the window of the log stream %s is exceeded, drop the chunk %d", chunk.RequestID, chunk.Sequence)
+	}
+}
+
+func (c *Client) send(ctx context.Context, action types.EventAction, clusterName, requestID string, data any) error {
+	evt, err := newEvent(c.sourceID, action, c.sourceID, clusterName, requestID, data)
+	if err != nil {
+		return err
+	}
+
+	return send(ctx, c.client, topic(c.tenantID, LogRequestTopic, c.sourceID, clusterName), evt)
+}
+
+func (c *Client) forget(requestID string) {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.streams, requestID)
+}
+
+// LogStream reads the logs of a log request, the received chunks are acknowledged after they are read.
+type LogStream struct {
+	ctx         context.Context
+	client      *Client
+	clusterName string
+	requestID   string
+	chunks      chan *LogChunk
+	done        chan struct{}
+	closeOnce   sync.Once
+
+	// the following fields are only accessed by the reader
+	sequence int64
+	data     []byte
+	err      error
+}
+
+var _ io.ReadCloser = &LogStream{}
+
+// RequestID returns the ID of the log stream.
+func (s *LogStream) RequestID() string {
+	return s.requestID
+}
+
+// Read reads the logs of the stream, it returns io.EOF after the last chunk is read, or the error of the agent if the
+// stream is failed on the agent.
+func (s *LogStream) Read(p []byte) (int, error) {
+	for len(s.data) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+
+		select {
+		case chunk := <-s.chunks:
+			s.err = s.next(chunk)
+		case <-s.done:
+			s.err = ErrClosed
+		case <-s.ctx.Done():
+			s.err = s.ctx.Err()
+		}
+	}
+
+	n := copy(p, s.data)
+	s.data = s.data[n:]
+	return n, nil
+}
+
+// next handles a received chunk, the chunk is acknowledged unless it is the last chunk.
+func (s *LogStream) next(chunk *LogChunk) error {
+	if chunk.Sequence != s.sequence+1 {
+		return fmt.Errorf("the log stream %s expects the chunk %d, but got %d", s.requestID, s.sequence+1, chunk.Sequence)
+	}
+	s.sequence = chunk.Sequence
+	s.data = chunk.Data
+
+	if chunk.EOF {
+		s.client.forget(s.requestID)
+
+		if len(chunk.Error) != 0 {
+			return fmt.Errorf("the log stream %s is failed, %s", s.requestID, chunk.Error)
+		}
+		return io.EOF
+	}
+
+	if err := s.client.send(s.ctx, LogAckAction, s.clusterName, s.requestID,
+		&LogControl{RequestID: s.requestID, Sequence: chunk.Sequence}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Close closes the stream, the agent is notified to stop the stream if it is not finished.
+func (s *LogStream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+
+		s.client.Lock()
+		_, running := s.client.streams[s.requestID]
+		s.client.Unlock()
+
+		if !running {
+			return
+		}
+
+		s.client.forget(s.requestID)
+		err = s.client.send(context.WithoutCancel(s.ctx), LogCancelAction, s.clusterName, s.requestID,
+			&LogControl{RequestID: s.requestID})
+	})
+	return err
+}
@@ -0,0 +1,192 @@
+// Package logstream is a request/response subprotocol on the gRPC transport, a source requests a bounded log stream of
+// a container from an agent, and the agent streams the logs back in chunks over the existing gRPC connection to the
+// broker, so the logs of the managed clusters that have no ingress can be read from the hub.
+//
+// The requests and the flow control messages of a source are published to the LogRequestTopic of the cluster, and the
+// log chunks are published to the LogResponseTopic of the source. The agent sends at most the window of the request of
+// the chunks that are not acknowledged by the source, so a slow reader on the source throttles the agent instead of
+// the broker. The agent authorizes each request with an Authorizer, and bounds the bytes and the duration of a stream.
+package logstream
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventsclient "github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"google.golang.org/grpc"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protocol"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	// LogRequestTopic is a pubsub topic for the log stream requests and the flow control messages of the sources.
+	LogRequestTopic = "sources/+/clusters/+/logrequest"
+
+	// LogResponseTopic is a pubsub topic for the log chunks of the agents.
+	LogResponseTopic = "sources/+/clusters/+/logresponse"
+)
+
+const (
+	// LogRequestAction is the action of a log stream request.
+	LogRequestAction types.EventAction = "log_request"
+
+	// LogAckAction is the action of an acknowledgement of the received log chunks.
+	LogAckAction types.EventAction = "log_ack"
+
+	// LogCancelAction is the action of a cancellation of a log stream.
+	LogCancelAction types.EventAction = "log_cancel"
+
+	// LogChunkAction is the action of a log chunk.
+	LogChunkAction types.EventAction = "log_chunk"
+)
+
+const (
+	// DefaultWindow is the default number of the log chunks that are not acknowledged by the source.
+	DefaultWindow int32 = 8
+
+	// MaxWindow is the maximum number of the log chunks that are not acknowledged by the source.
+	MaxWindow int32 = 64
+
+	// chunkSize is the maximum size of the data of a log chunk.
+	chunkSize = 32 * 1024
+)
+
+var LogStreamDataType = types.CloudEventsDataType{
+	Group:    "io.open-cluster-management.logs",
+	Version:  "v1alpha1",
+	Resource: "logstreams",
+}
+
+// LogRequest is the data of a log stream request.
+type LogRequest struct {
+	// RequestID is the unique ID of the log stream, it is set by the client.
+	RequestID string `json:"requestID"`
+
+	// Namespace is the namespace of the pod.
+	Namespace string `json:"namespace"`
+
+	// PodName is the name of the pod.
+	PodName string `json:"podName"`
+
+	// Container is the container of the pod, it can be empty if the pod has only one container.
+	Container string `json:"container,omitempty"`
+
+	// TailLines is the number of the lines from the end of the logs, all the logs are returned if it is not set.
+	TailLines int64 `json:"tailLines,omitempty"`
+
+	// SinceSeconds is the relative time in seconds of the oldest logs.
+	SinceSeconds int64 `json:"sinceSeconds,omitempty"`
+
+	// LimitBytes is the maximum bytes of the logs, it is bounded by the max bytes of the agent.
+	LimitBytes int64 `json:"limitBytes,omitempty"`
+
+	// Follow streams the new logs until the max duration of the agent is reached or the stream is canceled.
+	Follow bool `json:"follow,omitempty"`
+
+	// Window is the number of the log chunks that the agent sends without the acknowledgements, if it's less than or
+	// equal to zero, the DefaultWindow is used. It is bounded by the MaxWindow.
+	Window int32 `json:"window,omitempty"`
+}
+
+// LogControl is the data of an acknowledgement or a cancellation of a log stream.
+type LogControl struct {
+	// RequestID is the ID of the log stream.
+	RequestID string `json:"requestID"`
+
+	// Sequence is the sequence of the last log chunk that is received by the source, the acknowledgements are
+	// cumulative.
+	Sequence int64 `json:"sequence,omitempty"`
+}
+
+// LogChunk is the data of a log chunk.
+type LogChunk struct {
+	// RequestID is the ID of the log stream.
+	RequestID string `json:"requestID"`
+
+	// Sequence is the sequence of the chunk in the log stream, the sequences start from 1.
+	Sequence int64 `json:"sequence"`
+
+	// Data is the logs of the chunk.
+	Data []byte `json:"data,omitempty"`
+
+	// EOF is true if the chunk is the last chunk of the log stream.
+	EOF bool `json:"eof,omitempty"`
+
+	// Error is the reason if the log stream is failed, e.g. the request is forbidden, it is set on the last chunk.
+	Error string `json:"error,omitempty"`
+}
+
+func eventType(action types.EventAction) types.CloudEventsType {
+	subResource := types.SubResourceSpec
+	if action == LogChunkAction {
+		subResource = types.SubResourceStatus
+	}
+
+	return types.CloudEventsType{
+		CloudEventsDataType: LogStreamDataType,
+		SubResource:         subResource,
+		Action:              action,
+	}
+}
+
+// newEvent returns a cloudevent of the log stream between the given source and cluster.
+func newEvent(source string, action types.EventAction, sourceID, clusterName, requestID string, data any) (*cloudevents.Event, error) {
+	evt := types.NewEventBuilder(source, eventType(action)).
+		WithResourceID(requestID).
+		WithClusterName(clusterName).
+		WithOriginalSource(sourceID).
+		NewEvent()
+
+	if err := evt.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return nil, fmt.Errorf("failed to encode the %s of the log stream %s, %v", action, requestID, err)
+	}
+
+	return &evt, nil
+}
+
+// parseEvent returns the action of a received cloudevent of the log streams.
+func parseEvent(evt cloudevents.Event) (types.EventAction, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return "", fmt.Errorf("failed to parse cloud event type %s, %v", evt.Type(), err)
+	}
+
+	if eventType.CloudEventsDataType != LogStreamDataType {
+		return "", fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	return eventType.Action, nil
+}
+
+// topic returns the topic of the given source and cluster in the given tenant.
+func topic(tenantID, topic, sourceID, clusterName string) string {
+	levels := strings.SplitN(topic, "+", 3)
+	return types.TenantTopic(tenantID, levels[0]+sourceID+levels[1]+clusterName+levels[2])
+}
+
+// newClient returns a cloudevents client on the given gRPC connection that subscribes to the given topic.
+func newClient(conn grpc.ClientConnInterface, subscribeTopic string) (cloudevents.Client, error) {
+	p, err := protocol.NewProtocol(conn,
+		protocol.WithPublishOption(&protocol.PublishOption{}),
+		protocol.WithSubscribeOption(&protocol.SubscribeOption{Topics: []string{subscribeTopic}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// the events of a stream are handled in order
+	return cloudevents.NewClient(p, cloudeventsclient.WithBlockingCallback())
+}
+
+// send publishes a cloudevent to the given topic.
+func send(ctx context.Context, client cloudevents.Client, topic string, evt *cloudevents.Event) error {
+	if result := client.Send(cecontext.WithTopic(ctx, topic), *evt); cloudevents.IsUndelivered(result) {
+		return fmt.Errorf("failed to send the event %s to %s, %v", evt.Type(), topic, result)
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package logstream
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"open-cluster-management.io/sdk-go/pkg/testing/broker"
+)
+
+const (
+	testSourceID    = "source1"
+	testClusterName = "cluster1"
+	probePod        = "probe"
+)
+
+// testOpener returns the logs of the pods in the logs, the pod2 is not found, and the logs of the other pods are
+// followed until the context is done, then the done channel is closed.
+type testOpener struct {
+	logs map[string][]byte
+	done chan struct{}
+}
+
+func (o *testOpener) open(ctx context.Context, req *LogRequest) (io.ReadCloser, error) {
+	if req.PodName == probePod {
+		return io.NopCloser(strings.NewReader("ping")), nil
+	}
+
+	if logs, ok := o.logs[req.PodName]; ok {
+		return io.NopCloser(bytes.NewReader(logs)), nil
+	}
+
+	if req.PodName == "pod2" {
+		return nil, fmt.Errorf("pods %q not found", req.PodName)
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		<-ctx.Done()
+		writer.CloseWithError(ctx.Err())
+		close(o.done)
+	}()
+	return reader, nil
+}
+
+func allowAll(ctx context.Context, sourceID string, req *LogRequest) error {
+	return nil
+}
+
+func newTestLogs(size int) []byte {
+	logs := &bytes.Buffer{}
+	for i := 0; logs.Len() < size; i++ {
+		fmt.Fprintf(logs, "line %d\n", i)
+	}
+	return logs.Bytes()[:size]
+}
+
+// startTestClient starts an agent with the given authorizer and a client, it waits until both of them subscribe to
+// the broker.
+func startTestClient(ctx context.Context, t *testing.T, opener *testOpener, authorizer Authorizer) *Client {
+	grpcBroker := broker.StartGRPCBroker(t)
+	conn, err := grpc.Dial(grpcBroker.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	agent := NewAgent(conn, testClusterName, opener.open).
+		WithMaxBytes(100 * 1024).
+		WithMaxDuration(500 * time.Millisecond)
+	if authorizer != nil {
+		agent = agent.WithAuthorizer(authorizer)
+	}
+	go func() {
+		_ = agent.Run(ctx)
+	}()
+
+	client := NewClient(conn, testSourceID)
+	go func() {
+		_ = client.Run(ctx)
+	}()
+
+	// the agent and the client may not subscribe the topics yet, probe the agent until the logs are received
+	err = wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 10*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			probeCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+			defer cancel()
+
+			stream, err := client.Stream(probeCtx, testClusterName, LogRequest{PodName: probePod})
+			if err != nil {
+				return false, nil
+			}
+			defer stream.Close()
+
+			_, err = io.ReadAll(stream)
+			return err == nil || !strings.Contains(err.Error(), "context deadline exceeded"), nil
+		})
+	if err != nil {
+		t.Fatalf("failed to probe the agent, %v", err)
+	}
+
+	return client
+}
+
+func TestLogStream(t *testing.T) {
+	logs := newTestLogs(3*chunkSize + 100)
+	largeLogs := newTestLogs(200 * 1024)
+
+	cases := []struct {
+		name          string
+		authorizer    Authorizer
+		request       LogRequest
+		expectedLogs  []byte
+		expectedError string
+	}{
+		{
+			name:          "not authorized",
+			request:       LogRequest{Namespace: "ns1", PodName: "pod1"},
+			expectedError: "the log request of the source source1 is not authorized",
+		},
+		{
+			name: "forbidden namespace",
+			authorizer: func(ctx context.Context, sourceID string, req *LogRequest) error {
+				if req.Namespace != "ns1" && req.PodName != probePod {
+					return fmt.Errorf("the namespace %s is forbidden", req.Namespace)
+				}
+				return nil
+			},
+			request:       LogRequest{Namespace: "ns2", PodName: "pod1"},
+			expectedError: "the namespace ns2 is forbidden",
+		},
+		{
+			name:         "logs with a small window",
+			authorizer:   allowAll,
+			request:      LogRequest{Namespace: "ns1", PodName: "pod1", Window: 1},
+			expectedLogs: logs,
+		},
+		{
+			name:         "logs with limit bytes",
+			authorizer:   allowAll,
+			request:      LogRequest{Namespace: "ns1", PodName: "pod1", LimitBytes: 100},
+			expectedLogs: logs[:100],
+		},
+		{
+			name:         "logs are bounded by the max bytes",
+			authorizer:   allowAll,
+			request:      LogRequest{Namespace: "ns1", PodName: "pod4", LimitBytes: 1024 * 1024},
+			expectedLogs: largeLogs[:100*1024],
+		},
+		{
+			name:          "open logs failed",
+			authorizer:    allowAll,
+			request:       LogRequest{Namespace: "ns1", PodName: "pod2"},
+			expectedError: "pods \"pod2\" not found",
+		},
+		{
+			name:         "follow logs until the max duration",
+			authorizer:   allowAll,
+			request:      LogRequest{Namespace: "ns1", PodName: "pod3", Follow: true},
+			expectedLogs: []byte{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			authorizer := c.authorizer
+			if authorizer == nil {
+				// the probe is authorized to start the test
+				authorizer = func(ctx context.Context, sourceID string, req *LogRequest) error {
+					if req.PodName == probePod {
+						return nil
+					}
+					return DenyAll(ctx, sourceID, req)
+				}
+			}
+
+			opener := &testOpener{logs: map[string][]byte{"pod1": logs, "pod4": largeLogs}, done: make(chan struct{})}
+			client := startTestClient(ctx, t, opener, authorizer)
+
+			stream, err := client.Stream(ctx, testClusterName, c.request)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			defer stream.Close()
+
+			actual, err := io.ReadAll(stream)
+			if len(c.expectedError) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.expectedError) {
+					t.Errorf("expected error %q, but got %v", c.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(c.expectedLogs, actual) {
+				t.Errorf("expected %d bytes of logs, but got %d", len(c.expectedLogs), len(actual))
+			}
+		})
+	}
+}
+
+func TestCancelLogStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	opener := &testOpener{done: make(chan struct{})}
+	client := startTestClient(ctx, t, opener, allowAll)
+
+	stream, err := client.Stream(ctx, testClusterName, LogRequest{Namespace: "ns1", PodName: "pod1", Follow: true})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// wait until the agent opens the logs
+	time.Sleep(100 * time.Millisecond)
+	if err := stream.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	select {
+	case <-opener.done:
+	case <-time.After(400 * time.Millisecond):
+		t.Errorf("expected the log stream is canceled before the max duration")
+	}
+
+	if _, err := stream.Read(make([]byte, 10)); err != ErrClosed {
+		t.Errorf("expected %v, but got %v", ErrClosed, err)
+	}
+}
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protocol"
+)
+
+type grpcObserverOptions struct {
+	GRPCOptions
+	errorChan chan error
+}
+
+// NewObserverOptions returns the options of an observer, the observer subscribes to the spec and status topics of all
+// sources and clusters, the server is expected to authorize the observer to read these topics.
+func NewObserverOptions(grpcOptions *GRPCOptions, observerID string) *options.CloudEventsObserverOptions {
+	return &options.CloudEventsObserverOptions{
+		CloudEventsOptions: &grpcObserverOptions{
+			GRPCOptions: *grpcOptions,
+			errorChan:   make(chan error),
+		},
+		ObserverID: observerID,
+		TenantID:   grpcOptions.TenantID,
+	}
+}
+
+func (o *grpcObserverOptions) WithContext(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+	return nil, fmt.Errorf("the observer does not send events")
+}
+
+func (o *grpcObserverOptions) Client(ctx context.Context) (cloudevents.Client, error) {
+	grpcOptions := o.GRPCOptions
+
+	receiver, err := grpcOptions.GetCloudEventsClient(
+		ctx,
+		func(err error) {
+			o.errorChan <- err
+		},
+		protocol.WithSubscribeOption(&protocol.SubscribeOption{
+			Topics: o.subscribeTopics(),
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return receiver, nil
+}
+
+func (o *grpcObserverOptions) ErrorChan() <-chan error {
+	return o.errorChan
+}
+
+// Diagnostics returns the address of the gRPC server and the subscribed topics.
+func (o *grpcObserverOptions) Diagnostics() options.TransportDiagnostics {
+	return options.TransportDiagnostics{
+		BrokerAddress: o.URL,
+		Subscriptions: o.subscribeTopics(),
+	}
+}
+
+func (o *grpcObserverOptions) subscribeTopics() []string {
+	return []string{
+		// receiving the resources spec of all sources and clusters
+		o.topic(SpecTopic),
+		// receiving the resources status of all sources and clusters
+		o.topic(StatusTopic),
+	}
+}
@@ -4,20 +4,29 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"gopkg.in/yaml.v2"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cloudeventsclient "github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
 
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protocol"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
 const (
@@ -34,17 +43,61 @@ const (
 	StatusResyncTopic = "sources/+/clusters/statusresync"
 )
 
+// poolHealthCheckInterval is the interval to evict the unhealthy connections of the connection pool.
+const poolHealthCheckInterval = 5 * time.Second
+
 // GRPCOptions holds the options that are used to build gRPC client.
 type GRPCOptions struct {
 	URL            string
 	CAFile         string
 	ClientCertFile string
 	ClientKeyFile  string
+	TokenFile      string
+
+	// LoadBalancingPolicy is the load balancing policy of the connection, e.g. round_robin.
+	LoadBalancingPolicy string
+
+	// ServiceConfig is the default service config of the connection in JSON, e.g. the retry policy or the hedging
+	// policy of the methods.
+	ServiceConfig string
+
+	// ContentMode is the CloudEvents content mode to publish the events, the events are published in the binary mode if
+	// it is not set.
+	ContentMode options.ContentMode
+
+	// ClientConn is an optional pre-built connection to the gRPC server, e.g. an in-process connection in the tests. If
+	// it is set, the URL and the credentials are ignored, and the connection is not closed by the client.
+	ClientConn *grpc.ClientConn
+
+	// DialOptions are the additional options to dial the gRPC server, e.g. the client interceptors. They are appended
+	// after the transport credentials options built from the config.
+	DialOptions []grpc.DialOption
+
+	// TenantID is the tenant of the client, the topics are prefixed with the tenant segment, so the clients of the
+	// different tenants can share a broker.
+	TenantID string
+
+	// ConnectionPoolSize is the number of the connections to the gRPC server, the events of a cluster are sent on one of
+	// the connections by hashing the cluster name, so a source that serves many clusters is not blocked by one slow
+	// connection. The unhealthy connections are evicted and redialed. If it's less than or equal to one, or the
+	// ClientConn is set, only one connection is used.
+	ConnectionPoolSize int
+
+	// Clock is optional, it is the clock of the connection checks and the connection pool health checks, so the tests
+	// can fast-forward the checks with a fake clock. By default, the real clock is used.
+	Clock clock.WithTicker
+
+	// PublishTimeout is the deadline of the publish calls, a publish that is not responded by the server in time fails
+	// with the options.PublishTimeoutError. The publishes are only bounded by the deadlines of their contexts if it is
+	// zero.
+	PublishTimeout time.Duration
 }
 
 // GRPCConfig holds the information needed to build connect to gRPC server as a given user.
 type GRPCConfig struct {
-	// URL is the address of the gRPC server (host:port).
+	// URL is the address of the gRPC server (host:port), or the path of a Unix domain socket with the unix scheme
+	// (unix:///var/run/broker.sock). The TLS certificate of the server on a Unix domain socket is verified with the
+	// localhost server name.
 	URL string `json:"url" yaml:"url"`
 	// CAFile is the file path to a cert file for the gRPC server certificate authority.
 	CAFile string `json:"caFile,omitempty" yaml:"caFile,omitempty"`
@@ -52,9 +105,45 @@ type GRPCConfig struct {
 	ClientCertFile string `json:"clientCertFile,omitempty" yaml:"clientCertFile,omitempty"`
 	// ClientKeyFile is the file path to a client key file for TLS.
 	ClientKeyFile string `json:"clientKeyFile,omitempty" yaml:"clientKeyFile,omitempty"`
+	// TokenFile is the file path to a token file for authentication, the token is sent as a bearer token.
+	TokenFile string `json:"tokenFile,omitempty" yaml:"tokenFile,omitempty"`
+	// LoadBalancingPolicy is the load balancing policy to distribute the requests across the resolved addresses of the
+	// gRPC server, e.g. pick_first or round_robin. If it is set and the url has no scheme, the url is resolved with DNS.
+	LoadBalancingPolicy string `json:"loadBalancingPolicy,omitempty" yaml:"loadBalancingPolicy,omitempty"`
+	// ServiceConfig is the default gRPC service config in JSON, e.g. the retry policy or the hedging policy of the
+	// methods. The loadBalancingPolicy takes precedence over the load balancing config in it.
+	ServiceConfig string `json:"serviceConfig,omitempty" yaml:"serviceConfig,omitempty"`
+	// ContentMode is the CloudEvents content mode (binary or structured) to publish the events, by default is binary.
+	ContentMode options.ContentMode `json:"contentMode,omitempty" yaml:"contentMode,omitempty"`
+	// TenantID is the tenant of the client, the topics are prefixed with `tenants/<tenantID>/`, and the events of the
+	// other tenants are rejected, so the hubs of the different tenants can share a broker.
+	TenantID string `json:"tenantID,omitempty" yaml:"tenantID,omitempty"`
+	// ConnectionPoolSize is the number of the connections that a source uses to send the events to the gRPC server, the
+	// events of a cluster are always sent on the same connection, by default only one connection is used.
+	ConnectionPoolSize int `json:"connectionPoolSize,omitempty" yaml:"connectionPoolSize,omitempty"`
+	// PublishTimeout is the deadline of the publish calls, by default the publishes are only bounded by the deadlines
+	// of their contexts.
+	PublishTimeout *time.Duration `json:"publishTimeout,omitempty" yaml:"publishTimeout,omitempty"`
+}
+
+// envOverrides maps the environment variables to the config fields that they override.
+func (c *GRPCConfig) envOverrides() map[string]*string {
+	return map[string]*string{
+		"GRPC_URL":              &c.URL,
+		"GRPC_CA_FILE":          &c.CAFile,
+		"GRPC_CLIENT_CERT_FILE": &c.ClientCertFile,
+		"GRPC_CLIENT_KEY_FILE":  &c.ClientKeyFile,
+		"GRPC_TOKEN_FILE":       &c.TokenFile,
+	}
 }
 
-// BuildGRPCOptionsFromFlags builds configs from a config filepath.
+// BuildGRPCOptionsFromFlags builds configs from a config filepath. The config fields can be overridden by the
+// following environment variables:
+//   - GRPC_URL
+//   - GRPC_CA_FILE
+//   - GRPC_CLIENT_CERT_FILE
+//   - GRPC_CLIENT_KEY_FILE
+//   - GRPC_TOKEN_FILE
 func BuildGRPCOptionsFromFlags(configPath string) (*GRPCOptions, error) {
 	configData, err := os.ReadFile(configPath)
 	if err != nil {
@@ -66,6 +155,17 @@ func BuildGRPCOptionsFromFlags(configPath string) (*GRPCOptions, error) {
 		return nil, err
 	}
 
+	for env, field := range config.envOverrides() {
+		if value, ok := os.LookupEnv(env); ok {
+			*field = value
+		}
+	}
+
+	return BuildGRPCOptionsFromConfig(config)
+}
+
+// BuildGRPCOptionsFromConfig validates the given config and builds the gRPC options from it.
+func BuildGRPCOptionsFromConfig(config *GRPCConfig) (*GRPCOptions, error) {
 	if config.URL == "" {
 		return nil, fmt.Errorf("url is required")
 	}
@@ -78,11 +178,46 @@ func BuildGRPCOptionsFromFlags(configPath string) (*GRPCOptions, error) {
 		return nil, fmt.Errorf("setting clientCertFile and clientKeyFile requires caFile")
 	}
 
+	if config.LoadBalancingPolicy != "" && balancer.Get(config.LoadBalancingPolicy) == nil {
+		return nil, fmt.Errorf("unsupported load balancing policy %s", config.LoadBalancingPolicy)
+	}
+
+	if config.ServiceConfig != "" && !json.Valid([]byte(config.ServiceConfig)) {
+		return nil, fmt.Errorf("serviceConfig must be a valid JSON")
+	}
+
+	if err := options.ValidateContentMode(config.ContentMode); err != nil {
+		return nil, err
+	}
+
+	if err := types.ValidateTenantID(config.TenantID); err != nil {
+		return nil, err
+	}
+
+	if config.ConnectionPoolSize < 0 {
+		return nil, fmt.Errorf("connectionPoolSize must not be negative")
+	}
+
+	var publishTimeout time.Duration
+	if config.PublishTimeout != nil {
+		if *config.PublishTimeout <= 0 {
+			return nil, fmt.Errorf("invalid publishTimeout %v", *config.PublishTimeout)
+		}
+		publishTimeout = *config.PublishTimeout
+	}
+
 	return &GRPCOptions{
-		URL:            config.URL,
-		CAFile:         config.CAFile,
-		ClientCertFile: config.ClientCertFile,
-		ClientKeyFile:  config.ClientKeyFile,
+		URL:                 config.URL,
+		CAFile:              config.CAFile,
+		ClientCertFile:      config.ClientCertFile,
+		ClientKeyFile:       config.ClientKeyFile,
+		TokenFile:           config.TokenFile,
+		LoadBalancingPolicy: config.LoadBalancingPolicy,
+		ServiceConfig:       config.ServiceConfig,
+		ContentMode:         config.ContentMode,
+		TenantID:            config.TenantID,
+		ConnectionPoolSize:  config.ConnectionPoolSize,
+		PublishTimeout:      publishTimeout,
 	}, nil
 }
 
@@ -90,7 +225,29 @@ func NewGRPCOptions() *GRPCOptions {
 	return &GRPCOptions{}
 }
 
+// WithDialOptions adds the additional options to dial the gRPC server.
+func (o *GRPCOptions) WithDialOptions(opts ...grpc.DialOption) *GRPCOptions {
+	o.DialOptions = append(o.DialOptions, opts...)
+	return o
+}
+
+// WithUnaryInterceptor adds the unary client interceptors, e.g. to inject the auth headers or the request IDs, the
+// interceptors are chained in the order they are added.
+func (o *GRPCOptions) WithUnaryInterceptor(interceptors ...grpc.UnaryClientInterceptor) *GRPCOptions {
+	return o.WithDialOptions(grpc.WithChainUnaryInterceptor(interceptors...))
+}
+
+// WithStreamInterceptor adds the stream client interceptors, e.g. to record the metrics or the traces of the
+// subscriptions, the interceptors are chained in the order they are added.
+func (o *GRPCOptions) WithStreamInterceptor(interceptors ...grpc.StreamClientInterceptor) *GRPCOptions {
+	return o.WithDialOptions(grpc.WithChainStreamInterceptor(interceptors...))
+}
+
 func (o *GRPCOptions) GetGRPCClientConn() (*grpc.ClientConn, error) {
+	if o.ClientConn != nil {
+		return o.ClientConn, nil
+	}
+
 	if len(o.CAFile) != 0 {
 		certPool, err := x509.SystemCertPool()
 		if err != nil {
@@ -118,7 +275,12 @@ func (o *GRPCOptions) GetGRPCClientConn() (*grpc.ClientConn, error) {
 			MaxVersion:   tls.VersionTLS13,
 		}
 
-		conn, err := grpc.Dial(o.URL, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+		dialOptions, err := o.dialOptions(credentials.NewTLS(tlsConfig), true)
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := grpc.Dial(o.target(), dialOptions...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to grpc server %s, %v", o.URL, err)
 		}
@@ -126,7 +288,12 @@ func (o *GRPCOptions) GetGRPCClientConn() (*grpc.ClientConn, error) {
 		return conn, nil
 	}
 
-	conn, err := grpc.Dial(o.URL, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	dialOptions, err := o.dialOptions(insecure.NewCredentials(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(o.target(), dialOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to grpc server %s, %v", o.URL, err)
 	}
@@ -134,25 +301,152 @@ func (o *GRPCOptions) GetGRPCClientConn() (*grpc.ClientConn, error) {
 	return conn, nil
 }
 
-func (o *GRPCOptions) GetCloudEventsClient(ctx context.Context, errorHandler func(error), clientOpts ...protocol.Option) (cloudevents.Client, error) {
-	conn, err := o.GetGRPCClientConn()
+// topic returns the given topic of the tenant of the client.
+func (o GRPCOptions) topic(topic string) string {
+	return types.TenantTopic(o.TenantID, topic)
+}
+
+// target returns the dial target of the gRPC server, the url without scheme is resolved with DNS if a load balancing
+// policy is set, so the requests can be distributed across all the addresses of the server.
+func (o *GRPCOptions) target() string {
+	if o.LoadBalancingPolicy != "" && !o.hasScheme() {
+		return "dns:///" + o.URL
+	}
+	return o.URL
+}
+
+func (o *GRPCOptions) hasScheme() bool {
+	return strings.HasPrefix(o.URL, "unix:") || strings.Contains(o.URL, "://")
+}
+
+// serviceConfig returns the default service config of the connection, the load balancing policy overrides the load
+// balancing config in the given service config.
+func (o *GRPCOptions) serviceConfig() (string, error) {
+	if o.LoadBalancingPolicy == "" {
+		return o.ServiceConfig, nil
+	}
+
+	serviceConfig := map[string]interface{}{}
+	if o.ServiceConfig != "" {
+		if err := json.Unmarshal([]byte(o.ServiceConfig), &serviceConfig); err != nil {
+			return "", fmt.Errorf("failed to parse the service config, %v", err)
+		}
+	}
+
+	serviceConfig["loadBalancingConfig"] = []map[string]interface{}{{o.LoadBalancingPolicy: map[string]interface{}{}}}
+	data, err := json.Marshal(serviceConfig)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (o *GRPCOptions) dialOptions(
+	transportCredentials credentials.TransportCredentials, secure bool) ([]grpc.DialOption, error) {
+	// the user agent is sent with the metadata of the calls, so the servers can audit the versions of the clients
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithUserAgent(options.UserAgent()),
+	}
+
+	serviceConfig, err := o.serviceConfig()
 	if err != nil {
 		return nil, err
 	}
+	if serviceConfig != "" {
+		dialOptions = append(dialOptions, grpc.WithDefaultServiceConfig(serviceConfig))
+	}
+
+	if len(o.TokenFile) != 0 {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(&tokenCredentials{
+			tokenFile: o.TokenFile,
+			secure:    secure,
+		}))
+	}
+	return append(dialOptions, o.DialOptions...), nil
+}
+
+// tokenCredentials sends the token in the token file as a bearer token, the token file is read for each request, so
+// the rotated token can be used without reconnecting.
+type tokenCredentials struct {
+	tokenFile string
+	secure    bool
+}
+
+func (c *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := os.ReadFile(c.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file %s, %v", c.tokenFile, err)
+	}
+
+	return map[string]string{
+		"authorization": "Bearer " + strings.TrimSpace(string(token)),
+	}, nil
+}
+
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return c.secure
+}
+
+// clock returns the clock of the connection checks, it is the real clock if the Clock is not set.
+func (o *GRPCOptions) clock() clock.WithTicker {
+	if o.Clock == nil {
+		return clock.RealClock{}
+	}
+	return o.Clock
+}
+
+func (o *GRPCOptions) GetCloudEventsClient(ctx context.Context, errorHandler func(error), clientOpts ...protocol.Option) (cloudevents.Client, error) {
+	var clientConn grpc.ClientConnInterface
+	var conn *grpc.ClientConn
+	var closeConn func()
+	// the evict chan is nil if the connection pool is not used
+	var evictChan <-chan time.Time
+
+	if o.ConnectionPoolSize > 1 && o.ClientConn == nil {
+		pool, err := newConnPool(o.ConnectionPoolSize, o.GetGRPCClientConn)
+		if err != nil {
+			return nil, err
+		}
+
+		evictTicker := o.clock().NewTicker(poolHealthCheckInterval)
+		clientConn, conn, evictChan = pool, pool.primary(), evictTicker.C()
+		closeConn = func() {
+			evictTicker.Stop()
+			pool.Close()
+		}
+	} else {
+		var err error
+		conn, err = o.GetGRPCClientConn()
+		if err != nil {
+			return nil, err
+		}
+
+		// the pre-built connection is owned by the caller, it is not closed by the client
+		clientConn = conn
+		closeConn = func() {
+			if o.ClientConn == nil {
+				conn.Close()
+			}
+		}
+	}
 
 	// Periodically (every 100ms) check the connection status and reconnect if necessary.
 	go func() {
-		ticker := time.NewTicker(100 * time.Millisecond)
+		ticker := o.clock().NewTicker(100 * time.Millisecond)
 		for {
 			select {
 			case <-ctx.Done():
 				ticker.Stop()
-				conn.Close()
-			case <-ticker.C:
+				closeConn()
+				return
+			case <-evictChan:
+				clientConn.(*connPool).evictUnhealthy()
+			case <-ticker.C():
 				if conn.GetState() == connectivity.TransientFailure {
 					errorHandler(fmt.Errorf("grpc connection is disconnected"))
 					ticker.Stop()
-					conn.Close()
+					closeConn()
 					return // exit the goroutine as the error handler function will handle the reconnection.
 				}
 			}
@@ -161,12 +455,27 @@ func (o *GRPCOptions) GetCloudEventsClient(ctx context.Context, errorHandler fun
 
 	opts := []protocol.Option{}
 	opts = append(opts, clientOpts...)
-	p, err := protocol.NewProtocol(conn, opts...)
+	p, err := protocol.NewProtocol(clientConn, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return cloudevents.NewClient(p)
+	// invoke the receive callback as a blocking call, so the receiving is throttled when the received events are not
+	// processed in time instead of starting a goroutine for each received event.
+	return cloudevents.NewClient(&timeoutProtocol{Protocol: p, timeout: o.PublishTimeout},
+		cloudeventsclient.WithBlockingCallback())
+}
+
+// timeoutProtocol sets the deadline of the publish calls with the publish timeout.
+type timeoutProtocol struct {
+	*protocol.Protocol
+	timeout time.Duration
+}
+
+func (p *timeoutProtocol) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) error {
+	return options.SendWithAckTimeout(ctx, p.timeout, cecontext.TopicFrom(ctx), func(ctx context.Context) error {
+		return p.Protocol.Send(ctx, m, transformers...)
+	})
 }
 
 // Replace the nth occurrence of old in str by new.
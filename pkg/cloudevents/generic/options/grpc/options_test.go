@@ -1,10 +1,23 @@
 package grpc
 
 import (
+	"context"
 	"log"
+	"net"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
 )
 
 func TestBuildGRPCOptionsFromFlags(t *testing.T) {
@@ -42,6 +55,19 @@ func TestBuildGRPCOptionsFromFlags(t *testing.T) {
 				URL: "test",
 			},
 		},
+		{
+			name:             "invalid content mode",
+			config:           "{\"url\":\"test\",\"contentMode\":\"batched\"}",
+			expectedErrorMsg: "unsupported content mode \"batched\", it should be binary or structured",
+		},
+		{
+			name:   "customized options with content mode",
+			config: "{\"url\":\"test\",\"contentMode\":\"structured\"}",
+			expectedOptions: &GRPCOptions{
+				URL:         "test",
+				ContentMode: options.ContentModeStructured,
+			},
+		},
 		{
 			name:   "customized options with yaml format",
 			config: "url: test",
@@ -67,6 +93,52 @@ func TestBuildGRPCOptionsFromFlags(t *testing.T) {
 				ClientKeyFile:  "test",
 			},
 		},
+		{
+			name:             "unsupported load balancing policy",
+			config:           "{\"url\":\"test\",\"loadBalancingPolicy\":\"unknown\"}",
+			expectedErrorMsg: "unsupported load balancing policy unknown",
+		},
+		{
+			name:             "invalid service config",
+			config:           "{\"url\":\"test\",\"serviceConfig\":\"{\"}",
+			expectedErrorMsg: "serviceConfig must be a valid JSON",
+		},
+		{
+			name: "customized options with load balancing",
+			config: "{\"url\":\"test\",\"loadBalancingPolicy\":\"round_robin\"," +
+				"\"serviceConfig\":\"{\\\"methodConfig\\\":[]}\"}",
+			expectedOptions: &GRPCOptions{
+				URL:                 "test",
+				LoadBalancingPolicy: "round_robin",
+				ServiceConfig:       "{\"methodConfig\":[]}",
+			},
+		},
+		{
+			name:             "negative connection pool size",
+			config:           "{\"url\":\"test\",\"connectionPoolSize\":-1}",
+			expectedErrorMsg: "connectionPoolSize must not be negative",
+		},
+		{
+			name:   "customized options with connection pool",
+			config: "{\"url\":\"test\",\"connectionPoolSize\":4}",
+			expectedOptions: &GRPCOptions{
+				URL:                "test",
+				ConnectionPoolSize: 4,
+			},
+		},
+		{
+			name:             "invalid publish timeout",
+			config:           "url: test\npublishTimeout: 0s\n",
+			expectedErrorMsg: "invalid publishTimeout 0s",
+		},
+		{
+			name:   "customized options with publish timeout",
+			config: "url: test\npublishTimeout: 10s\n",
+			expectedOptions: &GRPCOptions{
+				URL:            "test",
+				PublishTimeout: 10 * time.Second,
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -88,3 +160,216 @@ func TestBuildGRPCOptionsFromFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildGRPCOptionsWithEnvOverrides(t *testing.T) {
+	file, err := os.CreateTemp("", "grpc-config-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := os.WriteFile(file.Name(), []byte("{\"url\":\"test\"}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GRPC_URL", "test-env")
+	t.Setenv("GRPC_TOKEN_FILE", "/var/run/secrets/token")
+
+	options, err := BuildGRPCOptionsFromFlags(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOptions := &GRPCOptions{URL: "test-env", TokenFile: "/var/run/secrets/token"}
+	if !reflect.DeepEqual(options, expectedOptions) {
+		t.Errorf("expected %v, but got %v", expectedOptions, options)
+	}
+}
+
+func TestServiceConfig(t *testing.T) {
+	cases := []struct {
+		name                  string
+		options               *GRPCOptions
+		expectedTarget        string
+		expectedServiceConfig string
+	}{
+		{
+			name:           "default",
+			options:        &GRPCOptions{URL: "broker:8090"},
+			expectedTarget: "broker:8090",
+		},
+		{
+			name: "service config",
+			options: &GRPCOptions{
+				URL:           "broker:8090",
+				ServiceConfig: `{"methodConfig":[]}`,
+			},
+			expectedTarget:        "broker:8090",
+			expectedServiceConfig: `{"methodConfig":[]}`,
+		},
+		{
+			name: "load balancing policy with service config",
+			options: &GRPCOptions{
+				URL:                 "broker:8090",
+				LoadBalancingPolicy: "round_robin",
+				ServiceConfig:       `{"loadBalancingConfig":[{"pick_first":{}}],"methodConfig":[]}`,
+			},
+			expectedTarget:        "dns:///broker:8090",
+			expectedServiceConfig: `{"loadBalancingConfig":[{"round_robin":{}}],"methodConfig":[]}`,
+		},
+		{
+			name: "load balancing policy with url scheme",
+			options: &GRPCOptions{
+				URL:                 "passthrough:///broker:8090",
+				LoadBalancingPolicy: "pick_first",
+			},
+			expectedTarget:        "passthrough:///broker:8090",
+			expectedServiceConfig: `{"loadBalancingConfig":[{"pick_first":{}}]}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if target := c.options.target(); target != c.expectedTarget {
+				t.Errorf("expected %s, but got %s", c.expectedTarget, target)
+			}
+
+			serviceConfig, err := c.options.serviceConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if serviceConfig != c.expectedServiceConfig {
+				t.Errorf("expected %s, but got %s", c.expectedServiceConfig, serviceConfig)
+			}
+		})
+	}
+}
+
+func TestTokenCredentials(t *testing.T) {
+	file, err := os.CreateTemp("", "grpc-token-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := os.WriteFile(file.Name(), []byte("test-token\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	credentials := &tokenCredentials{tokenFile: file.Name()}
+	metadata, err := credentials.GetRequestMetadata(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if metadata["authorization"] != "Bearer test-token" {
+		t.Errorf("expected Bearer test-token, but got %v", metadata)
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	pbv1.RegisterCloudEventServiceServer(server, &pbv1.UnimplementedCloudEventServiceServer{})
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	calls := []string{}
+	options := NewGRPCOptions()
+	options.URL = lis.Addr().String()
+	options.WithUnaryInterceptor(
+		func(ctx context.Context, method string, req, reply interface{},
+			cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			calls = append(calls, "first:"+method)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		},
+		func(ctx context.Context, method string, req, reply interface{},
+			cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			calls = append(calls, "second:"+method)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		},
+	)
+
+	conn, err := options.GetGRPCClientConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// the server does not implement the service, only the interceptors are verified
+	_, _ = pbv1.NewCloudEventServiceClient(conn).Publish(context.TODO(), &pbv1.PublishRequest{})
+
+	expected := []string{
+		"first:/io.cloudevents.v1.CloudEventService/Publish",
+		"second:/io.cloudevents.v1.CloudEventService/Publish",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v, but got %v", expected, calls)
+	}
+}
+
+func TestUnixDomainSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "broker.sock")
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	pbv1.RegisterCloudEventServiceServer(server, &pbv1.UnimplementedCloudEventServiceServer{})
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	options := NewGRPCOptions()
+	options.URL = "unix://" + socket
+	conn, err := options.GetGRPCClientConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// the request reaches the server which does not implement the service
+	_, err = pbv1.NewCloudEventServiceClient(conn).Publish(context.TODO(), &pbv1.PublishRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected unimplemented error, but got %v", err)
+	}
+}
+
+func TestPrebuiltClientConn(t *testing.T) {
+	conn, err := grpc.Dial("passthrough:///in-process", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	options := NewGRPCOptions()
+	options.ClientConn = conn
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := options.GetCloudEventsClient(ctx, func(err error) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := options.GetGRPCClientConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != conn {
+		t.Errorf("expected the pre-built connection is used")
+	}
+
+	// the pre-built connection is not closed after the client is stopped
+	cancel()
+	time.Sleep(200 * time.Millisecond)
+	if conn.GetState() == connectivity.Shutdown {
+		t.Errorf("expected the pre-built connection is not closed")
+	}
+}
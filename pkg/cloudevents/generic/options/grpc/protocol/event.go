@@ -0,0 +1,93 @@
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+
+	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
+)
+
+// ToEvent converts a received protobuf CloudEvent to a cloudevent without the binding writers. The data of a binary
+// mode event refers to the data of the protobuf CloudEvent instead of being copied, and a structured mode event is
+// decoded from the data of the protobuf CloudEvent directly, so the protobuf CloudEvent must not be modified after it
+// is converted.
+func ToEvent(msg *pbv1.CloudEvent) (*cloudevents.Event, error) {
+	m := NewMessage(msg)
+	switch m.ReadEncoding() {
+	case binding.EncodingStructured:
+		evt := cloudevents.NewEvent()
+		if err := m.format.Unmarshal(m.DataRef(), &evt); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal the structured event, %v", err)
+		}
+		return &evt, nil
+	case binding.EncodingBinary:
+		evt := cloudevents.NewEvent(msg.SpecVersion)
+		if err := m.ReadBinary(context.Background(), (*eventWriter)(&evt)); err != nil {
+			return nil, err
+		}
+		return &evt, nil
+	default:
+		return nil, binding.ErrUnknownEncoding
+	}
+}
+
+// eventWriter writes the attributes and the data of a binary mode message to a cloudevent, the event must be created
+// with the spec version of the message.
+type eventWriter cloudevents.Event
+
+var _ binding.BinaryWriter = (*eventWriter)(nil)
+
+func (w *eventWriter) Start(ctx context.Context) error {
+	return nil
+}
+
+func (w *eventWriter) End(ctx context.Context) error {
+	return nil
+}
+
+func (w *eventWriter) SetAttribute(attribute spec.Attribute, value interface{}) error {
+	if attribute.Kind() == spec.SpecVersion {
+		// the event is created with the spec version
+		return nil
+	}
+
+	if value == nil {
+		return attribute.Delete(w.Context)
+	}
+
+	return attribute.Set(w.Context, value)
+}
+
+func (w *eventWriter) SetExtension(name string, value interface{}) error {
+	return w.Context.SetExtension(name, value)
+}
+
+func (w *eventWriter) SetData(data io.Reader) error {
+	buf, ok := data.(*bytes.Buffer)
+	if !ok {
+		buf = new(bytes.Buffer)
+		if _, err := io.Copy(buf, data); err != nil {
+			return err
+		}
+	}
+
+	if buf.Len() > 0 {
+		w.DataEncoded = buf.Bytes()
+	}
+	return nil
+}
+
+// DataRef returns the data of the message without copying it, the text data is converted to bytes.
+func (m *Message) DataRef() []byte {
+	if text, ok := m.internal.Data.(*pbv1.CloudEvent_TextData); ok {
+		return []byte(text.TextData)
+	}
+
+	return m.internal.GetBinaryData()
+}
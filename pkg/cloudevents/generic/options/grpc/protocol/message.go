@@ -42,12 +42,11 @@ var (
 func NewMessage(msg *pbv1.CloudEvent) *Message {
 	var f format.Format
 	var v spec.Version
-	if msg.Attributes != nil {
-		if contentType, ok := msg.Attributes[contenttype]; ok && format.IsFormat(contentType.GetCeString()) {
-			f = format.Lookup(contentType.GetCeString())
-		} else if s := msg.SpecVersion; s != "" {
-			v = specs.Version(s)
-		}
+	// the attributes of a received message are nil if the message does not have any attributes
+	if contentType, ok := msg.Attributes[contenttype]; ok && format.IsFormat(contentType.GetCeString()) {
+		f = format.Lookup(contentType.GetCeString())
+	} else if s := msg.SpecVersion; s != "" {
+		v = specs.Version(s)
 	}
 	return &Message{
 		internal: msg,
@@ -72,7 +71,7 @@ func (m *Message) ReadStructured(ctx context.Context, encoder binding.Structured
 		return binding.ErrNotStructured
 	}
 
-	return encoder.SetStructuredEvent(ctx, m.format, bytes.NewReader(m.internal.GetBinaryData()))
+	return encoder.SetStructuredEvent(ctx, m.format, bytes.NewReader(m.DataRef()))
 }
 
 func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter) error {
@@ -136,8 +135,8 @@ func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter)
 		}
 	}
 
-	if m.internal.GetBinaryData() != nil {
-		return encoder.SetData(bytes.NewBuffer(m.internal.GetBinaryData()))
+	if data := m.DataRef(); data != nil {
+		return encoder.SetData(bytes.NewBuffer(data))
 	}
 
 	return nil
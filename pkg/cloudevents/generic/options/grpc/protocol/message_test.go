@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
@@ -63,3 +64,149 @@ func TestReadBinary(t *testing.T) {
 		t.Errorf("Error unexpected. got: %v", err)
 	}
 }
+
+func TestToEvent(t *testing.T) {
+	data := []byte(`{"hello":"world"}`)
+	structuredEvent := event.New()
+	structuredEvent.SetID("3")
+	structuredEvent.SetSource("test")
+	structuredEvent.SetType("test")
+	if err := structuredEvent.SetData(event.ApplicationJSON, map[string]string{"hello": "world"}); err != nil {
+		t.Fatal(err)
+	}
+	structured, err := structuredEvent.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name            string
+		msg             *pbv1.CloudEvent
+		expectedErr     bool
+		expectedID      string
+		expectedData    string
+		expectedDataRef bool
+	}{
+		{
+			name: "binary",
+			msg: &pbv1.CloudEvent{
+				SpecVersion: "1.0",
+				Id:          "1",
+				Source:      "test",
+				Type:        "test",
+				Attributes: map[string]*pbv1.CloudEventAttributeValue{
+					contenttype:            {Attr: &pbv1.CloudEventAttributeValue_CeString{CeString: event.ApplicationJSON}},
+					prefix + "clustername": {Attr: &pbv1.CloudEventAttributeValue_CeString{CeString: "cluster1"}},
+				},
+				Data: &pbv1.CloudEvent_BinaryData{BinaryData: data},
+			},
+			expectedID:      "1",
+			expectedData:    string(data),
+			expectedDataRef: true,
+		},
+		{
+			name: "binary without attributes",
+			msg: &pbv1.CloudEvent{
+				SpecVersion: "1.0",
+				Id:          "2",
+				Source:      "test",
+				Type:        "test",
+				Data:        &pbv1.CloudEvent_TextData{TextData: string(data)},
+			},
+			expectedID:   "2",
+			expectedData: string(data),
+		},
+		{
+			name: "structured",
+			msg: &pbv1.CloudEvent{
+				Attributes: map[string]*pbv1.CloudEventAttributeValue{
+					contenttype: {Attr: &pbv1.CloudEventAttributeValue_CeString{CeString: event.ApplicationCloudEventsJSON}},
+				},
+				Data: &pbv1.CloudEvent_BinaryData{BinaryData: structured},
+			},
+			expectedID:   "3",
+			expectedData: string(data),
+		},
+		{
+			name:        "unknown encoding",
+			msg:         &pbv1.CloudEvent{},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evt, err := ToEvent(c.msg)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if evt.ID() != c.expectedID {
+				t.Errorf("expected %s, but got %s", c.expectedID, evt.ID())
+			}
+			if string(evt.Data()) != c.expectedData {
+				t.Errorf("expected %s, but got %s", c.expectedData, string(evt.Data()))
+			}
+			if c.expectedDataRef && &evt.Data()[0] != &c.msg.GetBinaryData()[0] {
+				t.Errorf("expected the event data refers to the message data")
+			}
+		})
+	}
+}
+
+func BenchmarkToEvent(b *testing.B) {
+	msg := &pbv1.CloudEvent{
+		SpecVersion: "1.0",
+		Id:          "1",
+		Source:      "test",
+		Type:        "io.open-cluster-management.works.v1alpha1.manifestbundles.spec.create_request",
+		Attributes: map[string]*pbv1.CloudEventAttributeValue{
+			contenttype:                {Attr: &pbv1.CloudEventAttributeValue_CeString{CeString: event.ApplicationJSON}},
+			prefix + "clustername":     {Attr: &pbv1.CloudEventAttributeValue_CeString{CeString: "cluster1"}},
+			prefix + "resourceid":      {Attr: &pbv1.CloudEventAttributeValue_CeString{CeString: "test"}},
+			prefix + "resourceversion": {Attr: &pbv1.CloudEventAttributeValue_CeInteger{CeInteger: 1}},
+		},
+		Data: &pbv1.CloudEvent_BinaryData{BinaryData: []byte(`{"manifests":[` + strings.Repeat(`{"kind":"ConfigMap"},`, 1024) + `{}]}`)},
+	}
+
+	structuredEvent, err := binding.ToEvent(context.Background(), NewMessage(msg))
+	if err != nil {
+		b.Fatal(err)
+	}
+	structuredData, err := structuredEvent.MarshalJSON()
+	if err != nil {
+		b.Fatal(err)
+	}
+	structuredMsg := &pbv1.CloudEvent{
+		Attributes: map[string]*pbv1.CloudEventAttributeValue{
+			contenttype: {Attr: &pbv1.CloudEventAttributeValue_CeString{CeString: event.ApplicationCloudEventsJSON}},
+		},
+		Data: &pbv1.CloudEvent_BinaryData{BinaryData: structuredData},
+	}
+
+	for name, msg := range map[string]*pbv1.CloudEvent{"binary": msg, "structured": structuredMsg} {
+		b.Run(name+"/binding.ToEvent", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := binding.ToEvent(context.Background(), NewMessage(msg)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(name+"/ToEvent", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ToEvent(msg); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
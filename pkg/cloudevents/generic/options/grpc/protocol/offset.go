@@ -0,0 +1,185 @@
+package protocol
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
+)
+
+const (
+	// OffsetAttribute is the cloudevent attribute of the offset of an event in its topic, it is set by the server for
+	// the resumable subscriptions. The offsets of a topic start from 1.
+	OffsetAttribute = "streamoffset"
+
+	// OffsetMetadataKey is the header metadata key of a subscription, the server sets it to the offset of the next
+	// event in the subscribed topic when the subscription starts.
+	OffsetMetadataKey = "subscription-offset"
+)
+
+// SubscriptionOffsets keeps the offset of the next event that is expected from each topic. It is shared by the
+// protocols that are created on reconnecting, so that the subscriptions are resumed after the last received events.
+type SubscriptionOffsets struct {
+	sync.RWMutex
+	offsets map[string]uint64
+}
+
+func NewSubscriptionOffsets() *SubscriptionOffsets {
+	return &SubscriptionOffsets{offsets: map[string]uint64{}}
+}
+
+// Get returns the offset of the next event that is expected from a topic, zero means the offset is unknown.
+func (o *SubscriptionOffsets) Get(topic string) uint64 {
+	o.RLock()
+	defer o.RUnlock()
+	return o.offsets[topic]
+}
+
+// Set records the offset of the next event that is expected from a topic, the offset is only moved forward.
+func (o *SubscriptionOffsets) Set(topic string, offset uint64) {
+	o.Lock()
+	defer o.Unlock()
+
+	if offset > o.offsets[topic] {
+		o.offsets[topic] = offset
+	}
+}
+
+// Resumable returns true if the offsets of all the given topics are known, so the subscriptions of the topics can be
+// resumed without missing any event.
+func (o *SubscriptionOffsets) Resumable(topics []string) bool {
+	o.RLock()
+	defer o.RUnlock()
+
+	for _, topic := range topics {
+		if o.offsets[topic] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset forgets the offsets of all topics, e.g. the server cannot replay the events from the offsets.
+func (o *SubscriptionOffsets) Reset() {
+	o.Lock()
+	defer o.Unlock()
+	o.offsets = map[string]uint64{}
+}
+
+// EventLog keeps the recent events of each topic with their offsets, it can be used by a gRPC server to replay the
+// missed events to the resumed subscriptions.
+type EventLog struct {
+	sync.RWMutex
+	size   int
+	topics map[string]*topicLog
+}
+
+type topicLog struct {
+	// next is the offset of the next event in the topic
+	next   uint64
+	events []*pbv1.CloudEvent
+}
+
+// NewEventLog returns an EventLog that keeps the given number of the latest events for each topic.
+func NewEventLog(size int) *EventLog {
+	return &EventLog{
+		size:   size,
+		topics: map[string]*topicLog{},
+	}
+}
+
+// Append adds an event to a topic, the event is assigned with the next offset of the topic in its OffsetAttribute.
+func (l *EventLog) Append(topic string, evt *pbv1.CloudEvent) uint64 {
+	l.Lock()
+	defer l.Unlock()
+
+	log, ok := l.topics[topic]
+	if !ok {
+		log = &topicLog{next: 1}
+		l.topics[topic] = log
+	}
+
+	offset := log.next
+	log.next++
+
+	if evt.Attributes == nil {
+		evt.Attributes = map[string]*pbv1.CloudEventAttributeValue{}
+	}
+	evt.Attributes[OffsetAttribute] = &pbv1.CloudEventAttributeValue{
+		Attr: &pbv1.CloudEventAttributeValue_CeString{CeString: strconv.FormatUint(offset, 10)},
+	}
+
+	log.events = append(log.events, evt)
+	if len(log.events) > l.size {
+		log.events = log.events[len(log.events)-l.size:]
+	}
+
+	return offset
+}
+
+// NextOffset returns the offset of the next event in a topic, a server sets it to the OffsetMetadataKey header of a new
+// subscription.
+func (l *EventLog) NextOffset(topic string) uint64 {
+	l.RLock()
+	defer l.RUnlock()
+
+	if log, ok := l.topics[topic]; ok {
+		return log.next
+	}
+	return 1
+}
+
+// Since returns the events from the given offset in a topic, it returns an OUT_OF_RANGE status error if the events
+// have been evicted from the log or the offset is unknown.
+func (l *EventLog) Since(topic string, offset uint64) ([]*pbv1.CloudEvent, error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	next := uint64(1)
+	var events []*pbv1.CloudEvent
+	if log, ok := l.topics[topic]; ok {
+		next = log.next
+		events = log.events
+	}
+
+	// the offset of the first event in the log
+	first := next - uint64(len(events))
+	if offset < first || offset > next {
+		return nil, status.Errorf(codes.OutOfRange, "the events from offset %d of topic %s are not found", offset, topic)
+	}
+
+	return append([]*pbv1.CloudEvent{}, events[offset-first:]...), nil
+}
+
+// offsetOf returns the offset of a received event, it returns false if the event does not have an offset.
+func offsetOf(evt *pbv1.CloudEvent) (uint64, bool) {
+	value, ok := evt.Attributes[OffsetAttribute]
+	if !ok {
+		return 0, false
+	}
+
+	offset, err := strconv.ParseUint(value.GetCeString(), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return offset, true
+}
+
+// offsetFromHeader returns the offset from the header metadata of a subscription.
+func offsetFromHeader(md metadata.MD) (uint64, error) {
+	values := md.Get(OffsetMetadataKey)
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	offset, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the subscription offset %s, %v", values[0], err)
+	}
+	return offset, nil
+}
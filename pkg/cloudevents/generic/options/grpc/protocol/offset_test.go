@@ -0,0 +1,216 @@
+package protocol
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	gotime "time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
+)
+
+func newTestEvent(id string) *pbv1.CloudEvent {
+	return &pbv1.CloudEvent{Id: id, Source: "test", SpecVersion: "1.0", Type: "test"}
+}
+
+func TestEventLog(t *testing.T) {
+	log := NewEventLog(2)
+
+	if offset := log.NextOffset("topic1"); offset != 1 {
+		t.Errorf("expected 1, but got %d", offset)
+	}
+
+	for _, id := range []string{"1", "2", "3"} {
+		log.Append("topic1", newTestEvent(id))
+	}
+
+	cases := []struct {
+		name        string
+		offset      uint64
+		expectedIDs []string
+		expectedErr bool
+	}{
+		{
+			name:        "replay from the first event in the log",
+			offset:      2,
+			expectedIDs: []string{"2", "3"},
+		},
+		{
+			name:        "nothing to replay",
+			offset:      4,
+			expectedIDs: []string{},
+		},
+		{
+			name:        "evicted",
+			offset:      1,
+			expectedErr: true,
+		},
+		{
+			name:        "unknown offset",
+			offset:      5,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evts, err := log.Since("topic1", c.offset)
+			if c.expectedErr {
+				if status.Code(err) != codes.OutOfRange {
+					t.Errorf("expected out of range error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			ids := []string{}
+			for _, evt := range evts {
+				ids = append(ids, evt.Id)
+				if offset, ok := offsetOf(evt); !ok || strconv.FormatUint(offset, 10) != evt.Id {
+					t.Errorf("expected offset %s, but got %d", evt.Id, offset)
+				}
+			}
+			if len(ids) != len(c.expectedIDs) {
+				t.Errorf("expected %v, but got %v", c.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+type resumableServer struct {
+	pbv1.UnimplementedCloudEventServiceServer
+	log *EventLog
+}
+
+func (s *resumableServer) Subscribe(req *pbv1.SubscriptionRequest, stream pbv1.CloudEventService_SubscribeServer) error {
+	if req.ResumeOffset == 0 {
+		if err := stream.SendHeader(metadata.Pairs(
+			OffsetMetadataKey, strconv.FormatUint(s.log.NextOffset(req.Topic), 10))); err != nil {
+			return err
+		}
+	} else {
+		evts, err := s.log.Since(req.Topic, req.ResumeOffset)
+		if err != nil {
+			return err
+		}
+		for _, evt := range evts {
+			if err := stream.Send(evt); err != nil {
+				return err
+			}
+		}
+	}
+
+	<-stream.Context().Done()
+	return nil
+}
+
+func TestResumeSubscription(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	server := &resumableServer{log: NewEventLog(2)}
+	grpcServer := grpc.NewServer()
+	pbv1.RegisterCloudEventServiceServer(grpcServer, server)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	defer grpcServer.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer conn.Close()
+
+	offsets := NewSubscriptionOffsets()
+	resumeFailed := make(chan error, 1)
+	subscribe := func() (context.CancelFunc, *Protocol) {
+		p, err := NewProtocol(conn, WithSubscribeOption(&SubscribeOption{
+			Topics:  []string{"topic1"},
+			Offsets: offsets,
+			ResumeFailedHandler: func(err error) {
+				resumeFailed <- err
+			},
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			_ = p.OpenInbound(ctx)
+		}()
+		return cancel, p
+	}
+
+	waitForOffset := func(expected uint64) {
+		if err := wait.PollUntilContextTimeout(context.Background(), 10*gotime.Millisecond, 5*gotime.Second, true,
+			func(ctx context.Context) (bool, error) {
+				return offsets.Get("topic1") == expected, nil
+			}); err != nil {
+			t.Fatalf("expected offset %d, but got %d", expected, offsets.Get("topic1"))
+		}
+	}
+
+	// the first subscription tracks the offsets from the next event
+	cancel, _ := subscribe()
+	waitForOffset(1)
+	cancel()
+
+	// the events are sent when the subscriber is disconnected
+	server.log.Append("topic1", newTestEvent("1"))
+	server.log.Append("topic1", newTestEvent("2"))
+
+	if !offsets.Resumable([]string{"topic1"}) {
+		t.Errorf("expected the subscription is resumable")
+	}
+
+	// the missed events are replayed to the resumed subscription
+	cancel, p := subscribe()
+	for _, id := range []string{"1", "2"} {
+		msg, err := p.Receive(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if actual := (*event.Event)(msg.(*binding.EventMessage)).ID(); actual != id {
+			t.Errorf("expected %s, but got %s", id, actual)
+		}
+	}
+	waitForOffset(3)
+	cancel()
+
+	// the missed events are evicted, the subscription cannot be resumed
+	for _, id := range []string{"3", "4", "5"} {
+		server.log.Append("topic1", newTestEvent(id))
+	}
+
+	cancel, _ = subscribe()
+	defer cancel()
+
+	select {
+	case err := <-resumeFailed:
+		if status.Code(err) != codes.OutOfRange {
+			t.Errorf("expected out of range error, but got %v", err)
+		}
+	case <-gotime.After(5 * gotime.Second):
+		t.Fatalf("expected the resume is failed")
+	}
+
+	if offsets.Resumable([]string{"topic1"}) {
+		t.Errorf("expected the offsets are reset")
+	}
+}
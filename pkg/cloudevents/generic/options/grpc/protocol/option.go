@@ -15,6 +15,13 @@ type PublishOption struct {
 // SubscribeOption
 type SubscribeOption struct {
 	Topics []string
+
+	// Offsets is optional, if it is set, the subscriptions are resumed from the offsets of the last received events.
+	Offsets *SubscriptionOffsets
+
+	// ResumeFailedHandler is optional, it is called after the server fails to resume a subscription from its offset,
+	// the offsets are reset before it is called.
+	ResumeFailedHandler func(err error)
 }
 
 // WithPublishOption sets the Publish configuration for the client. This option is required if you want to send messages.
@@ -7,6 +7,8 @@ import (
 	"sync"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
@@ -116,22 +118,55 @@ func (p *Protocol) OpenInbound(ctx context.Context) error {
 	defer p.openerMutex.Unlock()
 
 	logger := cecontext.LoggerFrom(ctx)
+	offsets := p.subscribeOption.Offsets
 	for _, topic := range p.subscribeOption.Topics {
+		topic := topic
+
+		var resumeOffset uint64
+		if offsets != nil {
+			resumeOffset = offsets.Get(topic)
+		}
+
 		subClient, err := p.client.Subscribe(ctx, &pbv1.SubscriptionRequest{
-			Topic: topic,
+			Topic:        topic,
+			ResumeOffset: resumeOffset,
 		})
 		if err != nil {
 			return err
 		}
 
-		logger.Infof("subscribing to topic: %v", topic)
+		logger.Infof("subscribing to topic: %v (resume offset: %d)", topic, resumeOffset)
 		go func() {
+			if offsets != nil && resumeOffset == 0 {
+				// the subscription is not resumed, track the offsets from the next event of the topic
+				if md, err := subClient.Header(); err == nil {
+					offset, err := offsetFromHeader(md)
+					if err != nil {
+						logger.Warnf("failed to get the offset of topic %s, %v", topic, err)
+					}
+					offsets.Set(topic, offset)
+				}
+			}
+
 			for {
 				msg, err := subClient.Recv()
 				if err != nil {
+					if offsets != nil && status.Code(err) == codes.OutOfRange {
+						logger.Warnf("failed to resume the subscription of topic %s, %v", topic, err)
+						offsets.Reset()
+						if p.subscribeOption.ResumeFailedHandler != nil {
+							p.subscribeOption.ResumeFailedHandler(err)
+						}
+					}
 					return
 				}
 				p.incoming <- msg
+
+				if offsets != nil {
+					if offset, ok := offsetOf(msg); ok {
+						offsets.Set(topic, offset+1)
+					}
+				}
 			}
 		}()
 	}
@@ -152,8 +187,12 @@ func (p *Protocol) Receive(ctx context.Context) (binding.Message, error) {
 		if !ok {
 			return nil, io.EOF
 		}
-		msg := NewMessage(m)
-		return msg, nil
+		// convert the received message to an event directly, so the event data refers to the received message
+		evt, err := ToEvent(m)
+		if err != nil {
+			return nil, err
+		}
+		return (*binding.EventMessage)(evt), nil
 	case <-ctx.Done():
 		return nil, io.EOF
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cloudeventscontext "github.com/cloudevents/sdk-go/v2/context"
@@ -14,9 +15,11 @@ import (
 )
 
 type gRPCSourceOptions struct {
+	sync.RWMutex
 	GRPCOptions
 	errorChan chan error // grpc client connection doesn't have error channel, it will handle reconnecting automatically
 	sourceID  string
+	offsets   *protocol.SubscriptionOffsets
 }
 
 func NewSourceOptions(gRPCOptions *GRPCOptions, sourceID string) *options.CloudEventsSourceOptions {
@@ -25,12 +28,16 @@ func NewSourceOptions(gRPCOptions *GRPCOptions, sourceID string) *options.CloudE
 			GRPCOptions: *gRPCOptions,
 			errorChan:   make(chan error),
 			sourceID:    sourceID,
+			offsets:     protocol.NewSubscriptionOffsets(),
 		},
-		SourceID: sourceID,
+		SourceID:                 sourceID,
+		CloudEventsClientOptions: options.CloudEventsClientOptions{TenantID: gRPCOptions.TenantID},
 	}
 }
 
 func (o *gRPCSourceOptions) WithContext(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+	ctx = options.WithContentMode(ctx, o.grpcOptions().ContentMode)
+
 	eventType, err := types.ParseCloudEventsType(evtCtx.GetType())
 	if err != nil {
 		return nil, fmt.Errorf("unsupported event type %s, %v", eventType, err)
@@ -38,7 +45,7 @@ func (o *gRPCSourceOptions) WithContext(ctx context.Context, evtCtx cloudevents.
 
 	if eventType.Action == types.ResyncRequestAction {
 		// source publishes event to status resync topic to request to get resources status from all clusters
-		return cloudeventscontext.WithTopic(ctx, strings.Replace(StatusResyncTopic, "+", o.sourceID, -1)), nil
+		return cloudeventscontext.WithTopic(ctx, o.grpcOptions().topic(strings.Replace(StatusResyncTopic, "+", o.sourceID, -1))), nil
 	}
 
 	clusterName, err := evtCtx.GetExtension(types.ExtensionClusterName)
@@ -46,23 +53,35 @@ func (o *gRPCSourceOptions) WithContext(ctx context.Context, evtCtx cloudevents.
 		return nil, err
 	}
 
+	if clusterName == types.ClusterAll {
+		return nil, fmt.Errorf("the resource specs cannot be broadcast to all clusters with gRPC")
+	}
+
 	// source publishes event to spec topic to send the resource spec to a specified cluster
 	specTopic := strings.Replace(SpecTopic, "+", o.sourceID, 1)
 	specTopic = strings.Replace(specTopic, "+", fmt.Sprintf("%s", clusterName), -1)
-	return cloudeventscontext.WithTopic(ctx, specTopic), nil
+	// the events of a cluster are sent on the same connection of the connection pool
+	ctx = withClusterName(ctx, fmt.Sprintf("%s", clusterName))
+	return cloudeventscontext.WithTopic(ctx, o.grpcOptions().topic(specTopic)), nil
 }
 
 func (o *gRPCSourceOptions) Client(ctx context.Context) (cloudevents.Client, error) {
-	receiver, err := o.GetCloudEventsClient(
+	grpcOptions := o.grpcOptions()
+
+	receiver, err := grpcOptions.GetCloudEventsClient(
 		ctx,
 		func(err error) {
 			o.errorChan <- err
 		},
 		protocol.WithPublishOption(&protocol.PublishOption{}),
 		protocol.WithSubscribeOption(&protocol.SubscribeOption{
-			Topics: []string{
-				strings.Replace(StatusTopic, "+", o.sourceID, 1), // receiving the resources status from agents with status topic
-				SpecResyncTopic, // receiving the resources spec resync request from agents with spec resync topic
+			Topics:  o.subscribeTopics(),
+			Offsets: o.offsets,
+			ResumeFailedHandler: func(err error) {
+				// reconnect to resubscribe the topics without the offsets
+				go func() {
+					o.errorChan <- err
+				}()
 			},
 		}),
 	)
@@ -75,3 +94,57 @@ func (o *gRPCSourceOptions) Client(ctx context.Context) (cloudevents.Client, err
 func (o *gRPCSourceOptions) ErrorChan() <-chan error {
 	return o.errorChan
 }
+
+// Reload rebuilds the gRPC options from the given config file, the cloudevents client is reconnected with the new
+// options.
+func (o *gRPCSourceOptions) Reload(configPath string) error {
+	grpcOptions, err := BuildGRPCOptionsFromFlags(configPath)
+	if err != nil {
+		return err
+	}
+
+	o.Lock()
+	// the dial options and the connection are not from the config file, keep them with the reloaded options
+	grpcOptions.DialOptions = o.GRPCOptions.DialOptions
+	grpcOptions.ClientConn = o.GRPCOptions.ClientConn
+	// the tenant of a client can not be changed by reloading
+	grpcOptions.TenantID = o.GRPCOptions.TenantID
+	o.GRPCOptions = *grpcOptions
+	o.Unlock()
+
+	// notify the cloudevents client to reconnect with the new options
+	go func() {
+		o.errorChan <- fmt.Errorf("the gRPC options are reloaded from %s", configPath)
+	}()
+
+	return nil
+}
+
+func (o *gRPCSourceOptions) grpcOptions() GRPCOptions {
+	o.RLock()
+	defer o.RUnlock()
+	return o.GRPCOptions
+}
+
+// Resumable returns true if the subscriptions can be resumed from the offsets of the last received events after the
+// client is reconnected, so the resync is not required.
+func (o *gRPCSourceOptions) Resumable() bool {
+	return o.offsets.Resumable(o.subscribeTopics())
+}
+
+// Diagnostics returns the address of the gRPC server and the subscribed topics.
+func (o *gRPCSourceOptions) Diagnostics() options.TransportDiagnostics {
+	return options.TransportDiagnostics{
+		BrokerAddress: o.grpcOptions().URL,
+		Subscriptions: o.subscribeTopics(),
+	}
+}
+
+func (o *gRPCSourceOptions) subscribeTopics() []string {
+	return []string{
+		// receiving the resources status from agents with status topic
+		o.grpcOptions().topic(strings.Replace(StatusTopic, "+", o.sourceID, 1)),
+		// receiving the resources spec resync request from agents with spec resync topic
+		o.grpcOptions().topic(SpecResyncTopic),
+	}
+}
@@ -5,14 +5,19 @@ import (
 	"testing"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
 	cloudeventscontext "github.com/cloudevents/sdk-go/v2/context"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protocol"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
 func TestSourceContext(t *testing.T) {
 	cases := []struct {
 		name          string
+		tenantID      string
 		event         cloudevents.Event
 		expectedTopic string
 		assertError   func(error)
@@ -90,11 +95,33 @@ func TestSourceContext(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:     "send spec of tenant",
+			tenantID: "tenant1",
+			event: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              "test",
+				}
+
+				evt := cloudevents.NewEvent()
+				evt.SetType(eventType.String())
+				evt.SetExtension("clustername", "cluster1")
+				return evt
+			}(),
+			expectedTopic: "tenants/tenant1/sources/hub1/clusters/cluster1/spec",
+			assertError: func(err error) {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+			},
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			sourceOptions := &gRPCSourceOptions{sourceID: "hub1"}
+			sourceOptions := &gRPCSourceOptions{GRPCOptions: GRPCOptions{TenantID: c.tenantID}, sourceID: "hub1"}
 			ctx, err := sourceOptions.WithContext(context.TODO(), c.event.Context)
 			c.assertError(err)
 
@@ -112,3 +139,59 @@ func TestSourceContext(t *testing.T) {
 		})
 	}
 }
+
+func TestContentMode(t *testing.T) {
+	cases := []struct {
+		name               string
+		contentMode        options.ContentMode
+		expectedStructured bool
+	}{
+		{
+			name:               "default content mode",
+			expectedStructured: false,
+		},
+		{
+			name:               "binary content mode",
+			contentMode:        options.ContentModeBinary,
+			expectedStructured: false,
+		},
+		{
+			name:               "structured content mode",
+			contentMode:        options.ContentModeStructured,
+			expectedStructured: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evt := cloudevents.NewEvent()
+			evt.SetID("1")
+			evt.SetSource("hub1")
+			evt.SetType("io.open-cluster-management.works.v1alpha1.manifests.spec.create_request")
+			evt.SetExtension("clustername", "cluster1")
+			if err := evt.SetData(cloudevents.ApplicationJSON, map[string]string{"test": "test"}); err != nil {
+				t.Fatal(err)
+			}
+
+			sourceOptions := &gRPCSourceOptions{
+				GRPCOptions: GRPCOptions{ContentMode: c.contentMode},
+				sourceID:    "hub1",
+			}
+			ctx, err := sourceOptions.WithContext(context.TODO(), evt.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pbEvt := &pbv1.CloudEvent{}
+			if err := protocol.WritePBMessage(ctx, binding.ToMessage(&evt), pbEvt); err != nil {
+				t.Fatal(err)
+			}
+
+			// the event attributes are in the payload in the structured mode
+			structured := pbEvt.GetId() == ""
+			if structured != c.expectedStructured {
+				t.Errorf("expected %v, but got %v", c.expectedStructured, structured)
+			}
+		})
+	}
+}
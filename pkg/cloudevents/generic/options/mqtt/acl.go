@@ -0,0 +1,124 @@
+package mqtt
+
+import (
+	"fmt"
+	"strings"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/acl"
+)
+
+// ACLRules returns the broker access control rules of the given sources and the agents of the given clusters with the
+// topics of the MQTT options, the topics are the same as the topics that the source and agent clients publish to and
+// subscribe, so the clients can work with the least privilege:
+//   - a source publishes the specs to its source events topic of each cluster and its source broadcast topic, and
+//     subscribes its agent events topic and the agent broadcast topic.
+//   - an agent publishes the status to its agent events topic of each source and its agent broadcast topic, and
+//     subscribes the source events topic of its cluster and the source broadcast topic.
+//
+// The topics of a source are published with `+` if the clusters are not given, and the topics of an agent are published
+// with `+` if the sources are not given. The `$share` prefix of the shared subscriptions is removed, because the brokers
+// authorize a shared subscription with its topic. The claim topic of each cluster is allowed for its agent if
+// clusterClaim is true.
+func ACLRules(mqttOptions *MQTTOptions, sourceIDs, clusterNames []string, clusterClaim bool) ([]acl.Rule, error) {
+	topics := mqttOptions.Topics
+	if len(topics.SourceEvents) == 0 || len(topics.AgentEvents) == 0 {
+		return nil, fmt.Errorf("the source events and the agent events topics are required")
+	}
+
+	publishedSources := sourceIDs
+	if len(publishedSources) == 0 {
+		publishedSources = []string{"+"}
+	}
+
+	publishedClusters := clusterNames
+	if len(publishedClusters) == 0 {
+		publishedClusters = []string{"+"}
+	}
+
+	rules := []acl.Rule{}
+	for _, sourceID := range sourceIDs {
+		rule := func(access acl.Access, topic string) {
+			rules = append(rules, acl.Rule{
+				Role: acl.RoleSource, Name: sourceID, Access: access, Topic: mqttOptions.topic(unshare(topic)),
+			})
+		}
+
+		for _, clusterName := range publishedClusters {
+			eventsTopic := withLevels(topics.SourceEvents, sourceID, clusterName)
+			rule(acl.AccessPublish, eventsTopic)
+			if mqttOptions.RetainSpecs {
+				rule(acl.AccessPublish, eventsTopic+"/+")
+			}
+		}
+
+		if len(topics.SourceBroadcast) != 0 {
+			rule(acl.AccessPublish, withLevels(topics.SourceBroadcast, sourceID))
+		}
+
+		agentEventsTopic := strings.Split(unshare(topics.AgentEvents), "/")
+		rule(acl.AccessSubscribe, withLevels(topics.AgentEvents, sourceID, agentEventsTopic[3]))
+
+		if len(topics.AgentBroadcast) != 0 {
+			rule(acl.AccessSubscribe, topics.AgentBroadcast)
+		}
+	}
+
+	for _, clusterName := range clusterNames {
+		rule := func(access acl.Access, topic string) {
+			rules = append(rules, acl.Rule{
+				Role: acl.RoleAgent, Name: clusterName, Access: access, Topic: mqttOptions.topic(unshare(topic)),
+			})
+		}
+
+		for _, sourceID := range publishedSources {
+			rule(acl.AccessPublish, withLevels(topics.AgentEvents, sourceID, clusterName))
+		}
+
+		if len(topics.AgentBroadcast) != 0 {
+			rule(acl.AccessPublish, withLevels(topics.AgentBroadcast, clusterName))
+		}
+
+		sourceEventsTopic := replaceLast(topics.SourceEvents, "+", clusterName)
+		rule(acl.AccessSubscribe, sourceEventsTopic)
+		if mqttOptions.RetainSpecs {
+			rule(acl.AccessSubscribe, sourceEventsTopic+"/+")
+		}
+
+		if len(topics.SourceBroadcast) != 0 {
+			rule(acl.AccessSubscribe, topics.SourceBroadcast)
+		}
+
+		if clusterClaim {
+			claimTopic := fmt.Sprintf("clusters/%s/claim", clusterName)
+			rule(acl.AccessPublish, claimTopic)
+			rule(acl.AccessSubscribe, claimTopic)
+		}
+	}
+
+	return rules, nil
+}
+
+// unshare removes the `$share/<group>/` prefix of a shared subscription topic.
+func unshare(topic string) string {
+	if !strings.HasPrefix(topic, "$share/") {
+		return topic
+	}
+
+	levels := strings.SplitN(topic, "/", 3)
+	if len(levels) < 3 {
+		return topic
+	}
+	return levels[2]
+}
+
+// withLevels replaces the ID levels of an events or a broadcast topic, e.g. the source ID and the cluster name of
+// sources/+/clusters/+/sourceevents, with the given values.
+func withLevels(topic string, values ...string) string {
+	levels := strings.Split(unshare(topic), "/")
+	for i, value := range values {
+		if index := 2*i + 1; index < len(levels) {
+			levels[index] = value
+		}
+	}
+	return strings.Join(levels, "/")
+}
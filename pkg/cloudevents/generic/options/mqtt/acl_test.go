@@ -0,0 +1,98 @@
+package mqtt
+
+import (
+	"testing"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/acl"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestACLRules(t *testing.T) {
+	cases := []struct {
+		name         string
+		options      *MQTTOptions
+		sourceIDs    []string
+		clusterNames []string
+		clusterClaim bool
+		expectedACL  string
+		expectedErr  bool
+	}{
+		{
+			name:        "no topics",
+			options:     &MQTTOptions{},
+			sourceIDs:   []string{"source1"},
+			expectedErr: true,
+		},
+		{
+			name: "sources and clusters",
+			options: &MQTTOptions{
+				Topics: types.Topics{
+					SourceEvents:    "sources/+/clusters/+/sourceevents",
+					AgentEvents:     "$share/group/sources/+/clusters/+/agentevents",
+					SourceBroadcast: "sources/+/sourcebroadcast",
+					AgentBroadcast:  "clusters/+/agentbroadcast",
+				},
+			},
+			sourceIDs:    []string{"source1"},
+			clusterNames: []string{"cluster1"},
+			clusterClaim: true,
+			expectedACL: `user source1
+topic write sources/source1/clusters/cluster1/sourceevents
+topic write sources/source1/sourcebroadcast
+topic read sources/source1/clusters/+/agentevents
+topic read clusters/+/agentbroadcast
+
+user cluster1
+topic write sources/source1/clusters/cluster1/agentevents
+topic write clusters/cluster1/agentbroadcast
+topic readwrite clusters/cluster1/claim
+topic read sources/+/clusters/cluster1/sourceevents
+topic read sources/+/sourcebroadcast
+`,
+		},
+		{
+			name: "source of all clusters with the retained specs and tenant",
+			options: &MQTTOptions{
+				Topics: types.Topics{
+					SourceEvents: "sources/source1/clusters/+/sourceevents",
+					AgentEvents:  "sources/source1/clusters/+/agentevents",
+				},
+				RetainSpecs: true,
+				TenantID:    "tenant1",
+			},
+			sourceIDs: []string{"source1"},
+			expectedACL: `user source1
+topic write tenants/tenant1/sources/source1/clusters/+/sourceevents
+topic write tenants/tenant1/sources/source1/clusters/+/sourceevents/+
+topic read tenants/tenant1/sources/source1/clusters/+/agentevents
+`,
+		},
+		{
+			name: "agent of all sources",
+			options: &MQTTOptions{
+				Topics: types.Topics{
+					SourceEvents: "sources/+/clusters/+/sourceevents",
+					AgentEvents:  "sources/+/clusters/+/agentevents",
+				},
+			},
+			clusterNames: []string{"cluster1"},
+			expectedACL: `user cluster1
+topic write sources/+/clusters/cluster1/agentevents
+topic read sources/+/clusters/cluster1/sourceevents
+`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rules, err := ACLRules(c.options, c.sourceIDs, c.clusterNames, c.clusterClaim)
+			if c.expectedErr != (err != nil) {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+
+			if aclFile := string(acl.Mosquitto(rules, acl.Subjects{})); aclFile != c.expectedACL {
+				t.Errorf("expected %s, but got %s", c.expectedACL, aclFile)
+			}
+		})
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
-	cloudeventsmqtt "github.com/cloudevents/sdk-go/protocol/mqtt_paho/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cloudeventscontext "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/eclipse/paho.golang/paho"
@@ -16,10 +16,13 @@ import (
 )
 
 type mqttAgentOptions struct {
+	sync.RWMutex
 	MQTTOptions
 	errorChan   chan error
 	clusterName string
 	agentID     string
+	clientIDs   *clientIDs
+	connection  connection
 }
 
 func NewAgentOptions(mqttOptions *MQTTOptions, clusterName, agentID string) *options.CloudEventsAgentOptions {
@@ -28,16 +31,21 @@ func NewAgentOptions(mqttOptions *MQTTOptions, clusterName, agentID string) *opt
 		errorChan:   make(chan error),
 		clusterName: clusterName,
 		agentID:     agentID,
+		clientIDs:   newClientIDs(fmt.Sprintf("%s-client", agentID)),
 	}
 
 	return &options.CloudEventsAgentOptions{
-		CloudEventsOptions: mqttAgentOptions,
-		AgentID:            mqttAgentOptions.agentID,
-		ClusterName:        mqttAgentOptions.clusterName,
+		CloudEventsOptions:       mqttAgentOptions,
+		AgentID:                  mqttAgentOptions.agentID,
+		ClusterName:              mqttAgentOptions.clusterName,
+		CloudEventsClientOptions: options.CloudEventsClientOptions{TenantID: mqttOptions.TenantID},
 	}
 }
 
 func (o *mqttAgentOptions) WithContext(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+	mqttOptions := o.mqttOptions()
+	ctx = options.WithContentMode(ctx, mqttOptions.ContentMode)
+
 	eventType, err := types.ParseCloudEventsType(evtCtx.GetType())
 	if err != nil {
 		return nil, fmt.Errorf("unsupported event type %s, %v", eventType, err)
@@ -48,61 +56,114 @@ func (o *mqttAgentOptions) WithContext(ctx context.Context, evtCtx cloudevents.E
 		return nil, err
 	}
 
-	// agent request to sync resource spec from all sources
-	if eventType.Action == types.ResyncRequestAction && originalSource == types.SourceAll {
-		if len(o.Topics.AgentBroadcast) == 0 {
+	// agent request to sync resource spec from all sources or to register to all sources
+	isBroadcast := eventType.Action == types.ResyncRequestAction || eventType.Action == types.RegisterRequestAction
+	if isBroadcast && originalSource == types.SourceAll {
+		if len(mqttOptions.Topics.AgentBroadcast) == 0 {
 			klog.Warningf("the agent broadcast topic not set, fall back to the agent events topic")
 
 			// TODO after supporting multiple sources, we should list each source
-			eventsTopic := replaceLast(o.Topics.AgentEvents, "+", o.clusterName)
-			return cloudeventscontext.WithTopic(ctx, eventsTopic), nil
+			eventsTopic := replaceLast(mqttOptions.Topics.AgentEvents, "+", o.clusterName)
+			return cloudeventscontext.WithTopic(ctx, mqttOptions.topic(eventsTopic)), nil
 		}
 
-		resyncTopic := strings.Replace(o.Topics.AgentBroadcast, "+", o.clusterName, 1)
-		return cloudeventscontext.WithTopic(ctx, resyncTopic), nil
+		resyncTopic := strings.Replace(mqttOptions.Topics.AgentBroadcast, "+", o.clusterName, 1)
+		return cloudeventscontext.WithTopic(ctx, mqttOptions.topic(resyncTopic)), nil
 	}
 
-	topicSource, err := getSourceFromEventsTopic(o.Topics.AgentEvents)
+	topicSource, err := getSourceFromEventsTopic(mqttOptions.Topics.AgentEvents)
 	if err != nil {
 		return nil, err
 	}
 
 	// agent publishes status events or spec resync events
-	eventsTopic := replaceLast(o.Topics.AgentEvents, "+", o.clusterName)
+	eventsTopic := replaceLast(mqttOptions.Topics.AgentEvents, "+", o.clusterName)
 	eventsTopic = replaceLast(eventsTopic, "+", topicSource)
-	return cloudeventscontext.WithTopic(ctx, eventsTopic), nil
+	return cloudeventscontext.WithTopic(ctx, mqttOptions.topic(eventsTopic)), nil
 }
 
 func (o *mqttAgentOptions) Client(ctx context.Context) (cloudevents.Client, error) {
+	mqttOptions := o.mqttOptions()
+
 	subscribe := &paho.Subscribe{
 		Subscriptions: map[string]paho.SubscribeOptions{
 			// TODO support multiple sources, currently the client require the source events topic has a sourceID, in
 			// the future, client may need a source list, it will subscribe to each source
 			// receiving the sources events
-			replaceLast(o.Topics.SourceEvents, "+", o.clusterName): {QoS: byte(o.SubQoS)},
+			mqttOptions.topic(replaceLast(mqttOptions.Topics.SourceEvents, "+", o.clusterName)): {
+				QoS: byte(mqttOptions.SubQoS),
+			},
 		},
 	}
 
-	if len(o.Topics.SourceBroadcast) != 0 {
+	if mqttOptions.RetainSpecs {
+		// receiving the retained resource spec events, the latest spec of each resource is delivered once subscribed
+		resourcesTopic := replaceLast(mqttOptions.Topics.SourceEvents, "+", o.clusterName) + "/+"
+		subscribe.Subscriptions[mqttOptions.topic(resourcesTopic)] = paho.SubscribeOptions{QoS: byte(mqttOptions.SubQoS)}
+	}
+
+	if len(mqttOptions.Topics.SourceBroadcast) != 0 {
 		// receiving status resync events from all sources
-		subscribe.Subscriptions[o.Topics.SourceBroadcast] = paho.SubscribeOptions{QoS: byte(o.SubQoS)}
+		subscribe.Subscriptions[mqttOptions.topic(mqttOptions.Topics.SourceBroadcast)] =
+			paho.SubscribeOptions{QoS: byte(mqttOptions.SubQoS)}
 	}
 
-	receiver, err := o.GetCloudEventsClient(
+	receiver, connack, err := mqttOptions.newCloudEventsClient(
 		ctx,
-		fmt.Sprintf("%s-client", o.agentID),
-		func(err error) {
+		o.clientIDs.next(mqttOptions),
+		o.clientIDs.errorHandler(func(err error) {
 			o.errorChan <- err
-		},
-		cloudeventsmqtt.WithPublish(&paho.Publish{QoS: byte(o.PubQoS)}),
-		cloudeventsmqtt.WithSubscribe(subscribe),
+		}),
+		WithPublish(mqttOptions.GetMQTTPublishOption()),
+		WithSubscribe(subscribe),
 	)
 	if err != nil {
 		return nil, err
 	}
+	o.connection.connected(connack, subscribe)
 	return receiver, nil
 }
 
+// Resumable returns true if the broker resumed the session of the client on the last connection, the events to the
+// client are queued by the broker while it is offline, so the resync is not required after it is reconnected.
+func (o *mqttAgentOptions) Resumable() bool {
+	return o.connection.sessionPresent()
+}
+
+// Diagnostics returns the broker host, the subscribed topics and the features of the broker on the last connection.
+func (o *mqttAgentOptions) Diagnostics() options.TransportDiagnostics {
+	return o.connection.diagnostics(o.mqttOptions().BrokerHost)
+}
+
 func (o *mqttAgentOptions) ErrorChan() <-chan error {
 	return o.errorChan
 }
+
+// Reload rebuilds the MQTT options from the given config file, the cloudevents client is reconnected with the new
+// options.
+func (o *mqttAgentOptions) Reload(configPath string) error {
+	mqttOptions, err := BuildMQTTOptionsFromFlags(configPath)
+	if err != nil {
+		return err
+	}
+
+	o.Lock()
+	// the tenant and the client ID suffix of a client can not be changed by reloading
+	mqttOptions.TenantID = o.MQTTOptions.TenantID
+	mqttOptions.ClientIDSuffix = o.MQTTOptions.ClientIDSuffix
+	o.MQTTOptions = *mqttOptions
+	o.Unlock()
+
+	// notify the cloudevents client to reconnect with the new options
+	go func() {
+		o.errorChan <- fmt.Errorf("the MQTT options are reloaded from %s", configPath)
+	}()
+
+	return nil
+}
+
+func (o *mqttAgentOptions) mqttOptions() MQTTOptions {
+	o.RLock()
+	defer o.RUnlock()
+	return o.MQTTOptions
+}
@@ -82,6 +82,28 @@ func TestAgentContext(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "register",
+			event: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: types.RegistrationDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              types.RegisterRequestAction,
+				}
+
+				evt := cloudevents.NewEvent()
+				evt.SetType(eventType.String())
+				evt.SetExtension("originalsource", types.SourceAll)
+				evt.SetExtension("clustername", "cluster1")
+				return evt
+			}(),
+			expectedTopic: "clusters/cluster1/agentbroadcast",
+			assertError: func(err error) {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+			},
+		},
 		{
 			name: "send status no original source",
 			event: func() cloudevents.Event {
@@ -0,0 +1,80 @@
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/klog/v2"
+)
+
+// ErrClientIDCollision is reported when the broker disconnects the client because another client connects with the
+// same client ID and takes over its session.
+var ErrClientIDCollision = errors.New("client ID collision")
+
+// clientIDs generates the client IDs of the connections of a client. The ClientIDSuffix of the options is appended to
+// the client ID, and a random suffix is appended to each connection if the UniqueClientID is set or the client ID has
+// collided with another client, so the clients stop taking over the sessions of each other.
+type clientIDs struct {
+	sync.Mutex
+	clientID string
+	collided bool
+}
+
+func newClientIDs(clientID string) *clientIDs {
+	return &clientIDs{clientID: clientID}
+}
+
+// next returns the client ID of a new connection with the given options.
+func (c *clientIDs) next(o MQTTOptions) string {
+	c.Lock()
+	defer c.Unlock()
+
+	clientID := c.clientID
+	if len(o.ClientIDSuffix) != 0 {
+		clientID = fmt.Sprintf("%s-%s", clientID, o.ClientIDSuffix)
+	}
+
+	if o.UniqueClientID || c.collided {
+		clientID = fmt.Sprintf("%s-%s", clientID, rand.String(8))
+	}
+
+	return clientID
+}
+
+// errorHandler returns the error handler of a connection, the following connections are suffixed with the random
+// suffixes once a client ID collision is reported.
+func (c *clientIDs) errorHandler(handler func(error)) func(error) {
+	return func(err error) {
+		if errors.Is(err, ErrClientIDCollision) {
+			c.Lock()
+			if !c.collided {
+				klog.Warningf("the client ID %s is used by another client, the following connections use unique client IDs",
+					c.clientID)
+				c.collided = true
+			}
+			c.Unlock()
+		}
+
+		handler(err)
+	}
+}
+
+// disconnectError returns the error of a disconnection that is initiated by the broker, ErrClientIDCollision is
+// returned if the session of the client is taken over by another client with the same client ID.
+func disconnectError(clientID string, d *paho.Disconnect) error {
+	reason := ""
+	if d.Properties != nil && len(d.Properties.ReasonString) != 0 {
+		reason = fmt.Sprintf(" (%s)", d.Properties.ReasonString)
+	}
+
+	if d.ReasonCode == packets.DisconnectSessionTakenOver {
+		return fmt.Errorf("%w: the session of the client %s is taken over by another client%s",
+			ErrClientIDCollision, clientID, reason)
+	}
+
+	return fmt.Errorf("the broker disconnected the client %s with the reason code 0x%02X%s", clientID, d.ReasonCode, reason)
+}
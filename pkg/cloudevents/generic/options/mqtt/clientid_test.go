@@ -0,0 +1,153 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+	mochimqtt "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestClientIDs(t *testing.T) {
+	clientIDs := newClientIDs("hub1-client")
+
+	if clientID := clientIDs.next(MQTTOptions{}); clientID != "hub1-client" {
+		t.Errorf("expected hub1-client, but got %s", clientID)
+	}
+
+	if clientID := clientIDs.next(MQTTOptions{ClientIDSuffix: "pod1"}); clientID != "hub1-client-pod1" {
+		t.Errorf("expected hub1-client-pod1, but got %s", clientID)
+	}
+
+	unique := MQTTOptions{ClientIDSuffix: "pod1", UniqueClientID: true}
+	first, second := clientIDs.next(unique), clientIDs.next(unique)
+	if !strings.HasPrefix(first, "hub1-client-pod1-") || first == second {
+		t.Errorf("expected unique client IDs, but got %s and %s", first, second)
+	}
+
+	// the client IDs are suffixed once a collision is reported
+	var reported error
+	clientIDs.errorHandler(func(err error) { reported = err })(
+		disconnectError("hub1-client", &paho.Disconnect{ReasonCode: packets.DisconnectSessionTakenOver}))
+	if !errors.Is(reported, ErrClientIDCollision) {
+		t.Errorf("expected client ID collision, but got %v", reported)
+	}
+	if clientID := clientIDs.next(MQTTOptions{}); !strings.HasPrefix(clientID, "hub1-client-") {
+		t.Errorf("expected a suffixed client ID, but got %s", clientID)
+	}
+}
+
+func TestDisconnectError(t *testing.T) {
+	cases := []struct {
+		name              string
+		disconnect        *paho.Disconnect
+		expectedCollision bool
+		expectedMessage   string
+	}{
+		{
+			name:              "session taken over",
+			disconnect:        &paho.Disconnect{ReasonCode: packets.DisconnectSessionTakenOver},
+			expectedCollision: true,
+			expectedMessage:   "client ID collision: the session of the client hub1-client is taken over by another client",
+		},
+		{
+			name: "server shutting down",
+			disconnect: &paho.Disconnect{
+				ReasonCode: packets.DisconnectServerShuttingDown,
+				Properties: &paho.DisconnectProperties{ReasonString: "maintenance"},
+			},
+			expectedMessage: "the broker disconnected the client hub1-client with the reason code 0x8B (maintenance)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := disconnectError("hub1-client", c.disconnect)
+			if errors.Is(err, ErrClientIDCollision) != c.expectedCollision {
+				t.Errorf("expected collision %v, but got %v", c.expectedCollision, err)
+			}
+			if err.Error() != c.expectedMessage {
+				t.Errorf("expected %s, but got %s", c.expectedMessage, err.Error())
+			}
+		})
+	}
+}
+
+func TestClientIDCollision(t *testing.T) {
+	ln := newLocalListener(t)
+	address := ln.Addr().String()
+	ln.Close()
+
+	broker := mochimqtt.New(&mochimqtt.Options{})
+	if err := broker.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.AddListener(listeners.NewTCP("mqtt-test-tcp", address, nil)); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = broker.Serve()
+	}()
+	defer broker.Close()
+
+	options, err := BuildMQTTOptionsFromConfig(&MQTTConfig{
+		BrokerHost: address,
+		Topics: &types.Topics{
+			SourceEvents: "sources/hub1/clusters/+/sourceevents",
+			AgentEvents:  "sources/hub1/clusters/+/agentevents",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.DialTimeout = 5 * time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the sources are built with the same client ID
+	first := NewSourceOptions(options, "hub1-client", "hub1")
+	second := NewSourceOptions(options, "hub1-client", "hub1")
+
+	// wait for the broker to start
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			_, err := first.CloudEventsOptions.Client(ctx)
+			return err == nil, nil
+		}); err != nil {
+		t.Fatalf("failed to connect to the broker, %v", err)
+	}
+
+	if _, err := second.CloudEventsOptions.Client(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-first.CloudEventsOptions.ErrorChan():
+		if !errors.Is(err, ErrClientIDCollision) {
+			t.Errorf("expected client ID collision, but got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected client ID collision is reported")
+	}
+
+	// the first source reconnects with a unique client ID, so the second source keeps its session
+	if _, err := first.CloudEventsOptions.Client(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-second.CloudEventsOptions.ErrorChan():
+		t.Errorf("unexpected error %v", err)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
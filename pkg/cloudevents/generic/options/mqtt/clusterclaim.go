@@ -0,0 +1,252 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// DefaultClusterClaimExpiry is the default expiry interval of the retained claims of the clusters.
+const DefaultClusterClaimExpiry = 30 * time.Second
+
+// defaultClusterClaimSettle is the time to wait for the retained claim of a cluster after the claim topic is
+// subscribed.
+const defaultClusterClaimSettle = time.Second
+
+// clusterClaimRecord is the payload of a retained claim.
+type clusterClaimRecord struct {
+	Holder string `json:"holder"`
+}
+
+// observedClaim is the latest retained claim of a cluster that is received by the claimer.
+type observedClaim struct {
+	holder     string
+	expireTime time.Time
+}
+
+type clusterClaimer struct {
+	sync.Mutex
+	mqttOptions *MQTTOptions
+	expiry      time.Duration
+	settle      time.Duration
+	client      *paho.Client
+	broken      atomic.Bool
+	subscribed  map[string]bool
+
+	observedLock sync.RWMutex
+	observed     map[string]observedClaim
+}
+
+// NewClusterClaimer returns a ClusterClaimer that claims a cluster with a retained message on the claim topic of the
+// cluster, `clusters/<cluster name>/claim`, the message expires with the expiry interval unless it is renewed by its
+// agent. The broker must support the retained messages and the message expiry, and the agents must be authorized to
+// subscribe and publish the claim topics of their clusters. A claim is last writer wins, if two agents claim a cluster
+// at the same time, the agent whose claim is retained keeps the claim, and the other agent finds the claim is held by
+// another agent when it renews its claim. If the expiry is less than or equal to zero, the DefaultClusterClaimExpiry
+// is used.
+func NewClusterClaimer(mqttOptions *MQTTOptions, expiry time.Duration) options.ClusterClaimer {
+	if expiry <= 0 {
+		expiry = DefaultClusterClaimExpiry
+	}
+
+	return &clusterClaimer{
+		mqttOptions: mqttOptions,
+		expiry:      expiry,
+		settle:      defaultClusterClaimSettle,
+		subscribed:  map[string]bool{},
+		observed:    map[string]observedClaim{},
+	}
+}
+
+func (c *clusterClaimer) Claim(ctx context.Context, clusterName, agentID string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	topic := c.claimTopic(clusterName)
+	if err := c.subscribe(ctx, topic, agentID); err != nil {
+		return err
+	}
+
+	if holder, ok := c.holder(topic); ok && holder != agentID {
+		return &options.ClusterClaimedError{ClusterName: clusterName, Holder: holder}
+	}
+
+	payload, err := json.Marshal(&clusterClaimRecord{Holder: agentID})
+	if err != nil {
+		return err
+	}
+
+	return c.publish(ctx, topic, payload)
+}
+
+func (c *clusterClaimer) Release(ctx context.Context, clusterName, agentID string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	topic := c.claimTopic(clusterName)
+	if holder, ok := c.holder(topic); !ok || holder != agentID {
+		return nil
+	}
+
+	// the retained claim is removed with an empty payload
+	if err := c.publish(ctx, topic, nil); err != nil {
+		return err
+	}
+
+	c.observedLock.Lock()
+	delete(c.observed, topic)
+	c.observedLock.Unlock()
+	return nil
+}
+
+func (c *clusterClaimer) claimTopic(clusterName string) string {
+	return c.mqttOptions.topic(fmt.Sprintf("clusters/%s/claim", clusterName))
+}
+
+// holder returns the holder of the live claim of the claim topic.
+func (c *clusterClaimer) holder(topic string) (string, bool) {
+	c.observedLock.RLock()
+	defer c.observedLock.RUnlock()
+
+	claim, ok := c.observed[topic]
+	if !ok || time.Now().After(claim.expireTime) {
+		return "", false
+	}
+
+	return claim.holder, true
+}
+
+// subscribe subscribes the claim topic, the retained claim of the topic is received within the settle time after the
+// topic is subscribed.
+func (c *clusterClaimer) subscribe(ctx context.Context, topic, agentID string) error {
+	if err := c.connect(ctx, agentID); err != nil {
+		return err
+	}
+
+	if c.subscribed[topic] {
+		return nil
+	}
+
+	if _, err := c.client.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: map[string]paho.SubscribeOptions{topic: {QoS: 1}},
+	}); err != nil {
+		c.reset()
+		return fmt.Errorf("failed to subscribe the claim topic %s, %v", topic, err)
+	}
+	c.subscribed[topic] = true
+
+	select {
+	case <-time.After(c.settle):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *clusterClaimer) publish(ctx context.Context, topic string, payload []byte) error {
+	if c.client == nil {
+		return fmt.Errorf("the claimer is not connected")
+	}
+
+	expiry := uint32(math.Ceil(c.expiry.Seconds()))
+	if _, err := c.client.Publish(ctx, &paho.Publish{
+		QoS:        1,
+		Retain:     true,
+		Topic:      topic,
+		Payload:    payload,
+		Properties: &paho.PublishProperties{MessageExpiry: &expiry},
+	}); err != nil {
+		c.reset()
+		return fmt.Errorf("failed to publish the claim to %s, %v", topic, err)
+	}
+
+	return nil
+}
+
+// connect connects the claimer to the broker if it is not connected or its connection is broken.
+func (c *clusterClaimer) connect(ctx context.Context, agentID string) error {
+	if c.client != nil && !c.broken.Load() {
+		return nil
+	}
+	c.reset()
+
+	netConn, err := c.mqttOptions.GetNetConn()
+	if err != nil {
+		return err
+	}
+
+	clientID := fmt.Sprintf("%s-claimer", agentID)
+	c.broken.Store(false)
+	client := paho.NewClient(paho.ClientConfig{
+		ClientID: clientID,
+		Conn:     netConn,
+		Router:   paho.NewSingleHandlerRouter(c.observe),
+		OnClientError: func(err error) {
+			klog.V(4).Infof("the claimer %s is disconnected, %v", clientID, err)
+			c.broken.Store(true)
+		},
+		OnServerDisconnect: func(d *paho.Disconnect) {
+			klog.V(4).Infof("the claimer %s is disconnected, %v", clientID, disconnectError(clientID, d))
+			c.broken.Store(true)
+		},
+	})
+
+	connect := c.mqttOptions.GetMQTTConnectOption(clientID)
+	// the claimer does not resume its subscriptions
+	connect.CleanStart = true
+	connect.Properties.SessionExpiryInterval = nil
+	connAck, err := client.Connect(ctx, connect)
+	if err != nil {
+		return fmt.Errorf("failed to connect the claimer to the broker, %v", err)
+	}
+	if connAck.ReasonCode != 0 {
+		return fmt.Errorf("failed to connect the claimer to the broker, the reason code 0x%02X", connAck.ReasonCode)
+	}
+
+	c.client = client
+	return nil
+}
+
+// reset closes the connection of the claimer, the claim topics are subscribed again after it is reconnected.
+func (c *clusterClaimer) reset() {
+	if c.client != nil {
+		_ = c.client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+	}
+
+	c.client = nil
+	c.subscribed = map[string]bool{}
+}
+
+// observe records the retained claims that are received from the claim topics.
+func (c *clusterClaimer) observe(p *paho.Publish) {
+	c.observedLock.Lock()
+	defer c.observedLock.Unlock()
+
+	if len(p.Payload) == 0 {
+		delete(c.observed, p.Topic)
+		return
+	}
+
+	record := &clusterClaimRecord{}
+	if err := json.Unmarshal(p.Payload, record); err != nil {
+		klog.Errorf("failed to unmarshal the claim of the topic %s, %v", p.Topic, err)
+		return
+	}
+
+	// the broker sets the remaining expiry interval of the retained claim
+	expiry := c.expiry
+	if p.Properties != nil && p.Properties.MessageExpiry != nil {
+		expiry = time.Duration(*p.Properties.MessageExpiry) * time.Second
+	}
+
+	c.observed[p.Topic] = observedClaim{holder: record.Holder, expireTime: time.Now().Add(expiry)}
+}
@@ -0,0 +1,92 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mochimqtt "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestClusterClaimer(t *testing.T) {
+	ln := newLocalListener(t)
+	address := ln.Addr().String()
+	ln.Close()
+
+	broker := mochimqtt.New(&mochimqtt.Options{})
+	if err := broker.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.AddListener(listeners.NewTCP("mqtt-test-tcp", address, nil)); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = broker.Serve()
+	}()
+	defer broker.Close()
+
+	mqttOptions, err := BuildMQTTOptionsFromConfig(&MQTTConfig{
+		BrokerHost: address,
+		Topics: &types.Topics{
+			SourceEvents: "sources/hub1/clusters/+/sourceevents",
+			AgentEvents:  "sources/hub1/clusters/+/agentevents",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mqttOptions.DialTimeout = 5 * time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newClaimer := func() *clusterClaimer {
+		claimer := NewClusterClaimer(mqttOptions, time.Minute).(*clusterClaimer)
+		claimer.settle = 200 * time.Millisecond
+		return claimer
+	}
+	claimer1, claimer2 := newClaimer(), newClaimer()
+
+	// wait for the broker to start
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			return claimer1.Claim(ctx, "cluster1", "agent1") == nil, nil
+		}); err != nil {
+		t.Fatalf("failed to claim the cluster, %v", err)
+	}
+
+	// the claim is renewed by its holder
+	if err := claimer1.Claim(ctx, "cluster1", "agent1"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	var claimedErr *options.ClusterClaimedError
+	if err := claimer2.Claim(ctx, "cluster1", "agent2"); !errors.As(err, &claimedErr) || claimedErr.Holder != "agent1" {
+		t.Errorf("expected the cluster is claimed by agent1, but got %v", err)
+	}
+
+	// the cluster is not released by an agent that does not hold its claim
+	if err := claimer2.Release(ctx, "cluster1", "agent2"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := claimer2.Claim(ctx, "cluster1", "agent2"); !errors.As(err, &claimedErr) {
+		t.Errorf("expected the cluster is claimed, but got %v", err)
+	}
+
+	if err := claimer1.Release(ctx, "cluster1", "agent1"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			return claimer2.Claim(ctx, "cluster1", "agent2") == nil, nil
+		}); err != nil {
+		t.Errorf("expected the released cluster is claimed by agent2, but got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package mqtt
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// connection records the CONNACK and the subscriptions of the last connection of a client.
+type connection struct {
+	sync.RWMutex
+	connack       *packets.Connack
+	subscriptions []string
+}
+
+func (c *connection) connected(connack *packets.Connack, subscribe *paho.Subscribe) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.connack = connack
+	c.subscriptions = []string{}
+	if subscribe != nil {
+		for topic := range subscribe.Subscriptions {
+			c.subscriptions = append(c.subscriptions, topic)
+		}
+	}
+	sort.Strings(c.subscriptions)
+}
+
+// sessionPresent returns true if the broker resumed the session of the client on the last connection.
+func (c *connection) sessionPresent() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.connack != nil && c.connack.SessionPresent
+}
+
+// diagnostics returns the diagnostics of the last connection to the given broker, the features are the properties of
+// the CONNACK, the absent properties are reported with their default values of the MQTT v5 specification.
+func (c *connection) diagnostics(brokerHost string) options.TransportDiagnostics {
+	c.RLock()
+	defer c.RUnlock()
+
+	diagnostics := options.TransportDiagnostics{
+		BrokerAddress: brokerHost,
+		Subscriptions: append([]string{}, c.subscriptions...),
+	}
+
+	if c.connack == nil {
+		return diagnostics
+	}
+
+	props := c.connack.Properties
+	if props == nil {
+		props = &packets.Properties{}
+	}
+
+	diagnostics.Features = map[string]string{
+		"sessionPresent":                strconv.FormatBool(c.connack.SessionPresent),
+		"receiveMaximum":                strconv.Itoa(int(uint16Or(props.ReceiveMaximum, 65535))),
+		"topicAliasMaximum":             strconv.Itoa(int(uint16Or(props.TopicAliasMaximum, 0))),
+		"maximumQoS":                    strconv.Itoa(int(byteOr(props.MaximumQOS, 2))),
+		"retainAvailable":               strconv.FormatBool(byteOr(props.RetainAvailable, 1) == 1),
+		"wildcardSubscriptionAvailable": strconv.FormatBool(byteOr(props.WildcardSubAvailable, 1) == 1),
+		"sharedSubscriptionAvailable":   strconv.FormatBool(byteOr(props.SharedSubAvailable, 1) == 1),
+	}
+	if props.MaximumPacketSize != nil {
+		diagnostics.Features["maximumPacketSize"] = strconv.FormatUint(uint64(*props.MaximumPacketSize), 10)
+	}
+	if props.ServerKeepAlive != nil {
+		diagnostics.Features["serverKeepAlive"] = strconv.Itoa(int(*props.ServerKeepAlive))
+	}
+	if props.SessionExpiryInterval != nil {
+		diagnostics.Features["sessionExpiryInterval"] = strconv.FormatUint(uint64(*props.SessionExpiryInterval), 10)
+	}
+	if len(props.AssignedClientID) != 0 {
+		diagnostics.Features["assignedClientID"] = props.AssignedClientID
+	}
+
+	return diagnostics
+}
+
+func uint16Or(v *uint16, defaultValue uint16) uint16 {
+	if v == nil {
+		return defaultValue
+	}
+	return *v
+}
+
+func byteOr(v *byte, defaultValue byte) byte {
+	if v == nil {
+		return defaultValue
+	}
+	return *v
+}
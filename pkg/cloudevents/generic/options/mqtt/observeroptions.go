@@ -0,0 +1,96 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/eclipse/paho.golang/paho"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type mqttObserverOptions struct {
+	MQTTOptions
+	errorChan  chan error
+	clientIDs  *clientIDs
+	connection connection
+}
+
+// NewObserverOptions returns the options of an observer, the observer subscribes to the source events and the agent
+// events of all sources and clusters, the broker ACLs are expected to allow the client to read these topics.
+func NewObserverOptions(mqttOptions *MQTTOptions, clientID string) *options.CloudEventsObserverOptions {
+	return &options.CloudEventsObserverOptions{
+		CloudEventsOptions: &mqttObserverOptions{
+			MQTTOptions: *mqttOptions,
+			errorChan:   make(chan error),
+			clientIDs:   newClientIDs(clientID),
+		},
+		ObserverID: clientID,
+		TenantID:   mqttOptions.TenantID,
+	}
+}
+
+func (o *mqttObserverOptions) WithContext(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+	return nil, fmt.Errorf("the observer does not send events")
+}
+
+func (o *mqttObserverOptions) Client(ctx context.Context) (cloudevents.Client, error) {
+	mqttOptions := o.MQTTOptions
+
+	sourceEventsTopic, err := observedTopic(mqttOptions.Topics.SourceEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	agentEventsTopic, err := observedTopic(mqttOptions.Topics.AgentEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribe := &paho.Subscribe{
+		Subscriptions: map[string]paho.SubscribeOptions{
+			// receiving the source events of all sources and clusters
+			mqttOptions.topic(sourceEventsTopic): {QoS: byte(mqttOptions.SubQoS)},
+			// receiving the agent events of all sources and clusters
+			mqttOptions.topic(agentEventsTopic): {QoS: byte(mqttOptions.SubQoS)},
+		},
+	}
+
+	receiver, connack, err := mqttOptions.newCloudEventsClient(
+		ctx,
+		o.clientIDs.next(mqttOptions),
+		o.clientIDs.errorHandler(func(err error) {
+			o.errorChan <- err
+		}),
+		WithSubscribe(subscribe),
+	)
+	if err != nil {
+		return nil, err
+	}
+	o.connection.connected(connack, subscribe)
+	return receiver, nil
+}
+
+func (o *mqttObserverOptions) ErrorChan() <-chan error {
+	return o.errorChan
+}
+
+// Diagnostics returns the broker host, the subscribed topics and the features of the broker on the last connection.
+func (o *mqttObserverOptions) Diagnostics() options.TransportDiagnostics {
+	return o.connection.diagnostics(o.BrokerHost)
+}
+
+// observedTopic returns the topic that matches the given events topic of all sources and clusters, the shared
+// subscription prefix is removed, so the observer does not take the events from the subscribers of the shared group.
+func observedTopic(topic string) (string, error) {
+	matches := regexp.MustCompile(types.EventsTopicPattern).FindStringSubmatch(topic)
+	if len(matches) == 0 {
+		return "", fmt.Errorf("invalid events topic %q, it should match `%s`", topic, types.EventsTopicPattern)
+	}
+
+	// e.g. sources/+/clusters/+/sourceevents
+	return fmt.Sprintf("%s/+/%s/+/%s", matches[2], matches[4], matches[6]), nil
+}
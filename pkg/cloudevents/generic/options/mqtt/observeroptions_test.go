@@ -0,0 +1,47 @@
+package mqtt
+
+import "testing"
+
+func TestObservedTopic(t *testing.T) {
+	cases := []struct {
+		name          string
+		topic         string
+		expectedTopic string
+		expectedErr   bool
+	}{
+		{
+			name:          "source events topic",
+			topic:         "sources/hub1/clusters/+/sourceevents",
+			expectedTopic: "sources/+/clusters/+/sourceevents",
+		},
+		{
+			name:          "shared agent events topic",
+			topic:         "$share/hub1/sources/hub1/clusters/+/agentevents",
+			expectedTopic: "sources/+/clusters/+/agentevents",
+		},
+		{
+			name:        "invalid topic",
+			topic:       "sources/hub1/sourcebroadcast",
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			topic, err := observedTopic(c.topic)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if topic != c.expectedTopic {
+				t.Errorf("expected %s, but got %s", c.expectedTopic, topic)
+			}
+		})
+	}
+}
@@ -5,18 +5,22 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
-	cloudeventsmqtt "github.com/cloudevents/sdk-go/protocol/mqtt_paho/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventsclient "github.com/cloudevents/sdk-go/v2/client"
 	"github.com/eclipse/paho.golang/packets"
 	"github.com/eclipse/paho.golang/paho"
 	"gopkg.in/yaml.v2"
 	"k8s.io/apimachinery/pkg/util/errors"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
@@ -29,15 +33,61 @@ type MQTTOptions struct {
 	CAFile         string
 	ClientCertFile string
 	ClientKeyFile  string
+	ServerName     string
 	KeepAlive      uint16
 	DialTimeout    time.Duration
 	PubQoS         int
 	SubQoS         int
+	ContentMode    options.ContentMode
+
+	// TopicAliasMaximum is the number of the MQTT v5 topic aliases that are used on a connection, the topics of the
+	// published events are replaced with the aliases after their first publishes, and the broker is allowed to send
+	// the same number of topic aliases to the client. It must not exceed the topic alias maximum of the broker, the
+	// topic aliases are disabled if it is zero.
+	TopicAliasMaximum uint16
+
+	// MessageExpiry is the MQTT v5 message expiry interval of the published events, the broker discards the queued and
+	// retained events that are not delivered within the interval. The events never expire if it is zero.
+	MessageExpiry time.Duration
+
+	// RetainSpecs publishes the resource spec events as the retained messages on the topics of the resources, so the
+	// agents receive the latest spec of each resource once they subscribe without waiting for a resync. The agents
+	// subscribe to the topics of the resources if it is set.
+	RetainSpecs bool
+
+	// RetainedDeleteExpiry is the expiry interval of the retained spec events that delete the resources, it does not
+	// exceed the MessageExpiry. DefaultRetainedDeleteExpiry is used if it is zero.
+	RetainedDeleteExpiry time.Duration
+
+	// ClientIDSuffix is appended to the client IDs of the connections, e.g. the name of the pod, so the replicas that
+	// are built with the same client ID connect with the distinct client IDs. It is kept when the options are reloaded.
+	ClientIDSuffix string
+
+	// UniqueClientID appends a random suffix to the client ID of each connection. The broker disconnects a client once
+	// another client connects with the same client ID, the collision is reported with the ErrClientIDCollision, and
+	// the random suffixes are appended after the collision even if it is not set.
+	UniqueClientID bool
+
+	// SessionExpiry is the interval that the broker keeps the session of the client after it disconnects, the client
+	// connects without the clean start to resume its session, and the broker queues the QoS 1 events to the client
+	// while it is offline. The source/agent client skips the resync if its session is resumed on reconnecting. The
+	// session is cleaned on each connection if it is zero.
+	SessionExpiry time.Duration
+
+	// TenantID is the tenant of the client, the topics are prefixed with the tenant segment, so the clients of the
+	// different tenants can share a broker.
+	TenantID string
+
+	// PublishAckTimeout is the timeout to wait for the acknowledgment of a published event, e.g. the PUBACK of a QoS 1
+	// event, a publish that is not acknowledged in time fails with the options.PublishTimeoutError. The publishes are
+	// only bounded by the deadlines of their contexts and the packet timeout of the client if it is zero.
+	PublishAckTimeout time.Duration
 }
 
 // MQTTConfig holds the information needed to build connect to MQTT broker as a given user.
 type MQTTConfig struct {
-	// BrokerHost is the host of the MQTT broker (hostname:port).
+	// BrokerHost is the host of the MQTT broker (hostname:port). If the broker exposes a WebSocket listener, it can be
+	// a WebSocket URL (ws://hostname:port/path or wss://hostname:port/path), the path is /mqtt by default.
 	BrokerHost string `json:"brokerHost" yaml:"brokerHost"`
 
 	// Username is the username for basic authentication to connect the MQTT broker.
@@ -51,6 +101,9 @@ type MQTTConfig struct {
 	ClientCertFile string `json:"clientCertFile,omitempty" yaml:"clientCertFile,omitempty"`
 	// ClientKeyFile is the file path to a client key file for TLS.
 	ClientKeyFile string `json:"clientKeyFile,omitempty" yaml:"clientKeyFile,omitempty"`
+	// ServerName overrides the server name that is used to verify the broker certificate and is sent in the TLS SNI,
+	// e.g. the broker is accessed through an address that is not in its certificate.
+	ServerName string `json:"serverName,omitempty" yaml:"serverName,omitempty"`
 
 	// KeepAlive is the keep alive time in seconds for MQTT clients, by default is 60s
 	KeepAlive *uint16 `json:"keepAlive,omitempty" yaml:"keepAlive,omitempty"`
@@ -63,11 +116,67 @@ type MQTTConfig struct {
 	// SubQoS is the Qos for subscribe, by default is 1
 	SubQoS *int `json:"subQoS,omitempty" yaml:"subQoS,omitempty"`
 
+	// ContentMode is the CloudEvents content mode (binary or structured) to publish the events, by default is binary
+	ContentMode options.ContentMode `json:"contentMode,omitempty" yaml:"contentMode,omitempty"`
+
+	// TopicAliasMaximum is the number of the MQTT v5 topic aliases that are used on a connection, by default is 0 and
+	// the topic aliases are disabled. It must not exceed the topic alias maximum of the broker.
+	TopicAliasMaximum uint16 `json:"topicAliasMaximum,omitempty" yaml:"topicAliasMaximum,omitempty"`
+
+	// MessageExpiry is the MQTT v5 message expiry interval of the published events, by default the events never
+	// expire. It is rounded up to seconds.
+	MessageExpiry *time.Duration `json:"messageExpiry,omitempty" yaml:"messageExpiry,omitempty"`
+
+	// RetainSpecs publishes the resource spec events as the retained messages, so the agents receive the latest spec
+	// of each resource once they connect. It must be set for both the sources and the agents, by default is false.
+	RetainSpecs bool `json:"retainSpecs,omitempty" yaml:"retainSpecs,omitempty"`
+
+	// RetainedDeleteExpiry is the expiry interval of the retained spec events that delete the resources, by default
+	// is 10m. It is rounded up to seconds.
+	RetainedDeleteExpiry *time.Duration `json:"retainedDeleteExpiry,omitempty" yaml:"retainedDeleteExpiry,omitempty"`
+
+	// UniqueClientID appends a random suffix to the client ID of each connection, so the clients that are configured
+	// with the same client ID do not take over the sessions of each other, by default is false.
+	UniqueClientID bool `json:"uniqueClientID,omitempty" yaml:"uniqueClientID,omitempty"`
+
+	// SessionExpiry is the interval that the broker keeps the session of the client after it disconnects, the events
+	// are queued by the broker while the client is offline, so a short outage does not require a resync. By default
+	// the session is cleaned on each connection. It can not be used with the uniqueClientID, and it is rounded up to
+	// seconds.
+	SessionExpiry *time.Duration `json:"sessionExpiry,omitempty" yaml:"sessionExpiry,omitempty"`
+
 	// Topics are MQTT topics for resource spec, status and resync.
 	Topics *types.Topics `json:"topics,omitempty" yaml:"topics,omitempty"`
+
+	// TenantID is the tenant of the client, the topics are prefixed with `tenants/<tenantID>/`, and the events of the
+	// other tenants are rejected, so the hubs of the different tenants can share a broker.
+	TenantID string `json:"tenantID,omitempty" yaml:"tenantID,omitempty"`
+
+	// PublishAckTimeout is the timeout to wait for the acknowledgment of a published event, by default the publishes
+	// are only bounded by the deadlines of their contexts.
+	PublishAckTimeout *time.Duration `json:"publishAckTimeout,omitempty" yaml:"publishAckTimeout,omitempty"`
+}
+
+// envOverrides maps the environment variables to the config fields that they override.
+func (c *MQTTConfig) envOverrides() map[string]*string {
+	return map[string]*string{
+		"MQTT_BROKER_HOST":      &c.BrokerHost,
+		"MQTT_USERNAME":         &c.Username,
+		"MQTT_PASSWORD":         &c.Password,
+		"MQTT_CA_FILE":          &c.CAFile,
+		"MQTT_CLIENT_CERT_FILE": &c.ClientCertFile,
+		"MQTT_CLIENT_KEY_FILE":  &c.ClientKeyFile,
+	}
 }
 
-// BuildMQTTOptionsFromFlags builds configs from a config filepath.
+// BuildMQTTOptionsFromFlags builds configs from a config filepath. The config fields can be overridden by the
+// following environment variables:
+//   - MQTT_BROKER_HOST
+//   - MQTT_USERNAME
+//   - MQTT_PASSWORD
+//   - MQTT_CA_FILE
+//   - MQTT_CLIENT_CERT_FILE
+//   - MQTT_CLIENT_KEY_FILE
 func BuildMQTTOptionsFromFlags(configPath string) (*MQTTOptions, error) {
 	configData, err := os.ReadFile(configPath)
 	if err != nil {
@@ -79,6 +188,17 @@ func BuildMQTTOptionsFromFlags(configPath string) (*MQTTOptions, error) {
 		return nil, err
 	}
 
+	for env, field := range config.envOverrides() {
+		if value, ok := os.LookupEnv(env); ok {
+			*field = value
+		}
+	}
+
+	return BuildMQTTOptionsFromConfig(config)
+}
+
+// BuildMQTTOptionsFromConfig validates the given config and builds the MQTT options from it.
+func BuildMQTTOptionsFromConfig(config *MQTTConfig) (*MQTTOptions, error) {
 	if config.BrokerHost == "" {
 		return nil, fmt.Errorf("brokerHost is required")
 	}
@@ -91,22 +211,40 @@ func BuildMQTTOptionsFromFlags(configPath string) (*MQTTOptions, error) {
 		return nil, fmt.Errorf("setting clientCertFile and clientKeyFile requires caFile")
 	}
 
+	if err := validateBrokerHost(config.BrokerHost); err != nil {
+		return nil, err
+	}
+
+	if err := options.ValidateContentMode(config.ContentMode); err != nil {
+		return nil, err
+	}
+
 	if err := validateTopics(config.Topics); err != nil {
 		return nil, err
 	}
 
+	if err := types.ValidateTenantID(config.TenantID); err != nil {
+		return nil, err
+	}
+
 	options := &MQTTOptions{
-		BrokerHost:     config.BrokerHost,
-		Username:       config.Username,
-		Password:       config.Password,
-		CAFile:         config.CAFile,
-		ClientCertFile: config.ClientCertFile,
-		ClientKeyFile:  config.ClientKeyFile,
-		KeepAlive:      60,
-		PubQoS:         1,
-		SubQoS:         1,
-		DialTimeout:    60 * time.Second,
-		Topics:         *config.Topics,
+		BrokerHost:        config.BrokerHost,
+		Username:          config.Username,
+		Password:          config.Password,
+		CAFile:            config.CAFile,
+		ClientCertFile:    config.ClientCertFile,
+		ClientKeyFile:     config.ClientKeyFile,
+		ServerName:        config.ServerName,
+		KeepAlive:         60,
+		PubQoS:            1,
+		SubQoS:            1,
+		DialTimeout:       60 * time.Second,
+		ContentMode:       config.ContentMode,
+		Topics:            *config.Topics,
+		TenantID:          config.TenantID,
+		TopicAliasMaximum: config.TopicAliasMaximum,
+		RetainSpecs:       config.RetainSpecs,
+		UniqueClientID:    config.UniqueClientID,
 	}
 
 	if config.KeepAlive != nil {
@@ -125,34 +263,73 @@ func BuildMQTTOptionsFromFlags(configPath string) (*MQTTOptions, error) {
 		options.SubQoS = *config.SubQoS
 	}
 
+	if config.MessageExpiry != nil {
+		if *config.MessageExpiry < 0 || *config.MessageExpiry > math.MaxUint32*time.Second {
+			return nil, fmt.Errorf("invalid messageExpiry %v", *config.MessageExpiry)
+		}
+		options.MessageExpiry = *config.MessageExpiry
+	}
+
+	if config.RetainedDeleteExpiry != nil {
+		if *config.RetainedDeleteExpiry <= 0 || *config.RetainedDeleteExpiry > math.MaxUint32*time.Second {
+			return nil, fmt.Errorf("invalid retainedDeleteExpiry %v", *config.RetainedDeleteExpiry)
+		}
+		options.RetainedDeleteExpiry = *config.RetainedDeleteExpiry
+	}
+
+	if config.SessionExpiry != nil {
+		if *config.SessionExpiry <= 0 || *config.SessionExpiry > math.MaxUint32*time.Second {
+			return nil, fmt.Errorf("invalid sessionExpiry %v", *config.SessionExpiry)
+		}
+		if config.UniqueClientID {
+			// the session is bound to the client ID
+			return nil, fmt.Errorf("sessionExpiry can not be used with uniqueClientID")
+		}
+		options.SessionExpiry = *config.SessionExpiry
+	}
+
+	if config.PublishAckTimeout != nil {
+		if *config.PublishAckTimeout <= 0 {
+			return nil, fmt.Errorf("invalid publishAckTimeout %v", *config.PublishAckTimeout)
+		}
+		options.PublishAckTimeout = *config.PublishAckTimeout
+	}
+
 	return options, nil
 }
 
+// topic returns the given topic of the tenant of the client.
+func (o *MQTTOptions) topic(topic string) string {
+	return types.TenantTopic(o.TenantID, topic)
+}
+
 func (o *MQTTOptions) GetNetConn() (net.Conn, error) {
-	if len(o.CAFile) != 0 {
-		certPool, err := x509.SystemCertPool()
-		if err != nil {
-			return nil, err
+	if isWebSocket(o.BrokerHost) {
+		var tlsConfig *tls.Config
+		if strings.HasPrefix(o.BrokerHost, "wss://") {
+			config, err := o.tlsConfig()
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig = config
 		}
 
-		caPEM, err := os.ReadFile(o.CAFile)
+		conn, err := dialWebSocket(o.BrokerHost, o.DialTimeout, tlsConfig)
 		if err != nil {
 			return nil, err
 		}
 
-		if ok := certPool.AppendCertsFromPEM(caPEM); !ok {
-			return nil, fmt.Errorf("invalid CA %s", o.CAFile)
-		}
+		// ensure parallel writes are thread-Safe
+		return packets.NewThreadSafeConn(conn), nil
+	}
 
-		clientCerts, err := tls.LoadX509KeyPair(o.ClientCertFile, o.ClientKeyFile)
+	if len(o.CAFile) != 0 {
+		tlsConfig, err := o.tlsConfig()
 		if err != nil {
 			return nil, err
 		}
 
-		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: o.DialTimeout}, "tcp", o.BrokerHost, &tls.Config{
-			RootCAs:      certPool,
-			Certificates: []tls.Certificate{clientCerts},
-		})
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: o.DialTimeout}, "tcp", o.BrokerHost, tlsConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to MQTT broker %s, %v", o.BrokerHost, err)
 		}
@@ -170,11 +347,45 @@ func (o *MQTTOptions) GetNetConn() (net.Conn, error) {
 	return packets.NewThreadSafeConn(conn), nil
 }
 
+// tlsConfig builds the TLS config to connect the MQTT broker, the system cert pool is used if the CA file is not set.
+func (o *MQTTOptions) tlsConfig() (*tls.Config, error) {
+	certPool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(o.CAFile) != 0 {
+		caPEM, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok := certPool.AppendCertsFromPEM(caPEM); !ok {
+			return nil, fmt.Errorf("invalid CA %s", o.CAFile)
+		}
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:    certPool,
+		ServerName: o.ServerName,
+	}
+
+	if len(o.ClientCertFile) != 0 && len(o.ClientKeyFile) != 0 {
+		clientCerts, err := tls.LoadX509KeyPair(o.ClientCertFile, o.ClientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCerts}
+	}
+
+	return tlsConfig, nil
+}
+
 func (o *MQTTOptions) GetMQTTConnectOption(clientID string) *paho.Connect {
 	connect := &paho.Connect{
 		ClientID:   clientID,
 		KeepAlive:  o.KeepAlive,
-		CleanStart: true,
+		CleanStart: o.SessionExpiry <= 0,
 	}
 
 	if len(o.Username) != 0 {
@@ -187,34 +398,183 @@ func (o *MQTTOptions) GetMQTTConnectOption(clientID string) *paho.Connect {
 		connect.PasswordFlag = true
 	}
 
+	// the problem information is requested explicitly, otherwise it is disabled once the properties are set, and the
+	// brokers may drop the user properties that carry the event attributes
+	connect.Properties = &paho.ConnectProperties{
+		RequestProblemInfo: true,
+		// the user agent is sent with the connect packet, so the brokers can audit the versions of the clients
+		User: paho.UserProperties{{Key: options.UserAgentProperty, Value: options.UserAgent()}},
+	}
+
+	if o.TopicAliasMaximum > 0 {
+		topicAliasMaximum := o.TopicAliasMaximum
+		connect.Properties.TopicAliasMaximum = &topicAliasMaximum
+	}
+
+	if o.SessionExpiry > 0 {
+		sessionExpiry := uint32(math.Ceil(o.SessionExpiry.Seconds()))
+		connect.Properties.SessionExpiryInterval = &sessionExpiry
+	}
+
 	return connect
 }
 
+// GetMQTTPublishOption returns the publish option of the events, the message expiry interval is set if the
+// MessageExpiry is set.
+func (o *MQTTOptions) GetMQTTPublishOption() *paho.Publish {
+	publish := &paho.Publish{QoS: byte(o.PubQoS)}
+
+	if o.MessageExpiry > 0 {
+		messageExpiry := uint32(math.Ceil(o.MessageExpiry.Seconds()))
+		publish.Properties = &paho.PublishProperties{MessageExpiry: &messageExpiry}
+	}
+
+	return publish
+}
+
 func (o *MQTTOptions) GetCloudEventsClient(
 	ctx context.Context,
 	clientID string,
 	errorHandler func(error),
-	clientOpts ...cloudeventsmqtt.Option,
+	clientOpts ...ProtocolOption,
 ) (cloudevents.Client, error) {
+	client, _, err := o.newCloudEventsClient(ctx, clientID, errorHandler, clientOpts...)
+	return client, err
+}
+
+// newCloudEventsClient returns a connected cloudevents client, and the CONNACK of its connection, the CONNACK is nil if
+// it can not be read.
+func (o *MQTTOptions) newCloudEventsClient(
+	ctx context.Context,
+	clientID string,
+	errorHandler func(error),
+	clientOpts ...ProtocolOption,
+) (cloudevents.Client, *packets.Connack, error) {
 	netConn, err := o.GetNetConn()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	conn := newSessionConn(netConn)
+	router := newSessionRouter()
 	config := &paho.ClientConfig{
-		ClientID:      clientID,
-		Conn:          netConn,
-		OnClientError: errorHandler,
+		ClientID: clientID,
+		Conn:     conn,
+		// the router is installed before the client is connected and never replaced, the messages are acknowledged
+		// after they are handled
+		Router:                     router,
+		EnableManualAcknowledgment: true,
+		SendAcksInterval:           sendAcksInterval,
+		OnClientError:              errorHandler,
+		OnServerDisconnect: func(d *paho.Disconnect) {
+			errorHandler(disconnectError(clientID, d))
+		},
+	}
+
+	var hooks []func(*paho.Publish)
+	if o.TopicAliasMaximum > 0 {
+		// the topic aliases are scoped to a connection, so they are assigned from scratch for each connection
+		hooks = append(hooks, newTopicAliases(o.TopicAliasMaximum).assign)
+	}
+
+	var retainer *retainer
+	if o.RetainSpecs {
+		retainer = newRetainer(o.MessageExpiry)
+		hooks = append(hooks, retainer.mark)
 	}
 
-	opts := []cloudeventsmqtt.Option{cloudeventsmqtt.WithConnect(o.GetMQTTConnectOption(clientID))}
-	opts = append(opts, clientOpts...)
-	protocol, err := cloudeventsmqtt.New(ctx, config, opts...)
+	if len(hooks) != 0 {
+		config.PublishHook = func(p *paho.Publish) {
+			for _, hook := range hooks {
+				hook(p)
+			}
+		}
+	}
+
+	mqttProtocol := &protocol{
+		client:            paho.NewClient(*config),
+		router:            router,
+		retainer:          retainer,
+		publishAckTimeout: o.PublishAckTimeout,
+	}
+	for _, opt := range clientOpts {
+		if err := opt(mqttProtocol); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	connack, err := mqttProtocol.client.Connect(ctx, o.GetMQTTConnectOption(clientID))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if connack.ReasonCode != 0 {
+		return nil, nil, fmt.Errorf("failed to connect to %q : %d - %q", conn.RemoteAddr(), connack.ReasonCode,
+			connack.Properties.ReasonString)
+	}
+
+	// invoke the receive callback as a blocking call, so the receiving is throttled when the received events are not
+	// processed in time instead of starting a goroutine for each received event.
+	client, err := cloudevents.NewClient(mqttProtocol, cloudeventsclient.WithBlockingCallback())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, conn.Connack(), nil
+}
+
+// validateBrokerHost validates the broker host, the scheme of a broker URL must be ws or wss.
+func validateBrokerHost(brokerHost string) error {
+	if !strings.Contains(brokerHost, "://") {
+		return nil
+	}
+
+	if !isWebSocket(brokerHost) {
+		return fmt.Errorf("invalid broker host %q, the scheme must be ws or wss", brokerHost)
+	}
+
+	return nil
+}
+
+// topicAliases assigns the topic aliases to the topics of the published events on a connection. A topic is published
+// with its name and a new alias until the aliases are used up, then it is published with the alias only. The topics
+// that have no aliases are published with their names.
+type topicAliases struct {
+	sync.Mutex
+	maximum uint16
+	aliases map[string]uint16
+}
+
+func newTopicAliases(maximum uint16) *topicAliases {
+	return &topicAliases{maximum: maximum, aliases: map[string]uint16{}}
+}
+
+func (a *topicAliases) assign(p *paho.Publish) {
+	a.Lock()
+	defer a.Unlock()
+
+	if p.Properties == nil {
+		p.Properties = &paho.PublishProperties{}
+	}
+
+	// the publish option is reused by the publishes, so the alias of the previous publish is reset
+	p.Properties.TopicAlias = nil
+	if len(p.Topic) == 0 {
+		return
+	}
+
+	if alias, ok := a.aliases[p.Topic]; ok {
+		p.Properties.TopicAlias = &alias
+		p.Topic = ""
+		return
+	}
+
+	if len(a.aliases) >= int(a.maximum) {
+		return
 	}
 
-	return cloudevents.NewClient(protocol)
+	alias := uint16(len(a.aliases) + 1)
+	a.aliases[p.Topic] = alias
+	p.Properties.TopicAlias = &alias
 }
 
 func validateTopics(topics *types.Topics) error {
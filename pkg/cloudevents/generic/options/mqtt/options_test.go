@@ -3,6 +3,7 @@ package mqtt
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"net"
 	"os"
@@ -11,6 +12,15 @@ import (
 	"testing"
 	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/eclipse/paho.golang/paho"
+	mochimqtt "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
@@ -27,6 +37,19 @@ keepAlive: 30
 dialTimeout: 10m
 pubQoS: 0
 subQoS: 2
+topicAliasMaximum: 10
+messageExpiry: 1h
+retainSpecs: true
+retainedDeleteExpiry: 5m
+sessionExpiry: 30m
+publishAckTimeout: 30s
+topics:
+  sourceEvents: sources/hub1/clusters/+/sourceevents
+  agentEvents: sources/hub1/clusters/+/agentevents
+`
+	testTenantConfig = `
+brokerHost: test
+tenantID: tenant1
 topics:
   sourceEvents: sources/hub1/clusters/+/sourceevents
   agentEvents: sources/hub1/clusters/+/agentevents
@@ -70,11 +93,31 @@ func TestBuildMQTTOptionsFromFlags(t *testing.T) {
 			config:           "{\"brokerHost\":\"test\",\"clientCertFile\":\"test\",\"clientKeyFile\":\"test\"}",
 			expectedErrorMsg: "setting clientCertFile and clientKeyFile requires caFile",
 		},
+		{
+			name:             "invalid broker host scheme",
+			config:           "{\"brokerHost\":\"tcp://test:1883\"}",
+			expectedErrorMsg: "invalid broker host \"tcp://test:1883\", the scheme must be ws or wss",
+		},
 		{
 			name:             "without topics",
 			config:           "{\"brokerHost\":\"test\"}",
 			expectedErrorMsg: "the topics must be set",
 		},
+		{
+			name:             "invalid tenant",
+			config:           strings.Replace(testTenantConfig, "tenant1", "tenant/1", 1),
+			expectedErrorMsg: "invalid tenant ID \"tenant/1\", it must consist of lower case alphanumeric characters or '-'",
+		},
+		{
+			name:             "session expiry with unique client ID",
+			config:           testYamlConfig + "sessionExpiry: 1h\nuniqueClientID: true\n",
+			expectedErrorMsg: "sessionExpiry can not be used with uniqueClientID",
+		},
+		{
+			name:             "invalid publish ack timeout",
+			config:           testYamlConfig + "publishAckTimeout: -1s\n",
+			expectedErrorMsg: "invalid publishAckTimeout -1s",
+		},
 		{
 			name:   "default options",
 			config: testConfig,
@@ -105,15 +148,37 @@ func TestBuildMQTTOptionsFromFlags(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "tenant options",
+			config: testTenantConfig,
+			expectedOptions: &MQTTOptions{
+				BrokerHost:  "test",
+				KeepAlive:   60,
+				PubQoS:      1,
+				SubQoS:      1,
+				DialTimeout: 60 * time.Second,
+				Topics: types.Topics{
+					SourceEvents: "sources/hub1/clusters/+/sourceevents",
+					AgentEvents:  "sources/hub1/clusters/+/agentevents",
+				},
+				TenantID: "tenant1",
+			},
+		},
 		{
 			name:   "customized options",
 			config: testCustomizedConfig,
 			expectedOptions: &MQTTOptions{
-				BrokerHost:  "test",
-				KeepAlive:   30,
-				PubQoS:      0,
-				SubQoS:      2,
-				DialTimeout: 10 * time.Minute,
+				BrokerHost:           "test",
+				KeepAlive:            30,
+				PubQoS:               0,
+				SubQoS:               2,
+				DialTimeout:          10 * time.Minute,
+				TopicAliasMaximum:    10,
+				MessageExpiry:        time.Hour,
+				RetainSpecs:          true,
+				RetainedDeleteExpiry: 5 * time.Minute,
+				SessionExpiry:        30 * time.Minute,
+				PublishAckTimeout:    30 * time.Second,
 				Topics: types.Topics{
 					SourceEvents: "sources/hub1/clusters/+/sourceevents",
 					AgentEvents:  "sources/hub1/clusters/+/agentevents",
@@ -347,6 +412,7 @@ func TestConnectionTimeout(t *testing.T) {
 	agentOptions := &mqttAgentOptions{
 		MQTTOptions: *options,
 		clusterName: "cluster1",
+		clientIDs:   newClientIDs("cluster1-client"),
 	}
 	_, err = agentOptions.Client(context.TODO())
 	if !errors.Is(err, context.DeadlineExceeded) {
@@ -354,6 +420,304 @@ func TestConnectionTimeout(t *testing.T) {
 	}
 }
 
+func TestWebSocketConnection(t *testing.T) {
+	ln := newLocalListener(t)
+	address := ln.Addr().String()
+	ln.Close()
+
+	broker := mochimqtt.New(&mochimqtt.Options{})
+	if err := broker.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.AddListener(listeners.NewWebsocket("mqtt-test-ws", address, nil)); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = broker.Serve()
+	}()
+	defer broker.Close()
+
+	options, err := BuildMQTTOptionsFromConfig(&MQTTConfig{
+		BrokerHost: fmt.Sprintf("ws://%s/mqtt", address),
+		Topics: &types.Topics{
+			SourceEvents: "sources/hub1/clusters/+/sourceevents",
+			AgentEvents:  "sources/hub1/clusters/+/agentevents",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.DialTimeout = 5 * time.Second
+
+	// wait for the broker to start
+	var conn net.Conn
+	if err := wait.PollUntilContextTimeout(context.Background(), 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			conn, err = options.GetNetConn()
+			return err == nil, nil
+		}); err != nil {
+		t.Fatalf("failed to connect to the broker, %v", err)
+	}
+
+	client := paho.NewClient(paho.ClientConfig{Conn: conn})
+	connAck, err := client.Connect(context.Background(), options.GetMQTTConnectOption("ws-client"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+
+	if connAck.ReasonCode != 0 {
+		t.Errorf("expected success, but got %d", connAck.ReasonCode)
+	}
+
+	if _, err := client.Subscribe(context.Background(), &paho.Subscribe{
+		Subscriptions: map[string]paho.SubscribeOptions{"sources/hub1/clusters/cluster1/sourceevents": {QoS: 1}},
+	}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestTopicAliases(t *testing.T) {
+	ln := newLocalListener(t)
+	address := ln.Addr().String()
+	ln.Close()
+
+	broker := mochimqtt.New(&mochimqtt.Options{})
+	if err := broker.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.AddListener(listeners.NewTCP("mqtt-test-tcp", address, nil)); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = broker.Serve()
+	}()
+	defer broker.Close()
+
+	options, err := BuildMQTTOptionsFromConfig(&MQTTConfig{
+		BrokerHost: address,
+		Topics: &types.Topics{
+			SourceEvents: "sources/hub1/clusters/+/sourceevents",
+			AgentEvents:  "sources/hub1/clusters/+/agentevents",
+		},
+		TopicAliasMaximum: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.DialTimeout = 5 * time.Second
+	options.MessageExpiry = time.Minute
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// wait for the broker to start
+	var receiver cloudevents.Client
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			receiver, err = options.GetCloudEventsClient(ctx, "receiver", func(error) {},
+				WithSubscribe(&paho.Subscribe{
+					Subscriptions: map[string]paho.SubscribeOptions{"sources/hub1/clusters/+/sourceevents": {QoS: 1}},
+				}))
+			return err == nil, nil
+		}); err != nil {
+		t.Fatalf("failed to connect to the broker, %v", err)
+	}
+
+	received := make(chan cloudevents.Event, 4)
+	go func() {
+		_ = receiver.StartReceiver(ctx, func(evt cloudevents.Event) {
+			received <- evt
+		})
+	}()
+
+	sender, err := options.GetCloudEventsClient(ctx, "sender", func(error) {},
+		WithPublish(options.GetMQTTPublishOption()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first topic is aliased after its first publish, the second topic is published with its name since the
+	// aliases are used up
+	topics := []string{
+		"sources/hub1/clusters/cluster1/sourceevents",
+		"sources/hub1/clusters/cluster1/sourceevents",
+		"sources/hub1/clusters/cluster2/sourceevents",
+		"sources/hub1/clusters/cluster1/sourceevents",
+	}
+	var sent []string
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			// wait for the subscription of the receiver
+			evt := cloudevents.NewEvent()
+			evt.SetID("probe")
+			evt.SetSource("test")
+			evt.SetType("test")
+			if result := sender.Send(cecontext.WithTopic(ctx, "sources/hub1/clusters/probe/sourceevents"), evt); cloudevents.IsUndelivered(result) {
+				return false, result
+			}
+			select {
+			case <-received:
+				return true, nil
+			case <-time.After(100 * time.Millisecond):
+				return false, nil
+			}
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, topic := range topics {
+		evt := cloudevents.NewEvent()
+		evt.SetID(fmt.Sprintf("event%d", i))
+		evt.SetSource("test")
+		evt.SetType("test")
+		if result := sender.Send(cecontext.WithTopic(ctx, topic), evt); cloudevents.IsUndelivered(result) {
+			t.Fatal(result)
+		}
+		sent = append(sent, evt.ID())
+	}
+
+	var got []string
+	for len(got) < len(sent) {
+		select {
+		case evt := <-received:
+			if evt.ID() != "probe" {
+				got = append(got, evt.ID())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected events %v, but got %v", sent, got)
+		}
+	}
+	if !reflect.DeepEqual(sent, got) {
+		t.Errorf("expected %v, but got %v", sent, got)
+	}
+}
+
+func TestTopicAliasAssignment(t *testing.T) {
+	aliases := newTopicAliases(1)
+
+	publish := &paho.Publish{Topic: "topic1"}
+	aliases.assign(publish)
+	if publish.Topic != "topic1" || publish.Properties.TopicAlias == nil || *publish.Properties.TopicAlias != 1 {
+		t.Errorf("expected the topic with the alias 1, but got %v", publish)
+	}
+
+	publish.Topic = "topic1"
+	aliases.assign(publish)
+	if publish.Topic != "" || *publish.Properties.TopicAlias != 1 {
+		t.Errorf("expected the alias only, but got %v", publish)
+	}
+
+	publish.Topic = "topic2"
+	aliases.assign(publish)
+	if publish.Topic != "topic2" || publish.Properties.TopicAlias != nil {
+		t.Errorf("expected the topic without alias, but got %v", publish)
+	}
+}
+
+func TestBuildMQTTOptionsWithEnvOverrides(t *testing.T) {
+	file, err := os.CreateTemp("", "mqtt-config-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := os.WriteFile(file.Name(), []byte(testYamlConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MQTT_BROKER_HOST", "test-env")
+	t.Setenv("MQTT_PASSWORD", "password-env")
+
+	options, err := BuildMQTTOptionsFromFlags(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if options.BrokerHost != "test-env" {
+		t.Errorf("expected test-env, but got %s", options.BrokerHost)
+	}
+
+	if options.Password != "password-env" {
+		t.Errorf("expected password-env, but got %s", options.Password)
+	}
+}
+
+func TestWatchConfigFile(t *testing.T) {
+	file, err := os.CreateTemp("", "mqtt-config-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := os.WriteFile(file.Name(), []byte(testYamlConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mqttOptions, err := BuildMQTTOptionsFromFlags(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agentOptions := NewAgentOptions(mqttOptions, "cluster1", "agent1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloaded := make(chan error, 2)
+	if err := options.WatchConfigFile(ctx, file.Name(), 10*time.Millisecond, agentOptions.CloudEventsOptions,
+		func(configPath string, err error) {
+			reloaded <- err
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	replaceFile(t, file.Name(), strings.Replace(testYamlConfig, "test", "test-new", 1))
+
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the options are not reloaded")
+	}
+
+	// the client is notified to reconnect
+	select {
+	case <-agentOptions.CloudEventsOptions.ErrorChan():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the reconnection is not notified")
+	}
+
+	if brokerHost := agentOptions.CloudEventsOptions.(*mqttAgentOptions).mqttOptions().BrokerHost; brokerHost != "test-new" {
+		t.Errorf("expected test-new, but got %s", brokerHost)
+	}
+
+	replaceFile(t, file.Name(), "{}")
+
+	select {
+	case err := <-reloaded:
+		if err == nil {
+			t.Errorf("expected error for the invalid config, but got nil")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the options are not reloaded")
+	}
+}
+
+// replaceFile replaces the file content atomically to avoid reading a partially written file.
+func replaceFile(t *testing.T, name, content string) {
+	tmpFile := name + ".tmp"
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Rename(tmpFile, name); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func newLocalListener(t *testing.T) net.Listener {
 	ln, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
@@ -0,0 +1,121 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/eclipse/paho.golang/paho"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// DefaultRetainedDeleteExpiry is the default expiry interval of the retained spec events that delete the resources.
+const DefaultRetainedDeleteExpiry = 10 * time.Minute
+
+type retainKey struct{}
+
+// withRetain returns a context that publishes the event as a retained message with the given expiry interval, the
+// message expiry interval of the options is used if the expiry is zero.
+func withRetain(ctx context.Context, expiry time.Duration) context.Context {
+	return context.WithValue(ctx, retainKey{}, expiry)
+}
+
+// resourceSpecTopic returns the topic of the latest spec of a resource, it is the subtopic of the source events topic
+// named with the resource ID. The spec events of the resource are retained on the topic, so an agent receives the
+// latest spec of each resource once it subscribes to the topics. False is returned if the event is not a resource
+// spec event or the resource ID can not be a topic level.
+func resourceSpecTopic(eventsTopic string, eventType types.CloudEventsType, evtCtx cloudevents.EventContext) (string, bool) {
+	if eventType.SubResource != types.SubResourceSpec {
+		return "", false
+	}
+
+	switch eventType.Action {
+	case types.ResyncRequestAction, types.ResyncResponseAction, types.ResyncCompleteAction,
+		types.RegisterRequestAction, types.RegisterResponseAction, types.HandoverRequestAction,
+		types.ClaimRequestAction, types.CallRequestAction, types.CallResponseAction:
+		// the protocol events are delivered to the connected agents only
+		return "", false
+	}
+
+	resourceID, err := evtCtx.GetExtension(types.ExtensionResourceID)
+	if err != nil {
+		return "", false
+	}
+
+	id := fmt.Sprintf("%s", resourceID)
+	if len(id) == 0 || strings.ContainsAny(id, "/+#") {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s/%s", eventsTopic, id), true
+}
+
+// retainedExpiry returns the expiry interval of a retained spec event. The retained spec event that deletes a resource
+// always expires, so a stale delete is not delivered to the agents that subscribe after the resource is gone.
+func (o *MQTTOptions) retainedExpiry(evtCtx cloudevents.EventContext) time.Duration {
+	if _, err := evtCtx.GetExtension(types.ExtensionDeletionTimestamp); err != nil {
+		return 0
+	}
+
+	expiry := o.RetainedDeleteExpiry
+	if expiry <= 0 {
+		expiry = DefaultRetainedDeleteExpiry
+	}
+	if o.MessageExpiry > 0 && o.MessageExpiry < expiry {
+		expiry = o.MessageExpiry
+	}
+	return expiry
+}
+
+// retainer publishes the events with the retain flag and the expiry interval of their contexts. The publish option of
+// the protocol is shared by the publishes, so the events are published one by one, and the publish hook sets the flag
+// and the expiry interval of the event that is being published.
+type retainer struct {
+	sync.Mutex
+	messageExpiry time.Duration
+
+	// retain and expiry are the retain flag and the expiry interval of the event that is being published
+	retain bool
+	expiry time.Duration
+}
+
+func newRetainer(messageExpiry time.Duration) *retainer {
+	return &retainer{messageExpiry: messageExpiry}
+}
+
+// send publishes an event with the given send func and the retain flag and the expiry interval of the context.
+func (r *retainer) send(ctx context.Context, send func() error) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.expiry, r.retain = ctx.Value(retainKey{}).(time.Duration)
+	return send()
+}
+
+func (r *retainer) mark(publish *paho.Publish) {
+	// the publish option is reused by the publishes, so the flags of the previous publish are reset
+	publish.Retain = r.retain
+
+	expiry := r.messageExpiry
+	if r.retain && r.expiry > 0 {
+		expiry = r.expiry
+	}
+
+	if expiry <= 0 {
+		if publish.Properties != nil {
+			publish.Properties.MessageExpiry = nil
+		}
+		return
+	}
+
+	if publish.Properties == nil {
+		publish.Properties = &paho.PublishProperties{}
+	}
+	messageExpiry := uint32(math.Ceil(expiry.Seconds()))
+	publish.Properties.MessageExpiry = &messageExpiry
+}
@@ -0,0 +1,228 @@
+package mqtt
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventscontext "github.com/cloudevents/sdk-go/v2/context"
+	mochimqtt "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func newSpecEvent(id string, action types.EventAction, resourceID string, deleting bool) cloudevents.Event {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              action,
+	}
+
+	evt := cloudevents.NewEvent()
+	evt.SetID(id)
+	evt.SetSource("hub1")
+	evt.SetType(eventType.String())
+	evt.SetExtension(types.ExtensionClusterName, "cluster1")
+	evt.SetExtension(types.ExtensionResourceID, resourceID)
+	if deleting {
+		evt.SetExtension(types.ExtensionDeletionTimestamp, time.Now())
+	}
+	if err := evt.SetData(cloudevents.ApplicationJSON, map[string]string{"id": resourceID}); err != nil {
+		panic(err)
+	}
+	return evt
+}
+
+func TestRetainedSpecContext(t *testing.T) {
+	cases := []struct {
+		name           string
+		messageExpiry  time.Duration
+		event          cloudevents.Event
+		expectedTopic  string
+		expectedRetain bool
+		expectedExpiry time.Duration
+	}{
+		{
+			name:           "resource spec",
+			event:          newSpecEvent("event1", "update_request", "test1", false),
+			expectedTopic:  "sources/hub1/clusters/cluster1/sourceevents/test1",
+			expectedRetain: true,
+		},
+		{
+			name:           "resource deletion",
+			event:          newSpecEvent("event1", "delete_request", "test1", true),
+			expectedTopic:  "sources/hub1/clusters/cluster1/sourceevents/test1",
+			expectedRetain: true,
+			expectedExpiry: DefaultRetainedDeleteExpiry,
+		},
+		{
+			name:           "resource deletion with message expiry",
+			messageExpiry:  time.Minute,
+			event:          newSpecEvent("event1", "delete_request", "test1", true),
+			expectedTopic:  "sources/hub1/clusters/cluster1/sourceevents/test1",
+			expectedRetain: true,
+			expectedExpiry: time.Minute,
+		},
+		{
+			name:          "handover request",
+			event:         newSpecEvent("event1", types.HandoverRequestAction, "test1", false),
+			expectedTopic: "sources/hub1/clusters/cluster1/sourceevents",
+		},
+		{
+			name:          "invalid resource topic level",
+			event:         newSpecEvent("event1", "update_request", "ns/test1", false),
+			expectedTopic: "sources/hub1/clusters/cluster1/sourceevents",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sourceOptions := &mqttSourceOptions{
+				MQTTOptions: MQTTOptions{
+					Topics: types.Topics{
+						SourceEvents: "sources/hub1/clusters/+/sourceevents",
+						AgentEvents:  "sources/hub1/clusters/+/agentevents",
+					},
+					MessageExpiry: c.messageExpiry,
+					RetainSpecs:   true,
+				},
+				sourceID: "hub1",
+			}
+
+			ctx, err := sourceOptions.WithContext(context.TODO(), c.event.Context)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if topic := cloudeventscontext.TopicFrom(ctx); topic != c.expectedTopic {
+				t.Errorf("expected %s, but got %s", c.expectedTopic, topic)
+			}
+
+			expiry, retain := ctx.Value(retainKey{}).(time.Duration)
+			if retain != c.expectedRetain {
+				t.Errorf("expected %v, but got %v", c.expectedRetain, retain)
+			}
+			if expiry != c.expectedExpiry {
+				t.Errorf("expected %v, but got %v", c.expectedExpiry, expiry)
+			}
+		})
+	}
+}
+
+func TestRetainedSpecs(t *testing.T) {
+	ln := newLocalListener(t)
+	address := ln.Addr().String()
+	ln.Close()
+
+	broker := mochimqtt.New(&mochimqtt.Options{})
+	if err := broker.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.AddListener(listeners.NewTCP("mqtt-test-tcp", address, nil)); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = broker.Serve()
+	}()
+	defer broker.Close()
+
+	options, err := BuildMQTTOptionsFromConfig(&MQTTConfig{
+		BrokerHost: address,
+		Topics: &types.Topics{
+			SourceEvents: "sources/hub1/clusters/+/sourceevents",
+			AgentEvents:  "sources/hub1/clusters/+/agentevents",
+		},
+		RetainSpecs: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.DialTimeout = 5 * time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceOptions := NewSourceOptions(options, "hub1-client", "hub1")
+
+	// wait for the broker to start
+	var sender cloudevents.Client
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			sender, err = sourceOptions.CloudEventsOptions.Client(ctx)
+			return err == nil, nil
+		}); err != nil {
+		t.Fatalf("failed to connect to the broker, %v", err)
+	}
+
+	// the specs are published before the agent connects, the agent receives the latest spec of each resource
+	for _, evt := range []cloudevents.Event{
+		newSpecEvent("event1", "create_request", "test1", false),
+		newSpecEvent("event2", "update_request", "test1", false),
+		newSpecEvent("event3", "create_request", "test2", false),
+		newSpecEvent("event4", "delete_request", "test2", true),
+		newSpecEvent("event5", types.HandoverRequestAction, "test3", false),
+	} {
+		sendCtx, err := sourceOptions.CloudEventsOptions.WithContext(ctx, evt.Context)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := sender.Send(sendCtx, evt); cloudevents.IsUndelivered(result) {
+			t.Fatal(result)
+		}
+	}
+
+	agentOptions := NewAgentOptions(options, "cluster1", "agent1")
+	receiver, err := agentOptions.CloudEventsOptions.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan cloudevents.Event, 5)
+	go func() {
+		_ = receiver.StartReceiver(ctx, func(evt cloudevents.Event) {
+			received <- evt
+		})
+	}()
+
+	expected := []string{"event2", "event4"}
+	var got []string
+	for len(got) < len(expected) {
+		select {
+		case evt := <-received:
+			got = append(got, evt.ID())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected events %v, but got %v", expected, got)
+		}
+	}
+
+	select {
+	case evt := <-received:
+		got = append(got, evt.ID())
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	sort.Strings(got)
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected %v, but got %v", expected, got)
+	}
+
+	// the retained delete expires, and the retained spec does not expire
+	for topic, expectedExpiry := range map[string]uint32{
+		"sources/hub1/clusters/cluster1/sourceevents/test1": 0,
+		"sources/hub1/clusters/cluster1/sourceevents/test2": uint32(DefaultRetainedDeleteExpiry.Seconds()),
+	} {
+		retained := broker.Topics.Messages(topic)
+		if len(retained) != 1 {
+			t.Fatalf("expected a retained message of %s, but got %v", topic, retained)
+		}
+		if expiry := retained[0].Properties.MessageExpiryInterval; expiry != expectedExpiry {
+			t.Errorf("expected %d, but got %d", expectedExpiry, expiry)
+		}
+	}
+}
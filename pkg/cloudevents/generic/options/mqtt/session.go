@@ -0,0 +1,339 @@
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cloudeventsmqtt "github.com/cloudevents/sdk-go/protocol/mqtt_paho/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	cloudeventsprotocol "github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// sendAcksInterval is the interval that the acknowledgments of the handled messages are sent to the broker.
+const sendAcksInterval = 10 * time.Millisecond
+
+const (
+	connackType = iota
+	connackRemainingLength
+	connackVariableHeader
+	connackParsed
+)
+
+// sessionConn records the CONNACK of a connection, the CONNACK is the first packet that is read from the connection,
+// it tells whether the broker resumed the session of the client and the features that the broker supports.
+type sessionConn struct {
+	net.Conn
+	state     int
+	packet    []byte
+	remaining int
+	shift     uint
+	connack   atomic.Pointer[packets.Connack]
+}
+
+func newSessionConn(conn net.Conn) *sessionConn {
+	return &sessionConn{Conn: conn}
+}
+
+func (c *sessionConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	for i := 0; i < n && c.state != connackParsed; i++ {
+		c.parse(b[i])
+	}
+	return n, err
+}
+
+func (c *sessionConn) parse(b byte) {
+	c.packet = append(c.packet, b)
+
+	switch c.state {
+	case connackType:
+		c.state = connackRemainingLength
+		if b>>4 != packets.CONNACK {
+			c.state = connackParsed
+			c.packet = nil
+		}
+	case connackRemainingLength:
+		// the remaining length is a variable byte integer, its last byte has no continuation bit
+		c.remaining |= int(b&0x7f) << c.shift
+		c.shift += 7
+		if b&0x80 == 0 {
+			c.state = connackVariableHeader
+			if c.remaining == 0 {
+				c.unpack()
+			}
+		}
+	case connackVariableHeader:
+		c.remaining--
+		if c.remaining == 0 {
+			c.unpack()
+		}
+	}
+}
+
+func (c *sessionConn) unpack() {
+	packet := c.packet
+	c.state = connackParsed
+	c.packet = nil
+
+	cp, err := packets.ReadPacket(bytes.NewReader(packet))
+	if err != nil {
+		klog.Warningf("failed to read the CONNACK, %v", err)
+		return
+	}
+
+	if connack, ok := cp.Content.(*packets.Connack); ok {
+		c.connack.Store(connack)
+	}
+}
+
+// Connack returns the CONNACK of the connection, it is nil if the CONNACK has not been read.
+func (c *sessionConn) Connack() *packets.Connack {
+	return c.connack.Load()
+}
+
+// sessionRouter is the only router of the MQTT client, it is installed before the client is connected and it is never
+// replaced, so the paho client always routes the messages to it. The broker delivers the queued messages of a resumed
+// session right after the client is connected, the router holds them until the inbound of the protocol is opened, then
+// it hands the messages to the receiver one by one, so the receiving is throttled when the messages are not handled
+// in time.
+type sessionRouter struct {
+	sync.Mutex
+	opened   bool
+	messages []*paho.Publish
+
+	incoming  chan *paho.Publish
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newSessionRouter() *sessionRouter {
+	return &sessionRouter{incoming: make(chan *paho.Publish), closed: make(chan struct{})}
+}
+
+func (r *sessionRouter) RegisterHandler(string, paho.MessageHandler) {}
+
+func (r *sessionRouter) UnregisterHandler(string) {}
+
+func (r *sessionRouter) Route(pb *packets.Publish) {
+	m := paho.PublishFromPacketPublish(pb)
+
+	r.Lock()
+	if !r.opened {
+		r.messages = append(r.messages, m)
+		r.Unlock()
+		return
+	}
+	r.Unlock()
+
+	select {
+	case r.incoming <- m:
+	case <-r.closed:
+	}
+}
+
+func (r *sessionRouter) SetDebugLogger(paho.Logger) {}
+
+// open hands the messages that are routed from now on to the receiver, the held messages are received before them.
+func (r *sessionRouter) open() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.opened = true
+}
+
+// receive returns the held messages first, then the messages that are routed after the inbound is opened.
+func (r *sessionRouter) receive(ctx context.Context) (*paho.Publish, error) {
+	if m := r.pop(); m != nil {
+		return m, nil
+	}
+
+	select {
+	case m := <-r.incoming:
+		return m, nil
+	case <-ctx.Done():
+		return nil, io.EOF
+	case <-r.closed:
+		return nil, io.EOF
+	}
+}
+
+func (r *sessionRouter) pop() *paho.Publish {
+	r.Lock()
+	defer r.Unlock()
+
+	if len(r.messages) == 0 {
+		return nil
+	}
+
+	m := r.messages[0]
+	r.messages = r.messages[1:]
+	return m
+}
+
+// close stops routing the messages, the messages that are not received are not acknowledged, so the broker delivers
+// them again on the next connection of the session.
+func (r *sessionRouter) close() {
+	r.closeOnce.Do(func() {
+		close(r.closed)
+	})
+}
+
+// ProtocolOption is the option of the MQTT protocol of a cloudevents client.
+type ProtocolOption func(*protocol) error
+
+// WithPublish sets the publish option of the protocol. This option is required if the client sends events.
+func WithPublish(publish *paho.Publish) ProtocolOption {
+	return func(p *protocol) error {
+		if publish == nil {
+			return fmt.Errorf("the paho.Publish option must not be nil")
+		}
+		p.publish = publish
+		return nil
+	}
+}
+
+// WithSubscribe sets the subscribe option of the protocol. This option is required if the client receives events.
+func WithSubscribe(subscribe *paho.Subscribe) ProtocolOption {
+	return func(p *protocol) error {
+		if subscribe == nil {
+			return fmt.Errorf("the paho.Subscribe option must not be nil")
+		}
+		p.subscribe = subscribe
+		return nil
+	}
+}
+
+// protocol is the cloudevents MQTT protocol on a paho client. Unlike the protocol of the cloudevents MQTT binding, it
+// does not replace the router of the client when the inbound is opened, the messages are received from the
+// sessionRouter that is installed before the client is connected, and a message is acknowledged after it is handled
+// rather than when it is routed. The retained messages are published with the retainer.
+type protocol struct {
+	client            *paho.Client
+	router            *sessionRouter
+	publish           *paho.Publish
+	subscribe         *paho.Subscribe
+	retainer          *retainer
+	publishAckTimeout time.Duration
+
+	// openerMutex makes sure the inbound is opened once at a time
+	openerMutex sync.Mutex
+}
+
+var (
+	_ cloudeventsprotocol.Sender   = (*protocol)(nil)
+	_ cloudeventsprotocol.Opener   = (*protocol)(nil)
+	_ cloudeventsprotocol.Receiver = (*protocol)(nil)
+	_ cloudeventsprotocol.Closer   = (*protocol)(nil)
+)
+
+func (p *protocol) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	defer func() {
+		_ = m.Finish(err)
+	}()
+
+	if p.publish == nil {
+		return fmt.Errorf("the paho.Publish option must not be nil")
+	}
+
+	topic := cecontext.TopicFrom(ctx)
+	return options.SendWithAckTimeout(ctx, p.publishAckTimeout, topic, func(ctx context.Context) error {
+		if p.retainer == nil {
+			return p.send(ctx, topic, m, transformers...)
+		}
+
+		return p.retainer.send(ctx, func() error {
+			return p.send(ctx, topic, m, transformers...)
+		})
+	})
+}
+
+// send publishes the message with a copy of the publish option, so the concurrent sends do not share the message.
+func (p *protocol) send(ctx context.Context, topic string, m binding.Message, transformers ...binding.Transformer) error {
+	msg := &paho.Publish{QoS: p.publish.QoS, Retain: p.publish.Retain, Topic: p.publish.Topic}
+	if p.publish.Properties != nil {
+		properties := *p.publish.Properties
+		msg.Properties = &properties
+	}
+	if len(topic) != 0 {
+		msg.Topic = topic
+	}
+
+	if err := cloudeventsmqtt.WritePubMessage(ctx, m, msg, transformers...); err != nil {
+		return err
+	}
+
+	_, err := p.client.Publish(ctx, msg)
+	return err
+}
+
+// OpenInbound subscribes to the topics, and it blocks until the context is done or the protocol is closed, then the
+// client is disconnected.
+func (p *protocol) OpenInbound(ctx context.Context) error {
+	if p.subscribe == nil {
+		return fmt.Errorf("the paho.Subscribe option must not be nil")
+	}
+
+	p.openerMutex.Lock()
+	defer p.openerMutex.Unlock()
+
+	p.router.open()
+
+	klog.V(4).Infof("subscribing to topics: %v", p.subscribe.Subscriptions)
+	if _, err := p.client.Subscribe(ctx, p.subscribe); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-p.router.closed:
+	}
+
+	p.router.close()
+
+	// the acknowledgments are sent in batches, wait for the batch of the handled messages before disconnecting, so the
+	// broker does not deliver them again on the next connection of the session
+	time.Sleep(2 * sendAcksInterval)
+	return p.client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+}
+
+func (p *protocol) Receive(ctx context.Context) (binding.Message, error) {
+	m, err := p.router.receive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &message{Message: cloudeventsmqtt.NewMessage(m), client: p.client, publish: m}, nil
+}
+
+func (p *protocol) Close(ctx context.Context) error {
+	p.router.close()
+	return nil
+}
+
+// message acknowledges the received message once it is finished, the cloudevents client finishes a message after
+// the receiver handles its event.
+type message struct {
+	*cloudeventsmqtt.Message
+	client  *paho.Client
+	publish *paho.Publish
+}
+
+func (m *message) Finish(err error) error {
+	if ackErr := m.client.Ack(m.publish); ackErr != nil {
+		// the message is delivered again if the client is disconnected before it is acknowledged
+		klog.V(4).Infof("failed to acknowledge the message %d on the topic %s, %v", m.publish.PacketID, m.publish.Topic, ackErr)
+	}
+	return m.Message.Finish(err)
+}
@@ -0,0 +1,442 @@
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	mochimqtt "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type bufferConn struct {
+	net.Conn
+	data *bytes.Buffer
+}
+
+func (c *bufferConn) Read(b []byte) (int, error) {
+	// the bytes are read one by one to verify the CONNACK is parsed across the reads
+	return c.data.Read(b[:1])
+}
+
+func TestSessionConn(t *testing.T) {
+	defaultFeatures := func(overrides map[string]string) map[string]string {
+		features := map[string]string{
+			"sessionPresent":                "false",
+			"receiveMaximum":                "65535",
+			"topicAliasMaximum":             "0",
+			"maximumQoS":                    "2",
+			"retainAvailable":               "true",
+			"wildcardSubscriptionAvailable": "true",
+			"sharedSubscriptionAvailable":   "true",
+		}
+		for k, v := range overrides {
+			features[k] = v
+		}
+		return features
+	}
+
+	// the CONNACK with an assigned client ID property whose remaining length is encoded with two bytes
+	assignedClientID := strings.Repeat("a", 123)
+	longConnack := append([]byte{0x20, 0x81, 0x01, 0x01, 0x00, 0x7e, 0x12, 0x00, 0x7b}, []byte(assignedClientID)...)
+
+	cases := []struct {
+		name             string
+		data             []byte
+		expectedFeatures map[string]string
+	}{
+		{
+			name:             "session present",
+			data:             []byte{0x20, 0x03, 0x01, 0x00, 0x00},
+			expectedFeatures: defaultFeatures(map[string]string{"sessionPresent": "true"}),
+		},
+		{
+			name:             "session not present",
+			data:             []byte{0x20, 0x03, 0x00, 0x00, 0x00},
+			expectedFeatures: defaultFeatures(nil),
+		},
+		{
+			name: "broker properties",
+			data: []byte{0x20, 0x0b, 0x00, 0x00, 0x08, 0x21, 0x00, 0x0a, 0x22, 0x00, 0x05, 0x25, 0x00},
+			expectedFeatures: defaultFeatures(map[string]string{
+				"receiveMaximum":    "10",
+				"topicAliasMaximum": "5",
+				"retainAvailable":   "false",
+			}),
+		},
+		{
+			name: "multiple bytes remaining length",
+			data: longConnack,
+			expectedFeatures: defaultFeatures(map[string]string{
+				"sessionPresent":   "true",
+				"assignedClientID": assignedClientID,
+			}),
+		},
+		{
+			name:             "following packets are not parsed",
+			data:             []byte{0x20, 0x03, 0x00, 0x00, 0x00, 0x20, 0x03, 0x01, 0x00, 0x00},
+			expectedFeatures: defaultFeatures(nil),
+		},
+		{
+			name: "not a CONNACK",
+			data: []byte{0xd0, 0x00, 0x20, 0x03, 0x01, 0x00, 0x00},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			conn := newSessionConn(&bufferConn{data: bytes.NewBuffer(c.data)})
+
+			data := make([]byte, len(c.data))
+			for i := range data {
+				if _, err := conn.Read(data[i:]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if !bytes.Equal(data, c.data) {
+				t.Errorf("expected %v, but got %v", c.data, data)
+			}
+
+			connection := &connection{}
+			connection.connected(conn.Connack(), nil)
+			features := connection.diagnostics("127.0.0.1:1883").Features
+			if !reflect.DeepEqual(c.expectedFeatures, features) {
+				t.Errorf("expected %v, but got %v", c.expectedFeatures, features)
+			}
+		})
+	}
+}
+
+func TestSessionPresent(t *testing.T) {
+	cases := []struct {
+		name                   string
+		data                   []byte
+		expectedSessionPresent bool
+	}{
+		{
+			name:                   "session present",
+			data:                   []byte{0x20, 0x03, 0x01, 0x00, 0x00},
+			expectedSessionPresent: true,
+		},
+		{
+			name: "session not present",
+			data: []byte{0x20, 0x03, 0x00, 0x00, 0x00},
+		},
+		{
+			name:                   "multiple bytes remaining length",
+			data:                   append([]byte{0x20, 0x80, 0x01, 0x01, 0x00}, make([]byte, 126)...),
+			expectedSessionPresent: true,
+		},
+		{
+			name: "following packets are not parsed",
+			data: []byte{0x20, 0x03, 0x00, 0x00, 0x00, 0x20, 0x03, 0x01, 0x00, 0x00},
+		},
+		{
+			name: "not a CONNACK",
+			data: []byte{0xd0, 0x00, 0x20, 0x03, 0x01, 0x00, 0x00},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			conn := newSessionConn(&bufferConn{data: bytes.NewBuffer(c.data)})
+
+			data := make([]byte, len(c.data))
+			for i := range data {
+				if _, err := conn.Read(data[i:]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			connection := &connection{}
+			connection.connected(conn.Connack(), nil)
+			if connection.sessionPresent() != c.expectedSessionPresent {
+				t.Errorf("expected %v, but got %v", c.expectedSessionPresent, connection.sessionPresent())
+			}
+		})
+	}
+}
+
+func TestPersistentSession(t *testing.T) {
+	ln := newLocalListener(t)
+	address := ln.Addr().String()
+	ln.Close()
+
+	broker := mochimqtt.New(&mochimqtt.Options{})
+	if err := broker.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.AddListener(listeners.NewTCP("mqtt-test-tcp", address, nil)); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = broker.Serve()
+	}()
+	defer broker.Close()
+
+	sessionExpiry := time.Hour
+	options, err := BuildMQTTOptionsFromConfig(&MQTTConfig{
+		BrokerHost: address,
+		Topics: &types.Topics{
+			SourceEvents: "sources/hub1/clusters/+/sourceevents",
+			AgentEvents:  "sources/hub1/clusters/+/agentevents",
+		},
+		SessionExpiry: &sessionExpiry,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.DialTimeout = 5 * time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceOptions := NewSourceOptions(options, "hub1-client", "hub1")
+	agentOptions := NewAgentOptions(options, "cluster1", "agent1")
+	resumable := agentOptions.CloudEventsOptions.(interface{ Resumable() bool })
+
+	// wait for the broker to start
+	var sender cloudevents.Client
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			sender, err = sourceOptions.CloudEventsOptions.Client(ctx)
+			return err == nil, nil
+		}); err != nil {
+		t.Fatalf("failed to connect to the broker, %v", err)
+	}
+
+	send := func(id string) {
+		evt := newSpecEvent(id, "update_request", "test1", false)
+		sendCtx, err := sourceOptions.CloudEventsOptions.WithContext(ctx, evt.Context)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := sender.Send(sendCtx, evt); cloudevents.IsUndelivered(result) {
+			t.Fatal(result)
+		}
+	}
+
+	receive := func(receiverCtx context.Context, receiver cloudevents.Client) <-chan cloudevents.Event {
+		received := make(chan cloudevents.Event, 1)
+		go func() {
+			_ = receiver.StartReceiver(receiverCtx, func(evt cloudevents.Event) {
+				received <- evt
+			})
+		}()
+		return received
+	}
+
+	receiverCtx, stopReceiver := context.WithCancel(ctx)
+	receiver, err := agentOptions.CloudEventsOptions.Client(receiverCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumable.Resumable() {
+		t.Errorf("expected the session is not resumed on the first connection")
+	}
+
+	received := receive(receiverCtx, receiver)
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			// wait for the subscription of the agent
+			send("probe")
+			select {
+			case <-received:
+				return true, nil
+			case <-time.After(100 * time.Millisecond):
+				return false, nil
+			}
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the agent disconnects, the event is queued by the broker until the agent reconnects
+	stopReceiver()
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			cl, ok := broker.Clients.Get("agent1-client")
+			return ok && cl.Closed(), nil
+		}); err != nil {
+		t.Fatal(err)
+	}
+	send("event1")
+
+	receiver, err = agentOptions.CloudEventsOptions.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resumable.Resumable() {
+		t.Errorf("expected the session is resumed")
+	}
+
+	diagnostics := agentOptions.CloudEventsOptions.(*mqttAgentOptions).Diagnostics()
+	if diagnostics.BrokerAddress != address {
+		t.Errorf("expected %s, but got %s", address, diagnostics.BrokerAddress)
+	}
+	if diagnostics.Features["sessionPresent"] != "true" {
+		t.Errorf("expected the session present feature, but got %v", diagnostics.Features)
+	}
+	expectedSubscriptions := []string{"sources/hub1/clusters/cluster1/sourceevents"}
+	if !reflect.DeepEqual(expectedSubscriptions, diagnostics.Subscriptions) {
+		t.Errorf("expected %v, but got %v", expectedSubscriptions, diagnostics.Subscriptions)
+	}
+
+	// the queued event is delivered before the receiver is started
+	time.Sleep(500 * time.Millisecond)
+
+	select {
+	case evt := <-receive(ctx, receiver):
+		if evt.ID() != "event1" {
+			t.Errorf("expected event1, but got %s", evt.ID())
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected the queued event is received")
+	}
+}
+
+func TestSessionAckAfterHandled(t *testing.T) {
+	ln := newLocalListener(t)
+	address := ln.Addr().String()
+	ln.Close()
+
+	broker := mochimqtt.New(&mochimqtt.Options{})
+	if err := broker.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.AddListener(listeners.NewTCP("mqtt-test-tcp", address, nil)); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = broker.Serve()
+	}()
+	defer broker.Close()
+
+	sessionExpiry := time.Hour
+	options, err := BuildMQTTOptionsFromConfig(&MQTTConfig{
+		BrokerHost: address,
+		Topics: &types.Topics{
+			SourceEvents: "sources/hub1/clusters/+/sourceevents",
+			AgentEvents:  "sources/hub1/clusters/+/agentevents",
+		},
+		SessionExpiry: &sessionExpiry,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.DialTimeout = 5 * time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceOptions := NewSourceOptions(options, "hub1-client", "hub1")
+	agentOptions := NewAgentOptions(options, "cluster1", "agent1")
+
+	// wait for the broker to start
+	var sender cloudevents.Client
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			sender, err = sourceOptions.CloudEventsOptions.Client(ctx)
+			return err == nil, nil
+		}); err != nil {
+		t.Fatalf("failed to connect to the broker, %v", err)
+	}
+
+	send := func(id string) {
+		evt := newSpecEvent(id, "update_request", "test1", false)
+		sendCtx, err := sourceOptions.CloudEventsOptions.WithContext(ctx, evt.Context)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := sender.Send(sendCtx, evt); cloudevents.IsUndelivered(result) {
+			t.Fatal(result)
+		}
+	}
+
+	// the handling of the event1 is blocked until it is released
+	release := make(chan struct{})
+	received := make(chan string, 10)
+	receive := func(receiverCtx context.Context, receiver cloudevents.Client) {
+		go func() {
+			_ = receiver.StartReceiver(receiverCtx, func(evt cloudevents.Event) {
+				received <- evt.ID()
+				if evt.ID() == "event1" {
+					<-release
+				}
+			})
+		}()
+	}
+
+	receiverCtx, stopReceiver := context.WithCancel(ctx)
+	receiver, err := agentOptions.CloudEventsOptions.Client(receiverCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receive(receiverCtx, receiver)
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			// wait for the subscription of the agent
+			send("probe")
+			select {
+			case <-received:
+				return true, nil
+			case <-time.After(100 * time.Millisecond):
+				return false, nil
+			}
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	send("event1")
+	select {
+	case id := <-received:
+		if id != "event1" {
+			t.Fatalf("expected event1, but got %s", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the event1 is received")
+	}
+
+	// the agent disconnects before the event1 is handled
+	stopReceiver()
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			cl, ok := broker.Clients.Get("agent1-client")
+			return ok && cl.Closed(), nil
+		}); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+
+	// the event1 is not acknowledged, so it is delivered again on the resumed session
+	receiver, err = agentOptions.CloudEventsOptions.Client(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive(ctx, receiver)
+
+	for {
+		select {
+		case id := <-received:
+			if id == "probe" {
+				continue
+			}
+			if id != "event1" {
+				t.Errorf("expected event1, but got %s", id)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("expected the unhandled event1 is delivered again")
+		}
+		return
+	}
+}
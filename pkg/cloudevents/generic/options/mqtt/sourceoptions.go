@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
-	cloudeventsmqtt "github.com/cloudevents/sdk-go/protocol/mqtt_paho/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cloudeventscontext "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/eclipse/paho.golang/paho"
@@ -15,10 +15,12 @@ import (
 )
 
 type mqttSourceOptions struct {
+	sync.RWMutex
 	MQTTOptions
-	errorChan chan error
-	sourceID  string
-	clientID  string
+	errorChan  chan error
+	sourceID   string
+	clientIDs  *clientIDs
+	connection connection
 }
 
 func NewSourceOptions(mqttOptions *MQTTOptions, clientID, sourceID string) *options.CloudEventsSourceOptions {
@@ -26,16 +28,20 @@ func NewSourceOptions(mqttOptions *MQTTOptions, clientID, sourceID string) *opti
 		MQTTOptions: *mqttOptions,
 		errorChan:   make(chan error),
 		sourceID:    sourceID,
-		clientID:    clientID,
+		clientIDs:   newClientIDs(clientID),
 	}
 
 	return &options.CloudEventsSourceOptions{
-		CloudEventsOptions: mqttSourceOptions,
-		SourceID:           mqttSourceOptions.sourceID,
+		CloudEventsOptions:       mqttSourceOptions,
+		SourceID:                 mqttSourceOptions.sourceID,
+		CloudEventsClientOptions: options.CloudEventsClientOptions{TenantID: mqttOptions.TenantID},
 	}
 }
 
 func (o *mqttSourceOptions) WithContext(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+	mqttOptions := o.mqttOptions()
+	ctx = options.WithContentMode(ctx, mqttOptions.ContentMode)
+
 	eventType, err := types.ParseCloudEventsType(evtCtx.GetType())
 	if err != nil {
 		return nil, fmt.Errorf("unsupported event type %s, %v", eventType, err)
@@ -46,59 +52,110 @@ func (o *mqttSourceOptions) WithContext(ctx context.Context, evtCtx cloudevents.
 		return nil, err
 	}
 
-	if eventType.Action == types.ResyncRequestAction && clusterName == types.ClusterAll {
-		// source request to get resources status from all agents
-		if len(o.Topics.SourceBroadcast) == 0 {
+	if clusterName == types.ClusterAll {
+		// source request to get resources status from all agents or broadcasts the resource specs to all agents
+		if len(mqttOptions.Topics.SourceBroadcast) == 0 {
 			return nil, fmt.Errorf("the source broadcast topic not set")
 		}
 
-		resyncTopic := strings.Replace(o.Topics.SourceBroadcast, "+", o.sourceID, 1)
-		return cloudeventscontext.WithTopic(ctx, resyncTopic), nil
+		broadcastTopic := strings.Replace(mqttOptions.Topics.SourceBroadcast, "+", o.sourceID, 1)
+		return cloudeventscontext.WithTopic(ctx, mqttOptions.topic(broadcastTopic)), nil
 	}
 
 	// source publishes spec events or status resync events
-	eventsTopic := strings.Replace(o.Topics.SourceEvents, "+", fmt.Sprintf("%s", clusterName), 1)
-	return cloudeventscontext.WithTopic(ctx, eventsTopic), nil
+	eventsTopic := strings.Replace(mqttOptions.Topics.SourceEvents, "+", fmt.Sprintf("%s", clusterName), 1)
+	if mqttOptions.RetainSpecs {
+		// the resource spec events are retained on the topics of the resources
+		if resourceTopic, ok := resourceSpecTopic(eventsTopic, *eventType, evtCtx); ok {
+			ctx = withRetain(ctx, mqttOptions.retainedExpiry(evtCtx))
+			return cloudeventscontext.WithTopic(ctx, mqttOptions.topic(resourceTopic)), nil
+		}
+	}
+	return cloudeventscontext.WithTopic(ctx, mqttOptions.topic(eventsTopic)), nil
 }
 
 func (o *mqttSourceOptions) Client(ctx context.Context) (cloudevents.Client, error) {
-	topicSource, err := getSourceFromEventsTopic(o.Topics.AgentEvents)
+	mqttOptions := o.mqttOptions()
+
+	topicSource, err := getSourceFromEventsTopic(mqttOptions.Topics.AgentEvents)
 	if err != nil {
 		return nil, err
 	}
 
 	if topicSource != o.sourceID {
 		return nil, fmt.Errorf("the topic source %q does not match with the client sourceID %q",
-			o.Topics.AgentEvents, o.sourceID)
+			mqttOptions.Topics.AgentEvents, o.sourceID)
 	}
 
 	subscribe := &paho.Subscribe{
 		Subscriptions: map[string]paho.SubscribeOptions{
 			// receiving the agent events
-			o.Topics.AgentEvents: {QoS: byte(o.SubQoS)},
+			mqttOptions.topic(mqttOptions.Topics.AgentEvents): {QoS: byte(mqttOptions.SubQoS)},
 		},
 	}
 
-	if len(o.Topics.AgentBroadcast) != 0 {
+	if len(mqttOptions.Topics.AgentBroadcast) != 0 {
 		// receiving spec resync events from all agents
-		subscribe.Subscriptions[o.Topics.AgentBroadcast] = paho.SubscribeOptions{QoS: byte(o.SubQoS)}
+		subscribe.Subscriptions[mqttOptions.topic(mqttOptions.Topics.AgentBroadcast)] =
+			paho.SubscribeOptions{QoS: byte(mqttOptions.SubQoS)}
 	}
 
-	receiver, err := o.GetCloudEventsClient(
+	receiver, connack, err := mqttOptions.newCloudEventsClient(
 		ctx,
-		o.clientID,
-		func(err error) {
+		o.clientIDs.next(mqttOptions),
+		o.clientIDs.errorHandler(func(err error) {
 			o.errorChan <- err
-		},
-		cloudeventsmqtt.WithPublish(&paho.Publish{QoS: byte(o.PubQoS)}),
-		cloudeventsmqtt.WithSubscribe(subscribe),
+		}),
+		WithPublish(mqttOptions.GetMQTTPublishOption()),
+		WithSubscribe(subscribe),
 	)
 	if err != nil {
 		return nil, err
 	}
+	o.connection.connected(connack, subscribe)
 	return receiver, nil
 }
 
+// Resumable returns true if the broker resumed the session of the client on the last connection, the events to the
+// client are queued by the broker while it is offline, so the resync is not required after it is reconnected.
+func (o *mqttSourceOptions) Resumable() bool {
+	return o.connection.sessionPresent()
+}
+
+// Diagnostics returns the broker host, the subscribed topics and the features of the broker on the last connection.
+func (o *mqttSourceOptions) Diagnostics() options.TransportDiagnostics {
+	return o.connection.diagnostics(o.mqttOptions().BrokerHost)
+}
+
 func (o *mqttSourceOptions) ErrorChan() <-chan error {
 	return o.errorChan
 }
+
+// Reload rebuilds the MQTT options from the given config file, the cloudevents client is reconnected with the new
+// options.
+func (o *mqttSourceOptions) Reload(configPath string) error {
+	mqttOptions, err := BuildMQTTOptionsFromFlags(configPath)
+	if err != nil {
+		return err
+	}
+
+	o.Lock()
+	// the tenant and the client ID suffix of a client can not be changed by reloading
+	mqttOptions.TenantID = o.MQTTOptions.TenantID
+	mqttOptions.ClientIDSuffix = o.MQTTOptions.ClientIDSuffix
+	o.MQTTOptions = *mqttOptions
+	o.Unlock()
+
+	// notify the cloudevents client to reconnect with the new options
+	go func() {
+		o.errorChan <- fmt.Errorf("the MQTT options are reloaded from %s", configPath)
+	}()
+
+	return nil
+}
+
+func (o *mqttSourceOptions) mqttOptions() MQTTOptions {
+	o.RLock()
+	defer o.RUnlock()
+	return o.MQTTOptions
+}
@@ -114,6 +114,26 @@ func TestSourceContext(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "broadcast spec without the source broadcast topic",
+			event: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              "test",
+				}
+
+				evt := cloudevents.NewEvent()
+				evt.SetType(eventType.String())
+				evt.SetExtension("clustername", types.ClusterAll)
+				return evt
+			}(),
+			assertError: func(err error) {
+				if err == nil {
+					t.Errorf("expected error, but failed")
+				}
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,103 @@
+package mqtt
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const defaultWebSocketPath = "/mqtt"
+
+// isWebSocket returns true if the broker host is a WebSocket URL (ws:// or wss://).
+func isWebSocket(brokerHost string) bool {
+	u, err := url.Parse(brokerHost)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "ws" || u.Scheme == "wss"
+}
+
+// dialWebSocket connects to the WebSocket listener of the MQTT broker, the path of the broker URL is /mqtt by default.
+func dialWebSocket(brokerURL string, timeout time.Duration, tlsConfig *tls.Config) (net.Conn, error) {
+	u, err := url.Parse(brokerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Path == "" {
+		u.Path = defaultWebSocketPath
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: timeout,
+		TLSClientConfig:  tlsConfig,
+		Subprotocols:     []string{"mqtt"},
+	}
+
+	conn, resp, err := dialer.Dial(u.String(), nil)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker %s, %v", u.String(), err)
+	}
+
+	return &webSocketConn{Conn: conn}, nil
+}
+
+// webSocketConn adapts a WebSocket connection to a net.Conn, the MQTT packets are sent with binary messages and a
+// packet may span multiple messages.
+type webSocketConn struct {
+	*websocket.Conn
+	reader io.Reader
+}
+
+var _ net.Conn = &webSocketConn{}
+
+func (c *webSocketConn) Read(p []byte) (int, error) {
+	for {
+		if c.reader == nil {
+			messageType, reader, err := c.NextReader()
+			if err != nil {
+				return 0, err
+			}
+
+			if messageType != websocket.BinaryMessage {
+				continue
+			}
+			c.reader = reader
+		}
+
+		n, err := c.reader.Read(p)
+		if err == io.EOF {
+			// the current message is read completely, continue with the next message
+			c.reader = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (c *webSocketConn) Write(p []byte) (int, error) {
+	if err := c.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *webSocketConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
@@ -2,8 +2,19 @@ package options
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/features"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
 // CloudEventsOptions provides cloudevents clients to send/receive cloudevents based on different event protocol.
@@ -24,6 +35,584 @@ type CloudEventsOptions interface {
 	ErrorChan() <-chan error
 }
 
+// ResumableOptions is implemented by the CloudEventsOptions whose subscriptions can be resumed after the client is
+// reconnected, e.g. the gRPC server replays the missed events from the offsets of the last received events.
+type ResumableOptions interface {
+	// Resumable returns true if the subscriptions can be resumed without missing any event, the source/agent client
+	// does not notify the reconnection to trigger a full resync in this case.
+	Resumable() bool
+}
+
+// DiagnosableOptions is implemented by the CloudEventsOptions that report the diagnostics of their transports, the
+// diagnostics are included in the Diagnostics of the source/agent client.
+type DiagnosableOptions interface {
+	// Diagnostics returns the diagnostics of the transport, e.g. the broker address and the subscribed topics.
+	Diagnostics() TransportDiagnostics
+}
+
+// ContentMode is the CloudEvents content mode that is used to send the events.
+type ContentMode string
+
+const (
+	// ContentModeBinary sends the event attributes in the transport metadata, e.g. the MQTT user properties, and the
+	// event data in the payload. It is the default content mode of the MQTT and gRPC transports.
+	ContentModeBinary ContentMode = "binary"
+
+	// ContentModeStructured sends the whole event as a JSON document in the payload, it is used with the brokers or the
+	// intermediaries that only handle the structured JSON.
+	ContentModeStructured ContentMode = "structured"
+)
+
+// ValidateContentMode returns an error if the given content mode is not supported, the empty content mode is valid to
+// use the default content mode of the transport.
+func ValidateContentMode(mode ContentMode) error {
+	switch mode {
+	case "", ContentModeBinary, ContentModeStructured:
+		return nil
+	default:
+		return fmt.Errorf("unsupported content mode %q, it should be %s or %s",
+			mode, ContentModeBinary, ContentModeStructured)
+	}
+}
+
+// WithContentMode returns back a new context that forces the event to be sent in the given content mode, the context
+// is not changed if the content mode is empty, so the event is sent in the default content mode of the transport.
+func WithContentMode(ctx context.Context, mode ContentMode) context.Context {
+	switch mode {
+	case ContentModeStructured:
+		return binding.WithForceStructured(ctx)
+	case ContentModeBinary:
+		return binding.WithForceBinary(ctx)
+	default:
+		return ctx
+	}
+}
+
+// PublishTimeoutError is returned by the transports when a published event is not acknowledged by the broker or the
+// server within the publish acknowledgment timeout, e.g. the MQTT broker accepts the connection but never sends the
+// PUBACK. It wraps the context.DeadlineExceeded, so the source/agent client returns it with the ErrPublishTimeout.
+type PublishTimeoutError struct {
+	// Topic is the topic that the event is published to.
+	Topic string
+
+	// Timeout is the publish acknowledgment timeout.
+	Timeout time.Duration
+}
+
+func (e *PublishTimeoutError) Error() string {
+	return fmt.Sprintf("the event published to %q is not acknowledged within %v", e.Topic, e.Timeout)
+}
+
+func (e *PublishTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// SendWithAckTimeout calls the send func with a context that is done after the given timeout, it returns a
+// PublishTimeoutError if the send func is stopped by the timeout instead of the given context. The send func is called
+// with the given context if the timeout is not positive.
+func SendWithAckTimeout(
+	ctx context.Context, timeout time.Duration, topic string, send func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return send(ctx)
+	}
+
+	sendingCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := send(sendingCtx)
+	if err != nil && ctx.Err() == nil &&
+		(errors.Is(err, context.DeadlineExceeded) || errors.Is(sendingCtx.Err(), context.DeadlineExceeded)) {
+		return &PublishTimeoutError{Topic: topic, Timeout: timeout}
+	}
+
+	return err
+}
+
+// ConnectionEvent describes a connection transition of the source/agent client.
+type ConnectionEvent struct {
+	// Time is the time of the transition.
+	Time time.Time
+
+	// Transport is the diagnostics of the transport of the connection, e.g. the broker address, it is empty if the
+	// transport does not implement the DiagnosableOptions.
+	Transport TransportDiagnostics
+
+	// Reconnected is true if the client is connected again after it is disconnected or switched to another transport,
+	// it is false for the first connection of the client.
+	Reconnected bool
+
+	// Resumed is true if the subscriptions are resumed from the last received events, so the resync is not required
+	// after the resubscribe, see the ResumableOptions.
+	Resumed bool
+
+	// Err is the error that disconnects the client, it is nil if the client is disconnected on purpose, e.g. the client
+	// is switched to another transport or closed.
+	Err error
+}
+
+// ConnectionEventHandlers are the optional callbacks of the connection transitions of the source/agent client, so the
+// controllers can update their metrics, flip their readiness or log the broker of each connection without inferring
+// the connection state from the publish failures. The callbacks are called in the order of the transitions on the
+// connection goroutine of the client, so they must not block.
+type ConnectionEventHandlers struct {
+	// OnConnect is called after the client is connected or reconnected to the broker/server.
+	OnConnect func(event ConnectionEvent)
+
+	// OnDisconnect is called after the client is disconnected from the broker/server.
+	OnDisconnect func(event ConnectionEvent)
+
+	// OnResubscribe is called after the receiver of the client is restarted on a reconnected connection, it is called
+	// after the OnConnect of the reconnection.
+	OnResubscribe func(event ConnectionEvent)
+}
+
+// DiscardedEventHandler is called when a received event is discarded by the source/agent client without handling, the
+// reason describes why the event is discarded.
+type DiscardedEventHandler func(evt cloudevents.Event, reason string)
+
+// ResyncReport describes a resync request that is responded by a source/agent client.
+type ResyncReport struct {
+	// EventDataType is the event data type of the resynced resources.
+	EventDataType types.CloudEventsDataType
+
+	// ClusterName is the cluster name of the agent that requests the spec resync, it is set on the source.
+	ClusterName string
+
+	// Source is the source that requests the status resync, it is set on the agent.
+	Source string
+
+	// ResourceID is the resource ID of a targeted resync that is requested for a single resource, it is empty if the
+	// resync is requested for all the resources.
+	ResourceID string
+
+	// Resources is the number of the resources that are checked in the resync.
+	Resources int
+
+	// OutOfSync is the number of the resources that are out of sync and resent to the requester.
+	OutOfSync int
+
+	// Duration is the time that is taken to respond the resync request.
+	Duration time.Duration
+
+	// ReceivedBytes is the data size of the resync request.
+	ReceivedBytes int
+
+	// SentBytes is the total data size of the events that are resent to the requester.
+	SentBytes int
+}
+
+// ResyncReporter is called after a source/agent client responds a resync request.
+type ResyncReporter func(report ResyncReport)
+
+// ResyncTargetStatus is the resync progress of a cluster on a source client or of a source on an agent client.
+type ResyncTargetStatus struct {
+	// InProgress is true if a resync request is being sent to the cluster or source.
+	InProgress bool
+
+	// LastAttemptTime is the time when the last resync request was started.
+	LastAttemptTime time.Time
+
+	// LastSuccessTime is the time when the last resync request was sent successfully, it is zero if no resync request
+	// has been sent successfully.
+	LastSuccessTime time.Time
+
+	// LastError is the error of the last resync request, it is nil if the last resync request was sent successfully.
+	LastError error
+
+	// ConsecutiveFailures is the number of the resync requests that are failed since the last successful one.
+	ConsecutiveFailures int
+}
+
+// ResyncStatus is the resync state of a source/agent client.
+type ResyncStatus struct {
+	// LastResyncTime is the time when a resync request was sent successfully by the client most recently.
+	LastResyncTime time.Time
+
+	// Targets is the resync progress of the clusters on a source client or of the sources on an agent client, it is
+	// keyed by the cluster name or the source. The resync of all clusters (types.ClusterAll) or all sources
+	// (types.SourceAll) is keyed by the empty string.
+	Targets map[string]ResyncTargetStatus
+}
+
+// StaleTargets returns the clusters or sources whose resync has not succeeded in the given duration, the targets that
+// never resynced successfully are stale once their first resync attempt is older than the duration.
+func (s ResyncStatus) StaleTargets(d time.Duration) []string {
+	stale := []string{}
+	for target, status := range s.Targets {
+		last := status.LastSuccessTime
+		if last.IsZero() {
+			last = status.LastAttemptTime
+		}
+		if time.Since(last) > d {
+			stale = append(stale, target)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// ConnectionState is the state of the connection between a source/agent client and the broker.
+type ConnectionState string
+
+const (
+	// ConnectionStateConnected means the client is connected to the broker.
+	ConnectionStateConnected ConnectionState = "Connected"
+
+	// ConnectionStateDisconnected means the client is disconnected from the broker and is reconnecting.
+	ConnectionStateDisconnected ConnectionState = "Disconnected"
+
+	// ConnectionStateClosed means the client is closed, it does not reconnect anymore.
+	ConnectionStateClosed ConnectionState = "Closed"
+)
+
+// TransportDiagnostics is the diagnostics of the transport of a source/agent client.
+type TransportDiagnostics struct {
+	// BrokerAddress is the address of the broker or the server that the client connects to.
+	BrokerAddress string `json:"brokerAddress,omitempty"`
+
+	// Features are the protocol features that are negotiated with the broker on the last connection, e.g. the MQTT
+	// receive maximum and topic alias maximum of the broker.
+	Features map[string]string `json:"features,omitempty"`
+
+	// Subscriptions are the topics that the client subscribes to.
+	Subscriptions []string `json:"subscriptions,omitempty"`
+}
+
+// Diagnostics is a snapshot of the state of a source/agent client, it is used to troubleshoot the client, e.g. it is
+// collected in the support bundles.
+type Diagnostics struct {
+	// State is the state of the connection between the client and the broker.
+	State ConnectionState `json:"state"`
+
+	// Transport is the diagnostics of the transport, it is only reported by the transports that implement the
+	// DiagnosableOptions.
+	Transport TransportDiagnostics `json:"transport"`
+
+	// Resumable is true if the subscriptions of the client can be resumed without a resync after it is reconnected.
+	Resumable bool `json:"resumable"`
+
+	// InFlight is the number of the publishes and the received events that are being processed.
+	InFlight int `json:"inFlight"`
+
+	// Buffered is the number of the received events that are held by the receive buffer.
+	Buffered int `json:"buffered"`
+
+	// PendingCalls is the number of the call requests that are waiting for their responses.
+	PendingCalls int `json:"pendingCalls"`
+
+	// LastConnectedTime is the time when the client was connected to the broker most recently.
+	LastConnectedTime time.Time `json:"lastConnectedTime"`
+
+	// LastDisconnectedTime is the time when the client was disconnected from the broker most recently.
+	LastDisconnectedTime time.Time `json:"lastDisconnectedTime"`
+
+	// LastConnectErrorTime is the time when the client failed to reconnect to the broker most recently.
+	LastConnectErrorTime time.Time `json:"lastConnectErrorTime"`
+
+	// LastPublishErrorTime is the time when the client failed to send an event most recently.
+	LastPublishErrorTime time.Time `json:"lastPublishErrorTime"`
+
+	// LastError is the most recent error of the connection or the publishes, it is empty if no error has occurred.
+	LastError string `json:"lastError,omitempty"`
+
+	// PublishTimeouts is the number of the published events that are not acknowledged by the broker or the server
+	// before the publish acknowledgment timeout or the deadline of the publish context.
+	PublishTimeouts int64 `json:"publishTimeouts,omitempty"`
+
+	// QuotaRejections is the number of the published resources that are rejected by the quota of the source.
+	QuotaRejections int64 `json:"quotaRejections,omitempty"`
+
+	// PayloadSizes are the size histograms of the sent and received events by the event data types, so the data
+	// types that produce the oversized events can be found before the broker starts rejecting them.
+	PayloadSizes map[string]PayloadSizes `json:"payloadSizes,omitempty"`
+}
+
+// PayloadSizeObserver is called with the size in bytes of the JSON encoded event that is sent or received by the
+// source/agent client, the sent events that are rejected by the MaxEventSize are observed too.
+type PayloadSizeObserver func(direction AuditDirection, evt cloudevents.Event, size int)
+
+// PayloadSizeBuckets are the upper bounds in bytes of the buckets of the payload size histograms.
+var PayloadSizeBuckets = []int{1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20}
+
+// PayloadSizes are the size histograms of the events of an event data type.
+type PayloadSizes struct {
+	// Sent is the size histogram of the sent events.
+	Sent PayloadSizeHistogram `json:"sent"`
+
+	// Received is the size histogram of the received events.
+	Received PayloadSizeHistogram `json:"received"`
+}
+
+// PayloadSizeHistogram is a histogram of the event sizes in bytes.
+type PayloadSizeHistogram struct {
+	// Buckets are the cumulative counts of the events by the PayloadSizeBuckets, the events that are larger than the
+	// last bucket are only counted in the Count.
+	Buckets []PayloadSizeBucket `json:"buckets"`
+
+	// Count is the number of the events.
+	Count int64 `json:"count"`
+
+	// Sum is the total size of the events.
+	Sum int64 `json:"sum"`
+
+	// Max is the size of the largest event.
+	Max int `json:"max"`
+
+	// Rejected is the number of the sent events that are rejected because they are larger than the MaxEventSize.
+	Rejected int64 `json:"rejected,omitempty"`
+}
+
+// PayloadSizeBucket is the number of the events whose sizes are less than or equal to the upper bound.
+type PayloadSizeBucket struct {
+	UpperBound int   `json:"le"`
+	Count      int64 `json:"count"`
+}
+
+// ResyncCompleteHandler is called after a source/agent client finishes sending a resync request to a cluster or a
+// source, the err is nil if the request was sent successfully.
+type ResyncCompleteHandler func(target string, err error)
+
+// CallHandler is called when a source/agent client receives a call request, the returned event is sent back to the
+// caller as the call response, only its data and its custom extensions are used, the type, the routing extensions and
+// the correlation ID of the response are set by the client. If an error is returned, the call fails on the caller with
+// the error message.
+type CallHandler func(ctx context.Context, request cloudevents.Event) (*cloudevents.Event, error)
+
+// DefaultCallTimeout is the default timeout to wait for the response of a call.
+const DefaultCallTimeout = 30 * time.Second
+
+// DeliveryReceiptHandler is called when a source client receives the delivery receipt of a resource spec from the
+// agent of a cluster.
+type DeliveryReceiptHandler func(clusterName string, receipt payload.DeliveryReceipt)
+
+// AgentRegistrationHandler is called when a source client receives the registration request of an agent, the returned
+// bootstrap is sent back to the agent as the registration response.
+type AgentRegistrationHandler func(ctx context.Context, registration payload.AgentRegistration) (*payload.AgentBootstrap, error)
+
+// HandlerErrorHandler is called when a received event is failed to be handled by a resource handler after all the
+// retries.
+type HandlerErrorHandler func(evt cloudevents.Event, err error)
+
+// HandlerErrorPolicy decides what the source/agent client does when a resource handler returns an error.
+type HandlerErrorPolicy struct {
+	// MaxRetries is the maximum number of times to retry a failed handler with exponential backoff.
+	// If it's less than or equal to zero, the failed handler will not be retried.
+	MaxRetries int
+
+	// RetryBackoff is the delay of the first retry, the delay is doubled for each subsequent retry.
+	// If it's less than or equal to zero, the DefaultRetryBackoff (100ms) will be used.
+	RetryBackoff time.Duration
+
+	// Requeue indicates the failed handler is retried asynchronously with an internal workqueue, otherwise the retries
+	// block the receiving of the subsequent events. The retries are keyed by the resources, the retry of a resource is
+	// dropped once a newer event of the resource is received. The newer event does not wait for a retry that is being
+	// handled, so the handlers are not blocked by each other, the result of the superseded retry is discarded.
+	Requeue bool
+
+	// ErrorHandler is an optional hook to handle the error of the failed handler after all the retries.
+	ErrorHandler HandlerErrorHandler
+}
+
+// ReceiveOverflowPolicy decides what the source/agent client does with a received event when its receive buffer is
+// full.
+type ReceiveOverflowPolicy string
+
+const (
+	// ReceiveOverflowBlock blocks the receiving of the transport until the buffer has room, the MQTT messages are not
+	// acknowledged and the gRPC stream is not read until then, so the broker/server throttles the delivery.
+	ReceiveOverflowBlock ReceiveOverflowPolicy = "Block"
+
+	// ReceiveOverflowDrop discards the received event, the discarded event is reported to the DiscardedEventHandler.
+	ReceiveOverflowDrop ReceiveOverflowPolicy = "Drop"
+)
+
+// ObjectStore stores the event data that is offloaded with the claim-check, e.g. an S3 or GCS bucket.
+type ObjectStore interface {
+	// Put uploads the data with the given key.
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Get downloads the data of the given key.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// ClaimCheck offloads the large event data to an object store, the event only carries a signed reference of the
+// data, and the receiving client fetches the data with the reference and verifies it.
+type ClaimCheck struct {
+	// Store is the object store that the event data is uploaded to and downloaded from.
+	Store ObjectStore
+
+	// Threshold is the size of the event data in bytes, the data larger than it is offloaded to the store.
+	// If it's less than or equal to zero, the data of all the sent events is offloaded.
+	Threshold int
+
+	// SigningKey is the key to sign the reference with HMAC-SHA256, the sending and receiving clients must use the
+	// same key.
+	SigningKey []byte
+}
+
+// AuditDirection indicates an audited event is sent or received by the source/agent client.
+type AuditDirection string
+
+const (
+	AuditSent     AuditDirection = "Sent"
+	AuditReceived AuditDirection = "Received"
+)
+
+// AuditRecord records who changed what and when with a resource spec or status event.
+type AuditRecord struct {
+	// Time is the time when the event is sent or received.
+	Time time.Time `json:"time"`
+
+	// Direction indicates the event is sent or received by the client.
+	Direction AuditDirection `json:"direction"`
+
+	// EventID is the ID of the event.
+	EventID string `json:"eventID"`
+
+	// Source is the source of the event, e.g. the source ID of a source or the agent ID of an agent.
+	Source string `json:"source"`
+
+	// ClusterName is the name of the cluster that the resource belongs to.
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// ResourceID is the ID of the resource.
+	ResourceID string `json:"resourceID"`
+
+	// ResourceVersion is the version of the resource.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+
+	// DataType is the event data type of the resource.
+	DataType string `json:"dataType"`
+
+	// SubResource indicates the event is a spec or status event.
+	SubResource types.EventSubResource `json:"subResource"`
+
+	// Action is the action of the event.
+	Action types.EventAction `json:"action"`
+
+	// Hash is the SHA-256 hash of the event data.
+	Hash string `json:"hash"`
+}
+
+// AuditSink records the audit records of the source/agent client, e.g. to a file, a webhook or an audit topic.
+// Available implementations are in the audit package.
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord) error
+}
+
+// EventExtensionHook customizes the extensions of the events that are sent and received by the source/agent client
+// without changing the codecs, e.g. adds a tenant ID, a trace ID or a priority to every sent event.
+type EventExtensionHook struct {
+	// Enrich is optional, it returns the custom extensions that are added to an event before the event is sent. The
+	// extensions that are already set on the event, e.g. the resource ID, cannot be overridden.
+	Enrich func(ctx context.Context, evt cloudevents.Event) (map[string]any, error)
+
+	// Received is optional, it is called with a received event before the event is handled, the custom extensions can
+	// be read from the event. The returned context is used to handle the event, e.g. to send the resync responses.
+	Received func(ctx context.Context, evt cloudevents.Event) context.Context
+}
+
+// DefaultClusterClaimRenewInterval is the default interval of the agents to renew the claims of their clusters.
+const DefaultClusterClaimRenewInterval = 10 * time.Second
+
+// ClusterClaimedError is returned by a ClusterClaimer when the cluster is claimed by another live agent.
+type ClusterClaimedError struct {
+	// ClusterName is the name of the claimed cluster.
+	ClusterName string
+
+	// Holder is the ID of the agent that holds the claim of the cluster.
+	Holder string
+}
+
+func (e *ClusterClaimedError) Error() string {
+	return fmt.Sprintf("the cluster %s is claimed by the agent %s", e.ClusterName, e.Holder)
+}
+
+// ClusterClaimer acquires the claims of the clusters for the agents, a claim expires if it is not renewed by its agent
+// in time, e.g. the agent is gone, so only one live agent handles the resources of a cluster. Available
+// implementations are in the transport packages, e.g. the retained message claimer of the mqtt package.
+type ClusterClaimer interface {
+	// Claim acquires or renews the claim of the cluster for the agent, it returns a ClusterClaimedError if the cluster
+	// is claimed by another live agent.
+	Claim(ctx context.Context, clusterName, agentID string) error
+
+	// Release releases the claim of the cluster if it is held by the agent, so another agent can claim the cluster
+	// without waiting for the claim to expire.
+	Release(ctx context.Context, clusterName, agentID string) error
+}
+
+// ClusterClaim makes an agent claim its cluster before it subscribes to the resources of the cluster, so two agents
+// that are started for one cluster, e.g. an old agent that is not stopped after the cluster is re-imported, do not
+// apply the resources of the cluster at the same time.
+type ClusterClaim struct {
+	// Claimer acquires and renews the claim of the cluster.
+	Claimer ClusterClaimer
+
+	// RenewInterval is the interval to renew the claim, it must be shorter than the expiry of the claims of the
+	// claimer. If it's less than or equal to zero, the DefaultClusterClaimRenewInterval is used.
+	RenewInterval time.Duration
+
+	// Standby decides what the agent does if another live agent holds the claim of the cluster when the agent is
+	// started. By default, the agent client fails to start with the ErrClusterClaimed. If it is true, the agent client
+	// is started as a standby, it discards the received resource specs and does not publish the resource status until
+	// it acquires the claim, then it notifies the reconnected signal, so the agent resyncs the resources.
+	Standby bool
+}
+
+const (
+	// QuotaMaxResourcesPerCluster is the quota of the number of the resources of a source on a cluster.
+	QuotaMaxResourcesPerCluster = "MaxResourcesPerCluster"
+
+	// QuotaMaxPublishRate is the quota of the publish rate of a source.
+	QuotaMaxPublishRate = "MaxPublishRate"
+)
+
+// QuotaExceededError is returned when a source publishes a resource over its quota.
+type QuotaExceededError struct {
+	// Quota is the exceeded quota, e.g. QuotaMaxResourcesPerCluster.
+	Quota string
+
+	// Limit is the limit of the exceeded quota.
+	Limit float64
+
+	// ClusterName is the cluster that the resource is published to.
+	ClusterName string
+
+	// ResourceID is the ID of the rejected resource.
+	ResourceID string
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("the resource %s of the cluster %s exceeds the quota %s (%v)",
+		e.ResourceID, e.ClusterName, e.Quota, e.Limit)
+}
+
+// SourceQuota limits the resources and the publish rate of a source, so a runaway source controller cannot flood the
+// broker that is shared with the other sources. Different from the EventRateLimit, which throttles the publishes, the
+// publishes over the quota are rejected immediately. The quota only limits the resource specs that are created or
+// updated by the source, the deletes, the resync responses, the handovers and the claims are not limited, so a resync
+// is never rejected partway and the agents always get the specs that they already have.
+type SourceQuota struct {
+	// MaxResourcesPerCluster is the maximum number of the resources of the source on a cluster, the resources of a
+	// cluster are listed with the lister of the source client when the cluster is published to for the first time, and
+	// then they are counted with the published specs, a resource is rejected if the cluster already has the maximum
+	// number of the other resources, e.g. a new resource or the resources over the quota after the quota is lowered,
+	// the deleting resources are not counted. If it's less than or equal to zero, the resources are not limited.
+	MaxResourcesPerCluster int
+
+	// MaxPublishQPS is the maximum rate of the resource specs that are published by the source. If it's less than or
+	// equal to zero, the publish rate is not limited.
+	MaxPublishQPS float32
+
+	// MaxPublishBurst is the maximum burst of the published resource specs. If it's less than or equal to zero, the
+	// burst is the MaxPublishQPS rounded up.
+	MaxPublishBurst int
+
+	// OnExceeded is an optional hook that is called with the rejected publishes, e.g. to export the rejections to the
+	// metrics of the controller, the number of the rejections is also reported by the Diagnostics of the client.
+	OnExceeded func(err *QuotaExceededError)
+}
+
 // EventRateLimit for limiting the event sending rate.
 type EventRateLimit struct {
 	// QPS indicates the maximum QPS to send the event.
@@ -35,6 +624,121 @@ type EventRateLimit struct {
 	Burst int
 }
 
+// DefaultResyncConcurrency is the default number of the clusters whose status resync requests are sent in parallel.
+const DefaultResyncConcurrency = 10
+
+// DefaultReconnectJitter is the default jitter factor of the reconnect backoff of the source/agent clients.
+const DefaultReconnectJitter = 1.0
+
+// Jitter is the random delays of the timers of a source/agent client, so the thousands of clients that lose their
+// connections at the same time, e.g. the broker is restarted, do not reconnect and resync at the same time.
+type Jitter struct {
+	// Resync is the maximum random delay of the reconnected signal of the client, so the resync requests that are sent
+	// on the signal are spread over the delay. If it's less than or equal to zero, the signal is sent immediately.
+	Resync time.Duration
+
+	// Reconnect is the jitter factor of the reconnect backoff, the delay of each reconnect is randomly increased by up
+	// to the factor of the delay. If it's less than or equal to zero, the DefaultReconnectJitter is used.
+	Reconnect float64
+}
+
+// CloudEventsClientOptions provides the options that are shared by the source and agent CloudEventsClients
+type CloudEventsClientOptions struct {
+	// DiscardedEventHandler is an optional hook to report the received events that are discarded by the client, e.g.
+	// the events that are expired.
+	DiscardedEventHandler DiscardedEventHandler
+
+	// DedupeCacheSize is the number of the recently received events that are remembered by the client to suppress the
+	// duplicated events, an event is identified by its ID and resource version.
+	// If it's less than or equal to zero, the duplicated events will not be suppressed.
+	DedupeCacheSize int
+
+	// ResyncReporter is an optional hook to report how effective the resync requests are, e.g. how many resources are
+	// out of sync.
+	ResyncReporter ResyncReporter
+
+	// OnResyncComplete is an optional hook that is called after the client finishes sending a resync request, e.g. to
+	// alert when the resync of a cluster has not succeeded for a while, see also the ResyncStatus of the client.
+	OnResyncComplete ResyncCompleteHandler
+
+	// ConnectionEventHandlers are the optional callbacks of the connection transitions of the client, e.g. the
+	// connects, the disconnects and the resubscribes.
+	ConnectionEventHandlers ConnectionEventHandlers
+
+	// HandlerErrorPolicy decides how to handle the errors that are returned by the resource handlers, by default, the
+	// errors are only logged.
+	HandlerErrorPolicy HandlerErrorPolicy
+
+	// ReceiveWorkers is the number of workers that process the received events, the events of one resource are
+	// processed one by one in the order they are received.
+	// If it's less than or equal to zero, the events are processed on the receiving goroutine of the transport.
+	ReceiveWorkers int
+
+	// ReceivePriorityLanes is the number of the priority lanes of the receive workers, the events with a higher priority
+	// are processed ahead of the others, e.g. the resync requests, the resync responses and the delete events are
+	// processed ahead of the bulk spec or status updates, the events of one resource are still processed in order. The
+	// priority of an event is its priority extension, see types.GetPriority. It is only used with the ReceiveWorkers.
+	// If it's less than or equal to one, the events are processed in the order they are received.
+	ReceivePriorityLanes int
+
+	// ReceiveBufferSize is the maximum number of the received events that are being processed or waiting to be
+	// processed. If it's less than or equal to zero, the received events are not limited.
+	ReceiveBufferSize int
+
+	// ReceiveOverflowPolicy decides what to do with a received event when the receive buffer is full, by default, the
+	// receiving is blocked (ReceiveOverflowBlock).
+	ReceiveOverflowPolicy ReceiveOverflowPolicy
+
+	// ClaimCheck is optional, if it is set, the event data larger than its threshold is offloaded to an object store,
+	// and the received events that carry a claim-check reference are fetched from the object store.
+	ClaimCheck *ClaimCheck
+
+	// AuditSink is optional, if it is set, the resource spec and status events that are sent or received by the client
+	// are recorded to it.
+	AuditSink AuditSink
+
+	// MaxEventSize is the maximum size in bytes of the JSON encoded event that is published by the client, a larger
+	// event is rejected before it is sent. If it's less than or equal to zero, the event size is not limited.
+	MaxEventSize int
+
+	// PayloadSizeObserver is optional, it is called with the size of every event that is sent or received by the
+	// client, e.g. to export the sizes to a histogram of the metrics of the controller.
+	PayloadSizeObserver PayloadSizeObserver
+
+	// Clock is optional, it is the clock of the timers of the client, e.g. the reconnect backoff, the resync jitter, the
+	// handler retries and the coalescing windows, so the tests can fast-forward the timers with a fake clock. By
+	// default, the real clock is used.
+	Clock clock.WithTickerAndDelayedExecution
+
+	// Jitter spreads the timers of the clients that are started or reconnected at the same time, e.g. the agents that
+	// are reconnected to a restarted broker, see Jitter.
+	Jitter Jitter
+
+	// EventExtensionHook is optional, it adds the custom extensions to the sent events and reads the custom extensions
+	// of the received events.
+	EventExtensionHook EventExtensionHook
+
+	// Capabilities are the protocol features that are enabled on the client, they are advertised to the peers with the
+	// resync requests, and a feature is only used with a peer that advertises it too.
+	Capabilities features.Set
+
+	// TenantID is the tenant of the client, if it is set, the sent events are labeled with the tenant, and the received
+	// events of the other tenants are discarded.
+	TenantID string
+
+	// EventSchemas is optional, if it is set, the received events are validated with the schemas of their data types,
+	// the invalid events are discarded before they are handled.
+	EventSchemas *schema.Registry
+
+	// CallHandler is optional, if it is set, the client responds the call requests with it, otherwise the call requests
+	// are responded with an error.
+	CallHandler CallHandler
+
+	// CallTimeout is the timeout to wait for the response of a call if the context of the call has no earlier deadline.
+	// If it's less than or equal to zero, DefaultCallTimeout is used.
+	CallTimeout time.Duration
+}
+
 // CloudEventsSourceOptions provides the required options to build a source CloudEventsClient
 type CloudEventsSourceOptions struct {
 	// CloudEventsOptions provides cloudevents clients to send/receive cloudevents based on different event protocol.
@@ -47,6 +751,67 @@ type CloudEventsSourceOptions struct {
 
 	// EventRateLimit limits the event sending rate.
 	EventRateLimit EventRateLimit
+
+	// CloudEventsClientOptions are the options that are shared by the source and agent clients.
+	CloudEventsClientOptions
+
+	// AgentRegistrationHandler is optional, if it is set, the client responds the registration requests of the agents
+	// with the bootstrap payloads that are returned by it, otherwise the registration requests are ignored.
+	AgentRegistrationHandler AgentRegistrationHandler
+
+	// ResyncConcurrency is the number of the clusters whose status resync requests are sent in parallel when the
+	// client resyncs a set of clusters. If it's less than or equal to zero, DefaultResyncConcurrency is used.
+	ResyncConcurrency int
+
+	// ShardFilter is optional, if it is set, the client only handles the received events of the clusters that the
+	// filter accepts, e.g. the Owns of a sharding.Sharder, so the replicas of a source controller each handle a
+	// disjoint subset of the clusters.
+	ShardFilter func(clusterName string) bool
+
+	// IsLeader is optional, if it is set, only the leader replica of an active-passive source publishes the resource
+	// specs, sends the status resync requests and responds the spec resync and registration requests. The standby
+	// replicas keep receiving the resource status, so their caches are warm when they take over, e.g. the IsLeader
+	// reports the state of a leader election lock. The standby replicas get ErrNotLeader when they publish or resync.
+	IsLeader func() bool
+
+	// SpecCoalesceWindow is the window to debounce the spec updates of a resource, the spec updates of a resource within
+	// the window are coalesced into its latest update, which is published at the end of the window. The spec of the
+	// deleting resources and the responses of the spec resync requests are published immediately.
+	// If it's less than or equal to zero, the spec updates are published immediately.
+	SpecCoalesceWindow time.Duration
+
+	// SpecCoalesceErrorHandler is optional, it is called with the coalesced spec updates that cannot be published at
+	// the end of their windows, e.g. they are rejected by the quota, or they still fail after they are retried in the
+	// next windows, because Publish has returned before the updates are published. The pending spec updates are also
+	// published when the client is drained or closed, and Drain returns the errors of them.
+	SpecCoalesceErrorHandler func(eventType types.CloudEventsType, resourceID string, err error)
+
+	// VersionComparator is optional, it compares the resource versions of the resources, e.g. to find the resources
+	// whose specs are out of date on the agents when the spec resync requests are responded, see generic.VersionComparator.
+	// By default, the resource versions are int64 sequence numbers.
+	VersionComparator func(a, b string) (int, error)
+
+	// RequestDeliveryReceipts requests the agents to acknowledge the published resource specs with the delivery
+	// receipts once the specs are decoded, so a spec that is not delivered can be told from a spec that is delivered
+	// but not applied yet, see the DeliveryReceipt of the CloudEventSourceClient.
+	RequestDeliveryReceipts bool
+
+	// DeliveryReceiptHandler is an optional hook to handle the received delivery receipts.
+	DeliveryReceiptHandler DeliveryReceiptHandler
+
+	// Quota limits the resources and the publish rate of the source, the publishes over the quota are rejected with
+	// the ErrQuotaExceeded, by default, the source is not limited.
+	Quota SourceQuota
+
+	// StatusHashAlgorithm is the algorithm of the status hashes that are calculated by the StatusHashGetter of the
+	// client, see generic.StatusHashOptions, it is sent with the status resync requests, so the agents only compare
+	// the status hashes that are calculated by the same algorithm. By default, it is the SHA256 checksum.
+	StatusHashAlgorithm string
+
+	// StatusResyncedAction is optional, if it's true, the status of a resource that is resent by a resync response
+	// without a status change is passed to the handlers with the types.StatusResynced action, otherwise the status is
+	// ignored as the other unchanged statuses. The handlers that enable it must handle the types.StatusResynced action.
+	StatusResyncedAction bool
 }
 
 // CloudEventsAgentOptions provides the required options to build an agent CloudEventsClient
@@ -63,4 +828,62 @@ type CloudEventsAgentOptions struct {
 
 	// EventRateLimit limits the event sending rate.
 	EventRateLimit EventRateLimit
+
+	// CloudEventsClientOptions are the options that are shared by the source and agent clients.
+	CloudEventsClientOptions
+
+	// ClusterClaim is optional, if it is set, the agent claims its cluster before it is started, and the agent is a
+	// standby while another live agent holds the claim of the cluster, see ClusterClaim.
+	ClusterClaim *ClusterClaim
+
+	// StatusCoalesceWindow is the window to debounce the status updates of a resource, the status updates of a resource
+	// within the window are coalesced into its latest update, which is published at the end of the window only if its
+	// status hash is changed since the last published status. The status of the deleting resources and the responses
+	// of the status resync requests are published immediately.
+	// If it's less than or equal to zero, the status updates are published immediately.
+	StatusCoalesceWindow time.Duration
+
+	// StatusCoalesceErrorHandler is optional, it is called with the coalesced status updates that cannot be published
+	// at the end of their windows, e.g. they are rejected by the validation, or they still fail after they are retried
+	// in the next windows, because Publish has returned before the updates are published. The pending status updates
+	// are also published when the client is drained or closed, and Drain returns the errors of them.
+	StatusCoalesceErrorHandler func(eventType types.CloudEventsType, resourceID string, err error)
+
+	// VersionComparator is optional, it compares the resource versions of the received resource specs, e.g. to discard
+	// the specs that are older than the handled specs, see generic.VersionComparator. By default, the resource versions
+	// are int64 sequence numbers.
+	VersionComparator func(a, b string) (int, error)
+
+	// StatusHashAlgorithm is the algorithm of the status hashes that are calculated by the StatusHashGetter of the
+	// client, see generic.StatusHashOptions. If a status resync request carries the status hashes of another
+	// algorithm, the client resends the status of all the resources instead of comparing the status hashes. By
+	// default, it is the SHA256 checksum.
+	StatusHashAlgorithm string
+
+	// SpecResyncedAction is optional, if it's true, the spec of a resource that is resent by a resync response without
+	// a resource version change is passed to the handlers with the types.SpecResynced action, otherwise the spec is
+	// ignored as the other unchanged specs. The handlers that enable it must handle the types.SpecResynced action.
+	SpecResyncedAction bool
+}
+
+// CloudEventsObserverOptions provides the required options to build an observer client, an observer subscribes to the
+// resource spec and status events of all sources and clusters that it is allowed to read by the broker, and it never
+// sends events, so it does not participate in the resync between the sources and the agents.
+type CloudEventsObserverOptions struct {
+	// CloudEventsOptions provides cloudevents clients to receive cloudevents based on different event protocol, the
+	// clients subscribe to the topics of all sources and clusters with the wildcards.
+	CloudEventsOptions CloudEventsOptions
+
+	// ObserverID is a unique identifier for an observer.
+	ObserverID string
+
+	// DiscardedEventHandler is an optional hook to report the received events that are discarded by the client, e.g.
+	// the events that cannot be decoded.
+	DiscardedEventHandler DiscardedEventHandler
+
+	// TenantID is the tenant of the client, if it is set, only the events of the tenant are observed.
+	TenantID string
+
+	// Clock is optional, it is the clock of the reconnect backoff of the client, by default, the real clock is used.
+	Clock clock.WithTickerAndDelayedExecution
 }
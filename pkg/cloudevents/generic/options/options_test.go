@@ -0,0 +1,67 @@
+package options
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSendWithAckTimeout(t *testing.T) {
+	stalled := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	cases := []struct {
+		name          string
+		ctx           func() (context.Context, context.CancelFunc)
+		timeout       time.Duration
+		send          func(ctx context.Context) error
+		expectedError func(err error) bool
+	}{
+		{
+			name:    "acknowledged",
+			ctx:     func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			timeout: time.Second,
+			send:    func(ctx context.Context) error { return nil },
+			expectedError: func(err error) bool {
+				return err == nil
+			},
+		},
+		{
+			name:    "not acknowledged",
+			ctx:     func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			timeout: 10 * time.Millisecond,
+			send:    stalled,
+			expectedError: func(err error) bool {
+				var timeoutErr *PublishTimeoutError
+				return errors.As(err, &timeoutErr) && timeoutErr.Topic == "test" &&
+					errors.Is(err, context.DeadlineExceeded)
+			},
+		},
+		{
+			name: "context deadline exceeded",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			timeout: time.Minute,
+			send:    stalled,
+			expectedError: func(err error) bool {
+				var timeoutErr *PublishTimeoutError
+				return !errors.As(err, &timeoutErr) && errors.Is(err, context.DeadlineExceeded)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := c.ctx()
+			defer cancel()
+
+			if err := SendWithAckTimeout(ctx, c.timeout, "test", c.send); !c.expectedError(err) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
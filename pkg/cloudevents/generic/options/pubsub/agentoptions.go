@@ -0,0 +1,70 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type pubSubAgentOptions struct {
+	PubSubOptions
+	errorChan   chan error
+	clusterName string
+}
+
+// NewAgentOptions returns the agent options of the publish/subscribe service, the agent publishes the events to the
+// AgentEventsTopic and receives the events of its cluster from the Subscription.
+func NewAgentOptions(pubSubOptions *PubSubOptions, clusterName, agentID string) *options.CloudEventsAgentOptions {
+	return &options.CloudEventsAgentOptions{
+		CloudEventsOptions: &pubSubAgentOptions{
+			PubSubOptions: *pubSubOptions,
+			errorChan:     make(chan error),
+			clusterName:   clusterName,
+		},
+		AgentID:     agentID,
+		ClusterName: clusterName,
+	}
+}
+
+func (o *pubSubAgentOptions) WithContext(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+	if _, err := types.ParseCloudEventsType(evtCtx.GetType()); err != nil {
+		return nil, fmt.Errorf("unsupported event type %s, %v", evtCtx.GetType(), err)
+	}
+
+	ctx = withOrderingKey(ctx, eventOrderingKey(evtCtx))
+	return cecontext.WithTopic(ctx, o.AgentEventsTopic), nil
+}
+
+func (o *pubSubAgentOptions) Client(ctx context.Context) (cloudevents.Client, error) {
+	return newCloudEventsClient(&o.PubSubOptions, o.received, func(err error) {
+		o.errorChan <- err
+	})
+}
+
+func (o *pubSubAgentOptions) ErrorChan() <-chan error {
+	return o.errorChan
+}
+
+// Diagnostics returns the topic and the subscription of the agent.
+func (o *pubSubAgentOptions) Diagnostics() options.TransportDiagnostics {
+	return options.TransportDiagnostics{
+		BrokerAddress: o.AgentEventsTopic,
+		Subscriptions: []string{o.Subscription},
+	}
+}
+
+// received returns true if the event is sent to the cluster of the agent or all the clusters.
+func (o *pubSubAgentOptions) received(evt *cloudevents.Event) bool {
+	clusterName, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+	if err != nil {
+		return true
+	}
+
+	return clusterName == types.ClusterAll || clusterName == o.clusterName
+}
@@ -0,0 +1,213 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultGooglePubSubEndpoint is the endpoint of the REST API of the Google Cloud Pub/Sub.
+	DefaultGooglePubSubEndpoint = "https://pubsub.googleapis.com"
+
+	// defaultMaxMessages is the maximum number of the messages that are pulled at a time.
+	defaultMaxMessages = 100
+)
+
+// GooglePubSubClient is a Client of the Google Cloud Pub/Sub, it publishes and pulls the messages with the REST API of
+// the service, so the client library of the service is not a dependency of the sdk.
+//
+// The requests are authorized by the HTTP client, e.g. an HTTP client of the golang.org/x/oauth2/google package with
+// the credentials of a service account that has the roles/pubsub.publisher on the topics and the
+// roles/pubsub.subscriber on the subscription. The ordering keys of the messages take effect only if the message
+// ordering is enabled on the subscriptions.
+type GooglePubSubClient struct {
+	endpoint    string
+	projectID   string
+	httpClient  *http.Client
+	maxMessages int
+	backoff     wait.Backoff
+}
+
+var _ Client = (*GooglePubSubClient)(nil)
+
+// NewGooglePubSubClient returns a Client of the Google Cloud Pub/Sub for the topics and the subscriptions of the
+// project. The endpoint is the DefaultGooglePubSubEndpoint if it is empty, it can be the address of the Pub/Sub
+// emulator, e.g. http://localhost:8085, and the http.DefaultClient is used if the httpClient is nil.
+func NewGooglePubSubClient(endpoint, projectID string, httpClient *http.Client) *GooglePubSubClient {
+	if len(endpoint) == 0 {
+		endpoint = DefaultGooglePubSubEndpoint
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &GooglePubSubClient{
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		projectID:   projectID,
+		httpClient:  httpClient,
+		maxMessages: defaultMaxMessages,
+		backoff: wait.Backoff{
+			Duration: 100 * time.Millisecond,
+			Factor:   2,
+			Jitter:   0.1,
+			Steps:    10,
+			Cap:      30 * time.Second,
+		},
+	}
+}
+
+// googlePubSubMessage is the message of the REST API, its data is base64 encoded in the JSON.
+type googlePubSubMessage struct {
+	Data        []byte            `json:"data,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	OrderingKey string            `json:"orderingKey,omitempty"`
+}
+
+type googlePubSubReceivedMessage struct {
+	AckID   string              `json:"ackId"`
+	Message googlePubSubMessage `json:"message"`
+}
+
+// googlePubSubError is an error response of the REST API.
+type googlePubSubError struct {
+	statusCode int
+	message    string
+}
+
+func (e *googlePubSubError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.statusCode, http.StatusText(e.statusCode), e.message)
+}
+
+// retriable returns true if the request can be retried, e.g. the service is unavailable or the quota is exceeded.
+func (e *googlePubSubError) retriable() bool {
+	return e.statusCode == http.StatusTooManyRequests || e.statusCode >= http.StatusInternalServerError
+}
+
+// Publish publishes the message to the topic and waits for the service to accept it.
+func (c *GooglePubSubClient) Publish(ctx context.Context, topic string, msg *Message) error {
+	request := map[string][]googlePubSubMessage{
+		"messages": {{Data: msg.Data, Attributes: msg.Attributes, OrderingKey: msg.OrderingKey}},
+	}
+
+	if err := c.call(ctx, c.resourceName("topics", topic)+":publish", request, nil); err != nil {
+		return fmt.Errorf("failed to publish the message to the topic %s, %w", topic, err)
+	}
+	return nil
+}
+
+// Receive pulls the messages from the subscription and handles them one by one in the order that they are pulled, a
+// message is acknowledged if the handler returns nil, otherwise its ack deadline is reset for a redelivery. The pulls
+// that fail with a transient error are retried with a backoff, the other errors stop the receiving.
+func (c *GooglePubSubClient) Receive(ctx context.Context, subscription string,
+	handler func(ctx context.Context, msg *Message) error) error {
+	name := c.resourceName("subscriptions", subscription)
+	backoff := c.backoff
+
+	for {
+		response := struct {
+			ReceivedMessages []googlePubSubReceivedMessage `json:"receivedMessages"`
+		}{}
+		err := c.call(ctx, name+":pull", map[string]int{"maxMessages": c.maxMessages}, &response)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			var apiErr *googlePubSubError
+			if errors.As(err, &apiErr) && !apiErr.retriable() {
+				return fmt.Errorf("failed to pull the messages from the subscription %s, %w", subscription, err)
+			}
+
+			klog.Warningf("failed to pull the messages from the subscription %s, retry, %v", subscription, err)
+			select {
+			case <-time.After(backoff.Step()):
+			case <-ctx.Done():
+				return nil
+			}
+			continue
+		}
+		backoff = c.backoff
+
+		for _, received := range response.ReceivedMessages {
+			msg := &Message{
+				Data:        received.Message.Data,
+				Attributes:  received.Message.Attributes,
+				OrderingKey: received.Message.OrderingKey,
+			}
+
+			if ctx.Err() != nil {
+				// the messages that are not handled are redelivered once their ack deadlines expire
+				return nil
+			}
+
+			if handlerErr := handler(ctx, msg); handlerErr != nil {
+				c.settle(ctx, name+":modifyAckDeadline",
+					map[string]any{"ackIds": []string{received.AckID}, "ackDeadlineSeconds": 0})
+				continue
+			}
+
+			c.settle(ctx, name+":acknowledge", map[string]any{"ackIds": []string{received.AckID}})
+		}
+	}
+}
+
+// settle acknowledges a message or resets its ack deadline, the message is redelivered if it fails.
+func (c *GooglePubSubClient) settle(ctx context.Context, method string, request any) {
+	if err := c.call(ctx, method, request, nil); err != nil && ctx.Err() == nil {
+		klog.Warningf("failed to call %s, the message will be redelivered, %v", method, err)
+	}
+}
+
+// resourceName returns the full name of a topic or a subscription, the name that starts with projects/ is already
+// a full name.
+func (c *GooglePubSubClient) resourceName(kind, name string) string {
+	if strings.HasPrefix(name, "projects/") {
+		return name
+	}
+	return fmt.Sprintf("projects/%s/%s/%s", c.projectID, kind, name)
+}
+
+// call posts the request to a method of the REST API and decodes the response.
+func (c *GooglePubSubClient) call(ctx context.Context, method string, request, response any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/v1/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &googlePubSubError{statusCode: resp.StatusCode, message: strings.TrimSpace(string(data))}
+	}
+
+	if response == nil {
+		return nil
+	}
+	return json.Unmarshal(data, response)
+}
@@ -0,0 +1,242 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// fakeGooglePubSub is a fake of the REST API of the Google Cloud Pub/Sub, the pulled messages are returned by the first
+// successful pull, and the pulls fail with the statuses of the pullErrors first. The topics and the subscriptions named
+// unknown are not found.
+type fakeGooglePubSub struct {
+	sync.Mutex
+	requests    map[string][]map[string]any
+	pulled      []googlePubSubReceivedMessage
+	pullErrors  []int
+	pulledCount int
+}
+
+func (s *fakeGooglePubSub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := map[string]any{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.Lock()
+	s.requests[r.URL.Path] = append(s.requests[r.URL.Path], request)
+	response := map[string]any{}
+	status := http.StatusOK
+	switch {
+	case strings.Contains(r.URL.Path, "/unknown:"):
+		status = http.StatusNotFound
+	case strings.HasSuffix(r.URL.Path, ":pull"):
+		switch {
+		case len(s.pullErrors) != 0:
+			status = s.pullErrors[0]
+			s.pullErrors = s.pullErrors[1:]
+		case s.pulledCount == 0:
+			response["receivedMessages"] = s.pulled
+			s.pulledCount++
+		}
+	}
+	s.Unlock()
+
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	if strings.HasSuffix(r.URL.Path, ":pull") && response["receivedMessages"] == nil {
+		// there are no messages, the pull is held until it is canceled
+		<-r.Context().Done()
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *fakeGooglePubSub) get(path string) []map[string]any {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.requests[path]
+}
+
+func TestGooglePubSubPublish(t *testing.T) {
+	service := &fakeGooglePubSub{requests: map[string][]map[string]any{}}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	client := NewGooglePubSubClient(server.URL, "project1", server.Client())
+	if err := client.Publish(context.TODO(), "sourceevents", &Message{
+		Data:        []byte(`{"id":"1"}`),
+		Attributes:  map[string]string{AttributeClusterName: "cluster1"},
+		OrderingKey: "cluster1/test1",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the data is base64 encoded
+	expected := []map[string]any{{"messages": []any{map[string]any{
+		"data":        "eyJpZCI6IjEifQ==",
+		"attributes":  map[string]any{AttributeClusterName: "cluster1"},
+		"orderingKey": "cluster1/test1",
+	}}}}
+	if published := service.get("/v1/projects/project1/topics/sourceevents:publish"); !reflect.DeepEqual(expected, published) {
+		t.Errorf("expected %v, but got %v", expected, published)
+	}
+
+	// the full name of a topic is not prefixed with the project
+	if err := client.Publish(context.TODO(), "projects/project2/topics/agentevents", &Message{}); err != nil {
+		t.Fatal(err)
+	}
+	if published := service.get("/v1/projects/project2/topics/agentevents:publish"); len(published) != 1 {
+		t.Errorf("expected the message is published to the full name, but got %v", published)
+	}
+
+	if err := client.Publish(context.TODO(), "unknown", &Message{}); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+}
+
+func TestGooglePubSubReceive(t *testing.T) {
+	service := &fakeGooglePubSub{
+		requests: map[string][]map[string]any{},
+		pulled: []googlePubSubReceivedMessage{
+			{AckID: "ack1", Message: googlePubSubMessage{Data: []byte("event1"), OrderingKey: "cluster1/test1"}},
+			{AckID: "ack2", Message: googlePubSubMessage{Data: []byte("event2"), OrderingKey: "cluster1/test1"}},
+		},
+		// the unavailable service is retried
+		pullErrors: []int{http.StatusServiceUnavailable},
+	}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	client := NewGooglePubSubClient(server.URL, "project1", server.Client())
+	client.backoff.Duration = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handled := make(chan string, 2)
+	done := make(chan error)
+	go func() {
+		done <- client.Receive(ctx, "agent1", func(ctx context.Context, msg *Message) error {
+			handled <- string(msg.Data)
+			if string(msg.Data) == "event2" {
+				return fmt.Errorf("failed to handle the event")
+			}
+			return nil
+		})
+	}()
+
+	// the messages are handled in order
+	for _, expected := range []string{"event1", "event2"} {
+		select {
+		case data := <-handled:
+			if data != expected {
+				t.Errorf("expected %s, but got %s", expected, data)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected %s is handled", expected)
+		}
+	}
+
+	// the handled message is acknowledged, and the failed message is nacked for a redelivery
+	expectedAcks := []map[string]any{{"ackIds": []any{"ack1"}}}
+	expectedNacks := []map[string]any{{"ackIds": []any{"ack2"}, "ackDeadlineSeconds": float64(0)}}
+	var acks, nacks []map[string]any
+	for i := 0; i < 50; i++ {
+		acks = service.get("/v1/projects/project1/subscriptions/agent1:acknowledge")
+		nacks = service.get("/v1/projects/project1/subscriptions/agent1:modifyAckDeadline")
+		if len(acks) != 0 && len(nacks) != 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !reflect.DeepEqual(expectedAcks, acks) {
+		t.Errorf("expected %v, but got %v", expectedAcks, acks)
+	}
+	if !reflect.DeepEqual(expectedNacks, nacks) {
+		t.Errorf("expected %v, but got %v", expectedNacks, nacks)
+	}
+
+	// the receiving is stopped once the context is done
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected the receiving is stopped")
+	}
+}
+
+func TestGooglePubSubReceiveError(t *testing.T) {
+	service := &fakeGooglePubSub{requests: map[string][]map[string]any{}}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	// the subscription is not found, the receiving is stopped with the error
+	client := NewGooglePubSubClient(server.URL, "project1", server.Client())
+	err := client.Receive(context.TODO(), "unknown", func(ctx context.Context, msg *Message) error {
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected the not found error, but got %v", err)
+	}
+}
+
+func TestGooglePubSubSourceOptions(t *testing.T) {
+	service := &fakeGooglePubSub{requests: map[string][]map[string]any{}}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	sourceOptions := NewSourceOptions(&PubSubOptions{
+		Client:            NewGooglePubSubClient(server.URL, "project1", server.Client()),
+		SourceEventsTopic: "sourceevents",
+		AgentEventsTopic:  "agentevents",
+		Subscription:      "source1-sub",
+	}, "source1")
+
+	// the event is published to the source events topic with the ordering key of the resource
+	evt := newEvent("cluster1", "", "test1")
+	sendingCtx, err := sourceOptions.CloudEventsOptions.WithContext(context.TODO(), evt.Context)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	client, err := sourceOptions.CloudEventsOptions.Client(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result := client.Send(sendingCtx, evt); cloudevents.IsUndelivered(result) {
+		t.Fatalf("unexpected error %v", result)
+	}
+
+	published := service.get("/v1/projects/project1/topics/sourceevents:publish")
+	if len(published) != 1 {
+		t.Fatalf("expected 1 published message, but got %v", published)
+	}
+	message := published[0]["messages"].([]any)[0].(map[string]any)
+	if message["orderingKey"] != "cluster1/test1" {
+		t.Errorf("expected the ordering key cluster1/test1, but got %v", message["orderingKey"])
+	}
+	if attributes := message["attributes"].(map[string]any); attributes[AttributeClusterName] != "cluster1" {
+		t.Errorf("expected the cluster name attribute, but got %v", attributes)
+	}
+}
@@ -0,0 +1,299 @@
+// Package pubsub provides the CloudEventsOptions of the cloud-managed publish/subscribe services, e.g. the Google Cloud
+// Pub/Sub, for the hubs that cannot run a self-managed broker. The service is accessed with a Client, the sdk provides
+// the GooglePubSubClient, which accesses the Google Cloud Pub/Sub with its REST API, so the client libraries are not
+// dependencies of the sdk. The other services, e.g. the AWS SNS/SQS, are accessed with a Client that wraps the client
+// library of the service and is implemented by the callers, e.g. its Publish publishes a message to an SNS topic, and
+// its Receive receives the messages from an SQS queue and deletes a message once the handler returns nil.
+//
+// A received message is acknowledged after its event is handled by the source/agent client, and it is negatively
+// acknowledged for a redelivery if the client is closed before the event is handled. If the client processes the
+// received events with the ReceiveWorkers, the event is handed over once it is queued to the workers.
+//
+// The sources publish the events to the SourceEventsTopic and the agents publish the events to the AgentEventsTopic.
+// Each agent receives the events from its own subscription of the SourceEventsTopic and each source receives the
+// events from its own subscription of the AgentEventsTopic. The subscriptions should be filtered by the
+// AttributeClusterName and the AttributeOriginalSource attributes to save the deliveries, the received events of the
+// other clusters or sources are dropped by the clients anyway.
+//
+// The events are published in the structured content mode with an ordering key of the cluster and the resource, so the
+// events of a resource are delivered in order if the message ordering is enabled on the subscriptions.
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cloudeventsclient "github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	// AttributeClusterName is the message attribute of the cluster name of an event.
+	AttributeClusterName = "clustername"
+
+	// AttributeOriginalSource is the message attribute of the original source of an event.
+	AttributeOriginalSource = "originalsource"
+
+	// AttributeType is the message attribute of the type of an event.
+	AttributeType = "type"
+
+	contentTypeAttribute = "content-type"
+)
+
+// Message is a message of the publish/subscribe service.
+type Message struct {
+	// Data is the payload of the message, it is the JSON of an event.
+	Data []byte
+
+	// Attributes are the attributes of the message, they can be used to filter the messages of a subscription.
+	Attributes map[string]string
+
+	// OrderingKey is the key of the message ordering, the messages with the same key are delivered in order.
+	OrderingKey string
+}
+
+// Client publishes and receives the messages of the publish/subscribe service, it is implemented with the client
+// library of the service, e.g. the Publish of a Google Cloud Pub/Sub client publishes a pubsub.Message to the topic
+// and waits for the result, and the Receive receives the messages from the subscription and acknowledges a message
+// once the handler returns nil.
+type Client interface {
+	// Publish publishes a message to the topic, it returns once the message is accepted by the service.
+	Publish(ctx context.Context, topic string, msg *Message) error
+
+	// Receive receives the messages from the subscription until the context is done or an unrecoverable error occurs,
+	// a message is negatively acknowledged for a redelivery if the handler returns an error.
+	Receive(ctx context.Context, subscription string, handler func(ctx context.Context, msg *Message) error) error
+}
+
+// PubSubOptions holds the options that are used to build a publish/subscribe client.
+type PubSubOptions struct {
+	// Client accesses the publish/subscribe service.
+	Client Client
+
+	// SourceEventsTopic is the topic of the events that are published by the sources.
+	SourceEventsTopic string
+
+	// AgentEventsTopic is the topic of the events that are published by the agents.
+	AgentEventsTopic string
+
+	// Subscription is the subscription that the client receives the events from, it is a subscription of the
+	// AgentEventsTopic for a source and a subscription of the SourceEventsTopic for an agent.
+	Subscription string
+}
+
+// Validate checks the options are complete.
+func (o *PubSubOptions) Validate() error {
+	if o.Client == nil {
+		return fmt.Errorf("the pubsub client is required")
+	}
+
+	if len(o.SourceEventsTopic) == 0 || len(o.AgentEventsTopic) == 0 {
+		return fmt.Errorf("the source events topic and the agent events topic are required")
+	}
+
+	if len(o.Subscription) == 0 {
+		return fmt.Errorf("the subscription is required")
+	}
+
+	return nil
+}
+
+type orderingKey struct{}
+
+// withOrderingKey returns back a new context with the ordering key of the published message.
+func withOrderingKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, orderingKey{}, key)
+}
+
+// eventOrderingKey returns the ordering key of an event, the events of a resource on a cluster have the same key and
+// the events without a resource, e.g. the resync requests, have the key of their cluster.
+func eventOrderingKey(evtCtx cloudevents.EventContext) string {
+	clusterName, _ := cloudeventstypes.ToString(evtCtx.GetExtensions()[types.ExtensionClusterName])
+	resourceID, _ := cloudeventstypes.ToString(evtCtx.GetExtensions()[types.ExtensionResourceID])
+	if len(resourceID) == 0 {
+		return clusterName
+	}
+
+	return clusterName + "/" + resourceID
+}
+
+// filter returns true if the received event should be handled by the client.
+type filter func(evt *cloudevents.Event) bool
+
+// receivedMessage is a received event whose message is acknowledged once the event is handled, the Finish of the
+// message is called by the cloudevents client with the result of the handling.
+type receivedMessage struct {
+	*binding.EventMessage
+	finished chan error
+}
+
+// Finish implements binding.Message.Finish
+func (m *receivedMessage) Finish(err error) error {
+	m.finished <- err
+	return nil
+}
+
+// GetWrappedMessage implements binding.MessageWrapper, so the event of the message is read without a conversion.
+func (m *receivedMessage) GetWrappedMessage() binding.Message {
+	return m.EventMessage
+}
+
+// pubSubProtocol is a cloudevents protocol of the publish/subscribe service.
+type pubSubProtocol struct {
+	client       Client
+	subscription string
+	filter       filter
+	errorHandler func(error)
+
+	incoming  chan *receivedMessage
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+var (
+	_ protocol.Sender   = (*pubSubProtocol)(nil)
+	_ protocol.Opener   = (*pubSubProtocol)(nil)
+	_ protocol.Receiver = (*pubSubProtocol)(nil)
+	_ protocol.Closer   = (*pubSubProtocol)(nil)
+)
+
+func newCloudEventsClient(
+	pubSubOptions *PubSubOptions, filter filter, errorHandler func(error)) (cloudevents.Client, error) {
+	if err := pubSubOptions.Validate(); err != nil {
+		return nil, err
+	}
+
+	p := &pubSubProtocol{
+		client:       pubSubOptions.Client,
+		subscription: pubSubOptions.Subscription,
+		filter:       filter,
+		errorHandler: errorHandler,
+		incoming:     make(chan *receivedMessage),
+		closeChan:    make(chan struct{}),
+	}
+
+	return cloudevents.NewClient(p, cloudeventsclient.WithBlockingCallback())
+}
+
+// Send publishes the event as a structured message to the topic of the context.
+func (p *pubSubProtocol) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	defer func() {
+		err = m.Finish(err)
+	}()
+
+	evt, err := binding.ToEvent(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+
+	data, err := evt.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshal the event %s, %v", evt.ID(), err)
+	}
+
+	attributes := map[string]string{
+		AttributeType:        evt.Type(),
+		contentTypeAttribute: cloudevents.ApplicationCloudEventsJSON,
+	}
+	for name, key := range map[string]string{
+		AttributeClusterName:    types.ExtensionClusterName,
+		AttributeOriginalSource: types.ExtensionOriginalSource,
+	} {
+		if value, err := cloudeventstypes.ToString(evt.Extensions()[key]); err == nil && len(value) != 0 {
+			attributes[name] = value
+		}
+	}
+
+	key, _ := ctx.Value(orderingKey{}).(string)
+	return p.client.Publish(ctx, cecontext.TopicFrom(ctx), &Message{
+		Data:        data,
+		Attributes:  attributes,
+		OrderingKey: key,
+	})
+}
+
+// OpenInbound receives the messages from the subscription until the context is done or the protocol is closed, the
+// error handler is called if the receiving is stopped by an error.
+func (p *pubSubProtocol) OpenInbound(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-p.closeChan:
+			cancel()
+		}
+	}()
+
+	err := p.client.Receive(ctx, p.subscription, func(ctx context.Context, msg *Message) error {
+		evt := cloudevents.NewEvent()
+		if err := evt.UnmarshalJSON(msg.Data); err != nil {
+			// the message is not an event, it is dropped without a redelivery
+			cecontext.LoggerFrom(ctx).Warnf("failed to unmarshal the message, %v", err)
+			return nil
+		}
+
+		if p.filter != nil && !p.filter(&evt) {
+			return nil
+		}
+
+		received := &receivedMessage{
+			EventMessage: (*binding.EventMessage)(&evt),
+			finished:     make(chan error, 1),
+		}
+		select {
+		case p.incoming <- received:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		// the message is acknowledged after the event is handled, so it is redelivered if the client is crashed or
+		// closed before the event is handled
+		select {
+		case err := <-received.finished:
+			if !protocol.IsACK(err) {
+				return err
+			}
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	if err != nil && ctx.Err() == nil {
+		if p.errorHandler != nil {
+			p.errorHandler(err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Receive implements Receiver.Receive
+func (p *pubSubProtocol) Receive(ctx context.Context) (binding.Message, error) {
+	select {
+	case received := <-p.incoming:
+		return received, nil
+	case <-p.closeChan:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// Close implements Closer.Close
+func (p *pubSubProtocol) Close(ctx context.Context) error {
+	p.closeOnce.Do(func() {
+		close(p.closeChan)
+	})
+	return nil
+}
@@ -0,0 +1,279 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+var mockEventDataType = types.CloudEventsDataType{
+	Group:    "resources.test",
+	Version:  "v1",
+	Resource: "mockresources",
+}
+
+// fakeService is an in-memory publish/subscribe service, each subscription receives the messages of one topic.
+type fakeService struct {
+	sync.Mutex
+	subscriptions map[string]string
+	published     map[string][]*Message
+	receiveErr    error
+	// results receives the handling results of the received messages if it is set, a nil result acks the message
+	results chan error
+}
+
+func newFakeService(subscriptions map[string]string) *fakeService {
+	return &fakeService{subscriptions: subscriptions, published: map[string][]*Message{}}
+}
+
+func (s *fakeService) Publish(ctx context.Context, topic string, msg *Message) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.published[topic] = append(s.published[topic], msg)
+	return nil
+}
+
+func (s *fakeService) Receive(ctx context.Context, subscription string,
+	handler func(ctx context.Context, msg *Message) error) error {
+	s.Lock()
+	topic, ok := s.subscriptions[subscription]
+	messages := s.published[topic]
+	receiveErr := s.receiveErr
+	s.Unlock()
+
+	if !ok {
+		return fmt.Errorf("the subscription %s is not found", subscription)
+	}
+
+	for _, msg := range messages {
+		err := handler(ctx, msg)
+		if s.results != nil {
+			s.results <- err
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if receiveErr != nil {
+		return receiveErr
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+func newEvent(clusterName, originalSource, resourceID string) cloudevents.Event {
+	builder := types.NewEventBuilder("test", types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}).WithClusterName(clusterName).WithOriginalSource(originalSource)
+	if len(resourceID) != 0 {
+		builder = builder.WithResourceID(resourceID)
+	}
+	return builder.NewEvent()
+}
+
+func TestAgentOptions(t *testing.T) {
+	service := newFakeService(map[string]string{"cluster1-sub": "sourceevents"})
+	pubSubOptions := &PubSubOptions{
+		Client:            service,
+		SourceEventsTopic: "sourceevents",
+		AgentEventsTopic:  "agentevents",
+		Subscription:      "cluster1-sub",
+	}
+
+	// the events of the cluster1 and all the clusters are received
+	for _, clusterName := range []string{"cluster1", "cluster2", types.ClusterAll} {
+		evt := newEvent(clusterName, "source1", "")
+		data, err := evt.MarshalJSON()
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if err := service.Publish(context.TODO(), "sourceevents", &Message{Data: data}); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	agentOptions := NewAgentOptions(pubSubOptions, "cluster1", "cluster1-agent")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := agentOptions.CloudEventsOptions.Client(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	received := make(chan cloudevents.Event, 3)
+	go func() {
+		_ = client.StartReceiver(ctx, func(evt cloudevents.Event) {
+			received <- evt
+		})
+	}()
+
+	for _, expected := range []string{"cluster1", types.ClusterAll} {
+		select {
+		case evt := <-received:
+			if clusterName := evt.Extensions()[types.ExtensionClusterName]; clusterName != expected {
+				t.Errorf("expected %q, but got %v", expected, clusterName)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout to receive the event of %q", expected)
+		}
+	}
+
+	// the agent publishes the events to the agent events topic with the ordering key of the resource
+	evt := newEvent("cluster1", "source1", "test1")
+	sendingCtx, err := agentOptions.CloudEventsOptions.WithContext(ctx, evt.Context)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result := client.Send(sendingCtx, evt); cloudevents.IsUndelivered(result) {
+		t.Fatalf("unexpected error %v", result)
+	}
+
+	published := service.published["agentevents"]
+	if len(published) != 1 {
+		t.Fatalf("expected 1 published message, but got %v", published)
+	}
+	if published[0].OrderingKey != "cluster1/test1" {
+		t.Errorf("expected cluster1/test1, but got %s", published[0].OrderingKey)
+	}
+	if published[0].Attributes[AttributeClusterName] != "cluster1" ||
+		published[0].Attributes[AttributeOriginalSource] != "source1" {
+		t.Errorf("unexpected attributes %v", published[0].Attributes)
+	}
+}
+
+func TestSourceOptions(t *testing.T) {
+	service := newFakeService(map[string]string{"source1-sub": "agentevents"})
+	service.receiveErr = fmt.Errorf("subscription deleted")
+	pubSubOptions := &PubSubOptions{
+		Client:            service,
+		SourceEventsTopic: "sourceevents",
+		AgentEventsTopic:  "agentevents",
+		Subscription:      "source1-sub",
+	}
+
+	sourceOptions := NewSourceOptions(pubSubOptions, "source1")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the resync request without a resource has the ordering key of the cluster
+	evt := newEvent("cluster1", "", "")
+	sendingCtx, err := sourceOptions.CloudEventsOptions.WithContext(ctx, evt.Context)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	client, err := sourceOptions.CloudEventsOptions.Client(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result := client.Send(sendingCtx, evt); cloudevents.IsUndelivered(result) {
+		t.Fatalf("unexpected error %v", result)
+	}
+	if published := service.published["sourceevents"]; len(published) != 1 || published[0].OrderingKey != "cluster1" {
+		t.Errorf("unexpected published messages %v", published)
+	}
+
+	// the receiving error is reported to reconnect
+	go func() {
+		_ = client.StartReceiver(ctx, func(evt cloudevents.Event) {})
+	}()
+
+	select {
+	case err := <-sourceOptions.CloudEventsOptions.ErrorChan():
+		if err.Error() != "subscription deleted" {
+			t.Errorf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout to receive the error")
+	}
+
+	if err := (&PubSubOptions{Client: service}).Validate(); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+}
+
+func TestReceiveAcknowledge(t *testing.T) {
+	service := newFakeService(map[string]string{"cluster1-sub": "sourceevents"})
+	service.results = make(chan error, 2)
+	pubSubOptions := &PubSubOptions{
+		Client:            service,
+		SourceEventsTopic: "sourceevents",
+		AgentEventsTopic:  "agentevents",
+		Subscription:      "cluster1-sub",
+	}
+
+	for _, resourceID := range []string{"test1", "test2"} {
+		evt := newEvent("cluster1", "source1", resourceID)
+		data, err := evt.MarshalJSON()
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if err := service.Publish(context.TODO(), "sourceevents", &Message{Data: data}); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	agentOptions := NewAgentOptions(pubSubOptions, "cluster1", "cluster1-agent")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := agentOptions.CloudEventsOptions.Client(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	receiverCtx, stopReceiver := context.WithCancel(ctx)
+	defer stopReceiver()
+
+	handling := make(chan struct{})
+	handled := make(chan struct{})
+	go func() {
+		_ = client.StartReceiver(receiverCtx, func(evt cloudevents.Event) {
+			handling <- struct{}{}
+			<-handled
+		})
+	}()
+
+	// the first message is not acknowledged until its event is handled
+	<-handling
+	select {
+	case err := <-service.results:
+		t.Fatalf("unexpected result %v before the event is handled", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	handled <- struct{}{}
+	select {
+	case err := <-service.results:
+		if err != nil {
+			t.Errorf("expected the message is acked, but got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout to ack the message")
+	}
+
+	// the second message is nacked if the receiver is stopped before its event is handled
+	<-handling
+	stopReceiver()
+	select {
+	case err := <-service.results:
+		if err == nil {
+			t.Errorf("expected the message is nacked, but got nil")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout to nack the message")
+	}
+	close(handled)
+}
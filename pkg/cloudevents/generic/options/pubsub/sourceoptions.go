@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type pubSubSourceOptions struct {
+	PubSubOptions
+	errorChan chan error
+	sourceID  string
+}
+
+// NewSourceOptions returns the source options of the publish/subscribe service, the source publishes the events to the
+// SourceEventsTopic and receives the events that are sent to it or to all the sources from the Subscription.
+func NewSourceOptions(pubSubOptions *PubSubOptions, sourceID string) *options.CloudEventsSourceOptions {
+	return &options.CloudEventsSourceOptions{
+		CloudEventsOptions: &pubSubSourceOptions{
+			PubSubOptions: *pubSubOptions,
+			errorChan:     make(chan error),
+			sourceID:      sourceID,
+		},
+		SourceID: sourceID,
+	}
+}
+
+func (o *pubSubSourceOptions) WithContext(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+	if _, err := types.ParseCloudEventsType(evtCtx.GetType()); err != nil {
+		return nil, fmt.Errorf("unsupported event type %s, %v", evtCtx.GetType(), err)
+	}
+
+	ctx = withOrderingKey(ctx, eventOrderingKey(evtCtx))
+	return cecontext.WithTopic(ctx, o.SourceEventsTopic), nil
+}
+
+func (o *pubSubSourceOptions) Client(ctx context.Context) (cloudevents.Client, error) {
+	return newCloudEventsClient(&o.PubSubOptions, o.received, func(err error) {
+		o.errorChan <- err
+	})
+}
+
+func (o *pubSubSourceOptions) ErrorChan() <-chan error {
+	return o.errorChan
+}
+
+// Diagnostics returns the topic and the subscription of the source.
+func (o *pubSubSourceOptions) Diagnostics() options.TransportDiagnostics {
+	return options.TransportDiagnostics{
+		BrokerAddress: o.SourceEventsTopic,
+		Subscriptions: []string{o.Subscription},
+	}
+}
+
+// received returns true if the event is sent to the source or all the sources.
+func (o *pubSubSourceOptions) received(evt *cloudevents.Event) bool {
+	originalSource, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionOriginalSource])
+	if err != nil {
+		return true
+	}
+
+	return originalSource == types.SourceAll || originalSource == o.sourceID
+}
@@ -0,0 +1,57 @@
+package recorder
+
+import (
+	"fmt"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// EventMatcher returns true if the recorded event is matched.
+type EventMatcher func(evt RecordedEvent) bool
+
+// All returns a matcher that matches the events which are matched by all the given matchers.
+func All(matchers ...EventMatcher) EventMatcher {
+	return func(evt RecordedEvent) bool {
+		for _, matcher := range matchers {
+			if !matcher(evt) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Sent matches the events that are sent by the client.
+func Sent() EventMatcher {
+	return func(evt RecordedEvent) bool {
+		return evt.Direction == DirectionSent
+	}
+}
+
+// Received matches the events that are received by the client.
+func Received() EventMatcher {
+	return func(evt RecordedEvent) bool {
+		return evt.Direction == DirectionReceived
+	}
+}
+
+// Extension matches the events whose extension has the given value.
+func Extension(name string, value any) EventMatcher {
+	return func(evt RecordedEvent) bool {
+		val, ok := evt.Event.Extensions()[name]
+		if !ok {
+			return false
+		}
+		return fmt.Sprintf("%v", val) == fmt.Sprintf("%v", value)
+	}
+}
+
+// ResourceID matches the events whose resourceid extension is the given resource ID.
+func ResourceID(resourceID string) EventMatcher {
+	return Extension(types.ExtensionResourceID, resourceID)
+}
+
+// ClusterName matches the events whose clustername extension is the given cluster name.
+func ClusterName(clusterName string) EventMatcher {
+	return Extension(types.ExtensionClusterName, clusterName)
+}
@@ -0,0 +1,232 @@
+// Package recorder provides a CloudEventsOptions wrapper that records the published and received cloudevents, so the
+// source/agent logic that is built on the cloudevents clients can be tested deterministically, e.g.
+//
+//	rec := recorder.NewRecorder(sourceOptions.CloudEventsOptions)
+//	sourceOptions.CloudEventsOptions = rec
+//	client, err := generic.NewCloudEventSourceClient(ctx, sourceOptions, lister, statusHash, codec)
+//	...
+//	rec.ExpectEventually(t, createRequest, recorder.All(recorder.Sent(), recorder.ResourceID("test")))
+package recorder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// DefaultTimeout is the default timeout to wait for an expected event.
+const DefaultTimeout = 10 * time.Second
+
+// Direction is the direction of a recorded event.
+type Direction string
+
+const (
+	// DirectionSent is the direction of the events that are published by the client.
+	DirectionSent Direction = "sent"
+
+	// DirectionReceived is the direction of the events that are received by the client.
+	DirectionReceived Direction = "received"
+)
+
+// RecordedEvent is a cloudevent that is sent or received by the client.
+type RecordedEvent struct {
+	Direction Direction
+	Event     cloudevents.Event
+}
+
+// Recorder wraps a CloudEventsOptions and records all the events that are sent or received by its clients, the events
+// that fail to be delivered are not recorded.
+type Recorder struct {
+	options.CloudEventsOptions
+
+	mu      sync.Mutex
+	events  []RecordedEvent
+	updated chan struct{}
+	timeout time.Duration
+}
+
+var _ options.CloudEventsOptions = &Recorder{}
+
+// NewRecorder returns a Recorder that records the events of the given cloudevents options.
+func NewRecorder(cloudEventsOptions options.CloudEventsOptions) *Recorder {
+	return &Recorder{
+		CloudEventsOptions: cloudEventsOptions,
+		events:             []RecordedEvent{},
+		updated:            make(chan struct{}),
+		timeout:            DefaultTimeout,
+	}
+}
+
+// WithTimeout sets the timeout to wait for an expected event.
+func (r *Recorder) WithTimeout(timeout time.Duration) *Recorder {
+	r.timeout = timeout
+	return r
+}
+
+// Client returns a cloudevents client that records the events that are sent or received by the client of the wrapped
+// cloudevents options.
+func (r *Recorder) Client(ctx context.Context) (cloudevents.Client, error) {
+	client, err := r.CloudEventsOptions.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingClient{Client: client, recorder: r}, nil
+}
+
+// Events returns a copy of all the recorded events in the order they are recorded.
+func (r *Recorder) Events() []RecordedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]RecordedEvent, 0, len(r.events))
+	for _, evt := range r.events {
+		events = append(events, RecordedEvent{Direction: evt.Direction, Event: evt.Event.Clone()})
+	}
+	return events
+}
+
+// SentEvents returns the recorded events that are sent by the client.
+func (r *Recorder) SentEvents() []cloudevents.Event {
+	return r.filter(DirectionSent)
+}
+
+// ReceivedEvents returns the recorded events that are received by the client.
+func (r *Recorder) ReceivedEvents() []cloudevents.Event {
+	return r.filter(DirectionReceived)
+}
+
+// Reset clears the recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = []RecordedEvent{}
+}
+
+// WaitFor waits until an event of the given event type matches the matcher and returns the event, it returns an error
+// if there is no such event when the context is done. A nil matcher matches all the events of the event type.
+func (r *Recorder) WaitFor(ctx context.Context, eventType types.CloudEventsType,
+	matcher EventMatcher) (*RecordedEvent, error) {
+	for {
+		r.mu.Lock()
+		updated := r.updated
+		evt := r.find(eventType, matcher)
+		r.mu.Unlock()
+
+		if evt != nil {
+			return evt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to find the event %s, %v", eventType, ctx.Err())
+		case <-updated:
+		}
+	}
+}
+
+// ExpectEventually fails the test if an event of the given event type that matches the matcher is not recorded before
+// the timeout of the recorder, a nil matcher matches all the events of the event type.
+func (r *Recorder) ExpectEventually(t testing.TB, eventType types.CloudEventsType,
+	matcher EventMatcher) *RecordedEvent {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	evt, err := r.WaitFor(ctx, eventType, matcher)
+	if err != nil {
+		t.Fatalf("expected event %s, but got %v: %v", eventType, r.eventTypes(), err)
+	}
+	return evt
+}
+
+func (r *Recorder) record(direction Direction, evt cloudevents.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, RecordedEvent{Direction: direction, Event: evt.Clone()})
+
+	// wake up the waiters
+	close(r.updated)
+	r.updated = make(chan struct{})
+}
+
+func (r *Recorder) find(eventType types.CloudEventsType, matcher EventMatcher) *RecordedEvent {
+	for _, evt := range r.events {
+		if evt.Event.Type() != eventType.String() {
+			continue
+		}
+
+		if matcher != nil && !matcher(evt) {
+			continue
+		}
+
+		return &RecordedEvent{Direction: evt.Direction, Event: evt.Event.Clone()}
+	}
+	return nil
+}
+
+func (r *Recorder) filter(direction Direction) []cloudevents.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []cloudevents.Event{}
+	for _, evt := range r.events {
+		if evt.Direction == direction {
+			events = append(events, evt.Event.Clone())
+		}
+	}
+	return events
+}
+
+func (r *Recorder) eventTypes() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	eventTypes := []string{}
+	for _, evt := range r.events {
+		eventTypes = append(eventTypes, fmt.Sprintf("%s(%s)", evt.Event.Type(), evt.Direction))
+	}
+	return eventTypes
+}
+
+// recordingClient is a cloudevents client that records the sent and received events to the recorder.
+type recordingClient struct {
+	cloudevents.Client
+	recorder *Recorder
+}
+
+func (c *recordingClient) Send(ctx context.Context, evt cloudevents.Event) protocol.Result {
+	result := c.Client.Send(ctx, evt)
+	if !cloudevents.IsUndelivered(result) {
+		c.recorder.record(DirectionSent, evt)
+	}
+	return result
+}
+
+func (c *recordingClient) StartReceiver(ctx context.Context, fn interface{}) error {
+	switch receiver := fn.(type) {
+	case func(evt cloudevents.Event):
+		return c.Client.StartReceiver(ctx, func(evt cloudevents.Event) {
+			c.recorder.record(DirectionReceived, evt)
+			receiver(evt)
+		})
+	case func(ctx context.Context, evt cloudevents.Event):
+		return c.Client.StartReceiver(ctx, func(ctx context.Context, evt cloudevents.Event) {
+			c.recorder.record(DirectionReceived, evt)
+			receiver(ctx, evt)
+		})
+	default:
+		return fmt.Errorf("unsupported receiver %T", fn)
+	}
+}
@@ -0,0 +1,107 @@
+package recorder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+var (
+	testDataType = types.CloudEventsDataType{Group: "test", Version: "v1", Resource: "tests"}
+
+	createRequest = types.CloudEventsType{
+		CloudEventsDataType: testDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}
+
+	updateRequest = types.CloudEventsType{
+		CloudEventsDataType: testDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "update_request",
+	}
+)
+
+func TestRecorder(t *testing.T) {
+	received := newEvent("source1", updateRequest, "test1")
+	rec := NewRecorder(fake.NewSourceOptions(fake.NewCloudEventsFakeClient(received), "source1").CloudEventsOptions)
+
+	client, err := rec.Client(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	handled := []string{}
+	if err := client.StartReceiver(context.Background(), func(evt cloudevents.Event) {
+		handled = append(handled, evt.ID())
+	}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(handled) != 1 {
+		t.Errorf("expected 1 handled event, but got %v", handled)
+	}
+
+	if result := client.Send(context.Background(), newEvent("source1", createRequest, "test2")); result != nil {
+		t.Fatalf("unexpected error %v", result)
+	}
+
+	rec.ExpectEventually(t, updateRequest, All(Received(), ResourceID("test1")))
+	rec.ExpectEventually(t, createRequest, All(Sent(), ResourceID("test2"), ClusterName("cluster1")))
+
+	if len(rec.SentEvents()) != 1 {
+		t.Errorf("expected 1 sent event, but got %d", len(rec.SentEvents()))
+	}
+	if len(rec.ReceivedEvents()) != 1 {
+		t.Errorf("expected 1 received event, but got %d", len(rec.ReceivedEvents()))
+	}
+
+	rec.Reset()
+	if len(rec.Events()) != 0 {
+		t.Errorf("expected no events, but got %d", len(rec.Events()))
+	}
+}
+
+func TestRecorderWaitFor(t *testing.T) {
+	rec := NewRecorder(fake.NewSourceOptions(fake.NewCloudEventsFakeClient(), "source1").CloudEventsOptions)
+
+	client, err := rec.Client(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		client.Send(context.Background(), newEvent("source1", createRequest, "test1"))
+		client.Send(context.Background(), newEvent("source1", createRequest, "test2"))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	evt, err := rec.WaitFor(ctx, createRequest, ResourceID("test2"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if evt.Direction != DirectionSent {
+		t.Errorf("expected %s, but got %s", DirectionSent, evt.Direction)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := rec.WaitFor(ctx, updateRequest, nil); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+}
+
+func newEvent(source string, eventType types.CloudEventsType, resourceID string) cloudevents.Event {
+	return types.NewEventBuilder(source, eventType).
+		WithResourceID(resourceID).
+		WithResourceVersion(1).
+		WithClusterName("cluster1").
+		NewEvent()
+}
@@ -0,0 +1,64 @@
+package options
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// ReloadableOptions is implemented by the CloudEventsOptions whose config can be reloaded at runtime.
+type ReloadableOptions interface {
+	// Reload rebuilds the options from the given config file, the cloudevents client should be reconnected with the
+	// new options.
+	Reload(configPath string) error
+}
+
+// ReloadCallback is called after the config file is changed and the options are reloaded, the err is nil if the
+// options are reloaded successfully.
+type ReloadCallback func(configPath string, err error)
+
+// WatchConfigFile checks the given config file periodically until the context is done, if the file content is changed,
+// the options will be reloaded with the file and the callback will be called with the reloading result. The callback
+// is optional.
+func WatchConfigFile(ctx context.Context, configPath string, interval time.Duration,
+	cloudEventsOptions CloudEventsOptions, callback ReloadCallback) error {
+	reloadable, ok := cloudEventsOptions.(ReloadableOptions)
+	if !ok {
+		return fmt.Errorf("the options %T cannot be reloaded", cloudEventsOptions)
+	}
+
+	lastConfigData, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		configData, err := os.ReadFile(configPath)
+		if err != nil {
+			klog.Warningf("failed to read config file %s, %v", configPath, err)
+			return
+		}
+
+		if bytes.Equal(configData, lastConfigData) {
+			return
+		}
+		lastConfigData = configData
+
+		klog.Infof("the config file %s is changed, reload the options", configPath)
+		err = reloadable.Reload(configPath)
+		if err != nil {
+			klog.Errorf("failed to reload the options from %s, %v", configPath, err)
+		}
+
+		if callback != nil {
+			callback(configPath, err)
+		}
+	}, interval)
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+)
+
+// The keys of the credentials in the secret.
+const (
+	UsernameKey   = "username"
+	PasswordKey   = "password"
+	TokenKey      = "token"
+	CAKey         = "ca.crt"
+	ClientCertKey = "tls.crt"
+	ClientKeyKey  = "tls.key"
+)
+
+const configFileName = "config.yaml"
+
+// CredentialLoader loads the transport options with the credentials in a Kubernetes secret.
+//
+// The loader writes the TLS material and the token of the secret to a local directory, and generates a config file in
+// the directory with the given base config file and the credentials, then the transport options are built from the
+// generated config file. The loader can keep the credentials refreshed with the secret, once the secret is changed,
+// the files are rewritten and the options are reloaded.
+type CredentialLoader struct {
+	sync.Mutex
+	secretClient        corev1client.SecretsGetter
+	namespace           string
+	name                string
+	baseConfigPath      string
+	dir                 string
+	render              func(baseConfigData []byte, secret *corev1.Secret) (any, error)
+	lastResourceVersion string
+}
+
+// NewCredentialLoader returns a CredentialLoader for the secret with the given namespace and name. The baseConfigPath
+// is the config file that has the non-credential fields, e.g. the broker host, and the dir is used to save the
+// credentials and the generated config file.
+func NewCredentialLoader(secretClient corev1client.SecretsGetter,
+	namespace, name, baseConfigPath, dir string) *CredentialLoader {
+	return &CredentialLoader{
+		secretClient:   secretClient,
+		namespace:      namespace,
+		name:           name,
+		baseConfigPath: baseConfigPath,
+		dir:            dir,
+	}
+}
+
+// ConfigPath returns the path of the config file that is generated by the loader.
+func (l *CredentialLoader) ConfigPath() string {
+	return filepath.Join(l.dir, configFileName)
+}
+
+// LoadMQTTOptions loads the MQTT options, the username, password and TLS material are from the secret.
+func (l *CredentialLoader) LoadMQTTOptions(ctx context.Context) (*mqtt.MQTTOptions, error) {
+	l.Lock()
+	l.render = l.renderMQTTConfig
+	l.Unlock()
+
+	if _, err := l.sync(ctx); err != nil {
+		return nil, err
+	}
+
+	return mqtt.BuildMQTTOptionsFromFlags(l.ConfigPath())
+}
+
+// LoadGRPCOptions loads the gRPC options, the token and TLS material are from the secret.
+func (l *CredentialLoader) LoadGRPCOptions(ctx context.Context) (*grpc.GRPCOptions, error) {
+	l.Lock()
+	l.render = l.renderGRPCConfig
+	l.Unlock()
+
+	if _, err := l.sync(ctx); err != nil {
+		return nil, err
+	}
+
+	return grpc.BuildGRPCOptionsFromFlags(l.ConfigPath())
+}
+
+// Run checks the secret periodically until the context is done, once the secret is changed, the credentials are
+// refreshed and the given options are reloaded with the generated config file. The callback is optional.
+func (l *CredentialLoader) Run(ctx context.Context, interval time.Duration,
+	cloudEventsOptions options.CloudEventsOptions, callback options.ReloadCallback) error {
+	reloadable, ok := cloudEventsOptions.(options.ReloadableOptions)
+	if !ok {
+		return fmt.Errorf("the options %T cannot be reloaded", cloudEventsOptions)
+	}
+
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		changed, err := l.sync(ctx)
+		if err != nil {
+			klog.Errorf("failed to refresh the credentials from secret %s/%s, %v", l.namespace, l.name, err)
+		}
+
+		if !changed && err == nil {
+			return
+		}
+
+		if err == nil {
+			klog.Infof("the secret %s/%s is changed, reload the options", l.namespace, l.name)
+			err = reloadable.Reload(l.ConfigPath())
+		}
+
+		if callback != nil {
+			callback(l.ConfigPath(), err)
+		}
+	}, interval)
+
+	return nil
+}
+
+// sync writes the credentials and the config file with the current secret, it returns true if the secret is changed.
+func (l *CredentialLoader) sync(ctx context.Context) (bool, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.render == nil {
+		return false, fmt.Errorf("the transport options are not loaded")
+	}
+
+	secret, err := l.secretClient.Secrets(l.namespace).Get(ctx, l.name, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get secret %s/%s, %v", l.namespace, l.name, err)
+	}
+
+	if secret.ResourceVersion == l.lastResourceVersion {
+		return false, nil
+	}
+
+	baseConfigData, err := os.ReadFile(l.baseConfigPath)
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.MkdirAll(l.dir, 0700); err != nil {
+		return false, err
+	}
+
+	for _, key := range []string{TokenKey, CAKey, ClientCertKey, ClientKeyKey} {
+		data, ok := secret.Data[key]
+		if !ok {
+			continue
+		}
+
+		if err := writeFile(filepath.Join(l.dir, key), data); err != nil {
+			return false, err
+		}
+	}
+
+	config, err := l.render(baseConfigData, secret)
+	if err != nil {
+		return false, err
+	}
+
+	configData, err := yaml.Marshal(config)
+	if err != nil {
+		return false, err
+	}
+
+	if err := writeFile(l.ConfigPath(), configData); err != nil {
+		return false, err
+	}
+
+	l.lastResourceVersion = secret.ResourceVersion
+	return true, nil
+}
+
+func (l *CredentialLoader) renderMQTTConfig(baseConfigData []byte, secret *corev1.Secret) (any, error) {
+	config := &mqtt.MQTTConfig{}
+	if err := yaml.Unmarshal(baseConfigData, config); err != nil {
+		return nil, err
+	}
+
+	if username, ok := secret.Data[UsernameKey]; ok {
+		config.Username = string(username)
+	}
+
+	if password, ok := secret.Data[PasswordKey]; ok {
+		config.Password = string(password)
+	}
+
+	l.setFile(secret, CAKey, &config.CAFile)
+	l.setFile(secret, ClientCertKey, &config.ClientCertFile)
+	l.setFile(secret, ClientKeyKey, &config.ClientKeyFile)
+	return config, nil
+}
+
+func (l *CredentialLoader) renderGRPCConfig(baseConfigData []byte, secret *corev1.Secret) (any, error) {
+	config := &grpc.GRPCConfig{}
+	if err := yaml.Unmarshal(baseConfigData, config); err != nil {
+		return nil, err
+	}
+
+	l.setFile(secret, TokenKey, &config.TokenFile)
+	l.setFile(secret, CAKey, &config.CAFile)
+	l.setFile(secret, ClientCertKey, &config.ClientCertFile)
+	l.setFile(secret, ClientKeyKey, &config.ClientKeyFile)
+	return config, nil
+}
+
+// setFile sets the config field with the file path of the secret key if the secret has the key.
+func (l *CredentialLoader) setFile(secret *corev1.Secret, key string, field *string) {
+	if _, ok := secret.Data[key]; ok {
+		*field = filepath.Join(l.dir, key)
+	}
+}
+
+// writeFile replaces the file atomically, so the file is not read partially when it is being written.
+func writeFile(name string, data []byte) error {
+	tmpFile := name + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile, name)
+}
@@ -0,0 +1,128 @@
+package secret
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+)
+
+const testMQTTConfig = `
+brokerHost: test
+topics:
+  sourceEvents: sources/hub1/clusters/+/sourceevents
+  agentEvents: sources/hub1/clusters/+/agentevents
+`
+
+func TestLoadMQTTOptions(t *testing.T) {
+	dir := t.TempDir()
+	baseConfigPath := filepath.Join(dir, "base.yaml")
+	if err := os.WriteFile(baseConfigPath, []byte(testMQTTConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	secrets := &fakeSecrets{secret: newSecret("1", "user1", "ca1")}
+	loader := NewCredentialLoader(secrets, "default", "mqtt-credentials", baseConfigPath, filepath.Join(dir, "credentials"))
+
+	options, err := loader.LoadMQTTOptions(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if options.BrokerHost != "test" || options.Username != "user1" || options.Password != "password" {
+		t.Errorf("unexpected options %v", options)
+	}
+
+	if options.CAFile != filepath.Join(dir, "credentials", CAKey) {
+		t.Errorf("unexpected ca file %s", options.CAFile)
+	}
+	assertFile(t, options.CAFile, "ca1")
+
+	agentOptions := mqtt.NewAgentOptions(options, "cluster1", "agent1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloaded := make(chan error, 1)
+	if err := loader.Run(ctx, 10*time.Millisecond, agentOptions.CloudEventsOptions, func(configPath string, err error) {
+		reloaded <- err
+	}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	secrets.set(newSecret("2", "user2", "ca2"))
+
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the options are not reloaded")
+	}
+	assertFile(t, options.CAFile, "ca2")
+
+	reloadedOptions, err := mqtt.BuildMQTTOptionsFromFlags(loader.ConfigPath())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if reloadedOptions.Username != "user2" {
+		t.Errorf("expected user2, but got %s", reloadedOptions.Username)
+	}
+}
+
+func newSecret(resourceVersion, username, ca string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "mqtt-credentials",
+			ResourceVersion: resourceVersion,
+		},
+		Data: map[string][]byte{
+			UsernameKey: []byte(username),
+			PasswordKey: []byte("password"),
+			CAKey:       []byte(ca),
+		},
+	}
+}
+
+func assertFile(t *testing.T, name, expected string) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if string(data) != expected {
+		t.Errorf("expected %s, but got %s", expected, string(data))
+	}
+}
+
+type fakeSecrets struct {
+	corev1client.SecretInterface
+	sync.Mutex
+	secret *corev1.Secret
+}
+
+func (f *fakeSecrets) Secrets(namespace string) corev1client.SecretInterface {
+	return f
+}
+
+func (f *fakeSecrets) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Secret, error) {
+	f.Lock()
+	defer f.Unlock()
+	return f.secret.DeepCopy(), nil
+}
+
+func (f *fakeSecrets) set(secret *corev1.Secret) {
+	f.Lock()
+	defer f.Unlock()
+	f.secret = secret
+}
@@ -0,0 +1,134 @@
+package options
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+const (
+	// SDKUserAgentName is the product name of the sdk in the user agent.
+	SDKUserAgentName = "ocm-sdk-go"
+
+	// UserAgentProperty is the name of the MQTT user property and the gRPC metadata that carry the user agent of a
+	// connection.
+	UserAgentProperty = "user-agent"
+
+	sdkModulePath = "open-cluster-management.io/sdk-go"
+)
+
+// userAgentComponent is a component of the user agent, e.g. the work agent that is built with the sdk.
+type userAgentComponent struct {
+	name    string
+	version string
+}
+
+var userAgentComponents = struct {
+	sync.RWMutex
+	components []userAgentComponent
+}{}
+
+// SDKVersion returns the version of the sdk module that the binary is built with, it is "unknown" if the build
+// information is not available.
+func SDKVersion() string {
+	return sdkVersion()
+}
+
+var sdkVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	version := ""
+	if info.Main.Path == sdkModulePath {
+		version = info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != sdkModulePath {
+			continue
+		}
+
+		version = dep.Version
+		if dep.Replace != nil && len(dep.Replace.Version) != 0 {
+			version = dep.Replace.Version
+		}
+	}
+
+	if len(version) == 0 {
+		return "unknown"
+	}
+
+	return version
+})
+
+// AppendUserAgent appends a component name and its version to the user agent of the process, e.g. the name and the
+// version of the agent that is built with the sdk, so the brokers and the hubs can audit which versions of the
+// components are connected. Appending a component that is already appended updates its version. The user agent is
+// stamped on the connections and the events that are created after it is appended.
+func AppendUserAgent(name, version string) error {
+	if err := validateUserAgentToken(name); err != nil {
+		return fmt.Errorf("invalid user agent component name %q, %v", name, err)
+	}
+
+	if err := validateUserAgentToken(version); err != nil {
+		return fmt.Errorf("invalid user agent component version %q, %v", version, err)
+	}
+
+	userAgentComponents.Lock()
+	defer userAgentComponents.Unlock()
+
+	for i, component := range userAgentComponents.components {
+		if component.name == name {
+			userAgentComponents.components[i].version = version
+			return nil
+		}
+	}
+
+	userAgentComponents.components = append(userAgentComponents.components,
+		userAgentComponent{name: name, version: version})
+	return nil
+}
+
+// UserAgent returns the user agent of the process, it is the product of the sdk and its version followed by the
+// appended components, e.g. "ocm-sdk-go/v0.16.0 work-agent/v0.16.1".
+func UserAgent() string {
+	userAgentComponents.RLock()
+	defer userAgentComponents.RUnlock()
+
+	products := []string{SDKUserAgentName + "/" + SDKVersion()}
+	for _, component := range userAgentComponents.components {
+		products = append(products, component.name+"/"+component.version)
+	}
+
+	return strings.Join(products, " ")
+}
+
+// ParseUserAgent returns the versions of the products in a user agent by their names, the products without a version
+// are ignored.
+func ParseUserAgent(userAgent string) map[string]string {
+	versions := map[string]string{}
+	for _, product := range strings.Fields(userAgent) {
+		name, version, ok := strings.Cut(product, "/")
+		if !ok || len(name) == 0 || len(version) == 0 {
+			continue
+		}
+		versions[name] = version
+	}
+
+	return versions
+}
+
+func validateUserAgentToken(token string) error {
+	if len(token) == 0 {
+		return fmt.Errorf("it must not be empty")
+	}
+
+	if strings.ContainsAny(token, "/ \t\r\n") {
+		return fmt.Errorf("it must not contain '/' or whitespaces")
+	}
+
+	return nil
+}
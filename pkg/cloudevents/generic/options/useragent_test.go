@@ -0,0 +1,34 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	if err := AppendUserAgent("work-agent", "v0.1.0"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := AppendUserAgent("addon-agent", "v1.0.0"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	// the version of an appended component is updated
+	if err := AppendUserAgent("work-agent", "v0.2.0"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, invalid := range [][2]string{{"", "v1"}, {"work agent", "v1"}, {"work-agent", "v1/v2"}} {
+		if err := AppendUserAgent(invalid[0], invalid[1]); err == nil {
+			t.Errorf("expected error of %v, but got nil", invalid)
+		}
+	}
+
+	expected := map[string]string{
+		SDKUserAgentName: SDKVersion(),
+		"work-agent":     "v0.2.0",
+		"addon-agent":    "v1.0.0",
+	}
+	if versions := ParseUserAgent(UserAgent()); !reflect.DeepEqual(versions, expected) {
+		t.Errorf("expected %v, but got %v", expected, versions)
+	}
+}
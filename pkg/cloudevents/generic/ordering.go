@@ -0,0 +1,157 @@
+package generic
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// tombstoneTTL is how long the resource version of a deleted resource is kept to drop the delayed events of the
+	// resource that are received after it is deleted.
+	tombstoneTTL = 10 * time.Minute
+
+	// maxTombstones is the maximum number of the deleted resources whose resource versions are kept, the oldest one is
+	// dropped once the maximum is exceeded.
+	maxTombstones = 10000
+)
+
+// tombstone is the resource version of a deleted resource.
+type tombstone struct {
+	resourceVersion string
+	deletedTime     time.Time
+}
+
+// resourceVersionTracker tracks the last processed resource version of each resource with its resource ID, it is used
+// to find the events that are out of order, e.g. the events that are redelivered or reordered by the broker. The
+// resource version of a deleted resource is kept as a tombstone for the tombstoneTTL, so a delayed event of the resource
+// does not recreate it after it is deleted.
+type resourceVersionTracker struct {
+	sync.Mutex
+	compare         VersionComparator
+	clock           clock.PassiveClock
+	lastVersions    map[string]string
+	tombstones      map[string]tombstone
+	outOfOrderCount atomic.Int64
+}
+
+func newResourceVersionTracker(compare VersionComparator, clock clock.PassiveClock) *resourceVersionTracker {
+	return &resourceVersionTracker{
+		compare:      compare,
+		clock:        clock,
+		lastVersions: map[string]string{},
+		tombstones:   map[string]tombstone{},
+	}
+}
+
+// isOutOfOrder returns true and the last processed resource version if the given resource version is older than the
+// last processed resource version of the resource. The resource versions that cannot be compared are in order.
+func (t *resourceVersionTracker) isOutOfOrder(resourceID, resourceVersion string) (bool, string) {
+	t.Lock()
+	defer t.Unlock()
+
+	if deleted, ok := t.tombstone(resourceID); ok {
+		// the resource is deleted, only a newer resource version recreates it
+		c, err := t.compare(resourceVersion, deleted.resourceVersion)
+		if err != nil {
+			klog.V(4).Infof("failed to compare the resource versions of the resource %s, %v", resourceID, err)
+			return false, deleted.resourceVersion
+		}
+		if c > 0 {
+			return false, deleted.resourceVersion
+		}
+
+		t.outOfOrderCount.Add(1)
+		return true, deleted.resourceVersion
+	}
+
+	lastVersion, ok := t.lastVersions[resourceID]
+	if !ok {
+		return false, lastVersion
+	}
+
+	c, err := t.compare(resourceVersion, lastVersion)
+	if err != nil {
+		klog.V(4).Infof("failed to compare the resource versions of the resource %s, %v", resourceID, err)
+		return false, lastVersion
+	}
+	if c >= 0 {
+		return false, lastVersion
+	}
+
+	t.outOfOrderCount.Add(1)
+	return true, lastVersion
+}
+
+// processed records the resource version of a resource after the resource is handled, the resource version is
+// recorded unless it is older than the last processed resource version.
+func (t *resourceVersionTracker) processed(resourceID, resourceVersion string) {
+	t.Lock()
+	defer t.Unlock()
+
+	lastVersion, ok := t.lastVersions[resourceID]
+	if ok {
+		if c, err := t.compare(resourceVersion, lastVersion); err == nil && c < 0 {
+			return
+		}
+	}
+
+	delete(t.tombstones, resourceID)
+	t.lastVersions[resourceID] = resourceVersion
+}
+
+// deleted records the resource version of a deleted resource as its tombstone, the events of the resource with an
+// older or the same resource version are out of order until the tombstone expires.
+func (t *resourceVersionTracker) deleted(resourceID, resourceVersion string) {
+	t.Lock()
+	defer t.Unlock()
+
+	delete(t.lastVersions, resourceID)
+	t.tombstones[resourceID] = tombstone{resourceVersion: resourceVersion, deletedTime: t.clock.Now()}
+
+	if len(t.tombstones) <= maxTombstones {
+		return
+	}
+
+	// drop the oldest tombstone
+	oldestID := ""
+	for id, deleted := range t.tombstones {
+		if len(oldestID) == 0 || deleted.deletedTime.Before(t.tombstones[oldestID].deletedTime) {
+			oldestID = id
+		}
+	}
+	delete(t.tombstones, oldestID)
+}
+
+// tombstone returns the tombstone of a deleted resource, the expired tombstone is dropped.
+func (t *resourceVersionTracker) tombstone(resourceID string) (tombstone, bool) {
+	deleted, ok := t.tombstones[resourceID]
+	if !ok {
+		return deleted, false
+	}
+
+	if t.clock.Since(deleted.deletedTime) > tombstoneTTL {
+		delete(t.tombstones, resourceID)
+		return deleted, false
+	}
+
+	return deleted, true
+}
+
+// forget stops tracking a resource, e.g. the resource is claimed by another source whose resource versions are not
+// related to the tracked ones.
+func (t *resourceVersionTracker) forget(resourceID string) {
+	t.Lock()
+	defer t.Unlock()
+
+	delete(t.lastVersions, resourceID)
+	delete(t.tombstones, resourceID)
+}
+
+// outOfOrderEvents returns the number of the events that are out of order.
+func (t *resourceVersionTracker) outOfOrderEvents() int64 {
+	return t.outOfOrderCount.Load()
+}
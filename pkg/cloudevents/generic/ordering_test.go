@@ -0,0 +1,85 @@
+package generic
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/utils/clock"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestResourceVersionTracker(t *testing.T) {
+	tracker := newResourceVersionTracker(IntegerVersionComparator, clock.RealClock{})
+
+	if outOfOrder, _ := tracker.isOutOfOrder("test1", "2"); outOfOrder {
+		t.Errorf("expected the first event is in order")
+	}
+	tracker.processed("test1", "2")
+
+	if outOfOrder, _ := tracker.isOutOfOrder("test1", "2"); outOfOrder {
+		t.Errorf("expected the event with same resource version is in order")
+	}
+
+	outOfOrder, lastVersion := tracker.isOutOfOrder("test1", "1")
+	if !outOfOrder || lastVersion != "2" {
+		t.Errorf("expected the event is out of order, last version %s", lastVersion)
+	}
+
+	if outOfOrder, _ := tracker.isOutOfOrder("test2", "1"); outOfOrder {
+		t.Errorf("expected the event of another resource is in order")
+	}
+
+	tracker.forget("test1")
+	if outOfOrder, _ := tracker.isOutOfOrder("test1", "1"); outOfOrder {
+		t.Errorf("expected the event of a forgotten resource is in order")
+	}
+
+	if tracker.outOfOrderEvents() != 1 {
+		t.Errorf("expected 1 out of order event, but got %d", tracker.outOfOrderEvents())
+	}
+}
+
+func TestResourceVersionTrackerTombstone(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	tracker := newResourceVersionTracker(IntegerVersionComparator, fakeClock)
+
+	tracker.processed("test1", "2")
+	tracker.deleted("test1", "3")
+
+	// the delayed update and the redelivered delete of the deleted resource are out of order
+	for _, version := range []string{"2", "3"} {
+		outOfOrder, lastVersion := tracker.isOutOfOrder("test1", version)
+		if !outOfOrder || lastVersion != "3" {
+			t.Errorf("expected the resource version %s is out of order, last version %s", version, lastVersion)
+		}
+	}
+
+	// the resource is recreated with a newer resource version
+	if outOfOrder, _ := tracker.isOutOfOrder("test1", "4"); outOfOrder {
+		t.Errorf("expected the newer resource version is in order")
+	}
+	tracker.processed("test1", "4")
+	if outOfOrder, _ := tracker.isOutOfOrder("test1", "3"); !outOfOrder {
+		t.Errorf("expected the older resource version of the recreated resource is out of order")
+	}
+
+	// the tombstone expires after the TTL
+	tracker.deleted("test2", "5")
+	fakeClock.Step(tombstoneTTL + time.Second)
+	if outOfOrder, _ := tracker.isOutOfOrder("test2", "1"); outOfOrder {
+		t.Errorf("expected the expired tombstone is dropped")
+	}
+
+	// the oldest tombstones are dropped once the maximum is exceeded
+	for i := 0; i <= maxTombstones; i++ {
+		tracker.deleted(fmt.Sprintf("resource%d", i), "1")
+		fakeClock.Step(time.Millisecond)
+	}
+	if len(tracker.tombstones) != maxTombstones {
+		t.Errorf("expected %d tombstones, but got %d", maxTombstones, len(tracker.tombstones))
+	}
+	if _, ok := tracker.tombstones["resource0"]; ok {
+		t.Errorf("expected the oldest tombstone is dropped")
+	}
+}
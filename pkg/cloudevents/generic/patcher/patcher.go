@@ -0,0 +1,155 @@
+// Package patcher publishes the status of the resources with the cloudevents agent clients only when the status is
+// changed, it is the counterpart of the patcher package of the kube clients for the resources whose status is sent
+// across a cloudevents transport, so the status updates are minimal and idempotent.
+//
+// The conditions of the new status are merged with the conditions of the old status with the conditions package, so
+// the LastTransitionTime of a condition whose status is not changed is kept and a stale condition never overwrites the
+// existing condition, then the status is published only if the merged status is different from the old status and
+// from the status that is published last time.
+package patcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/conditions"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// StatusPublisher publishes the status of a resource, e.g. the CloudEventAgentClient.
+type StatusPublisher[T generic.ResourceObject] interface {
+	Publish(ctx context.Context, eventType types.CloudEventsType, obj T) error
+}
+
+// StatusPatcher publishes the status of the resources only when the status is changed.
+type StatusPatcher[T generic.ResourceObject, St any] struct {
+	sync.Mutex
+	publisher  StatusPublisher[T]
+	eventType  types.CloudEventsType
+	setStatus  func(obj T, status St) T
+	conditions func(status *St) *[]metav1.Condition
+	published  map[kubetypes.UID]string
+}
+
+// NewStatusPatcher returns a StatusPatcher that publishes the status with the event type, e.g. the update_request of
+// the status sub resource of a data type. The setStatus returns a copy of a resource with the given status, the
+// resource is published with the returned copy.
+func NewStatusPatcher[T generic.ResourceObject, St any](
+	publisher StatusPublisher[T], eventType types.CloudEventsType, setStatus func(obj T, status St) T) *StatusPatcher[T, St] {
+	return &StatusPatcher[T, St]{
+		publisher: publisher,
+		eventType: eventType,
+		setStatus: setStatus,
+		published: map[kubetypes.UID]string{},
+	}
+}
+
+// WithConditions merges the conditions of the status that are returned by the given func, the func returns nil if the
+// status does not have the conditions.
+func (p *StatusPatcher[T, St]) WithConditions(conditions func(status *St) *[]metav1.Condition) *StatusPatcher[T, St] {
+	p.conditions = conditions
+	return p
+}
+
+// PatchStatus publishes the resource with the new status if the new status is changed from the old status, it returns
+// true if the status is published. The conditions of the new status are merged with the old conditions, the merged
+// status is not published again if it is the status that is published last time for the resource, e.g. the old
+// status is read from a cache that is not refreshed yet.
+func (p *StatusPatcher[T, St]) PatchStatus(ctx context.Context, obj T, newStatus, oldStatus St) (bool, error) {
+	merged, err := p.merge(newStatus, oldStatus)
+	if err != nil {
+		return false, err
+	}
+
+	if equality.Semantic.DeepEqual(merged, oldStatus) {
+		return false, nil
+	}
+
+	hash, err := statusHash(merged)
+	if err != nil {
+		return false, err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.published[obj.GetUID()] == hash {
+		return false, nil
+	}
+
+	if err := p.publisher.Publish(ctx, p.eventType, p.setStatus(obj, merged)); err != nil {
+		return false, err
+	}
+
+	p.published[obj.GetUID()] = hash
+	return true, nil
+}
+
+// Forget forgets the status that is published last time for the resource, e.g. after the resource is deleted.
+func (p *StatusPatcher[T, St]) Forget(resourceID kubetypes.UID) {
+	p.Lock()
+	defer p.Unlock()
+
+	delete(p.published, resourceID)
+}
+
+// merge returns a copy of the new status whose conditions are merged with the conditions of the old status, the
+// conditions that are not in the new status are removed.
+func (p *StatusPatcher[T, St]) merge(newStatus, oldStatus St) (St, error) {
+	merged, err := deepCopy(newStatus)
+	if err != nil {
+		return merged, err
+	}
+
+	if p.conditions == nil {
+		return merged, nil
+	}
+
+	newConditions := p.conditions(&merged)
+	oldConditions := p.conditions(&oldStatus)
+	if newConditions == nil || oldConditions == nil {
+		return merged, nil
+	}
+
+	mergedConditions := []metav1.Condition{}
+	for _, condition := range *newConditions {
+		if existing := conditions.Get(*oldConditions, condition.Type); existing != nil {
+			mergedConditions = append(mergedConditions, *existing.DeepCopy())
+		}
+		conditions.Set(&mergedConditions, condition)
+	}
+
+	*newConditions = mergedConditions
+	return merged, nil
+}
+
+func deepCopy[St any](status St) (St, error) {
+	var copied St
+	data, err := json.Marshal(status)
+	if err != nil {
+		return copied, fmt.Errorf("failed to marshal the status, %v", err)
+	}
+
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return copied, fmt.Errorf("failed to unmarshal the status, %v", err)
+	}
+
+	return copied, nil
+}
+
+func statusHash[St any](status St) (string, error) {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the status, %v", err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
@@ -0,0 +1,212 @@
+package patcher
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type testStatus struct {
+	Phase      string             `json:"phase,omitempty"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type testResource struct {
+	UID             kubetypes.UID
+	ResourceVersion string
+	Status          testStatus
+}
+
+func (r *testResource) GetUID() kubetypes.UID {
+	return r.UID
+}
+
+func (r *testResource) GetResourceVersion() string {
+	return r.ResourceVersion
+}
+
+func (r *testResource) GetDeletionTimestamp() *metav1.Time {
+	return nil
+}
+
+type fakePublisher struct {
+	published []*testResource
+	err       error
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, eventType types.CloudEventsType, obj *testResource) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	p.published = append(p.published, obj)
+	return nil
+}
+
+func newTestPatcher(publisher *fakePublisher) *StatusPatcher[*testResource, testStatus] {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: types.CloudEventsDataType{Group: "test", Version: "v1", Resource: "tests"},
+		SubResource:         types.SubResourceStatus,
+		Action:              "update_request",
+	}
+
+	return NewStatusPatcher(publisher, eventType, func(obj *testResource, status testStatus) *testResource {
+		copied := *obj
+		copied.Status = status
+		return &copied
+	}).WithConditions(func(status *testStatus) *[]metav1.Condition {
+		return &status.Conditions
+	})
+}
+
+func TestPatchStatus(t *testing.T) {
+	transitionTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	available := metav1.Condition{
+		Type:               "Available",
+		Status:             metav1.ConditionTrue,
+		Reason:             "Available",
+		ObservedGeneration: 2,
+		LastTransitionTime: transitionTime,
+	}
+
+	cases := []struct {
+		name              string
+		oldStatus         testStatus
+		newStatus         testStatus
+		publishErr        error
+		expectedPatched   bool
+		expectedErr       bool
+		expectedCondition *metav1.Condition
+	}{
+		{
+			name:            "status is not changed",
+			oldStatus:       testStatus{Phase: "Running", Conditions: []metav1.Condition{available}},
+			newStatus:       testStatus{Phase: "Running", Conditions: []metav1.Condition{available}},
+			expectedPatched: false,
+		},
+		{
+			name:      "condition without the transition time is not changed",
+			oldStatus: testStatus{Phase: "Running", Conditions: []metav1.Condition{available}},
+			newStatus: testStatus{Phase: "Running", Conditions: []metav1.Condition{{
+				Type: "Available", Status: metav1.ConditionTrue, Reason: "Available", ObservedGeneration: 2,
+			}}},
+			expectedPatched: false,
+		},
+		{
+			name:      "stale condition is ignored",
+			oldStatus: testStatus{Phase: "Running", Conditions: []metav1.Condition{available}},
+			newStatus: testStatus{Phase: "Running", Conditions: []metav1.Condition{{
+				Type: "Available", Status: metav1.ConditionFalse, Reason: "Unavailable", ObservedGeneration: 1,
+			}}},
+			expectedPatched: false,
+		},
+		{
+			name:      "condition message is changed",
+			oldStatus: testStatus{Phase: "Running", Conditions: []metav1.Condition{available}},
+			newStatus: testStatus{Phase: "Running", Conditions: []metav1.Condition{{
+				Type: "Available", Status: metav1.ConditionTrue, Reason: "Available", Message: "ready", ObservedGeneration: 2,
+			}}},
+			expectedPatched: true,
+			expectedCondition: &metav1.Condition{
+				Type: "Available", Status: metav1.ConditionTrue, Reason: "Available", Message: "ready",
+				ObservedGeneration: 2, LastTransitionTime: transitionTime,
+			},
+		},
+		{
+			name:            "phase is changed",
+			oldStatus:       testStatus{Phase: "Pending", Conditions: []metav1.Condition{available}},
+			newStatus:       testStatus{Phase: "Running", Conditions: []metav1.Condition{available}},
+			expectedPatched: true,
+		},
+		{
+			name:        "failed to publish",
+			oldStatus:   testStatus{Phase: "Pending"},
+			newStatus:   testStatus{Phase: "Running"},
+			publishErr:  fmt.Errorf("failed"),
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			publisher := &fakePublisher{err: c.publishErr}
+			patcher := newTestPatcher(publisher)
+
+			obj := &testResource{UID: "test", ResourceVersion: "2", Status: c.oldStatus}
+			patched, err := patcher.PatchStatus(context.Background(), obj, c.newStatus, c.oldStatus)
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+
+			if patched != c.expectedPatched {
+				t.Errorf("expected patched %v, but got %v", c.expectedPatched, patched)
+			}
+
+			if !c.expectedPatched {
+				if len(publisher.published) != 0 {
+					t.Errorf("expected no published resources, but got %d", len(publisher.published))
+				}
+				return
+			}
+
+			if len(publisher.published) != 1 {
+				t.Fatalf("expected one published resource, but got %d", len(publisher.published))
+			}
+
+			status := publisher.published[0].Status
+			if status.Phase != c.newStatus.Phase {
+				t.Errorf("expected phase %s, but got %s", c.newStatus.Phase, status.Phase)
+			}
+
+			if c.expectedCondition != nil {
+				if len(status.Conditions) != 1 {
+					t.Fatalf("expected one condition, but got %v", status.Conditions)
+				}
+				if !status.Conditions[0].LastTransitionTime.Equal(&c.expectedCondition.LastTransitionTime) ||
+					status.Conditions[0].Message != c.expectedCondition.Message {
+					t.Errorf("expected condition %v, but got %v", c.expectedCondition, status.Conditions[0])
+				}
+			}
+
+			// the old status of the same resource is not changed
+			if obj.Status.Phase != c.oldStatus.Phase {
+				t.Errorf("expected the resource is not changed, but got %v", obj.Status)
+			}
+		})
+	}
+}
+
+func TestPatchStatusIdempotent(t *testing.T) {
+	publisher := &fakePublisher{}
+	patcher := newTestPatcher(publisher)
+
+	obj := &testResource{UID: "test", ResourceVersion: "1"}
+	oldStatus := testStatus{Phase: "Pending"}
+	newStatus := testStatus{Phase: "Running"}
+
+	for i := 0; i < 3; i++ {
+		// the old status is not refreshed, e.g. it is read from a stale cache
+		if _, err := patcher.PatchStatus(context.Background(), obj, newStatus, oldStatus); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+
+	if len(publisher.published) != 1 {
+		t.Errorf("expected one published resource, but got %d", len(publisher.published))
+	}
+
+	patcher.Forget(obj.UID)
+	patched, err := patcher.PatchStatus(context.Background(), obj, newStatus, oldStatus)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !patched || len(publisher.published) != 2 {
+		t.Errorf("expected the status is published again after it is forgotten, but got %d", len(publisher.published))
+	}
+}
@@ -3,6 +3,7 @@ package payload
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 )
@@ -10,6 +11,29 @@ import (
 type ResourceVersion struct {
 	ResourceID      string `json:"resourceID"`
 	ResourceVersion int64  `json:"resourceVersion"`
+
+	// StringVersion is the resource version that is not an integer, e.g. a Kubernetes resourceVersion or a content
+	// hash, the ResourceVersion is zero if it is set.
+	StringVersion string `json:"stringVersion,omitempty"`
+}
+
+// NewResourceVersion returns the ResourceVersion of a resource, the version is set to the ResourceVersion if it is an
+// integer, otherwise it is set to the StringVersion.
+func NewResourceVersion(resourceID, version string) ResourceVersion {
+	if resourceVersion, err := strconv.ParseInt(version, 10, 64); err == nil {
+		return ResourceVersion{ResourceID: resourceID, ResourceVersion: resourceVersion}
+	}
+
+	return ResourceVersion{ResourceID: resourceID, StringVersion: version}
+}
+
+// GetVersion returns the version of the resource as a string.
+func (v ResourceVersion) GetVersion() string {
+	if v.StringVersion != "" {
+		return v.StringVersion
+	}
+
+	return strconv.FormatInt(v.ResourceVersion, 10)
 }
 
 type ResourceStatusHash struct {
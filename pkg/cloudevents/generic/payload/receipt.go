@@ -0,0 +1,36 @@
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// DeliveryReceipt is the payload of a delivery receipt, an agent acknowledges a resource spec event with it once the
+// spec is decoded and before the spec is applied.
+type DeliveryReceipt struct {
+	// EventID is the ID of the spec event that is acknowledged.
+	EventID string `json:"eventID"`
+
+	// ResourceID is the ID of the resource of the spec.
+	ResourceID string `json:"resourceID"`
+
+	// ResourceVersion is the resource version of the spec.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+
+	// Accepted is true if the spec is accepted by the agent to apply.
+	Accepted bool `json:"accepted"`
+
+	// Message is a human readable message of the receipt, e.g. the reason why the spec is rejected.
+	Message string `json:"message,omitempty"`
+}
+
+func DecodeDeliveryReceipt(evt cloudevents.Event) (*DeliveryReceipt, error) {
+	receipt := &DeliveryReceipt{}
+	data := evt.Data()
+	if err := json.Unmarshal(data, receipt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal delivery receipt payload %s, %v", string(data), err)
+	}
+	return receipt, nil
+}
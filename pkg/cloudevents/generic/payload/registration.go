@@ -0,0 +1,57 @@
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// AgentRegistration is the payload of a registration request, an agent announces itself to the sources with it before
+// it handles the resource spec.
+type AgentRegistration struct {
+	// ClusterName is the name of the cluster that the agent runs on.
+	ClusterName string `json:"clusterName"`
+
+	// AgentID is the unique identifier of the agent.
+	AgentID string `json:"agentID"`
+
+	// AgentVersion is the version of the agent.
+	AgentVersion string `json:"agentVersion,omitempty"`
+
+	// Capabilities are the features that are supported by the agent, e.g. the event data types that it can handle.
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// AgentBootstrap is the payload of a registration response, a source acknowledges a registration request with it.
+type AgentBootstrap struct {
+	// RequestID is the event ID of the registration request that is acknowledged.
+	RequestID string `json:"requestID"`
+
+	// Accepted is true if the source accepts the agent.
+	Accepted bool `json:"accepted"`
+
+	// Message is a human readable message of the acknowledgment, e.g. the reason why the agent is rejected.
+	Message string `json:"message,omitempty"`
+
+	// Config is the optional bootstrap configuration of the agent, it is opaque to the clients.
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+func DecodeAgentRegistration(evt cloudevents.Event) (*AgentRegistration, error) {
+	registration := &AgentRegistration{}
+	data := evt.Data()
+	if err := json.Unmarshal(data, registration); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal registration request payload %s, %v", string(data), err)
+	}
+	return registration, nil
+}
+
+func DecodeAgentBootstrap(evt cloudevents.Event) (*AgentBootstrap, error) {
+	bootstrap := &AgentBootstrap{}
+	data := evt.Data()
+	if err := json.Unmarshal(data, bootstrap); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal registration response payload %s, %v", string(data), err)
+	}
+	return bootstrap, nil
+}
@@ -0,0 +1,101 @@
+package generic
+
+import (
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// payloadSizes records the size histograms of the sent and received events by the event data types.
+type payloadSizes struct {
+	sync.Mutex
+	histograms map[string]*options.PayloadSizes
+}
+
+// observe records the size of an event of an event data type, the rejected is true if the sent event is rejected
+// because it is too large.
+func (s *payloadSizes) observe(direction options.AuditDirection, eventDataType string, size int, rejected bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.histograms == nil {
+		s.histograms = map[string]*options.PayloadSizes{}
+	}
+
+	sizes, ok := s.histograms[eventDataType]
+	if !ok {
+		sizes = &options.PayloadSizes{
+			Sent:     newPayloadSizeHistogram(),
+			Received: newPayloadSizeHistogram(),
+		}
+		s.histograms[eventDataType] = sizes
+	}
+
+	histogram := &sizes.Sent
+	if direction == options.AuditReceived {
+		histogram = &sizes.Received
+	}
+
+	histogram.Count++
+	histogram.Sum += int64(size)
+	histogram.Max = max(histogram.Max, size)
+	for i := range histogram.Buckets {
+		if size <= histogram.Buckets[i].UpperBound {
+			histogram.Buckets[i].Count++
+		}
+	}
+	if rejected {
+		histogram.Rejected++
+	}
+}
+
+// snapshot returns a copy of the histograms, it returns nil if no event is recorded.
+func (s *payloadSizes) snapshot() map[string]options.PayloadSizes {
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.histograms) == 0 {
+		return nil
+	}
+
+	snapshot := make(map[string]options.PayloadSizes, len(s.histograms))
+	for eventDataType, sizes := range s.histograms {
+		copied := *sizes
+		copied.Sent.Buckets = append([]options.PayloadSizeBucket{}, sizes.Sent.Buckets...)
+		copied.Received.Buckets = append([]options.PayloadSizeBucket{}, sizes.Received.Buckets...)
+		snapshot[eventDataType] = copied
+	}
+	return snapshot
+}
+
+func newPayloadSizeHistogram() options.PayloadSizeHistogram {
+	buckets := make([]options.PayloadSizeBucket, len(options.PayloadSizeBuckets))
+	for i, upperBound := range options.PayloadSizeBuckets {
+		buckets[i].UpperBound = upperBound
+	}
+	return options.PayloadSizeHistogram{Buckets: buckets}
+}
+
+// observePayloadSize records the size of a sent or received event, and passes it to the PayloadSizeObserver of the
+// client.
+func (c *baseClient) observePayloadSize(direction options.AuditDirection, evt cloudevents.Event, rejected bool) {
+	size, err := eventSize(evt)
+	if err != nil {
+		klog.V(4).Infof("failed to get the size of the event %s, %v", evt.ID(), err)
+		return
+	}
+
+	eventDataType := evt.Type()
+	if eventType, err := types.ParseCloudEventsType(evt.Type()); err == nil {
+		eventDataType = eventType.CloudEventsDataType.String()
+	}
+
+	c.payloadSizes.observe(direction, eventDataType, size, rejected)
+	if c.payloadSizeObserver != nil {
+		c.payloadSizeObserver(direction, evt, size)
+	}
+}
@@ -0,0 +1,93 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestPublishPayloadSize(t *testing.T) {
+	observed := []int{}
+	fakeClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	sourceOptions.MaxEventSize = 2048
+	sourceOptions.PayloadSizeObserver = func(direction options.AuditDirection, evt cloudevents.Event, size int) {
+		if direction != options.AuditSent {
+			t.Errorf("expected %s, but got %s", options.AuditSent, direction)
+		}
+		observed = append(observed, size)
+	}
+
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+
+	if err := source.Publish(context.TODO(), eventType, &mockResource{
+		UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "test1", Namespace: "cluster1"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	err = source.Publish(context.TODO(), eventType, &mockResource{
+		UID: kubetypes.UID("test2"), ResourceVersion: "1", Status: strings.Repeat("a", 4096), Namespace: "cluster1"})
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Fatalf("expected payload too large error, but got %v", err)
+	}
+	var validationErr *EventValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("expected validation error, but got %v", err)
+	}
+
+	if len(fakeClient.GetSentEvents()) != 1 {
+		t.Errorf("expected 1 sent event, but got %v", fakeClient.GetSentEvents())
+	}
+	if len(observed) != 2 || observed[0] > 1024 || observed[1] <= 4096 {
+		t.Errorf("unexpected observed sizes %v", observed)
+	}
+
+	sizes, ok := source.Diagnostics().PayloadSizes[mockEventDataType.String()]
+	if !ok {
+		t.Fatalf("expected the payload sizes of %s", mockEventDataType)
+	}
+	if sizes.Sent.Count != 2 || sizes.Sent.Rejected != 1 || sizes.Sent.Max != observed[1] ||
+		sizes.Sent.Sum != int64(observed[0]+observed[1]) || sizes.Received.Count != 0 {
+		t.Errorf("unexpected payload sizes %v", sizes)
+	}
+}
+
+func TestPayloadSizeHistogram(t *testing.T) {
+	sizes := payloadSizes{}
+	for _, size := range []int{100, 1024, 2000, 10 << 20} {
+		sizes.observe(options.AuditReceived, "test", size, false)
+	}
+
+	histogram := sizes.snapshot()["test"].Received
+	expected := []int64{2, 3, 3, 3, 3, 3, 3}
+	for i, bucket := range histogram.Buckets {
+		if bucket.UpperBound != options.PayloadSizeBuckets[i] || bucket.Count != expected[i] {
+			t.Errorf("expected %d events of the bucket %d, but got %v", expected[i], options.PayloadSizeBuckets[i], bucket)
+		}
+	}
+	if histogram.Count != 4 || histogram.Max != 10<<20 {
+		t.Errorf("unexpected histogram %v", histogram)
+	}
+
+	if snapshot := (&payloadSizes{}).snapshot(); snapshot != nil {
+		t.Errorf("expected nil, but got %v", snapshot)
+	}
+}
@@ -0,0 +1,245 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// sourceQuota enforces the quota of a source client.
+type sourceQuota struct {
+	options.SourceQuota
+	publishLimiter *publishBucket
+
+	// resources are the IDs of the resources of the source on each cluster, the resources of a cluster are listed
+	// once when the cluster is checked for the first time, then they are updated by the reserved and published specs.
+	sync.Mutex
+	resources map[string]sets.Set[string]
+}
+
+func newSourceQuota(quota options.SourceQuota, clock clock.PassiveClock) *sourceQuota {
+	q := &sourceQuota{SourceQuota: quota, resources: map[string]sets.Set[string]{}}
+	if quota.MaxPublishQPS > 0 {
+		burst := quota.MaxPublishBurst
+		if burst <= 0 {
+			burst = int(math.Ceil(float64(quota.MaxPublishQPS)))
+		}
+		q.publishLimiter = newPublishBucket(float64(quota.MaxPublishQPS), burst, clock)
+	}
+
+	return q
+}
+
+// publishBucket is the token bucket of the publish rate, unlike the token bucket of a rate limiter, the token of a
+// resource that fails to be published is put back to the bucket.
+type publishBucket struct {
+	sync.Mutex
+	clock  clock.PassiveClock
+	qps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newPublishBucket(qps float64, burst int, clock clock.PassiveClock) *publishBucket {
+	return &publishBucket{clock: clock, qps: qps, burst: float64(burst), tokens: float64(burst), last: clock.Now()}
+}
+
+// take takes a token from the bucket, it returns false if there is no token.
+func (b *publishBucket) take() bool {
+	b.Lock()
+	defer b.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// put puts a taken token back to the bucket.
+func (b *publishBucket) put() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.refill()
+	b.tokens = math.Min(b.tokens+1, b.burst)
+}
+
+func (b *publishBucket) refill() {
+	now := b.clock.Now()
+	b.tokens = math.Min(b.tokens+now.Sub(b.last).Seconds()*b.qps, b.burst)
+	b.last = now
+}
+
+// quotaExempted returns true if the event is not limited by the quota, the quota limits the resource specs that are
+// created or updated by the source, the deletes, the resync responses, the handovers and the claims are not limited,
+// so the agents always get the specs that they already have, e.g. a resync is never rejected partway.
+func quotaExempted[T ResourceObject](evt cloudevents.Event, obj T) bool {
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return true
+	}
+
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return false
+	}
+
+	switch eventType.Action {
+	case types.ResyncResponseAction, types.HandoverRequestAction, types.ClaimRequestAction:
+		return true
+	default:
+		return false
+	}
+}
+
+// reserveQuota reserves the quota for the resource of the event before it is published, it returns an error that
+// wraps the ErrQuotaExceeded if the resource exceeds the quota of the source. The quota is checked and consumed in one
+// step, so the resources that are published concurrently cannot exceed the quota together, the returned release gives
+// the reserved quota back if the resource fails to be published. The publish rate is reserved after the resources, so
+// a rejected resource does not consume the publish rate.
+func (c *CloudEventSourceClient[T]) reserveQuota(ctx context.Context, evt cloudevents.Event, obj T) (func(), error) {
+	release := func() {}
+	if quotaExempted(evt, obj) {
+		return release, nil
+	}
+
+	clusterName, _ := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+	resourceID := string(obj.GetUID())
+
+	// the resources of the clusters of a broadcast spec are not counted
+	if c.quota.MaxResourcesPerCluster > 0 && clusterName != types.ClusterAll {
+		releaseResource, reserved, err := c.reserveResource(ctx, clusterName, resourceID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reserved {
+			return nil, c.quotaExceeded(&options.QuotaExceededError{
+				Quota:       options.QuotaMaxResourcesPerCluster,
+				Limit:       float64(c.quota.MaxResourcesPerCluster),
+				ClusterName: clusterName,
+				ResourceID:  resourceID,
+			})
+		}
+		release = releaseResource
+	}
+
+	// the events in the dry-run mode are not sent, so they do not consume the publish quota
+	if c.quota.publishLimiter != nil && !isDryRun(ctx) {
+		if !c.quota.publishLimiter.take() {
+			release()
+			return nil, c.quotaExceeded(&options.QuotaExceededError{
+				Quota:       options.QuotaMaxPublishRate,
+				Limit:       float64(c.quota.MaxPublishQPS),
+				ClusterName: clusterName,
+				ResourceID:  resourceID,
+			})
+		}
+
+		releaseResource := release
+		release = func() {
+			c.quota.publishLimiter.put()
+			releaseResource()
+		}
+	}
+
+	return release, nil
+}
+
+// reserveResource counts the resource in the resources of the cluster and returns true if the cluster has room for it,
+// the resources of a cluster are listed from the lister only once, the deleting resources are not counted. The returned
+// release removes the resource from the cluster if it is counted by this reservation, a resource in the dry-run mode is
+// checked but not counted.
+func (c *CloudEventSourceClient[T]) reserveResource(
+	ctx context.Context, clusterName, resourceID string) (func(), bool, error) {
+	c.quota.Lock()
+	defer c.quota.Unlock()
+
+	resources, ok := c.quota.resources[clusterName]
+	if !ok {
+		objs, err := c.lister.List(types.ListOptions{Source: c.sourceID, ClusterName: clusterName})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to list the resources of the cluster %s, %v", clusterName, err)
+		}
+
+		resources = sets.New[string]()
+		for _, o := range objs {
+			if o.GetDeletionTimestamp().IsZero() {
+				resources.Insert(string(o.GetUID()))
+			}
+		}
+		c.quota.resources[clusterName] = resources
+	}
+
+	// the resource may be counted or not, so only the other resources are counted
+	counted := resources.Has(resourceID)
+	others := resources.Len()
+	if counted {
+		others--
+	}
+
+	if others >= c.quota.MaxResourcesPerCluster {
+		return nil, false, nil
+	}
+
+	if counted || isDryRun(ctx) {
+		return func() {}, true, nil
+	}
+
+	resources.Insert(resourceID)
+	return func() {
+		c.quota.Lock()
+		defer c.quota.Unlock()
+
+		c.quota.resources[clusterName].Delete(resourceID)
+	}, true, nil
+}
+
+// observePublished updates the resources of the cluster with a published spec, a deleting resource is removed from the
+// cluster. The clusters that are not checked yet are listed when they are checked, so they are not updated.
+func (c *CloudEventSourceClient[T]) observePublished(evt cloudevents.Event, obj T) {
+	if c.quota.MaxResourcesPerCluster <= 0 {
+		return
+	}
+
+	clusterName, _ := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+
+	c.quota.Lock()
+	defer c.quota.Unlock()
+
+	resources, ok := c.quota.resources[clusterName]
+	if !ok {
+		return
+	}
+
+	if obj.GetDeletionTimestamp().IsZero() {
+		resources.Insert(string(obj.GetUID()))
+		return
+	}
+	resources.Delete(string(obj.GetUID()))
+}
+
+func (c *CloudEventSourceClient[T]) quotaExceeded(err *options.QuotaExceededError) error {
+	klog.V(4).Infof("Reject the resource %s, %v", err.ResourceID, err)
+
+	c.status.quotaExceeded()
+	if c.quota.OnExceeded != nil {
+		c.quota.OnExceeded(err)
+	}
+
+	return fmt.Errorf("%w: %w", ErrQuotaExceeded, err)
+}
@@ -0,0 +1,254 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestSourceQuota(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	existing := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"}
+	now := metav1.Now()
+	deleting := &mockResource{UID: kubetypes.UID("test2"), ResourceVersion: "1", Namespace: "cluster1",
+		DeletionTimestamp: &now}
+	newResource := &mockResource{UID: kubetypes.UID("test3"), ResourceVersion: "1", Namespace: "cluster1"}
+
+	cases := []struct {
+		name          string
+		quota         options.SourceQuota
+		resources     []*mockResource
+		expectedQuota []string
+	}{
+		{
+			name:          "not limited",
+			resources:     []*mockResource{existing, deleting, newResource},
+			expectedQuota: []string{"", "", ""},
+		},
+		{
+			name:          "max resources per cluster",
+			quota:         options.SourceQuota{MaxResourcesPerCluster: 1},
+			resources:     []*mockResource{existing, deleting, newResource},
+			expectedQuota: []string{"", "", options.QuotaMaxResourcesPerCluster},
+		},
+		{
+			// the deleting resources do not consume the publish rate
+			name:          "max publish rate",
+			quota:         options.SourceQuota{MaxPublishQPS: 0.001, MaxPublishBurst: 1},
+			resources:     []*mockResource{existing, deleting, newResource},
+			expectedQuota: []string{"", "", options.QuotaMaxPublishRate},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			exceeded := []*options.QuotaExceededError{}
+			c.quota.OnExceeded = func(err *options.QuotaExceededError) {
+				exceeded = append(exceeded, err)
+			}
+
+			sourceOptions := fake.NewSourceOptions(fake.NewCloudEventsFakeClient(), testSourceName)
+			sourceOptions.Quota = c.quota
+			source, err := NewCloudEventSourceClient[*mockResource](context.TODO(), sourceOptions,
+				newMockResourceLister(existing, deleting), statusHash, newMockResourceCodec())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rejected := int64(0)
+			for i, res := range c.resources {
+				err := source.Publish(context.TODO(), eventType, res)
+
+				var quotaErr *options.QuotaExceededError
+				switch {
+				case c.expectedQuota[i] == "" && err != nil:
+					t.Errorf("unexpected error %v", err)
+				case c.expectedQuota[i] != "" && (!errors.Is(err, ErrQuotaExceeded) || !errors.As(err, &quotaErr)):
+					t.Errorf("expected %v, but got %v", ErrQuotaExceeded, err)
+				case c.expectedQuota[i] != "":
+					rejected++
+					if quotaErr.Quota != c.expectedQuota[i] || quotaErr.ClusterName != "cluster1" {
+						t.Errorf("unexpected quota error %v", quotaErr)
+					}
+				}
+			}
+
+			if len(exceeded) != int(rejected) {
+				t.Errorf("expected %d exceeded quotas, but got %v", rejected, exceeded)
+			}
+			if diagnostics := source.Diagnostics(); diagnostics.QuotaRejections != rejected {
+				t.Errorf("expected %d rejections, but got %d", rejected, diagnostics.QuotaRejections)
+			}
+		})
+	}
+}
+
+func TestSourceQuotaExemptions(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	resources := []*mockResource{
+		{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"},
+		{UID: kubetypes.UID("test2"), ResourceVersion: "1", Namespace: "cluster1"},
+		{UID: kubetypes.UID("test3"), ResourceVersion: "1", Namespace: "cluster1"},
+	}
+
+	fakeClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	sourceOptions.Quota = options.SourceQuota{MaxResourcesPerCluster: 1, MaxPublishQPS: 0.001, MaxPublishBurst: 1}
+	lister := newMockResourceLister(resources...)
+	source, err := NewCloudEventSourceClient[*mockResource](context.TODO(), sourceOptions,
+		lister, statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the resync response is larger than the publish burst and the resources are over the quota after the quota is
+	// lowered, but the agent still gets all the specs
+	resyncRequest := cloudevents.NewEvent()
+	resyncRequest.SetType(types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncRequestAction,
+	}.String())
+	resyncRequest.SetExtension("clustername", "cluster1")
+	if err := resyncRequest.SetData(cloudevents.ApplicationJSON, &payload.ResourceVersionList{}); err != nil {
+		t.Fatal(err)
+	}
+	source.receive(context.TODO(), resyncRequest)
+	if len(fakeClient.GetSentEvents()) != len(resources) {
+		t.Fatalf("expected %d resync responses, but got %d", len(resources), len(fakeClient.GetSentEvents()))
+	}
+
+	// the update of a resource over the quota is rejected
+	if err := source.Publish(context.TODO(), eventType, resources[0]); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected %v, but got %v", ErrQuotaExceeded, err)
+	}
+
+	// the deletes are not limited, and the deleted resources are not counted without listing the resources again
+	now := metav1.Now()
+	lister.resources = nil
+	for _, res := range resources[1:] {
+		deleting := *res
+		deleting.DeletionTimestamp = &now
+		if err := source.Publish(context.TODO(), eventType, &deleting); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+
+	if err := source.Publish(context.TODO(), eventType, resources[0]); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// the resource is counted once it is published
+	var quotaErr *options.QuotaExceededError
+	err = source.Publish(context.TODO(), eventType, &mockResource{
+		UID: kubetypes.UID("test4"), ResourceVersion: "1", Namespace: "cluster1"})
+	if !errors.As(err, &quotaErr) || quotaErr.Quota != options.QuotaMaxResourcesPerCluster {
+		t.Errorf("expected %v, but got %v", options.QuotaMaxResourcesPerCluster, err)
+	}
+}
+
+func TestSourceQuotaReservation(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+
+	newSource := func(client cloudevents.Client, quota options.SourceQuota) *CloudEventSourceClient[*mockResource] {
+		sourceOptions := fake.NewSourceOptions(fake.NewCloudEventsFakeClient(), testSourceName)
+		sourceOptions.CloudEventsOptions = &sendingOptions{
+			CloudEventsFakeOptions: sourceOptions.CloudEventsOptions.(*fake.CloudEventsFakeOptions),
+			client:                 client,
+		}
+		sourceOptions.Quota = quota
+		source, err := NewCloudEventSourceClient[*mockResource](context.TODO(), sourceOptions,
+			newMockResourceLister(), statusHash, newMockResourceCodec())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return source
+	}
+
+	// the new resources that are published concurrently cannot exceed the quota together
+	source := newSource(fake.NewCloudEventsFakeClient(), options.SourceQuota{MaxResourcesPerCluster: 1})
+	published := int32(0)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := source.Publish(context.TODO(), eventType, &mockResource{
+				UID: kubetypes.UID(fmt.Sprintf("test%d", i)), ResourceVersion: "1", Namespace: "cluster1"})
+			switch {
+			case err == nil:
+				atomic.AddInt32(&published, 1)
+			case !errors.Is(err, ErrQuotaExceeded):
+				t.Errorf("unexpected error %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if published != 1 {
+		t.Errorf("expected 1 published resource, but got %d", published)
+	}
+
+	// the quota of a resource that fails to be sent is given back
+	client := &failingClient{CloudEventsFakeClient: fake.NewCloudEventsFakeClient()}
+	client.failing.Store(true)
+	source = newSource(client, options.SourceQuota{MaxResourcesPerCluster: 1, MaxPublishQPS: 0.001, MaxPublishBurst: 1})
+	err := source.Publish(context.TODO(), eventType, &mockResource{
+		UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"})
+	if err == nil || errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected the sending error, but got %v", err)
+	}
+
+	client.failing.Store(false)
+	if err := source.Publish(context.TODO(), eventType, &mockResource{
+		UID: kubetypes.UID("test2"), ResourceVersion: "1", Namespace: "cluster1"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+// sendingOptions builds the source with the given cloudevents client.
+type sendingOptions struct {
+	*fake.CloudEventsFakeOptions
+	client cloudevents.Client
+}
+
+func (o *sendingOptions) Client(ctx context.Context) (cloudevents.Client, error) {
+	return o.client, nil
+}
+
+// failingClient fails to send the events while it is failing.
+type failingClient struct {
+	*fake.CloudEventsFakeClient
+	failing atomic.Bool
+}
+
+func (c *failingClient) Send(ctx context.Context, evt cloudevents.Event) protocol.Result {
+	if c.failing.Load() {
+		return fmt.Errorf("the broker is down")
+	}
+	return c.CloudEventsFakeClient.Send(ctx, evt)
+}
@@ -0,0 +1,121 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// receiptTracker keeps the last delivery receipt of each resource of each cluster on a source. The receipt of a
+// deleting resource is removed once the agent accepts the deletion, so the receipts of the deleted resources are not
+// kept.
+type receiptTracker struct {
+	sync.Mutex
+	receipts map[string]payload.DeliveryReceipt
+	deleting map[string]string
+}
+
+func newReceiptTracker() *receiptTracker {
+	return &receiptTracker{
+		receipts: map[string]payload.DeliveryReceipt{},
+		deleting: map[string]string{},
+	}
+}
+
+func receiptKey(clusterName, resourceID string) string {
+	return clusterName + "/" + resourceID
+}
+
+// delete marks the resource as deleting with the resource version of its deletion spec.
+func (r *receiptTracker) delete(clusterName, resourceID, resourceVersion string) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.deleting[receiptKey(clusterName, resourceID)] = resourceVersion
+}
+
+// record keeps the receipt as the last receipt of its resource.
+func (r *receiptTracker) record(clusterName string, receipt payload.DeliveryReceipt) {
+	r.Lock()
+	defer r.Unlock()
+
+	key := receiptKey(clusterName, receipt.ResourceID)
+	if version, ok := r.deleting[key]; ok && receipt.Accepted && version == receipt.ResourceVersion {
+		delete(r.deleting, key)
+		delete(r.receipts, key)
+		return
+	}
+
+	r.receipts[key] = receipt
+}
+
+func (r *receiptTracker) get(clusterName, resourceID string) (payload.DeliveryReceipt, bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	receipt, ok := r.receipts[receiptKey(clusterName, resourceID)]
+	return receipt, ok
+}
+
+// ackRequested returns true if the source of the spec event requests a delivery receipt.
+func ackRequested(evt cloudevents.Event) bool {
+	val, ok := evt.Extensions()[types.ExtensionAckRequested]
+	if !ok {
+		return false
+	}
+
+	requested, err := cloudeventstypes.ToBool(val)
+	return err == nil && requested
+}
+
+// sendDeliveryReceipt acknowledges a spec event of a source with a delivery receipt if the source requests it, the
+// spec is rejected with the given reason if the reason is not empty. The resource ID and version default to the
+// extensions of the spec event if they are empty, e.g. the spec cannot be decoded.
+func (c *CloudEventAgentClient[T]) sendDeliveryReceipt(ctx context.Context, evt cloudevents.Event,
+	eventDataType types.CloudEventsDataType, resourceID, resourceVersion, reason string) {
+	if !ackRequested(evt) {
+		return
+	}
+
+	if resourceID == "" {
+		resourceID, _ = cloudeventstypes.ToString(evt.Extensions()[types.ExtensionResourceID])
+	}
+	if resourceVersion == "" {
+		resourceVersion, _ = cloudeventstypes.ToString(evt.Extensions()[types.ExtensionResourceVersion])
+	}
+
+	receipt := payload.DeliveryReceipt{
+		EventID:         evt.ID(),
+		ResourceID:      resourceID,
+		ResourceVersion: resourceVersion,
+		Accepted:        len(reason) == 0,
+		Message:         reason,
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: eventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              types.DeliveryReceiptAction,
+	}
+
+	receiptEvt := types.NewEventBuilder(c.agentID, eventType).
+		WithResourceID(resourceID).
+		WithStringResourceVersion(resourceVersion).
+		WithClusterName(c.clusterName).
+		WithOriginalSource(evt.Source()).
+		NewEvent()
+	if err := receiptEvt.SetData(cloudevents.ApplicationJSON, receipt); err != nil {
+		c.discard(evt, fmt.Sprintf("failed to set the delivery receipt data, %v", err))
+		return
+	}
+
+	if err := c.publish(ctx, receiptEvt); err != nil {
+		c.discard(evt, fmt.Sprintf("failed to send the delivery receipt, %v", err))
+	}
+}
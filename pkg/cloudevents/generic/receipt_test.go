@@ -0,0 +1,115 @@
+package generic
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestDeliveryReceipt(t *testing.T) {
+	ctx := context.TODO()
+
+	var handled []payload.DeliveryReceipt
+	sourceClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(sourceClient, testSourceName)
+	sourceOptions.RequestDeliveryReceipts = true
+	sourceOptions.DeliveryReceiptHandler = func(clusterName string, receipt payload.DeliveryReceipt) {
+		if clusterName != "cluster1" {
+			t.Errorf("expected cluster1, but got %s", clusterName)
+		}
+		handled = append(handled, receipt)
+	}
+	source, err := NewCloudEventSourceClient[*mockResource](
+		ctx, sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agentClient := fake.NewCloudEventsFakeClient()
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx,
+		fake.NewAgentOptions(agentClient, "cluster1", testAgentName), newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+	lastSent := func(fakeClient *fake.CloudEventsFakeClient) cloudevents.Event {
+		sent := fakeClient.GetSentEvents()
+		return sent[len(sent)-1]
+	}
+	deliver := func(obj *mockResource) cloudevents.Event {
+		if err := source.Publish(ctx, eventType, obj); err != nil {
+			t.Fatal(err)
+		}
+		spec := lastSent(sourceClient)
+		agent.receive(ctx, spec)
+		return spec
+	}
+
+	if _, ok := source.DeliveryReceipt("cluster1", "test1"); ok {
+		t.Errorf("expected no receipt before the spec is delivered")
+	}
+
+	// the spec is acknowledged once it is decoded by the agent
+	spec := deliver(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2", Namespace: "cluster1"})
+	if !ackRequested(spec) {
+		t.Errorf("expected the delivery receipt is requested, but got %v", spec.Extensions())
+	}
+	receiptEvt := lastSent(agentClient)
+	if receiptEvt.Type() != mockEventDataType.String()+".status.delivery_receipt" {
+		t.Errorf("unexpected receipt type %s", receiptEvt.Type())
+	}
+	source.receive(ctx, receiptEvt)
+
+	receipt, ok := source.DeliveryReceipt("cluster1", "test1")
+	if !ok {
+		t.Fatalf("expected the receipt of the spec")
+	}
+	expected := payload.DeliveryReceipt{EventID: spec.ID(), ResourceID: "test1", ResourceVersion: "2", Accepted: true}
+	if receipt != expected {
+		t.Errorf("expected %v, but got %v", expected, receipt)
+	}
+	if len(handled) != 1 {
+		t.Errorf("expected the receipt is handled, but got %v", handled)
+	}
+
+	// the outdated spec is rejected
+	spec = deliver(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"})
+	source.receive(ctx, lastSent(agentClient))
+	receipt, _ = source.DeliveryReceipt("cluster1", "test1")
+	if receipt.EventID != spec.ID() || receipt.Accepted || len(receipt.Message) == 0 {
+		t.Errorf("expected the spec is rejected, but got %v", receipt)
+	}
+
+	// the receipt is forgotten once the deletion is accepted
+	now := metav1.Now()
+	deliver(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "3", Namespace: "cluster1", DeletionTimestamp: &now})
+	source.receive(ctx, lastSent(agentClient))
+	if _, ok := source.DeliveryReceipt("cluster1", "test1"); ok {
+		t.Errorf("expected the receipt of the deleted resource is forgotten")
+	}
+	if len(handled) != 3 {
+		t.Errorf("expected 3 handled receipts, but got %v", handled)
+	}
+
+	// the agent does not send a receipt if it is not requested
+	sent := len(agentClient.GetSentEvents())
+	spec.SetExtension(types.ExtensionAckRequested, nil)
+	spec.SetExtension(types.ExtensionResourceVersion, "4")
+	agent.receive(ctx, spec)
+	if len(agentClient.GetSentEvents()) != sent {
+		t.Errorf("expected no receipt, but got %v", agentClient.GetSentEvents()[sent:])
+	}
+}
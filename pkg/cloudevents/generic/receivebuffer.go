@@ -0,0 +1,80 @@
+package generic
+
+import (
+	"sync"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// receiveBuffer limits the number of the received events that are being processed or waiting to be processed. When
+// the buffer is full, the receiving is blocked or the received event is dropped with the overflow policy.
+//
+// Blocking the receiving stops the transport from reading the next message, e.g. the MQTT messages are not
+// acknowledged and the gRPC stream is not read, so the events are not buffered unbounded in memory.
+type receiveBuffer struct {
+	sync.Mutex
+	cond    *sync.Cond
+	size    int
+	pending int
+	policy  options.ReceiveOverflowPolicy
+	stopped bool
+}
+
+// newReceiveBuffer returns a receiveBuffer with the given size, the blocked receiving is released after the stopCh is
+// closed.
+func newReceiveBuffer(size int, policy options.ReceiveOverflowPolicy, stopCh <-chan struct{}) *receiveBuffer {
+	if len(policy) == 0 {
+		policy = options.ReceiveOverflowBlock
+	}
+
+	b := &receiveBuffer{
+		size:   size,
+		policy: policy,
+	}
+	b.cond = sync.NewCond(&b.Mutex)
+
+	go func() {
+		<-stopCh
+
+		b.Lock()
+		defer b.Unlock()
+		b.stopped = true
+		b.cond.Broadcast()
+	}()
+
+	return b
+}
+
+// reserve reserves the room for a received event, it returns false if the event should be discarded.
+func (b *receiveBuffer) reserve() bool {
+	b.Lock()
+	defer b.Unlock()
+
+	for b.pending >= b.size {
+		if b.policy == options.ReceiveOverflowDrop || b.stopped {
+			return false
+		}
+
+		b.cond.Wait()
+	}
+
+	b.pending++
+	return true
+}
+
+// release releases the room of a received event after the event is processed.
+func (b *receiveBuffer) release() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.pending--
+	b.cond.Signal()
+}
+
+// len returns the number of the received events that are held by the buffer.
+func (b *receiveBuffer) len() int {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.pending
+}
@@ -0,0 +1,85 @@
+package generic
+
+import (
+	"testing"
+	"time"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+func TestReceiveBuffer(t *testing.T) {
+	cases := []struct {
+		name          string
+		policy        options.ReceiveOverflowPolicy
+		expectBlocked bool
+	}{
+		{
+			name:          "block when the buffer is full",
+			policy:        options.ReceiveOverflowBlock,
+			expectBlocked: true,
+		},
+		{
+			name:          "block by default",
+			expectBlocked: true,
+		},
+		{
+			name:   "drop when the buffer is full",
+			policy: options.ReceiveOverflowDrop,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			buffer := newReceiveBuffer(2, c.policy, stopCh)
+			for i := 0; i < 2; i++ {
+				if !buffer.reserve() {
+					t.Fatalf("expected the buffer has room")
+				}
+			}
+
+			reserved := make(chan bool)
+			go func() {
+				reserved <- buffer.reserve()
+			}()
+
+			if !c.expectBlocked {
+				if <-reserved {
+					t.Errorf("expected the event is dropped, but it is reserved")
+				}
+				return
+			}
+
+			select {
+			case <-reserved:
+				t.Fatalf("expected the receiving is blocked")
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			buffer.release()
+			if !<-reserved {
+				t.Errorf("expected the event is reserved after the buffer has room")
+			}
+		})
+	}
+}
+
+func TestReceiveBufferStopped(t *testing.T) {
+	stopCh := make(chan struct{})
+	buffer := newReceiveBuffer(1, options.ReceiveOverflowBlock, stopCh)
+	if !buffer.reserve() {
+		t.Fatalf("expected the buffer has room")
+	}
+
+	reserved := make(chan bool)
+	go func() {
+		reserved <- buffer.reserve()
+	}()
+
+	close(stopCh)
+	if <-reserved {
+		t.Errorf("expected the blocked receiving is released without room after the buffer is stopped")
+	}
+}
@@ -0,0 +1,63 @@
+package generic
+
+import (
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+)
+
+// registrationTracker tracks the registration requests of an agent that are waiting for the registration responses,
+// a registration request is completed by the first response that acknowledges it.
+type registrationTracker struct {
+	sync.Mutex
+	pending map[string]chan *payload.AgentBootstrap
+}
+
+func newRegistrationTracker() *registrationTracker {
+	return &registrationTracker{pending: map[string]chan *payload.AgentBootstrap{}}
+}
+
+// add starts waiting for the response of the registration request with the given event ID, it must be called before
+// the request is sent, so the response that is received before the request is sent returns is not missed.
+func (r *registrationTracker) add(requestID string) <-chan *payload.AgentBootstrap {
+	r.Lock()
+	defer r.Unlock()
+
+	responseChan := make(chan *payload.AgentBootstrap, 1)
+	r.pending[requestID] = responseChan
+	return responseChan
+}
+
+func (r *registrationTracker) remove(requestID string) {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.pending, requestID)
+}
+
+// complete delivers a registration response to the request that it acknowledges, the responses of the requests that
+// are not pending, e.g. the responses of the other sources, are ignored.
+func (r *registrationTracker) complete(evt cloudevents.Event) error {
+	bootstrap, err := payload.DecodeAgentBootstrap(evt)
+	if err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	responseChan, ok := r.pending[bootstrap.RequestID]
+	if !ok {
+		return fmt.Errorf("the registration request %s is not pending", bootstrap.RequestID)
+	}
+
+	select {
+	case responseChan <- bootstrap:
+	default:
+		// the request is already acknowledged
+	}
+	return nil
+}
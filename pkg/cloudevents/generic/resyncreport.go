@@ -0,0 +1,43 @@
+package generic
+
+import (
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// resyncRecorder records a resync request that is being responded, and reports it with a ResyncReporter.
+type resyncRecorder struct {
+	report options.ResyncReport
+	start  time.Time
+}
+
+func newResyncRecorder(eventDataType types.CloudEventsDataType, request cloudevents.Event) *resyncRecorder {
+	return &resyncRecorder{
+		report: options.ResyncReport{
+			EventDataType: eventDataType,
+			ResourceID:    resyncResourceID(request),
+			ReceivedBytes: len(request.Data()),
+		},
+		start: time.Now(),
+	}
+}
+
+// resent records an event that is resent for an out of sync resource.
+func (r *resyncRecorder) resent(evt *cloudevents.Event) {
+	r.report.OutOfSync++
+	r.report.SentBytes += len(evt.Data())
+}
+
+// done reports the resync with the given reporter, the reporter is optional.
+func (r *resyncRecorder) done(reporter options.ResyncReporter) {
+	if reporter == nil {
+		return
+	}
+
+	r.report.Duration = time.Since(r.start)
+	reporter(r.report)
+}
@@ -0,0 +1,32 @@
+package generic
+
+import (
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// resyncResourceID returns the resource ID of a targeted resync request, the targeted resync request is sent by
+// ResyncResource with the `resourceid` extension, it is empty for the resync request of all the resources.
+func resyncResourceID(evt cloudevents.Event) string {
+	val, ok := evt.Extensions()[types.ExtensionResourceID]
+	if !ok {
+		return ""
+	}
+
+	resourceID, err := cloudeventstypes.ToString(val)
+	if err != nil {
+		return ""
+	}
+	return resourceID
+}
+
+// filterResource returns the objects that have the given resource ID.
+func filterResource[T ResourceObject](resourceID string, objs []T) []T {
+	obj, exists := getObj(resourceID, objs)
+	if !exists {
+		return nil
+	}
+	return []T{obj}
+}
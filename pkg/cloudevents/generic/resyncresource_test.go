@@ -0,0 +1,123 @@
+package generic
+
+import (
+	"context"
+	"testing"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+)
+
+func TestResyncResourceStatus(t *testing.T) {
+	resources := []*mockResource{
+		{UID: kubetypes.UID("test1"), ResourceVersion: "2", Status: "test1", Namespace: "cluster1"},
+		{UID: kubetypes.UID("test2"), ResourceVersion: "3", Status: "test2", Namespace: "cluster1"},
+	}
+
+	sourceClient := fake.NewCloudEventsFakeClient()
+	source, err := NewCloudEventSourceClient[*mockResource](context.TODO(),
+		fake.NewSourceOptions(sourceClient, testSourceName), newMockResourceLister(resources...), statusHash,
+		newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := source.ResyncResource(context.TODO(), "cluster1", "test1"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	request := sourceClient.GetSentEvents()[0]
+	if resourceID := resyncResourceID(request); resourceID != "test1" {
+		t.Errorf("expected test1, but got %s", resourceID)
+	}
+	hashes, err := payload.DecodeStatusResyncRequest(request)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(hashes.Hashes) != 1 || hashes.Hashes[0].ResourceID != "test1" {
+		t.Errorf("expected the status hash of test1, but got %v", hashes)
+	}
+
+	// the agent resends the status of the resource even if its status is not changed
+	var reports []options.ResyncReport
+	agentClient := fake.NewCloudEventsFakeClient()
+	agentOptions := fake.NewAgentOptions(agentClient, "cluster1", testAgentName)
+	agentOptions.ResyncReporter = func(report options.ResyncReport) {
+		reports = append(reports, report)
+	}
+	agent, err := NewCloudEventAgentClient[*mockResource](context.TODO(), agentOptions,
+		newMockResourceLister(resources...), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	agent.receive(context.TODO(), request)
+
+	sent := agentClient.GetSentEvents()
+	if len(sent) != 1 || sent[0].Extensions()["resourceid"] != "test1" {
+		t.Errorf("expected the status of test1, but got %v", sent)
+	}
+	if len(reports) != 1 || reports[0].ResourceID != "test1" || reports[0].Resources != 1 {
+		t.Errorf("unexpected reports %v", reports)
+	}
+}
+
+func TestResyncResourceSpec(t *testing.T) {
+	resources := []*mockResource{
+		{UID: kubetypes.UID("test1"), ResourceVersion: "2", Spec: "test1", Namespace: "cluster1"},
+		{UID: kubetypes.UID("test2"), ResourceVersion: "3", Spec: "test2", Namespace: "cluster1"},
+	}
+
+	agentClient := fake.NewCloudEventsFakeClient()
+	agent, err := NewCloudEventAgentClient[*mockResource](context.TODO(),
+		fake.NewAgentOptions(agentClient, "cluster1", testAgentName), newMockResourceLister(resources...), statusHash,
+		newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := agent.ResyncResource(context.TODO(), "cluster2", "test1"); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+
+	if err := agent.ResyncResource(context.TODO(), "cluster1", "test2"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	request := agentClient.GetSentEvents()[0]
+	versions, err := payload.DecodeSpecResyncRequest(request)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(versions.Versions) != 1 || versions.Versions[0].ResourceID != "test2" {
+		t.Errorf("expected the version of test2, but got %v", versions)
+	}
+
+	// the source resends the spec of the resource even if its version is not changed, and the resource that is not
+	// maintained by the source is not deleted by the targeted resync
+	sourceClient := fake.NewCloudEventsFakeClient()
+	source, err := NewCloudEventSourceClient[*mockResource](context.TODO(),
+		fake.NewSourceOptions(sourceClient, testSourceName), newMockResourceLister(resources[0]), statusHash,
+		newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	source.receive(context.TODO(), request)
+	if sent := sourceClient.GetSentEvents(); len(sent) != 0 {
+		t.Errorf("expected no spec events, but got %v", sent)
+	}
+
+	if err := agent.ResyncResource(context.TODO(), "cluster1", "test1"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	source.receive(context.TODO(), agentClient.GetSentEvents()[1])
+	sent := sourceClient.GetSentEvents()
+	if len(sent) != 1 || sent[0].Extensions()["resourceid"] != "test1" {
+		t.Errorf("expected the spec of test1, but got %v", sent)
+	}
+}
@@ -0,0 +1,298 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect of the written schemas.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is a subset of the JSON Schema that describes the event data and the structured events. A schema without
+// a type accepts any value.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 Types                  `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	MinLength            int                    `json:"minLength,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+}
+
+// Types is the type of a JSON schema, a value matches the schema if it is one of the types. It is marshalled to a
+// string if it has only one type.
+type Types []string
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// For returns the JSON schema of the JSON encoded T, see FromType.
+func For[T any]() *JSONSchema {
+	return FromType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// FromType returns the JSON schema of the JSON encoded values of the given type. The schema follows the rules of the
+// encoding/json package:
+//   - the fields of a struct are the properties of an object, they are named with the json tags, and the fields of
+//     the embedded structs are inlined.
+//   - the fields without the omitempty option are required, the pointer, slice and map fields without the omitempty
+//     option can be null.
+//   - the time is a date-time string and the byte slice is a base64 string, the zero Kubernetes time is null.
+//
+// The types that marshal themselves to JSON accept any value, except the unstructured objects and the raw extensions,
+// which are objects. A struct that inherits the marshaller of an embedded field has the schema of the field.
+func FromType(t reflect.Type) *JSONSchema {
+	return (&generator{visiting: map[reflect.Type]bool{}}).schema(t)
+}
+
+var (
+	// the zero metav1.Time and metav1.MicroTime are marshalled to null
+	timeTypes = map[reflect.Type]Types{
+		reflect.TypeOf(time.Time{}):        {"string"},
+		reflect.TypeOf(metav1.Time{}):      {"string", "null"},
+		reflect.TypeOf(metav1.MicroTime{}): {"string", "null"},
+	}
+	objectTypes = map[reflect.Type]bool{
+		reflect.TypeOf(unstructured.Unstructured{}): true,
+		reflect.TypeOf(runtime.RawExtension{}):      true,
+	}
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+type generator struct {
+	// visiting is the types that are being generated, a recursive type accepts any value at its recursion.
+	visiting map[reflect.Type]bool
+}
+
+func (g *generator) schema(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if timeType, ok := timeTypes[t]; ok {
+		return &JSONSchema{Type: slices.Clone(timeType), Format: "date-time"}
+	}
+
+	switch {
+	case objectTypes[t]:
+		return &JSONSchema{Type: Types{"object"}}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		if t.Kind() == reflect.Struct {
+			for i := 0; i < t.NumField(); i++ {
+				if f := t.Field(i); f.Anonymous && (f.Type.Implements(marshalerType) ||
+					reflect.PointerTo(f.Type).Implements(marshalerType)) {
+					return g.schema(f.Type)
+				}
+			}
+		}
+		return &JSONSchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: Types{"number"}}
+	case reflect.String:
+		return &JSONSchema{Type: Types{"string"}}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: Types{"string"}, Format: "byte"}
+		}
+		return &JSONSchema{Type: Types{"array"}, Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: Types{"object"}, AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if g.visiting[t] {
+			return &JSONSchema{}
+		}
+		g.visiting[t] = true
+		defer delete(g.visiting, t)
+
+		s := &JSONSchema{Type: Types{"object"}, Properties: map[string]*JSONSchema{}}
+		g.properties(t, s)
+		sort.Strings(s.Required)
+		return s
+	default:
+		// the interfaces accept any value
+		return &JSONSchema{}
+	}
+}
+
+// properties adds the fields of the struct to the properties of the object schema.
+func (g *generator) properties(t reflect.Type, s *JSONSchema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.properties(ft, s)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		property := g.schema(f.Type)
+		if hasOption(opts, "string") {
+			property = &JSONSchema{Type: Types{"string"}}
+		}
+
+		if hasOption(opts, "omitempty") {
+			s.Properties[name] = property
+			continue
+		}
+
+		switch f.Type.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+			if len(property.Type) > 0 && !slices.Contains(property.Type, "null") {
+				property.Type = append(property.Type, "null")
+			}
+		}
+		s.Properties[name] = property
+		s.Required = append(s.Required, name)
+	}
+}
+
+func hasOption(opts, option string) bool {
+	return slices.Contains(strings.Split(opts, ","), option)
+}
+
+// Validate checks the decoded JSON value with the schema, e.g. a value that is unmarshalled to an interface by the
+// encoding/json package. The properties that are not described by an object schema are allowed unless the schema has
+// the additional properties.
+func (s *JSONSchema) Validate(value any, path *field.Path) field.ErrorList {
+	if len(s.Type) > 0 && !s.matchType(value) {
+		return field.ErrorList{field.Invalid(path, value, fmt.Sprintf("must be of type %s", strings.Join(s.Type, " or ")))}
+	}
+
+	errs := field.ErrorList{}
+	switch v := value.(type) {
+	case string:
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, v) {
+			errs = append(errs, field.NotSupported(path, v, s.Enum))
+		}
+		if len(v) < s.MinLength {
+			errs = append(errs, field.Invalid(path, v, fmt.Sprintf("must be at least %d characters", s.MinLength)))
+		}
+		if s.Pattern != "" {
+			if matched, err := regexp.MatchString(s.Pattern, v); err != nil || !matched {
+				errs = append(errs, field.Invalid(path, v, fmt.Sprintf("must match the pattern %s", s.Pattern)))
+			}
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				errs = append(errs, field.Invalid(path, v, "must be a date-time"))
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				errs = append(errs, s.Items.Validate(item, path.Index(i))...)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				errs = append(errs, field.Required(path.Child(name), ""))
+			}
+		}
+
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if property, ok := s.Properties[name]; ok {
+				errs = append(errs, property.Validate(v[name], path.Child(name))...)
+			} else if s.AdditionalProperties != nil {
+				errs = append(errs, s.AdditionalProperties.Validate(v[name], path.Key(name))...)
+			}
+		}
+	}
+
+	return errs
+}
+
+func (s *JSONSchema) matchType(value any) bool {
+	for _, t := range s.Type {
+		switch v := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case float64:
+			if t == "number" || (t == "integer" && v == math.Trunc(v)) {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case []any:
+			if t == "array" {
+				return true
+			}
+		case map[string]any:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
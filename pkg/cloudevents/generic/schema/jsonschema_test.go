@@ -0,0 +1,167 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type testItem struct {
+	Name string `json:"name"`
+}
+
+type testEmbedded struct {
+	Embedded string `json:"embedded"`
+}
+
+type testObject struct {
+	testEmbedded
+	Name      string            `json:"name"`
+	Count     int64             `json:"count,omitempty"`
+	Ratio     float64           `json:"ratio,omitempty"`
+	Enabled   bool              `json:"enabled,omitempty"`
+	Items     []testItem        `json:"items"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Data      []byte            `json:"data,omitempty"`
+	Time      metav1.Time       `json:"time,omitempty"`
+	Raw       runtime.RawExtension
+	Any       any         `json:"any,omitempty"`
+	Next      *testObject `json:"next,omitempty"`
+	Ignored   string      `json:"-"`
+	unexposed string
+}
+
+func TestFromType(t *testing.T) {
+	actual := For[testObject]()
+
+	expected := &JSONSchema{
+		Type: Types{"object"},
+		Properties: map[string]*JSONSchema{
+			"embedded": {Type: Types{"string"}},
+			"name":     {Type: Types{"string"}},
+			"count":    {Type: Types{"integer"}},
+			"ratio":    {Type: Types{"number"}},
+			"enabled":  {Type: Types{"boolean"}},
+			"items": {Type: Types{"array", "null"}, Items: &JSONSchema{
+				Type:       Types{"object"},
+				Properties: map[string]*JSONSchema{"name": {Type: Types{"string"}}},
+				Required:   []string{"name"},
+			}},
+			"labels": {Type: Types{"object"}, AdditionalProperties: &JSONSchema{Type: Types{"string"}}},
+			"data":   {Type: Types{"string"}, Format: "byte"},
+			"time":   {Type: Types{"string", "null"}, Format: "date-time"},
+			"Raw":    {Type: Types{"object"}},
+			"any":    {},
+			"next":   {},
+		},
+		Required: []string{"Raw", "embedded", "items", "name"},
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		expectedJSON, _ := json.Marshal(expected)
+		actualJSON, _ := json.Marshal(actual)
+		t.Errorf("expected %s, but got %s", string(expectedJSON), string(actualJSON))
+	}
+}
+
+func TestTypesJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		types    Types
+		expected string
+	}{
+		{
+			name:     "single type",
+			types:    Types{"string"},
+			expected: `"string"`,
+		},
+		{
+			name:     "multiple types",
+			types:    Types{"array", "null"},
+			expected: `["array","null"]`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := json.Marshal(c.types)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != c.expected {
+				t.Errorf("expected %s, but got %s", c.expected, string(data))
+			}
+
+			var types Types
+			if err := json.Unmarshal(data, &types); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(types, c.types) {
+				t.Errorf("expected %v, but got %v", c.types, types)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name           string
+		value          string
+		expectedFields []string
+	}{
+		{
+			name:  "valid",
+			value: `{"embedded":"a","name":"b","count":1,"items":[{"name":"c"}],"labels":{"a":"b"},"Raw":{},"time":null}`,
+		},
+		{
+			name:  "null items",
+			value: `{"embedded":"a","name":"b","items":null,"Raw":{}}`,
+		},
+		{
+			name:           "missing required properties",
+			value:          `{"embedded":"a","items":[]}`,
+			expectedFields: []string{"data.Raw", "data.name"},
+		},
+		{
+			name:           "invalid types",
+			value:          `{"embedded":1,"name":"b","count":1.5,"items":[{"name":true}],"labels":{"a":1},"Raw":[]}`,
+			expectedFields: []string{"data.Raw", "data.count", "data.embedded", "data.items[0].name", "data.labels[a]"},
+		},
+		{
+			name:           "invalid time",
+			value:          `{"embedded":"a","name":"b","items":[],"Raw":{},"time":"yesterday"}`,
+			expectedFields: []string{"data.time"},
+		},
+		{
+			name:           "not an object",
+			value:          `[]`,
+			expectedFields: []string{"data"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var value any
+			if err := json.Unmarshal([]byte(c.value), &value); err != nil {
+				t.Fatal(err)
+			}
+
+			errs := For[testObject]().Validate(value, field.NewPath("data"))
+
+			fields := []string{}
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			if len(c.expectedFields) == 0 && len(fields) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(c.expectedFields, fields) {
+				t.Errorf("expected %v, but got %v", c.expectedFields, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// EventSchema describes the events of a CloudEventsDataType.
+type EventSchema struct {
+	// DataType is the cloud event data type of the events.
+	DataType types.CloudEventsDataType
+
+	// Spec is the JSON schema of the data of the spec events.
+	Spec *JSONSchema
+
+	// Status is the JSON schema of the data of the status events.
+	Status *JSONSchema
+}
+
+// Registry is a set of the event schemas of the data types. The events of the resync and registration requests are
+// described by the registry for all the data types.
+type Registry struct {
+	sync.RWMutex
+	schemas map[types.CloudEventsDataType]EventSchema
+}
+
+// NewRegistry returns a Registry of the given event schemas.
+func NewRegistry(schemas ...EventSchema) *Registry {
+	r := &Registry{schemas: map[types.CloudEventsDataType]EventSchema{}}
+	for _, s := range schemas {
+		r.Register(s)
+	}
+	return r
+}
+
+// Register adds an event schema to the registry, the previous schema of the same data type is replaced.
+func (r *Registry) Register(s EventSchema) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.schemas[s.DataType] = s
+}
+
+// Get returns the event schema of the data type.
+func (r *Registry) Get(dataType types.CloudEventsDataType) (EventSchema, bool) {
+	r.RLock()
+	defer r.RUnlock()
+
+	s, ok := r.schemas[dataType]
+	return s, ok
+}
+
+// DataTypes returns the data types of the registered event schemas.
+func (r *Registry) DataTypes() []types.CloudEventsDataType {
+	r.RLock()
+	defer r.RUnlock()
+
+	dataTypes := make([]types.CloudEventsDataType, 0, len(r.schemas))
+	for dataType := range r.schemas {
+		dataTypes = append(dataTypes, dataType)
+	}
+	sort.Slice(dataTypes, func(i, j int) bool {
+		return dataTypes[i].String() < dataTypes[j].String()
+	})
+	return dataTypes
+}
+
+// Validate checks the attributes, the required extensions and the data of the event with the schema of its data type.
+// The data of the encrypted events and the events whose data is offloaded by the claim-check is not checked, and the
+// data of the spec events that delete the resources is optional.
+func (r *Registry) Validate(evt cloudevents.Event) error {
+	errs := field.ErrorList{}
+
+	if err := evt.Validate(); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("context"), evt.Type(), err.Error()))
+	}
+
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("type"), evt.Type(), err.Error()))
+		return &ValidationError{EventID: evt.ID(), EventType: evt.Type(), Errors: errs}
+	}
+
+	errs = append(errs, ValidateExtensions(evt, *eventType)...)
+	errs = append(errs, r.validateData(evt, *eventType)...)
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{EventID: evt.ID(), EventType: evt.Type(), Errors: errs}
+}
+
+func (r *Registry) validateData(evt cloudevents.Event, eventType types.CloudEventsType) field.ErrorList {
+	extensions := evt.Extensions()
+	if _, ok := extensions[types.ExtensionEncryptionKeyID]; ok {
+		return nil
+	}
+	if _, ok := extensions[types.ExtensionClaimCheckKey]; ok {
+		return nil
+	}
+
+	dataPath := field.NewPath("data")
+	dataSchema, err := r.dataSchema(eventType)
+	if err != nil {
+		return field.ErrorList{field.NotSupported(field.NewPath("type"), eventType.CloudEventsDataType.String(),
+			dataTypeStrings(r.DataTypes()))}
+	}
+
+	if len(evt.Data()) == 0 {
+		if _, ok := extensions[types.ExtensionDeletionTimestamp]; ok && eventType.SubResource == types.SubResourceSpec {
+			return nil
+		}
+		return field.ErrorList{field.Required(dataPath, "")}
+	}
+
+	if !isJSON(evt.DataMediaType()) {
+		return field.ErrorList{field.NotSupported(field.NewPath("datacontenttype"), evt.DataMediaType(),
+			[]string{cloudevents.ApplicationJSON})}
+	}
+
+	var data any
+	if err := json.Unmarshal(evt.Data(), &data); err != nil {
+		return field.ErrorList{field.Invalid(dataPath, string(evt.Data()), err.Error())}
+	}
+
+	return dataSchema.Validate(data, dataPath)
+}
+
+// dataSchema returns the JSON schema of the data of the event type.
+func (r *Registry) dataSchema(eventType types.CloudEventsType) (*JSONSchema, error) {
+	switch eventType.Action {
+	case types.RegisterRequestAction:
+		return For[payload.AgentRegistration](), nil
+	case types.RegisterResponseAction:
+		return For[payload.AgentBootstrap](), nil
+	case types.DeliveryReceiptAction:
+		return For[payload.DeliveryReceipt](), nil
+	case types.ResyncCompleteAction:
+		return For[payload.ResourceVersionList](), nil
+	case types.ResyncRequestAction:
+		if eventType.SubResource == types.SubResourceSpec {
+			return For[payload.ResourceVersionList](), nil
+		}
+		return For[payload.ResourceStatusHashList](), nil
+	}
+
+	s, ok := r.Get(eventType.CloudEventsDataType)
+	if !ok {
+		return nil, fmt.Errorf("the schema of the data type %s is not found", eventType.CloudEventsDataType)
+	}
+
+	dataSchema := s.Status
+	if eventType.SubResource == types.SubResourceSpec {
+		dataSchema = s.Spec
+	}
+	if dataSchema == nil {
+		// the data of the data type is not described
+		return &JSONSchema{}, nil
+	}
+	return dataSchema, nil
+}
+
+// JSONSchemas returns the JSON schemas of the structured events of the registered data types, they are keyed by the
+// file names of the schemas. The following events of each data type are described:
+//   - <data type>.spec.json, the spec events that create, update or delete the resources.
+//   - <data type>.status.json, the status events of the resources.
+//   - <data type>.spec.resync_request.json, the spec resync requests of the agents.
+//   - <data type>.status.resync_request.json, the status resync requests of the sources.
+//
+// The registration requests and responses are described with the registration data type.
+func (r *Registry) JSONSchemas() map[string]*JSONSchema {
+	schemas := map[string]*JSONSchema{}
+	for _, dataType := range r.DataTypes() {
+		for _, subResource := range []types.EventSubResource{types.SubResourceSpec, types.SubResourceStatus} {
+			for _, action := range []types.EventAction{"", types.ResyncRequestAction} {
+				eventType := types.CloudEventsType{CloudEventsDataType: dataType, SubResource: subResource, Action: action}
+				id, s := r.eventJSONSchema(eventType)
+				schemas[id] = s
+			}
+		}
+	}
+
+	for _, action := range []types.EventAction{types.RegisterRequestAction, types.RegisterResponseAction} {
+		eventType := types.CloudEventsType{
+			CloudEventsDataType: types.RegistrationDataType,
+			SubResource:         types.SubResourceSpec,
+			Action:              action,
+		}
+		id, s := r.eventJSONSchema(eventType)
+		schemas[id] = s
+	}
+
+	return schemas
+}
+
+// WriteFiles writes the JSON schemas of the registry to the files of the directory, see JSONSchemas.
+func (r *Registry) WriteFiles(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create the schema directory %s, %v", dir, err)
+	}
+
+	for name, s := range r.JSONSchemas() {
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the schema %s, %v", name, err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, name), append(data, '\n'), 0o644); err != nil {
+			return fmt.Errorf("failed to write the schema %s, %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// eventJSONSchema returns the file name and the JSON schema of the structured events of the event type, the events
+// of the resources are described if the action of the event type is empty.
+func (r *Registry) eventJSONSchema(eventType types.CloudEventsType) (string, *JSONSchema) {
+	prefix := fmt.Sprintf("%s.%s", eventType.CloudEventsDataType, eventType.SubResource)
+	id := prefix + ".json"
+	title := fmt.Sprintf("The %s events of %s", eventType.SubResource, eventType.CloudEventsDataType)
+	typeSchema := &JSONSchema{Type: Types{"string"}, Pattern: "^" + regexp.QuoteMeta(prefix+".") + "[a-z_]+$"}
+	if eventType.Action != "" {
+		id = fmt.Sprintf("%s.%s.json", prefix, eventType.Action)
+		title = fmt.Sprintf("The %s %s of %s", eventType.SubResource, strings.ReplaceAll(string(eventType.Action), "_", " "),
+			eventType.CloudEventsDataType)
+		typeSchema = &JSONSchema{Type: Types{"string"}, Enum: []string{eventType.String()}}
+	}
+
+	s := &JSONSchema{
+		Schema: JSONSchemaDraft,
+		ID:     id,
+		Title:  title,
+		Type:   Types{"object"},
+		Properties: map[string]*JSONSchema{
+			"specversion":     {Type: Types{"string"}, Enum: []string{cloudevents.VersionV1}},
+			"id":              {Type: Types{"string"}, MinLength: 1},
+			"source":          {Type: Types{"string"}, MinLength: 1},
+			"type":            typeSchema,
+			"datacontenttype": {Type: Types{"string"}},
+		},
+		Required: []string{"specversion", "id", "source", "type"},
+	}
+
+	required, _ := RequiredExtensions(eventType)
+	for _, extension := range required {
+		property := &JSONSchema{Type: extension.Type}
+		if slices.Contains(extension.Type, "string") && !extension.AllowEmpty {
+			property.MinLength = 1
+		}
+		s.Properties[extension.Name] = property
+		s.Required = append(s.Required, extension.Name)
+	}
+
+	if dataSchema, err := r.dataSchema(eventType); err == nil {
+		s.Properties["data"] = dataSchema
+		if eventType.SubResource == types.SubResourceStatus || eventType.Action != "" {
+			// the data of the spec events is absent when the resources are deleted
+			s.Required = append(s.Required, "data")
+		}
+	}
+
+	sort.Strings(s.Required)
+	return id, s
+}
+
+func dataTypeStrings(dataTypes []types.CloudEventsDataType) []string {
+	strs := make([]string, len(dataTypes))
+	for i, dataType := range dataTypes {
+		strs[i] = dataType.String()
+	}
+	return strs
+}
+
+func isJSON(mediaType string) bool {
+	mediaType = strings.ToLower(mediaType)
+	return mediaType == "" || mediaType == cloudevents.ApplicationJSON || mediaType == event.TextJSON
+}
@@ -0,0 +1,227 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+var testDataType = types.CloudEventsDataType{
+	Group:    "test",
+	Version:  "v1",
+	Resource: "tests",
+}
+
+type testSpec struct {
+	Name string `json:"name"`
+}
+
+type testStatus struct {
+	Phase string `json:"phase"`
+}
+
+func newTestRegistry() *Registry {
+	return NewRegistry(EventSchema{
+		DataType: testDataType,
+		Spec:     For[testSpec](),
+		Status:   For[testStatus](),
+	})
+}
+
+func newTestEvent(subResource types.EventSubResource, action types.EventAction, data any) cloudevents.Event {
+	eventType := types.CloudEventsType{CloudEventsDataType: testDataType, SubResource: subResource, Action: action}
+	evt := types.NewEventBuilder("source1", eventType).
+		WithResourceID("test1").
+		WithResourceVersion(1).
+		WithClusterName("cluster1").
+		WithOriginalSource("source1").
+		NewEvent()
+	if data != nil {
+		if err := evt.SetData(cloudevents.ApplicationJSON, data); err != nil {
+			panic(err)
+		}
+	}
+	return evt
+}
+
+func TestRegistryValidate(t *testing.T) {
+	deleteEvent := newTestEvent(types.SubResourceSpec, "delete_request", nil)
+	deleteEvent.SetExtension(types.ExtensionDeletionTimestamp, time.Now())
+
+	encryptedEvent := newTestEvent(types.SubResourceSpec, "create_request", nil)
+	encryptedEvent.SetExtension(types.ExtensionEncryptionKeyID, "key1")
+
+	missingExtensionEvent := newTestEvent(types.SubResourceStatus, "update_request", map[string]any{"phase": "Ready"})
+	missingExtensionEvent.SetExtension(types.ExtensionOriginalSource, nil)
+
+	unknownDataTypeEvent := newTestEvent(types.SubResourceSpec, "create_request", map[string]any{"name": "test"})
+	unknownDataTypeEvent.SetType("unknown.v1.unknowns.spec.create_request")
+
+	cases := []struct {
+		name           string
+		event          cloudevents.Event
+		expectedFields []string
+	}{
+		{
+			name:  "valid spec event",
+			event: newTestEvent(types.SubResourceSpec, "create_request", map[string]any{"name": "test"}),
+		},
+		{
+			name:  "valid status event",
+			event: newTestEvent(types.SubResourceStatus, "update_request", map[string]any{"phase": "Ready"}),
+		},
+		{
+			name:  "delete event without data",
+			event: deleteEvent,
+		},
+		{
+			name:  "encrypted event",
+			event: encryptedEvent,
+		},
+		{
+			name: "valid resync request",
+			event: newTestEvent(types.SubResourceSpec, types.ResyncRequestAction, &payload.ResourceVersionList{
+				Versions: []payload.ResourceVersion{{ResourceID: "test1", ResourceVersion: 1}},
+			}),
+		},
+		{
+			name:           "invalid resync request",
+			event:          newTestEvent(types.SubResourceStatus, types.ResyncRequestAction, map[string]any{"statusHashes": "a"}),
+			expectedFields: []string{"data.statusHashes"},
+		},
+		{
+			name: "valid delivery receipt",
+			event: newTestEvent(types.SubResourceStatus, types.DeliveryReceiptAction, &payload.DeliveryReceipt{
+				EventID: "event1", ResourceID: "test1", ResourceVersion: "1", Accepted: true,
+			}),
+		},
+		{
+			name: "invalid delivery receipt",
+			event: newTestEvent(types.SubResourceStatus, types.DeliveryReceiptAction, map[string]any{
+				"eventID": "event1", "resourceID": "test1", "accepted": "yes",
+			}),
+			expectedFields: []string{"data.accepted"},
+		},
+		{
+			name:           "invalid spec data",
+			event:          newTestEvent(types.SubResourceSpec, "create_request", map[string]any{"name": 1}),
+			expectedFields: []string{"data.name"},
+		},
+		{
+			name:           "spec event without data",
+			event:          newTestEvent(types.SubResourceSpec, "create_request", nil),
+			expectedFields: []string{"data"},
+		},
+		{
+			name:           "missing extension",
+			event:          missingExtensionEvent,
+			expectedFields: []string{"extensions.originalsource"},
+		},
+		{
+			name:           "unknown data type",
+			event:          unknownDataTypeEvent,
+			expectedFields: []string{"type"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := newTestRegistry().Validate(c.event)
+			if len(c.expectedFields) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, but got %v", err)
+			}
+
+			fields := []string{}
+			for _, err := range validationErr.Errors {
+				fields = append(fields, err.Field)
+			}
+			if !reflect.DeepEqual(c.expectedFields, fields) {
+				t.Errorf("expected %v, but got %v", c.expectedFields, validationErr.Errors)
+			}
+		})
+	}
+}
+
+func TestRegistryWriteFiles(t *testing.T) {
+	dir, err := os.MkdirTemp("", "schemas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := newTestRegistry().WriteFiles(dir); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	expected := []string{
+		"io.open-cluster-management.agents.v1.registrations.spec.register_request.json",
+		"io.open-cluster-management.agents.v1.registrations.spec.register_response.json",
+		"test.v1.tests.spec.json",
+		"test.v1.tests.spec.resync_request.json",
+		"test.v1.tests.status.json",
+		"test.v1.tests.status.resync_request.json",
+	}
+	if !reflect.DeepEqual(expected, names) {
+		t.Errorf("expected %v, but got %v", expected, names)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "test.v1.tests.status.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventSchema := &JSONSchema{}
+	if err := json.Unmarshal(data, eventSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	// the written schema validates the structured status events
+	evt := newTestEvent(types.SubResourceStatus, "update_request", map[string]any{"phase": "Ready"})
+	structured, err := json.Marshal(evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value any
+	if err := json.Unmarshal(structured, &value); err != nil {
+		t.Fatal(err)
+	}
+	if errs := eventSchema.Validate(value, nil); len(errs) != 0 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+
+	delete(value.(map[string]any), types.ExtensionResourceID)
+	value.(map[string]any)["type"] = "test.v1.tests.spec.update_request"
+	if errs := eventSchema.Validate(value, nil); len(errs) != 2 {
+		t.Errorf("expected 2 errors, but got %v", errs)
+	}
+}
@@ -0,0 +1,173 @@
+// Package schema describes the cloudevents of each CloudEventsDataType, the extensions that are required by an event
+// type and the JSON schema of the event data. The schemas are used to validate the received events at runtime, and are
+// written as the JSON Schema files for the consumers that implement the sources or agents in other languages.
+//
+// The schema of a data type is registered to a Registry, e.g.
+//
+//	registry := schema.NewRegistry(schema.EventSchema{
+//		DataType: payload.ManifestBundleEventDataType,
+//		Spec:     schema.For[payload.ManifestBundle](),
+//		Status:   schema.For[payload.ManifestBundleStatus](),
+//	})
+//
+//	if err := registry.Validate(evt); err != nil {
+//		// the event is invalid
+//	}
+package schema
+
+import (
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// ValidationError is returned when an event is invalid, the Errors describe each invalid attribute, extension or data
+// field of the event.
+type ValidationError struct {
+	EventID   string
+	EventType string
+	Errors    field.ErrorList
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid event %s (%s): %v", e.EventID, e.EventType, e.Errors.ToAggregate())
+}
+
+// Extension describes a cloud event extension that is required by an event type.
+type Extension struct {
+	// Name is the name of the extension.
+	Name string
+
+	// Type is the JSON schema types of the extension value in the structured content mode, e.g. the resourceversion
+	// is an integer sequence number or a string version.
+	Type Types
+
+	// AllowEmpty is true if the extension can be empty, e.g. the empty cluster name of a resync request requests the
+	// resources of all clusters.
+	AllowEmpty bool
+}
+
+// RequiredExtensions returns the extensions that are required by the event type:
+//   - the spec and status events require the resourceid and resourceversion extensions, the delivery receipts are
+//     validated as the status events.
+//   - the spec events and the status resync requests require the clustername extension.
+//   - the status events and the spec resync requests require the clustername and originalsource extensions.
+//   - the handover requests require the handovertarget extension, and the claim requests require the handoversource
+//     extension besides the extensions of the spec events.
+//   - the call requests require the clustername, originalsource and replyto extensions, and the call responses require
+//     the clustername, originalsource and correlationid extensions.
+//   - the resync complete events require the clustername and correlationid extensions.
+//
+// The clustername and originalsource extensions of the resync requests can be empty to request all clusters or all
+// sources, and the clustername extension of the spec events that have the broadcast extension can be empty. The false is returned if the subresource of the event type is not supported.
+func RequiredExtensions(eventType types.CloudEventsType) ([]Extension, bool) {
+	switch {
+	case eventType.Action == types.ResyncRequestAction && eventType.SubResource == types.SubResourceSpec:
+		return []Extension{
+			{Name: types.ExtensionClusterName, Type: Types{"string"}, AllowEmpty: true},
+			{Name: types.ExtensionOriginalSource, Type: Types{"string"}, AllowEmpty: true},
+		}, true
+	case eventType.Action == types.ResyncRequestAction && eventType.SubResource == types.SubResourceStatus:
+		return []Extension{
+			{Name: types.ExtensionClusterName, Type: Types{"string"}, AllowEmpty: true},
+		}, true
+	case eventType.Action == types.RegisterRequestAction:
+		return []Extension{
+			{Name: types.ExtensionClusterName, Type: Types{"string"}},
+			{Name: types.ExtensionOriginalSource, Type: Types{"string"}, AllowEmpty: true},
+		}, true
+	case eventType.Action == types.RegisterResponseAction:
+		return []Extension{
+			{Name: types.ExtensionClusterName, Type: Types{"string"}},
+		}, true
+	case eventType.Action == types.CallRequestAction:
+		return []Extension{
+			{Name: types.ExtensionClusterName, Type: Types{"string"}},
+			{Name: types.ExtensionOriginalSource, Type: Types{"string"}},
+			{Name: types.ExtensionReplyTo, Type: Types{"string"}},
+		}, true
+	case eventType.Action == types.CallResponseAction:
+		return []Extension{
+			{Name: types.ExtensionClusterName, Type: Types{"string"}},
+			{Name: types.ExtensionOriginalSource, Type: Types{"string"}},
+			{Name: types.ExtensionCorrelationID, Type: Types{"string"}},
+		}, true
+	case eventType.Action == types.ResyncCompleteAction:
+		return []Extension{
+			{Name: types.ExtensionClusterName, Type: Types{"string"}},
+			{Name: types.ExtensionCorrelationID, Type: Types{"string"}},
+		}, true
+	case eventType.SubResource == types.SubResourceSpec:
+		extensions := []Extension{
+			{Name: types.ExtensionResourceID, Type: Types{"string"}},
+			{Name: types.ExtensionResourceVersion, Type: Types{"integer", "string"}},
+			{Name: types.ExtensionClusterName, Type: Types{"string"}},
+		}
+
+		switch eventType.Action {
+		case types.HandoverRequestAction:
+			extensions = append(extensions, Extension{Name: types.ExtensionHandoverTarget, Type: Types{"string"}, AllowEmpty: true})
+		case types.ClaimRequestAction:
+			extensions = append(extensions, Extension{Name: types.ExtensionHandoverSource, Type: Types{"string"}})
+		}
+
+		return extensions, true
+	case eventType.SubResource == types.SubResourceStatus:
+		return []Extension{
+			{Name: types.ExtensionResourceID, Type: Types{"string"}},
+			{Name: types.ExtensionResourceVersion, Type: Types{"integer", "string"}},
+			{Name: types.ExtensionClusterName, Type: Types{"string"}},
+			{Name: types.ExtensionOriginalSource, Type: Types{"string"}},
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// ValidateExtensions checks the event has the extensions that are required by its event type.
+func ValidateExtensions(evt cloudevents.Event, eventType types.CloudEventsType) field.ErrorList {
+	required, ok := RequiredExtensions(eventType)
+	if !ok {
+		return field.ErrorList{field.NotSupported(field.NewPath("type").Child("subresource"), eventType.SubResource,
+			[]string{string(types.SubResourceSpec), string(types.SubResourceStatus)})}
+	}
+
+	if eventType.SubResource == types.SubResourceSpec && types.IsBroadcast(evt) {
+		// the spec is broadcast to all clusters
+		for i := range required {
+			if required[i].Name == types.ExtensionClusterName {
+				required[i].AllowEmpty = true
+			}
+		}
+	}
+
+	errs := field.ErrorList{}
+	extensions := field.NewPath("extensions")
+	for _, extension := range required {
+		errs = append(errs, requireExtension(evt, extensions, extension)...)
+	}
+	return errs
+}
+
+// requireExtension returns an error if the extension is not set, or its value is empty and it cannot be empty.
+func requireExtension(evt cloudevents.Event, extensions *field.Path, extension Extension) field.ErrorList {
+	value, ok := evt.Extensions()[extension.Name]
+	if !ok {
+		return field.ErrorList{field.Required(extensions.Child(extension.Name), "")}
+	}
+
+	str, err := cloudeventstypes.Format(value)
+	if err != nil {
+		return field.ErrorList{field.Invalid(extensions.Child(extension.Name), value, err.Error())}
+	}
+
+	if str == "" && !extension.AllowEmpty {
+		return field.ErrorList{field.Required(extensions.Child(extension.Name), "")}
+	}
+
+	return nil
+}
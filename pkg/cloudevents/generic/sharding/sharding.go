@@ -0,0 +1,187 @@
+// Package sharding distributes the clusters across the replicas of a horizontally scaled source controller.
+//
+// The clusters are assigned to the replicas with a consistent hash ring, so each replica handles a disjoint subset of
+// the clusters, and only a small part of the clusters are moved between the replicas when a replica joins or leaves.
+// A replica filters the received events of the clusters that it does not own with the ShardFilter of its source
+// options, and handles the clusters that it acquires after a rebalance, e.g. by resyncing their status.
+package sharding
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// DefaultVirtualNodes is the default number of the virtual nodes of each member on the hash ring, the more virtual
+// nodes a member has, the more evenly the clusters are distributed.
+const DefaultVirtualNodes = 100
+
+// Ring is a consistent hash ring of the members, a key is owned by the first virtual node of the members that follows
+// the hash of the key on the ring.
+type Ring struct {
+	members []string
+	hashes  []uint32
+	owners  map[uint32]string
+}
+
+// NewRing returns a hash ring of the given members, each member has the given number of the virtual nodes. If the
+// number of the virtual nodes is less than or equal to zero, DefaultVirtualNodes is used.
+func NewRing(virtualNodes int, members ...string) *Ring {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+
+	members = slices.Clone(members)
+	slices.Sort(members)
+	members = slices.Compact(members)
+
+	r := &Ring{
+		members: members,
+		hashes:  make([]uint32, 0, len(members)*virtualNodes),
+		owners:  make(map[uint32]string, len(members)*virtualNodes),
+	}
+	for _, member := range members {
+		for i := 0; i < virtualNodes; i++ {
+			h := hash(fmt.Sprintf("%s#%d", member, i))
+			if _, ok := r.owners[h]; ok {
+				// the hash collides with another virtual node, keep the first one
+				continue
+			}
+			r.owners[h] = member
+			r.hashes = append(r.hashes, h)
+		}
+	}
+	slices.Sort(r.hashes)
+
+	return r
+}
+
+// Members returns the sorted members of the ring.
+func (r *Ring) Members() []string {
+	return slices.Clone(r.members)
+}
+
+// Owner returns the member that owns the given key, it returns an empty string if the ring has no member.
+func (r *Ring) Owner(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+
+	h := hash(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.owners[r.hashes[i]]
+}
+
+// Rebalance describes a membership change of the replicas, it tells which clusters are moved to or from a replica.
+type Rebalance struct {
+	// Self is the replica that the rebalance is reported to.
+	Self string
+
+	// Previous is the ring before the membership is changed.
+	Previous *Ring
+
+	// Current is the ring after the membership is changed.
+	Current *Ring
+}
+
+// Acquired returns true if the cluster is moved to the replica by the rebalance.
+func (r Rebalance) Acquired(clusterName string) bool {
+	return r.Previous.Owner(clusterName) != r.Self && r.Current.Owner(clusterName) == r.Self
+}
+
+// Released returns true if the cluster is moved from the replica by the rebalance.
+func (r Rebalance) Released(clusterName string) bool {
+	return r.Previous.Owner(clusterName) == r.Self && r.Current.Owner(clusterName) != r.Self
+}
+
+// RebalanceHandler is called after the membership of the replicas is changed.
+type RebalanceHandler func(rebalance Rebalance)
+
+// Sharder decides the clusters that are owned by a replica of a source controller.
+type Sharder struct {
+	sync.RWMutex
+	self         string
+	virtualNodes int
+	ring         *Ring
+	handlers     []RebalanceHandler
+}
+
+// NewSharder returns a sharder of the replica self with the given members, the replica itself is always a member.
+func NewSharder(self string, virtualNodes int, members ...string) *Sharder {
+	return &Sharder{
+		self:         self,
+		virtualNodes: virtualNodes,
+		ring:         NewRing(virtualNodes, append(slices.Clone(members), self)...),
+	}
+}
+
+// Owns returns true if the cluster is owned by the replica, it can be used as the ShardFilter of the source options.
+func (s *Sharder) Owns(clusterName string) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.ring.Owner(clusterName) == s.self
+}
+
+// Filter returns the clusters that are owned by the replica from the given clusters.
+func (s *Sharder) Filter(clusterNames ...string) []string {
+	s.RLock()
+	defer s.RUnlock()
+
+	owned := []string{}
+	for _, clusterName := range clusterNames {
+		if s.ring.Owner(clusterName) == s.self {
+			owned = append(owned, clusterName)
+		}
+	}
+	return owned
+}
+
+// Members returns the sorted members of the replicas.
+func (s *Sharder) Members() []string {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.ring.Members()
+}
+
+// OnRebalance adds a handler that is called after the membership of the replicas is changed.
+func (s *Sharder) OnRebalance(handler RebalanceHandler) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.handlers = append(s.handlers, handler)
+}
+
+// SetMembers updates the members of the replicas, e.g. from the endpoints of the source controller or a membership
+// lease, the replica itself is always a member. The rebalance handlers are called if the membership is changed.
+func (s *Sharder) SetMembers(members ...string) {
+	s.Lock()
+	current := NewRing(s.virtualNodes, append(slices.Clone(members), s.self)...)
+	previous := s.ring
+	if slices.Equal(previous.members, current.members) {
+		s.Unlock()
+		return
+	}
+	s.ring = current
+	handlers := slices.Clone(s.handlers)
+	s.Unlock()
+
+	rebalance := Rebalance{Self: s.self, Previous: previous, Current: current}
+	for _, handler := range handlers {
+		handler(rebalance)
+	}
+}
+
+// hash returns the first 4 bytes of the SHA-256 digest of the key, the similar keys, e.g. cluster1 and cluster2, are
+// spread across the ring.
+func hash(key string) uint32 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint32(sum[:4])
+}
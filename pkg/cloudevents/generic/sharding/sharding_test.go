@@ -0,0 +1,105 @@
+package sharding
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRingOwner(t *testing.T) {
+	if owner := NewRing(0).Owner("cluster1"); owner != "" {
+		t.Errorf("expected no owner, but got %s", owner)
+	}
+
+	ring := NewRing(0, "replica-a", "replica-b", "replica-c", "replica-a")
+	if !reflect.DeepEqual(ring.Members(), []string{"replica-a", "replica-b", "replica-c"}) {
+		t.Errorf("unexpected members %v", ring.Members())
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		clusterName := fmt.Sprintf("cluster%d", i)
+		owner := ring.Owner(clusterName)
+		if owner != ring.Owner(clusterName) {
+			t.Fatalf("expected the owner of %s is stable", clusterName)
+		}
+		counts[owner]++
+	}
+
+	for member, count := range counts {
+		if count < 600 || count > 1400 {
+			t.Errorf("expected the clusters are distributed evenly, but %s owns %d clusters", member, count)
+		}
+	}
+}
+
+func TestRingMembershipChange(t *testing.T) {
+	previous := NewRing(0, "replica-a", "replica-b", "replica-c")
+	current := NewRing(0, "replica-a", "replica-b", "replica-c", "replica-d")
+
+	moved := 0
+	for i := 0; i < 3000; i++ {
+		clusterName := fmt.Sprintf("cluster%d", i)
+		if previous.Owner(clusterName) == current.Owner(clusterName) {
+			continue
+		}
+
+		// the clusters are only moved to the new member
+		if current.Owner(clusterName) != "replica-d" {
+			t.Errorf("expected %s is moved to replica-d, but got %s", clusterName, current.Owner(clusterName))
+		}
+		moved++
+	}
+
+	if moved == 0 || moved > 1500 {
+		t.Errorf("expected a part of the clusters are moved, but got %d", moved)
+	}
+}
+
+func TestSharderRebalance(t *testing.T) {
+	sharderA := NewSharder("replica-a", 0, "replica-b")
+	sharderB := NewSharder("replica-b", 0, "replica-a")
+
+	for i := 0; i < 100; i++ {
+		clusterName := fmt.Sprintf("cluster%d", i)
+		if sharderA.Owns(clusterName) == sharderB.Owns(clusterName) {
+			t.Errorf("expected %s is owned by exactly one replica", clusterName)
+		}
+	}
+
+	var rebalances []Rebalance
+	sharderA.OnRebalance(func(rebalance Rebalance) {
+		rebalances = append(rebalances, rebalance)
+	})
+
+	// the membership is not changed
+	sharderA.SetMembers("replica-b", "replica-a")
+	if len(rebalances) != 0 {
+		t.Fatalf("expected no rebalance, but got %d", len(rebalances))
+	}
+
+	// replica-b leaves, replica-a acquires its clusters
+	sharderA.SetMembers()
+	if len(rebalances) != 1 {
+		t.Fatalf("expected 1 rebalance, but got %d", len(rebalances))
+	}
+	if !reflect.DeepEqual(sharderA.Members(), []string{"replica-a"}) {
+		t.Errorf("unexpected members %v", sharderA.Members())
+	}
+
+	clusters := []string{}
+	for i := 0; i < 100; i++ {
+		clusterName := fmt.Sprintf("cluster%d", i)
+		clusters = append(clusters, clusterName)
+		if rebalances[0].Acquired(clusterName) != sharderB.Owns(clusterName) {
+			t.Errorf("expected %s is acquired if it was owned by replica-b", clusterName)
+		}
+		if rebalances[0].Released(clusterName) {
+			t.Errorf("expected %s is not released", clusterName)
+		}
+	}
+
+	if owned := sharderA.Filter(clusters...); len(owned) != len(clusters) {
+		t.Errorf("expected all clusters are owned, but got %d", len(owned))
+	}
+}
@@ -2,14 +2,18 @@ package generic
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"sync"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/features"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
@@ -21,10 +25,24 @@ import (
 // handling resource requests.
 type CloudEventSourceClient[T ResourceObject] struct {
 	*baseClient
-	lister           Lister[T]
-	codecs           map[types.CloudEventsDataType]Codec[T]
-	statusHashGetter StatusHashGetter[T]
-	sourceID         string
+	lister              Lister[T]
+	store               ResourceStore[T]
+	subscribers         *subscriberRegistry[T]
+	codecs              *CodecRegistry[T]
+	statusHashGetter    StatusHashGetter[T]
+	registrationHandler options.AgentRegistrationHandler
+	sourceID            string
+	resyncConcurrency   int
+	shardFilter         func(clusterName string) bool
+	isLeader            func() bool
+	specCoalescer       *coalescer[T]
+	compareVersions     VersionComparator
+	requestReceipts     bool
+	receipts            *receiptTracker
+	receiptHandler      options.DeliveryReceiptHandler
+	quota               *sourceQuota
+	statusHashAlgorithm string
+	resyncedAction      bool
 }
 
 // NewCloudEventSourceClient returns an instance for CloudEventSourceClient. The following arguments are required to
@@ -41,36 +59,109 @@ func NewCloudEventSourceClient[T ResourceObject](
 	statusHashGetter StatusHashGetter[T],
 	codecs ...Codec[T],
 ) (*CloudEventSourceClient[T], error) {
-	baseClient := &baseClient{
-		cloudEventsOptions:     sourceOptions.CloudEventsOptions,
-		cloudEventsRateLimiter: NewRateLimiter(sourceOptions.EventRateLimit),
-		reconnectedChan:        make(chan struct{}),
+	baseClient, err := newBaseClient(sourceOptions.CloudEventsOptions, sourceOptions.EventRateLimit, sourceOptions.CloudEventsClientOptions)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := baseClient.connect(ctx); err != nil {
 		return nil, err
 	}
 
-	evtCodes := make(map[types.CloudEventsDataType]Codec[T])
-	for _, codec := range codecs {
-		evtCodes[codec.EventDataType()] = codec
+	resyncConcurrency := sourceOptions.ResyncConcurrency
+	if resyncConcurrency <= 0 {
+		resyncConcurrency = options.DefaultResyncConcurrency
+	}
+
+	client := &CloudEventSourceClient[T]{
+		baseClient:          baseClient,
+		lister:              lister,
+		codecs:              NewCodecRegistry(codecs...),
+		statusHashGetter:    statusHashGetter,
+		registrationHandler: sourceOptions.AgentRegistrationHandler,
+		subscribers:         &subscriberRegistry[T]{},
+		sourceID:            sourceOptions.SourceID,
+		resyncConcurrency:   resyncConcurrency,
+		shardFilter:         sourceOptions.ShardFilter,
+		isLeader:            sourceOptions.IsLeader,
+		compareVersions:     versionComparator(sourceOptions.VersionComparator),
+		requestReceipts:     sourceOptions.RequestDeliveryReceipts,
+		receipts:            newReceiptTracker(),
+		receiptHandler:      sourceOptions.DeliveryReceiptHandler,
+		quota:               newSourceQuota(sourceOptions.Quota, baseClient.clock),
+		statusHashAlgorithm: statusHashAlgorithm(sourceOptions.StatusHashAlgorithm),
+		resyncedAction:      sourceOptions.StatusResyncedAction,
+	}
+
+	if sourceOptions.SpecCoalesceWindow > 0 {
+		client.specCoalescer = newCoalescer(sourceOptions.SpecCoalesceWindow, baseClient.clock, nil,
+			func(ctx context.Context, eventType types.CloudEventsType, obj T) error {
+				_, err := client.publishObject(ctx, eventType, obj, nil)
+				return err
+			}, sourceOptions.SpecCoalesceErrorHandler)
+		baseClient.flushers = append(baseClient.flushers, client.specCoalescer.flushAll)
+	}
+
+	return client, nil
+}
+
+// NewCloudEventSourceClientWithStore returns an instance for CloudEventSourceClient that owns the given resource store,
+// the client lists the resources from the store for the resync and saves the received resource status to the store
+// before handling them.
+func NewCloudEventSourceClientWithStore[T ResourceObject](
+	ctx context.Context,
+	sourceOptions *options.CloudEventsSourceOptions,
+	store ResourceStore[T],
+	statusHashGetter StatusHashGetter[T],
+	codecs ...Codec[T],
+) (*CloudEventSourceClient[T], error) {
+	client, err := NewCloudEventSourceClient[T](ctx, sourceOptions, store, statusHashGetter, codecs...)
+	if err != nil {
+		return nil, err
 	}
 
-	return &CloudEventSourceClient[T]{
-		baseClient:       baseClient,
-		lister:           lister,
-		codecs:           evtCodes,
-		statusHashGetter: statusHashGetter,
-		sourceID:         sourceOptions.SourceID,
-	}, nil
+	client.store = store
+	return client, nil
+}
+
+// Store returns the resource store of the client, it is nil if the client does not own a store.
+func (c *CloudEventSourceClient[T]) Store() ResourceStore[T] {
+	return c.store
 }
 
 func (c *CloudEventSourceClient[T]) ReconnectedChan() <-chan struct{} {
 	return c.reconnectedChan
 }
 
+// RegisterCodec registers a codec to the client, the client will encode/decode the events with the event data type of
+// the codec after the registration.
+func (c *CloudEventSourceClient[T]) RegisterCodec(codec Codec[T]) error {
+	return c.codecs.Register(codec)
+}
+
+// EventDataTypes returns the event data types that are registered in the client.
+func (c *CloudEventSourceClient[T]) EventDataTypes() []types.CloudEventsDataType {
+	return c.codecs.EventDataTypes()
+}
+
 // Resync the resources status by sending a status resync request from the current source to a specified cluster.
-func (c *CloudEventSourceClient[T]) Resync(ctx context.Context, clusterName string) error {
+func (c *CloudEventSourceClient[T]) Resync(ctx context.Context, clusterName string) (err error) {
+	if !c.leading() {
+		return fmt.Errorf("%w: the status resync of the cluster %s is sent by the leader", ErrNotLeader, clusterName)
+	}
+
+	if err := c.checkContext(ctx); err != nil {
+		return err
+	}
+
+	done, err := c.startResync(clusterName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		done(err)
+	}()
+
 	// list the resource objects that are maintained by the current source with a specified cluster
 	objs, err := c.lister.List(types.ListOptions{Source: c.sourceID, ClusterName: clusterName})
 	if err != nil {
@@ -91,16 +182,20 @@ func (c *CloudEventSourceClient[T]) Resync(ctx context.Context, clusterName stri
 	}
 
 	// only resync the resources whose event data type is registered
-	for eventDataType := range c.codecs {
+	for _, eventDataType := range c.codecs.EventDataTypes() {
 		eventType := types.CloudEventsType{
 			CloudEventsDataType: eventDataType,
 			SubResource:         types.SubResourceStatus,
 			Action:              types.ResyncRequestAction,
 		}
 
-		evt := types.NewEventBuilder(c.sourceID, eventType).WithClusterName(clusterName).NewEvent()
+		evt := types.NewEventBuilder(c.sourceID, eventType).
+			WithClusterName(clusterName).
+			WithCapabilities(c.featureNegotiator.Local().String()).
+			NewEvent()
+		evt.SetExtension(types.ExtensionStatusHashAlgorithm, c.statusHashAlgorithm)
 		if err := evt.SetData(cloudevents.ApplicationJSON, hashes); err != nil {
-			return fmt.Errorf("failed to set data to cloud event: %v", err)
+			return fmt.Errorf("%w: failed to set data to cloud event: %w", ErrEncode, err)
 		}
 
 		if err := c.publish(ctx, evt); err != nil {
@@ -111,47 +206,325 @@ func (c *CloudEventSourceClient[T]) Resync(ctx context.Context, clusterName stri
 	return nil
 }
 
-// Publish a resource spec from a source to an agent.
+// ResyncResource resyncs the status of a single resource by sending a targeted status resync request from the current
+// source to a specified cluster, so a suspicious resource is resynced without resyncing all the resources of the
+// cluster. The agent always resends the status of the resource if it maintains the resource.
+func (c *CloudEventSourceClient[T]) ResyncResource(ctx context.Context, clusterName, resourceID string) error {
+	if !c.leading() {
+		return fmt.Errorf("%w: the status resync of the resource %s is sent by the leader", ErrNotLeader, resourceID)
+	}
+
+	if err := c.checkContext(ctx); err != nil {
+		return err
+	}
+
+	objs, err := c.lister.List(types.ListOptions{Source: c.sourceID, ClusterName: clusterName})
+	if err != nil {
+		return err
+	}
+
+	hashes := &payload.ResourceStatusHashList{Hashes: []payload.ResourceStatusHash{}}
+	if obj, exists := getObj(resourceID, objs); exists {
+		statusHash, err := c.statusHashGetter(obj)
+		if err != nil {
+			return err
+		}
+
+		hashes.Hashes = append(hashes.Hashes, payload.ResourceStatusHash{
+			ResourceID: resourceID,
+			StatusHash: statusHash,
+		})
+	}
+
+	for _, eventDataType := range c.codecs.EventDataTypes() {
+		eventType := types.CloudEventsType{
+			CloudEventsDataType: eventDataType,
+			SubResource:         types.SubResourceStatus,
+			Action:              types.ResyncRequestAction,
+		}
+
+		evt := types.NewEventBuilder(c.sourceID, eventType).
+			WithResourceID(resourceID).
+			WithClusterName(clusterName).
+			WithCapabilities(c.featureNegotiator.Local().String()).
+			NewEvent()
+		evt.SetExtension(types.ExtensionStatusHashAlgorithm, c.statusHashAlgorithm)
+		if err := evt.SetData(cloudevents.ApplicationJSON, hashes); err != nil {
+			return fmt.Errorf("%w: failed to set data to cloud event: %w", ErrEncode, err)
+		}
+
+		if err := c.publish(ctx, evt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResyncClusters sends the status resync requests from the current source to the given clusters. The requests of the
+// clusters are sent in parallel by a bounded number of workers, see the ResyncConcurrency of the source options. A
+// cluster whose previous resync request is not finished is skipped. It returns an aggregated error of the clusters that
+// are failed to resync.
+func (c *CloudEventSourceClient[T]) ResyncClusters(ctx context.Context, clusterNames ...string) error {
+	errs := make([]error, len(clusterNames))
+	indexes := make(chan int)
+
+	wg := &sync.WaitGroup{}
+	for w := 0; w < min(c.resyncConcurrency, len(clusterNames)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				err := c.Resync(ctx, clusterNames[i])
+				if errors.Is(err, ErrResyncInProgress) {
+					klog.V(4).Infof("skip the resync of the cluster %s, %v", clusterNames[i], err)
+					continue
+				}
+				if err != nil {
+					errs[i] = fmt.Errorf("failed to resync the cluster %s, %v", clusterNames[i], err)
+				}
+			}
+		}()
+	}
+
+	var ctxErr error
+	for i := 0; i < len(clusterNames) && ctxErr == nil; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			ctxErr = fmt.Errorf("the resync is stopped before %d clusters are resynced, %v", len(clusterNames)-i, ctx.Err())
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return utilerrors.NewAggregate(append(errs, ctxErr))
+}
+
+// Publish a resource spec from a source to an agent. If the spec coalescing is enabled, the spec is published
+// asynchronously at the end of the coalescing window of the resource, see the SpecCoalesceWindow of the options, the
+// errors of the coalesced spec are passed to the SpecCoalesceErrorHandler of the options.
 func (c *CloudEventSourceClient[T]) Publish(ctx context.Context, eventType types.CloudEventsType, obj T) error {
 	if eventType.SubResource != types.SubResourceSpec {
-		return fmt.Errorf("unsupported event eventType %s", eventType)
+		return fmt.Errorf("%w: unsupported event eventType %s", ErrUnsupportedType, eventType)
+	}
+
+	if err := c.checkContext(ctx); err != nil {
+		return err
+	}
+
+	// the specs in the dry-run mode are published directly, so the errors are returned to the callers
+	if c.specCoalescer != nil && !isDryRun(ctx) {
+		if !c.leading() {
+			return fmt.Errorf("%w: the resource %s is published by the leader", ErrNotLeader, obj.GetUID())
+		}
+		if _, ok := c.codecs.Get(eventType.CloudEventsDataType); !ok {
+			return fmt.Errorf("%w: failed to find a codec for event %s", ErrUnsupportedType, eventType.CloudEventsDataType)
+		}
+		return c.specCoalescer.add(ctx, eventType, obj)
 	}
 
-	codec, ok := c.codecs[eventType.CloudEventsDataType]
+	_, err := c.publishObject(ctx, eventType, obj, nil)
+	return err
+}
+
+// Handover hands a resource over to the target source, e.g. when the resources are migrated to a new hub. The agent
+// marks the resource as migrating, and the resource is kept on the agent until the target source claims it with Claim,
+// then the agent switches the original source of the resource to the target source without recreating it. The current
+// source must keep the resource until it is claimed, after that, the spec events of the current source for the
+// resource are discarded by the agent. An empty target source cancels the handover.
+func (c *CloudEventSourceClient[T]) Handover(
+	ctx context.Context, eventDataType types.CloudEventsDataType, obj T, targetSource string) error {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: eventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.HandoverRequestAction,
+	}
+
+	_, err := c.publishObject(ctx, eventType, obj, map[string]any{types.ExtensionHandoverTarget: targetSource})
+	return err
+}
+
+// Claim claims a resource that is handed over from the previous source, the resource is published with the spec of
+// the current source, and the agent only accepts the claim after the previous source handed the resource over to the
+// current source.
+func (c *CloudEventSourceClient[T]) Claim(
+	ctx context.Context, eventDataType types.CloudEventsDataType, obj T, previousSource string) error {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: eventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ClaimRequestAction,
+	}
+
+	_, err := c.publishObject(ctx, eventType, obj, map[string]any{types.ExtensionHandoverSource: previousSource})
+	return err
+}
+
+// publishObject encodes the resource object to an event with the given extensions and publishes it, the published
+// event is returned.
+func (c *CloudEventSourceClient[T]) publishObject(ctx context.Context,
+	eventType types.CloudEventsType, obj T, extensions map[string]any) (*cloudevents.Event, error) {
+	evt, err := c.encodeObject(eventType, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.publishEncoded(ctx, evt, obj, extensions); err != nil {
+		return nil, err
+	}
+
+	return evt, nil
+}
+
+// encodeObject encodes the resource object to an event by the leader.
+func (c *CloudEventSourceClient[T]) encodeObject(eventType types.CloudEventsType, obj T) (*cloudevents.Event, error) {
+	if !c.leading() {
+		return nil, fmt.Errorf("%w: the resource %s is published by the leader", ErrNotLeader, obj.GetUID())
+	}
+
+	codec, ok := c.codecs.Get(eventType.CloudEventsDataType)
 	if !ok {
-		return fmt.Errorf("failed to find the codec for event %s", eventType.CloudEventsDataType)
+		return nil, fmt.Errorf("%w: failed to find the codec for event %s", ErrUnsupportedType, eventType.CloudEventsDataType)
 	}
 
 	evt, err := codec.Encode(c.sourceID, eventType, obj)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to encode the resource %s, %w", ErrEncode, obj.GetUID(), err)
+	}
+
+	return evt, nil
+}
+
+// publishEncoded publishes the encoded event of the resource object with the given extensions.
+func (c *CloudEventSourceClient[T]) publishEncoded(ctx context.Context,
+	evt *cloudevents.Event, obj T, extensions map[string]any) error {
+	releaseQuota, err := c.reserveQuota(ctx, *evt, obj)
 	if err != nil {
 		return err
 	}
 
+	for name, value := range extensions {
+		evt.SetExtension(name, value)
+	}
+
+	if c.requestReceipts {
+		evt.SetExtension(types.ExtensionAckRequested, true)
+		if !obj.GetDeletionTimestamp().IsZero() && !isDryRun(ctx) {
+			clusterName, _ := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+			c.receipts.delete(clusterName, string(obj.GetUID()), obj.GetResourceVersion())
+		}
+	}
+
 	if err := c.publish(ctx, *evt); err != nil {
+		// the resource is not sent, give its reserved quota back
+		releaseQuota()
 		return err
 	}
 
+	if !isDryRun(ctx) {
+		c.observePublished(*evt, obj)
+	}
 	return nil
 }
 
 // Subscribe the events that are from the agent spec resync request or agent resource status request.
 // For spec resync request, source publish the current resources spec back as response.
 // For resource status request, source receives resource status and handles the status with resource handlers.
-func (c *CloudEventSourceClient[T]) Subscribe(ctx context.Context, handlers ...ResourceHandler[T]) {
-	c.subscribe(ctx, func(ctx context.Context, evt cloudevents.Event) {
-		c.receive(ctx, evt, handlers...)
-	})
+//
+// Subscribe can be called multiple times, the resource status is handled by the handlers of all the subscriptions.
+// A subscription is removed when its context is done or it is unsubscribed, and the client keeps receiving events
+// until it is closed or the context that it is created with is done.
+func (c *CloudEventSourceClient[T]) Subscribe(ctx context.Context, handlers ...ResourceHandler[T]) Subscription {
+	return c.subscribeWith(ctx, nil, handlers...)
+}
+
+// SubscribeWithOptions is same as Subscribe, but only the resource status events that match the given options are
+// handled with the resource handlers of the subscription.
+func (c *CloudEventSourceClient[T]) SubscribeWithOptions(
+	ctx context.Context, opts SubscriptionOptions, handlers ...ResourceHandler[T]) Subscription {
+	return c.subscribeWith(ctx, &opts, handlers...)
+}
+
+// SubscribeWithEvents is same as Subscribe, but the received status events are passed to the event handlers alongside
+// the resource objects that are decoded from them.
+func (c *CloudEventSourceClient[T]) SubscribeWithEvents(ctx context.Context, handlers ...EventHandler[T]) Subscription {
+	return c.subscribers.addEventHandlers(ctx, nil, handlers, c.startReceiving())
+}
+
+func (c *CloudEventSourceClient[T]) subscribeWith(
+	ctx context.Context, opts *SubscriptionOptions, handlers ...ResourceHandler[T]) Subscription {
+	return c.subscribers.add(ctx, opts, handlers, c.startReceiving())
+}
+
+// startReceiving returns the func that starts receiving the events for the first subscription, the events are received
+// until the client is closed or its context is done, so the other subscriptions are not stopped with the first one.
+func (c *CloudEventSourceClient[T]) startReceiving() func() {
+	return func() {
+		c.subscribe(c.ctx, func(ctx context.Context, evt cloudevents.Event) {
+			c.receive(ctx, evt, c.subscribers.handlers(ctx, evt)...)
+		})
+	}
 }
 
 func (c *CloudEventSourceClient[T]) receive(ctx context.Context, evt cloudevents.Event, handlers ...ResourceHandler[T]) {
 	klog.V(4).Infof("Received event:\n%s", evt)
 
+	if c.discardDuplicated(evt) {
+		return
+	}
+
 	eventType, err := types.ParseCloudEventsType(evt.Type())
 	if err != nil {
 		klog.Errorf("failed to parse cloud event type, %v", err)
 		return
 	}
 
+	if eventType.Action == types.CallResponseAction {
+		// the response is handled by the replica that sends the call request
+		if err := c.calls.complete(evt); err != nil {
+			klog.V(4).Infof("ignore the call response %s, %v", evt.ID(), err)
+		}
+
+		return
+	}
+
+	if !c.ownsCluster(evt) {
+		// the cluster is handled by another replica of the source
+		klog.V(4).Infof("ignore the event %s of the cluster that is not owned by the shard", evt.ID())
+		return
+	}
+
+	if eventType.Action == types.CallRequestAction {
+		if err := c.respondCallRequest(ctx, evt); err != nil {
+			klog.Errorf("failed to respond the call request %s, %v", evt.ID(), err)
+		}
+
+		return
+	}
+
+	if eventType.Action == types.DeliveryReceiptAction {
+		if err := c.handleDeliveryReceipt(evt); err != nil {
+			klog.Errorf("failed to handle the delivery receipt %s, %v", evt.ID(), err)
+		}
+
+		return
+	}
+
+	if (eventType.Action == types.RegisterRequestAction || eventType.Action == types.ResyncRequestAction) &&
+		!c.leading() {
+		// the request is responded by the leader replica of the source
+		klog.V(4).Infof("ignore the %s request %s on the standby replica", eventType.Action, evt.ID())
+		return
+	}
+
+	if eventType.Action == types.RegisterRequestAction {
+		if err := c.respondRegistrationRequest(ctx, evt); err != nil {
+			klog.Errorf("failed to respond the registration request, %v", err)
+		}
+
+		return
+	}
+
 	if eventType.Action == types.ResyncRequestAction {
 		if eventType.SubResource != types.SubResourceSpec {
 			klog.Warningf("unsupported event type %s, ignore", eventType)
@@ -165,7 +538,11 @@ func (c *CloudEventSourceClient[T]) receive(ctx context.Context, evt cloudevents
 		return
 	}
 
-	codec, ok := c.codecs[eventType.CloudEventsDataType]
+	if c.discardExpired(evt) {
+		return
+	}
+
+	codec, ok := c.codecs.Get(eventType.CloudEventsDataType)
 	if !ok {
 		klog.Warningf("failed to find the codec for event %s, ignore", eventType.CloudEventsDataType)
 		return
@@ -188,7 +565,7 @@ func (c *CloudEventSourceClient[T]) receive(ctx context.Context, evt cloudevents
 		return
 	}
 
-	action, err := c.statusAction(fmt.Sprintf("%s", clusterName), obj)
+	action, err := c.statusAction(fmt.Sprintf("%s", clusterName), eventType.Action, obj)
 	if err != nil {
 		klog.Errorf("failed to generate status event %s, %v", evt, err)
 		return
@@ -199,11 +576,116 @@ func (c *CloudEventSourceClient[T]) receive(ctx context.Context, evt cloudevents
 		return
 	}
 
-	for _, handler := range handlers {
-		if err := handler(action, obj); err != nil {
-			klog.Errorf("failed to handle status event %s, %v", evt, err)
+	if c.store != nil {
+		if err := c.store.UpsertStatus(obj); err != nil {
+			klog.Errorf("failed to save status event %s, %v", evt, err)
+			return
 		}
 	}
+
+	for index, handler := range handlers {
+		handler := handler
+		c.handlerInvoker.invoke(ctx, evt, fmt.Sprintf("%s/%d", obj.GetUID(), index), func() error {
+			return handler(action, obj)
+		}, nil)
+	}
+}
+
+// respondRegistrationRequest responds the registration request of an agent with the bootstrap payload that is returned
+// by the registration handler, the agent is rejected if the handler returns an error. The registration requests are
+// ignored if the source does not have a registration handler, so they can be responded by the other sources.
+func (c *CloudEventSourceClient[T]) respondRegistrationRequest(ctx context.Context, evt cloudevents.Event) error {
+	if c.registrationHandler == nil {
+		klog.V(4).Infof("no registration handler, ignore the registration request %s", evt.ID())
+		return nil
+	}
+
+	registration, err := payload.DecodeAgentRegistration(evt)
+	if err != nil {
+		return err
+	}
+
+	clusterName, err := evt.Context.GetExtension(types.ExtensionClusterName)
+	if err != nil {
+		return err
+	}
+
+	capabilities := make([]features.Feature, len(registration.Capabilities))
+	for i, capability := range registration.Capabilities {
+		capabilities[i] = features.Feature(capability)
+	}
+	c.featureNegotiator.Observe(fmt.Sprintf("%s", clusterName), features.NewSet(capabilities...))
+
+	bootstrap, err := c.registrationHandler(ctx, *registration)
+	if err != nil {
+		bootstrap = &payload.AgentBootstrap{Message: err.Error()}
+	} else if bootstrap == nil {
+		bootstrap = &payload.AgentBootstrap{Accepted: true}
+	}
+	bootstrap.RequestID = evt.ID()
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: types.RegistrationDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.RegisterResponseAction,
+	}
+
+	responseEvt := types.NewEventBuilder(c.sourceID, eventType).
+		WithClusterName(fmt.Sprintf("%s", clusterName)).
+		NewEvent()
+	if err := responseEvt.SetData(cloudevents.ApplicationJSON, bootstrap); err != nil {
+		return fmt.Errorf("%w: failed to set data to cloud event: %w", ErrEncode, err)
+	}
+
+	return c.publish(ctx, responseEvt)
+}
+
+// Call sends a call request to the agent of the given cluster and waits for the call response, the request is sent as
+// the call request of its data type, e.g. a request of the type `io.open-cluster-management.mustgather.v1alpha1.bundles.spec.collect`
+// is sent with the type `io.open-cluster-management.mustgather.v1alpha1.bundles.spec.call_request`. The call fails with
+// ErrCallTimeout if the response is not received within the CallTimeout of the options, and with ErrCallFailed if the
+// agent responds an error.
+func (c *CloudEventSourceClient[T]) Call(ctx context.Context, clusterName string, request cloudevents.Event) (cloudevents.Event, error) {
+	return c.call(ctx, request, c.sourceID, types.SubResourceSpec, clusterName, c.sourceID, c.sourceID)
+}
+
+// respondCallRequest responds the call request of an agent, the response is sent to the cluster of the agent.
+func (c *CloudEventSourceClient[T]) respondCallRequest(ctx context.Context, evt cloudevents.Event) error {
+	clusterName, err := replyTo(evt)
+	if err != nil {
+		return err
+	}
+
+	return c.respondCall(ctx, evt, c.sourceID, types.SubResourceSpec, clusterName, c.sourceID)
+}
+
+// DeliveryReceipt returns the last delivery receipt of the resource on the given cluster, it returns false if no
+// receipt is received, e.g. the spec is not delivered to the agent yet or the RequestDeliveryReceipts of the options is
+// not enabled. A receipt whose resource version is the version of the resource means the spec is delivered to the agent,
+// and the spec is applied once the agent reports the status of the version.
+func (c *CloudEventSourceClient[T]) DeliveryReceipt(clusterName, resourceID string) (payload.DeliveryReceipt, bool) {
+	return c.receipts.get(clusterName, resourceID)
+}
+
+// handleDeliveryReceipt keeps the delivery receipt of an agent and handles it with the delivery receipt handler.
+func (c *CloudEventSourceClient[T]) handleDeliveryReceipt(evt cloudevents.Event) error {
+	clusterName, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+	if err != nil {
+		return fmt.Errorf("failed to get the cluster name, %v", err)
+	}
+
+	receipt, err := payload.DecodeDeliveryReceipt(evt)
+	if err != nil {
+		return err
+	}
+
+	c.receipts.record(clusterName, *receipt)
+
+	if c.receiptHandler != nil {
+		c.receiptHandler(clusterName, *receipt)
+	}
+
+	return nil
 }
 
 // Upon receiving the spec resync event, the source responds by sending resource status events to the broker as follows:
@@ -214,8 +696,11 @@ func (c *CloudEventSourceClient[T]) receive(ctx context.Context, evt cloudevents
 //     resend the resource.
 //   - If the requested resource version is older than the source's current maintained resource version, the source
 //     sends the resource.
+//   - If the request event message has the resource ID, the source always sends the resource with the ID.
 func (c *CloudEventSourceClient[T]) respondResyncSpecRequest(
 	ctx context.Context, evtDataType types.CloudEventsDataType, evt cloudevents.Event) error {
+	recorder := newResyncRecorder(evtDataType, evt)
+
 	resourceVersions, err := payload.DecodeSpecResyncRequest(evt)
 	if err != nil {
 		return err
@@ -232,22 +717,30 @@ func (c *CloudEventSourceClient[T]) respondResyncSpecRequest(
 		return err
 	}
 
+	// the agent advertises its features with the resync request
+	c.featureNegotiator.Observe(fmt.Sprintf("%s", clusterName), features.FromEvent(evt))
+
 	objs, err := c.lister.List(types.ListOptions{ClusterName: fmt.Sprintf("%s", clusterName), Source: c.sourceID})
 	if err != nil {
 		return err
 	}
 
-	for _, obj := range objs {
-		lastResourceVersion := findResourceVersion(string(obj.GetUID()), resourceVersions.Versions)
-		currentResourceVersion, err := strconv.ParseInt(obj.GetResourceVersion(), 10, 64)
-		if err != nil {
-			continue
-		}
+	resourceID := resyncResourceID(evt)
+	if len(resourceID) != 0 {
+		objs = filterResource(resourceID, objs)
+	}
+
+	recorder.report.ClusterName = fmt.Sprintf("%s", clusterName)
+	recorder.report.Resources = len(objs)
 
-		if currentResourceVersion > lastResourceVersion {
-			if err := c.Publish(ctx, eventType, obj); err != nil {
+	for _, obj := range objs {
+		// the targeted resync request always resends the spec of the resource
+		if len(resourceID) != 0 || c.isNewer(obj, resourceVersions.Versions) {
+			resentEvt, err := c.publishObject(ctx, eventType, obj, nil)
+			if err != nil {
 				return err
 			}
+			recorder.resent(resentEvt)
 		}
 	}
 
@@ -258,28 +751,94 @@ func (c *CloudEventSourceClient[T]) respondResyncSpecRequest(
 			continue
 		}
 
+		if len(resourceID) != 0 {
+			// the targeted resync request is broadcast to all the sources, so a source cannot tell whether a resource
+			// that it does not maintain belongs to another source, the resource is deleted by a full resync instead
+			continue
+		}
+
 		// send a delete event for the current resource
 		evt := types.NewEventBuilder(c.sourceID, eventType).
 			WithResourceID(rv.ResourceID).
-			WithResourceVersion(rv.ResourceVersion).
+			WithStringResourceVersion(rv.GetVersion()).
 			WithClusterName(fmt.Sprintf("%s", clusterName)).
 			WithDeletionTimestamp(metav1.Now().Time).
 			NewEvent()
 		if err := c.publish(ctx, evt); err != nil {
 			return err
 		}
+		recorder.resent(&evt)
 	}
 
+	if len(resourceID) == 0 && c.featureNegotiator.Enabled(fmt.Sprintf("%s", clusterName), features.ResyncBarrier) {
+		if err := c.sendResyncComplete(ctx, evtDataType, fmt.Sprintf("%s", clusterName), evt.ID(), objs); err != nil {
+			return err
+		}
+	}
+
+	recorder.done(c.resyncReporter)
 	return nil
 }
 
-func (c *CloudEventSourceClient[T]) statusAction(clusterName string, obj T) (evt types.ResourceAction, err error) {
-	objs, err := c.lister.List(types.ListOptions{ClusterName: clusterName, Source: c.sourceID})
+// sendResyncComplete marks the end of the resync response of a spec resync request with a resync complete event, the
+// event carries the versions of the resources that the source maintains for the cluster, so the agent knows which
+// resources it should have before its spec resync is completed.
+func (c *CloudEventSourceClient[T]) sendResyncComplete(ctx context.Context, evtDataType types.CloudEventsDataType,
+	clusterName, requestID string, objs []T) error {
+	resources := &payload.ResourceVersionList{Versions: []payload.ResourceVersion{}}
+	for _, obj := range objs {
+		if !obj.GetDeletionTimestamp().IsZero() {
+			// the deleting resource is removed from the agent
+			continue
+		}
+		resources.Versions = append(resources.Versions,
+			payload.NewResourceVersion(string(obj.GetUID()), obj.GetResourceVersion()))
+	}
+
+	evt := types.NewEventBuilder(c.sourceID, types.CloudEventsType{
+		CloudEventsDataType: evtDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncCompleteAction,
+	}).WithClusterName(clusterName).NewEvent()
+	evt.SetExtension(types.ExtensionCorrelationID, requestID)
+	if err := evt.SetData(cloudevents.ApplicationJSON, resources); err != nil {
+		return fmt.Errorf("%w: failed to set data to cloud event: %w", ErrEncode, err)
+	}
+
+	return c.publish(ctx, evt)
+}
+
+// ownsCluster returns true if the cluster of the event is handled by the client, the events without a cluster name are
+// handled by all the replicas of the source.
+func (c *CloudEventSourceClient[T]) ownsCluster(evt cloudevents.Event) bool {
+	if c.shardFilter == nil {
+		return true
+	}
+
+	clusterName, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+	if err != nil || clusterName == types.ClusterAll {
+		return true
+	}
+
+	return c.shardFilter(clusterName)
+}
+
+// leading returns true if the client is the leader replica of the source, the client is always the leader if the
+// source is not deployed with a leader election.
+func (c *CloudEventSourceClient[T]) leading() bool {
+	return c.isLeader == nil || c.isLeader()
+}
+
+// statusAction returns the action of a received status with the last resource of the source, the status of a resource
+// that the source does not have is ignored, and the status that has the same status hash as the last resource is
+// ignored unless it is resent by a resync response and the StatusResyncedAction of the options is enabled.
+func (c *CloudEventSourceClient[T]) statusAction(
+	clusterName string, eventAction types.EventAction, obj T) (evt types.ResourceAction, err error) {
+	lastObj, exists, err := c.lastObj(clusterName, obj)
 	if err != nil {
 		return evt, err
 	}
 
-	lastObj, exists := getObj(string(obj.GetUID()), objs)
 	if !exists {
 		return evt, nil
 	}
@@ -297,18 +856,55 @@ func (c *CloudEventSourceClient[T]) statusAction(clusterName string, obj T) (evt
 	}
 
 	if lastStatusHash == currentStatusHash {
+		if c.resyncedAction && eventAction == types.ResyncResponseAction {
+			return types.StatusResynced, nil
+		}
 		return evt, nil
 	}
 
 	return types.StatusModified, nil
 }
 
-func findResourceVersion(id string, versions []payload.ResourceVersion) int64 {
+// lastObj gets the last resource object of the given object, if the client owns a store, the object is got from the
+// store directly.
+func (c *CloudEventSourceClient[T]) lastObj(clusterName string, obj T) (T, bool, error) {
+	if c.store != nil {
+		return c.store.Get(string(obj.GetUID()))
+	}
+
+	objs, err := c.lister.List(types.ListOptions{ClusterName: clusterName, Source: c.sourceID})
+	if err != nil {
+		var lastObj T
+		return lastObj, false, err
+	}
+
+	lastObj, exists := getObj(string(obj.GetUID()), objs)
+	return lastObj, exists, nil
+}
+
+// isNewer returns true if the resource is not in the resource versions of a spec resync request, or its resource
+// version is newer than the requested resource version. The resource versions that cannot be compared are newer.
+func (c *CloudEventSourceClient[T]) isNewer(obj T, versions []payload.ResourceVersion) bool {
+	lastResourceVersion, ok := findResourceVersion(string(obj.GetUID()), versions)
+	if !ok {
+		return true
+	}
+
+	result, err := c.compareVersions(obj.GetResourceVersion(), lastResourceVersion)
+	if err != nil {
+		klog.V(4).Infof("failed to compare the resource versions of the resource %s, %v", obj.GetUID(), err)
+		return true
+	}
+
+	return result > 0
+}
+
+func findResourceVersion(id string, versions []payload.ResourceVersion) (string, bool) {
 	for _, version := range versions {
 		if id == version.ResourceID {
-			return version.ResourceVersion
+			return version.GetVersion(), true
 		}
 	}
 
-	return 0
+	return "", false
 }
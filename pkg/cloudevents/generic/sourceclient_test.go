@@ -2,14 +2,26 @@ package generic
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/features"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
@@ -63,10 +75,147 @@ func TestSourceResync(t *testing.T) {
 			if len(resourceList.Hashes) != c.expectedItems {
 				t.Errorf("expected %d, but got %v", c.expectedItems, resourceList)
 			}
+
+			if algorithm := resyncStatusHashAlgorithm(evt); algorithm != StatusHashAlgorithmSHA256 {
+				t.Errorf("expected %s, but got %s", StatusHashAlgorithmSHA256, algorithm)
+			}
 		})
 	}
 }
 
+func TestSourceResyncClusters(t *testing.T) {
+	clusterNames := []string{}
+	for i := 0; i < 50; i++ {
+		clusterNames = append(clusterNames, fmt.Sprintf("cluster%d", i))
+	}
+
+	cases := []struct {
+		name                string
+		concurrency         int
+		cancel              bool
+		expectedErr         bool
+		expectedSentCluster int
+	}{
+		{
+			name:                "default concurrency",
+			expectedSentCluster: len(clusterNames),
+		},
+		{
+			name:                "bounded concurrency",
+			concurrency:         4,
+			expectedSentCluster: len(clusterNames),
+		},
+		{
+			name:        "context is canceled",
+			concurrency: 4,
+			cancel:      true,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fakeClient := fake.NewCloudEventsFakeClient()
+			sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+			sourceOptions.ResyncConcurrency = c.concurrency
+			lister := newMockResourceLister(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1"})
+			source, err := NewCloudEventSourceClient[*mockResource](context.TODO(), sourceOptions, lister, statusHash, newMockResourceCodec())
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			if c.cancel {
+				cancel()
+			}
+			defer cancel()
+
+			err = source.ResyncClusters(ctx, clusterNames...)
+			if c.expectedErr && err == nil {
+				t.Errorf("expected error, but got nil")
+			}
+			if !c.expectedErr && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+
+			if c.cancel {
+				return
+			}
+
+			sentClusters := map[string]bool{}
+			for _, evt := range fakeClient.GetSentEvents() {
+				clusterName, err := evt.Context.GetExtension(types.ExtensionClusterName)
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				sentClusters[fmt.Sprintf("%s", clusterName)] = true
+			}
+			if len(sentClusters) != c.expectedSentCluster {
+				t.Errorf("expected %d, but got %d", c.expectedSentCluster, len(sentClusters))
+			}
+		})
+	}
+}
+
+func TestSourceResyncStatus(t *testing.T) {
+	completed := map[string]error{}
+	fakeClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	sourceOptions.OnResyncComplete = func(target string, err error) {
+		completed[target] = err
+	}
+	source, err := NewCloudEventSourceClient[*mockResource](context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !source.ResyncStatus().LastResyncTime.IsZero() {
+		t.Errorf("expected no resync, but got %v", source.ResyncStatus())
+	}
+
+	if err := source.Resync(context.TODO(), "cluster1"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// simulate a failed resync of another cluster
+	done, err := source.startResync("cluster2")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if source.ResyncStatus().Targets["cluster2"].InProgress != true {
+		t.Errorf("expected the resync of cluster2 is in progress, but got %v", source.ResyncStatus())
+	}
+	done(fmt.Errorf("failed"))
+
+	status := source.ResyncStatus()
+	if status.LastResyncTime.IsZero() {
+		t.Errorf("expected the last resync time is set")
+	}
+
+	cluster1 := status.Targets["cluster1"]
+	if cluster1.InProgress || cluster1.LastSuccessTime.IsZero() || cluster1.LastError != nil || cluster1.ConsecutiveFailures != 0 {
+		t.Errorf("unexpected status of cluster1 %v", cluster1)
+	}
+
+	cluster2 := status.Targets["cluster2"]
+	if cluster2.InProgress || !cluster2.LastSuccessTime.IsZero() || cluster2.LastError == nil || cluster2.ConsecutiveFailures != 1 {
+		t.Errorf("unexpected status of cluster2 %v", cluster2)
+	}
+
+	if len(completed) != 2 || completed["cluster1"] != nil || completed["cluster2"] == nil {
+		t.Errorf("unexpected completed resyncs %v", completed)
+	}
+
+	stale := status.StaleTargets(0)
+	if len(stale) != 2 {
+		t.Errorf("expected 2 stale targets, but got %v", stale)
+	}
+	stale = status.StaleTargets(time.Hour)
+	if len(stale) != 0 {
+		t.Errorf("expected no stale targets, but got %v", stale)
+	}
+}
+
 func TestSourcePublish(t *testing.T) {
 	cases := []struct {
 		name      string
@@ -79,6 +228,7 @@ func TestSourcePublish(t *testing.T) {
 				UID:             kubetypes.UID("1234"),
 				ResourceVersion: "2",
 				Spec:            "test-spec",
+				Namespace:       "cluster1",
 			},
 			eventType: types.CloudEventsType{
 				CloudEventsDataType: mockEventDataType,
@@ -124,12 +274,201 @@ func TestSourcePublish(t *testing.T) {
 	}
 }
 
+func TestSourceSpecCoalescing(t *testing.T) {
+	fakeClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	sourceOptions.SpecCoalesceWindow = 100 * time.Millisecond
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+
+	publish := func(uid kubetypes.UID, resourceVersion string, deleting bool) {
+		resource := &mockResource{UID: uid, ResourceVersion: resourceVersion, Spec: "test-spec", Namespace: "cluster1"}
+		if deleting {
+			resource.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		if err := source.Publish(context.TODO(), eventType, resource); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	sentVersions := func() []string {
+		versions := []string{}
+		for _, evt := range fakeClient.GetSentEvents() {
+			versions = append(versions, fmt.Sprintf("%v/%v",
+				evt.Extensions()[types.ExtensionResourceID], evt.Extensions()[types.ExtensionResourceVersion]))
+		}
+		return versions
+	}
+
+	// the successive spec updates of a resource are coalesced into the latest version
+	publish("1234", "1", false)
+	publish("1234", "2", false)
+	publish("1234", "3", false)
+	publish("5678", "1", false)
+	if versions := sentVersions(); len(versions) != 0 {
+		t.Errorf("expected no sent events within the window, but got %v", versions)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	versions := sentVersions()
+	sort.Strings(versions)
+	if !reflect.DeepEqual(versions, []string{"1234/3", "5678/1"}) {
+		t.Errorf("expected %v, but got %v", []string{"1234/3", "5678/1"}, versions)
+	}
+
+	// the spec of the deleting resource is published immediately, the pending spec is dropped
+	publish("1234", "4", false)
+	publish("1234", "5", true)
+	if versions := sentVersions(); len(versions) != 3 || versions[2] != "1234/5" {
+		t.Errorf("expected the deleting resource is published, but got %v", versions)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if versions := sentVersions(); len(versions) != 3 {
+		t.Errorf("expected 3 sent events, but got %v", versions)
+	}
+}
+
+func TestSourceSpecCoalescingClose(t *testing.T) {
+	fakeClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	sourceOptions.SpecCoalesceWindow = 50 * time.Millisecond
+	sourceOptions.Quota = options.SourceQuota{MaxResourcesPerCluster: 1}
+
+	var lock sync.Mutex
+	handledErrs := map[string]error{}
+	sourceOptions.SpecCoalesceErrorHandler = func(eventType types.CloudEventsType, resourceID string, err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		handledErrs[resourceID] = err
+	}
+
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_update_request",
+	}
+
+	publish := func(uid kubetypes.UID) {
+		resource := &mockResource{UID: uid, ResourceVersion: "1", Spec: "test-spec", Namespace: "cluster1"}
+		if err := source.Publish(context.TODO(), eventType, resource); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	// the quota error of a coalesced spec is passed to the error handler at the end of the window
+	publish("1234")
+	if err := wait.PollUntilContextTimeout(context.TODO(), 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) { return len(fakeClient.GetSentEvents()) == 1, nil }); err != nil {
+		t.Fatalf("expected the coalesced spec is published, but got %v", fakeClient.GetSentEvents())
+	}
+	publish("5678")
+	if err := wait.PollUntilContextTimeout(context.TODO(), 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(handledErrs) == 1, nil
+		}); err != nil {
+		t.Fatalf("expected the quota error is handled, but got %v", handledErrs)
+	}
+	lock.Lock()
+	if !errors.Is(handledErrs["5678"], ErrQuotaExceeded) {
+		t.Errorf("expected %v, but got %v", ErrQuotaExceeded, handledErrs["5678"])
+	}
+	lock.Unlock()
+
+	// the pending spec is published when the client is closed, its error is returned
+	fakeClient = fake.NewCloudEventsFakeClient()
+	sourceOptions = fake.NewSourceOptions(fakeClient, testSourceName)
+	sourceOptions.SpecCoalesceWindow = time.Hour
+	sourceOptions.Quota = options.SourceQuota{MaxResourcesPerCluster: 1}
+	source, err = NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	publish("1234")
+	publish("5678")
+	if err := source.Close(context.TODO()); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected %v, but got %v", ErrQuotaExceeded, err)
+	}
+	if len(fakeClient.GetSentEvents()) != 1 {
+		t.Errorf("expected one pending spec is published, but got %v", fakeClient.GetSentEvents())
+	}
+
+	if err := source.Publish(context.TODO(), eventType, &mockResource{UID: "1234", ResourceVersion: "2",
+		Spec: "test-spec", Namespace: "cluster1"}); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected %v, but got %v", ErrClientClosed, err)
+	}
+}
+
+func TestSourceDrain(t *testing.T) {
+	fakeClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	resource := &mockResource{UID: kubetypes.UID("1234"), ResourceVersion: "1", Namespace: "cluster1"}
+
+	if err := source.Publish(context.TODO(), eventType, resource); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// an in-flight publish blocks the draining until it is finished
+	if !source.acquire() {
+		t.Fatalf("expected the client is not draining")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := source.Drain(ctx); err == nil {
+		t.Errorf("expected error for the in-flight publish, but got nil")
+	}
+
+	source.release()
+	if err := source.Close(context.TODO()); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := source.Publish(context.TODO(), eventType, resource); err == nil {
+		t.Errorf("expected error after the client is closed, but got nil")
+	}
+
+	if len(fakeClient.GetSentEvents()) != 1 {
+		t.Errorf("expected 1 sent event, but got %d", len(fakeClient.GetSentEvents()))
+	}
+}
+
 func TestSpecResyncResponse(t *testing.T) {
 	cases := []struct {
-		name         string
-		requestEvent cloudevents.Event
-		resources    []*mockResource
-		validate     func([]cloudevents.Event)
+		name              string
+		requestEvent      cloudevents.Event
+		resources         []*mockResource
+		versionComparator VersionComparator
+		validate          func([]cloudevents.Event)
 	}{
 		{
 			name: "unsupported event type",
@@ -261,12 +600,78 @@ func TestSpecResyncResponse(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "resync specs - invalid spec",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              types.ResyncRequestAction,
+				}
+
+				evt := cloudevents.NewEvent()
+				evt.SetType(eventType.String())
+				evt.SetExtension("clustername", "cluster1")
+				if err := evt.SetData(cloudevents.ApplicationJSON, &payload.ResourceVersionList{}); err != nil {
+					t.Fatal(err)
+				}
+				return evt
+			}(),
+			resources: []*mockResource{
+				{UID: kubetypes.UID("test1"), Spec: "test1", Namespace: "cluster1"},
+			},
+			validate: func(pubEvents []cloudevents.Event) {
+				if len(pubEvents) != 0 {
+					t.Errorf("expected the spec without a resource version is not published, but got %v", pubEvents)
+				}
+			},
+		},
+		{
+			name: "resync specs with semantic versions",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceSpec,
+					Action:              types.ResyncRequestAction,
+				}
+
+				versions := &payload.ResourceVersionList{
+					Versions: []payload.ResourceVersion{
+						payload.NewResourceVersion("test1", "1.2.0"),
+						payload.NewResourceVersion("test2", "1.10.0"),
+					},
+				}
+
+				evt := cloudevents.NewEvent()
+				evt.SetType(eventType.String())
+				evt.SetExtension("clustername", "cluster1")
+				if err := evt.SetData(cloudevents.ApplicationJSON, versions); err != nil {
+					t.Fatal(err)
+				}
+				return evt
+			}(),
+			resources: []*mockResource{
+				{UID: kubetypes.UID("test1"), ResourceVersion: "1.10.0", Spec: "test1-updated", Namespace: "cluster1"},
+				{UID: kubetypes.UID("test2"), ResourceVersion: "1.10.0", Spec: "test2", Namespace: "cluster1"},
+			},
+			versionComparator: SemanticVersionComparator,
+			validate: func(pubEvents []cloudevents.Event) {
+				if len(pubEvents) != 1 {
+					t.Fatalf("expected one publish events, but got %v", pubEvents)
+				}
+
+				if resourceID := pubEvents[0].Extensions()[types.ExtensionResourceID]; resourceID != "test1" {
+					t.Errorf("expected the resource test1 is resent, but got %v", resourceID)
+				}
+			},
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			fakeClient := fake.NewCloudEventsFakeClient(c.requestEvent)
 			sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+			sourceOptions.VersionComparator = c.versionComparator
 			lister := newMockResourceLister(c.resources...)
 			source, err := NewCloudEventSourceClient[*mockResource](context.TODO(), sourceOptions, lister, statusHash, newMockResourceCodec())
 			if err != nil {
@@ -280,12 +685,386 @@ func TestSpecResyncResponse(t *testing.T) {
 	}
 }
 
+func TestRegistrationResponse(t *testing.T) {
+	cases := []struct {
+		name     string
+		handler  options.AgentRegistrationHandler
+		validate func(pubEvents []cloudevents.Event)
+	}{
+		{
+			name: "no registration handler",
+			validate: func(pubEvents []cloudevents.Event) {
+				if len(pubEvents) != 0 {
+					t.Errorf("expected no publish events, but got %v", pubEvents)
+				}
+			},
+		},
+		{
+			name: "accept the agent",
+			handler: func(ctx context.Context, registration payload.AgentRegistration) (*payload.AgentBootstrap, error) {
+				if registration.AgentVersion != "v1.0.0" {
+					return nil, fmt.Errorf("unsupported agent version %s", registration.AgentVersion)
+				}
+				return &payload.AgentBootstrap{Accepted: true, Config: []byte(`{"interval":"10s"}`)}, nil
+			},
+			validate: func(pubEvents []cloudevents.Event) {
+				if len(pubEvents) != 1 {
+					t.Fatalf("expected one publish events, but got %v", pubEvents)
+				}
+
+				bootstrap, err := payload.DecodeAgentBootstrap(pubEvents[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bootstrap.Accepted || bootstrap.RequestID != "request1" || string(bootstrap.Config) != `{"interval":"10s"}` {
+					t.Errorf("unexpected bootstrap %v", bootstrap)
+				}
+
+				clusterName, err := pubEvents[0].Context.GetExtension(types.ExtensionClusterName)
+				if err != nil || clusterName != "cluster1" {
+					t.Errorf("expected cluster1, but got %v", clusterName)
+				}
+			},
+		},
+		{
+			name: "reject the agent",
+			handler: func(ctx context.Context, registration payload.AgentRegistration) (*payload.AgentBootstrap, error) {
+				return nil, fmt.Errorf("the cluster is not allowed")
+			},
+			validate: func(pubEvents []cloudevents.Event) {
+				if len(pubEvents) != 1 {
+					t.Fatalf("expected one publish events, but got %v", pubEvents)
+				}
+
+				bootstrap, err := payload.DecodeAgentBootstrap(pubEvents[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if bootstrap.Accepted || bootstrap.Message != "the cluster is not allowed" {
+					t.Errorf("unexpected bootstrap %v", bootstrap)
+				}
+			},
+		},
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: types.RegistrationDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.RegisterRequestAction,
+	}
+	requestEvent := types.NewEventBuilder(testAgentName, eventType).
+		WithOriginalSource(types.SourceAll).
+		WithClusterName("cluster1").
+		NewEvent()
+	requestEvent.SetID("request1")
+	if err := requestEvent.SetData(cloudevents.ApplicationJSON, &payload.AgentRegistration{
+		ClusterName:  "cluster1",
+		AgentID:      testAgentName,
+		AgentVersion: "v1.0.0",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fakeClient := fake.NewCloudEventsFakeClient()
+			sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+			sourceOptions.AgentRegistrationHandler = c.handler
+			source, err := NewCloudEventSourceClient[*mockResource](
+				context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+
+			source.receive(context.TODO(), requestEvent)
+
+			c.validate(fakeClient.GetSentEvents())
+		})
+	}
+}
+
+func TestSourceFeatureNegotiation(t *testing.T) {
+	fakeClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	sourceOptions.Capabilities = features.NewSet(features.Compression, features.DeltaResync)
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the source advertises its features with the status resync request
+	if err := source.Resync(context.TODO(), "cluster1"); err != nil {
+		t.Fatal(err)
+	}
+	if actual := features.FromEvent(fakeClient.GetSentEvents()[0]).String(); actual != "compression,deltaresync" {
+		t.Errorf("expected %s, but got %s", "compression,deltaresync", actual)
+	}
+
+	// the agent advertises its features with the spec resync request
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncRequestAction,
+	}
+	requestEvent := types.NewEventBuilder(testAgentName, eventType).
+		WithClusterName("cluster1").
+		WithCapabilities("compression,chunking").
+		NewEvent()
+	if err := requestEvent.SetData(cloudevents.ApplicationJSON, &payload.ResourceVersionList{}); err != nil {
+		t.Fatal(err)
+	}
+	source.receive(context.TODO(), requestEvent)
+
+	if !source.Features().Enabled("cluster1", features.Compression) {
+		t.Errorf("expected compression is enabled for cluster1")
+	}
+	if source.Features().Enabled("cluster1", features.Chunking) {
+		t.Errorf("expected chunking is not enabled for cluster1")
+	}
+	if source.Features().Enabled("cluster2", features.Compression) {
+		t.Errorf("expected compression is not enabled for cluster2")
+	}
+}
+
+func TestSourceShardFilter(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}
+
+	sourceOptions := fake.NewSourceOptions(fake.NewCloudEventsFakeClient(), testSourceName)
+	sourceOptions.ShardFilter = func(clusterName string) bool {
+		return clusterName == "cluster1"
+	}
+	lister := newMockResourceLister(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "test1"})
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, lister, statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var handled []string
+	handler := func(action types.ResourceAction, obj *mockResource) error {
+		handled = append(handled, obj.Status)
+		return nil
+	}
+
+	for _, clusterName := range []string{"cluster1", "cluster2"} {
+		evt, err := newMockResourceCodec().Encode(testAgentName, eventType,
+			&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: clusterName})
+		if err != nil {
+			t.Fatal(err)
+		}
+		evt.SetExtension("clustername", clusterName)
+		source.receive(context.TODO(), *evt, handler)
+	}
+
+	if !reflect.DeepEqual(handled, []string{"cluster1"}) {
+		t.Errorf("expected only the status of cluster1 is handled, but got %v", handled)
+	}
+}
+
+func TestSourceLeaderElection(t *testing.T) {
+	leader := false
+	fakeClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	sourceOptions.IsLeader = func() bool {
+		return leader
+	}
+	lister := newMockResourceLister(&mockResource{
+		UID: kubetypes.UID("test1"), ResourceVersion: "2", Spec: "test1", Status: "old", Namespace: "cluster1"})
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, lister, statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	specEventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	obj := &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2", Spec: "test1", Namespace: "cluster1"}
+
+	// the standby replica does not publish or resync
+	if err := source.Publish(context.TODO(), specEventType, obj); !errors.Is(err, ErrNotLeader) {
+		t.Errorf("expected not leader error, but got %v", err)
+	}
+	if err := source.Resync(context.TODO(), "cluster1"); !errors.Is(err, ErrNotLeader) {
+		t.Errorf("expected not leader error, but got %v", err)
+	}
+
+	resyncRequest := types.NewEventBuilder(testAgentName, types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncRequestAction,
+	}).WithClusterName("cluster1").NewEvent()
+	if err := resyncRequest.SetData(cloudevents.ApplicationJSON, &payload.ResourceVersionList{}); err != nil {
+		t.Fatal(err)
+	}
+	source.receive(context.TODO(), resyncRequest)
+
+	if len(fakeClient.GetSentEvents()) != 0 {
+		t.Errorf("expected no sent events on the standby replica, but got %d", len(fakeClient.GetSentEvents()))
+	}
+
+	// the standby replica keeps handling the status
+	statusEvt, err := newMockResourceCodec().Encode(testAgentName, types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2", Status: "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	statusEvt.SetExtension("clustername", "cluster1")
+
+	handled := 0
+	source.receive(context.TODO(), *statusEvt, func(action types.ResourceAction, obj *mockResource) error {
+		handled++
+		return nil
+	})
+	if handled != 1 {
+		t.Errorf("expected the status is handled on the standby replica, but got %d", handled)
+	}
+
+	// the replica publishes and responds the resync requests after it becomes the leader
+	leader = true
+	if err := source.Publish(context.TODO(), specEventType, obj); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	source.receive(context.TODO(), resyncRequest)
+
+	if len(fakeClient.GetSentEvents()) != 2 {
+		t.Errorf("expected 2 sent events on the leader replica, but got %d", len(fakeClient.GetSentEvents()))
+	}
+}
+
+func TestSourceEventSchemas(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}
+
+	newStatusEvent := func(status any) cloudevents.Event {
+		evt, err := newMockResourceCodec().Encode(testAgentName, eventType,
+			&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := evt.SetData(cloudevents.ApplicationJSON, status); err != nil {
+			t.Fatal(err)
+		}
+		return *evt
+	}
+
+	discarded := make(chan string, 2)
+	sourceOptions := fake.NewSourceOptions(fake.NewCloudEventsFakeClient(newStatusEvent(1), newStatusEvent("new")),
+		testSourceName)
+	sourceOptions.EventSchemas = schema.NewRegistry(schema.EventSchema{
+		DataType: mockEventDataType,
+		Status:   &schema.JSONSchema{Type: schema.Types{"string"}},
+	})
+	sourceOptions.DiscardedEventHandler = func(evt cloudevents.Event, reason string) {
+		discarded <- reason
+	}
+	lister := newMockResourceLister(
+		&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "old", Namespace: "cluster1"})
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, lister, statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handled := make(chan string, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source.Subscribe(ctx, func(action types.ResourceAction, obj *mockResource) error {
+		handled <- obj.Status
+		return nil
+	})
+
+	select {
+	case reason := <-discarded:
+		if !strings.Contains(reason, "data") {
+			t.Errorf("expected the event is discarded for its data, but got %s", reason)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the invalid event is discarded")
+	}
+
+	select {
+	case status := <-handled:
+		if status != `"new"` {
+			t.Errorf("expected the valid status is handled, but got %s", status)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the valid event is handled")
+	}
+}
+
+func TestSpecResyncReport(t *testing.T) {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncRequestAction,
+	}
+
+	requestEvent := cloudevents.NewEvent()
+	requestEvent.SetType(eventType.String())
+	requestEvent.SetExtension("clustername", "cluster1")
+	if err := requestEvent.SetData(cloudevents.ApplicationJSON, &payload.ResourceVersionList{
+		Versions: []payload.ResourceVersion{
+			{ResourceID: "test1", ResourceVersion: 1},
+			{ResourceID: "test2", ResourceVersion: 3},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var reports []options.ResyncReport
+	fakeClient := fake.NewCloudEventsFakeClient(requestEvent)
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	sourceOptions.ResyncReporter = func(report options.ResyncReport) {
+		reports = append(reports, report)
+	}
+
+	lister := newMockResourceLister(
+		&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2", Spec: "test1", Namespace: "cluster1"},
+		&mockResource{UID: kubetypes.UID("test2"), ResourceVersion: "3", Spec: "test2", Namespace: "cluster1"},
+	)
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, lister, statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	source.receive(context.TODO(), requestEvent)
+
+	if len(reports) != 1 {
+		t.Fatalf("expected 1 report, but got %v", reports)
+	}
+
+	report := reports[0]
+	if report.ClusterName != "cluster1" || report.Resources != 2 || report.OutOfSync != 1 {
+		t.Errorf("unexpected report %v", report)
+	}
+
+	if report.ReceivedBytes != len(requestEvent.Data()) || report.SentBytes != len(fakeClient.GetSentEvents()[0].Data()) {
+		t.Errorf("unexpected report %v", report)
+	}
+}
+
 func TestReceiveResourceStatus(t *testing.T) {
 	cases := []struct {
-		name         string
-		requestEvent cloudevents.Event
-		resources    []*mockResource
-		validate     func(event types.ResourceAction, resource *mockResource)
+		name           string
+		requestEvent   cloudevents.Event
+		resources      []*mockResource
+		resyncedAction bool
+		validate       func(event types.ResourceAction, resource *mockResource)
 	}{
 		{
 			name: "unsupported sub resource",
@@ -373,6 +1152,51 @@ func TestReceiveResourceStatus(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "status resynced",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceStatus,
+					Action:              types.ResyncResponseAction,
+				}
+
+				evt, _ := newMockResourceCodec().Encode(testAgentName, eventType, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "test1"})
+				evt.SetExtension("clustername", "cluster1")
+				return *evt
+			}(),
+			resources: []*mockResource{
+				{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "test1"},
+			},
+			resyncedAction: true,
+			validate: func(event types.ResourceAction, resource *mockResource) {
+				if event != types.StatusResynced {
+					t.Errorf("expected %s, but got %s", types.StatusResynced, event)
+				}
+			},
+		},
+		{
+			name: "status resynced without the resynced action",
+			requestEvent: func() cloudevents.Event {
+				eventType := types.CloudEventsType{
+					CloudEventsDataType: mockEventDataType,
+					SubResource:         types.SubResourceStatus,
+					Action:              types.ResyncResponseAction,
+				}
+
+				evt, _ := newMockResourceCodec().Encode(testAgentName, eventType, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "test1"})
+				evt.SetExtension("clustername", "cluster1")
+				return *evt
+			}(),
+			resources: []*mockResource{
+				{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "test1"},
+			},
+			validate: func(event types.ResourceAction, resource *mockResource) {
+				if len(event) != 0 {
+					t.Errorf("unexpected event %s, %v", event, resource)
+				}
+			},
+		},
 		{
 			name: "none existing resource",
 			requestEvent: func() cloudevents.Event {
@@ -398,6 +1222,7 @@ func TestReceiveResourceStatus(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			fakeClient := fake.NewCloudEventsFakeClient(c.requestEvent)
 			sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+			sourceOptions.StatusResyncedAction = c.resyncedAction
 			lister := newMockResourceLister(c.resources...)
 			source, err := NewCloudEventSourceClient[*mockResource](context.TODO(), sourceOptions, lister, statusHash, newMockResourceCodec())
 			if err != nil {
@@ -416,3 +1241,77 @@ func TestReceiveResourceStatus(t *testing.T) {
 		})
 	}
 }
+
+type recordingAuditSink struct {
+	records []options.AuditRecord
+}
+
+func (s *recordingAuditSink) Record(ctx context.Context, record options.AuditRecord) error {
+	s.records = append(s.records, record)
+	return nil
+}
+
+func TestSourcePublishAudit(t *testing.T) {
+	fakeClient := fake.NewCloudEventsFakeClient()
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	auditSink := &recordingAuditSink{}
+	sourceOptions.AuditSink = auditSink
+
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	if err := source.Publish(context.TODO(), eventType, &mockResource{
+		UID: kubetypes.UID("1234"), ResourceVersion: "2", Namespace: "cluster1"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the resync request is not audited
+	if err := source.Resync(context.TODO(), "cluster1"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(auditSink.records) != 1 {
+		t.Fatalf("expected 1 audit record, but got %v", auditSink.records)
+	}
+
+	record := auditSink.records[0]
+	if record.Direction != options.AuditSent || record.Source != testSourceName || record.ClusterName != "cluster1" ||
+		record.ResourceID != "1234" || record.ResourceVersion != "2" || record.SubResource != types.SubResourceSpec ||
+		record.Action != "test_create_request" || len(record.Hash) == 0 {
+		t.Errorf("unexpected audit record %v", record)
+	}
+}
+
+func BenchmarkSourcePublish(b *testing.B) {
+	sourceOptions := fake.NewSourceOptions(fake.NewCloudEventsFakeClient(), testSourceName)
+	sourceOptions.EventRateLimit = options.EventRateLimit{QPS: math.MaxFloat32, Burst: math.MaxInt32}
+	sourceOptions.MaxEventSize = 1024 * 1024
+	source, err := NewCloudEventSourceClient[*mockResource](
+		context.TODO(), sourceOptions, newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	resource := &mockResource{UID: kubetypes.UID("1234"), ResourceVersion: "1", Namespace: "cluster1"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := source.Publish(context.TODO(), eventType, resource); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package generic
+
+import (
+	"sort"
+	"sync"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+)
+
+// specCache keeps the last received spec of each resource on the agent, so the agent reconcilers can diff against the
+// intended state of a resource without maintaining a parallel store.
+type specCache[T ResourceObject] struct {
+	sync.RWMutex
+	specs map[string]T
+}
+
+func newSpecCache[T ResourceObject]() *specCache[T] {
+	return &specCache[T]{specs: map[string]T{}}
+}
+
+// update saves the received spec of a resource, the resource is removed from the cache if it is being deleted.
+func (c *specCache[T]) update(obj T) {
+	c.Lock()
+	defer c.Unlock()
+
+	if !obj.GetDeletionTimestamp().IsZero() {
+		delete(c.specs, string(obj.GetUID()))
+		return
+	}
+
+	c.specs[string(obj.GetUID())] = obj
+}
+
+func (c *specCache[T]) get(resourceID string) (T, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	obj, ok := c.specs[resourceID]
+	return obj, ok
+}
+
+// versions returns the resource versions of the cached specs that are sorted by the resource IDs.
+func (c *specCache[T]) versions() []payload.ResourceVersion {
+	c.RLock()
+	defer c.RUnlock()
+
+	versions := make([]payload.ResourceVersion, 0, len(c.specs))
+	for resourceID, obj := range c.specs {
+		versions = append(versions, payload.NewResourceVersion(resourceID, obj.GetResourceVersion()))
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].ResourceID < versions[j].ResourceID
+	})
+	return versions
+}
+
+// len returns the number of the cached specs.
+func (c *specCache[T]) len() int {
+	c.RLock()
+	defer c.RUnlock()
+
+	return len(c.specs)
+}
@@ -0,0 +1,127 @@
+package generic
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/payload"
+)
+
+// specSyncCheckInterval is the interval that WaitForSpecSync checks whether the resources listed by the resync complete
+// event are handled by the agent.
+const specSyncCheckInterval = 100 * time.Millisecond
+
+// specSyncTracker tracks the initial spec resync of an agent. The spec is synced once a source completes one of the
+// spec resync requests of the agent with a resync complete event and the agent has all the resources that the source
+// listed in the event at their listed versions. The resources are checked against the lister of the agent because the
+// resync complete event may be handled before the resource specs that are resent ahead of it.
+type specSyncTracker struct {
+	sync.Mutex
+	compare   VersionComparator
+	requests  map[string]struct{}
+	pending   []payload.ResourceVersion
+	completed bool
+	synced    chan struct{}
+}
+
+func newSpecSyncTracker(compare VersionComparator) *specSyncTracker {
+	return &specSyncTracker{
+		compare:  compare,
+		requests: map[string]struct{}{},
+		synced:   make(chan struct{}),
+	}
+}
+
+// hasSynced returns true if the spec of the agent is synced.
+func (t *specSyncTracker) hasSynced() bool {
+	select {
+	case <-t.synced:
+		return true
+	default:
+		return false
+	}
+}
+
+// request records the ID of a spec resync request, it is ignored once the spec is synced.
+func (t *specSyncTracker) request(requestID string) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.hasSynced() {
+		return
+	}
+
+	t.requests[requestID] = struct{}{}
+}
+
+// complete records the resources of the resync complete event of a spec resync request, it returns false if the event
+// does not complete a recorded request.
+func (t *specSyncTracker) complete(requestID string, versions []payload.ResourceVersion) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.hasSynced() {
+		return false
+	}
+
+	if _, ok := t.requests[requestID]; !ok {
+		return false
+	}
+
+	if t.completed {
+		// the first completed request is waited, the resources of another source are not required
+		return true
+	}
+
+	t.completed = true
+	t.pending = versions
+	return true
+}
+
+// check marks the spec as synced if the resync is completed and the current resource versions of the agent are not
+// older than the pending resource versions.
+func (t *specSyncTracker) check(current []payload.ResourceVersion) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.hasSynced() || !t.completed {
+		return
+	}
+
+	versions := map[string]string{}
+	for _, rv := range current {
+		versions[rv.ResourceID] = rv.GetVersion()
+	}
+
+	pending := []payload.ResourceVersion{}
+	for _, rv := range t.pending {
+		version, ok := versions[rv.ResourceID]
+		if !ok {
+			pending = append(pending, rv)
+			continue
+		}
+
+		result, err := t.compare(version, rv.GetVersion())
+		if err != nil {
+			klog.Warningf("failed to compare the version of the resource %s, %v", rv.ResourceID, err)
+			if version != rv.GetVersion() {
+				pending = append(pending, rv)
+			}
+			continue
+		}
+
+		if result < 0 {
+			pending = append(pending, rv)
+		}
+	}
+
+	t.pending = pending
+	if len(t.pending) != 0 {
+		return
+	}
+
+	t.requests = nil
+	close(t.synced)
+}
@@ -0,0 +1,101 @@
+package generic
+
+import (
+	"context"
+	"testing"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/features"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestWaitForSpecSync(t *testing.T) {
+	cases := []struct {
+		name           string
+		capabilities   features.Set
+		expectedEvents int
+	}{
+		{
+			name:           "the source completes the resync",
+			capabilities:   features.NewSet(features.ResyncBarrier),
+			expectedEvents: 3,
+		},
+		{
+			name:           "the resync barrier is not enabled",
+			expectedEvents: 2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			agentLister := newMockResourceLister(
+				&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"})
+			agentClient := fake.NewCloudEventsFakeClient()
+			agentOptions := fake.NewAgentOptions(agentClient, "cluster1", testAgentName)
+			agentOptions.Capabilities = features.NewSet(features.ResyncBarrier)
+			agent, err := NewCloudEventAgentClient[*mockResource](context.TODO(), agentOptions, agentLister,
+				statusHash, newMockResourceCodec())
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if err := agent.Resync(context.TODO(), testSourceName); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			sourceResources := []*mockResource{
+				{UID: kubetypes.UID("test1"), ResourceVersion: "2", Namespace: "cluster1"},
+				{UID: kubetypes.UID("test2"), ResourceVersion: "1", Namespace: "cluster1"},
+			}
+			sourceClient := fake.NewCloudEventsFakeClient()
+			sourceOptions := fake.NewSourceOptions(sourceClient, testSourceName)
+			sourceOptions.Capabilities = c.capabilities
+			source, err := NewCloudEventSourceClient[*mockResource](context.TODO(), sourceOptions,
+				newMockResourceLister(sourceResources...), statusHash, newMockResourceCodec())
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			source.receive(context.TODO(), agentClient.GetSentEvents()[0])
+
+			sent := sourceClient.GetSentEvents()
+			if len(sent) != c.expectedEvents {
+				t.Fatalf("expected %d events, but got %v", c.expectedEvents, sent)
+			}
+
+			// the resync complete event is received before the resources are handled
+			for i := len(sent) - 1; i >= 0; i-- {
+				agent.receive(context.TODO(), sent[i])
+				if agent.HasSpecSynced() {
+					t.Errorf("expected the spec is not synced")
+				}
+			}
+
+			agentLister.resources = sourceResources
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err = agent.WaitForSpecSync(ctx)
+			if c.capabilities == nil {
+				if err != context.Canceled {
+					t.Errorf("expected %v, but got %v", context.Canceled, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+
+			eventType, err := types.ParseCloudEventsType(sent[2].Type())
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if eventType.Action != types.ResyncCompleteAction {
+				t.Errorf("expected %s, but got %s", types.ResyncCompleteAction, eventType.Action)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+// Package sqlite provides a generic.ResourceStore that saves the resources in an SQLite database, so the resources and
+// their status are kept across the restarts of a source.
+//
+// The database is accessed with the github.com/mattn/go-sqlite3 driver, it requires cgo, the store fails to open the
+// database if the binary is built with CGO_ENABLED=0.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const schema = `CREATE TABLE IF NOT EXISTS resources (
+	id TEXT PRIMARY KEY,
+	cluster TEXT NOT NULL,
+	data BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS resources_cluster ON resources (cluster);`
+
+// ResourceStore is a generic.ResourceStore that saves the resource objects as JSON in an SQLite database, the resource
+// objects must be pointers to the types that can be marshaled to and unmarshaled from JSON, e.g. *workv1.ManifestWork.
+//
+// The changes are only watched in the process that makes them, the database should not be shared by the stores of
+// several processes.
+type ResourceStore[T generic.ResourceObject] struct {
+	// lock serializes the writes, so the status is merged to the last saved resource object
+	sync.Mutex
+	db                *sql.DB
+	clusterNameGetter generic.ClusterNameGetter[T]
+	statusMerger      generic.StatusMerger[T]
+
+	watchersLock sync.Mutex
+	watchers     []*watcher[T]
+}
+
+var _ generic.ResourceStore[generic.ResourceObject] = &ResourceStore[generic.ResourceObject]{}
+
+type watcher[T generic.ResourceObject] struct {
+	ctx    context.Context
+	result chan generic.ResourceStoreEvent[T]
+}
+
+// NewResourceStore returns a ResourceStore with the given database file, the file and its tables will be created if
+// they do not exist. The clusterNameGetter is used to find the resources of a cluster when listing the resources. The
+// statusMerger is optional, if it is not set, the received resource object replaces the last resource object when
+// upserting the status.
+func NewResourceStore[T generic.ResourceObject](path string,
+	clusterNameGetter generic.ClusterNameGetter[T], statusMerger generic.StatusMerger[T]) (*ResourceStore[T], error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store database %s, %v", path, err)
+	}
+
+	// the writes are serialized by the store, one connection avoids the database is locked errors
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create store tables in %s, %v", path, err)
+	}
+
+	return &ResourceStore[T]{
+		db:                db,
+		clusterNameGetter: clusterNameGetter,
+		statusMerger:      statusMerger,
+	}, nil
+}
+
+// Upsert adds or updates a resource object in the store.
+func (s *ResourceStore[T]) Upsert(obj T) error {
+	s.Lock()
+	_, exists, err := s.Get(string(obj.GetUID()))
+	if err == nil {
+		err = s.save(obj)
+	}
+	s.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		s.notify(types.Modified, obj)
+		return nil
+	}
+	s.notify(types.Added, obj)
+	return nil
+}
+
+// Delete removes a resource object from the store.
+func (s *ResourceStore[T]) Delete(resourceID string) error {
+	s.Lock()
+	obj, exists, err := s.Get(resourceID)
+	if err == nil && exists {
+		_, err = s.db.Exec("DELETE FROM resources WHERE id = ?", resourceID)
+	}
+	s.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to delete resource %s, %v", resourceID, err)
+	}
+
+	if exists {
+		s.notify(types.Deleted, obj)
+	}
+	return nil
+}
+
+func (s *ResourceStore[T]) Get(resourceID string) (T, bool, error) {
+	var obj T
+	var data []byte
+	err := s.db.QueryRow("SELECT data FROM resources WHERE id = ?", resourceID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return obj, false, nil
+	}
+	if err != nil {
+		return obj, false, fmt.Errorf("failed to get resource %s, %v", resourceID, err)
+	}
+
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return obj, false, fmt.Errorf("failed to unmarshal resource %s, %v", resourceID, err)
+	}
+	return obj, true, nil
+}
+
+func (s *ResourceStore[T]) List(options types.ListOptions) ([]T, error) {
+	var rows *sql.Rows
+	var err error
+	if options.ClusterName == types.ClusterAll || s.clusterNameGetter == nil {
+		rows, err = s.db.Query("SELECT id, data FROM resources")
+	} else {
+		rows, err = s.db.Query("SELECT id, data FROM resources WHERE cluster = ?", options.ClusterName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources, %v", err)
+	}
+	defer rows.Close()
+
+	objs := []T{}
+	for rows.Next() {
+		var id string
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to list resources, %v", err)
+		}
+
+		var obj T
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal resource %s, %v", id, err)
+		}
+		objs = append(objs, obj)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list resources, %v", err)
+	}
+
+	return objs, nil
+}
+
+func (s *ResourceStore[T]) UpsertStatus(obj T) error {
+	s.Lock()
+	last, exists, err := s.Get(string(obj.GetUID()))
+	if err == nil {
+		if exists && s.statusMerger != nil {
+			obj = s.statusMerger(last, obj)
+		}
+		err = s.save(obj)
+	}
+	s.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	s.notify(types.StatusModified, obj)
+	return nil
+}
+
+func (s *ResourceStore[T]) Watch(ctx context.Context) <-chan generic.ResourceStoreEvent[T] {
+	w := &watcher[T]{
+		ctx:    ctx,
+		result: make(chan generic.ResourceStoreEvent[T]),
+	}
+
+	s.watchersLock.Lock()
+	s.watchers = append(s.watchers, w)
+	s.watchersLock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		s.watchersLock.Lock()
+		defer s.watchersLock.Unlock()
+		for i, existing := range s.watchers {
+			if existing == w {
+				s.watchers = append(s.watchers[:i:i], s.watchers[i+1:]...)
+				return
+			}
+		}
+	}()
+
+	return w.result
+}
+
+// Close closes the database of the store.
+func (s *ResourceStore[T]) Close() error {
+	return s.db.Close()
+}
+
+// save inserts or replaces the resource object in the database, the caller must hold the lock of the store.
+func (s *ResourceStore[T]) save(obj T) error {
+	resourceID := string(obj.GetUID())
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal resource %s, %v", resourceID, err)
+	}
+
+	clusterName := ""
+	if s.clusterNameGetter != nil {
+		clusterName = s.clusterNameGetter(obj)
+	}
+
+	if _, err := s.db.Exec("INSERT INTO resources (id, cluster, data) VALUES (?, ?, ?) "+
+		"ON CONFLICT (id) DO UPDATE SET cluster = excluded.cluster, data = excluded.data",
+		resourceID, clusterName, data); err != nil {
+		return fmt.Errorf("failed to save resource %s, %v", resourceID, err)
+	}
+	return nil
+}
+
+func (s *ResourceStore[T]) notify(action types.ResourceAction, obj T) {
+	s.watchersLock.Lock()
+	watchers := s.watchers
+	s.watchersLock.Unlock()
+
+	for _, w := range watchers {
+		select {
+		case w.result <- generic.ResourceStoreEvent[T]{Action: action, Object: obj}:
+		case <-w.ctx.Done():
+		}
+	}
+}
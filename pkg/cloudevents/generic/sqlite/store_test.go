@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type testResource struct {
+	UID             kubetypes.UID `json:"uid"`
+	ResourceVersion string        `json:"resourceVersion"`
+	Namespace       string        `json:"namespace"`
+	Spec            string        `json:"spec"`
+	Status          string        `json:"status"`
+}
+
+func (r *testResource) GetUID() kubetypes.UID {
+	return r.UID
+}
+
+func (r *testResource) GetResourceVersion() string {
+	return r.ResourceVersion
+}
+
+func (r *testResource) GetDeletionTimestamp() *metav1.Time {
+	return nil
+}
+
+func newTestStore(t *testing.T, path string) *ResourceStore[*testResource] {
+	store, err := NewResourceStore[*testResource](
+		path,
+		func(obj *testResource) string { return obj.Namespace },
+		func(last, obj *testResource) *testResource {
+			merged := *last
+			merged.Status = obj.Status
+			return &merged
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return store
+}
+
+func TestResourceStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resources.db")
+	store := newTestStore(t, path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := store.Watch(ctx)
+	go func() {
+		for _, obj := range []*testResource{
+			{UID: kubetypes.UID("test1"), Namespace: "cluster1", Spec: "test1"},
+			{UID: kubetypes.UID("test2"), Namespace: "cluster2", Spec: "test2"},
+		} {
+			if err := store.Upsert(obj); err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		}
+	}()
+
+	for _, expected := range []string{"test1", "test2"} {
+		evt := <-events
+		if evt.Action != types.Added || string(evt.Object.UID) != expected {
+			t.Errorf("expected %s is added, but got %v", expected, evt)
+		}
+	}
+
+	objs, err := store.List(types.ListOptions{ClusterName: "cluster1"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(objs) != 1 || objs[0].UID != "test1" {
+		t.Errorf("expected test1 in cluster1, but got %v", objs)
+	}
+
+	objs, err = store.List(types.ListOptions{ClusterName: types.ClusterAll})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(objs) != 2 {
+		t.Errorf("expected 2 resources, but got %v", objs)
+	}
+
+	go func() {
+		if err := store.UpsertStatus(&testResource{UID: kubetypes.UID("test1"), Status: "applied"}); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		if err := store.Delete("test2"); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	}()
+
+	if evt := <-events; evt.Action != types.StatusModified {
+		t.Errorf("expected status modified, but got %v", evt)
+	}
+	if evt := <-events; evt.Action != types.Deleted || string(evt.Object.UID) != "test2" {
+		t.Errorf("expected test2 is deleted, but got %v", evt)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the resources and their status are kept after the store is reopened
+	store = newTestStore(t, path)
+	defer store.Close()
+
+	obj, exists, err := store.Get("test1")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !exists || obj.Namespace != "cluster1" || obj.Spec != "test1" || obj.Status != "applied" {
+		t.Errorf("expected the status of test1 is merged, but got %v", obj)
+	}
+
+	if _, exists, _ := store.Get("test2"); exists {
+		t.Errorf("expected test2 is deleted")
+	}
+
+	// the merged resource is still listed by its cluster
+	objs, err = store.List(types.ListOptions{ClusterName: "cluster1"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(objs) != 1 || objs[0].Status != "applied" {
+		t.Errorf("expected test1 in cluster1, but got %v", objs)
+	}
+}
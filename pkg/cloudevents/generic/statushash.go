@@ -0,0 +1,264 @@
+package generic
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	// StatusHashAlgorithmSHA256 is the SHA256 checksum of the status, it is the default algorithm of the status hashes.
+	StatusHashAlgorithmSHA256 = "sha256"
+
+	// StatusHashAlgorithmSHA384 is the SHA384 checksum of the status.
+	StatusHashAlgorithmSHA384 = "sha384"
+
+	// StatusHashAlgorithmSHA512 is the SHA512 checksum of the status.
+	StatusHashAlgorithmSHA512 = "sha512"
+
+	// StatusHashAlgorithmFNV128a is the 128-bit FNV-1a hash of the status, it is faster than the SHA checksums but it
+	// is not allowed in the FIPS mode.
+	StatusHashAlgorithmFNV128a = "fnv128a"
+)
+
+// StatusHashOptions are the options of a StatusHashGetter.
+type StatusHashOptions struct {
+	// Algorithm is the algorithm of the status hashes, by default, it is StatusHashAlgorithmSHA256. The sources and the
+	// agents carry it with the StatusHashAlgorithm of their options, so an agent does not compare the status hashes that
+	// are calculated by another algorithm.
+	Algorithm string
+
+	// SortKeys sorts the keys of the objects at any level of the status before the status is hashed, so the hash does
+	// not depend on the order of the fields of the status types, e.g. the hashers that are implemented in another
+	// language calculate the same hash. The keys are always sorted if the IgnoredFields is set.
+	SortKeys bool
+
+	// IgnoredFields are the names of the fields that are ignored at any level of the status, e.g. the volatile
+	// lastTransitionTime of the conditions, so the hash is not changed if only the ignored fields are changed.
+	IgnoredFields []string
+
+	// FIPS only allows the FIPS-approved algorithms, the SHA256, SHA384 and SHA512 checksums, which are calculated by
+	// the validated cryptographic module of the Go toolchain if the binary is built in the FIPS mode of the toolchain.
+	FIPS bool
+}
+
+// NewStatusHashGetter returns a StatusHashGetter that calculates the hash of the status that is returned by the
+// statusFunc with the given options, it returns an error if the algorithm is unknown or it is not allowed in the FIPS
+// mode.
+//
+// The source and the agent must use the same hasher, otherwise the status hashes in the resync requests never match.
+func NewStatusHashGetter[T ResourceObject](statusFunc func(obj T) any, opts StatusHashOptions) (StatusHashGetter[T], error) {
+	newHash, err := statusHashFunc(opts.Algorithm, opts.FIPS)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStatusHashGetter(statusFunc, newHash, opts.SortKeys, opts.IgnoredFields...), nil
+}
+
+// statusHashAlgorithm returns the algorithm of the status hashes, it is StatusHashAlgorithmSHA256 if it is not set.
+func statusHashAlgorithm(algorithm string) string {
+	if len(algorithm) == 0 {
+		return StatusHashAlgorithmSHA256
+	}
+	return algorithm
+}
+
+// resyncStatusHashAlgorithm returns the algorithm of the status hashes of a status resync request.
+func resyncStatusHashAlgorithm(evt cloudevents.Event) string {
+	algorithm, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionStatusHashAlgorithm])
+	if err != nil {
+		return StatusHashAlgorithmSHA256
+	}
+	return statusHashAlgorithm(algorithm)
+}
+
+// statusHashFunc returns the hash func of the algorithm.
+func statusHashFunc(algorithm string, fips bool) (func() hash.Hash, error) {
+	switch algorithm {
+	case "", StatusHashAlgorithmSHA256:
+		return sha256.New, nil
+	case StatusHashAlgorithmSHA384:
+		return sha512.New384, nil
+	case StatusHashAlgorithmSHA512:
+		return sha512.New, nil
+	case StatusHashAlgorithmFNV128a:
+		if fips {
+			return nil, fmt.Errorf("the status hash algorithm %s is not allowed in the FIPS mode", algorithm)
+		}
+		return fnv.New128a, nil
+	default:
+		return nil, fmt.Errorf("unknown status hash algorithm %q", algorithm)
+	}
+}
+
+// StatusHashKeyFunc returns the key of the status of a resource object, a cached status hash is reused until the key
+// of the status is changed.
+type StatusHashKeyFunc[T ResourceObject] func(obj T) string
+
+// ResourceVersionStatusHashKey is the default StatusHashKeyFunc, the key is the resource ID and the resource version of
+// the resource object. It can be used only if the resource version is changed whenever the status is changed.
+func ResourceVersionStatusHashKey[T ResourceObject](obj T) string {
+	return fmt.Sprintf("%s/%s", obj.GetUID(), obj.GetResourceVersion())
+}
+
+// NewStructuredStatusHashGetter returns a StatusHashGetter that calculates the SHA256 checksum of the status that is
+// returned by the statusFunc, the fields with the given names are ignored at any level of the status, e.g. the volatile
+// lastTransitionTime of the conditions, so the hash is not changed if only the ignored fields are changed.
+//
+// The source and the agent must use the same hasher, otherwise the status hashes in the resync requests never match.
+func NewStructuredStatusHashGetter[T ResourceObject](
+	statusFunc func(obj T) any, ignoredFields ...string) StatusHashGetter[T] {
+	return newStatusHashGetter(statusFunc, sha256.New, false, ignoredFields...)
+}
+
+func newStatusHashGetter[T ResourceObject](
+	statusFunc func(obj T) any, newHash func() hash.Hash, sortKeys bool, ignoredFields ...string) StatusHashGetter[T] {
+	ignored := map[string]bool{}
+	for _, field := range ignoredFields {
+		ignored[field] = true
+	}
+
+	return func(obj T) (string, error) {
+		statusBytes, err := json.Marshal(statusFunc(obj))
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal the status of %s, %v", obj.GetUID(), err)
+		}
+
+		if sortKeys || len(ignored) != 0 {
+			var status any
+			if err := json.Unmarshal(statusBytes, &status); err != nil {
+				return "", fmt.Errorf("failed to unmarshal the status of %s, %v", obj.GetUID(), err)
+			}
+
+			// the keys of the maps are sorted when they are marshalled, so the hash is stable
+			statusBytes, err = json.Marshal(removeFields(status, ignored))
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal the status of %s, %v", obj.GetUID(), err)
+			}
+		}
+
+		h := newHash()
+		_, _ = h.Write(statusBytes)
+		return fmt.Sprintf("%x", h.Sum(nil)), nil
+	}
+}
+
+func removeFields(value any, fields map[string]bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if fields[key] {
+				delete(v, key)
+				continue
+			}
+			v[key] = removeFields(item, fields)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = removeFields(item, fields)
+		}
+	}
+	return value
+}
+
+// NewCachedStatusHashGetter returns a StatusHashGetter that caches the status hashes calculated by the given hasher, so
+// the status of a resource is not hashed again until the key of its status is changed, e.g. on the resync. The keyFunc
+// is optional, by default, the ResourceVersionStatusHashKey is used. The cache keeps the hashes of the given number of
+// the recently hashed resources, if the size is less than or equal to zero, the hasher is returned without caching.
+func NewCachedStatusHashGetter[T ResourceObject](
+	hasher StatusHashGetter[T], keyFunc StatusHashKeyFunc[T], size int) StatusHashGetter[T] {
+	if size <= 0 {
+		return hasher
+	}
+
+	if keyFunc == nil {
+		keyFunc = ResourceVersionStatusHashKey[T]
+	}
+
+	cache := newStatusHashCache(size)
+	return func(obj T) (string, error) {
+		resourceID := string(obj.GetUID())
+		key := keyFunc(obj)
+		if hash, ok := cache.get(resourceID, key); ok {
+			return hash, nil
+		}
+
+		hash, err := hasher(obj)
+		if err != nil {
+			return "", err
+		}
+
+		cache.add(resourceID, key, hash)
+		return hash, nil
+	}
+}
+
+// statusHashCache keeps the status hash of the latest status key for each resource, it evicts the least recently used
+// resource when it is full.
+type statusHashCache struct {
+	sync.Mutex
+	size      int
+	entries   map[string]*list.Element
+	resources *list.List
+}
+
+type statusHashEntry struct {
+	resourceID string
+	key        string
+	hash       string
+}
+
+func newStatusHashCache(size int) *statusHashCache {
+	return &statusHashCache{
+		size:      size,
+		entries:   map[string]*list.Element{},
+		resources: list.New(),
+	}
+}
+
+func (c *statusHashCache) get(resourceID, key string) (string, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, ok := c.entries[resourceID]
+	if !ok {
+		return "", false
+	}
+
+	entry := elem.Value.(*statusHashEntry)
+	if entry.key != key {
+		return "", false
+	}
+
+	c.resources.MoveToFront(elem)
+	return entry.hash, true
+}
+
+func (c *statusHashCache) add(resourceID, key, hash string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, ok := c.entries[resourceID]; ok {
+		elem.Value = &statusHashEntry{resourceID: resourceID, key: key, hash: hash}
+		c.resources.MoveToFront(elem)
+		return
+	}
+
+	c.entries[resourceID] = c.resources.PushFront(&statusHashEntry{resourceID: resourceID, key: key, hash: hash})
+	if c.resources.Len() > c.size {
+		oldest := c.resources.Back()
+		c.resources.Remove(oldest)
+		delete(c.entries, oldest.Value.(*statusHashEntry).resourceID)
+	}
+}
@@ -0,0 +1,177 @@
+package generic
+
+import (
+	"testing"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+)
+
+type mockStatus struct {
+	Phase              string `json:"phase"`
+	LastTransitionTime string `json:"lastTransitionTime"`
+}
+
+func TestStructuredStatusHashGetter(t *testing.T) {
+	statuses := map[string]mockStatus{}
+	hasher := NewStructuredStatusHashGetter[*mockResource](func(obj *mockResource) any {
+		return []mockStatus{statuses[obj.Status]}
+	}, "lastTransitionTime")
+
+	cases := []struct {
+		name          string
+		last          mockStatus
+		current       mockStatus
+		expectedEqual bool
+	}{
+		{
+			name:          "only the ignored field is changed",
+			last:          mockStatus{Phase: "Applied", LastTransitionTime: "2024-01-01T00:00:00Z"},
+			current:       mockStatus{Phase: "Applied", LastTransitionTime: "2024-01-02T00:00:00Z"},
+			expectedEqual: true,
+		},
+		{
+			name:          "the status is changed",
+			last:          mockStatus{Phase: "Applied", LastTransitionTime: "2024-01-01T00:00:00Z"},
+			current:       mockStatus{Phase: "Available", LastTransitionTime: "2024-01-01T00:00:00Z"},
+			expectedEqual: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			statuses["last"] = c.last
+			statuses["current"] = c.current
+
+			lastHash, err := hasher(&mockResource{UID: kubetypes.UID("test1"), Status: "last"})
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			currentHash, err := hasher(&mockResource{UID: kubetypes.UID("test1"), Status: "current"})
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if (lastHash == currentHash) != c.expectedEqual {
+				t.Errorf("expected equal %v, but got %s and %s", c.expectedEqual, lastHash, currentHash)
+			}
+		})
+	}
+}
+
+func TestStatusHashGetter(t *testing.T) {
+	statusFunc := func(obj *mockResource) any {
+		return map[string]string{"phase": obj.Status}
+	}
+
+	cases := []struct {
+		name           string
+		opts           StatusHashOptions
+		expectedLength int
+		expectedErr    bool
+	}{
+		{
+			name:           "default algorithm",
+			opts:           StatusHashOptions{},
+			expectedLength: 64,
+		},
+		{
+			name:           "sha384 with sorted keys",
+			opts:           StatusHashOptions{Algorithm: StatusHashAlgorithmSHA384, SortKeys: true},
+			expectedLength: 96,
+		},
+		{
+			name:           "sha512 in the FIPS mode",
+			opts:           StatusHashOptions{Algorithm: StatusHashAlgorithmSHA512, FIPS: true},
+			expectedLength: 128,
+		},
+		{
+			name:           "fnv128a",
+			opts:           StatusHashOptions{Algorithm: StatusHashAlgorithmFNV128a},
+			expectedLength: 32,
+		},
+		{
+			name:        "fnv128a in the FIPS mode",
+			opts:        StatusHashOptions{Algorithm: StatusHashAlgorithmFNV128a, FIPS: true},
+			expectedErr: true,
+		},
+		{
+			name:        "unknown algorithm",
+			opts:        StatusHashOptions{Algorithm: "md5"},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hasher, err := NewStatusHashGetter[*mockResource](statusFunc, c.opts)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			hash, err := hasher(&mockResource{UID: kubetypes.UID("test1"), Status: "Applied"})
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if len(hash) != c.expectedLength {
+				t.Errorf("expected hash length %d, but got %s", c.expectedLength, hash)
+			}
+		})
+	}
+
+	// the default algorithm is compatible with the structured status hash getter
+	hasher, err := NewStatusHashGetter[*mockResource](statusFunc, StatusHashOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	obj := &mockResource{UID: kubetypes.UID("test1"), Status: "Applied"}
+	expected, _ := NewStructuredStatusHashGetter[*mockResource](statusFunc)(obj)
+	if hash, _ := hasher(obj); hash != expected {
+		t.Errorf("expected %s, but got %s", expected, hash)
+	}
+}
+
+func TestCachedStatusHashGetter(t *testing.T) {
+	hashed := 0
+	hasher := NewCachedStatusHashGetter[*mockResource](func(obj *mockResource) (string, error) {
+		hashed++
+		return obj.Status, nil
+	}, nil, 1)
+
+	hash := func(res *mockResource) string {
+		h, err := hasher(res)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		return h
+	}
+
+	if h := hash(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "s1"}); h != "s1" {
+		t.Errorf("expected s1, but got %s", h)
+	}
+
+	// the resource version is not changed, the cached hash is returned
+	if h := hash(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "s2"}); h != "s1" {
+		t.Errorf("expected s1, but got %s", h)
+	}
+
+	// the resource version is changed, the status is hashed again
+	if h := hash(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2", Status: "s2"}); h != "s2" {
+		t.Errorf("expected s2, but got %s", h)
+	}
+
+	// the cache is full, the least recently used resource is evicted
+	hash(&mockResource{UID: kubetypes.UID("test2"), ResourceVersion: "1", Status: "s1"})
+	hash(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "2", Status: "s2"})
+
+	if hashed != 4 {
+		t.Errorf("expected 4, but got %d", hashed)
+	}
+}
@@ -0,0 +1,172 @@
+package generic
+
+import (
+	"context"
+	"sync"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// ResourceStore is a persistent store of the resources that are maintained by a source, the source client uses it to
+// list the resources for the resync and to save the resource status that is reported by the agents.
+//
+// Available implementations:
+//   - MemoryResourceStore, keeps the resources in memory.
+//   - sqlite.ResourceStore, saves the resources in an SQLite database, see the generic/sqlite package.
+type ResourceStore[T ResourceObject] interface {
+	Lister[T]
+
+	// Get returns the resource object with the given resource ID.
+	Get(resourceID string) (T, bool, error)
+
+	// UpsertStatus saves the status of a resource object, the resource object will be added if it does not exist.
+	UpsertStatus(obj T) error
+
+	// Watch returns a chan to receive the changes of the store, the changes will not be sent to the chan after the
+	// given context is done.
+	Watch(ctx context.Context) <-chan ResourceStoreEvent[T]
+}
+
+// ResourceStoreEvent represents a change of a resource object in a ResourceStore.
+type ResourceStoreEvent[T ResourceObject] struct {
+	Action types.ResourceAction
+	Object T
+}
+
+// ClusterNameGetter gets the cluster name of one resource object.
+type ClusterNameGetter[T ResourceObject] func(obj T) string
+
+// StatusMerger merges the status of the received resource object to the last resource object in a store.
+type StatusMerger[T ResourceObject] func(last, obj T) T
+
+// MemoryResourceStore is an in-memory ResourceStore.
+type MemoryResourceStore[T ResourceObject] struct {
+	sync.RWMutex
+	resources         map[string]T
+	clusterNameGetter ClusterNameGetter[T]
+	statusMerger      StatusMerger[T]
+
+	watchersLock sync.Mutex
+	watchers     []*resourceStoreWatcher[T]
+}
+
+var _ ResourceStore[ResourceObject] = &MemoryResourceStore[ResourceObject]{}
+
+type resourceStoreWatcher[T ResourceObject] struct {
+	ctx    context.Context
+	result chan ResourceStoreEvent[T]
+}
+
+// NewMemoryResourceStore returns a MemoryResourceStore, the clusterNameGetter is used to find the resources of a cluster
+// when listing the resources. The statusMerger is optional, if it is not set, the received resource object replaces
+// the last resource object when upserting the status.
+func NewMemoryResourceStore[T ResourceObject](
+	clusterNameGetter ClusterNameGetter[T], statusMerger StatusMerger[T]) *MemoryResourceStore[T] {
+	return &MemoryResourceStore[T]{
+		resources:         make(map[string]T),
+		clusterNameGetter: clusterNameGetter,
+		statusMerger:      statusMerger,
+	}
+}
+
+// Upsert adds or updates a resource object in the store.
+func (s *MemoryResourceStore[T]) Upsert(obj T) {
+	s.Lock()
+	_, exists := s.resources[string(obj.GetUID())]
+	s.resources[string(obj.GetUID())] = obj
+	s.Unlock()
+
+	if exists {
+		s.notify(types.Modified, obj)
+		return
+	}
+	s.notify(types.Added, obj)
+}
+
+// Delete removes a resource object from the store.
+func (s *MemoryResourceStore[T]) Delete(resourceID string) {
+	s.Lock()
+	obj, exists := s.resources[resourceID]
+	delete(s.resources, resourceID)
+	s.Unlock()
+
+	if exists {
+		s.notify(types.Deleted, obj)
+	}
+}
+
+func (s *MemoryResourceStore[T]) Get(resourceID string) (T, bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	obj, exists := s.resources[resourceID]
+	return obj, exists, nil
+}
+
+func (s *MemoryResourceStore[T]) List(options types.ListOptions) ([]T, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	objs := []T{}
+	for _, obj := range s.resources {
+		if options.ClusterName != types.ClusterAll && s.clusterNameGetter != nil &&
+			s.clusterNameGetter(obj) != options.ClusterName {
+			continue
+		}
+
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+func (s *MemoryResourceStore[T]) UpsertStatus(obj T) error {
+	s.Lock()
+	last, exists := s.resources[string(obj.GetUID())]
+	if exists && s.statusMerger != nil {
+		obj = s.statusMerger(last, obj)
+	}
+	s.resources[string(obj.GetUID())] = obj
+	s.Unlock()
+
+	s.notify(types.StatusModified, obj)
+	return nil
+}
+
+func (s *MemoryResourceStore[T]) Watch(ctx context.Context) <-chan ResourceStoreEvent[T] {
+	watcher := &resourceStoreWatcher[T]{
+		ctx:    ctx,
+		result: make(chan ResourceStoreEvent[T]),
+	}
+
+	s.watchersLock.Lock()
+	s.watchers = append(s.watchers, watcher)
+	s.watchersLock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		s.watchersLock.Lock()
+		defer s.watchersLock.Unlock()
+		for i, w := range s.watchers {
+			if w == watcher {
+				s.watchers = append(s.watchers[:i:i], s.watchers[i+1:]...)
+				return
+			}
+		}
+	}()
+
+	return watcher.result
+}
+
+func (s *MemoryResourceStore[T]) notify(action types.ResourceAction, obj T) {
+	s.watchersLock.Lock()
+	watchers := s.watchers
+	s.watchersLock.Unlock()
+
+	for _, watcher := range watchers {
+		select {
+		case watcher.result <- ResourceStoreEvent[T]{Action: action, Object: obj}:
+		case <-watcher.ctx.Done():
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package generic
+
+import (
+	"context"
+	"testing"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestMemoryResourceStore(t *testing.T) {
+	store := NewMemoryResourceStore[*mockResource](
+		func(obj *mockResource) string { return obj.Namespace },
+		func(last, obj *mockResource) *mockResource {
+			merged := *last
+			merged.Status = obj.Status
+			return &merged
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := store.Watch(ctx)
+	go func() {
+		store.Upsert(&mockResource{UID: kubetypes.UID("test1"), Namespace: "cluster1", Spec: "test1"})
+		store.Upsert(&mockResource{UID: kubetypes.UID("test2"), Namespace: "cluster2", Spec: "test2"})
+	}()
+
+	for _, expected := range []string{"test1", "test2"} {
+		evt := <-events
+		if evt.Action != types.Added || string(evt.Object.UID) != expected {
+			t.Errorf("expected %s is added, but got %v", expected, evt)
+		}
+	}
+
+	objs, err := store.List(types.ListOptions{ClusterName: "cluster1"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(objs) != 1 || objs[0].UID != "test1" {
+		t.Errorf("expected test1 in cluster1, but got %v", objs)
+	}
+
+	objs, err = store.List(types.ListOptions{ClusterName: types.ClusterAll})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(objs) != 2 {
+		t.Errorf("expected 2 resources, but got %v", objs)
+	}
+
+	go func() {
+		if err := store.UpsertStatus(&mockResource{UID: kubetypes.UID("test1"), Status: "applied"}); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	}()
+
+	if evt := <-events; evt.Action != types.StatusModified {
+		t.Errorf("expected status modified, but got %v", evt)
+	}
+
+	obj, exists, err := store.Get("test1")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !exists || obj.Spec != "test1" || obj.Status != "applied" {
+		t.Errorf("expected the status of test1 is merged, but got %v", obj)
+	}
+}
+
+func TestSourceClientWithStore(t *testing.T) {
+	store := NewMemoryResourceStore[*mockResource](nil, nil)
+	store.Upsert(&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "test1"})
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}
+	evt, err := newMockResourceCodec().Encode(testAgentName, eventType,
+		&mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Status: "update-test1"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	evt.SetExtension("clustername", "cluster1")
+
+	fakeClient := fake.NewCloudEventsFakeClient(*evt)
+	sourceOptions := fake.NewSourceOptions(fakeClient, testSourceName)
+	source, err := NewCloudEventSourceClientWithStore[*mockResource](
+		context.TODO(), sourceOptions, store, statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var actualEvent types.ResourceAction
+	source.receive(context.TODO(), *evt, func(event types.ResourceAction, resource *mockResource) error {
+		actualEvent = event
+		return nil
+	})
+
+	if actualEvent != types.StatusModified {
+		t.Errorf("expected modified, but get %s", actualEvent)
+	}
+
+	obj, _, err := source.Store().Get("test1")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if obj.Status != "update-test1" {
+		t.Errorf("expected the status is saved to the store, but got %v", obj)
+	}
+}
@@ -0,0 +1,163 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// Subscription is a handle of the handlers that are subscribed to a source/agent client.
+type Subscription interface {
+	// Unsubscribe stops handling the received events with the handlers of the subscription.
+	Unsubscribe()
+}
+
+// SubscriptionOptions restricts the events that are delivered to the handlers of a subscription. An event is delivered
+// only if it matches all of the non-empty conditions.
+type SubscriptionOptions struct {
+	// DataTypes is the list of the event data types that are delivered.
+	DataTypes []types.CloudEventsDataType
+
+	// Actions is the list of the event actions that are delivered, e.g. `update_request`.
+	Actions []types.EventAction
+
+	// ClusterNames is the list of the cluster name patterns that are delivered, the pattern syntax is the same as
+	// path.Match, e.g. `cluster-*`.
+	ClusterNames []string
+}
+
+// Matches returns true if the event type and the cluster name of the given event match the options.
+func (o SubscriptionOptions) Matches(evt cloudevents.Event) (bool, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return false, fmt.Errorf("failed to parse cloud event type %s, %v", evt.Type(), err)
+	}
+
+	if len(o.DataTypes) != 0 && !slices.Contains(o.DataTypes, eventType.CloudEventsDataType) {
+		return false, nil
+	}
+
+	if len(o.Actions) != 0 && !slices.Contains(o.Actions, eventType.Action) {
+		return false, nil
+	}
+
+	if len(o.ClusterNames) == 0 {
+		return true, nil
+	}
+
+	clusterName, err := evt.Context.GetExtension(types.ExtensionClusterName)
+	if err != nil {
+		return false, nil
+	}
+
+	for _, pattern := range o.ClusterNames {
+		matched, err := path.Match(pattern, fmt.Sprintf("%s", clusterName))
+		if err != nil {
+			return false, fmt.Errorf("invalid cluster name pattern %q, %v", pattern, err)
+		}
+
+		if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type subscriber[T ResourceObject] struct {
+	opts          *SubscriptionOptions
+	handlers      []ResourceHandler[T]
+	eventHandlers []EventHandler[T]
+	registry      *subscriberRegistry[T]
+	once          sync.Once
+}
+
+func (s *subscriber[T]) Unsubscribe() {
+	s.once.Do(func() {
+		s.registry.remove(s)
+	})
+}
+
+// subscriberRegistry holds the subscribers of a client, the received events are fanned out to the handlers of all the
+// subscribers.
+type subscriberRegistry[T ResourceObject] struct {
+	sync.RWMutex
+	subscribers []*subscriber[T]
+	receiving   sync.Once
+}
+
+// add registers the handlers as a subscriber, the subscriber is removed when the given context is done. The receive
+// function is called only once for the first subscriber to start receiving the events.
+func (r *subscriberRegistry[T]) add(ctx context.Context, opts *SubscriptionOptions,
+	handlers []ResourceHandler[T], receive func()) *subscriber[T] {
+	return r.addSubscriber(ctx, &subscriber[T]{opts: opts, handlers: handlers}, receive)
+}
+
+// addEventHandlers registers the event handlers as a subscriber, see add.
+func (r *subscriberRegistry[T]) addEventHandlers(ctx context.Context, opts *SubscriptionOptions,
+	eventHandlers []EventHandler[T], receive func()) *subscriber[T] {
+	return r.addSubscriber(ctx, &subscriber[T]{opts: opts, eventHandlers: eventHandlers}, receive)
+}
+
+func (r *subscriberRegistry[T]) addSubscriber(ctx context.Context, s *subscriber[T], receive func()) *subscriber[T] {
+	s.registry = r
+
+	r.Lock()
+	r.subscribers = append(r.subscribers, s)
+	r.Unlock()
+
+	context.AfterFunc(ctx, s.Unsubscribe)
+	r.receiving.Do(receive)
+	return s
+}
+
+func (r *subscriberRegistry[T]) remove(s *subscriber[T]) {
+	r.Lock()
+	defer r.Unlock()
+
+	for i, subscriber := range r.subscribers {
+		if subscriber == s {
+			r.subscribers = append(r.subscribers[:i:i], r.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// handlers returns the handlers of the subscribers whose options match the given event, the event handlers are bound to
+// the event.
+func (r *subscriberRegistry[T]) handlers(ctx context.Context, evt cloudevents.Event) []ResourceHandler[T] {
+	r.RLock()
+	defer r.RUnlock()
+
+	handlers := []ResourceHandler[T]{}
+	for _, s := range r.subscribers {
+		if s.opts != nil {
+			matched, err := s.opts.Matches(evt)
+			if err != nil {
+				klog.Errorf("failed to match event %s, %v", evt.ID(), err)
+				continue
+			}
+
+			if !matched {
+				continue
+			}
+		}
+
+		handlers = append(handlers, s.handlers...)
+		for _, eventHandler := range s.eventHandlers {
+			eventHandler := eventHandler
+			handlers = append(handlers, func(action types.ResourceAction, obj T) error {
+				return eventHandler(ctx, evt, action, obj)
+			})
+		}
+	}
+	return handlers
+}
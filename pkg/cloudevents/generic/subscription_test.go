@@ -0,0 +1,251 @@
+package generic
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestSubscriptionOptions(t *testing.T) {
+	otherDataType := types.CloudEventsDataType{Group: "test", Version: "v1", Resource: "others"}
+
+	cases := []struct {
+		name        string
+		opts        SubscriptionOptions
+		dataType    types.CloudEventsDataType
+		action      types.EventAction
+		clusterName string
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			name:        "no conditions",
+			dataType:    mockEventDataType,
+			action:      "update_request",
+			clusterName: "cluster1",
+			expected:    true,
+		},
+		{
+			name:        "data type matched",
+			opts:        SubscriptionOptions{DataTypes: []types.CloudEventsDataType{mockEventDataType}},
+			dataType:    mockEventDataType,
+			action:      "update_request",
+			clusterName: "cluster1",
+			expected:    true,
+		},
+		{
+			name:        "data type mismatched",
+			opts:        SubscriptionOptions{DataTypes: []types.CloudEventsDataType{otherDataType}},
+			dataType:    mockEventDataType,
+			action:      "update_request",
+			clusterName: "cluster1",
+			expected:    false,
+		},
+		{
+			name:        "action mismatched",
+			opts:        SubscriptionOptions{Actions: []types.EventAction{"delete_request"}},
+			dataType:    mockEventDataType,
+			action:      "update_request",
+			clusterName: "cluster1",
+			expected:    false,
+		},
+		{
+			name:        "cluster name matched",
+			opts:        SubscriptionOptions{ClusterNames: []string{"prod-*"}},
+			dataType:    mockEventDataType,
+			action:      "update_request",
+			clusterName: "prod-east",
+			expected:    true,
+		},
+		{
+			name:        "cluster name mismatched",
+			opts:        SubscriptionOptions{ClusterNames: []string{"prod-*"}},
+			dataType:    mockEventDataType,
+			action:      "update_request",
+			clusterName: "dev-east",
+			expected:    false,
+		},
+		{
+			name:        "invalid cluster name pattern",
+			opts:        SubscriptionOptions{ClusterNames: []string{"prod-["}},
+			dataType:    mockEventDataType,
+			action:      "update_request",
+			clusterName: "prod-east",
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evt := cloudevents.NewEvent()
+			evt.SetType(types.CloudEventsType{
+				CloudEventsDataType: c.dataType,
+				SubResource:         types.SubResourceStatus,
+				Action:              c.action,
+			}.String())
+			evt.SetExtension(types.ExtensionClusterName, c.clusterName)
+
+			matched, err := c.opts.Matches(evt)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			if matched != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, matched)
+			}
+		})
+	}
+}
+
+func TestSubscriberRegistry(t *testing.T) {
+	registry := &subscriberRegistry[*mockResource]{}
+	handler := func(action types.ResourceAction, obj *mockResource) error { return nil }
+
+	receiving := 0
+	receive := func() { receiving++ }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	all := registry.add(context.Background(), nil, []ResourceHandler[*mockResource]{handler}, receive)
+	registry.add(context.Background(), &SubscriptionOptions{ClusterNames: []string{"cluster1"}},
+		[]ResourceHandler[*mockResource]{handler}, receive)
+	registry.add(ctx, nil, []ResourceHandler[*mockResource]{handler, handler}, receive)
+
+	if receiving != 1 {
+		t.Errorf("expected receiving is started once, but got %d", receiving)
+	}
+
+	evt := cloudevents.NewEvent()
+	evt.SetType(types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "update_request",
+	}.String())
+	evt.SetExtension(types.ExtensionClusterName, "cluster2")
+
+	if handlers := registry.handlers(context.TODO(), evt); len(handlers) != 3 {
+		t.Errorf("expected 3 handlers, but got %d", len(handlers))
+	}
+
+	all.Unsubscribe()
+	all.Unsubscribe()
+	if handlers := registry.handlers(context.TODO(), evt); len(handlers) != 2 {
+		t.Errorf("expected 2 handlers, but got %d", len(handlers))
+	}
+
+	cancel()
+	if err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			return len(registry.handlers(context.TODO(), evt)) == 0, nil
+		}); err != nil {
+		t.Errorf("expected the subscription is removed after its context is done, %v", err)
+	}
+}
+
+func TestSubscribeWithEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx,
+		fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster1", testAgentName),
+		newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var received []cloudevents.Event
+	var actions []types.ResourceAction
+	agent.SubscribeWithEvents(ctx, func(ctx context.Context, evt cloudevents.Event, action types.ResourceAction, obj *mockResource) error {
+		if obj.UID != "test1" {
+			t.Errorf("expected test1, but got %s", obj.UID)
+		}
+		received = append(received, evt)
+		actions = append(actions, action)
+		return nil
+	})
+
+	evt, err := newMockResourceCodec().Encode(testSourceName, types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	evt.SetExtension("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	agent.receive(ctx, *evt, agent.subscribers.handlers(ctx, *evt)...)
+
+	if !reflect.DeepEqual(actions, []types.ResourceAction{types.Added}) {
+		t.Fatalf("expected the resource is added, but got %v", actions)
+	}
+	if received[0].ID() != evt.ID() || received[0].Source() != testSourceName {
+		t.Errorf("expected the event %s, but got %s", evt.ID(), received[0].ID())
+	}
+	if received[0].Extensions()["traceparent"] != evt.Extensions()["traceparent"] {
+		t.Errorf("expected the custom extension, but got %v", received[0].Extensions())
+	}
+}
+
+func TestSubscribeAfterFirstSubscriptionCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	agent, err := NewCloudEventAgentClient[*mockResource](ctx,
+		fake.NewAgentOptions(fake.NewCloudEventsFakeClient(), "cluster1", testAgentName),
+		newMockResourceLister(), statusHash, newMockResourceCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first subscription starts the receiving and is cancelled
+	firstCtx, firstCancel := context.WithCancel(ctx)
+	agent.Subscribe(firstCtx, func(action types.ResourceAction, obj *mockResource) error { return nil })
+	firstCancel()
+
+	received := make(chan kubetypes.UID, 1)
+	agent.Subscribe(ctx, func(action types.ResourceAction, obj *mockResource) error {
+		received <- obj.UID
+		return nil
+	})
+
+	evt, err := newMockResourceCodec().Encode(testSourceName, types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}, &mockResource{UID: kubetypes.UID("test1"), ResourceVersion: "1", Namespace: "cluster1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the client is reconnected with a transport that delivers the event after the first subscription is cancelled
+	time.Sleep(100 * time.Millisecond)
+	if err := agent.SwitchTransport(ctx,
+		fake.NewAgentOptions(fake.NewCloudEventsFakeClient(*evt), "cluster1", testAgentName)); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case uid := <-received:
+		if uid != "test1" {
+			t.Errorf("expected test1, but got %s", uid)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the second subscription receives the event after the client is reconnected")
+	}
+}
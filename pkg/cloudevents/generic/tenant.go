@@ -0,0 +1,54 @@
+package generic
+
+import (
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// labelTenant sets the tenant extension to an event that is being sent if the client has a tenant, an event that is
+// labeled with another tenant cannot be sent.
+func (c *baseClient) labelTenant(evt cloudevents.Event) (cloudevents.Event, error) {
+	if c.tenantID == "" {
+		return evt, nil
+	}
+
+	if tenantID, ok := evt.Extensions()[types.ExtensionTenantID]; ok {
+		if fmt.Sprintf("%v", tenantID) != c.tenantID {
+			return evt, fmt.Errorf("failed to send event %s, it belongs to the tenant %v instead of %s",
+				evt.ID(), tenantID, c.tenantID)
+		}
+		return evt, nil
+	}
+
+	labeledEvt := evt.Clone()
+	labeledEvt.SetExtension(types.ExtensionTenantID, c.tenantID)
+	return labeledEvt, nil
+}
+
+// checkTenant returns an error if a received event does not belong to the tenant of the client. A client without a
+// tenant accepts the events of all tenants.
+func (c *baseClient) checkTenant(evt cloudevents.Event) error {
+	if c.tenantID == "" {
+		return nil
+	}
+
+	val, ok := evt.Extensions()[types.ExtensionTenantID]
+	if !ok {
+		return fmt.Errorf("the event does not have the tenant, expected tenant %s", c.tenantID)
+	}
+
+	tenantID, err := cloudeventstypes.ToString(val)
+	if err != nil {
+		return fmt.Errorf("failed to get the tenant of the event, %v", err)
+	}
+
+	if tenantID != c.tenantID {
+		return fmt.Errorf("the event belongs to the tenant %s, expected tenant %s", tenantID, c.tenantID)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package generic
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestLabelTenant(t *testing.T) {
+	cases := []struct {
+		name           string
+		tenantID       string
+		eventTenantID  string
+		expectedTenant string
+		expectedErr    bool
+	}{
+		{
+			name: "no tenant",
+		},
+		{
+			name:           "label the tenant",
+			tenantID:       "tenant1",
+			expectedTenant: "tenant1",
+		},
+		{
+			name:           "the tenant is already labeled",
+			tenantID:       "tenant1",
+			eventTenantID:  "tenant1",
+			expectedTenant: "tenant1",
+		},
+		{
+			name:          "the event belongs to another tenant",
+			tenantID:      "tenant1",
+			eventTenantID: "tenant2",
+			expectedErr:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &baseClient{tenantID: c.tenantID}
+
+			evt := cloudevents.NewEvent()
+			if c.eventTenantID != "" {
+				evt.SetExtension(types.ExtensionTenantID, c.eventTenantID)
+			}
+
+			labeledEvt, err := client.labelTenant(evt)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			tenantID, _ := labeledEvt.Extensions()[types.ExtensionTenantID].(string)
+			if tenantID != c.expectedTenant {
+				t.Errorf("expected %s, but got %s", c.expectedTenant, tenantID)
+			}
+
+			if c.eventTenantID == "" {
+				if _, ok := evt.Extensions()[types.ExtensionTenantID]; ok {
+					t.Errorf("expected the original event is not modified")
+				}
+			}
+		})
+	}
+}
+
+func TestCheckTenant(t *testing.T) {
+	cases := []struct {
+		name          string
+		tenantID      string
+		eventTenantID string
+		expectedErr   bool
+	}{
+		{
+			name: "no tenant",
+		},
+		{
+			name:          "client without tenant accepts the events of a tenant",
+			eventTenantID: "tenant1",
+		},
+		{
+			name:          "same tenant",
+			tenantID:      "tenant1",
+			eventTenantID: "tenant1",
+		},
+		{
+			name:          "cross tenant",
+			tenantID:      "tenant1",
+			eventTenantID: "tenant2",
+			expectedErr:   true,
+		},
+		{
+			name:        "the event does not have the tenant",
+			tenantID:    "tenant1",
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &baseClient{tenantID: c.tenantID}
+
+			evt := cloudevents.NewEvent()
+			if c.eventTenantID != "" {
+				evt.SetExtension(types.ExtensionTenantID, c.eventTenantID)
+			}
+
+			err := client.checkTenant(evt)
+			if c.expectedErr && err == nil {
+				t.Errorf("expected error, but got nil")
+			}
+			if !c.expectedErr && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
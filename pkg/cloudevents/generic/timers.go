@@ -0,0 +1,34 @@
+package generic
+
+import (
+	"math/rand"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+// clockOrDefault returns the given clock of the timers of a client, it is the real clock if the clock is not set.
+func clockOrDefault(c clock.WithTickerAndDelayedExecution) clock.WithTickerAndDelayedExecution {
+	if c == nil {
+		return clock.RealClock{}
+	}
+	return c
+}
+
+// reconnectJitter returns the jitter factor of the reconnect backoff.
+func reconnectJitter(jitter options.Jitter) float64 {
+	if jitter.Reconnect <= 0 {
+		return options.DefaultReconnectJitter
+	}
+	return jitter.Reconnect
+}
+
+// randomDelay returns a random delay in [0, maxDelay), it is zero if the maxDelay is less than or equal to zero.
+func randomDelay(maxDelay time.Duration) time.Duration {
+	if maxDelay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(maxDelay)))
+}
@@ -0,0 +1,94 @@
+package generic
+
+import (
+	"testing"
+	"time"
+
+	testingclock "k8s.io/utils/clock/testing"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+)
+
+func TestReconnectedSignalWithJitter(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	client := &baseClient{
+		reconnectedChan: make(chan struct{}, 1),
+		stopChan:        make(chan struct{}),
+		clock:           fakeClock,
+		jitter:          options.Jitter{Resync: time.Minute},
+	}
+
+	client.sendReconnectedSignalWithJitter()
+	select {
+	case <-client.reconnectedChan:
+		t.Fatalf("expected the reconnected signal is delayed")
+	default:
+	}
+
+	fakeClock.Step(time.Minute)
+	select {
+	case <-client.reconnectedChan:
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected the reconnected signal after the jitter")
+	}
+
+	// the delayed signal is dropped after the client is stopped
+	client.sendReconnectedSignalWithJitter()
+	close(client.stopChan)
+	fakeClock.Step(time.Minute)
+	select {
+	case <-client.reconnectedChan:
+		t.Errorf("unexpected reconnected signal")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestReconnectedSignalWithoutLock(t *testing.T) {
+	client := &baseClient{
+		reconnectedChan: make(chan struct{}),
+		stopChan:        make(chan struct{}),
+	}
+	defer close(client.stopChan)
+
+	// the signal is blocked until it is received, the lock of the client is not held while it is blocked
+	go client.sendReconnectedSignal()
+	time.Sleep(100 * time.Millisecond)
+
+	locked := make(chan struct{})
+	go func() {
+		client.Lock()
+		defer client.Unlock()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the lock is not held by the blocked signal")
+	}
+
+	select {
+	case <-client.reconnectedChan:
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected the reconnected signal")
+	}
+}
+
+func TestRandomDelay(t *testing.T) {
+	if delay := randomDelay(0); delay != 0 {
+		t.Errorf("expected 0, but got %v", delay)
+	}
+
+	for i := 0; i < 100; i++ {
+		if delay := randomDelay(time.Second); delay < 0 || delay >= time.Second {
+			t.Errorf("expected the delay in [0, 1s), but got %v", delay)
+		}
+	}
+
+	if jitter := reconnectJitter(options.Jitter{}); jitter != options.DefaultReconnectJitter {
+		t.Errorf("expected %v, but got %v", options.DefaultReconnectJitter, jitter)
+	}
+	if jitter := reconnectJitter(options.Jitter{Reconnect: 0.5}); jitter != 0.5 {
+		t.Errorf("expected 0.5, but got %v", jitter)
+	}
+}
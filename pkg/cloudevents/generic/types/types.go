@@ -2,10 +2,13 @@ package types
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
 	"github.com/google/uuid"
 )
 
@@ -39,8 +42,45 @@ const (
 
 	// ResyncRequestAction represents the cloud event is for the resync response.
 	ResyncResponseAction EventAction = "resync_response"
+
+	// RegisterRequestAction represents the cloud event is for the registration request of an agent.
+	RegisterRequestAction EventAction = "register_request"
+
+	// RegisterResponseAction represents the cloud event is for the registration response of a source.
+	RegisterResponseAction EventAction = "register_response"
+
+	// HandoverRequestAction represents the cloud event is for handing over a resource from its source to another
+	// source, the agent marks the resource as migrating to the target source.
+	HandoverRequestAction EventAction = "handover_request"
+
+	// ClaimRequestAction represents the cloud event is for claiming a resource that is handed over from another source,
+	// the agent switches the original source of the resource to the claiming source.
+	ClaimRequestAction EventAction = "claim_request"
+
+	// CallRequestAction represents the cloud event is for the request of a call, the response of the call is correlated
+	// with the ID of the request event.
+	CallRequestAction EventAction = "call_request"
+
+	// CallResponseAction represents the cloud event is for the response of a call.
+	CallResponseAction EventAction = "call_response"
+
+	// DeliveryReceiptAction represents the cloud event is for the delivery receipt of a resource spec, an agent sends
+	// it once the spec is decoded if the source requests it with the ackrequested extension.
+	DeliveryReceiptAction EventAction = "delivery_receipt"
+
+	// ResyncCompleteAction represents the cloud event marks the end of the resync response of a source, a source sends
+	// it after it resends the resource specs of a spec resync request, its correlationid is the ID of the request.
+	ResyncCompleteAction EventAction = "resync_complete"
 )
 
+// RegistrationDataType is the cloud event data type of the agent registration requests and responses, the
+// registration is not bound to the data type of a resource.
+var RegistrationDataType = CloudEventsDataType{
+	Group:    "io.open-cluster-management.agents",
+	Version:  "v1",
+	Resource: "registrations",
+}
+
 const (
 	// ExtensionResourceID is the cloud event extension key of the resource ID.
 	ExtensionResourceID = "resourceid"
@@ -48,6 +88,16 @@ const (
 	// ExtensionResourceVersion is the cloud event extension key of the resource version.
 	ExtensionResourceVersion = "resourceversion"
 
+	// ExtensionGeneration is the cloud event extension key of the generation of the resource spec. The generation is an
+	// optional integer property of the spec events, unlike the resource version, it is only changed when the spec of
+	// the resource is changed.
+	ExtensionGeneration = "generation"
+
+	// ExtensionObservedGeneration is the cloud event extension key of the observed generation of the resource status.
+	// The observed generation is an optional integer property of the status events, it is the generation of the spec
+	// that the status is observed with.
+	ExtensionObservedGeneration = "observedgeneration"
+
 	// ExtensionStatusUpdateSequenceID is the cloud event extension key of the status update event sequence ID.
 	// The status update event sequence id represents the order in which status update events occur on a single agent.
 	ExtensionStatusUpdateSequenceID = "sequenceid"
@@ -60,25 +110,159 @@ const (
 
 	// ExtensionOriginalSource is the cloud event extension key of the original source.
 	ExtensionOriginalSource = "originalsource"
+
+	// ExtensionDeletePropagationPolicy is the cloud event extension key of the delete propagation policy.
+	ExtensionDeletePropagationPolicy = "deletepropagationpolicy"
+
+	// ExtensionDeleteTTLSeconds is the cloud event extension key of the delete TTL seconds.
+	ExtensionDeleteTTLSeconds = "deletettlseconds"
+
+	// ExtensionExpirationTime is the cloud event extension key of the expiration time. The expiration time is an
+	// optional timestamp property, the receiver discards the event if it is received after this time.
+	ExtensionExpirationTime = "expirationtime"
+
+	// ExtensionClaimCheckKey is the cloud event extension key of the object store key of the offloaded event data.
+	ExtensionClaimCheckKey = "claimcheckkey"
+
+	// ExtensionClaimCheckDigest is the cloud event extension key of the SHA-256 digest of the offloaded event data.
+	ExtensionClaimCheckDigest = "claimcheckdigest"
+
+	// ExtensionClaimCheckSignature is the cloud event extension key of the HMAC-SHA256 signature of the claim-check
+	// key and digest.
+	ExtensionClaimCheckSignature = "claimchecksignature"
+
+	// ExtensionEncryptionKeyID is the cloud event extension key of the ID of the key that encrypts the event data.
+	ExtensionEncryptionKeyID = "encryptionkeyid"
+
+	// ExtensionEncryptedDataContentType is the cloud event extension key of the content type of the event data before
+	// it is encrypted.
+	ExtensionEncryptedDataContentType = "encrypteddatacontenttype"
+
+	// ExtensionPriority is the cloud event extension key of the priority of the event. The priority is an optional
+	// integer property, the receivers that have the priority lanes process the events with a higher priority ahead of
+	// the others.
+	ExtensionPriority = "priority"
+
+	// ExtensionCapabilities is the cloud event extension key of the comma separated features that are supported by the
+	// sender, it is set on the resync requests, see the features package.
+	ExtensionCapabilities = "capabilities"
+
+	// ExtensionTenantID is the cloud event extension key of the tenant ID, the events of a tenant are only handled by
+	// the clients of the same tenant.
+	ExtensionTenantID = "tenantid"
+
+	// ExtensionHandoverTarget is the cloud event extension key of the source that a resource is handed over to, it is
+	// set on the handover requests, an empty target cancels the handover of the resource.
+	ExtensionHandoverTarget = "handovertarget"
+
+	// ExtensionHandoverSource is the cloud event extension key of the source that a resource is handed over from, it
+	// is set on the claim requests.
+	ExtensionHandoverSource = "handoversource"
+
+	// ExtensionCorrelationID is the cloud event extension key of the ID of the call request that a call response
+	// responds, or of the spec resync request that a resync complete event completes.
+	ExtensionCorrelationID = "correlationid"
+
+	// ExtensionReplyTo is the cloud event extension key of the source ID or the cluster name that the response of a call
+	// request is sent to.
+	ExtensionReplyTo = "replyto"
+
+	// ExtensionCallError is the cloud event extension key of the error of a failed call, it is set on the call
+	// responses.
+	ExtensionCallError = "callerror"
+
+	// ExtensionAckRequested is the cloud event extension key of whether the source requests a delivery receipt of a
+	// resource spec event.
+	ExtensionAckRequested = "ackrequested"
+
+	// ExtensionBridgeHops is the cloud event extension key of the comma separated IDs of the bridges that have relayed
+	// the event between the brokers, it is used to prevent the relay loops.
+	ExtensionBridgeHops = "bridgehops"
+
+	// ExtensionLeafClusterName is the cloud event extension key of the name of the leaf cluster that an event comes
+	// from, it is set when the event is rescoped to the cluster of an intermediate hub.
+	ExtensionLeafClusterName = "leafclustername"
+
+	// ExtensionUserAgent is the cloud event extension key of the user agent of the sender, it is the product of the sdk
+	// and its version followed by the components that are built with the sdk, e.g. "ocm-sdk-go/v0.16.0
+	// work-agent/v0.16.1", so the receivers can audit which versions of the senders are in the fleet.
+	ExtensionUserAgent = "useragent"
+
+	// ExtensionStatusHashAlgorithm is the cloud event extension key of the algorithm of the status hashes in a status
+	// resync request, the status hashes are the SHA256 checksums if it is not set, e.g. the request is sent by an older
+	// source.
+	ExtensionStatusHashAlgorithm = "statushashalgorithm"
+
+	// ExtensionBroadcast is the cloud event extension key of whether a resource spec event is broadcast to all
+	// clusters, the clustername extension of a broadcast spec event is empty.
+	ExtensionBroadcast = "broadcast"
 )
 
-// ResourceAction represents an action on a resource object on the source or agent.
+const (
+	// PriorityNormal is the default priority of the resource spec and status events.
+	PriorityNormal = 0
+
+	// PriorityHigh is the default priority of the resync requests, the resync responses, the registration requests, the
+	// registration responses, the call requests, the call responses and the delete events.
+	PriorityHigh = 1
+)
+
+// DeleteOption represents the deletion strategy of a resource when the resource is deleted from the source, it is
+// carried with the deletion timestamp in a spec event.
+type DeleteOption struct {
+	// PropagationPolicy represents how the agent handles the resource on its cluster, e.g. `Foreground` means the
+	// agent cleans up the resource, `Orphan` means the agent keeps the resource on its cluster.
+	PropagationPolicy string
+
+	// TTLSeconds is the time in seconds the agent waits before cleaning up the resource from its cluster.
+	TTLSeconds *int64
+}
+
+// ResourceAction represents an action on a resource object on the source or agent. The spec actions are passed to the
+// handlers of an agent and the status actions are passed to the handlers of a source, they are derived from the event
+// type and the resource in the local cache, so the handlers can tell the changes apart without diffing the resources.
 type ResourceAction string
 
+// The spec actions that are passed to the handlers of an agent.
 const (
-	// Added represents a resource is added on the source part.
+	// Added represents a resource is created on the source part, the agent does not have the resource and the resource
+	// is not deleting.
 	Added ResourceAction = "ADDED"
 
-	// Modified represents a resource is modified on the source part.
+	// Modified represents the spec of a resource is updated on the source part, the agent has the resource and the
+	// resource version of the spec is different from the local one.
 	Modified ResourceAction = "MODIFIED"
 
-	// StatusModified represents the status of a resource is modified on the agent part.
+	// Deleted represents a resource that the agent has is deleting on the source part.
+	Deleted ResourceAction = "DELETED"
+
+	// Claimed represents a resource is claimed by another source on the source part, the agent switches the original
+	// source of the resource to the claiming source without recreating the resource.
+	Claimed ResourceAction = "CLAIMED"
+
+	// SpecResynced represents the spec of a resource that the agent has is resent by a resync response without a
+	// resource version change, the handlers can skip it without comparing the specs. It is only passed to the handlers
+	// when the SpecResyncedAction of the agent options is enabled.
+	SpecResynced ResourceAction = "SPECRESYNCED"
+)
+
+// The status actions that are passed to the handlers of a source.
+const (
+	// StatusModified represents the status of a resource is modified on the agent part, the status hash is different
+	// from the one of the resource in the source.
 	StatusModified ResourceAction = "STATUSMODIFIED"
 
-	// Deleted represents a resource is deleted from the source prat.
-	Deleted ResourceAction = "DELETED"
+	// StatusResynced represents the status of a resource is resent by a resync response without a status change, the
+	// handlers can skip it without comparing the statuses. It is only passed to the handlers when the
+	// StatusResyncedAction of the source options is enabled.
+	StatusResynced ResourceAction = "STATUSRESYNCED"
 )
 
+// IsStatusAction returns true if the action is a status action of a source.
+func (a ResourceAction) IsStatusAction() bool {
+	return a == StatusModified || a == StatusResynced
+}
+
 const (
 	EventsTopicPattern          = `^(\$share/[a-z0-9-]+/)?([a-z]+)/([a-z0-9-]+|\+)/([a-z]+)/([a-z0-9-]+|\+)/(sourceevents|agentevents)$`
 	SourceEventsTopicPattern    = `^(\$share/[a-z0-9-]+/)?([a-z]+)/([a-z0-9-]+|\+)/([a-z]+)/([a-z0-9-]+|\+)/sourceevents$`
@@ -126,6 +310,42 @@ type Topics struct {
 	AgentBroadcast string `json:"agentBroadcast,omitempty" yaml:"agentBroadcast,omitempty"`
 }
 
+var tenantIDRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ValidateTenantID returns an error if the tenant ID is not a valid topic segment, a tenant ID consists of lower case
+// alphanumeric characters or '-', and starts and ends with an alphanumeric character. The empty tenant ID is valid.
+func ValidateTenantID(tenantID string) error {
+	if tenantID != "" && !tenantIDRegex.MatchString(tenantID) {
+		return fmt.Errorf("invalid tenant ID %q, it must consist of lower case alphanumeric characters or '-'", tenantID)
+	}
+	return nil
+}
+
+// IsBroadcast returns true if the event has the broadcast extension, i.e. a resource spec event that is broadcast to
+// all clusters.
+func IsBroadcast(evt cloudevents.Event) bool {
+	broadcast, err := cloudeventstypes.ToBool(evt.Extensions()[ExtensionBroadcast])
+	return err == nil && broadcast
+}
+
+// TenantTopic returns the topic of a tenant by prefixing the tenant segment `tenants/<tenant-id>/` to the given topic,
+// the segment is inserted after the shared subscription prefix `$share/<group>/` if the topic has one. The topic is
+// returned as it is if the tenant ID is empty.
+func TenantTopic(tenantID, topic string) string {
+	if tenantID == "" {
+		return topic
+	}
+
+	tenant := "tenants/" + tenantID + "/"
+	if strings.HasPrefix(topic, "$share/") {
+		if i := strings.Index(topic[len("$share/"):], "/"); i >= 0 {
+			i += len("$share/") + 1
+			return topic[:i] + tenant + topic[i:]
+		}
+	}
+	return tenant + topic
+}
+
 // ListOptions is the query options for listing the resource objects from the source/agent.
 type ListOptions struct {
 	// Source use the cluster name to restrict the list of returned objects by their cluster name.
@@ -145,7 +365,8 @@ type CloudEventsDataType struct {
 }
 
 func (t CloudEventsDataType) String() string {
-	return fmt.Sprintf("%s.%s.%s", t.Group, t.Version, t.Resource)
+	// the string is built for every sent event, concatenate the string instead of formatting it to reduce allocations
+	return t.Group + "." + t.Version + "." + t.Resource
 }
 
 // CloudEventsType represents the type of cloud events, which describes the type of cloud event data.
@@ -161,7 +382,7 @@ type CloudEventsType struct {
 }
 
 func (t CloudEventsType) String() string {
-	return fmt.Sprintf("%s.%s.%s.%s.%s", t.Group, t.Version, t.Resource, t.SubResource, t.Action)
+	return t.Group + "." + t.Version + "." + t.Resource + "." + string(t.SubResource) + "." + string(t.Action)
 }
 
 // ParseCloudEventsDataType parse  the cloud event data type to a struct object.
@@ -206,14 +427,23 @@ func ParseCloudEventsType(cloudEventsType string) (*CloudEventsType, error) {
 }
 
 type EventBuilder struct {
-	source            string
-	clusterName       string
-	originalSource    string
-	resourceID        string
-	sequenceID        string
-	resourceVersion   *int64
-	eventType         CloudEventsType
-	deletionTimestamp time.Time
+	source             string
+	id                 string
+	subject            string
+	time               time.Time
+	clusterName        string
+	originalSource     string
+	resourceID         string
+	sequenceID         string
+	resourceVersion    any
+	eventType          CloudEventsType
+	deletionTimestamp  time.Time
+	deleteOption       *DeleteOption
+	expirationTime     time.Time
+	priority           *int
+	capabilities       string
+	generation         int64
+	observedGeneration int64
 }
 
 func NewEventBuilder(source string, eventType CloudEventsType) *EventBuilder {
@@ -229,7 +459,32 @@ func (b *EventBuilder) WithResourceID(resourceID string) *EventBuilder {
 }
 
 func (b *EventBuilder) WithResourceVersion(resourceVersion int64) *EventBuilder {
-	b.resourceVersion = &resourceVersion
+	b.resourceVersion = resourceVersion
+	return b
+}
+
+// WithStringResourceVersion sets a resource version that is not necessarily an integer, e.g. a Kubernetes
+// resourceVersion or a content hash. The integer versions are still set as integers, so the peers that only support
+// the integer versions can decode them.
+func (b *EventBuilder) WithStringResourceVersion(resourceVersion string) *EventBuilder {
+	if version, err := strconv.ParseInt(resourceVersion, 10, 64); err == nil {
+		return b.WithResourceVersion(version)
+	}
+
+	b.resourceVersion = resourceVersion
+	return b
+}
+
+// WithGeneration sets the generation of the resource spec, it is not set if it is less than or equal to zero.
+func (b *EventBuilder) WithGeneration(generation int64) *EventBuilder {
+	b.generation = generation
+	return b
+}
+
+// WithObservedGeneration sets the generation of the spec that the resource status is observed with, it is not set if
+// it is less than or equal to zero.
+func (b *EventBuilder) WithObservedGeneration(observedGeneration int64) *EventBuilder {
+	b.observedGeneration = observedGeneration
 	return b
 }
 
@@ -253,13 +508,73 @@ func (b *EventBuilder) WithDeletionTimestamp(timestamp time.Time) *EventBuilder
 	return b
 }
 
+// WithDeleteOption sets the delete option of a deleting resource, it is only used with the deletion timestamp.
+func (b *EventBuilder) WithDeleteOption(deleteOption DeleteOption) *EventBuilder {
+	b.deleteOption = &deleteOption
+	return b
+}
+
+// WithExpirationTime sets the expiration time of the event, the event will be discarded if it is received after this
+// time.
+func (b *EventBuilder) WithExpirationTime(expirationTime time.Time) *EventBuilder {
+	b.expirationTime = expirationTime
+	return b
+}
+
+// WithPriority sets the priority of the event, by default, the priority of the event is determined by its type, see
+// GetPriority.
+func (b *EventBuilder) WithPriority(priority int) *EventBuilder {
+	b.priority = &priority
+	return b
+}
+
+// WithCapabilities sets the comma separated features that are supported by the sender of the event.
+func (b *EventBuilder) WithCapabilities(capabilities string) *EventBuilder {
+	b.capabilities = capabilities
+	return b
+}
+
+// WithID sets the ID of the event, e.g. a deterministic ID that is derived from the resource ID and version, so the
+// redelivered events can be deduplicated by the receivers. A random UUID is used if it is not set.
+func (b *EventBuilder) WithID(id string) *EventBuilder {
+	b.id = id
+	return b
+}
+
+// WithSubject sets the subject of the event, e.g. the namespace and name of the resource, so the brokers can route or
+// filter the events by the subject.
+func (b *EventBuilder) WithSubject(subject string) *EventBuilder {
+	b.subject = subject
+	return b
+}
+
+// WithTime sets the time of the event, the current time is used if it is not set.
+func (b *EventBuilder) WithTime(t time.Time) *EventBuilder {
+	b.time = t
+	return b
+}
+
 func (b *EventBuilder) NewEvent() cloudevents.Event {
 	evt := cloudevents.NewEvent()
-	evt.SetID(uuid.New().String())
+	if evtCtx, ok := evt.Context.(*cloudevents.EventContextV1); ok {
+		// allocate the extensions once for the extensions that are set below
+		evtCtx.Extensions = make(map[string]any, 8)
+	}
+	evt.SetID(b.id)
+	if len(b.id) == 0 {
+		evt.SetID(uuid.New().String())
+	}
 	evt.SetType(b.eventType.String())
-	evt.SetTime(time.Now())
+	evt.SetTime(b.time)
+	if b.time.IsZero() {
+		evt.SetTime(time.Now())
+	}
 	evt.SetSource(b.source)
 
+	if len(b.subject) != 0 {
+		evt.SetSubject(b.subject)
+	}
+
 	evt.SetExtension(ExtensionClusterName, b.clusterName)
 	evt.SetExtension(ExtensionOriginalSource, b.originalSource)
 
@@ -268,16 +583,171 @@ func (b *EventBuilder) NewEvent() cloudevents.Event {
 	}
 
 	if b.resourceVersion != nil {
-		evt.SetExtension(ExtensionResourceVersion, *b.resourceVersion)
+		evt.SetExtension(ExtensionResourceVersion, b.resourceVersion)
 	}
 
 	if len(b.sequenceID) != 0 {
 		evt.SetExtension(ExtensionStatusUpdateSequenceID, b.sequenceID)
 	}
 
+	if b.generation > 0 {
+		evt.SetExtension(ExtensionGeneration, b.generation)
+	}
+
+	if b.observedGeneration > 0 {
+		evt.SetExtension(ExtensionObservedGeneration, b.observedGeneration)
+	}
+
+	if !b.expirationTime.IsZero() {
+		evt.SetExtension(ExtensionExpirationTime, b.expirationTime)
+	}
+
+	if b.priority != nil {
+		evt.SetExtension(ExtensionPriority, *b.priority)
+	}
+
+	if len(b.capabilities) != 0 {
+		evt.SetExtension(ExtensionCapabilities, b.capabilities)
+	}
+
 	if !b.deletionTimestamp.IsZero() {
 		evt.SetExtension(ExtensionDeletionTimestamp, b.deletionTimestamp)
+
+		if b.deleteOption != nil && len(b.deleteOption.PropagationPolicy) != 0 {
+			evt.SetExtension(ExtensionDeletePropagationPolicy, b.deleteOption.PropagationPolicy)
+		}
+
+		if b.deleteOption != nil && b.deleteOption.TTLSeconds != nil {
+			evt.SetExtension(ExtensionDeleteTTLSeconds, *b.deleteOption.TTLSeconds)
+		}
 	}
 
 	return evt
 }
+
+// GetDeleteOption returns the delete option of a cloud event, it returns nil if the event does not have the delete
+// option extensions.
+func GetDeleteOption(evt cloudevents.Event) (*DeleteOption, error) {
+	extensions := evt.Extensions()
+	policy, hasPolicy := extensions[ExtensionDeletePropagationPolicy]
+	ttl, hasTTL := extensions[ExtensionDeleteTTLSeconds]
+	if !hasPolicy && !hasTTL {
+		return nil, nil
+	}
+
+	deleteOption := &DeleteOption{}
+	if hasPolicy {
+		propagationPolicy, err := cloudeventstypes.ToString(policy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deletepropagationpolicy extension: %v", err)
+		}
+		deleteOption.PropagationPolicy = propagationPolicy
+	}
+
+	if hasTTL {
+		ttlSeconds, err := cloudeventstypes.ToInteger(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deletettlseconds extension: %v", err)
+		}
+		ttlSeconds64 := int64(ttlSeconds)
+		deleteOption.TTLSeconds = &ttlSeconds64
+	}
+
+	return deleteOption, nil
+}
+
+// IsExpired returns true if the event has the expiration time extension and the expiration time is before the given
+// time.
+func IsExpired(evt cloudevents.Event, now time.Time) (bool, error) {
+	val, ok := evt.Extensions()[ExtensionExpirationTime]
+	if !ok {
+		return false, nil
+	}
+
+	expirationTime, err := cloudeventstypes.ToTime(val)
+	if err != nil {
+		return false, fmt.Errorf("failed to get expirationtime extension: %v", err)
+	}
+
+	return expirationTime.Before(now), nil
+}
+
+// GetResourceVersion returns the resource version of the event as a string, the resource version can be an integer
+// sequence number or a string version, see EventBuilder.WithStringResourceVersion.
+func GetResourceVersion(evt cloudevents.Event) (string, error) {
+	val, ok := evt.Extensions()[ExtensionResourceVersion]
+	if !ok {
+		return "", fmt.Errorf("failed to get resourceversion extension: not found")
+	}
+
+	resourceVersion, err := cloudeventstypes.Format(val)
+	if err != nil {
+		return "", fmt.Errorf("failed to get resourceversion extension: %v", err)
+	}
+
+	if resourceVersion == "" {
+		return "", fmt.Errorf("failed to get resourceversion extension: empty")
+	}
+
+	return resourceVersion, nil
+}
+
+// GetGeneration returns the generation of the resource spec of the event, zero is returned if the event does not
+// have the generation extension.
+func GetGeneration(evt cloudevents.Event) (int64, error) {
+	return getInt64Extension(evt, ExtensionGeneration)
+}
+
+// GetObservedGeneration returns the generation of the spec that the resource status of the event is observed with,
+// zero is returned if the event does not have the observed generation extension.
+func GetObservedGeneration(evt cloudevents.Event) (int64, error) {
+	return getInt64Extension(evt, ExtensionObservedGeneration)
+}
+
+func getInt64Extension(evt cloudevents.Event, name string) (int64, error) {
+	val, ok := evt.Extensions()[name]
+	if !ok {
+		return 0, nil
+	}
+
+	str, err := cloudeventstypes.Format(val)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s extension: %v", name, err)
+	}
+
+	i, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s extension: %v", name, err)
+	}
+	return i, nil
+}
+
+// GetPriority returns the priority of the event. If the event does not have the priority extension, the resync
+// requests, the resync responses and the delete events have the PriorityHigh, the other events have the PriorityNormal.
+func GetPriority(evt cloudevents.Event) (int, error) {
+	extensions := evt.Extensions()
+	if val, ok := extensions[ExtensionPriority]; ok {
+		priority, err := cloudeventstypes.ToInteger(val)
+		if err != nil {
+			return PriorityNormal, fmt.Errorf("failed to get priority extension: %v", err)
+		}
+		return int(priority), nil
+	}
+
+	if _, ok := extensions[ExtensionDeletionTimestamp]; ok {
+		return PriorityHigh, nil
+	}
+
+	eventType, err := ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return PriorityNormal, err
+	}
+
+	switch eventType.Action {
+	case ResyncRequestAction, ResyncResponseAction, ResyncCompleteAction, RegisterRequestAction,
+		RegisterResponseAction, CallRequestAction, CallResponseAction:
+		return PriorityHigh, nil
+	}
+
+	return PriorityNormal, nil
+}
@@ -3,7 +3,9 @@ package types
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"k8s.io/apimachinery/pkg/api/equality"
 )
 
@@ -113,3 +115,235 @@ func TestParseCloudEventsType(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteOption(t *testing.T) {
+	eventType := CloudEventsType{
+		CloudEventsDataType: CloudEventsDataType{
+			Group:    "io.open-cluster-management.works",
+			Version:  "v1alpha1",
+			Resource: "manifests",
+		},
+		SubResource: SubResourceSpec,
+		Action:      "delete_request",
+	}
+
+	evt := NewEventBuilder("test", eventType).NewEvent()
+	deleteOption, err := GetDeleteOption(evt)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if deleteOption != nil {
+		t.Errorf("expected no delete option, but got %v", deleteOption)
+	}
+
+	ttlSeconds := int64(60)
+	evt = NewEventBuilder("test", eventType).
+		WithDeletionTimestamp(time.Now()).
+		WithDeleteOption(DeleteOption{PropagationPolicy: "Orphan", TTLSeconds: &ttlSeconds}).
+		NewEvent()
+	deleteOption, err = GetDeleteOption(evt)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if deleteOption == nil || deleteOption.PropagationPolicy != "Orphan" || *deleteOption.TTLSeconds != ttlSeconds {
+		t.Errorf("unexpected delete option %v", deleteOption)
+	}
+}
+
+func TestEventBuilderAttributes(t *testing.T) {
+	eventType := CloudEventsType{
+		CloudEventsDataType: CloudEventsDataType{
+			Group:    "io.open-cluster-management.works",
+			Version:  "v1alpha1",
+			Resource: "manifests",
+		},
+		SubResource: SubResourceSpec,
+		Action:      "update_request",
+	}
+
+	evt := NewEventBuilder("test", eventType).NewEvent()
+	if len(evt.ID()) == 0 || evt.Time().IsZero() || len(evt.Subject()) != 0 {
+		t.Errorf("expected the default attributes, but got %v", evt)
+	}
+
+	eventTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	evt = NewEventBuilder("test", eventType).
+		WithID("test-1").
+		WithSubject("default/test").
+		WithTime(eventTime).
+		NewEvent()
+	if evt.ID() != "test-1" {
+		t.Errorf("expected test-1, but got %s", evt.ID())
+	}
+	if evt.Subject() != "default/test" {
+		t.Errorf("expected default/test, but got %s", evt.Subject())
+	}
+	if !evt.Time().Equal(eventTime) {
+		t.Errorf("expected %v, but got %v", eventTime, evt.Time())
+	}
+	if err := evt.Validate(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestGetPriority(t *testing.T) {
+	dataType := CloudEventsDataType{
+		Group:    "io.open-cluster-management.works",
+		Version:  "v1alpha1",
+		Resource: "manifests",
+	}
+
+	cases := []struct {
+		name             string
+		evt              cloudevents.Event
+		expectedPriority int
+	}{
+		{
+			name: "update request",
+			evt: NewEventBuilder("test", CloudEventsType{
+				CloudEventsDataType: dataType, SubResource: SubResourceSpec, Action: "update_request"}).NewEvent(),
+			expectedPriority: PriorityNormal,
+		},
+		{
+			name: "delete request",
+			evt: NewEventBuilder("test", CloudEventsType{
+				CloudEventsDataType: dataType, SubResource: SubResourceSpec, Action: "delete_request"}).
+				WithDeletionTimestamp(time.Now()).NewEvent(),
+			expectedPriority: PriorityHigh,
+		},
+		{
+			name: "resync response",
+			evt: NewEventBuilder("test", CloudEventsType{
+				CloudEventsDataType: dataType, SubResource: SubResourceSpec, Action: ResyncResponseAction}).NewEvent(),
+			expectedPriority: PriorityHigh,
+		},
+		{
+			name: "the priority is set",
+			evt: NewEventBuilder("test", CloudEventsType{
+				CloudEventsDataType: dataType, SubResource: SubResourceSpec, Action: ResyncResponseAction}).
+				WithPriority(5).NewEvent(),
+			expectedPriority: 5,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			priority, err := GetPriority(c.evt)
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			if priority != c.expectedPriority {
+				t.Errorf("expected %d, but got %d", c.expectedPriority, priority)
+			}
+		})
+	}
+}
+
+func TestTenantTopic(t *testing.T) {
+	cases := []struct {
+		name          string
+		tenantID      string
+		topic         string
+		expectedTopic string
+	}{
+		{
+			name:          "no tenant",
+			topic:         "sources/hub1/clusters/+/sourceevents",
+			expectedTopic: "sources/hub1/clusters/+/sourceevents",
+		},
+		{
+			name:          "tenant topic",
+			tenantID:      "tenant1",
+			topic:         "sources/hub1/clusters/+/sourceevents",
+			expectedTopic: "tenants/tenant1/sources/hub1/clusters/+/sourceevents",
+		},
+		{
+			name:          "tenant shared topic",
+			tenantID:      "tenant1",
+			topic:         "$share/statussubscribers/sources/+/clusters/+/agentevents",
+			expectedTopic: "$share/statussubscribers/tenants/tenant1/sources/+/clusters/+/agentevents",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			topic := TenantTopic(c.tenantID, c.topic)
+			if topic != c.expectedTopic {
+				t.Errorf("expected %s, but got %s", c.expectedTopic, topic)
+			}
+		})
+	}
+}
+
+func TestValidateTenantID(t *testing.T) {
+	cases := []struct {
+		name        string
+		tenantID    string
+		expectedErr bool
+	}{
+		{name: "empty tenant", tenantID: ""},
+		{name: "valid tenant", tenantID: "tenant-1"},
+		{name: "upper case tenant", tenantID: "Tenant1", expectedErr: true},
+		{name: "tenant with slash", tenantID: "tenant/1", expectedErr: true},
+		{name: "tenant with wildcard", tenantID: "+", expectedErr: true},
+		{name: "tenant ends with dash", tenantID: "tenant-", expectedErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateTenantID(c.tenantID)
+			if c.expectedErr && err == nil {
+				t.Errorf("expected error, but got nil")
+			}
+			if !c.expectedErr && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestResourceVersion(t *testing.T) {
+	eventType := CloudEventsType{
+		CloudEventsDataType: CloudEventsDataType{Group: "test", Version: "v1", Resource: "tests"},
+		SubResource:         SubResourceSpec,
+		Action:              "update_request",
+	}
+
+	cases := []struct {
+		name            string
+		resourceVersion string
+		expectedValue   any
+	}{
+		{
+			name:            "integer version",
+			resourceVersion: "12",
+			expectedValue:   int32(12),
+		},
+		{
+			name:            "string version",
+			resourceVersion: "a1b2c3",
+			expectedValue:   "a1b2c3",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evt := NewEventBuilder("test", eventType).WithStringResourceVersion(c.resourceVersion).NewEvent()
+			if value := evt.Extensions()[ExtensionResourceVersion]; value != c.expectedValue {
+				t.Errorf("expected %v (%T), but got %v (%T)", c.expectedValue, c.expectedValue, value, value)
+			}
+
+			resourceVersion, err := GetResourceVersion(evt)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if resourceVersion != c.resourceVersion {
+				t.Errorf("expected %s, but got %s", c.resourceVersion, resourceVersion)
+			}
+		})
+	}
+
+	if _, err := GetResourceVersion(NewEventBuilder("test", eventType).NewEvent()); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+}
@@ -0,0 +1,235 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	// UnstructuredClusterNameLabelKey is the label key of the cluster name of an unstructured object, the spec of the
+	// object is sent to the agent of this cluster.
+	UnstructuredClusterNameLabelKey = "cloudevents.open-cluster-management.io/clustername"
+
+	// UnstructuredOriginalSourceLabelKey is the label key of the original source of an unstructured object, the agent
+	// sends the status of the object back to this source.
+	UnstructuredOriginalSourceLabelKey = "cloudevents.open-cluster-management.io/originalsource"
+)
+
+// UnstructuredEventDataType returns the cloudevents data type of the given resource, the format is
+// `<group>.<version>.<resource>`.
+func UnstructuredEventDataType(gvr schema.GroupVersionResource) types.CloudEventsDataType {
+	return types.CloudEventsDataType{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+	}
+}
+
+// UnstructuredCodec is a codec to encode/decode the unstructured objects of a resource to/from the cloudevents, so the
+// custom resources can be synced from the source to the agent without a typed codec. The spec event carries the object
+// without its status, and the status event carries the status of the object.
+//
+// The UID of an object is its resource ID and the resource version of an object must be an integer, the cluster name
+// and the original source of an object are kept with the UnstructuredClusterNameLabelKey and the
+// UnstructuredOriginalSourceLabelKey labels.
+type UnstructuredCodec struct {
+	dataType types.CloudEventsDataType
+}
+
+var _ Codec[*unstructured.Unstructured] = &UnstructuredCodec{}
+
+func NewUnstructuredCodec(gvr schema.GroupVersionResource) *UnstructuredCodec {
+	return &UnstructuredCodec{dataType: UnstructuredEventDataType(gvr)}
+}
+
+func (c *UnstructuredCodec) EventDataType() types.CloudEventsDataType {
+	return c.dataType
+}
+
+// Encode the spec or the status of an unstructured object to a cloudevent by the subresource of the event type.
+func (c *UnstructuredCodec) Encode(
+	source string, eventType types.CloudEventsType, obj *unstructured.Unstructured) (*cloudevents.Event, error) {
+	if eventType.CloudEventsDataType != c.dataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	if len(obj.GetResourceVersion()) == 0 {
+		return nil, fmt.Errorf("the resourceversion of the object %s is empty", obj.GetUID())
+	}
+
+	builder := types.NewEventBuilder(source, eventType).
+		WithResourceID(string(obj.GetUID())).
+		WithStringResourceVersion(obj.GetResourceVersion()).
+		WithClusterName(UnstructuredClusterName(obj))
+
+	var data map[string]any
+	switch eventType.SubResource {
+	case types.SubResourceSpec:
+		builder = builder.WithGeneration(obj.GetGeneration())
+		if !obj.GetDeletionTimestamp().IsZero() {
+			evt := builder.WithDeletionTimestamp(obj.GetDeletionTimestamp().Time).NewEvent()
+			return &evt, nil
+		}
+
+		spec := obj.DeepCopy()
+		unstructured.RemoveNestedField(spec.Object, "status")
+		data = spec.Object
+	case types.SubResourceStatus:
+		originalSource, ok := obj.GetLabels()[UnstructuredOriginalSourceLabelKey]
+		if !ok {
+			return nil, fmt.Errorf("failed to find originalsource from the object %s", obj.GetUID())
+		}
+
+		builder = builder.WithOriginalSource(originalSource)
+		if observedGeneration, ok := getObservedGeneration(obj); ok {
+			builder = builder.WithObservedGeneration(observedGeneration)
+		}
+
+		data = map[string]any{}
+		if status, ok := obj.Object["status"]; ok {
+			data["status"] = status
+		}
+	default:
+		return nil, fmt.Errorf("unsupported subresource %s", eventType.SubResource)
+	}
+
+	evt := builder.NewEvent()
+	if err := evt.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return nil, fmt.Errorf("failed to encode the object %s to a cloudevent: %v", obj.GetUID(), err)
+	}
+
+	return &evt, nil
+}
+
+// Decode a cloudevent to an unstructured object, the object of a status event only has the status.
+func (c *UnstructuredCodec) Decode(evt *cloudevents.Event) (*unstructured.Unstructured, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cloud event type %s, %v", evt.Type(), err)
+	}
+
+	if eventType.CloudEventsDataType != c.dataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	evtExtensions := evt.Context.GetExtensions()
+
+	resourceID, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionResourceID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resourceid extension: %v", err)
+	}
+
+	resourceVersion, err := types.GetResourceVersion(*evt)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]any{}}
+	if len(evt.Data()) != 0 {
+		// the numbers are decoded to int64 or float64 as the unstructured objects require
+		if err := utiljson.Unmarshal(evt.Data(), &obj.Object); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event data %s, %v", string(evt.Data()), err)
+		}
+	}
+
+	obj.SetUID(kubetypes.UID(resourceID))
+	obj.SetResourceVersion(resourceVersion)
+
+	generation, err := types.GetGeneration(*evt)
+	if err != nil {
+		return nil, err
+	}
+	if generation > 0 {
+		obj.SetGeneration(generation)
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	if clusterName, ok := evtExtensions[types.ExtensionClusterName]; ok {
+		labels[UnstructuredClusterNameLabelKey] = fmt.Sprintf("%s", clusterName)
+	}
+
+	originalSource := ""
+	if val, ok := evtExtensions[types.ExtensionOriginalSource]; ok {
+		originalSource = fmt.Sprintf("%s", val)
+	}
+	if len(originalSource) == 0 && eventType.SubResource == types.SubResourceSpec {
+		// the spec is received by the agent, keep the source to send the status back
+		originalSource = evt.Source()
+	}
+	if len(originalSource) != 0 {
+		labels[UnstructuredOriginalSourceLabelKey] = originalSource
+	}
+	obj.SetLabels(labels)
+
+	if deletionTimestamp, ok := evtExtensions[types.ExtensionDeletionTimestamp]; ok {
+		timestamp, err := cloudeventstypes.ToTime(deletionTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deletiontimestamp extension: %v", err)
+		}
+		obj.SetDeletionTimestamp(&metav1.Time{Time: timestamp})
+	}
+
+	return obj, nil
+}
+
+// UnstructuredClusterName returns the cluster name of an unstructured object, it is the ClusterNameGetter of the
+// unstructured objects.
+func UnstructuredClusterName(obj *unstructured.Unstructured) string {
+	return obj.GetLabels()[UnstructuredClusterNameLabelKey]
+}
+
+// MergeUnstructuredStatus returns a copy of the last object with the status of the received object, it is the
+// StatusMerger of the unstructured objects.
+func MergeUnstructuredStatus(last, obj *unstructured.Unstructured) *unstructured.Unstructured {
+	merged := last.DeepCopy()
+	if status, ok := obj.Object["status"]; ok {
+		merged.Object["status"] = status
+	} else {
+		delete(merged.Object, "status")
+	}
+	return merged
+}
+
+// UnstructuredStatusHash returns the hash of the status of an unstructured object.
+func UnstructuredStatusHash(obj *unstructured.Unstructured) (string, error) {
+	return unstructuredStatusHashGetter(obj)
+}
+
+var unstructuredStatusHashGetter = NewStructuredStatusHashGetter(func(obj *unstructured.Unstructured) any {
+	return obj.Object["status"]
+})
+
+// NewUnstructuredClient returns a source client that publishes the unstructured objects of the given resource to the
+// agents and receives their status back. The client owns a MemoryResourceStore, the objects must be upserted to the
+// store before they are published, and the received status is merged to the objects in the store.
+func NewUnstructuredClient(ctx context.Context, sourceOptions *options.CloudEventsSourceOptions,
+	gvr schema.GroupVersionResource) (*CloudEventSourceClient[*unstructured.Unstructured], error) {
+	store := NewMemoryResourceStore[*unstructured.Unstructured](UnstructuredClusterName, MergeUnstructuredStatus)
+	return NewCloudEventSourceClientWithStore[*unstructured.Unstructured](
+		ctx, sourceOptions, store, UnstructuredStatusHash, NewUnstructuredCodec(gvr))
+}
+
+// NewUnstructuredAgentClient returns an agent client that receives the unstructured objects of the given resource from
+// the sources and publishes their status back, the lister lists the objects that are maintained by the agent.
+func NewUnstructuredAgentClient(ctx context.Context, agentOptions *options.CloudEventsAgentOptions,
+	lister Lister[*unstructured.Unstructured],
+	gvr schema.GroupVersionResource) (*CloudEventAgentClient[*unstructured.Unstructured], error) {
+	return NewCloudEventAgentClient[*unstructured.Unstructured](
+		ctx, agentOptions, lister, UnstructuredStatusHash, NewUnstructuredCodec(gvr))
+}
@@ -0,0 +1,192 @@
+package generic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+var testGVR = schema.GroupVersionResource{Group: "example.io", Version: "v1", Resource: "widgets"}
+
+func TestUnstructuredCodec(t *testing.T) {
+	codec := NewUnstructuredCodec(testGVR)
+	specType := types.CloudEventsType{
+		CloudEventsDataType: codec.EventDataType(),
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}
+	statusType := types.CloudEventsType{
+		CloudEventsDataType: codec.EventDataType(),
+		SubResource:         types.SubResourceStatus,
+		Action:              "update_request",
+	}
+
+	deleting := newWidget("1")
+	deleting.SetDeletionTimestamp(&metav1.Time{Time: time.Now().Truncate(time.Second)})
+
+	cases := []struct {
+		name           string
+		eventType      types.CloudEventsType
+		obj            *unstructured.Unstructured
+		expectedErr    bool
+		validateObject func(t *testing.T, obj *unstructured.Unstructured)
+	}{
+		{
+			name:        "unsupported data type",
+			eventType:   types.CloudEventsType{CloudEventsDataType: mockEventDataType, SubResource: types.SubResourceSpec},
+			obj:         newWidget("1"),
+			expectedErr: true,
+		},
+		{
+			name:        "empty resource version",
+			eventType:   specType,
+			obj:         newWidget(""),
+			expectedErr: true,
+		},
+		{
+			name:           "encode string resource version",
+			eventType:      specType,
+			obj:            newWidget("a1b2c3"),
+			validateObject: func(t *testing.T, obj *unstructured.Unstructured) {},
+		},
+		{
+			name:      "encode spec",
+			eventType: specType,
+			obj:       newWidget("1"),
+			validateObject: func(t *testing.T, obj *unstructured.Unstructured) {
+				if _, ok := obj.Object["status"]; ok {
+					t.Errorf("expected no status, but got %v", obj.Object["status"])
+				}
+				size, _, _ := unstructured.NestedInt64(obj.Object, "spec", "size")
+				if size != 3 {
+					t.Errorf("expected 3, but got %d", size)
+				}
+				if obj.GetLabels()[UnstructuredOriginalSourceLabelKey] != testSourceName {
+					t.Errorf("expected %s, but got %v", testSourceName, obj.GetLabels())
+				}
+			},
+		},
+		{
+			name:      "encode deleting spec",
+			eventType: specType,
+			obj:       deleting,
+			validateObject: func(t *testing.T, obj *unstructured.Unstructured) {
+				if !obj.GetDeletionTimestamp().Equal(deleting.GetDeletionTimestamp()) {
+					t.Errorf("expected %v, but got %v", deleting.GetDeletionTimestamp(), obj.GetDeletionTimestamp())
+				}
+			},
+		},
+		{
+			name:      "encode status",
+			eventType: statusType,
+			obj:       newWidget("1"),
+			validateObject: func(t *testing.T, obj *unstructured.Unstructured) {
+				if _, ok := obj.Object["spec"]; ok {
+					t.Errorf("expected no spec, but got %v", obj.Object["spec"])
+				}
+				if !equality.Semantic.DeepEqual(obj.Object["status"], newWidget("1").Object["status"]) {
+					t.Errorf("expected %v, but got %v", newWidget("1").Object["status"], obj.Object["status"])
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evt, err := codec.Encode(testSourceName, c.eventType, c.obj)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			obj, err := codec.Decode(evt)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if obj.GetUID() != c.obj.GetUID() || obj.GetResourceVersion() != c.obj.GetResourceVersion() ||
+				UnstructuredClusterName(obj) != "cluster1" {
+				t.Errorf("unexpected object %v", obj)
+			}
+
+			c.validateObject(t, obj)
+		})
+	}
+}
+
+func TestUnstructuredClient(t *testing.T) {
+	fakeClient := fake.NewCloudEventsFakeClient()
+	source, err := NewUnstructuredClient(context.TODO(), fake.NewSourceOptions(fakeClient, testSourceName), testGVR)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	widget := newWidget("1")
+	source.Store().(*MemoryResourceStore[*unstructured.Unstructured]).Upsert(widget)
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: UnstructuredEventDataType(testGVR),
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}
+	if err := source.Publish(context.TODO(), eventType, widget); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the objects in the store are listed by their cluster name labels
+	objs, err := source.Store().List(types.ListOptions{ClusterName: "cluster1"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(objs) != 1 {
+		t.Errorf("expected 1 object, but got %v", objs)
+	}
+
+	if len(fakeClient.GetSentEvents()) != 1 {
+		t.Errorf("expected 1 event, but got %v", fakeClient.GetSentEvents())
+	}
+
+	// the received status is merged to the object in the store
+	status := newWidget("1")
+	status.Object["status"] = map[string]any{"phase": "Running"}
+	merged := MergeUnstructuredStatus(widget, status)
+	if phase, _, _ := unstructured.NestedString(merged.Object, "status", "phase"); phase != "Running" {
+		t.Errorf("expected Running, but got %s", phase)
+	}
+	if size, _, _ := unstructured.NestedInt64(merged.Object, "spec", "size"); size != 3 {
+		t.Errorf("expected 3, but got %d", size)
+	}
+}
+
+func newWidget(resourceVersion string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.io/v1",
+		"kind":       "Widget",
+		"metadata": map[string]any{
+			"name":      "test",
+			"namespace": "default",
+			"uid":       "widget1",
+			"labels": map[string]any{
+				UnstructuredClusterNameLabelKey:    "cluster1",
+				UnstructuredOriginalSourceLabelKey: testSourceName,
+			},
+		},
+		"spec":   map[string]any{"size": int64(3)},
+		"status": map[string]any{"phase": "Pending"},
+	}}
+	obj.SetResourceVersion(resourceVersion)
+	return obj
+}
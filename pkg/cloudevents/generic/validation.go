@@ -0,0 +1,81 @@
+package generic
+
+import (
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// EventValidationError is returned when an event is invalid to publish, the Errors describe each invalid attribute or
+// extension of the event.
+type EventValidationError = schema.ValidationError
+
+// validateEvent checks the event before it is handed to the transport, so a malformed event fails on the sending side
+// instead of the receiving side. The required extensions depend on the event type, see schema.RequiredExtensions.
+//
+// If maxSize is greater than zero, the size of the JSON encoded event must not be larger than it, a larger event is
+// rejected with an error that wraps both ErrPayloadTooLarge and the EventValidationError.
+func validateEvent(evt cloudevents.Event, maxSize int) error {
+	errs := field.ErrorList{}
+
+	if err := evt.Validate(); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("context"), evt.Type(), err.Error()))
+	}
+
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("type"), evt.Type(), err.Error()))
+		return &EventValidationError{EventID: evt.ID(), EventType: evt.Type(), Errors: errs}
+	}
+
+	errs = append(errs, schema.ValidateExtensions(evt, *eventType)...)
+
+	tooLarge := false
+	if maxSize > 0 {
+		size, err := eventSize(evt)
+		if err != nil {
+			errs = append(errs, field.InternalError(field.NewPath("data"), err))
+		} else if size > maxSize {
+			errs = append(errs, field.TooLong(field.NewPath("data"),
+				fmt.Sprintf("the encoded event size %d bytes", size), maxSize))
+			tooLarge = true
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	err = &EventValidationError{EventID: evt.ID(), EventType: evt.Type(), Errors: errs}
+	if tooLarge {
+		return fmt.Errorf("%w: %w", ErrPayloadTooLarge, err)
+	}
+	return err
+}
+
+// eventSize returns the size of the JSON encoded event. The JSON data of an event is written to the encoded event as it
+// is, so the size of such an event is computed from its attributes without copying its data.
+func eventSize(evt cloudevents.Event) (int, error) {
+	if len(evt.DataEncoded) == 0 || evt.DataBase64 || !isJSON(evt.DataMediaType()) {
+		data, err := evt.MarshalJSON()
+		return len(data), err
+	}
+
+	attributes, err := cloudevents.Event{Context: evt.Context}.MarshalJSON()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(attributes) + len(`,"data":`) + len(evt.DataEncoded), nil
+}
+
+func isJSON(mediaType string) bool {
+	mediaType = strings.ToLower(mediaType)
+	return mediaType == "" || mediaType == cloudevents.ApplicationJSON || mediaType == event.TextJSON
+}
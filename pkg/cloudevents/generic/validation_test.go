@@ -0,0 +1,260 @@
+package generic
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestValidateEvent(t *testing.T) {
+	specEventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	statusEventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "test_update_request",
+	}
+	specResyncEventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ResyncRequestAction,
+	}
+	handoverEventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.HandoverRequestAction,
+	}
+	claimEventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.ClaimRequestAction,
+	}
+	registerEventType := types.CloudEventsType{
+		CloudEventsDataType: types.RegistrationDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              types.RegisterRequestAction,
+	}
+
+	cases := []struct {
+		name           string
+		event          cloudevents.Event
+		maxSize        int
+		expectedFields []string
+	}{
+		{
+			name: "valid spec event",
+			event: types.NewEventBuilder(testSourceName, specEventType).
+				WithResourceID("test1").
+				WithResourceVersion(1).
+				WithClusterName("cluster1").
+				NewEvent(),
+			expectedFields: []string{},
+		},
+		{
+			name:           "spec event without resource and cluster",
+			event:          types.NewEventBuilder(testSourceName, specEventType).NewEvent(),
+			expectedFields: []string{"extensions.resourceid", "extensions.resourceversion", "extensions.clustername"},
+		},
+		{
+			name: "status event without original source",
+			event: types.NewEventBuilder(testAgentName, statusEventType).
+				WithResourceID("test1").
+				WithResourceVersion(1).
+				WithClusterName("cluster1").
+				NewEvent(),
+			expectedFields: []string{"extensions.originalsource"},
+		},
+		{
+			name: "handover cancellation",
+			event: func() cloudevents.Event {
+				evt := types.NewEventBuilder(testSourceName, handoverEventType).
+					WithResourceID("test1").
+					WithResourceVersion(1).
+					WithClusterName("cluster1").
+					NewEvent()
+				evt.SetExtension(types.ExtensionHandoverTarget, "")
+				return evt
+			}(),
+			expectedFields: []string{},
+		},
+		{
+			name: "handover request without target",
+			event: types.NewEventBuilder(testSourceName, handoverEventType).
+				WithResourceID("test1").
+				WithResourceVersion(1).
+				WithClusterName("cluster1").
+				NewEvent(),
+			expectedFields: []string{"extensions.handovertarget"},
+		},
+		{
+			name: "claim request without previous source",
+			event: func() cloudevents.Event {
+				evt := types.NewEventBuilder(testSourceName, claimEventType).
+					WithResourceID("test1").
+					WithResourceVersion(1).
+					WithClusterName("cluster1").
+					NewEvent()
+				evt.SetExtension(types.ExtensionHandoverSource, "")
+				return evt
+			}(),
+			expectedFields: []string{"extensions.handoversource"},
+		},
+		{
+			name: "resync request for all sources",
+			event: types.NewEventBuilder(testAgentName, specResyncEventType).
+				WithClusterName("cluster1").
+				WithOriginalSource(types.SourceAll).
+				NewEvent(),
+			expectedFields: []string{},
+		},
+		{
+			name: "registration request for all sources",
+			event: types.NewEventBuilder(testAgentName, registerEventType).
+				WithClusterName("cluster1").
+				WithOriginalSource(types.SourceAll).
+				NewEvent(),
+			expectedFields: []string{},
+		},
+		{
+			name:           "registration request without cluster",
+			event:          types.NewEventBuilder(testAgentName, registerEventType).NewEvent(),
+			expectedFields: []string{"extensions.clustername"},
+		},
+		{
+			name: "unsupported event type",
+			event: func() cloudevents.Event {
+				evt := types.NewEventBuilder(testSourceName, specEventType).NewEvent()
+				evt.SetType("unsupported")
+				return evt
+			}(),
+			expectedFields: []string{"type"},
+		},
+		{
+			name: "too large event",
+			event: func() cloudevents.Event {
+				evt := types.NewEventBuilder(testSourceName, specEventType).
+					WithResourceID("test1").
+					WithResourceVersion(1).
+					WithClusterName("cluster1").
+					NewEvent()
+				if err := evt.SetData(cloudevents.TextPlain, strings.Repeat("a", 1024)); err != nil {
+					t.Fatal(err)
+				}
+				return evt
+			}(),
+			maxSize:        1024,
+			expectedFields: []string{"data"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateEvent(c.event, c.maxSize)
+			if len(c.expectedFields) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				return
+			}
+
+			var validationErr *EventValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, but got %v", err)
+			}
+
+			if validationErr.EventID != c.event.ID() {
+				t.Errorf("expected %s, but got %s", c.event.ID(), validationErr.EventID)
+			}
+
+			fields := []string{}
+			for _, fieldErr := range validationErr.Errors {
+				fields = append(fields, fieldErr.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(c.expectedFields, ",") {
+				t.Errorf("expected %v, but got %v", c.expectedFields, fields)
+			}
+		})
+	}
+}
+
+func TestEventValidationError(t *testing.T) {
+	err := &EventValidationError{
+		EventID:   "1",
+		EventType: "test",
+		Errors:    field.ErrorList{field.Required(field.NewPath("extensions", "resourceid"), "")},
+	}
+
+	expected := "invalid event 1 (test): extensions.resourceid: Required value"
+	if err.Error() != expected {
+		t.Errorf("expected %s, but got %s", expected, err.Error())
+	}
+}
+
+func TestEventSize(t *testing.T) {
+	specEventType := types.CloudEventsType{
+		CloudEventsDataType: mockEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "test_create_request",
+	}
+	newEvent := func(contentType string, data any) cloudevents.Event {
+		evt := types.NewEventBuilder(testSourceName, specEventType).
+			WithResourceID("test1").
+			WithResourceVersion(1).
+			WithClusterName("cluster1").
+			NewEvent()
+		if data != nil {
+			if err := evt.SetData(contentType, data); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return evt
+	}
+
+	cases := []struct {
+		name  string
+		event cloudevents.Event
+	}{
+		{
+			name:  "no data",
+			event: newEvent("", nil),
+		},
+		{
+			name:  "json data",
+			event: newEvent(cloudevents.ApplicationJSON, map[string]string{"test": "test"}),
+		},
+		{
+			name:  "text data",
+			event: newEvent(cloudevents.TextPlain, "test"),
+		},
+		{
+			name:  "binary data",
+			event: newEvent(cloudevents.ApplicationJSON, []byte{0x1, 0x2}),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := json.Marshal(c.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			size, err := eventSize(c.event)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if size != len(data) {
+				t.Errorf("expected %d, but got %d", len(data), size)
+			}
+		})
+	}
+}
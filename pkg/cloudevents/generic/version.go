@@ -0,0 +1,139 @@
+package generic
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionComparator compares the resource versions a and b, it returns a negative number if a is older than b, zero if
+// they are the same version, and a positive number if a is newer than b. An error is returned if a version cannot be
+// parsed, the callers treat the versions that cannot be compared as different versions.
+type VersionComparator func(a, b string) (int, error)
+
+// IntegerVersionComparator compares the resource versions that are int64 sequence numbers, it is the default
+// comparator of the clients.
+func IntegerVersionComparator(a, b string) (int, error) {
+	versionA, err := strconv.ParseInt(a, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the resource version %q, %v", a, err)
+	}
+
+	versionB, err := strconv.ParseInt(b, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the resource version %q, %v", b, err)
+	}
+
+	return cmp.Compare(versionA, versionB), nil
+}
+
+// OpaqueVersionComparator compares the resource versions that cannot be ordered, e.g. the Kubernetes resourceVersions
+// or the content hashes, a version that is different from the other one is always considered newer.
+func OpaqueVersionComparator(a, b string) (int, error) {
+	if a == b {
+		return 0, nil
+	}
+
+	return 1, nil
+}
+
+// SemanticVersionComparator compares the resource versions that are semantic versions, e.g. 1.2.3 or v1.2.3-rc.1,
+// the build metadata of the versions is ignored.
+func SemanticVersionComparator(a, b string) (int, error) {
+	versionA, err := parseSemanticVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	versionB, err := parseSemanticVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range versionA.numbers {
+		if c := cmp.Compare(versionA.numbers[i], versionB.numbers[i]); c != 0 {
+			return c, nil
+		}
+	}
+
+	return comparePreRelease(versionA.preRelease, versionB.preRelease), nil
+}
+
+// versionComparator returns the given comparator, or the IntegerVersionComparator if it is not set.
+func versionComparator(compare func(a, b string) (int, error)) VersionComparator {
+	if compare == nil {
+		return IntegerVersionComparator
+	}
+
+	return compare
+}
+
+type semanticVersion struct {
+	numbers    [3]uint64
+	preRelease []string
+}
+
+func parseSemanticVersion(version string) (*semanticVersion, error) {
+	str := strings.TrimPrefix(version, "v")
+	str, _, _ = strings.Cut(str, "+")
+
+	v := &semanticVersion{}
+	str, preRelease, hasPreRelease := strings.Cut(str, "-")
+	if hasPreRelease {
+		if preRelease == "" {
+			return nil, fmt.Errorf("invalid semantic version %q, the pre-release is empty", version)
+		}
+		v.preRelease = strings.Split(preRelease, ".")
+	}
+
+	numbers := strings.Split(str, ".")
+	if len(numbers) != len(v.numbers) {
+		return nil, fmt.Errorf("invalid semantic version %q, expected major.minor.patch", version)
+	}
+
+	for i, number := range numbers {
+		n, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid semantic version %q, %v", version, err)
+		}
+		v.numbers[i] = n
+	}
+
+	return v, nil
+}
+
+// comparePreRelease compares the pre-release identifiers with the precedence of the semantic versioning, a version
+// without the pre-release is newer than the one with the pre-release.
+func comparePreRelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+
+	for i := 0; i < min(len(a), len(b)); i++ {
+		numberA, errA := strconv.ParseUint(a[i], 10, 64)
+		numberB, errB := strconv.ParseUint(b[i], 10, 64)
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = cmp.Compare(numberA, numberB)
+		case errA == nil:
+			// the numeric identifiers have lower precedence than the alphanumeric identifiers
+			c = -1
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(a), len(b))
+}
@@ -0,0 +1,67 @@
+package generic
+
+import (
+	"testing"
+
+	"k8s.io/utils/clock"
+)
+
+func TestVersionComparators(t *testing.T) {
+	cases := []struct {
+		name        string
+		compare     VersionComparator
+		a, b        string
+		expected    int
+		expectedErr bool
+	}{
+		{name: "integer older", compare: IntegerVersionComparator, a: "2", b: "10", expected: -1},
+		{name: "integer same", compare: IntegerVersionComparator, a: "10", b: "10", expected: 0},
+		{name: "integer newer", compare: IntegerVersionComparator, a: "10", b: "2", expected: 1},
+		{name: "integer invalid", compare: IntegerVersionComparator, a: "v1", b: "2", expectedErr: true},
+		{name: "opaque same", compare: OpaqueVersionComparator, a: "a1b2", b: "a1b2", expected: 0},
+		{name: "opaque different", compare: OpaqueVersionComparator, a: "a1b2", b: "c3d4", expected: 1},
+		{name: "semantic older", compare: SemanticVersionComparator, a: "1.2.3", b: "1.10.0", expected: -1},
+		{name: "semantic same", compare: SemanticVersionComparator, a: "v1.2.3", b: "1.2.3+build1", expected: 0},
+		{name: "semantic newer", compare: SemanticVersionComparator, a: "2.0.0", b: "1.99.99", expected: 1},
+		{name: "semantic pre-release", compare: SemanticVersionComparator, a: "1.0.0-rc.1", b: "1.0.0", expected: -1},
+		{name: "semantic numeric pre-release", compare: SemanticVersionComparator, a: "1.0.0-rc.2", b: "1.0.0-rc.10", expected: -1},
+		{name: "semantic alphanumeric pre-release", compare: SemanticVersionComparator, a: "1.0.0-beta", b: "1.0.0-alpha.1", expected: 1},
+		{name: "semantic longer pre-release", compare: SemanticVersionComparator, a: "1.0.0-alpha.1", b: "1.0.0-alpha", expected: 1},
+		{name: "semantic invalid", compare: SemanticVersionComparator, a: "1.2", b: "1.2.3", expectedErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := c.compare(c.a, c.b)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if actual != c.expected {
+				t.Errorf("expected %d, but got %d", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResourceVersionTrackerWithComparator(t *testing.T) {
+	tracker := newResourceVersionTracker(SemanticVersionComparator, clock.RealClock{})
+	tracker.processed("test1", "1.10.0")
+
+	if outOfOrder, lastVersion := tracker.isOutOfOrder("test1", "1.9.0"); !outOfOrder || lastVersion != "1.10.0" {
+		t.Errorf("expected the event is out of order, last version %s", lastVersion)
+	}
+
+	if outOfOrder, _ := tracker.isOutOfOrder("test1", "1.10.1"); outOfOrder {
+		t.Errorf("expected the event with a newer resource version is in order")
+	}
+
+	if outOfOrder, _ := tracker.isOutOfOrder("test1", "invalid"); outOfOrder {
+		t.Errorf("expected the event with an invalid resource version is in order")
+	}
+}
@@ -0,0 +1,244 @@
+package hubofhubs
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// LeafFuncs get the summary key, the leaf cluster name and the conditions of a resource of the leaf clusters.
+type LeafFuncs[T generic.ResourceObject] struct {
+	// Key returns the key that groups the resources of the leaf clusters into a summary, e.g. the name of a
+	// ManifestWork that is applied to all the leaf clusters.
+	Key func(obj T) string
+
+	// ClusterName returns the name of the leaf cluster of the resource.
+	ClusterName func(obj T) string
+
+	// Conditions returns the conditions of the resource on the leaf cluster.
+	Conditions func(obj T) []metav1.Condition
+}
+
+// Aggregator summarizes the statuses of the resources of the leaf clusters on an intermediate hub and publishes the
+// summaries to a parent hub. Its Handle is the resource handler of the source client of the leaf clusters, and it is
+// the Lister of the agent client of the parent hub, so the summaries are resent when the parent hub resyncs the
+// status, e.g.
+//
+//	aggregator := hubofhubs.NewAggregator[*workv1.ManifestWork]("region1", "global-hub", leafFuncs)
+//	leafClient.Subscribe(ctx, aggregator.Handle)
+//	parentClient, err := generic.NewCloudEventAgentClient[*hubofhubs.ResourceSummary](
+//		ctx, agentOptions, aggregator, hubofhubs.StatusHash, hubofhubs.NewCodec())
+//	err = aggregator.Publish(ctx, parentClient)
+type Aggregator[T generic.ResourceObject] struct {
+	sync.RWMutex
+	hubName string
+	source  string
+	funcs   LeafFuncs[T]
+	version int64
+	// leaves are the latest statuses of the leaf clusters by the summary keys and the leaf cluster names
+	leaves    map[string]map[string]LeafStatus
+	summaries map[string]*ResourceSummary
+	changed   sets.Set[string]
+}
+
+// NewAggregator returns an Aggregator of the given intermediate hub that publishes the summaries to the given source
+// of the parent hub.
+func NewAggregator[T generic.ResourceObject](hubName, source string, funcs LeafFuncs[T]) *Aggregator[T] {
+	return &Aggregator[T]{
+		hubName: hubName,
+		source:  source,
+		funcs:   funcs,
+		// the versions start from the current time in seconds, so the versions after a restart are greater than the
+		// published versions
+		version:   time.Now().Unix(),
+		leaves:    map[string]map[string]LeafStatus{},
+		summaries: map[string]*ResourceSummary{},
+		changed:   sets.New[string](),
+	}
+}
+
+// Handle keeps the status of a resource of a leaf cluster, the resource is removed from its summary if it is deleted.
+func (a *Aggregator[T]) Handle(action types.ResourceAction, obj T) error {
+	key := a.funcs.Key(obj)
+	clusterName := a.funcs.ClusterName(obj)
+
+	a.Lock()
+	defer a.Unlock()
+
+	switch action {
+	case types.StatusModified, types.Added, types.Modified:
+		if _, ok := a.leaves[key]; !ok {
+			a.leaves[key] = map[string]LeafStatus{}
+		}
+		a.leaves[key][clusterName] = LeafStatus{
+			ClusterName: clusterName,
+			ResourceID:  string(obj.GetUID()),
+			Conditions:  a.funcs.Conditions(obj),
+		}
+	case types.Deleted:
+		if _, ok := a.leaves[key][clusterName]; !ok {
+			return nil
+		}
+		delete(a.leaves[key], clusterName)
+	default:
+		return nil
+	}
+
+	a.summarize(key)
+	return nil
+}
+
+// Forget removes the statuses of a leaf cluster from all the summaries, e.g. the cluster is detached from the
+// intermediate hub.
+func (a *Aggregator[T]) Forget(clusterName string) {
+	a.Lock()
+	defer a.Unlock()
+
+	for key, leaves := range a.leaves {
+		if _, ok := leaves[clusterName]; ok {
+			delete(leaves, clusterName)
+			a.summarize(key)
+		}
+	}
+}
+
+// Get returns the summary of a key.
+func (a *Aggregator[T]) Get(key string) (*ResourceSummary, bool) {
+	a.RLock()
+	defer a.RUnlock()
+
+	summary, ok := a.summaries[key]
+	return summary, ok
+}
+
+// List returns the summaries of the intermediate hub.
+func (a *Aggregator[T]) List(options types.ListOptions) ([]*ResourceSummary, error) {
+	a.RLock()
+	defer a.RUnlock()
+
+	if options.ClusterName != types.ClusterAll && options.ClusterName != a.hubName {
+		return nil, nil
+	}
+
+	if options.Source != types.SourceAll && options.Source != a.source {
+		return nil, nil
+	}
+
+	summaries := make([]*ResourceSummary, 0, len(a.summaries))
+	for _, summary := range a.summaries {
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// Publish publishes the changed summaries to the parent hub with the agent client, the summaries that are failed to
+// publish are published again on the next call. A summary without any leaf cluster is published once and then
+// removed.
+func (a *Aggregator[T]) Publish(ctx context.Context, client generic.CloudEventsClient[*ResourceSummary]) error {
+	a.Lock()
+	changed := a.changed.UnsortedList()
+	a.changed = sets.New[string]()
+	summaries := make([]*ResourceSummary, 0, len(changed))
+	for _, key := range changed {
+		summaries = append(summaries, a.summaries[key])
+	}
+	a.Unlock()
+
+	errs := []error{}
+	for _, summary := range summaries {
+		if err := client.Publish(ctx, SummaryStatusEventType, summary); err != nil {
+			errs = append(errs, err)
+
+			a.Lock()
+			// retry the summary unless it is changed again
+			if a.summaries[summary.Status.Key] == summary {
+				a.changed.Insert(summary.Status.Key)
+			}
+			a.Unlock()
+			continue
+		}
+
+		if summary.Status.Clusters == 0 {
+			a.Lock()
+			if a.summaries[summary.Status.Key] == summary {
+				delete(a.summaries, summary.Status.Key)
+			}
+			a.Unlock()
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// summarize recomputes the summary of a key, it is marked as changed if its status is changed.
+func (a *Aggregator[T]) summarize(key string) {
+	leaves := a.leaves[key]
+	if len(leaves) == 0 {
+		delete(a.leaves, key)
+	}
+
+	status := SummaryStatus{Key: key, Clusters: len(leaves)}
+	counts := map[string]*ConditionSummary{}
+	for _, leaf := range leaves {
+		status.LeafClusters = append(status.LeafClusters, leaf)
+		for _, condition := range leaf.Conditions {
+			count, ok := counts[condition.Type]
+			if !ok {
+				count = &ConditionSummary{Type: condition.Type}
+				counts[condition.Type] = count
+			}
+
+			switch condition.Status {
+			case metav1.ConditionTrue:
+				count.True++
+			case metav1.ConditionFalse:
+				count.False++
+			default:
+				count.Unknown++
+			}
+		}
+	}
+
+	sort.Slice(status.LeafClusters, func(i, j int) bool {
+		return status.LeafClusters[i].ClusterName < status.LeafClusters[j].ClusterName
+	})
+	for _, count := range counts {
+		status.Conditions = append(status.Conditions, *count)
+	}
+	sort.Slice(status.Conditions, func(i, j int) bool {
+		return status.Conditions[i].Type < status.Conditions[j].Type
+	})
+
+	last, ok := a.summaries[key]
+	if !ok && len(leaves) == 0 {
+		return
+	}
+
+	summary := &ResourceSummary{
+		UID:            SummaryID(a.hubName, key),
+		ClusterName:    a.hubName,
+		OriginalSource: a.source,
+		Status:         status,
+	}
+	if ok {
+		lastHash, lastErr := StatusHash(last)
+		hash, err := StatusHash(summary)
+		if lastErr == nil && err == nil && lastHash == hash {
+			return
+		}
+	}
+
+	a.version++
+	summary.ResourceVersion = strconv.FormatInt(a.version, 10)
+	a.summaries[key] = summary
+	a.changed.Insert(key)
+}
@@ -0,0 +1,100 @@
+package hubofhubs
+
+import (
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// Codec is a codec to encode/decode the ResourceSummary/cloudevent with SummaryStatus, it is used by both the
+// intermediate hubs and the parent hubs, only the status events are supported.
+type Codec struct{}
+
+func NewCodec() *Codec {
+	return &Codec{}
+}
+
+// EventDataType always returns the event data type `io.open-cluster-management.hubofhubs.v1alpha1.resourcesummaries`.
+func (c *Codec) EventDataType() types.CloudEventsDataType {
+	return SummaryEventDataType
+}
+
+// Encode the status of a ResourceSummary to a cloudevent.
+func (c *Codec) Encode(source string, eventType types.CloudEventsType, summary *ResourceSummary) (*cloudevents.Event, error) {
+	if eventType.CloudEventsDataType != SummaryEventDataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	if eventType.SubResource != types.SubResourceStatus {
+		return nil, fmt.Errorf("unsupported subresource %s", eventType.SubResource)
+	}
+
+	evt := types.NewEventBuilder(source, eventType).
+		WithResourceID(string(summary.UID)).
+		WithStringResourceVersion(summary.ResourceVersion).
+		WithClusterName(summary.ClusterName).
+		WithOriginalSource(summary.OriginalSource).
+		NewEvent()
+
+	if err := evt.SetData(cloudevents.ApplicationJSON, summary.Status); err != nil {
+		return nil, fmt.Errorf("failed to encode the summary %s to a cloudevent: %v", summary.Status.Key, err)
+	}
+
+	return &evt, nil
+}
+
+// Decode a cloudevent whose data is SummaryStatus to a ResourceSummary.
+func (c *Codec) Decode(evt *cloudevents.Event) (*ResourceSummary, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cloud event type %s, %v", evt.Type(), err)
+	}
+
+	if eventType.CloudEventsDataType != SummaryEventDataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	if eventType.SubResource != types.SubResourceStatus {
+		return nil, fmt.Errorf("unsupported subresource %s", eventType.SubResource)
+	}
+
+	evtExtensions := evt.Context.GetExtensions()
+
+	resourceID, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionResourceID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resourceid extension: %v", err)
+	}
+
+	resourceVersion, err := types.GetResourceVersion(*evt)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterName, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionClusterName])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clustername extension: %v", err)
+	}
+
+	originalSource, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionOriginalSource])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get originalsource extension: %v", err)
+	}
+
+	summary := &ResourceSummary{
+		UID:             kubetypes.UID(resourceID),
+		ResourceVersion: resourceVersion,
+		ClusterName:     clusterName,
+		OriginalSource:  originalSource,
+	}
+
+	if err := evt.DataAs(&summary.Status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event data %s, %v", string(evt.Data()), err)
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,167 @@
+// Package hubofhubs helps an intermediate hub of a multi-level fleet to re-expose the resources of its leaf clusters
+// to a parent hub, the intermediate hub is a source of its leaf clusters and an agent of the parent hub.
+//
+// The status events of the leaf clusters are either relayed to the parent hub as they are, with the cluster scope
+// renamed to the intermediate hub by Rescope, or summarized by an Aggregator, which groups the statuses of the same
+// resource on the leaf clusters into a ResourceSummary and publishes the summaries to the parent hub. There is no spec
+// of the ResourceSummary, so the parent hub never publishes the spec events of the SummaryEventDataType.
+package hubofhubs
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/uuid"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/bridge"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+var SummaryEventDataType = types.CloudEventsDataType{
+	Group:    "io.open-cluster-management.hubofhubs",
+	Version:  "v1alpha1",
+	Resource: "resourcesummaries",
+}
+
+// SummaryEventSchema describes the events of the resource summaries.
+var SummaryEventSchema = schema.EventSchema{
+	DataType: SummaryEventDataType,
+	Status:   schema.For[SummaryStatus](),
+}
+
+// SummaryStatusEventType is the event type of the resource summaries that are published by the intermediate hubs.
+var SummaryStatusEventType = types.CloudEventsType{
+	CloudEventsDataType: SummaryEventDataType,
+	SubResource:         types.SubResourceStatus,
+	Action:              "update_request",
+}
+
+// summaryNamespace is the namespace of the resource IDs of the resource summaries.
+var summaryNamespace = uuid.NewSHA1(uuid.NameSpaceOID, []byte(SummaryEventDataType.String()))
+
+// SummaryStatus is the data of a resource summary event, it summarizes the statuses of a resource on the leaf clusters
+// of an intermediate hub.
+type SummaryStatus struct {
+	// Key identifies the summarized resource on the leaf clusters, e.g. the name of a ManifestWork.
+	Key string `json:"key"`
+
+	// Clusters is the number of the leaf clusters that report the status of the resource.
+	Clusters int `json:"clusters"`
+
+	// Conditions are the counts of the condition statuses of the resource on the leaf clusters by the condition types,
+	// they are sorted by the types.
+	Conditions []ConditionSummary `json:"conditions,omitempty"`
+
+	// LeafClusters are the statuses of the resource on the leaf clusters, they are sorted by the cluster names.
+	LeafClusters []LeafStatus `json:"leafClusters,omitempty"`
+}
+
+// ConditionSummary is the counts of the statuses of a condition type on the leaf clusters.
+type ConditionSummary struct {
+	Type    string `json:"type"`
+	True    int    `json:"true"`
+	False   int    `json:"false"`
+	Unknown int    `json:"unknown"`
+}
+
+// LeafStatus is the status of a resource on a leaf cluster.
+type LeafStatus struct {
+	// ClusterName is the name of the leaf cluster.
+	ClusterName string `json:"clusterName"`
+
+	// ResourceID is the resource ID of the resource on the leaf cluster.
+	ResourceID string `json:"resourceID"`
+
+	// Conditions are the conditions of the resource on the leaf cluster.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ResourceSummary is the resource object of the summary of a resource on the leaf clusters, its cluster name is the
+// name of the intermediate hub on the parent hub.
+type ResourceSummary struct {
+	// UID is the resource ID of the summary, see SummaryID.
+	UID kubetypes.UID
+
+	// ResourceVersion is increased whenever the summary is changed.
+	ResourceVersion string
+
+	// ClusterName is the name of the intermediate hub on the parent hub.
+	ClusterName string
+
+	// OriginalSource is the source of the parent hub that the summary is published to.
+	OriginalSource string
+
+	// Status is the summarized status.
+	Status SummaryStatus
+}
+
+// SummaryID returns the resource ID of the summary of a key on an intermediate hub, the ID is derived from the name of
+// the hub and the key, so the hubs agree on it without exchanging it.
+func SummaryID(hubName, key string) kubetypes.UID {
+	return kubetypes.UID(uuid.NewSHA1(summaryNamespace, []byte(hubName+"/"+key)).String())
+}
+
+func (s *ResourceSummary) GetUID() kubetypes.UID {
+	return s.UID
+}
+
+func (s *ResourceSummary) GetResourceVersion() string {
+	return s.ResourceVersion
+}
+
+// GetDeletionTimestamp always returns nil, the resource summaries are not deleted by the parent hub.
+func (s *ResourceSummary) GetDeletionTimestamp() *metav1.Time {
+	return nil
+}
+
+// StatusHash returns the hash of the summarized status, it is the StatusHashGetter of the resource summaries. The
+// leaf clusters and the conditions are sorted in the status, so the intermediate hub and the parent hub compute the
+// same hash for the same summary.
+func StatusHash(s *ResourceSummary) (string, error) {
+	data, err := json.Marshal(s.Status)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the status of the summary %s, %v", s.Status.Key, err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// Rescope returns a bridge mapper that renames the cluster scope of the events of the leaf clusters to the given
+// intermediate hub, so the parent hub sees the resources of the leaf clusters as the resources of the intermediate
+// hub. The name of the leaf cluster is kept in the leafclustername extension, it is not changed if the event has been
+// rescoped by a lower level hub.
+func Rescope(hubName string) bridge.Mapper {
+	return func(evt cloudevents.Event) (cloudevents.Event, error) {
+		clusterName, err := cloudeventstypes.ToString(evt.Extensions()[types.ExtensionClusterName])
+		if err != nil {
+			return evt, fmt.Errorf("failed to get clustername extension: %v", err)
+		}
+
+		if len(LeafClusterName(evt)) == 0 {
+			evt.SetExtension(types.ExtensionLeafClusterName, clusterName)
+		}
+		evt.SetExtension(types.ExtensionClusterName, hubName)
+		return evt, nil
+	}
+}
+
+// LeafClusterName returns the name of the leaf cluster of a rescoped event, it is empty if the event is not rescoped.
+func LeafClusterName(evt cloudevents.Event) string {
+	val, ok := evt.Extensions()[types.ExtensionLeafClusterName]
+	if !ok {
+		return ""
+	}
+
+	clusterName, err := cloudeventstypes.ToString(val)
+	if err != nil {
+		return ""
+	}
+	return clusterName
+}
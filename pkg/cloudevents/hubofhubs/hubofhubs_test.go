@@ -0,0 +1,174 @@
+package hubofhubs
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	testHubName = "region1"
+	testSource  = "global-hub"
+)
+
+type leafResource struct {
+	uid         kubetypes.UID
+	name        string
+	clusterName string
+	conditions  []metav1.Condition
+}
+
+func (r *leafResource) GetUID() kubetypes.UID {
+	return r.uid
+}
+
+func (r *leafResource) GetResourceVersion() string {
+	return "1"
+}
+
+func (r *leafResource) GetDeletionTimestamp() *metav1.Time {
+	return nil
+}
+
+var leafFuncs = LeafFuncs[*leafResource]{
+	Key:         func(obj *leafResource) string { return obj.name },
+	ClusterName: func(obj *leafResource) string { return obj.clusterName },
+	Conditions:  func(obj *leafResource) []metav1.Condition { return obj.conditions },
+}
+
+func newLeafResource(clusterName string, available metav1.ConditionStatus) *leafResource {
+	return &leafResource{
+		uid:         kubetypes.UID(clusterName + "-work1"),
+		name:        "work1",
+		clusterName: clusterName,
+		conditions: []metav1.Condition{
+			{Type: "Applied", Status: metav1.ConditionTrue},
+			{Type: "Available", Status: available},
+		},
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aggregator := NewAggregator[*leafResource](testHubName, testSource, leafFuncs)
+
+	fakeClient := fake.NewCloudEventsFakeClient()
+	agent, err := generic.NewCloudEventAgentClient[*ResourceSummary](ctx,
+		fake.NewAgentOptions(fakeClient, testHubName, testHubName+"-agent"), aggregator, StatusHash, NewCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, leaf := range []*leafResource{
+		newLeafResource("cluster2", metav1.ConditionFalse),
+		newLeafResource("cluster1", metav1.ConditionTrue),
+	} {
+		if err := aggregator.Handle(types.StatusModified, leaf); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	summary, ok := aggregator.Get("work1")
+	if !ok {
+		t.Fatalf("expected the summary of work1")
+	}
+	if summary.UID != SummaryID(testHubName, "work1") || summary.ClusterName != testHubName {
+		t.Errorf("unexpected summary %v", summary)
+	}
+	if summary.Status.Clusters != 2 || summary.Status.LeafClusters[0].ClusterName != "cluster1" {
+		t.Errorf("unexpected summary status %v", summary.Status)
+	}
+	expected := []ConditionSummary{{Type: "Applied", True: 2}, {Type: "Available", True: 1, False: 1}}
+	for i, condition := range summary.Status.Conditions {
+		if condition != expected[i] {
+			t.Errorf("expected %v, but got %v", expected[i], condition)
+		}
+	}
+
+	if err := aggregator.Publish(ctx, agent); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	sent := fakeClient.GetSentEvents()
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 summary event, but got %v", sent)
+	}
+	if err := schema.NewRegistry(SummaryEventSchema).Validate(sent[0]); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	decoded, err := NewCodec().Decode(&sent[0])
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	decodedHash, _ := StatusHash(decoded)
+	hash, _ := StatusHash(summary)
+	if decodedHash != hash {
+		t.Errorf("expected the same hash of the decoded summary, but got %s and %s", hash, decodedHash)
+	}
+
+	// the unchanged status does not change the summary
+	if err := aggregator.Handle(types.StatusModified, newLeafResource("cluster1", metav1.ConditionTrue)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if unchanged, _ := aggregator.Get("work1"); unchanged.ResourceVersion != summary.ResourceVersion {
+		t.Errorf("expected the version %s, but got %s", summary.ResourceVersion, unchanged.ResourceVersion)
+	}
+	if err := aggregator.Publish(ctx, agent); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(fakeClient.GetSentEvents()) != 1 {
+		t.Errorf("expected no more summary event, but got %v", fakeClient.GetSentEvents())
+	}
+
+	// the empty summary is published once and removed
+	aggregator.Forget("cluster2")
+	if err := aggregator.Handle(types.Deleted, newLeafResource("cluster1", metav1.ConditionTrue)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := aggregator.Publish(ctx, agent); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(fakeClient.GetSentEvents()) != 2 {
+		t.Errorf("expected 2 summary events, but got %v", fakeClient.GetSentEvents())
+	}
+	if summaries, _ := aggregator.List(types.ListOptions{}); len(summaries) != 0 {
+		t.Errorf("expected no summaries, but got %v", summaries)
+	}
+}
+
+func TestRescope(t *testing.T) {
+	evt := types.NewEventBuilder("cluster1-agent", types.CloudEventsType{
+		CloudEventsDataType: SummaryEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "update_request",
+	}).WithClusterName("cluster1").NewEvent()
+
+	rescoped, err := Rescope(testHubName)(evt)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if clusterName := rescoped.Extensions()[types.ExtensionClusterName]; clusterName != testHubName {
+		t.Errorf("expected %s, but got %v", testHubName, clusterName)
+	}
+	if leaf := LeafClusterName(rescoped); leaf != "cluster1" {
+		t.Errorf("expected cluster1, but got %s", leaf)
+	}
+
+	// the leaf cluster is kept when the event is rescoped again by a higher level hub
+	rescoped, err = Rescope("global1")(rescoped)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if leaf := LeafClusterName(rescoped); leaf != "cluster1" {
+		t.Errorf("expected cluster1, but got %s", leaf)
+	}
+}
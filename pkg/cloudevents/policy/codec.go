@@ -0,0 +1,129 @@
+package policy
+
+import (
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// Codec is a codec to encode/decode the Policy/cloudevent, it is used by both the agents and the sources, the spec
+// events have the PolicySpec and the status events have the PolicyStatus.
+type Codec struct{}
+
+func NewCodec() *Codec {
+	return &Codec{}
+}
+
+// EventDataType always returns the event data type `io.open-cluster-management.policies.v1alpha1.policies`.
+func (c *Codec) EventDataType() types.CloudEventsDataType {
+	return PolicyEventDataType
+}
+
+// Encode the spec or the status of a Policy to a cloudevent by the subresource of the event type, the spec event of a
+// deleting Policy has no data.
+func (c *Codec) Encode(source string, eventType types.CloudEventsType, policy *Policy) (*cloudevents.Event, error) {
+	if eventType.CloudEventsDataType != PolicyEventDataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	builder := types.NewEventBuilder(source, eventType).
+		WithResourceID(string(policy.UID)).
+		WithStringResourceVersion(policy.ResourceVersion).
+		WithClusterName(policy.ClusterName)
+
+	switch eventType.SubResource {
+	case types.SubResourceSpec:
+		if policy.DeletionTimestamp != nil && !policy.DeletionTimestamp.IsZero() {
+			evt := builder.WithDeletionTimestamp(policy.DeletionTimestamp.Time).NewEvent()
+			return &evt, nil
+		}
+
+		evt := builder.NewEvent()
+		if err := evt.SetData(cloudevents.ApplicationJSON, policy.Spec); err != nil {
+			return nil, fmt.Errorf("failed to encode the policy spec %s to a cloudevent: %v", policy.UID, err)
+		}
+		return &evt, nil
+	case types.SubResourceStatus:
+		evt := builder.WithOriginalSource(policy.OriginalSource).NewEvent()
+		if err := evt.SetData(cloudevents.ApplicationJSON, policy.Status); err != nil {
+			return nil, fmt.Errorf("failed to encode the policy status %s to a cloudevent: %v", policy.UID, err)
+		}
+		return &evt, nil
+	default:
+		return nil, fmt.Errorf("unsupported subresource %s", eventType.SubResource)
+	}
+}
+
+// Decode a cloudevent whose data is PolicySpec or PolicyStatus to a Policy.
+func (c *Codec) Decode(evt *cloudevents.Event) (*Policy, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cloud event type %s, %v", evt.Type(), err)
+	}
+
+	if eventType.CloudEventsDataType != PolicyEventDataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	evtExtensions := evt.Context.GetExtensions()
+
+	resourceID, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionResourceID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resourceid extension: %v", err)
+	}
+
+	resourceVersion, err := types.GetResourceVersion(*evt)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterName, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionClusterName])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clustername extension: %v", err)
+	}
+
+	policy := &Policy{
+		UID:             kubetypes.UID(resourceID),
+		ResourceVersion: resourceVersion,
+		ClusterName:     clusterName,
+	}
+
+	switch eventType.SubResource {
+	case types.SubResourceSpec:
+		policy.OriginalSource = evt.Source()
+
+		if _, ok := evtExtensions[types.ExtensionDeletionTimestamp]; ok {
+			deletionTimestamp, err := cloudeventstypes.ToTime(evtExtensions[types.ExtensionDeletionTimestamp])
+			if err != nil {
+				return nil, fmt.Errorf("failed to get deletiontimestamp, %v", err)
+			}
+
+			policy.DeletionTimestamp = &metav1.Time{Time: deletionTimestamp}
+			return policy, nil
+		}
+
+		if err := evt.DataAs(&policy.Spec); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event data %s, %v", string(evt.Data()), err)
+		}
+	case types.SubResourceStatus:
+		originalSource, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionOriginalSource])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get originalsource extension: %v", err)
+		}
+		policy.OriginalSource = originalSource
+
+		if err := evt.DataAs(&policy.Status); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event data %s, %v", string(evt.Data()), err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported subresource %s", eventType.SubResource)
+	}
+
+	return policy, nil
+}
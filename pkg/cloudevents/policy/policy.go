@@ -0,0 +1,259 @@
+// Package policy distributes the governance policies from the sources to the agents with the cloud events, and reports
+// the compliance status of the policies from the agents back to the sources, so the governance addons can operate the
+// managed clusters that are only connected to the broker.
+//
+// The spec of a Policy is the replicated policy of a managed cluster, its policy templates are usually the
+// ConfigurationPolicies, see NewConfigurationPolicyTemplate. The status of a Policy is the compliance of the policy on
+// the managed cluster. The types mirror the wire format of the policy.open-cluster-management.io/v1 API, so the
+// governance addons can convert them to their own API types with a JSON round-trip.
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+var PolicyEventDataType = types.CloudEventsDataType{
+	Group:    "io.open-cluster-management.policies",
+	Version:  "v1alpha1",
+	Resource: "policies",
+}
+
+// PolicyEventSchema describes the events of the policies.
+var PolicyEventSchema = schema.EventSchema{
+	DataType: PolicyEventDataType,
+	Spec:     schema.For[PolicySpec](),
+	Status:   schema.For[PolicyStatus](),
+}
+
+// PolicyStatusEventType is the event type of the compliance status that is reported by the agents.
+var PolicyStatusEventType = types.CloudEventsType{
+	CloudEventsDataType: PolicyEventDataType,
+	SubResource:         types.SubResourceStatus,
+	Action:              "update_request",
+}
+
+// policyNamespace is the namespace of the resource IDs of the policies.
+var policyNamespace = uuid.NewSHA1(uuid.NameSpaceOID, []byte(PolicyEventDataType.String()))
+
+const (
+	// ConfigurationPolicyAPIVersion and ConfigurationPolicyKind are the type of the ConfigurationPolicy templates.
+	ConfigurationPolicyAPIVersion = "policy.open-cluster-management.io/v1"
+	ConfigurationPolicyKind       = "ConfigurationPolicy"
+)
+
+// RemediationAction is the remediation of a policy when it is not compliant.
+type RemediationAction string
+
+const (
+	// Inform reports the violations of the policy.
+	Inform RemediationAction = "inform"
+
+	// Enforce remediates the violations of the policy.
+	Enforce RemediationAction = "enforce"
+)
+
+// ComplianceState is the compliance of a policy or a policy template.
+type ComplianceState string
+
+const (
+	Compliant    ComplianceState = "Compliant"
+	NonCompliant ComplianceState = "NonCompliant"
+	Pending      ComplianceState = "Pending"
+)
+
+// ComplianceType is how an object template of a ConfigurationPolicy is compared with the object on the cluster.
+type ComplianceType string
+
+const (
+	MustHave     ComplianceType = "musthave"
+	MustOnlyHave ComplianceType = "mustonlyhave"
+	MustNotHave  ComplianceType = "mustnothave"
+)
+
+// PolicySpec is the data of a policy spec event, it is the spec of the replicated policy of a managed cluster.
+type PolicySpec struct {
+	// Name is the name of the replicated policy, e.g. <namespace>.<name> of the root policy on the hub.
+	Name string `json:"name"`
+
+	// Disabled indicates the policy is not evaluated on the managed cluster.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// RemediationAction overrides the remediation actions of the policy templates if it is set.
+	RemediationAction RemediationAction `json:"remediationAction,omitempty"`
+
+	// PolicyTemplates are the templates of the policy, e.g. the ConfigurationPolicies.
+	PolicyTemplates []PolicyTemplate `json:"policy-templates"`
+}
+
+// PolicyTemplate is a template of a policy, its object definition is a policy object, e.g. a ConfigurationPolicy.
+type PolicyTemplate struct {
+	ObjectDefinition runtime.RawExtension `json:"objectDefinition"`
+}
+
+// PolicyStatus is the data of a policy status event, it is the compliance of the policy on a managed cluster.
+type PolicyStatus struct {
+	// ComplianceState is the compliance of the policy, the policy is compliant if all its templates are compliant.
+	ComplianceState ComplianceState `json:"compliant,omitempty"`
+
+	// Details are the compliance of the templates of the policy.
+	Details []TemplateStatus `json:"details,omitempty"`
+}
+
+// TemplateStatus is the compliance of a policy template.
+type TemplateStatus struct {
+	// TemplateMeta is the name and kind of the template.
+	TemplateMeta TemplateMeta `json:"templateMeta"`
+
+	// ComplianceState is the compliance of the template.
+	ComplianceState ComplianceState `json:"compliant,omitempty"`
+
+	// History is the compliance history of the template, the latest is the first.
+	History []ComplianceHistory `json:"history,omitempty"`
+}
+
+// TemplateMeta identifies a policy template.
+type TemplateMeta struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// ComplianceHistory is a compliance message of a policy template.
+type ComplianceHistory struct {
+	LastTimestamp metav1.Time `json:"lastTimestamp,omitempty"`
+	Message       string      `json:"message,omitempty"`
+	EventName     string      `json:"eventName,omitempty"`
+}
+
+// ConfigurationPolicy is a policy template that checks or enforces the objects on a managed cluster.
+type ConfigurationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ConfigurationPolicySpec `json:"spec"`
+}
+
+// ConfigurationPolicySpec is the spec of a ConfigurationPolicy.
+type ConfigurationPolicySpec struct {
+	// RemediationAction is the remediation of the ConfigurationPolicy when it is not compliant.
+	RemediationAction RemediationAction `json:"remediationAction,omitempty"`
+
+	// Severity is the severity of the violations, e.g. low, medium, high or critical.
+	Severity string `json:"severity,omitempty"`
+
+	// NamespaceSelector selects the namespaces of the namespaced object templates.
+	NamespaceSelector *NamespaceSelector `json:"namespaceSelector,omitempty"`
+
+	// ObjectTemplates are the objects that are checked or enforced.
+	ObjectTemplates []ObjectTemplate `json:"object-templates,omitempty"`
+}
+
+// NamespaceSelector selects the namespaces by their names, the names may be the filepath patterns.
+type NamespaceSelector struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// ObjectTemplate is an object of a ConfigurationPolicy.
+type ObjectTemplate struct {
+	ComplianceType   ComplianceType       `json:"complianceType"`
+	ObjectDefinition runtime.RawExtension `json:"objectDefinition"`
+}
+
+// NewConfigurationPolicyTemplate returns the policy template of a ConfigurationPolicy with the given name and spec.
+func NewConfigurationPolicyTemplate(name string, spec ConfigurationPolicySpec) (PolicyTemplate, error) {
+	configPolicy := &ConfigurationPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ConfigurationPolicyAPIVersion,
+			Kind:       ConfigurationPolicyKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       spec,
+	}
+
+	data, err := json.Marshal(configPolicy)
+	if err != nil {
+		return PolicyTemplate{}, fmt.Errorf("failed to marshal the configuration policy %s, %v", name, err)
+	}
+
+	return PolicyTemplate{ObjectDefinition: runtime.RawExtension{Raw: data}}, nil
+}
+
+// ConfigurationPolicy returns the ConfigurationPolicy of the policy template, it returns false if the template is
+// another kind of policy.
+func (t PolicyTemplate) ConfigurationPolicy() (*ConfigurationPolicy, bool, error) {
+	configPolicy := &ConfigurationPolicy{}
+	if err := json.Unmarshal(t.ObjectDefinition.Raw, configPolicy); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal the policy template, %v", err)
+	}
+
+	if configPolicy.APIVersion != ConfigurationPolicyAPIVersion || configPolicy.Kind != ConfigurationPolicyKind {
+		return nil, false, nil
+	}
+
+	return configPolicy, true, nil
+}
+
+// Policy is the resource object of a replicated policy of a managed cluster.
+type Policy struct {
+	// UID is the resource ID of the policy, see PolicyID.
+	UID kubetypes.UID
+
+	// ResourceVersion is the version of the policy spec that is increased by the source, the status of the policy is
+	// reported with the version of the spec that it is evaluated with.
+	ResourceVersion string
+
+	// ClusterName is the name of the managed cluster.
+	ClusterName string
+
+	// OriginalSource is the source that distributes the policy.
+	OriginalSource string
+
+	// DeletionTimestamp is set when the policy is deleted from the managed cluster by the source.
+	DeletionTimestamp *metav1.Time
+
+	// Spec is the spec of the policy that is distributed by the source.
+	Spec PolicySpec
+
+	// Status is the compliance of the policy that is reported by the agent.
+	Status PolicyStatus
+}
+
+// PolicyID returns the resource ID of a policy of a cluster that is distributed by a source, the ID is derived from
+// the source, the cluster name and the policy name, so the agents and the sources agree on it without exchanging it.
+func PolicyID(source, clusterName, name string) kubetypes.UID {
+	id := fmt.Sprintf("%s-%s-%s", source, clusterName, name)
+	return kubetypes.UID(uuid.NewSHA1(policyNamespace, []byte(id)).String())
+}
+
+func (p *Policy) GetUID() kubetypes.UID {
+	return p.UID
+}
+
+func (p *Policy) GetResourceVersion() string {
+	return p.ResourceVersion
+}
+
+func (p *Policy) GetDeletionTimestamp() *metav1.Time {
+	return p.DeletionTimestamp
+}
+
+// StatusHash returns the hash of the policy status, it is the StatusHashGetter of the policies.
+func StatusHash(p *Policy) (string, error) {
+	data, err := json.Marshal(p.Status)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the status of the policy %s, %v", p.UID, err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
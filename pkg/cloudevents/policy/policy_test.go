@@ -0,0 +1,154 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	testSource      = "hub1"
+	testClusterName = "cluster1"
+)
+
+var specEventType = types.CloudEventsType{
+	CloudEventsDataType: PolicyEventDataType,
+	SubResource:         types.SubResourceSpec,
+	Action:              "create_request",
+}
+
+func newPolicy(t *testing.T) *Policy {
+	template, err := NewConfigurationPolicyTemplate("test-config", ConfigurationPolicySpec{
+		RemediationAction: Inform,
+		Severity:          "low",
+		NamespaceSelector: &NamespaceSelector{Include: []string{"default"}},
+		ObjectTemplates: []ObjectTemplate{{
+			ComplianceType:   MustHave,
+			ObjectDefinition: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"test"}}`)},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Policy{
+		UID:             PolicyID(testSource, testClusterName, "default.test"),
+		ResourceVersion: "1",
+		ClusterName:     testClusterName,
+		OriginalSource:  testSource,
+		Spec: PolicySpec{
+			Name:              "default.test",
+			RemediationAction: Enforce,
+			PolicyTemplates:   []PolicyTemplate{template},
+		},
+		Status: PolicyStatus{
+			ComplianceState: NonCompliant,
+			Details: []TemplateStatus{{
+				TemplateMeta:    TemplateMeta{Name: "test-config", Kind: ConfigurationPolicyKind},
+				ComplianceState: NonCompliant,
+				History: []ComplianceHistory{{
+					LastTimestamp: metav1.NewTime(time.Now().Truncate(time.Second)),
+					Message:       "NonCompliant; violation - namespaces [test] not found",
+				}},
+			}},
+		},
+	}
+}
+
+func TestCodec(t *testing.T) {
+	codec := NewCodec()
+	policy := newPolicy(t)
+	registry := schema.NewRegistry(PolicyEventSchema)
+
+	// the source distributes the spec
+	evt, err := codec.Encode(testSource, specEventType, policy)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := registry.Validate(*evt); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	decoded, err := codec.Decode(evt)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if decoded.UID != policy.UID || decoded.ResourceVersion != policy.ResourceVersion ||
+		decoded.ClusterName != policy.ClusterName || decoded.OriginalSource != policy.OriginalSource {
+		t.Errorf("expected %v, but got %v", policy, decoded)
+	}
+	if !reflect.DeepEqual(decoded.Spec, policy.Spec) {
+		t.Errorf("expected %v, but got %v", policy.Spec, decoded.Spec)
+	}
+
+	configPolicy, ok, err := decoded.Spec.PolicyTemplates[0].ConfigurationPolicy()
+	if err != nil || !ok {
+		t.Fatalf("expected a configuration policy, but got %v, %v", ok, err)
+	}
+	if configPolicy.Name != "test-config" || configPolicy.Spec.ObjectTemplates[0].ComplianceType != MustHave {
+		t.Errorf("unexpected configuration policy %v", configPolicy)
+	}
+
+	// the agent reports the compliance status
+	evt, err = codec.Encode("cluster1-agent", PolicyStatusEventType, policy)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := registry.Validate(*evt); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	decoded, err = codec.Decode(evt)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if decoded.OriginalSource != testSource {
+		t.Errorf("expected %s, but got %s", testSource, decoded.OriginalSource)
+	}
+	if !equality.Semantic.DeepEqual(decoded.Status, policy.Status) {
+		t.Errorf("expected %v, but got %v", policy.Status, decoded.Status)
+	}
+
+	// the source deletes the policy
+	policy.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	evt, err = codec.Encode(testSource, specEventType, policy)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(evt.Data()) != 0 {
+		t.Errorf("expected no data, but got %s", string(evt.Data()))
+	}
+
+	decoded, err = codec.Decode(evt)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if decoded.GetDeletionTimestamp() == nil {
+		t.Errorf("expected the deletion timestamp is set")
+	}
+}
+
+func TestStatusHash(t *testing.T) {
+	policy := newPolicy(t)
+
+	hash, err := StatusHash(policy)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	policy.Status.ComplianceState = Compliant
+	changed, err := StatusHash(policy)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if hash == changed {
+		t.Errorf("expected the hash is changed when the compliance is changed")
+	}
+}
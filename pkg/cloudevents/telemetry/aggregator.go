@@ -0,0 +1,162 @@
+package telemetry
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// FleetMetrics is the aggregation of the latest metrics samples of the clusters.
+type FleetMetrics struct {
+	// Clusters is the number of the clusters whose latest samples are aggregated.
+	Clusters int `json:"clusters"`
+
+	// StaleClusters are the names of the clusters whose latest samples are older than the max age of the aggregator,
+	// their samples are not aggregated.
+	StaleClusters []string `json:"staleClusters,omitempty"`
+
+	// Nodes is the total number of the nodes of the clusters.
+	Nodes int64 `json:"nodes"`
+
+	// ReadyNodes is the total number of the ready nodes of the clusters.
+	ReadyNodes int64 `json:"readyNodes"`
+
+	// Capacity is the total capacity of the clusters.
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+
+	// Allocatable is the total allocatable resources of the clusters.
+	Allocatable corev1.ResourceList `json:"allocatable,omitempty"`
+
+	// Usage is the total resource usage of the clusters.
+	Usage corev1.ResourceList `json:"usage,omitempty"`
+}
+
+// Aggregator keeps the latest metrics samples of the clusters on a source and aggregates them. The aggregator is the
+// Lister of the source client and its Handle is the resource handler of the subscription, e.g.
+//
+//	aggregator := telemetry.NewAggregator(5 * time.Minute)
+//	client, err := generic.NewCloudEventSourceClient[*telemetry.ClusterMetrics](
+//		ctx, sourceOptions, aggregator, telemetry.StatusHash, telemetry.NewCodec())
+//	client.Subscribe(ctx, aggregator.Handle)
+type Aggregator struct {
+	sync.RWMutex
+	maxAge  time.Duration
+	clock   clock.PassiveClock
+	metrics map[string]*ClusterMetrics
+}
+
+// NewAggregator returns an Aggregator, the samples that are older than the max age are not aggregated. If the max age
+// is less than or equal to zero, the samples never become stale.
+func NewAggregator(maxAge time.Duration) *Aggregator {
+	return &Aggregator{
+		maxAge:  maxAge,
+		clock:   clock.RealClock{},
+		metrics: map[string]*ClusterMetrics{},
+	}
+}
+
+// List returns the latest metrics of the clusters. If a cluster is specified and it has not reported a sample, the
+// metrics without a sample is returned, so the first sample of the cluster is handled by the source client.
+func (a *Aggregator) List(options types.ListOptions) ([]*ClusterMetrics, error) {
+	a.RLock()
+	defer a.RUnlock()
+
+	if options.ClusterName != types.ClusterAll {
+		if metrics, ok := a.metrics[options.ClusterName]; ok {
+			return []*ClusterMetrics{metrics}, nil
+		}
+
+		return []*ClusterMetrics{{
+			UID:            ClusterMetricsID(options.ClusterName),
+			ClusterName:    options.ClusterName,
+			OriginalSource: options.Source,
+		}}, nil
+	}
+
+	metrics := make([]*ClusterMetrics, 0, len(a.metrics))
+	for _, m := range a.metrics {
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+// Handle keeps the received metrics sample of a cluster, the sample is ignored if it is older than the latest sample of
+// the cluster.
+func (a *Aggregator) Handle(action types.ResourceAction, metrics *ClusterMetrics) error {
+	if action != types.StatusModified {
+		return nil
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	if last, ok := a.metrics[metrics.ClusterName]; ok && metrics.Sample.Timestamp.Before(&last.Sample.Timestamp) {
+		klog.V(4).Infof("the metrics sample of the cluster %s is older than the latest sample, ignore",
+			metrics.ClusterName)
+		return nil
+	}
+
+	a.metrics[metrics.ClusterName] = metrics
+	return nil
+}
+
+// Get returns the latest metrics of a cluster.
+func (a *Aggregator) Get(clusterName string) (*ClusterMetrics, bool) {
+	a.RLock()
+	defer a.RUnlock()
+
+	metrics, ok := a.metrics[clusterName]
+	return metrics, ok
+}
+
+// Forget removes the metrics of a cluster, e.g. the cluster is detached from the fleet.
+func (a *Aggregator) Forget(clusterName string) {
+	a.Lock()
+	defer a.Unlock()
+
+	delete(a.metrics, clusterName)
+}
+
+// Aggregate returns the aggregation of the latest samples of the clusters.
+func (a *Aggregator) Aggregate() FleetMetrics {
+	a.RLock()
+	defer a.RUnlock()
+
+	now := a.clock.Now()
+	fleet := FleetMetrics{
+		Capacity:    corev1.ResourceList{},
+		Allocatable: corev1.ResourceList{},
+		Usage:       corev1.ResourceList{},
+	}
+	for clusterName, metrics := range a.metrics {
+		sample := metrics.Sample
+		if a.maxAge > 0 && now.Sub(sample.Timestamp.Time) > a.maxAge {
+			fleet.StaleClusters = append(fleet.StaleClusters, clusterName)
+			continue
+		}
+
+		fleet.Clusters++
+		fleet.Nodes += int64(sample.Nodes)
+		fleet.ReadyNodes += int64(sample.ReadyNodes)
+		addResources(fleet.Capacity, sample.Capacity)
+		addResources(fleet.Allocatable, sample.Allocatable)
+		addResources(fleet.Usage, sample.Usage)
+	}
+
+	sort.Strings(fleet.StaleClusters)
+	return fleet
+}
+
+func addResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
@@ -0,0 +1,100 @@
+package telemetry
+
+import (
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// Codec is a codec to encode/decode the ClusterMetrics/cloudevent with MetricsSample, it is used by both the agents
+// and the sources, only the status events are supported.
+type Codec struct{}
+
+func NewCodec() *Codec {
+	return &Codec{}
+}
+
+// EventDataType always returns the event data type `io.open-cluster-management.telemetry.v1alpha1.clustermetrics`.
+func (c *Codec) EventDataType() types.CloudEventsDataType {
+	return MetricsEventDataType
+}
+
+// Encode the metrics sample of a ClusterMetrics to a cloudevent.
+func (c *Codec) Encode(source string, eventType types.CloudEventsType, metrics *ClusterMetrics) (*cloudevents.Event, error) {
+	if eventType.CloudEventsDataType != MetricsEventDataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	if eventType.SubResource != types.SubResourceStatus {
+		return nil, fmt.Errorf("unsupported subresource %s", eventType.SubResource)
+	}
+
+	evt := types.NewEventBuilder(source, eventType).
+		WithResourceID(string(metrics.UID)).
+		WithStringResourceVersion(metrics.ResourceVersion).
+		WithClusterName(metrics.ClusterName).
+		WithOriginalSource(metrics.OriginalSource).
+		NewEvent()
+
+	if err := evt.SetData(cloudevents.ApplicationJSON, metrics.Sample); err != nil {
+		return nil, fmt.Errorf("failed to encode the metrics of the cluster %s to a cloudevent: %v", metrics.ClusterName, err)
+	}
+
+	return &evt, nil
+}
+
+// Decode a cloudevent whose data is MetricsSample to a ClusterMetrics.
+func (c *Codec) Decode(evt *cloudevents.Event) (*ClusterMetrics, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cloud event type %s, %v", evt.Type(), err)
+	}
+
+	if eventType.CloudEventsDataType != MetricsEventDataType {
+		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
+	}
+
+	if eventType.SubResource != types.SubResourceStatus {
+		return nil, fmt.Errorf("unsupported subresource %s", eventType.SubResource)
+	}
+
+	evtExtensions := evt.Context.GetExtensions()
+
+	resourceID, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionResourceID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resourceid extension: %v", err)
+	}
+
+	resourceVersion, err := types.GetResourceVersion(*evt)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterName, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionClusterName])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clustername extension: %v", err)
+	}
+
+	originalSource, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionOriginalSource])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get originalsource extension: %v", err)
+	}
+
+	metrics := &ClusterMetrics{
+		UID:             kubetypes.UID(resourceID),
+		ResourceVersion: resourceVersion,
+		ClusterName:     clusterName,
+		OriginalSource:  originalSource,
+	}
+
+	if err := evt.DataAs(&metrics.Sample); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event data %s, %v", string(evt.Data()), err)
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,60 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// Reporter publishes the metrics samples of a cluster to a source with an agent client. The reporter keeps the latest
+// sample, it is the Lister of the agent client, so the latest sample is resent when the source resyncs the status.
+type Reporter struct {
+	sync.RWMutex
+	clusterName string
+	source      string
+	latest      *ClusterMetrics
+}
+
+// NewReporter returns a Reporter that reports the metrics of the given cluster to the given source.
+func NewReporter(clusterName, source string) *Reporter {
+	return &Reporter{
+		clusterName: clusterName,
+		source:      source,
+	}
+}
+
+// List returns the latest metrics of the cluster if it has been reported.
+func (r *Reporter) List(options types.ListOptions) ([]*ClusterMetrics, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	if r.latest == nil {
+		return nil, nil
+	}
+
+	if options.ClusterName != types.ClusterAll && options.ClusterName != r.clusterName {
+		return nil, nil
+	}
+
+	if options.Source != types.SourceAll && options.Source != r.source {
+		return nil, nil
+	}
+
+	return []*ClusterMetrics{r.latest}, nil
+}
+
+// Report publishes a metrics sample of the cluster with the agent client, the sample is kept as the latest sample
+// after it is published.
+func (r *Reporter) Report(ctx context.Context, client generic.CloudEventsClient[*ClusterMetrics], sample MetricsSample) error {
+	metrics := NewClusterMetrics(r.clusterName, r.source, sample)
+	if err := client.Publish(ctx, MetricsStatusEventType, metrics); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	r.latest = metrics
+	return nil
+}
@@ -0,0 +1,125 @@
+// Package telemetry publishes the lightweight metrics samples of the managed clusters from the agents to the sources
+// with the cloud events, so the fleet monitoring shares the transport of the resources.
+//
+// The samples of a cluster are the status of a ClusterMetrics resource, the agent publishes the latest sample with a
+// Reporter, and the source aggregates the latest samples of the clusters with an Aggregator. There is no spec of the
+// ClusterMetrics, so the sources never publish the spec events of the MetricsEventDataType.
+package telemetry
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+var MetricsEventDataType = types.CloudEventsDataType{
+	Group:    "io.open-cluster-management.telemetry",
+	Version:  "v1alpha1",
+	Resource: "clustermetrics",
+}
+
+// MetricsEventSchema describes the events of the cluster metrics.
+var MetricsEventSchema = schema.EventSchema{
+	DataType: MetricsEventDataType,
+	Status:   schema.For[MetricsSample](),
+}
+
+// MetricsStatusEventType is the event type of the metrics samples that are published by the agents.
+var MetricsStatusEventType = types.CloudEventsType{
+	CloudEventsDataType: MetricsEventDataType,
+	SubResource:         types.SubResourceStatus,
+	Action:              "update_request",
+}
+
+// metricsNamespace is the namespace of the resource IDs of the cluster metrics.
+var metricsNamespace = uuid.NewSHA1(uuid.NameSpaceOID, []byte(MetricsEventDataType.String()))
+
+// MetricsSample is the data of a metrics event, it is a snapshot of the metrics of a managed cluster.
+type MetricsSample struct {
+	// Timestamp is the time when the sample is collected.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// Nodes is the number of the nodes of the cluster.
+	Nodes int32 `json:"nodes"`
+
+	// ReadyNodes is the number of the ready nodes of the cluster.
+	ReadyNodes int32 `json:"readyNodes"`
+
+	// Capacity is the total capacity of the nodes of the cluster, e.g. the cpu and the memory.
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+
+	// Allocatable is the total allocatable resources of the nodes of the cluster.
+	Allocatable corev1.ResourceList `json:"allocatable,omitempty"`
+
+	// Usage is the snapshot of the resources that are used on the cluster.
+	Usage corev1.ResourceList `json:"usage,omitempty"`
+}
+
+// ClusterMetrics is the resource object of the metrics of a managed cluster, its status is the latest metrics sample.
+type ClusterMetrics struct {
+	// UID is the resource ID of the cluster metrics, see ClusterMetricsID.
+	UID kubetypes.UID
+
+	// ResourceVersion is the timestamp of the sample in seconds, so the later samples have the greater versions.
+	ResourceVersion string
+
+	// ClusterName is the name of the managed cluster.
+	ClusterName string
+
+	// OriginalSource is the source that the metrics are published to.
+	OriginalSource string
+
+	// Sample is the latest metrics sample of the cluster.
+	Sample MetricsSample
+}
+
+// NewClusterMetrics returns the ClusterMetrics of a cluster with the given sample, the sample is published to the
+// given source.
+func NewClusterMetrics(clusterName, source string, sample MetricsSample) *ClusterMetrics {
+	return &ClusterMetrics{
+		UID:             ClusterMetricsID(clusterName),
+		ResourceVersion: strconv.FormatInt(sample.Timestamp.Unix(), 10),
+		ClusterName:     clusterName,
+		OriginalSource:  source,
+		Sample:          sample,
+	}
+}
+
+// ClusterMetricsID returns the resource ID of the metrics of a cluster, the ID is derived from the cluster name, so
+// the agents and the sources agree on it without exchanging it.
+func ClusterMetricsID(clusterName string) kubetypes.UID {
+	return kubetypes.UID(uuid.NewSHA1(metricsNamespace, []byte(clusterName)).String())
+}
+
+func (m *ClusterMetrics) GetUID() kubetypes.UID {
+	return m.UID
+}
+
+func (m *ClusterMetrics) GetResourceVersion() string {
+	return m.ResourceVersion
+}
+
+// GetDeletionTimestamp always returns nil, the cluster metrics are not deleted by the sources.
+func (m *ClusterMetrics) GetDeletionTimestamp() *metav1.Time {
+	return nil
+}
+
+// StatusHash returns the hash of the metrics sample, it is the StatusHashGetter of the cluster metrics.
+func StatusHash(m *ClusterMetrics) (string, error) {
+	data, err := json.Marshal(m.Sample)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the metrics sample of the cluster %s, %v", m.ClusterName, err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
@@ -0,0 +1,154 @@
+package telemetry
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const testSource = "hub1"
+
+func newSample(timestamp time.Time, nodes int32, cpu string) MetricsSample {
+	return MetricsSample{
+		Timestamp:  metav1.NewTime(timestamp.Truncate(time.Second)),
+		Nodes:      nodes,
+		ReadyNodes: nodes,
+		Capacity:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+		Usage:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+	}
+}
+
+func TestCodec(t *testing.T) {
+	codec := NewCodec()
+	metrics := NewClusterMetrics("cluster1", testSource, newSample(time.Now(), 3, "12"))
+
+	evt, err := codec.Encode("cluster1-agent", MetricsStatusEventType, metrics)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := schema.NewRegistry(MetricsEventSchema).Validate(*evt); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	decoded, err := codec.Decode(evt)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if decoded.UID != metrics.UID || decoded.ResourceVersion != metrics.ResourceVersion ||
+		decoded.ClusterName != metrics.ClusterName || decoded.OriginalSource != metrics.OriginalSource {
+		t.Errorf("expected %v, but got %v", metrics, decoded)
+	}
+	if !equality.Semantic.DeepEqual(decoded.Sample, metrics.Sample) {
+		t.Errorf("expected %v, but got %v", metrics.Sample, decoded.Sample)
+	}
+
+	specEventType := types.CloudEventsType{
+		CloudEventsDataType: MetricsEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "update_request",
+	}
+	if _, err := codec.Encode(testSource, specEventType, metrics); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+}
+
+func TestReportAndAggregate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	now := time.Now()
+	events := []cloudevents.Event{}
+	for clusterName, sample := range map[string]MetricsSample{
+		"cluster1": newSample(now, 3, "12"),
+		"cluster2": newSample(now, 2, "8"),
+		"cluster3": newSample(now.Add(-time.Hour), 5, "20"),
+	} {
+		agentClient := fake.NewCloudEventsFakeClient()
+		agent, err := generic.NewCloudEventAgentClient[*ClusterMetrics](ctx,
+			fake.NewAgentOptions(agentClient, clusterName, clusterName+"-agent"),
+			NewReporter(clusterName, testSource), StatusHash, NewCodec())
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		reporter := NewReporter(clusterName, testSource)
+		if err := reporter.Report(ctx, agent, sample); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if metrics, _ := reporter.List(types.ListOptions{Source: testSource}); len(metrics) != 1 {
+			t.Errorf("expected the latest metrics, but got %v", metrics)
+		}
+
+		events = append(events, agentClient.GetSentEvents()...)
+	}
+
+	aggregator := NewAggregator(10 * time.Minute)
+	aggregator.clock = testingclock.NewFakePassiveClock(now)
+
+	source, err := generic.NewCloudEventSourceClient[*ClusterMetrics](ctx,
+		fake.NewSourceOptions(fake.NewCloudEventsFakeClient(events...), testSource),
+		aggregator, StatusHash, NewCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	source.Subscribe(ctx, aggregator.Handle)
+
+	if err := waitFor(func() bool {
+		metrics, _ := aggregator.List(types.ListOptions{})
+		return len(metrics) == 3
+	}); err != nil {
+		t.Fatalf("expected the metrics of 3 clusters, %v", err)
+	}
+
+	fleet := aggregator.Aggregate()
+	if fleet.Clusters != 2 || fleet.Nodes != 5 || fleet.ReadyNodes != 5 {
+		t.Errorf("unexpected fleet metrics %v", fleet)
+	}
+	if !reflect.DeepEqual(fleet.StaleClusters, []string{"cluster3"}) {
+		t.Errorf("expected %v, but got %v", []string{"cluster3"}, fleet.StaleClusters)
+	}
+	if cpu := fleet.Capacity[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse("20")) != 0 {
+		t.Errorf("expected 20 cpu, but got %s", cpu.String())
+	}
+	if cpu := fleet.Usage[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse("1")) != 0 {
+		t.Errorf("expected 1 cpu, but got %s", cpu.String())
+	}
+
+	// the older sample is ignored
+	older := NewClusterMetrics("cluster1", testSource, newSample(now.Add(-time.Minute), 1, "1"))
+	if err := aggregator.Handle(types.StatusModified, older); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if metrics, _ := aggregator.Get("cluster1"); metrics.Sample.Nodes != 3 {
+		t.Errorf("expected the latest sample is kept, but got %v", metrics.Sample)
+	}
+
+	aggregator.Forget("cluster3")
+	if fleet := aggregator.Aggregate(); len(fleet.StaleClusters) != 0 {
+		t.Errorf("expected no stale clusters, but got %v", fleet.StaleClusters)
+	}
+}
+
+func waitFor(condition func() bool) error {
+	return wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			return condition(), nil
+		})
+}
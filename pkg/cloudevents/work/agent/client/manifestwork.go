@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubetypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
@@ -15,6 +14,7 @@ import (
 	workv1lister "open-cluster-management.io/api/client/work/listers/work/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/conditions"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/utils"
@@ -125,7 +125,7 @@ func (c *ManifestWorkAgentClient) Patch(ctx context.Context, name string, pt kub
 	// the finalizers of a deleting manifestwork are removed, marking the manifestwork status to deleted and sending
 	// it back to source
 	if !newWork.DeletionTimestamp.IsZero() && len(newWork.Finalizers) == 0 {
-		meta.SetStatusCondition(&newWork.Status.Conditions, metav1.Condition{
+		conditions.Set(&newWork.Status.Conditions, metav1.Condition{
 			Type:    common.ManifestsDeleted,
 			Status:  metav1.ConditionTrue,
 			Reason:  "ManifestsDeleted",
@@ -19,6 +19,7 @@ import (
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
+	workutils "open-cluster-management.io/sdk-go/pkg/cloudevents/work/utils"
 )
 
 var sequenceGenerator *snowflake.Node
@@ -142,7 +143,13 @@ func (c *ManifestCodec) Decode(evt *cloudevents.Event) (*workv1.ManifestWork, er
 			return nil, fmt.Errorf("failed to get deletiontimestamp, %v", err)
 		}
 
+		deleteOption, err := types.GetDeleteOption(*evt)
+		if err != nil {
+			return nil, err
+		}
+
 		work.DeletionTimestamp = &metav1.Time{Time: deletionTimestamp}
+		workutils.SetDeleteOption(work, deleteOption)
 		return work, nil
 	}
 
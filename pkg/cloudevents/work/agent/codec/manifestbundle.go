@@ -15,6 +15,7 @@ import (
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
+	workutils "open-cluster-management.io/sdk-go/pkg/cloudevents/work/utils"
 )
 
 // ManifestBundleCodec is a codec to encode/decode a ManifestWork/cloudevent with ManifestBundle for an agent.
@@ -115,7 +116,13 @@ func (c *ManifestBundleCodec) Decode(evt *cloudevents.Event) (*workv1.ManifestWo
 			return nil, fmt.Errorf("failed to get deletiontimestamp, %v", err)
 		}
 
+		deleteOption, err := types.GetDeleteOption(*evt)
+		if err != nil {
+			return nil, err
+		}
+
 		work.DeletionTimestamp = &metav1.Time{Time: deletionTimestamp}
+		workutils.SetDeleteOption(work, deleteOption)
 		return work, nil
 	}
 
@@ -130,6 +137,7 @@ func (c *ManifestBundleCodec) Decode(evt *cloudevents.Event) (*workv1.ManifestWo
 		},
 		DeleteOption:    manifests.DeleteOption,
 		ManifestConfigs: manifests.ManifestConfigs,
+		Executor:        manifests.Executor,
 	}
 
 	// validate the manifests
@@ -137,5 +145,10 @@ func (c *ManifestBundleCodec) Decode(evt *cloudevents.Event) (*workv1.ManifestWo
 		return nil, fmt.Errorf("manifests are invalid, %v", err)
 	}
 
+	// validate the executor
+	if err := validator.ValidateExecutor(work.Spec.Executor); err != nil {
+		return nil, fmt.Errorf("executor is invalid, %v", err)
+	}
+
 	return work, nil
 }
@@ -202,6 +202,33 @@ func TestManifestBundleDecode(t *testing.T) {
 			}(),
 			expectedErr: true,
 		},
+		{
+			name: "decode a cloudevent with an invalid executor",
+			event: func() *cloudevents.Event {
+				evt := cloudevents.NewEvent()
+				evt.SetSource("source1")
+				evt.SetType("io.open-cluster-management.works.v1alpha1.manifestbundles.spec.test")
+				evt.SetExtension("resourceid", "test")
+				evt.SetExtension("resourceversion", "13")
+				evt.SetExtension("clustername", "cluster1")
+				if err := evt.SetData(cloudevents.ApplicationJSON, &payload.ManifestBundle{
+					Manifests: []workv1.Manifest{
+						{
+							RawExtension: runtime.RawExtension{
+								Raw: toConfigMap(t),
+							},
+						},
+					},
+					Executor: &workv1.ManifestWorkExecutor{
+						Subject: workv1.ManifestWorkExecutorSubject{Type: workv1.ExecutorSubjectTypeServiceAccount},
+					},
+				}); err != nil {
+					t.Fatal(err)
+				}
+				return &evt
+			}(),
+			expectedErr: true,
+		},
 		{
 			name: "decode a cloudevent",
 			event: func() *cloudevents.Event {
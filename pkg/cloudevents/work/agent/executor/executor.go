@@ -0,0 +1,180 @@
+// Package executor provides the helpers for a work agent to apply the manifests of a ManifestWork as its executor, as
+// the native work agent does, the executor ServiceAccount must be allowed to operate the manifests on the managed
+// cluster, and the manifests are applied by impersonating the executor ServiceAccount.
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/rest"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/apis/work/v1/validator"
+)
+
+const (
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+	serviceAccountGroupPrefix    = "system:serviceaccounts:"
+	allServiceAccountsGroup      = "system:serviceaccounts"
+
+	rbacGroup = "rbac.authorization.k8s.io"
+)
+
+// applyVerbs are the verbs that the executor must be allowed to apply a manifest, a manifest may be created, updated,
+// patched or deleted by the work agent, and its status is fed back with get and list.
+var applyVerbs = []string{"get", "list", "create", "update", "patch", "delete"}
+
+// NotAllowedError is returned by the Validator when the executor is not allowed to operate a manifest.
+type NotAllowedError struct {
+	// Executor is the username of the executor.
+	Executor string
+
+	// Resource is the resource of the manifest.
+	Resource schema.GroupVersionResource
+
+	// Namespace and Name are the namespace and name of the manifest, the namespace is empty for a cluster scoped
+	// manifest.
+	Namespace string
+	Name      string
+
+	// Verb is the verb that the executor is not allowed to perform.
+	Verb string
+}
+
+func (e *NotAllowedError) Error() string {
+	return fmt.Sprintf("the executor %s is not allowed to %s the resource %s %s/%s",
+		e.Executor, e.Verb, e.Resource.GroupResource().String(), e.Namespace, e.Name)
+}
+
+// IsNotAllowed returns true if the error is or wraps a NotAllowedError.
+func IsNotAllowed(err error) bool {
+	var notAllowed *NotAllowedError
+	return errors.As(err, &notAllowed)
+}
+
+// Username returns the username of the executor ServiceAccount, e.g. system:serviceaccount:<namespace>:<name>, an
+// empty string is returned for a nil executor.
+func Username(executor *workv1.ManifestWorkExecutor) (string, error) {
+	if executor == nil {
+		return "", nil
+	}
+
+	if err := validator.ValidateExecutor(executor); err != nil {
+		return "", err
+	}
+
+	serviceAccount := executor.Subject.ServiceAccount
+	return serviceAccountUsernamePrefix + serviceAccount.Namespace + ":" + serviceAccount.Name, nil
+}
+
+// Groups returns the groups of the executor ServiceAccount, a nil executor has no groups.
+func Groups(executor *workv1.ManifestWorkExecutor) []string {
+	if executor == nil || executor.Subject.ServiceAccount == nil {
+		return nil
+	}
+
+	return []string{allServiceAccountsGroup, serviceAccountGroupPrefix + executor.Subject.ServiceAccount.Namespace}
+}
+
+// ImpersonateConfig returns a copy of the rest config that impersonates the executor ServiceAccount, so the manifests
+// of a ManifestWork are applied with the permissions of its executor. The config itself is returned for a nil executor,
+// the manifests are applied with the identity of the work agent.
+func ImpersonateConfig(config *rest.Config, executor *workv1.ManifestWorkExecutor) (*rest.Config, error) {
+	if executor == nil {
+		return config, nil
+	}
+
+	username, err := Username(executor)
+	if err != nil {
+		return nil, err
+	}
+
+	impersonated := rest.CopyConfig(config)
+	impersonated.Impersonate = rest.ImpersonationConfig{
+		UserName: username,
+		Groups:   Groups(executor),
+	}
+	return impersonated, nil
+}
+
+// Validator validates whether the executor of a ManifestWork is allowed to apply a manifest with the
+// SubjectAccessReviews on the managed cluster.
+type Validator struct {
+	client authorizationv1client.SubjectAccessReviewsGetter
+}
+
+// NewValidator returns a Validator with the authorization client of the managed cluster.
+func NewValidator(client authorizationv1client.SubjectAccessReviewsGetter) *Validator {
+	return &Validator{client: client}
+}
+
+// Validate validates whether the executor is allowed to apply the manifest of the resource with the namespace and name,
+// a nil executor is always allowed. The executor must be allowed to get, list, create, update, patch and delete the
+// manifest, and to escalate a role or bind a role binding, so the executor cannot grant the permissions that it does
+// not have with the manifests. A NotAllowedError is returned if any verb is not allowed.
+func (v *Validator) Validate(ctx context.Context, executor *workv1.ManifestWorkExecutor,
+	gvr schema.GroupVersionResource, namespace, name string) error {
+	if executor == nil {
+		return nil
+	}
+
+	username, err := Username(executor)
+	if err != nil {
+		return err
+	}
+
+	for _, verb := range verbs(gvr) {
+		sar := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   username,
+				Groups: Groups(executor),
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Group:     gvr.Group,
+					Version:   gvr.Version,
+					Resource:  gvr.Resource,
+					Namespace: namespace,
+					Name:      name,
+					Verb:      verb,
+				},
+			},
+		}
+
+		result, err := v.client.SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to review the access of the executor %s, %v", username, err)
+		}
+
+		if !result.Status.Allowed {
+			return &NotAllowedError{
+				Executor:  username,
+				Resource:  gvr,
+				Namespace: namespace,
+				Name:      name,
+				Verb:      verb,
+			}
+		}
+	}
+
+	return nil
+}
+
+func verbs(gvr schema.GroupVersionResource) []string {
+	if gvr.Group != rbacGroup {
+		return applyVerbs
+	}
+
+	switch gvr.Resource {
+	case "roles", "clusterroles":
+		return append(append([]string{}, applyVerbs...), "escalate")
+	case "rolebindings", "clusterrolebindings":
+		return append(append([]string{}, applyVerbs...), "bind")
+	default:
+		return applyVerbs
+	}
+}
@@ -0,0 +1,158 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/rest"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+type fakeSubjectAccessReviews struct {
+	authorizationv1client.SubjectAccessReviewExpansion
+
+	// denied are the denied verbs
+	denied map[string]bool
+	err    error
+	verbs  []string
+}
+
+func (f *fakeSubjectAccessReviews) SubjectAccessReviews() authorizationv1client.SubjectAccessReviewInterface {
+	return f
+}
+
+func (f *fakeSubjectAccessReviews) Create(_ context.Context, sar *authorizationv1.SubjectAccessReview,
+	_ metav1.CreateOptions) (*authorizationv1.SubjectAccessReview, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	if sar.Spec.User != "system:serviceaccount:default:test" {
+		return nil, fmt.Errorf("unexpected user %s", sar.Spec.User)
+	}
+
+	verb := sar.Spec.ResourceAttributes.Verb
+	f.verbs = append(f.verbs, verb)
+	sar.Status.Allowed = !f.denied[verb]
+	return sar, nil
+}
+
+func newExecutor() *workv1.ManifestWorkExecutor {
+	return &workv1.ManifestWorkExecutor{Subject: workv1.ManifestWorkExecutorSubject{
+		Type:           workv1.ExecutorSubjectTypeServiceAccount,
+		ServiceAccount: &workv1.ManifestWorkSubjectServiceAccount{Namespace: "default", Name: "test"},
+	}}
+}
+
+func TestValidate(t *testing.T) {
+	configmaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	roles := schema.GroupVersionResource{Group: rbacGroup, Version: "v1", Resource: "roles"}
+
+	cases := []struct {
+		name               string
+		executor           *workv1.ManifestWorkExecutor
+		gvr                schema.GroupVersionResource
+		client             *fakeSubjectAccessReviews
+		expectedVerbs      int
+		expectedNotAllowed bool
+		expectedError      bool
+	}{
+		{
+			name:   "no executor",
+			gvr:    configmaps,
+			client: &fakeSubjectAccessReviews{},
+		},
+		{
+			name:          "allowed",
+			executor:      newExecutor(),
+			gvr:           configmaps,
+			client:        &fakeSubjectAccessReviews{},
+			expectedVerbs: 6,
+		},
+		{
+			name:               "not allowed",
+			executor:           newExecutor(),
+			gvr:                configmaps,
+			client:             &fakeSubjectAccessReviews{denied: map[string]bool{"delete": true}},
+			expectedVerbs:      6,
+			expectedNotAllowed: true,
+			expectedError:      true,
+		},
+		{
+			name:               "escalate a role",
+			executor:           newExecutor(),
+			gvr:                roles,
+			client:             &fakeSubjectAccessReviews{denied: map[string]bool{"escalate": true}},
+			expectedVerbs:      7,
+			expectedNotAllowed: true,
+			expectedError:      true,
+		},
+		{
+			name: "invalid executor",
+			executor: &workv1.ManifestWorkExecutor{Subject: workv1.ManifestWorkExecutorSubject{
+				Type: workv1.ExecutorSubjectTypeServiceAccount,
+			}},
+			gvr:           configmaps,
+			client:        &fakeSubjectAccessReviews{},
+			expectedError: true,
+		},
+		{
+			name:          "review failed",
+			executor:      newExecutor(),
+			gvr:           configmaps,
+			client:        &fakeSubjectAccessReviews{err: fmt.Errorf("failed")},
+			expectedError: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := NewValidator(c.client).Validate(context.TODO(), c.executor, c.gvr, "default", "test")
+			if c.expectedError != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedError, err)
+			}
+			if IsNotAllowed(err) != c.expectedNotAllowed {
+				t.Errorf("expected not allowed %v, but got %v", c.expectedNotAllowed, err)
+			}
+			if len(c.client.verbs) != c.expectedVerbs {
+				t.Errorf("expected %d verbs, but got %v", c.expectedVerbs, c.client.verbs)
+			}
+		})
+	}
+}
+
+func TestImpersonateConfig(t *testing.T) {
+	config := &rest.Config{Host: "https://127.0.0.1:6443"}
+
+	actual, err := ImpersonateConfig(config, nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if actual != config {
+		t.Errorf("expected the config is not changed for a nil executor")
+	}
+
+	actual, err = ImpersonateConfig(config, newExecutor())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if actual.Impersonate.UserName != "system:serviceaccount:default:test" {
+		t.Errorf("expected system:serviceaccount:default:test, but got %s", actual.Impersonate.UserName)
+	}
+	if len(actual.Impersonate.Groups) != 2 || actual.Impersonate.Groups[1] != "system:serviceaccounts:default" {
+		t.Errorf("expected the service account groups, but got %v", actual.Impersonate.Groups)
+	}
+	if len(config.Impersonate.UserName) != 0 {
+		t.Errorf("expected the original config is not changed, but got %v", config.Impersonate)
+	}
+
+	if _, err := ImpersonateConfig(config, &workv1.ManifestWorkExecutor{}); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+}
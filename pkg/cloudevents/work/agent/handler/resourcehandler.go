@@ -12,6 +12,8 @@ import (
 	workv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/utils"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/watcher"
 )
 
@@ -52,6 +54,27 @@ func NewManifestWorkAgentHandler(lister workv1lister.ManifestWorkNamespaceLister
 			updatedWork.Finalizers = lastWork.Finalizers
 			updatedWork.Status = lastWork.Status
 
+			watcher.Receive(watch.Event{Type: watch.Modified, Object: updatedWork})
+		case types.Claimed:
+			// the manifestwork is handed over to another source, switch its original source to the claiming source and
+			// apply the spec of the claiming source without recreating it.
+			lastWork, err := lister.Get(work.Name)
+			if err != nil {
+				return err
+			}
+
+			updatedWork := work.DeepCopy()
+
+			// restore the fields that are maintained by local agent
+			updatedWork.Labels = map[string]string{}
+			for key, value := range lastWork.Labels {
+				updatedWork.Labels[key] = value
+			}
+			updatedWork.Labels[common.CloudEventsOriginalSourceLabelKey] = work.Labels[common.CloudEventsOriginalSourceLabelKey]
+			updatedWork.Annotations = lastWork.Annotations
+			updatedWork.Finalizers = lastWork.Finalizers
+			updatedWork.Status = lastWork.Status
+
 			watcher.Receive(watch.Event{Type: watch.Modified, Object: updatedWork})
 		case types.Deleted:
 			// the manifestwork is deleting on the source, we just update its deletion timestamp.
@@ -66,7 +89,16 @@ func NewManifestWorkAgentHandler(lister workv1lister.ManifestWorkNamespaceLister
 
 			updatedWork := lastWork.DeepCopy()
 			updatedWork.DeletionTimestamp = work.DeletionTimestamp
+
+			// the delete option may be changed when the work is deleting on the source
+			deleteOption, err := utils.GetDeleteOption(work)
+			if err != nil {
+				return err
+			}
+			utils.SetDeleteOption(updatedWork, deleteOption)
 			watcher.Receive(watch.Event{Type: watch.Modified, Object: updatedWork})
+		case types.SpecResynced:
+			// the spec of the manifestwork is not changed, do nothing
 		default:
 			return fmt.Errorf("unsupported resource action %s", action)
 		}
@@ -3,9 +3,14 @@ package work
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
@@ -23,6 +28,7 @@ import (
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/internal"
 	sourceclient "open-cluster-management.io/sdk-go/pkg/cloudevents/work/source/client"
 	sourcehandler "open-cluster-management.io/sdk-go/pkg/cloudevents/work/source/handler"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/store"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/watcher"
 )
 
@@ -35,11 +41,18 @@ const defaultInformerResyncTime = 10 * time.Minute
 type ClientHolder struct {
 	workClientSet        workclientset.Interface
 	manifestWorkInformer workv1informers.ManifestWorkInformer
+	eventDataTypes       func() []types.CloudEventsDataType
+	drain                func(ctx context.Context) error
+	close                func(ctx context.Context) error
+	switchTransport      func(ctx context.Context, config any) error
 }
 
 var _ workv1client.ManifestWorksGetter = &ClientHolder{}
 
-// WorkInterface returns a workclientset Interface
+// WorkInterface returns a workclientset Interface, with the cloudevents clients, its WorkV1().ManifestWorks(namespace)
+// gets, lists and watches the manifestworks from the ManifestWorkInformer cache and creates, updates, patches and
+// deletes the manifestworks with the cloudevents client, so the code that uses the kube work clientset can switch to
+// the cloudevents clients by replacing the clientset.
 func (h *ClientHolder) WorkInterface() workclientset.Interface {
 	return h.workClientSet
 }
@@ -54,15 +67,68 @@ func (h *ClientHolder) ManifestWorkInformer() workv1informers.ManifestWorkInform
 	return h.manifestWorkInformer
 }
 
+// EventDataTypes returns the cloudevents data types that are registered with the codecs of the cloudevents client, it
+// returns nil if the ManifestWork client is built with kubeconfig.
+func (h *ClientHolder) EventDataTypes() []types.CloudEventsDataType {
+	if h.eventDataTypes == nil {
+		return nil
+	}
+
+	return h.eventDataTypes()
+}
+
+// Drain stops the cloudevents client accepting new publishes and received events, and waits for the in-flight
+// publishes and the handlers of the received events to finish. It does nothing if the ManifestWork client is built
+// with kubeconfig.
+func (h *ClientHolder) Drain(ctx context.Context) error {
+	if h.drain == nil {
+		return nil
+	}
+
+	return h.drain(ctx)
+}
+
+// Close drains the cloudevents client and closes its subscriptions to the broker. It does nothing if the ManifestWork
+// client is built with kubeconfig.
+func (h *ClientHolder) Close(ctx context.Context) error {
+	if h.close == nil {
+		return nil
+	}
+
+	return h.close(ctx)
+}
+
+// SwitchTransport switches the cloudevents client of a work agent to another broker at runtime with the given
+// configuration (*mqtt.MQTTOptions or *grpc.GRPCOptions), the manifestworks are resynced with the sources on the new
+// broker after the client is reconnected, so the agents can migrate brokers without being restarted. It returns an
+// error if the ManifestWork client is not built for a work agent with cloudevents.
+func (h *ClientHolder) SwitchTransport(ctx context.Context, config any) error {
+	if h.switchTransport == nil {
+		return fmt.Errorf("the transport can only be switched on a work agent with cloudevents")
+	}
+
+	return h.switchTransport(ctx, config)
+}
+
 // ClientHolderBuilder builds the ClientHolder with different configuration.
 type ClientHolderBuilder struct {
 	config             any
 	codecs             []generic.Codec[*workv1.ManifestWork]
 	informerOptions    []workinformers.SharedInformerOption
 	informerResyncTime time.Duration
+	informerTransform  cache.TransformFunc
+	informerIndexers   cache.Indexers
+	informerNamespaces sets.Set[string]
+	informerSelector   string
+	informerStore      store.Backend
+	hotCacheSize       int
 	sourceID           string
 	clusterName        string
 	clientID           string
+	clientIDSuffix     string
+	statusHashGetter   generic.StatusHashGetter[*workv1.ManifestWork]
+	validators         []sourceclient.Validator
+	jitter             options.Jitter
 }
 
 // NewClientHolderBuilder returns a ClientHolderBuilder with a given configuration.
@@ -77,6 +143,7 @@ func NewClientHolderBuilder(config any) *ClientHolderBuilder {
 	return &ClientHolderBuilder{
 		config:             config,
 		informerResyncTime: defaultInformerResyncTime,
+		statusHashGetter:   ManifestWorkStatusHash,
 	}
 }
 
@@ -86,6 +153,14 @@ func (b *ClientHolderBuilder) WithClientID(clientID string) *ClientHolderBuilder
 	return b
 }
 
+// WithClientIDSuffixFromPod appends the name of the current pod to the MQTT client ID, so the replicas of a source or
+// an agent that are built with the same client ID do not take over the MQTT sessions of each other. The pod name is
+// read from the POD_NAME environment variable (e.g. set with the downward API), or the host name is used.
+func (b *ClientHolderBuilder) WithClientIDSuffixFromPod() *ClientHolderBuilder {
+	b.clientIDSuffix = podName()
+	return b
+}
+
 // WithSourceID set the source ID when building a manifestwork client for a source.
 func (b *ClientHolderBuilder) WithSourceID(sourceID string) *ClientHolderBuilder {
 	b.sourceID = sourceID
@@ -104,6 +179,32 @@ func (b *ClientHolderBuilder) WithCodecs(codecs ...generic.Codec[*workv1.Manifes
 	return b
 }
 
+// WithStatusHashGetter set the status hash getter to compare the manifestwork status on the resync, by default, the
+// ManifestWorkStatusHash is used. The source and the agent must use the same status hash getter, e.g. the
+// ManifestWorkStructuredStatusHash, it can be wrapped with the generic.NewCachedStatusHashGetter to cache the hashes.
+func (b *ClientHolderBuilder) WithStatusHashGetter(
+	statusHashGetter generic.StatusHashGetter[*workv1.ManifestWork]) *ClientHolderBuilder {
+	b.statusHashGetter = statusHashGetter
+	return b
+}
+
+// WithValidators set the validators that validate a manifestwork in order before its spec is published by a source,
+// e.g. the sourceclient.ManifestsSizeLimit, sourceclient.ForbiddenKinds and sourceclient.RequiredLabels, a rejected
+// manifestwork is not published and a sourceclient.RejectedError is returned. The validators are not used with the
+// kubeconfig.
+func (b *ClientHolderBuilder) WithValidators(validators ...sourceclient.Validator) *ClientHolderBuilder {
+	b.validators = validators
+	return b
+}
+
+// WithJitter set the random delays of the reconnects and the resyncs of the source/agent cloudevents client, so the
+// many agents that are reconnected to a restarted broker at the same time do not resync at the same time. The jitter is
+// not used with the kubeconfig.
+func (b *ClientHolderBuilder) WithJitter(jitter options.Jitter) *ClientHolderBuilder {
+	b.jitter = jitter
+	return b
+}
+
 // WithInformerConfig set the ManifestWorkInformer configs. If the resync time is not set, the default time (10 minutes)
 // will be used when building the ManifestWorkInformer.
 func (b *ClientHolderBuilder) WithInformerConfig(
@@ -113,13 +214,65 @@ func (b *ClientHolderBuilder) WithInformerConfig(
 	return b
 }
 
+// WithInformerResyncPeriod set the resync period of the ManifestWorkInformer. If the period is not set, the default
+// time (10 minutes) will be used.
+func (b *ClientHolderBuilder) WithInformerResyncPeriod(resyncPeriod time.Duration) *ClientHolderBuilder {
+	b.informerResyncTime = resyncPeriod
+	return b
+}
+
+// WithInformerTransform set a transform function for the ManifestWorkInformer, the function will be called on each
+// object before it is stored in the informer local cache, e.g. using StripManagedFields to reduce the memory usage of
+// the cache.
+func (b *ClientHolderBuilder) WithInformerTransform(transform cache.TransformFunc) *ClientHolderBuilder {
+	b.informerTransform = transform
+	return b
+}
+
+// WithInformerIndexers add custom indexers to the ManifestWorkInformer.
+func (b *ClientHolderBuilder) WithInformerIndexers(indexers cache.Indexers) *ClientHolderBuilder {
+	if b.informerIndexers == nil {
+		b.informerIndexers = cache.Indexers{}
+	}
+
+	for name, indexFunc := range indexers {
+		b.informerIndexers[name] = indexFunc
+	}
+	return b
+}
+
+// WithInformerNamespaces restricts the ManifestWorkInformer to only cache the ManifestWorks in the given namespaces
+// (clusters). For the kubeconfig, the ManifestWorks in multiple namespaces are watched in all namespaces and the
+// ManifestWorks in the other namespaces are filtered out before they are cached.
+func (b *ClientHolderBuilder) WithInformerNamespaces(namespaces ...string) *ClientHolderBuilder {
+	b.informerNamespaces = sets.New[string](namespaces...)
+	return b
+}
+
+// WithInformerLabelSelector restricts the ManifestWorkInformer to only cache the ManifestWorks that match the given
+// label selector.
+func (b *ClientHolderBuilder) WithInformerLabelSelector(selector string) *ClientHolderBuilder {
+	b.informerSelector = selector
+	return b
+}
+
+// WithInformerStore saves the ManifestWorkInformer local cache in the given backend instead of the memory, only the
+// recently used works (at most hotCacheSize) are kept in memory. This reduces the memory usage of the informer when
+// there are a large number of ManifestWorks, e.g. with a store.BoltBackend or a store.SQLiteBackend. The existing works in the backend, e.g. the
+// works that were saved by a previous run, are purged when the informer is built.
+func (b *ClientHolderBuilder) WithInformerStore(backend store.Backend, hotCacheSize int) *ClientHolderBuilder {
+	b.informerStore = backend
+	b.hotCacheSize = hotCacheSize
+	return b
+}
+
 // NewSourceClientHolder returns a ClientHolder for source
 func (b *ClientHolderBuilder) NewSourceClientHolder(ctx context.Context) (*ClientHolder, error) {
 	switch config := b.config.(type) {
 	case *rest.Config:
 		return b.newKubeClients(config)
 	case *mqtt.MQTTOptions:
-		return b.newSourceClients(ctx, mqtt.NewSourceOptions(config, b.clientID, b.sourceID))
+		return b.newSourceClients(ctx, mqtt.NewSourceOptions(b.mqttOptions(config), b.clientID, b.sourceID))
 	case *grpc.GRPCOptions:
 		return b.newSourceClients(ctx, grpc.NewSourceOptions(config, b.sourceID))
 	default:
@@ -129,18 +282,41 @@ func (b *ClientHolderBuilder) NewSourceClientHolder(ctx context.Context) (*Clien
 
 // NewAgentClientHolder returns a ClientHolder for agent
 func (b *ClientHolderBuilder) NewAgentClientHolder(ctx context.Context) (*ClientHolder, error) {
-	switch config := b.config.(type) {
-	case *rest.Config:
+	if config, ok := b.config.(*rest.Config); ok {
 		return b.newKubeClients(config)
+	}
+
+	agentOptions, err := b.newAgentOptions(b.config)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.newAgentClients(ctx, agentOptions)
+}
+
+// newAgentOptions builds the cloudevents agent options of the agent with the given configuration.
+func (b *ClientHolderBuilder) newAgentOptions(config any) (*options.CloudEventsAgentOptions, error) {
+	switch config := config.(type) {
 	case *mqtt.MQTTOptions:
-		return b.newAgentClients(ctx, mqtt.NewAgentOptions(config, b.clusterName, b.clientID))
+		return mqtt.NewAgentOptions(b.mqttOptions(config), b.clusterName, b.clientID), nil
 	case *grpc.GRPCOptions:
-		return b.newAgentClients(ctx, grpc.NewAgentOptions(config, b.clusterName, b.clientID))
+		return grpc.NewAgentOptions(config, b.clusterName, b.clientID), nil
 	default:
 		return nil, fmt.Errorf("unsupported client configuration type %T", config)
 	}
 }
 
+// mqttOptions returns the MQTT options with the client ID suffix of the builder.
+func (b *ClientHolderBuilder) mqttOptions(config *mqtt.MQTTOptions) *mqtt.MQTTOptions {
+	if len(b.clientIDSuffix) == 0 {
+		return config
+	}
+
+	mqttOptions := *config
+	mqttOptions.ClientIDSuffix = b.clientIDSuffix
+	return &mqttOptions
+}
+
 func (b *ClientHolderBuilder) newAgentClients(ctx context.Context, agentOptions *options.CloudEventsAgentOptions) (*ClientHolder, error) {
 	if len(b.clientID) == 0 {
 		return nil, fmt.Errorf("client id is required")
@@ -150,13 +326,21 @@ func (b *ClientHolderBuilder) newAgentClients(ctx context.Context, agentOptions
 		return nil, fmt.Errorf("cluster name is required")
 	}
 
+	agentOptions.Jitter = b.jitter
+
+	filter, err := b.newInformerFilter()
+	if err != nil {
+		return nil, err
+	}
+
 	workLister := &ManifestWorkLister{}
 	watcher := watcher.NewManifestWorkWatcher()
+	watcher.SetFilter(filter)
 	cloudEventsClient, err := generic.NewCloudEventAgentClient[*workv1.ManifestWork](
 		ctx,
 		agentOptions,
 		workLister,
-		ManifestWorkStatusHash,
+		b.statusHashGetter,
 		b.codecs...,
 	)
 	if err != nil {
@@ -166,8 +350,10 @@ func (b *ClientHolderBuilder) newAgentClients(ctx context.Context, agentOptions
 	manifestWorkClient := agentclient.NewManifestWorkAgentClient(cloudEventsClient, watcher)
 	workClient := &internal.WorkV1ClientWrapper{ManifestWorkClient: manifestWorkClient}
 	workClientSet := &internal.WorkClientSetWrapper{WorkV1ClientWrapper: workClient}
-	factory := workinformers.NewSharedInformerFactoryWithOptions(workClientSet, b.informerResyncTime, b.informerOptions...)
-	informers := factory.Work().V1().ManifestWorks()
+	informers, err := b.newManifestWorkInformer(workClientSet, metav1.NamespaceAll, nil)
+	if err != nil {
+		return nil, err
+	}
 	manifestWorkLister := informers.Lister()
 	namespacedLister := manifestWorkLister.ManifestWorks(b.clusterName)
 
@@ -197,6 +383,17 @@ func (b *ClientHolderBuilder) newAgentClients(ctx context.Context, agentOptions
 	return &ClientHolder{
 		workClientSet:        workClientSet,
 		manifestWorkInformer: informers,
+		eventDataTypes:       cloudEventsClient.EventDataTypes,
+		drain:                cloudEventsClient.Drain,
+		close:                cloudEventsClient.Close,
+		switchTransport: func(ctx context.Context, config any) error {
+			agentOptions, err := b.newAgentOptions(config)
+			if err != nil {
+				return err
+			}
+
+			return cloudEventsClient.SwitchTransport(ctx, agentOptions)
+		},
 	}, nil
 }
 
@@ -209,13 +406,21 @@ func (b *ClientHolderBuilder) newSourceClients(ctx context.Context, sourceOption
 		return nil, fmt.Errorf("source id is required")
 	}
 
+	sourceOptions.Jitter = b.jitter
+
+	filter, err := b.newInformerFilter()
+	if err != nil {
+		return nil, err
+	}
+
 	workLister := &ManifestWorkLister{}
 	watcher := watcher.NewManifestWorkWatcher()
+	watcher.SetFilter(filter)
 	cloudEventsClient, err := generic.NewCloudEventSourceClient[*workv1.ManifestWork](
 		ctx,
 		sourceOptions,
 		workLister,
-		ManifestWorkStatusHash,
+		b.statusHashGetter,
 		b.codecs...,
 	)
 	if err != nil {
@@ -223,10 +428,13 @@ func (b *ClientHolderBuilder) newSourceClients(ctx context.Context, sourceOption
 	}
 
 	manifestWorkClient := sourceclient.NewManifestWorkSourceClient(b.sourceID, cloudEventsClient, watcher)
+	manifestWorkClient.SetValidators(b.validators...)
 	workClient := &internal.WorkV1ClientWrapper{ManifestWorkClient: manifestWorkClient}
 	workClientSet := &internal.WorkClientSetWrapper{WorkV1ClientWrapper: workClient}
-	factory := workinformers.NewSharedInformerFactoryWithOptions(workClientSet, b.informerResyncTime, b.informerOptions...)
-	informers := factory.Work().V1().ManifestWorks()
+	informers, err := b.newManifestWorkInformer(workClientSet, metav1.NamespaceAll, nil)
+	if err != nil {
+		return nil, err
+	}
 	manifestWorkLister := informers.Lister()
 	// Set informer lister back to work lister and client.
 	workLister.Lister = manifestWorkLister
@@ -253,6 +461,9 @@ func (b *ClientHolderBuilder) newSourceClients(ctx context.Context, sourceOption
 	return &ClientHolder{
 		workClientSet:        workClientSet,
 		manifestWorkInformer: informers,
+		eventDataTypes:       cloudEventsClient.EventDataTypes,
+		drain:                cloudEventsClient.Drain,
+		close:                cloudEventsClient.Close,
 	}, nil
 }
 
@@ -262,9 +473,126 @@ func (b *ClientHolderBuilder) newKubeClients(config *rest.Config) (*ClientHolder
 		return nil, err
 	}
 
-	factory := workinformers.NewSharedInformerFactoryWithOptions(kubeWorkClientSet, b.informerResyncTime, b.informerOptions...)
+	if _, err := labels.Parse(b.informerSelector); err != nil {
+		return nil, err
+	}
+
+	// the works in one namespace are listed and watched in the namespace, the works in multiple namespaces are listed
+	// and watched in all namespaces and the works in the other namespaces are filtered out
+	namespace := metav1.NamespaceAll
+	var informerWorkClientSet workclientset.Interface = kubeWorkClientSet
+	switch {
+	case b.informerNamespaces.Len() == 1:
+		namespace = b.informerNamespaces.UnsortedList()[0]
+	case b.informerNamespaces.Len() > 1:
+		informerWorkClientSet = newNamespacesWorkClientSet(kubeWorkClientSet, b.informerNamespaces)
+	}
+
+	var tweakListOptions func(*metav1.ListOptions)
+	if len(b.informerSelector) != 0 {
+		tweakListOptions = func(opts *metav1.ListOptions) {
+			opts.LabelSelector = b.informerSelector
+		}
+	}
+
+	informers, err := b.newManifestWorkInformer(informerWorkClientSet, namespace, tweakListOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ClientHolder{
 		workClientSet:        kubeWorkClientSet,
-		manifestWorkInformer: factory.Work().V1().ManifestWorks(),
+		manifestWorkInformer: informers,
+	}, nil
+}
+
+func (b *ClientHolderBuilder) newManifestWorkInformer(workClientSet workclientset.Interface,
+	namespace string, tweakListOptions func(*metav1.ListOptions)) (workv1informers.ManifestWorkInformer, error) {
+	var informers workv1informers.ManifestWorkInformer
+	if b.informerStore != nil {
+		storeInformers, err := store.NewManifestWorkInformer(
+			workClientSet, b.informerStore, b.hotCacheSize, namespace, b.informerResyncTime, tweakListOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		if b.informerTransform != nil {
+			if err := storeInformers.Informer().SetTransform(b.informerTransform); err != nil {
+				return nil, err
+			}
+		}
+
+		informers = storeInformers
+	} else {
+		informerOptions := append([]workinformers.SharedInformerOption{}, b.informerOptions...)
+		if namespace != metav1.NamespaceAll {
+			informerOptions = append(informerOptions, workinformers.WithNamespace(namespace))
+		}
+
+		if tweakListOptions != nil {
+			informerOptions = append(informerOptions, workinformers.WithTweakListOptions(tweakListOptions))
+		}
+
+		if b.informerTransform != nil {
+			informerOptions = append(informerOptions, workinformers.WithTransform(b.informerTransform))
+		}
+
+		factory := workinformers.NewSharedInformerFactoryWithOptions(workClientSet, b.informerResyncTime, informerOptions...)
+		informers = factory.Work().V1().ManifestWorks()
+	}
+
+	if len(b.informerIndexers) != 0 {
+		if err := informers.Informer().AddIndexers(b.informerIndexers); err != nil {
+			return nil, err
+		}
+	}
+
+	return informers, nil
+}
+
+// newInformerFilter returns a filter for the ManifestWorkWatcher with the informer namespaces and label selector, the
+// works that are not accepted by the filter will not be cached by the ManifestWorkInformer.
+func (b *ClientHolderBuilder) newInformerFilter() (func(obj metav1.Object) bool, error) {
+	selector, err := labels.Parse(b.informerSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.informerNamespaces.Len() == 0 && selector.Empty() {
+		return nil, nil
+	}
+
+	namespaces := b.informerNamespaces.Clone()
+	return func(obj metav1.Object) bool {
+		if namespaces.Len() != 0 && !namespaces.Has(obj.GetNamespace()) {
+			return false
+		}
+
+		return selector.Matches(labels.Set(obj.GetLabels()))
 	}, nil
 }
+
+// StripManagedFields is an informer transform function that removes the managed fields from the ManifestWorks before
+// they are stored in the informer local cache.
+func StripManagedFields(obj interface{}) (interface{}, error) {
+	if work, ok := obj.(*workv1.ManifestWork); ok {
+		work.ManagedFields = nil
+	}
+
+	return obj, nil
+}
+
+// podName returns the name of the current pod, the host name of a pod is its name if the POD_NAME environment variable
+// is not set.
+func podName() string {
+	if name := os.Getenv("POD_NAME"); len(name) != 0 {
+		return name
+	}
+
+	name, err := os.Hostname()
+	if err != nil {
+		klog.Warningf("failed to get the host name, %v", err)
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,122 @@
+package work
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+)
+
+func TestInformerConfig(t *testing.T) {
+	holder, err := NewClientHolderBuilder(&rest.Config{Host: "https://localhost:6443"}).
+		WithInformerResyncPeriod(time.Minute).
+		WithInformerTransform(StripManagedFields).
+		WithInformerIndexers(cache.Indexers{
+			"byCluster": func(obj interface{}) ([]string, error) {
+				return []string{obj.(*workv1.ManifestWork).Namespace}, nil
+			},
+		}).
+		NewSourceClientHolder(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, ok := holder.ManifestWorkInformer().Informer().GetIndexer().GetIndexers()["byCluster"]; !ok {
+		t.Errorf("expected the byCluster indexer is added")
+	}
+}
+
+func TestClientIDSuffixFromPod(t *testing.T) {
+	t.Setenv("POD_NAME", "pod1")
+
+	config := &mqtt.MQTTOptions{BrokerHost: "test"}
+	mqttOptions := NewClientHolderBuilder(config).WithClientIDSuffixFromPod().mqttOptions(config)
+	if mqttOptions.ClientIDSuffix != "pod1" {
+		t.Errorf("expected pod1, but got %s", mqttOptions.ClientIDSuffix)
+	}
+
+	// the given options are not changed
+	if len(config.ClientIDSuffix) != 0 {
+		t.Errorf("expected no suffix, but got %s", config.ClientIDSuffix)
+	}
+}
+
+func TestStripManagedFields(t *testing.T) {
+	work := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "test",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "test"}},
+		},
+	}
+
+	obj, err := StripManagedFields(work)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(obj.(*workv1.ManifestWork).ManagedFields) != 0 {
+		t.Errorf("expected the managed fields are removed, but got %v", obj)
+	}
+}
+
+func TestInformerFilter(t *testing.T) {
+	cases := []struct {
+		name       string
+		namespaces []string
+		selector   string
+		work       *workv1.ManifestWork
+		expected   bool
+	}{
+		{
+			name:     "no filter",
+			work:     &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1"}},
+			expected: true,
+		},
+		{
+			name:       "namespace is not matched",
+			namespaces: []string{"cluster2", "cluster3"},
+			work:       &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1"}},
+			expected:   false,
+		},
+		{
+			name:       "label is not matched",
+			namespaces: []string{"cluster1"},
+			selector:   "app=test",
+			work:       &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1"}},
+			expected:   false,
+		},
+		{
+			name:       "matched",
+			namespaces: []string{"cluster1"},
+			selector:   "app=test",
+			work: &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{
+				Namespace: "cluster1",
+				Labels:    map[string]string{"app": "test"},
+			}},
+			expected: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			filter, err := NewClientHolderBuilder(nil).
+				WithInformerNamespaces(c.namespaces...).
+				WithInformerLabelSelector(c.selector).
+				newInformerFilter()
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			matched := filter == nil || filter(c.work)
+			if matched != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, matched)
+			}
+		})
+	}
+}
@@ -12,6 +12,14 @@ const (
 
 	// CloudEventsGenerationAnnotationKey is the key of the manifestwork generation annotation.
 	CloudEventsGenerationAnnotationKey = "cloudevents.open-cluster-management.io/generation"
+
+	// CloudEventsDeleteTTLSecondsAnnotationKey is the key of the manifestwork delete TTL seconds annotation, the agent
+	// waits the TTL seconds before cleaning up the manifests of a deleting manifestwork.
+	CloudEventsDeleteTTLSecondsAnnotationKey = "cloudevents.open-cluster-management.io/delete-ttl-seconds"
+
+	// CloudEventsSpecTTLSecondsAnnotationKey is the key of the manifestwork spec TTL seconds annotation, the spec event
+	// of a manifestwork expires after the TTL seconds, the agent discards the expired spec events.
+	CloudEventsSpecTTLSecondsAnnotationKey = "cloudevents.open-cluster-management.io/spec-ttl-seconds"
 )
 
 // CloudEventsOriginalSourceLabelKey is the key of the cloudevents original source label.
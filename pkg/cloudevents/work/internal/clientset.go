@@ -39,8 +39,7 @@ var _ workv1client.WorkV1Interface = &WorkV1ClientWrapper{}
 
 func (c *WorkV1ClientWrapper) ManifestWorks(namespace string) workv1client.ManifestWorkInterface {
 	if sourceManifestWorkClient, ok := c.ManifestWorkClient.(*sourceclient.ManifestWorkSourceClient); ok {
-		sourceManifestWorkClient.SetNamespace(namespace)
-		return sourceManifestWorkClient
+		return sourceManifestWorkClient.Namespace(namespace)
 	}
 	return c.ManifestWorkClient
 }
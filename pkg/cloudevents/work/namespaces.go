@@ -0,0 +1,82 @@
+package work
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// namespacesWorkClientSet wraps a work clientset, the ManifestWorks that are listed and watched with it only include
+// the ManifestWorks in the given namespaces, so a ManifestWorkInformer that is built with it only caches the
+// ManifestWorks in these namespaces.
+type namespacesWorkClientSet struct {
+	workclientset.Interface
+	namespaces sets.Set[string]
+}
+
+func newNamespacesWorkClientSet(client workclientset.Interface, namespaces sets.Set[string]) *namespacesWorkClientSet {
+	return &namespacesWorkClientSet{Interface: client, namespaces: namespaces.Clone()}
+}
+
+func (c *namespacesWorkClientSet) WorkV1() workv1client.WorkV1Interface {
+	return &namespacesWorkV1Client{WorkV1Interface: c.Interface.WorkV1(), namespaces: c.namespaces}
+}
+
+type namespacesWorkV1Client struct {
+	workv1client.WorkV1Interface
+	namespaces sets.Set[string]
+}
+
+func (c *namespacesWorkV1Client) ManifestWorks(namespace string) workv1client.ManifestWorkInterface {
+	return &namespacesManifestWorkClient{
+		ManifestWorkInterface: c.WorkV1Interface.ManifestWorks(namespace),
+		namespaces:            c.namespaces,
+	}
+}
+
+type namespacesManifestWorkClient struct {
+	workv1client.ManifestWorkInterface
+	namespaces sets.Set[string]
+}
+
+func (c *namespacesManifestWorkClient) List(ctx context.Context, opts metav1.ListOptions) (*workv1.ManifestWorkList, error) {
+	works, err := c.ManifestWorkInterface.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []workv1.ManifestWork{}
+	for _, work := range works.Items {
+		if c.namespaces.Has(work.Namespace) {
+			items = append(items, work)
+		}
+	}
+	works.Items = items
+	return works, nil
+}
+
+func (c *namespacesManifestWorkClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	w, err := c.ManifestWorkInterface.Watch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return watch.Filter(w, func(evt watch.Event) (watch.Event, bool) {
+		if evt.Type == watch.Error || evt.Type == watch.Bookmark {
+			return evt, true
+		}
+
+		obj, err := meta.Accessor(evt.Object)
+		if err != nil {
+			return evt, true
+		}
+		return evt, c.namespaces.Has(obj.GetNamespace())
+	}), nil
+}
@@ -0,0 +1,52 @@
+package work
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestNamespacesWorkClientSet(t *testing.T) {
+	newWork := func(namespace, name string) *workv1.ManifestWork {
+		return &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+
+	kubeWorkClient := fakeworkclient.NewSimpleClientset(newWork("cluster1", "work1"), newWork("cluster3", "work1"))
+	workClient := newNamespacesWorkClientSet(kubeWorkClient, sets.New[string]("cluster1", "cluster2"))
+
+	works, err := workClient.WorkV1().ManifestWorks(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(works.Items) != 1 || works.Items[0].Namespace != "cluster1" {
+		t.Errorf("expected the works in cluster1, but got %v", works.Items)
+	}
+
+	w, err := workClient.WorkV1().ManifestWorks(metav1.NamespaceAll).Watch(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer w.Stop()
+
+	for _, work := range []*workv1.ManifestWork{newWork("cluster3", "work2"), newWork("cluster2", "work2")} {
+		if _, err := kubeWorkClient.WorkV1().ManifestWorks(work.Namespace).Create(
+			context.TODO(), work, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	select {
+	case evt := <-w.ResultChan():
+		if work := evt.Object.(*workv1.ManifestWork); work.Namespace != "cluster2" {
+			t.Errorf("expected the work in cluster2, but got %s/%s", work.Namespace, work.Name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected the work in cluster2 is watched")
+	}
+}
@@ -0,0 +1,92 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// the max capacity of the buffers that are put back to the pool, the larger buffers are dropped, so a few large
+// bundles do not hold the memory
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// EncodeManifestBundle returns the JSON encoding of a ManifestBundle. It is equivalent to the json.Marshal except that
+// the raw manifests are written to a pooled buffer as they are, the json.Marshal validates and compacts the raw
+// manifests again after they are marshaled, which doubles the encoding cost of a bundle. The raw manifests must be valid
+// JSON, they are validated when the ManifestWork is created.
+func EncodeManifestBundle(bundle *ManifestBundle) ([]byte, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	buf.WriteString(`{"manifests":`)
+	if bundle.Manifests == nil {
+		buf.WriteString("null")
+	} else {
+		buf.WriteByte('[')
+		for i, manifest := range bundle.Manifests {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			raw, err := manifest.MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+			if len(raw) == 0 {
+				return nil, fmt.Errorf("the manifest %d is empty", i)
+			}
+			buf.Write(raw)
+		}
+		buf.WriteByte(']')
+	}
+
+	if bundle.DeleteOption != nil {
+		buf.WriteString(`,"deleteOption":`)
+		if err := encodeJSON(buf, bundle.DeleteOption); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(bundle.ManifestConfigs) != 0 {
+		buf.WriteString(`,"manifestConfigs":`)
+		if err := encodeJSON(buf, bundle.ManifestConfigs); err != nil {
+			return nil, err
+		}
+	}
+
+	if bundle.Executor != nil {
+		buf.WriteString(`,"executor":`)
+		if err := encodeJSON(buf, bundle.Executor); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.WriteByte('}')
+
+	// the buffer is reused, copy the encoded data
+	data := make([]byte, buf.Len())
+	copy(data, buf.Bytes())
+	return data, nil
+}
+
+func encodeJSON(buf *bytes.Buffer, obj any) error {
+	if err := json.NewEncoder(buf).Encode(obj); err != nil {
+		return err
+	}
+
+	// remove the newline that is appended by the encoder
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
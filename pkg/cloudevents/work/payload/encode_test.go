@@ -0,0 +1,128 @@
+package payload
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestEncodeManifestBundle(t *testing.T) {
+	cases := []struct {
+		name   string
+		bundle *ManifestBundle
+	}{
+		{
+			name:   "no manifests",
+			bundle: &ManifestBundle{},
+		},
+		{
+			name: "empty manifests",
+			bundle: &ManifestBundle{
+				Manifests: []workv1.Manifest{},
+			},
+		},
+		{
+			name: "raw manifests",
+			bundle: &ManifestBundle{
+				Manifests: []workv1.Manifest{
+					{RawExtension: runtime.RawExtension{Raw: []byte(`{"kind":"ConfigMap","data":{"a":"<b>"}}`)}},
+					{RawExtension: runtime.RawExtension{Raw: []byte(`{"kind":"Secret"}`)}},
+				},
+			},
+		},
+		{
+			name: "object manifests with options",
+			bundle: &ManifestBundle{
+				Manifests: []workv1.Manifest{
+					{RawExtension: runtime.RawExtension{Object: &runtime.Unknown{Raw: []byte(`{"kind":"ConfigMap"}`)}}},
+				},
+				DeleteOption: &workv1.DeleteOption{PropagationPolicy: workv1.DeletePropagationPolicyTypeOrphan},
+				ManifestConfigs: []workv1.ManifestConfigOption{
+					{
+						ResourceIdentifier: workv1.ResourceIdentifier{Resource: "configmaps", Name: "test"},
+						UpdateStrategy: &workv1.UpdateStrategy{
+							Type:            workv1.UpdateStrategyTypeServerSideApply,
+							ServerSideApply: &workv1.ServerSideApplyConfig{FieldManager: "test", Force: true},
+						},
+						FeedbackRules: []workv1.FeedbackRule{{Type: workv1.WellKnownStatusType}},
+					},
+				},
+			},
+		},
+		{
+			name: "manifests with executor",
+			bundle: &ManifestBundle{
+				Manifests: []workv1.Manifest{
+					{RawExtension: runtime.RawExtension{Raw: []byte(`{"kind":"ConfigMap"}`)}},
+				},
+				Executor: &workv1.ManifestWorkExecutor{
+					Subject: workv1.ManifestWorkExecutorSubject{
+						Type: workv1.ExecutorSubjectTypeServiceAccount,
+						ServiceAccount: &workv1.ManifestWorkSubjectServiceAccount{
+							Namespace: "default",
+							Name:      "test",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expected, err := json.Marshal(c.bundle)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := EncodeManifestBundle(c.bundle)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			var expectedObj, actualObj any
+			if err := json.Unmarshal(expected, &expectedObj); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(actual, &actualObj); err != nil {
+				t.Fatalf("failed to unmarshal %s, %v", string(actual), err)
+			}
+
+			if !reflect.DeepEqual(expectedObj, actualObj) {
+				t.Errorf("expected %s, but got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func BenchmarkEncodeManifestBundle(b *testing.B) {
+	bundle := &ManifestBundle{}
+	for i := 0; i < 10; i++ {
+		bundle.Manifests = append(bundle.Manifests, workv1.Manifest{RawExtension: runtime.RawExtension{
+			Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test","namespace":"default"},` +
+				`"data":{"key1":"value1","key2":"value2","key3":"value3"}}`),
+		}})
+	}
+
+	b.Run("json.Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(bundle); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("EncodeManifestBundle", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := EncodeManifestBundle(bundle); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
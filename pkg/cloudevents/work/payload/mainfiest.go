@@ -5,6 +5,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/conditions"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
@@ -43,6 +44,21 @@ type ManifestStatus struct {
 	Status *workv1.ManifestCondition `json:"status,omitempty"`
 }
 
+// SetCondition sets a condition of the SingleManifest status, see conditions.Set.
+func (s *ManifestStatus) SetCondition(condition metav1.Condition) bool {
+	return conditions.Set(&s.Conditions, condition)
+}
+
+// GetCondition returns the condition of the given type, or nil if it is not found.
+func (s *ManifestStatus) GetCondition(conditionType string) *metav1.Condition {
+	return conditions.Get(s.Conditions, conditionType)
+}
+
+// IsConditionTrue returns true if the condition of the given type is true.
+func (s *ManifestStatus) IsConditionTrue(conditionType string) bool {
+	return conditions.IsTrue(s.Conditions, conditionType)
+}
+
 type ManifestConfigOption struct {
 	// FeedbackRules defines what resource status field should be returned.
 	// If it is not set or empty, no feedback rules will be honored.
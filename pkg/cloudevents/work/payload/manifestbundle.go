@@ -4,6 +4,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/conditions"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 )
 
@@ -23,6 +24,9 @@ type ManifestBundle struct {
 
 	// ManifestConfigs represents the configurations of manifests.
 	ManifestConfigs []workv1.ManifestConfigOption `json:"manifestConfigs,omitempty"`
+
+	// Executor is the configuration that makes the work agent to perform some pre-request processing/checking.
+	Executor *workv1.ManifestWorkExecutor `json:"executor,omitempty"`
 }
 
 // ManifestBundleStatus represents the data in a cloudevent, it contains the status of a ManifestBundle on a managed
@@ -41,3 +45,18 @@ type ManifestBundleStatus struct {
 	// ManifestResourceStatus represents the status of each resource in manifest work deployed on managed cluster.
 	ResourceStatus []workv1.ManifestCondition `json:"resourceStatus,omitempty"`
 }
+
+// SetCondition sets a condition of the ManifestBundle status, see conditions.Set.
+func (s *ManifestBundleStatus) SetCondition(condition metav1.Condition) bool {
+	return conditions.Set(&s.Conditions, condition)
+}
+
+// GetCondition returns the condition of the given type, or nil if it is not found.
+func (s *ManifestBundleStatus) GetCondition(conditionType string) *metav1.Condition {
+	return conditions.Get(s.Conditions, conditionType)
+}
+
+// IsConditionTrue returns true if the condition of the given type is true.
+func (s *ManifestBundleStatus) IsConditionTrue(conditionType string) bool {
+	return conditions.IsTrue(s.Conditions, conditionType)
+}
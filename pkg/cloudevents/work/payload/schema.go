@@ -0,0 +1,19 @@
+package payload
+
+import (
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+)
+
+// ManifestEventSchema describes the events of the single manifests.
+var ManifestEventSchema = schema.EventSchema{
+	DataType: ManifestEventDataType,
+	Spec:     schema.For[Manifest](),
+	Status:   schema.For[ManifestStatus](),
+}
+
+// ManifestBundleEventSchema describes the events of the manifest bundles.
+var ManifestBundleEventSchema = schema.EventSchema{
+	DataType: ManifestBundleEventDataType,
+	Spec:     schema.For[ManifestBundle](),
+	Status:   schema.For[ManifestBundleStatus](),
+}
@@ -0,0 +1,74 @@
+package payload
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/schema"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestManifestBundleEventSchema(t *testing.T) {
+	cases := []struct {
+		name        string
+		subResource types.EventSubResource
+		data        any
+		expectedErr bool
+	}{
+		{
+			name:        "spec",
+			subResource: types.SubResourceSpec,
+			data: &ManifestBundle{
+				Manifests: []workv1.Manifest{
+					{RawExtension: runtime.RawExtension{Raw: []byte(`{"kind":"ConfigMap"}`)}},
+				},
+				DeleteOption: &workv1.DeleteOption{PropagationPolicy: workv1.DeletePropagationPolicyTypeOrphan},
+			},
+		},
+		{
+			name:        "status",
+			subResource: types.SubResourceStatus,
+			data: &ManifestBundleStatus{
+				Conditions: []metav1.Condition{{Type: "Applied", Status: metav1.ConditionTrue}},
+				ResourceStatus: []workv1.ManifestCondition{
+					{ResourceMeta: workv1.ManifestResourceMeta{Kind: "ConfigMap", Name: "test"}},
+				},
+			},
+		},
+		{
+			name:        "invalid spec",
+			subResource: types.SubResourceSpec,
+			data:        map[string]any{"manifests": []any{"test"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evt := types.NewEventBuilder("source1", types.CloudEventsType{
+				CloudEventsDataType: ManifestBundleEventDataType,
+				SubResource:         c.subResource,
+				Action:              "update_request",
+			}).WithResourceID("test").
+				WithResourceVersion(1).
+				WithClusterName("cluster1").
+				WithOriginalSource("source1").
+				NewEvent()
+			if err := evt.SetData(cloudevents.ApplicationJSON, c.data); err != nil {
+				t.Fatal(err)
+			}
+
+			err := schema.NewRegistry(ManifestBundleEventSchema).Validate(evt)
+			if c.expectedErr && err == nil {
+				t.Errorf("expected error, but got nil")
+			}
+			if !c.expectedErr && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+type deleteTTLKey struct{}
+
+// WithDeleteTTL returns back a new context that deletes the manifestworks with the given delete TTL seconds, the agent
+// waits for the TTL before it cleans up the manifests of a deleted manifestwork. The grace period seconds of the delete
+// options are not used as the delete TTL, so deleting with a zero grace period does not change the deletion.
+func WithDeleteTTL(ctx context.Context, ttlSeconds int64) context.Context {
+	return context.WithValue(ctx, deleteTTLKey{}, ttlSeconds)
+}
+
+// toDeleteOption converts the kube delete options to the cloudevents delete option, the orphan propagation policy
+// keeps the manifests on the cluster and the foreground propagation policy cleans them up, the other propagation
+// policies are not supported by the agents. The delete TTL is set with WithDeleteTTL.
+func toDeleteOption(ctx context.Context, opts metav1.DeleteOptions) (*types.DeleteOption, error) {
+	ttlSeconds, hasTTL := ctx.Value(deleteTTLKey{}).(int64)
+	if opts.PropagationPolicy == nil && !hasTTL {
+		return nil, nil
+	}
+
+	deleteOption := &types.DeleteOption{}
+	if hasTTL {
+		deleteOption.TTLSeconds = &ttlSeconds
+	}
+
+	if opts.PropagationPolicy != nil {
+		switch *opts.PropagationPolicy {
+		case metav1.DeletePropagationOrphan:
+			deleteOption.PropagationPolicy = string(workv1.DeletePropagationPolicyTypeOrphan)
+		case metav1.DeletePropagationForeground:
+			deleteOption.PropagationPolicy = string(workv1.DeletePropagationPolicyTypeForeground)
+		default:
+			return nil, errors.NewBadRequest(fmt.Sprintf("unsupported propagation policy %s, only %s and %s are supported",
+				*opts.PropagationPolicy, metav1.DeletePropagationOrphan, metav1.DeletePropagationForeground))
+		}
+	}
+
+	return deleteOption, nil
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestToDeleteOption(t *testing.T) {
+	orphan := metav1.DeletePropagationOrphan
+	foreground := metav1.DeletePropagationForeground
+	background := metav1.DeletePropagationBackground
+	zero := int64(0)
+	ttl := int64(60)
+
+	cases := []struct {
+		name           string
+		ctx            context.Context
+		opts           metav1.DeleteOptions
+		expectedOption *types.DeleteOption
+		expectedErr    bool
+	}{
+		{
+			name: "no delete option",
+			ctx:  context.TODO(),
+			opts: metav1.DeleteOptions{},
+		},
+		{
+			name: "zero grace period is not a delete ttl",
+			ctx:  context.TODO(),
+			opts: metav1.DeleteOptions{GracePeriodSeconds: &zero},
+		},
+		{
+			name:           "orphan",
+			ctx:            context.TODO(),
+			opts:           metav1.DeleteOptions{PropagationPolicy: &orphan},
+			expectedOption: &types.DeleteOption{PropagationPolicy: "Orphan"},
+		},
+		{
+			name:           "foreground with delete ttl",
+			ctx:            WithDeleteTTL(context.TODO(), ttl),
+			opts:           metav1.DeleteOptions{PropagationPolicy: &foreground, GracePeriodSeconds: &zero},
+			expectedOption: &types.DeleteOption{PropagationPolicy: "Foreground", TTLSeconds: &ttl},
+		},
+		{
+			name:           "delete ttl",
+			ctx:            WithDeleteTTL(context.TODO(), ttl),
+			opts:           metav1.DeleteOptions{},
+			expectedOption: &types.DeleteOption{TTLSeconds: &ttl},
+		},
+		{
+			name:        "background is not supported",
+			ctx:         context.TODO(),
+			opts:        metav1.DeleteOptions{PropagationPolicy: &background},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			deleteOption, err := toDeleteOption(c.ctx, c.opts)
+			if c.expectedErr {
+				if !errors.IsBadRequest(err) {
+					t.Errorf("expected a bad request error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if !reflect.DeepEqual(deleteOption, c.expectedOption) {
+				t.Errorf("expected %v, but got %v", c.expectedOption, deleteOption)
+			}
+		})
+	}
+}
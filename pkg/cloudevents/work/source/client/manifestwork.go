@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"strconv"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	kubetypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/klog/v2"
@@ -28,6 +30,7 @@ type ManifestWorkSourceClient struct {
 	lister            workv1lister.ManifestWorkLister
 	namespace         string
 	sourceID          string
+	validators        []Validator
 }
 
 var _ workv1client.ManifestWorkInterface = &ManifestWorkSourceClient{}
@@ -46,10 +49,24 @@ func (c *ManifestWorkSourceClient) SetLister(lister workv1lister.ManifestWorkLis
 	c.lister = lister
 }
 
+// SetValidators sets the validators that validate a manifestwork in order before it is created, updated or patched, a
+// manifestwork that is rejected by a validator is not published and a RejectedError is returned.
+func (c *ManifestWorkSourceClient) SetValidators(validators ...Validator) {
+	c.validators = validators
+}
+
 func (mw *ManifestWorkSourceClient) SetNamespace(namespace string) {
 	mw.namespace = namespace
 }
 
+// Namespace returns a copy of the client for the given namespace, the copy shares the cloudevents client, the watcher
+// and the lister with this client, so the clients of different namespaces can be used concurrently.
+func (c *ManifestWorkSourceClient) Namespace(namespace string) *ManifestWorkSourceClient {
+	namespaced := *c
+	namespaced.namespace = namespace
+	return &namespaced
+}
+
 func (c *ManifestWorkSourceClient) Create(ctx context.Context, manifestWork *workv1.ManifestWork, opts metav1.CreateOptions) (*workv1.ManifestWork, error) {
 	_, err := c.lister.ManifestWorks(c.namespace).Get(manifestWork.Name)
 	if err == nil {
@@ -80,17 +97,82 @@ func (c *ManifestWorkSourceClient) Create(ctx context.Context, manifestWork *wor
 	newWork.UID = kubetypes.UID(utils.UID(c.sourceID, c.namespace, newWork.Name))
 	newWork.Generation = generation
 	ensureSourceLabel(c.sourceID, newWork)
+	if err := c.validate(ctx, newWork); err != nil {
+		return nil, err
+	}
+	ctx, dryRun := withDryRun(ctx, opts.DryRun)
 	if err := c.cloudEventsClient.Publish(ctx, eventType, newWork); err != nil {
 		return nil, err
 	}
+	if dryRun {
+		return newWork.DeepCopy(), nil
+	}
 
 	// add the new work to the ManifestWorkInformer local cache.
 	c.watcher.Receive(watch.Event{Type: watch.Added, Object: newWork})
 	return newWork.DeepCopy(), nil
 }
 
+// Update publishes the updated manifestwork to the agent. Unlike Patch, the generation annotation of the manifestwork
+// does not have to be increased, if it is not increased, the generation is increased by the client when the spec, the
+// labels or the annotations of the manifestwork are changed, so the code that updates the manifestworks with the kube
+// work clientset can switch to this client without changes.
 func (c *ManifestWorkSourceClient) Update(ctx context.Context, manifestWork *workv1.ManifestWork, opts metav1.UpdateOptions) (*workv1.ManifestWork, error) {
-	return nil, errors.NewMethodNotSupported(common.ManifestWorkGR, "update")
+	klog.V(4).Infof("updating manifestwork %s", manifestWork.Name)
+
+	lastWork, err := c.lister.ManifestWorks(c.namespace).Get(manifestWork.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	newWork := manifestWork.DeepCopy()
+	if newWork.Annotations == nil {
+		newWork.Annotations = map[string]string{}
+	}
+	if _, ok := newWork.Annotations[common.CloudEventsDataTypeAnnotationKey]; !ok {
+		newWork.Annotations[common.CloudEventsDataTypeAnnotationKey] = lastWork.Annotations[common.CloudEventsDataTypeAnnotationKey]
+	}
+
+	generation, err := getWorkGeneration(newWork)
+	if err != nil || generation <= lastWork.Generation {
+		if !workChanged(lastWork, newWork) {
+			return lastWork.DeepCopy(), nil
+		}
+
+		generation = lastWork.Generation + 1
+		newWork.Annotations[common.CloudEventsGenerationAnnotationKey] = strconv.FormatInt(generation, 10)
+	}
+
+	eventDataType, err := types.ParseCloudEventsDataType(newWork.Annotations[common.CloudEventsDataTypeAnnotationKey])
+	if err != nil {
+		return nil, err
+	}
+
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: *eventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              common.UpdateRequestAction,
+	}
+
+	// the status of a manifestwork is only updated by the agent
+	newWork.UID = lastWork.UID
+	newWork.Generation = generation
+	newWork.Status = lastWork.Status
+	ensureSourceLabel(c.sourceID, newWork)
+	if err := c.validate(ctx, newWork); err != nil {
+		return nil, err
+	}
+	ctx, dryRun := withDryRun(ctx, opts.DryRun)
+	if err := c.cloudEventsClient.Publish(ctx, eventType, newWork); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return newWork.DeepCopy(), nil
+	}
+
+	// refresh the work in the ManifestWorkInformer local cache with updated work.
+	c.watcher.Receive(watch.Event{Type: watch.Modified, Object: newWork})
+	return newWork.DeepCopy(), nil
 }
 
 func (c *ManifestWorkSourceClient) UpdateStatus(ctx context.Context, manifestWork *workv1.ManifestWork, opts metav1.UpdateOptions) (*workv1.ManifestWork, error) {
@@ -117,13 +199,23 @@ func (c *ManifestWorkSourceClient) Delete(ctx context.Context, name string, opts
 		Action:              common.DeleteRequestAction,
 	}
 
+	deleteOption, err := toDeleteOption(ctx, opts)
+	if err != nil {
+		return err
+	}
+
 	deletingWork := work.DeepCopy()
 	now := metav1.Now()
 	deletingWork.DeletionTimestamp = &now
+	utils.SetDeleteOption(deletingWork, deleteOption)
 
+	ctx, dryRun := withDryRun(ctx, opts.DryRun)
 	if err := c.cloudEventsClient.Publish(ctx, eventType, deletingWork); err != nil {
 		return err
 	}
+	if dryRun {
+		return nil
+	}
 
 	// update the deleting work in the ManifestWorkInformer local cache.
 	c.watcher.Receive(watch.Event{Type: watch.Modified, Object: deletingWork})
@@ -136,7 +228,12 @@ func (c *ManifestWorkSourceClient) DeleteCollection(ctx context.Context, opts me
 
 func (c *ManifestWorkSourceClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*workv1.ManifestWork, error) {
 	klog.V(4).Infof("getting manifestwork %s", name)
-	return c.lister.ManifestWorks(c.namespace).Get(name)
+	work, err := c.lister.ManifestWorks(c.namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return work.DeepCopy(), nil
 }
 
 func (c *ManifestWorkSourceClient) List(ctx context.Context, opts metav1.ListOptions) (*workv1.ManifestWorkList, error) {
@@ -146,7 +243,27 @@ func (c *ManifestWorkSourceClient) List(ctx context.Context, opts metav1.ListOpt
 		return nil, err
 	}
 
-	return &workv1.ManifestWorkList{}, nil
+	// the lister is not set until the ManifestWorkInformer is built
+	if c.lister == nil {
+		return &workv1.ManifestWorkList{}, nil
+	}
+
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	works, err := c.lister.ManifestWorks(c.namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []workv1.ManifestWork{}
+	for _, work := range works {
+		items = append(items, *work.DeepCopy())
+	}
+
+	return &workv1.ManifestWorkList{Items: items}, nil
 }
 
 func (c *ManifestWorkSourceClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
@@ -193,9 +310,16 @@ func (c *ManifestWorkSourceClient) Patch(ctx context.Context, name string, pt ku
 
 	newWork := patchedWork.DeepCopy()
 	newWork.Generation = generation
+	if err := c.validate(ctx, newWork); err != nil {
+		return nil, err
+	}
+	ctx, dryRun := withDryRun(ctx, opts.DryRun)
 	if err := c.cloudEventsClient.Publish(ctx, eventType, newWork); err != nil {
 		return nil, err
 	}
+	if dryRun {
+		return newWork.DeepCopy(), nil
+	}
 
 	// refresh the work in the ManifestWorkInformer local cache with patched work.
 	c.watcher.Receive(watch.Event{Type: watch.Modified, Object: newWork})
@@ -216,6 +340,33 @@ func getWorkGeneration(work *workv1.ManifestWork) (int64, error) {
 	return int64(generationInt), nil
 }
 
+// workChanged returns true if the spec, the labels or the annotations of the manifestwork are changed, the generation
+// annotation and the source label are ignored.
+func workChanged(lastWork, newWork *workv1.ManifestWork) bool {
+	if !equality.Semantic.DeepEqual(lastWork.Spec, newWork.Spec) {
+		return true
+	}
+
+	if !equality.Semantic.DeepEqual(withoutKeys(lastWork.Labels, common.CloudEventsOriginalSourceLabelKey),
+		withoutKeys(newWork.Labels, common.CloudEventsOriginalSourceLabelKey)) {
+		return true
+	}
+
+	return !equality.Semantic.DeepEqual(withoutKeys(lastWork.Annotations, common.CloudEventsGenerationAnnotationKey),
+		withoutKeys(newWork.Annotations, common.CloudEventsGenerationAnnotationKey))
+}
+
+func withoutKeys(m map[string]string, keys ...string) map[string]string {
+	result := map[string]string{}
+	for k, v := range m {
+		result[k] = v
+	}
+	for _, key := range keys {
+		delete(result, key)
+	}
+	return result
+}
+
 func ensureSourceLabel(sourceID string, work *workv1.ManifestWork) {
 	if work.Labels == nil {
 		work.Labels = map[string]string{}
@@ -223,3 +374,15 @@ func ensureSourceLabel(sourceID string, work *workv1.ManifestWork) {
 
 	work.Labels[common.CloudEventsOriginalSourceLabelKey] = sourceID
 }
+
+// withDryRun returns back a context that publishes the manifestwork in the dry-run mode if the dry-run options of a
+// request have metav1.DryRunAll, the manifestwork is encoded and validated with the limits of the protocol, but it is
+// not sent to the agent and the ManifestWorkInformer local cache is not changed.
+func withDryRun(ctx context.Context, dryRun []string) (context.Context, bool) {
+	for _, value := range dryRun {
+		if value == metav1.DryRunAll {
+			return generic.WithDryRun(ctx), true
+		}
+	}
+	return ctx, false
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	workv1lister "open-cluster-management.io/api/client/work/listers/work/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/fake"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/source/codec"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/watcher"
+)
+
+type testWorkLister struct {
+	lister workv1lister.ManifestWorkLister
+}
+
+func (l *testWorkLister) List(options types.ListOptions) ([]*workv1.ManifestWork, error) {
+	return l.lister.ManifestWorks(options.ClusterName).List(labels.Everything())
+}
+
+func TestCreateDryRun(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	lister := workv1lister.NewManifestWorkLister(indexer)
+
+	fakeClient := fake.NewCloudEventsFakeClient()
+	cloudEventsClient, err := generic.NewCloudEventSourceClient[*workv1.ManifestWork](context.TODO(),
+		fake.NewSourceOptions(fakeClient, "source1"), &testWorkLister{lister: lister},
+		func(*workv1.ManifestWork) (string, error) { return "", nil }, codec.NewManifestBundleCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	received := 0
+	workWatcher := watcher.NewManifestWorkWatcher()
+	workWatcher.SetFilter(func(obj metav1.Object) bool {
+		received++
+		return false
+	})
+
+	client := NewManifestWorkSourceClient("source1", cloudEventsClient, workWatcher)
+	client.SetLister(lister)
+	client.SetNamespace("cluster1")
+
+	manifestWork := newWork(nil, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test","namespace":"default"}}`)
+	manifestWork.Annotations = map[string]string{
+		common.CloudEventsDataTypeAnnotationKey:   payload.ManifestBundleEventDataType.String(),
+		common.CloudEventsGenerationAnnotationKey: "1",
+	}
+
+	created, err := client.Create(context.TODO(), manifestWork, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(created.UID) == 0 || created.Generation != 1 {
+		t.Errorf("expected the created manifestwork, but got %v", created)
+	}
+
+	if len(fakeClient.GetSentEvents()) != 0 {
+		t.Errorf("expected no sent events, but got %v", fakeClient.GetSentEvents())
+	}
+	if received != 0 {
+		t.Errorf("expected the local cache is not changed, but got %d events", received)
+	}
+
+	if _, err := client.Create(context.TODO(), manifestWork, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(fakeClient.GetSentEvents()) != 1 || received != 1 {
+		t.Errorf("expected the manifestwork is created, but got %v", fakeClient.GetSentEvents())
+	}
+}
+
+func TestDeleteDryRun(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	lister := workv1lister.NewManifestWorkLister(indexer)
+
+	fakeClient := fake.NewCloudEventsFakeClient()
+	cloudEventsClient, err := generic.NewCloudEventSourceClient[*workv1.ManifestWork](context.TODO(),
+		fake.NewSourceOptions(fakeClient, "source1"), &testWorkLister{lister: lister},
+		func(*workv1.ManifestWork) (string, error) { return "", nil }, codec.NewManifestBundleCodec())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	received := 0
+	workWatcher := watcher.NewManifestWorkWatcher()
+	workWatcher.SetFilter(func(obj metav1.Object) bool {
+		received++
+		return false
+	})
+
+	client := NewManifestWorkSourceClient("source1", cloudEventsClient, workWatcher)
+	client.SetLister(lister)
+	client.SetNamespace("cluster1")
+
+	manifestWork := newWork(nil, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test","namespace":"default"}}`)
+	manifestWork.UID = "test"
+	manifestWork.ResourceVersion = "1"
+	manifestWork.Annotations = map[string]string{
+		common.CloudEventsDataTypeAnnotationKey:   payload.ManifestBundleEventDataType.String(),
+		common.CloudEventsGenerationAnnotationKey: "1",
+	}
+	if err := indexer.Add(manifestWork); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := client.Delete(context.TODO(), manifestWork.Name, metav1.DeleteOptions{DryRun: []string{metav1.DryRunAll}}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(fakeClient.GetSentEvents()) != 0 {
+		t.Errorf("expected no sent events, but got %v", fakeClient.GetSentEvents())
+	}
+	if received != 0 {
+		t.Errorf("expected the local cache is not changed, but got %d events", received)
+	}
+	cached, err := lister.ManifestWorks("cluster1").Get(manifestWork.Name)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if cached.DeletionTimestamp != nil {
+		t.Errorf("expected the cached manifestwork is not deleting, but got %v", cached.DeletionTimestamp)
+	}
+
+	if err := client.Delete(context.TODO(), manifestWork.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(fakeClient.GetSentEvents()) != 1 || received != 1 {
+		t.Errorf("expected the manifestwork is deleted, but got %v", fakeClient.GetSentEvents())
+	}
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
+)
+
+// Validator validates a manifestwork before its spec is published to the agent, it returns an error with the reason
+// to reject the manifestwork.
+type Validator func(ctx context.Context, work *workv1.ManifestWork) error
+
+// RejectedError is returned by the ManifestWorkSourceClient when a manifestwork is rejected by a validator, it is a
+// forbidden kube API status error, so errors.IsForbidden returns true for it.
+type RejectedError struct {
+	// Namespace and Name are the namespace and name of the rejected manifestwork.
+	Namespace string
+	Name      string
+
+	// Err is the error returned by the validator.
+	Err error
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("manifestwork %s/%s is rejected, %v", e.Namespace, e.Name, e.Err)
+}
+
+func (e *RejectedError) Unwrap() error {
+	return e.Err
+}
+
+// Status implements the kube APIStatus interface.
+func (e *RejectedError) Status() metav1.Status {
+	return metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusForbidden,
+		Reason:  metav1.StatusReasonForbidden,
+		Message: e.Error(),
+		Details: &metav1.StatusDetails{
+			Group: common.ManifestWorkGR.Group,
+			Kind:  common.ManifestWorkGR.Resource,
+			Name:  e.Name,
+		},
+	}
+}
+
+// IsRejected returns true if the error is or wraps a RejectedError.
+func IsRejected(err error) bool {
+	var rejected *RejectedError
+	return errors.As(err, &rejected)
+}
+
+// ManifestsSizeLimit returns a validator that rejects a manifestwork whose total size of the manifests exceeds the
+// limit in bytes.
+func ManifestsSizeLimit(limit int) Validator {
+	return func(_ context.Context, work *workv1.ManifestWork) error {
+		size := 0
+		for _, manifest := range work.Spec.Workload.Manifests {
+			size = size + manifest.Size()
+		}
+
+		if size > limit {
+			return fmt.Errorf("the size of manifests is %d bytes which exceeds the %d limit", size, limit)
+		}
+		return nil
+	}
+}
+
+// ForbiddenKinds returns a validator that rejects a manifestwork that has a manifest of the forbidden kinds, the
+// manifests that cannot be decoded are rejected too.
+func ForbiddenKinds(kinds ...schema.GroupKind) Validator {
+	forbidden := map[schema.GroupKind]bool{}
+	for _, kind := range kinds {
+		forbidden[kind] = true
+	}
+
+	return func(_ context.Context, work *workv1.ManifestWork) error {
+		for index, manifest := range work.Spec.Workload.Manifests {
+			obj := &unstructured.Unstructured{}
+			if err := obj.UnmarshalJSON(manifest.Raw); err != nil {
+				return fmt.Errorf("failed to decode the manifest %d, %v", index, err)
+			}
+
+			if gk := obj.GroupVersionKind().GroupKind(); forbidden[gk] {
+				return fmt.Errorf("the manifest %d has the forbidden kind %s", index, gk.String())
+			}
+		}
+		return nil
+	}
+}
+
+// RequiredLabels returns a validator that rejects a manifestwork that does not have the labels of the keys.
+func RequiredLabels(keys ...string) Validator {
+	return func(_ context.Context, work *workv1.ManifestWork) error {
+		missing := []string{}
+		for _, key := range keys {
+			if _, ok := work.Labels[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+
+		if len(missing) != 0 {
+			return fmt.Errorf("the required labels %s are not found", strings.Join(missing, ","))
+		}
+		return nil
+	}
+}
+
+// validate runs the validators in order, the first error is returned as a RejectedError.
+func (c *ManifestWorkSourceClient) validate(ctx context.Context, work *workv1.ManifestWork) error {
+	for _, validate := range c.validators {
+		if err := validate(ctx, work); err != nil {
+			return &RejectedError{Namespace: c.namespace, Name: work.Name, Err: err}
+		}
+	}
+
+	return nil
+}
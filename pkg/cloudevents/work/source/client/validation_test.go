@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newWork(labels map[string]string, manifests ...string) *workv1.ManifestWork {
+	work := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "cluster1", Labels: labels},
+	}
+	for _, manifest := range manifests {
+		work.Spec.Workload.Manifests = append(work.Spec.Workload.Manifests,
+			workv1.Manifest{RawExtension: runtime.RawExtension{Raw: []byte(manifest)}})
+	}
+	return work
+}
+
+func TestValidate(t *testing.T) {
+	configMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test","namespace":"default"}}`
+	clusterRole := `{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"test"}}`
+
+	validators := []Validator{
+		ManifestsSizeLimit(200),
+		ForbiddenKinds(schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}),
+		RequiredLabels("team"),
+	}
+
+	cases := []struct {
+		name             string
+		work             *workv1.ManifestWork
+		expectedRejected bool
+	}{
+		{
+			name: "allowed",
+			work: newWork(map[string]string{"team": "test"}, configMap),
+		},
+		{
+			name:             "exceed the size limit",
+			work:             newWork(map[string]string{"team": "test"}, configMap, configMap, configMap),
+			expectedRejected: true,
+		},
+		{
+			name:             "forbidden kind",
+			work:             newWork(map[string]string{"team": "test"}, configMap, clusterRole),
+			expectedRejected: true,
+		},
+		{
+			name:             "invalid manifest",
+			work:             newWork(map[string]string{"team": "test"}, "{"),
+			expectedRejected: true,
+		},
+		{
+			name:             "no required label",
+			work:             newWork(map[string]string{}, configMap),
+			expectedRejected: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &ManifestWorkSourceClient{namespace: "cluster1"}
+			client.SetValidators(validators...)
+
+			err := client.validate(context.TODO(), c.work)
+			if IsRejected(err) != c.expectedRejected {
+				t.Errorf("expected rejected %v, but got %v", c.expectedRejected, err)
+			}
+			if errors.IsForbidden(err) != c.expectedRejected {
+				t.Errorf("expected forbidden %v, but got %v", c.expectedRejected, err)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package codec
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
@@ -11,7 +13,9 @@ import (
 
 	workv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/utils"
 )
 
 // ManifestBundleCodec is a codec to encode/decode a ManifestWork/cloudevent with ManifestBundle for a source.
@@ -32,25 +36,51 @@ func (c *ManifestBundleCodec) Encode(source string, eventType types.CloudEventsT
 		return nil, fmt.Errorf("unsupported cloudevents data type %s", eventType.CloudEventsDataType)
 	}
 
-	evt := types.NewEventBuilder(source, eventType).
+	builder := types.NewEventBuilder(source, eventType).
 		WithClusterName(work.Namespace).
 		WithResourceID(string(work.UID)).
-		WithResourceVersion(work.Generation).
-		NewEvent()
+		WithResourceVersion(work.Generation)
 	if !work.DeletionTimestamp.IsZero() {
-		evt.SetExtension(types.ExtensionDeletionTimestamp, work.DeletionTimestamp.Time)
+		deleteOption, err := utils.GetDeleteOption(work)
+		if err != nil {
+			return nil, err
+		}
+
+		builder = builder.WithDeletionTimestamp(work.DeletionTimestamp.Time)
+		if deleteOption != nil {
+			builder = builder.WithDeleteOption(*deleteOption)
+		}
+
+		evt := builder.NewEvent()
 		return &evt, nil
 	}
 
+	if ttl, ok := work.Annotations[common.CloudEventsSpecTTLSecondsAnnotationKey]; ok {
+		ttlSeconds, err := strconv.ParseInt(ttl, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the spec ttl seconds of the work %s, %v", work.UID, err)
+		}
+
+		builder = builder.WithExpirationTime(time.Now().Add(time.Duration(ttlSeconds) * time.Second))
+	}
+
+	evt := builder.NewEvent()
+
 	manifests := &payload.ManifestBundle{
 		Manifests:       work.Spec.Workload.Manifests,
 		DeleteOption:    work.Spec.DeleteOption,
 		ManifestConfigs: work.Spec.ManifestConfigs,
+		Executor:        work.Spec.Executor,
 	}
-	if err := evt.SetData(cloudevents.ApplicationJSON, manifests); err != nil {
-		return nil, fmt.Errorf("failed to encode manifestwork status to a cloudevent: %v", err)
+	data, err := payload.EncodeManifestBundle(manifests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifestwork spec to a cloudevent: %v", err)
 	}
 
+	// the data is encoded JSON, set it directly instead of encoding it again with the SetData
+	evt.SetDataContentType(cloudevents.ApplicationJSON)
+	evt.DataEncoded = data
+
 	return &evt, nil
 }
 
@@ -1,11 +1,13 @@
 package codec
 
 import (
+	"fmt"
 	"testing"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	workv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
@@ -150,3 +152,63 @@ func TestManifestBundleDecode(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkManifestBundleEncode(b *testing.B) {
+	codec := NewManifestBundleCodec()
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: payload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}
+	work := newBenchmarkWork(10)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := codec.Encode("source1", eventType, work); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkManifestBundleDecode(b *testing.B) {
+	codec := NewManifestBundleCodec()
+	evt := types.NewEventBuilder("agent1", types.CloudEventsType{
+		CloudEventsDataType: payload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              "status_update",
+	}).WithResourceID("test").WithResourceVersion(1).WithClusterName("cluster1").NewEvent()
+	if err := evt.SetData(cloudevents.ApplicationJSON, &payload.ManifestBundleStatus{
+		Conditions: []metav1.Condition{{Type: "Applied", Status: metav1.ConditionTrue, Reason: "Applied"}},
+	}); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := codec.Decode(&evt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func newBenchmarkWork(manifests int) *workv1.ManifestWork {
+	work := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:        "test",
+			Namespace:  "cluster1",
+			Generation: 1,
+		},
+	}
+
+	for i := 0; i < manifests; i++ {
+		work.Spec.Workload.Manifests = append(work.Spec.Workload.Manifests, workv1.Manifest{
+			RawExtension: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap",`+
+				`"metadata":{"name":"test%d","namespace":"default","labels":{"app":"test"}},`+
+				`"data":{"key1":"value1","key2":"value2","key3":"value3"}}`, i))},
+		})
+	}
+
+	return work
+}
@@ -7,7 +7,6 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/labels"
 	kubetypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -18,6 +17,7 @@ import (
 
 	workv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/conditions"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/watcher"
@@ -58,6 +58,8 @@ func (h *ManifestWorkSourceHandler) HandlerFunc() generic.ResourceHandler[*workv
 		switch action {
 		case types.StatusModified:
 			h.works.Add(obj)
+		case types.StatusResynced:
+			// the status of the manifestwork is not changed, do nothing
 		default:
 			return fmt.Errorf("unsupported resource action %s", action)
 		}
@@ -104,7 +106,7 @@ func (h *ManifestWorkSourceHandler) handleWork(work *workv1.ManifestWork) error
 	}
 
 	updatedWork := lastWork.DeepCopy()
-	if meta.IsStatusConditionTrue(work.Status.Conditions, common.ManifestsDeleted) {
+	if conditions.IsTrue(work.Status.Conditions, common.ManifestsDeleted) {
 		updatedWork.Finalizers = []string{}
 		h.watcher.Receive(watch.Event{Type: watch.Deleted, Object: updatedWork})
 		return nil
@@ -6,6 +6,15 @@ import (
 	"fmt"
 
 	workv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+)
+
+var manifestWorkStructuredStatusHash = generic.NewStructuredStatusHashGetter[*workv1.ManifestWork](
+	func(work *workv1.ManifestWork) any {
+		return work.Status
+	},
+	"lastTransitionTime",
 )
 
 // ManifestWorkStatusHash returns the SHA256 checksum of a ManifestWork status.
@@ -16,3 +25,9 @@ func ManifestWorkStatusHash(work *workv1.ManifestWork) (string, error) {
 	}
 	return fmt.Sprintf("%x", sha256.Sum256(statusBytes)), nil
 }
+
+// ManifestWorkStructuredStatusHash returns the SHA256 checksum of a ManifestWork status, the lastTransitionTime of the
+// conditions is ignored, so the status is not resent on the resync if only the lastTransitionTime is changed.
+func ManifestWorkStructuredStatusHash(work *workv1.ManifestWork) (string, error) {
+	return manifestWorkStructuredStatusHash(work)
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Backend persists the serialized objects of an informer store.
+//
+// Available implementations:
+//   - BoltBackend, saves the objects in an embedded bbolt key-value database, it is recommended for a large number
+//     of objects.
+//   - SQLiteBackend, saves the objects in an SQLite database, it requires cgo.
+//   - FileBackend, saves each object into a file.
+type Backend interface {
+	// Get returns the serialized object of the given key.
+	Get(key string) (data []byte, exists bool, err error)
+
+	// Put saves the serialized object with the given key.
+	Put(key string, data []byte) error
+
+	// Delete removes the object of the given key, deleting a nonexistent key is not an error.
+	Delete(key string) error
+
+	// Keys returns all the keys that are saved in the backend.
+	Keys() ([]string, error)
+}
+
+// FileBackend is a Backend that saves each object into a file under a given directory.
+type FileBackend struct {
+	dir string
+}
+
+var _ Backend = &FileBackend{}
+
+// NewFileBackend returns a FileBackend with the given directory, the directory will be created if it does not exist.
+func NewFileBackend(dir string) (*FileBackend, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create store directory %s, %v", dir, err)
+	}
+
+	return &FileBackend{dir: dir}, nil
+}
+
+func (b *FileBackend) Get(key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(b.path(key))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+func (b *FileBackend) Put(key string, data []byte) error {
+	// write to a temporary file first to avoid leaving a partial file
+	tmp, err := os.CreateTemp(b.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), b.path(key))
+}
+
+func (b *FileBackend) Delete(key string) error {
+	if err := os.Remove(b.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (b *FileBackend) Keys() ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		key, err := base64.RawURLEncoding.DecodeString(entry.Name())
+		if err != nil {
+			// not a file that is maintained by this backend, ignore
+			continue
+		}
+
+		keys = append(keys, string(key))
+	}
+
+	return keys, nil
+}
+
+// path encodes the key to a file name, the key of an object may contain the path separator.
+func (b *FileBackend) path(key string) string {
+	return filepath.Join(b.dir, base64.RawURLEncoding.EncodeToString([]byte(key)))
+}
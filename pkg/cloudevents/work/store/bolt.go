@@ -0,0 +1,82 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var boltBucket = []byte("objects")
+
+// BoltBackend is a Backend that saves the objects into an embedded bbolt key-value database.
+type BoltBackend struct {
+	db *bolt.DB
+}
+
+var _ Backend = &BoltBackend{}
+
+// NewBoltBackend returns a BoltBackend with the given database file, the file will be created if it does not exist.
+// The database file is locked by the backend until the backend is closed.
+func NewBoltBackend(path string) (*BoltBackend, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second, NoFreelistSync: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store database %s, %v", path, err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create store bucket, %v", err)
+	}
+
+	return &BoltBackend{db: db}, nil
+}
+
+func (b *BoltBackend) Get(key string) ([]byte, bool, error) {
+	var data []byte
+	if err := b.db.View(func(tx *bolt.Tx) error {
+		// the value is only valid in the transaction, copy it
+		if value := tx.Bucket(boltBucket).Get([]byte(key)); value != nil {
+			data = append([]byte{}, value...)
+		}
+		return nil
+	}); err != nil {
+		return nil, false, err
+	}
+
+	return data, data != nil, nil
+}
+
+func (b *BoltBackend) Put(key string, data []byte) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), data)
+	})
+}
+
+func (b *BoltBackend) Delete(key string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(key))
+	})
+}
+
+func (b *BoltBackend) Keys() ([]string, error) {
+	keys := []string{}
+	if err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).ForEach(func(key, _ []byte) error {
+			keys = append(keys, string(key))
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Close closes the database of the backend.
+func (b *BoltBackend) Close() error {
+	return b.db.Close()
+}
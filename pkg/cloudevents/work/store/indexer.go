@@ -0,0 +1,448 @@
+package store
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// DefaultHotCacheSize is the default number of the decoded objects that are kept in memory.
+const DefaultHotCacheSize = 1000
+
+// backendIndexer implements the cache.Indexer, it saves the objects in a Backend and only keeps the recently used
+// objects in an in-memory LRU cache (hot cache). The indices only hold the object keys, so they are kept in memory.
+type backendIndexer struct {
+	lock      sync.RWMutex
+	backend   Backend
+	newObject func() runtime.Object
+	keyFunc   cache.KeyFunc
+	indexers  cache.Indexers
+	indices   cache.Indices
+	// objectIndices keeps the index values of each object with its key, the indices can be updated without reading
+	// the last object back from the backend.
+	objectIndices map[string]map[string][]string
+	hotCache      *lruCache
+}
+
+var _ cache.Indexer = &backendIndexer{}
+
+// NewIndexer returns a cache.Indexer whose objects are saved in the given backend.
+//   - hotCacheSize is the max number of the decoded objects that are kept in memory, if it is less than or equal to zero,
+//     the DefaultHotCacheSize will be used.
+//   - newObject returns an empty object to decode the object from the backend.
+//
+// The backend must be empty, the objects of a previous run are not indexed, so they have to be purged with PurgeBackend
+// before the indexer is built, the informer will refill the store after it starts.
+func NewIndexer(backend Backend, hotCacheSize int, newObject func() runtime.Object,
+	keyFunc cache.KeyFunc, indexers cache.Indexers) (cache.Indexer, error) {
+	keys, err := backend.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) != 0 {
+		return nil, fmt.Errorf("the store backend has %d existing objects, purge them before building the indexer", len(keys))
+	}
+
+	if hotCacheSize <= 0 {
+		hotCacheSize = DefaultHotCacheSize
+	}
+
+	if indexers == nil {
+		indexers = cache.Indexers{}
+	}
+
+	return &backendIndexer{
+		backend:       backend,
+		newObject:     newObject,
+		keyFunc:       keyFunc,
+		indexers:      indexers,
+		indices:       cache.Indices{},
+		objectIndices: map[string]map[string][]string{},
+		hotCache:      newLRUCache(hotCacheSize),
+	}, nil
+}
+
+// PurgeBackend deletes all the existing objects in the given backend.
+func PurgeBackend(backend Backend) error {
+	keys, err := backend.Keys()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := backend.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *backendIndexer) Add(obj interface{}) error {
+	key, err := i.keyFunc(obj)
+	if err != nil {
+		return cache.KeyError{Obj: obj, Err: err}
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.put(key, obj)
+}
+
+func (i *backendIndexer) Update(obj interface{}) error {
+	return i.Add(obj)
+}
+
+func (i *backendIndexer) Delete(obj interface{}) error {
+	key, err := i.keyFunc(obj)
+	if err != nil {
+		return cache.KeyError{Obj: obj, Err: err}
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.delete(key)
+}
+
+func (i *backendIndexer) List() []interface{} {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	objs := make([]interface{}, 0, len(i.objectIndices))
+	for key := range i.objectIndices {
+		obj, exists, err := i.get(key)
+		if err != nil {
+			klog.Errorf("failed to get object %s from store, %v", key, err)
+			continue
+		}
+
+		if exists {
+			objs = append(objs, obj)
+		}
+	}
+
+	return objs
+}
+
+func (i *backendIndexer) ListKeys() []string {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	keys := make([]string, 0, len(i.objectIndices))
+	for key := range i.objectIndices {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (i *backendIndexer) Get(obj interface{}) (item interface{}, exists bool, err error) {
+	key, err := i.keyFunc(obj)
+	if err != nil {
+		return nil, false, cache.KeyError{Obj: obj, Err: err}
+	}
+
+	return i.GetByKey(key)
+}
+
+func (i *backendIndexer) GetByKey(key string) (item interface{}, exists bool, err error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return i.get(key)
+}
+
+func (i *backendIndexer) Replace(objs []interface{}, _ string) error {
+	items := make(map[string]interface{}, len(objs))
+	for _, obj := range objs {
+		key, err := i.keyFunc(obj)
+		if err != nil {
+			return cache.KeyError{Obj: obj, Err: err}
+		}
+		items[key] = obj
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for key := range i.objectIndices {
+		if _, ok := items[key]; ok {
+			continue
+		}
+
+		if err := i.delete(key); err != nil {
+			return err
+		}
+	}
+
+	for key, obj := range items {
+		if err := i.put(key, obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *backendIndexer) Resync() error {
+	// nothing to do
+	return nil
+}
+
+func (i *backendIndexer) Index(indexName string, obj interface{}) ([]interface{}, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	indexFunc, ok := i.indexers[indexName]
+	if !ok {
+		return nil, fmt.Errorf("index with name %s does not exist", indexName)
+	}
+
+	indexedValues, err := indexFunc(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := sets.New[string]()
+	for _, value := range indexedValues {
+		keys.Insert(i.indices[indexName][value].UnsortedList()...)
+	}
+
+	return i.getAll(sets.List(keys))
+}
+
+func (i *backendIndexer) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if _, ok := i.indexers[indexName]; !ok {
+		return nil, fmt.Errorf("index with name %s does not exist", indexName)
+	}
+
+	return i.indices[indexName][indexedValue].List(), nil
+}
+
+func (i *backendIndexer) ListIndexFuncValues(indexName string) []string {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	values := []string{}
+	for value := range i.indices[indexName] {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func (i *backendIndexer) ByIndex(indexName, indexedValue string) ([]interface{}, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if _, ok := i.indexers[indexName]; !ok {
+		return nil, fmt.Errorf("index with name %s does not exist", indexName)
+	}
+
+	return i.getAll(i.indices[indexName][indexedValue].List())
+}
+
+func (i *backendIndexer) GetIndexers() cache.Indexers {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return i.indexers
+}
+
+func (i *backendIndexer) AddIndexers(newIndexers cache.Indexers) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if len(i.objectIndices) > 0 {
+		return fmt.Errorf("cannot add indexers to running index")
+	}
+
+	for name, indexFunc := range newIndexers {
+		if _, ok := i.indexers[name]; ok {
+			return fmt.Errorf("indexer conflict: %v", name)
+		}
+		i.indexers[name] = indexFunc
+	}
+
+	return nil
+}
+
+func (i *backendIndexer) put(key string, obj interface{}) error {
+	indexValues := map[string][]string{}
+	for name, indexFunc := range i.indexers {
+		values, err := indexFunc(obj)
+		if err != nil {
+			return fmt.Errorf("unable to calculate an index entry for key %q on index %q: %v", key, name, err)
+		}
+		indexValues[name] = values
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to encode object %s, %v", key, err)
+	}
+
+	if err := i.backend.Put(key, data); err != nil {
+		return fmt.Errorf("failed to save object %s, %v", key, err)
+	}
+
+	i.deleteFromIndices(key)
+	i.objectIndices[key] = indexValues
+	for name, values := range indexValues {
+		index, ok := i.indices[name]
+		if !ok {
+			index = cache.Index{}
+			i.indices[name] = index
+		}
+
+		for _, value := range values {
+			if _, ok := index[value]; !ok {
+				index[value] = sets.String{}
+			}
+			index[value].Insert(key)
+		}
+	}
+
+	i.hotCache.add(key, obj)
+	return nil
+}
+
+func (i *backendIndexer) delete(key string) error {
+	if _, ok := i.objectIndices[key]; !ok {
+		return nil
+	}
+
+	if err := i.backend.Delete(key); err != nil {
+		return fmt.Errorf("failed to delete object %s, %v", key, err)
+	}
+
+	i.deleteFromIndices(key)
+	delete(i.objectIndices, key)
+	i.hotCache.remove(key)
+	return nil
+}
+
+func (i *backendIndexer) deleteFromIndices(key string) {
+	for name, values := range i.objectIndices[key] {
+		index := i.indices[name]
+		for _, value := range values {
+			index[value].Delete(key)
+			if index[value].Len() == 0 {
+				delete(index, value)
+			}
+		}
+	}
+}
+
+func (i *backendIndexer) get(key string) (interface{}, bool, error) {
+	if _, ok := i.objectIndices[key]; !ok {
+		return nil, false, nil
+	}
+
+	if obj, ok := i.hotCache.get(key); ok {
+		return obj, true, nil
+	}
+
+	data, exists, err := i.backend.Get(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !exists {
+		return nil, false, nil
+	}
+
+	obj := i.newObject()
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, false, fmt.Errorf("failed to decode object %s, %v", key, err)
+	}
+
+	i.hotCache.add(key, obj)
+	return obj, true, nil
+}
+
+func (i *backendIndexer) getAll(keys []string) ([]interface{}, error) {
+	objs := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		obj, exists, err := i.get(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			objs = append(objs, obj)
+		}
+	}
+
+	return objs, nil
+}
+
+// lruCache is a fixed size LRU cache, it is safe for concurrent use.
+type lruCache struct {
+	lock     sync.Mutex
+	size     int
+	items    map[string]*list.Element
+	evictors *list.List
+}
+
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{
+		size:     size,
+		items:    map[string]*list.Element{},
+		evictors: list.New(),
+	}
+}
+
+func (c *lruCache) add(key string, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.evictors.MoveToFront(elem)
+		elem.Value.(*lruEntry).value = value
+		return
+	}
+
+	c.items[key] = c.evictors.PushFront(&lruEntry{key: key, value: value})
+	if c.evictors.Len() > c.size {
+		oldest := c.evictors.Back()
+		c.evictors.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lruCache) get(key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.evictors.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true
+}
+
+func (c *lruCache) remove(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.evictors.Remove(elem)
+		delete(c.items, key)
+	}
+}
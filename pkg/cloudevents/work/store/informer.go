@@ -0,0 +1,416 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workv1informers "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	workv1lister "open-cluster-management.io/api/client/work/listers/work/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// manifestWorkInformer implements the ManifestWorkInformer with a backendIndexer.
+type manifestWorkInformer struct {
+	informer cache.SharedIndexInformer
+}
+
+var _ workv1informers.ManifestWorkInformer = &manifestWorkInformer{}
+
+// NewManifestWorkInformer returns a ManifestWorkInformer whose local cache is saved in the given backend, only the
+// recently used works (at most hotCacheSize) are kept in memory. The existing works in the backend are purged, the
+// informer refills the backend after it starts.
+func NewManifestWorkInformer(
+	client workclientset.Interface,
+	backend Backend,
+	hotCacheSize int,
+	namespace string,
+	resyncPeriod time.Duration,
+	tweakListOptions func(*metav1.ListOptions),
+) (workv1informers.ManifestWorkInformer, error) {
+	if err := PurgeBackend(backend); err != nil {
+		return nil, fmt.Errorf("failed to purge the store backend, %v", err)
+	}
+
+	indexer, err := NewIndexer(
+		backend,
+		hotCacheSize,
+		func() runtime.Object { return &workv1.ManifestWork{} },
+		cache.DeletionHandlingMetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			if tweakListOptions != nil {
+				tweakListOptions(&options)
+			}
+			return client.WorkV1().ManifestWorks(namespace).List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			if tweakListOptions != nil {
+				tweakListOptions(&options)
+			}
+			return client.WorkV1().ManifestWorks(namespace).Watch(context.TODO(), options)
+		},
+	}
+
+	return &manifestWorkInformer{
+		informer: newIndexerInformer(lw, &workv1.ManifestWork{}, resyncPeriod, indexer),
+	}, nil
+}
+
+func (i *manifestWorkInformer) Informer() cache.SharedIndexInformer {
+	return i.informer
+}
+
+func (i *manifestWorkInformer) Lister() workv1lister.ManifestWorkLister {
+	return workv1lister.NewManifestWorkLister(i.informer.GetIndexer())
+}
+
+// indexerInformer is a SharedIndexInformer with a given indexer, the shared informer of client-go always builds its
+// own in-memory indexer, so it cannot save the objects in a backend. Like the shared informer of client-go, each event
+// handler is notified asynchronously with its own unbounded notification queue, a new handler gets the existing
+// objects before any of their changes, and the informer resync period is used for all the handlers.
+type indexerInformer struct {
+	sync.RWMutex
+	lw                cache.ListerWatcher
+	exampleObject     runtime.Object
+	resyncPeriod      time.Duration
+	indexer           cache.Indexer
+	controller        cache.Controller
+	transform         cache.TransformFunc
+	watchErrorHandler cache.WatchErrorHandler
+	handlers          []*handlerRegistration
+	started           bool
+	stopped           bool
+
+	// processLock serializes the processing of the deltas and the notifications of the existing objects to a new
+	// handler, so the changes of an object are notified after the object is added to the handler.
+	processLock sync.Mutex
+}
+
+var _ cache.SharedIndexInformer = &indexerInformer{}
+
+// handlerRegistration notifies an event handler with the queued notifications in its own goroutine, so a slow handler
+// does not block the informer and the other handlers.
+type handlerRegistration struct {
+	handler  cache.ResourceEventHandler
+	informer *indexerInformer
+
+	lock          sync.Mutex
+	cond          *sync.Cond
+	notifications []notification
+	// initialPending is the number of the queued notifications of the initial objects, the handler is synced after
+	// the informer is synced and the initial objects are notified.
+	initialPending int
+	stopped        bool
+}
+
+type notification struct {
+	notify  func(handler cache.ResourceEventHandler)
+	initial bool
+}
+
+func newHandlerRegistration(handler cache.ResourceEventHandler, informer *indexerInformer) *handlerRegistration {
+	registration := &handlerRegistration{handler: handler, informer: informer}
+	registration.cond = sync.NewCond(&registration.lock)
+	return registration
+}
+
+func (r *handlerRegistration) HasSynced() bool {
+	// check the informer without holding the lock, the informer holds its queue lock when it notifies the handler
+	if !r.informer.HasSynced() {
+		return false
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.initialPending == 0
+}
+
+func (r *handlerRegistration) add(notify func(handler cache.ResourceEventHandler), initial bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.stopped {
+		return
+	}
+
+	if initial {
+		r.initialPending++
+	}
+	r.notifications = append(r.notifications, notification{notify: notify, initial: initial})
+	r.cond.Signal()
+}
+
+func (r *handlerRegistration) run() {
+	for {
+		r.lock.Lock()
+		for len(r.notifications) == 0 && !r.stopped {
+			r.cond.Wait()
+		}
+		if r.stopped {
+			r.lock.Unlock()
+			return
+		}
+
+		next := r.notifications[0]
+		r.notifications[0] = notification{}
+		r.notifications = r.notifications[1:]
+		r.lock.Unlock()
+
+		func() {
+			defer utilruntime.HandleCrash()
+			next.notify(r.handler)
+		}()
+
+		if next.initial {
+			r.lock.Lock()
+			r.initialPending--
+			r.lock.Unlock()
+		}
+	}
+}
+
+func (r *handlerRegistration) stop() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.stopped = true
+	r.notifications = nil
+	r.cond.Broadcast()
+}
+
+func newIndexerInformer(lw cache.ListerWatcher, exampleObject runtime.Object,
+	resyncPeriod time.Duration, indexer cache.Indexer) *indexerInformer {
+	return &indexerInformer{
+		lw:            lw,
+		exampleObject: exampleObject,
+		resyncPeriod:  resyncPeriod,
+		indexer:       indexer,
+	}
+}
+
+func (s *indexerInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	// hold the process lock, so the deltas are not processed until the existing objects are queued to the new handler
+	s.processLock.Lock()
+	defer s.processLock.Unlock()
+
+	s.Lock()
+	if s.stopped {
+		s.Unlock()
+		return nil, fmt.Errorf("handler %v was not added to shared informer because it has stopped already", handler)
+	}
+
+	registration := newHandlerRegistration(handler, s)
+	s.handlers = append(s.handlers, registration)
+	started := s.started
+	s.Unlock()
+
+	// notify the new handler with the existing objects
+	for _, obj := range s.indexer.List() {
+		obj := obj
+		registration.add(func(handler cache.ResourceEventHandler) { handler.OnAdd(obj, true) }, true)
+	}
+
+	// the handlers that are added before the informer starts are run by the informer
+	if started {
+		go registration.run()
+	}
+
+	return registration, nil
+}
+
+func (s *indexerInformer) AddEventHandlerWithResyncPeriod(
+	handler cache.ResourceEventHandler, _ time.Duration) (cache.ResourceEventHandlerRegistration, error) {
+	return s.AddEventHandler(handler)
+}
+
+func (s *indexerInformer) RemoveEventHandler(handle cache.ResourceEventHandlerRegistration) error {
+	s.Lock()
+	defer s.Unlock()
+
+	for i, registration := range s.handlers {
+		if registration == handle {
+			s.handlers = append(s.handlers[:i:i], s.handlers[i+1:]...)
+			registration.stop()
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (s *indexerInformer) GetStore() cache.Store {
+	return s.indexer
+}
+
+func (s *indexerInformer) GetIndexer() cache.Indexer {
+	return s.indexer
+}
+
+func (s *indexerInformer) AddIndexers(indexers cache.Indexers) error {
+	return s.indexer.AddIndexers(indexers)
+}
+
+func (s *indexerInformer) GetController() cache.Controller {
+	s.RLock()
+	defer s.RUnlock()
+	return s.controller
+}
+
+func (s *indexerInformer) Run(stopCh <-chan struct{}) {
+	s.Lock()
+	if s.started {
+		s.Unlock()
+		return
+	}
+
+	fifo := cache.NewDeltaFIFOWithOptions(cache.DeltaFIFOOptions{
+		KnownObjects:          s.indexer,
+		EmitDeltaTypeReplaced: true,
+		Transformer:           s.transform,
+	})
+
+	s.controller = cache.New(&cache.Config{
+		Queue:             fifo,
+		ListerWatcher:     s.lw,
+		ObjectType:        s.exampleObject,
+		FullResyncPeriod:  s.resyncPeriod,
+		Process:           s.handleDeltas,
+		WatchErrorHandler: s.watchErrorHandler,
+	})
+	s.started = true
+	for _, registration := range s.handlers {
+		go registration.run()
+	}
+	s.Unlock()
+
+	defer func() {
+		s.Lock()
+		defer s.Unlock()
+		s.stopped = true
+		for _, registration := range s.handlers {
+			registration.stop()
+		}
+	}()
+
+	s.controller.Run(stopCh)
+}
+
+func (s *indexerInformer) HasSynced() bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.controller == nil {
+		return false
+	}
+	return s.controller.HasSynced()
+}
+
+func (s *indexerInformer) LastSyncResourceVersion() string {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.controller == nil {
+		return ""
+	}
+	return s.controller.LastSyncResourceVersion()
+}
+
+func (s *indexerInformer) SetWatchErrorHandler(handler cache.WatchErrorHandler) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.started {
+		return fmt.Errorf("informer has already started")
+	}
+
+	s.watchErrorHandler = handler
+	return nil
+}
+
+func (s *indexerInformer) SetTransform(handler cache.TransformFunc) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.started {
+		return fmt.Errorf("informer has already started")
+	}
+
+	s.transform = handler
+	return nil
+}
+
+func (s *indexerInformer) IsStopped() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.stopped
+}
+
+func (s *indexerInformer) handleDeltas(obj interface{}, isInInitialList bool) error {
+	deltas, ok := obj.(cache.Deltas)
+	if !ok {
+		return fmt.Errorf("object given as Process argument is not Deltas")
+	}
+
+	s.processLock.Lock()
+	defer s.processLock.Unlock()
+
+	s.RLock()
+	handlers := s.handlers
+	s.RUnlock()
+
+	for _, d := range deltas {
+		obj := d.Object
+		switch d.Type {
+		case cache.Sync, cache.Replaced, cache.Added, cache.Updated:
+			old, exists, err := s.indexer.Get(obj)
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				if err := s.indexer.Update(obj); err != nil {
+					return err
+				}
+
+				for _, registration := range handlers {
+					registration.add(func(handler cache.ResourceEventHandler) { handler.OnUpdate(old, obj) }, false)
+				}
+				continue
+			}
+
+			if err := s.indexer.Add(obj); err != nil {
+				return err
+			}
+
+			for _, registration := range handlers {
+				registration.add(func(handler cache.ResourceEventHandler) { handler.OnAdd(obj, isInInitialList) },
+					isInInitialList)
+			}
+		case cache.Deleted:
+			if err := s.indexer.Delete(obj); err != nil {
+				return err
+			}
+
+			for _, registration := range handlers {
+				registration.add(func(handler cache.ResourceEventHandler) { handler.OnDelete(obj) }, false)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteBackend is a Backend that saves the objects into an SQLite database, the database is accessed with the
+// github.com/mattn/go-sqlite3 driver, so the binary must be built with cgo.
+type SQLiteBackend struct {
+	db *sql.DB
+}
+
+var _ Backend = &SQLiteBackend{}
+
+// NewSQLiteBackend returns a SQLiteBackend with the given database file, the file will be created if it does not exist.
+func NewSQLiteBackend(path string) (*SQLiteBackend, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store database %s, %v", path, err)
+	}
+
+	// the informer writes the objects one by one, one connection avoids the database is locked errors
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("CREATE TABLE IF NOT EXISTS objects (key TEXT PRIMARY KEY, data BLOB NOT NULL)"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create store table, %v", err)
+	}
+
+	return &SQLiteBackend{db: db}, nil
+}
+
+func (b *SQLiteBackend) Get(key string) ([]byte, bool, error) {
+	var data []byte
+	err := b.db.QueryRow("SELECT data FROM objects WHERE key = ?", key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+func (b *SQLiteBackend) Put(key string, data []byte) error {
+	_, err := b.db.Exec("INSERT INTO objects (key, data) VALUES (?, ?) "+
+		"ON CONFLICT (key) DO UPDATE SET data = excluded.data", key, data)
+	return err
+}
+
+func (b *SQLiteBackend) Delete(key string) error {
+	_, err := b.db.Exec("DELETE FROM objects WHERE key = ?", key)
+	return err
+}
+
+func (b *SQLiteBackend) Keys() ([]string, error) {
+	rows, err := b.db.Query("SELECT key FROM objects")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Close closes the database of the backend.
+func (b *SQLiteBackend) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,318 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestIndexer(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	indexer, err := NewIndexer(
+		backend,
+		1,
+		func() runtime.Object { return &workv1.ManifestWork{} },
+		cache.MetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	works := []*workv1.ManifestWork{
+		newWork("cluster1", "work1"),
+		newWork("cluster1", "work2"),
+		newWork("cluster2", "work1"),
+	}
+	for _, work := range works {
+		if err := indexer.Add(work); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	keys, err := backend.Keys()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(keys) != 3 {
+		t.Errorf("expected 3 keys in backend, but got %v", keys)
+	}
+
+	// the work is evicted from the hot cache, it should be read back from the backend
+	obj, exists, err := indexer.GetByKey("cluster1/work1")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !exists || obj.(*workv1.ManifestWork).Name != "work1" {
+		t.Errorf("expected work cluster1/work1, but got %v", obj)
+	}
+
+	objs, err := indexer.ByIndex(cache.NamespaceIndex, "cluster1")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(objs) != 2 {
+		t.Errorf("expected 2 works in cluster1, but got %v", objs)
+	}
+
+	if err := indexer.Delete(works[0]); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, exists, _ := indexer.GetByKey("cluster1/work1"); exists {
+		t.Errorf("expected work cluster1/work1 is deleted")
+	}
+
+	objs, err = indexer.ByIndex(cache.NamespaceIndex, "cluster1")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(objs) != 1 {
+		t.Errorf("expected 1 work in cluster1, but got %v", objs)
+	}
+
+	if err := indexer.Replace([]interface{}{newWork("cluster3", "work1")}, ""); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if keys := indexer.ListKeys(); len(keys) != 1 || keys[0] != "cluster3/work1" {
+		t.Errorf("expected only cluster3/work1, but got %v", keys)
+	}
+}
+
+func TestManifestWorkInformer(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	workClient := fakeworkclient.NewSimpleClientset(newWork("cluster1", "work1"))
+	informer, err := NewManifestWorkInformer(workClient, backend, 10, metav1.NamespaceAll, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	added := make(chan string, 10)
+	if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			added <- obj.(*workv1.ManifestWork).Name
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go informer.Informer().Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.Informer().HasSynced) {
+		t.Fatalf("failed to sync informer")
+	}
+
+	if _, err := workClient.WorkV1().ManifestWorks("cluster2").Create(
+		ctx, newWork("cluster2", "work2"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			_, err := informer.Lister().ManifestWorks("cluster2").Get("work2")
+			return err == nil, nil
+		}); err != nil {
+		t.Errorf("expected work cluster2/work2 is cached, %v", err)
+	}
+
+	// the handlers are notified asynchronously
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			return len(added) == 2, nil
+		}); err != nil {
+		t.Errorf("expected 2 add events, but got %d", len(added))
+	}
+}
+
+func TestManifestWorkInformerHandlers(t *testing.T) {
+	backend, err := NewBoltBackend(filepath.Join(t.TempDir(), "works.db"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer backend.Close()
+
+	works := []runtime.Object{}
+	for i := 0; i < 100; i++ {
+		works = append(works, newWork("cluster1", fmt.Sprintf("work%d", i)))
+	}
+
+	workClient := fakeworkclient.NewSimpleClientset(works...)
+	informer, err := NewManifestWorkInformer(workClient, backend, 10, metav1.NamespaceAll, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// a blocked handler does not block the informer and the other handlers
+	blocked := make(chan struct{})
+	defer close(blocked)
+	if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { <-blocked },
+	}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go informer.Informer().Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.Informer().HasSynced) {
+		t.Fatalf("failed to sync informer")
+	}
+
+	// update the works while a new handler is added, the handler gets the add event of a work before its updates
+	go func() {
+		for i := 0; i < 100; i++ {
+			work := newWork("cluster1", fmt.Sprintf("work%d", i))
+			work.Labels = map[string]string{"updated": "true"}
+			if _, err := workClient.WorkV1().ManifestWorks("cluster1").Update(
+				ctx, work, metav1.UpdateOptions{}); err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		}
+	}()
+
+	var lock sync.Mutex
+	added := sets.New[string]()
+	updated := sets.New[string]()
+	registration, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			lock.Lock()
+			defer lock.Unlock()
+			added.Insert(obj.(*workv1.ManifestWork).Name)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			lock.Lock()
+			defer lock.Unlock()
+			name := obj.(*workv1.ManifestWork).Name
+			if !added.Has(name) {
+				t.Errorf("expected the work %s is added before it is updated", name)
+			}
+			updated.Insert(name)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), registration.HasSynced) {
+		t.Fatalf("failed to sync handler")
+	}
+
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return added.Len() == 100 && updated.Len() == 100, nil
+		}); err != nil {
+		t.Errorf("expected 100 added and updated works, but got %d and %d", added.Len(), updated.Len())
+	}
+}
+
+// closableBackend is a Backend whose database is closed and reopened in the tests.
+type closableBackend interface {
+	Backend
+	Close() error
+}
+
+func TestDatabaseBackends(t *testing.T) {
+	cases := []struct {
+		name        string
+		openBackend func(path string) (closableBackend, error)
+	}{
+		{
+			name:        "bolt",
+			openBackend: func(path string) (closableBackend, error) { return NewBoltBackend(path) },
+		},
+		{
+			name:        "sqlite",
+			openBackend: func(path string) (closableBackend, error) { return NewSQLiteBackend(path) },
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "works.db")
+			backend, err := c.openBackend(path)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if err := backend.Put("cluster1/work1", []byte("work1")); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err := backend.Put("cluster1/work2", []byte("work2")); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err := backend.Delete("cluster1/work2"); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err := backend.Delete("cluster1/work3"); err != nil {
+				t.Errorf("expected deleting a nonexistent key is not an error, but got %v", err)
+			}
+			if err := backend.Close(); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			// the objects are kept after the backend is reopened
+			backend, err = c.openBackend(path)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			defer backend.Close()
+
+			data, exists, err := backend.Get("cluster1/work1")
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !exists || string(data) != "work1" {
+				t.Errorf("expected work1, but got %s", data)
+			}
+			if _, exists, _ := backend.Get("cluster1/work2"); exists {
+				t.Errorf("expected cluster1/work2 is deleted")
+			}
+
+			// the existing objects are not silently purged by the indexer
+			newObject := func() runtime.Object { return &workv1.ManifestWork{} }
+			if _, err := NewIndexer(backend, 1, newObject, cache.MetaNamespaceKeyFunc, nil); err == nil {
+				t.Errorf("expected an error for the existing objects, but got nil")
+			}
+			if err := PurgeBackend(backend); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if _, err := NewIndexer(backend, 1, newObject, cache.MetaNamespaceKeyFunc, nil); err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func newWork(namespace, name string) *workv1.ManifestWork {
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	workv1 "open-cluster-management.io/api/work/v1"
+	cloudeventstypes "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
 )
 
 func TestPatch(t *testing.T) {
@@ -84,3 +86,34 @@ func TestUID(t *testing.T) {
 		t.Errorf("expected two uid equal, but %v, %v", first, second)
 	}
 }
+
+func TestDeleteOption(t *testing.T) {
+	work := &workv1.ManifestWork{}
+	deleteOption, err := GetDeleteOption(work)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if deleteOption != nil {
+		t.Errorf("expected no delete option, but got %v", deleteOption)
+	}
+
+	ttlSeconds := int64(30)
+	SetDeleteOption(work, &cloudeventstypes.DeleteOption{
+		PropagationPolicy: string(workv1.DeletePropagationPolicyTypeOrphan),
+		TTLSeconds:        &ttlSeconds,
+	})
+	if work.Spec.DeleteOption.PropagationPolicy != workv1.DeletePropagationPolicyTypeOrphan {
+		t.Errorf("expected orphan policy, but got %v", work.Spec.DeleteOption)
+	}
+	if work.Annotations[common.CloudEventsDeleteTTLSecondsAnnotationKey] != "30" {
+		t.Errorf("expected ttl annotation, but got %v", work.Annotations)
+	}
+
+	deleteOption, err = GetDeleteOption(work)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if deleteOption.PropagationPolicy != string(workv1.DeletePropagationPolicyTypeOrphan) || *deleteOption.TTLSeconds != 30 {
+		t.Errorf("unexpected delete option %v", deleteOption)
+	}
+}
@@ -3,11 +3,13 @@ package utils
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/google/uuid"
 	"k8s.io/apimachinery/pkg/types"
 	workv1 "open-cluster-management.io/api/work/v1"
+	cloudeventstypes "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
 )
 
@@ -53,3 +55,50 @@ func UID(sourceID, namespace, name string) string {
 	id := fmt.Sprintf("%s-%s-%s-%s", sourceID, common.ManifestWorkGR.String(), namespace, name)
 	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(id)).String()
 }
+
+// GetDeleteOption returns the cloudevents delete option of a work with its delete option and delete TTL seconds
+// annotation, it returns nil if neither of them is set.
+func GetDeleteOption(work *workv1.ManifestWork) (*cloudeventstypes.DeleteOption, error) {
+	ttl, hasTTL := work.Annotations[common.CloudEventsDeleteTTLSecondsAnnotationKey]
+	if work.Spec.DeleteOption == nil && !hasTTL {
+		return nil, nil
+	}
+
+	deleteOption := &cloudeventstypes.DeleteOption{}
+	if work.Spec.DeleteOption != nil {
+		deleteOption.PropagationPolicy = string(work.Spec.DeleteOption.PropagationPolicy)
+	}
+
+	if hasTTL {
+		ttlSeconds, err := strconv.ParseInt(ttl, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the delete ttl seconds of the work %s, %v", work.UID, err)
+		}
+		deleteOption.TTLSeconds = &ttlSeconds
+	}
+
+	return deleteOption, nil
+}
+
+// SetDeleteOption sets the cloudevents delete option to a work, the propagation policy is set to the work delete
+// option and the TTL seconds is set to the work delete TTL seconds annotation.
+func SetDeleteOption(work *workv1.ManifestWork, deleteOption *cloudeventstypes.DeleteOption) {
+	if deleteOption == nil {
+		return
+	}
+
+	if len(deleteOption.PropagationPolicy) != 0 {
+		policy := workv1.DeletePropagationPolicyType(deleteOption.PropagationPolicy)
+		if work.Spec.DeleteOption == nil || work.Spec.DeleteOption.PropagationPolicy != policy {
+			// the selectively orphan resources are only kept with the selectively orphan policy
+			work.Spec.DeleteOption = &workv1.DeleteOption{PropagationPolicy: policy}
+		}
+	}
+
+	if deleteOption.TTLSeconds != nil {
+		if work.Annotations == nil {
+			work.Annotations = map[string]string{}
+		}
+		work.Annotations[common.CloudEventsDeleteTTLSecondsAnnotationKey] = strconv.FormatInt(*deleteOption.TTLSeconds, 10)
+	}
+}
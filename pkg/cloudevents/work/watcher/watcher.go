@@ -4,6 +4,8 @@ import (
 	"sync"
 
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/klog/v2"
 )
@@ -14,6 +16,12 @@ type ManifestWorkWatcher struct {
 
 	result chan watch.Event
 	done   chan struct{}
+	filter func(obj metav1.Object) bool
+
+	// accepted records the keys of the objects that are accepted by the filter, so the objects that no longer match
+	// the filter can be removed from the ManifestWorkInformer local cache.
+	acceptedLock sync.Mutex
+	accepted     sets.Set[string]
 }
 
 var _ watch.Interface = &ManifestWorkWatcher{}
@@ -28,7 +36,8 @@ func NewManifestWorkWatcher() *ManifestWorkWatcher {
 		// and the send operations on the result channel, especially the
 		// error reporting might block forever.
 		// Therefore a dedicated stop channel is used to resolve this blocking.
-		done: make(chan struct{}),
+		done:     make(chan struct{}),
+		accepted: sets.New[string](),
 	}
 
 	return mw
@@ -53,12 +62,56 @@ func (mw *ManifestWorkWatcher) Stop() {
 	}
 }
 
+// SetFilter sets a filter to the watcher, the events whose object is not accepted by the filter will be ignored, this
+// helps the ManifestWorkInformer only to cache the interested works. If an object that was accepted no longer matches
+// the filter, e.g. its labels are changed, a Deleted event is sent for it, so it is removed from the local cache.
+func (mw *ManifestWorkWatcher) SetFilter(filter func(obj metav1.Object) bool) {
+	mw.filter = filter
+}
+
 // Receive a event from the work client and sends down the result channel.
 func (mw *ManifestWorkWatcher) Receive(evt watch.Event) {
-	if klog.V(4).Enabled() {
-		obj, _ := meta.Accessor(evt.Object)
-		klog.V(4).Infof("Receive the event %v for %v", evt.Type, obj.GetName())
+	obj, err := meta.Accessor(evt.Object)
+	if err != nil {
+		klog.Errorf("failed to access the object of event %v, %v", evt.Type, err)
+		return
 	}
 
+	if mw.filter != nil {
+		filtered, ok := mw.filterEvent(evt, obj)
+		if !ok {
+			klog.V(4).Infof("The object %s/%s is filtered out, ignore the event %v",
+				obj.GetNamespace(), obj.GetName(), evt.Type)
+			return
+		}
+		evt = filtered
+	}
+
+	klog.V(4).Infof("Receive the event %v for %v", evt.Type, obj.GetName())
+
 	mw.result <- evt
 }
+
+// filterEvent returns the event that is sent down the result channel and whether the event is accepted by the filter.
+// The event of an object that was accepted before but no longer matches the filter is turned into a Deleted event.
+func (mw *ManifestWorkWatcher) filterEvent(evt watch.Event, obj metav1.Object) (watch.Event, bool) {
+	mw.acceptedLock.Lock()
+	defer mw.acceptedLock.Unlock()
+
+	key := obj.GetNamespace() + "/" + obj.GetName()
+	if !mw.filter(obj) {
+		if !mw.accepted.Has(key) || evt.Type == watch.Deleted {
+			return evt, false
+		}
+
+		mw.accepted.Delete(key)
+		return watch.Event{Type: watch.Deleted, Object: evt.Object}, true
+	}
+
+	if evt.Type == watch.Deleted {
+		mw.accepted.Delete(key)
+	} else {
+		mw.accepted.Insert(key)
+	}
+	return evt, true
+}
@@ -0,0 +1,102 @@
+package watcher
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestReceiveWithFilter(t *testing.T) {
+	mw := NewManifestWorkWatcher()
+	mw.SetFilter(func(obj metav1.Object) bool {
+		return obj.GetLabels()["app"] == "test"
+	})
+
+	events := make(chan watch.Event, 10)
+	go func() {
+		for evt := range mw.ResultChan() {
+			events <- evt
+		}
+	}()
+
+	newWork := func(name, app string) *workv1.ManifestWork {
+		return &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "cluster1",
+			Name:      name,
+			Labels:    map[string]string{"app": app},
+		}}
+	}
+
+	cases := []struct {
+		name         string
+		event        watch.Event
+		expectedType watch.EventType
+	}{
+		{
+			name:  "not matched work is ignored",
+			event: watch.Event{Type: watch.Modified, Object: newWork("work1", "other")},
+		},
+		{
+			name:         "matched work is added",
+			event:        watch.Event{Type: watch.Added, Object: newWork("work1", "test")},
+			expectedType: watch.Added,
+		},
+		{
+			name:         "matched work is modified",
+			event:        watch.Event{Type: watch.Modified, Object: newWork("work1", "test")},
+			expectedType: watch.Modified,
+		},
+		{
+			name:         "work no longer matches",
+			event:        watch.Event{Type: watch.Modified, Object: newWork("work1", "other")},
+			expectedType: watch.Deleted,
+		},
+		{
+			name:  "deleted work is ignored",
+			event: watch.Event{Type: watch.Modified, Object: newWork("work1", "other")},
+		},
+		{
+			name:         "work matches again",
+			event:        watch.Event{Type: watch.Modified, Object: newWork("work1", "test")},
+			expectedType: watch.Modified,
+		},
+		{
+			name:         "matched work is deleted",
+			event:        watch.Event{Type: watch.Deleted, Object: newWork("work1", "test")},
+			expectedType: watch.Deleted,
+		},
+		{
+			name:  "work no longer matches after it is deleted",
+			event: watch.Event{Type: watch.Modified, Object: newWork("work1", "other")},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mw.Receive(c.event)
+
+			// the events are received in order, so a marker event is used to check that nothing is sent
+			mw.Receive(watch.Event{Type: watch.Bookmark, Object: newWork("marker", "test")})
+
+			evt := <-events
+			if len(c.expectedType) == 0 {
+				if evt.Type != watch.Bookmark {
+					t.Errorf("expected no event, but got %v", evt.Type)
+					<-events
+				}
+				return
+			}
+
+			if evt.Type != c.expectedType {
+				t.Errorf("expected %v, but got %v", c.expectedType, evt.Type)
+			}
+			if evt.Object.(*workv1.ManifestWork).Name != "work1" {
+				t.Errorf("expected work1, but got %v", evt.Object)
+			}
+			<-events
+		})
+	}
+}
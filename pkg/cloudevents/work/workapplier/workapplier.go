@@ -0,0 +1,247 @@
+package workapplier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workv1lister "open-cluster-management.io/api/client/work/listers/work/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/conditions"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
+)
+
+const (
+	// OwnerAnnotationKey is the key of the annotation that records the owner of a manifestwork, e.g. the controller
+	// that applies the manifestwork.
+	OwnerAnnotationKey = "cloudevents.open-cluster-management.io/owner"
+
+	// SourceAnnotationKey is the key of the annotation that records the source that applies a manifestwork.
+	SourceAnnotationKey = "cloudevents.open-cluster-management.io/source"
+
+	// SpecHashAnnotationKey is the key of the annotation that records the hash of the spec, the labels and the
+	// annotations of an applied manifestwork, the manifestwork is not updated if the hash is not changed.
+	SpecHashAnnotationKey = "cloudevents.open-cluster-management.io/spec-hash"
+)
+
+const defaultAppliedCheckInterval = time.Second
+
+// WorkApplier creates or updates the manifestworks with the cloudevents work client on the source, it sets the
+// annotations that are required by the cloudevents work client, e.g. the data type and the generation annotations,
+// and skips the updates that do not change the manifestworks.
+type WorkApplier struct {
+	sourceID      string
+	owner         string
+	dataType      types.CloudEventsDataType
+	checkInterval time.Duration
+	workClient    workv1client.ManifestWorksGetter
+	workLister    workv1lister.ManifestWorkLister
+}
+
+// NewWorkApplier returns a WorkApplier with the given work client and lister, the work client should be a cloudevents
+// work client of the given source.
+func NewWorkApplier(sourceID string,
+	workClient workv1client.ManifestWorksGetter, workLister workv1lister.ManifestWorkLister) *WorkApplier {
+	return &WorkApplier{
+		sourceID:      sourceID,
+		dataType:      payload.ManifestBundleEventDataType,
+		checkInterval: defaultAppliedCheckInterval,
+		workClient:    workClient,
+		workLister:    workLister,
+	}
+}
+
+// NewWorkApplierFromClientHolder returns a WorkApplier with the work client and the informer lister of the given
+// source client holder.
+func NewWorkApplierFromClientHolder(sourceID string, clientHolder *work.ClientHolder) *WorkApplier {
+	return NewWorkApplier(sourceID,
+		clientHolder.WorkInterface().WorkV1(), clientHolder.ManifestWorkInformer().Lister())
+}
+
+// WithOwner sets the owner annotation of the applied manifestworks.
+func (a *WorkApplier) WithOwner(owner string) *WorkApplier {
+	a.owner = owner
+	return a
+}
+
+// WithDataType sets the cloudevents data type of the applied manifestworks if their data type annotation is not set,
+// by default, the manifestbundle data type is used.
+func (a *WorkApplier) WithDataType(dataType types.CloudEventsDataType) *WorkApplier {
+	a.dataType = dataType
+	return a
+}
+
+// WithAppliedCheckInterval sets the interval of checking the Applied condition in ApplyAndWait, by default, it is
+// one second.
+func (a *WorkApplier) WithAppliedCheckInterval(interval time.Duration) *WorkApplier {
+	a.checkInterval = interval
+	return a
+}
+
+// Apply creates the given manifestwork if it does not exist, otherwise, it patches the existing manifestwork with a
+// new generation if the spec, the labels or the annotations of the manifestwork are changed.
+func (a *WorkApplier) Apply(ctx context.Context, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+	requiredWork := manifestWork.DeepCopy()
+	if err := a.setAnnotations(requiredWork); err != nil {
+		return nil, err
+	}
+
+	existingWork, err := a.workLister.ManifestWorks(requiredWork.Namespace).Get(requiredWork.Name)
+	if errors.IsNotFound(err) {
+		if _, ok := requiredWork.Annotations[common.CloudEventsGenerationAnnotationKey]; !ok {
+			requiredWork.Annotations[common.CloudEventsGenerationAnnotationKey] = "1"
+		}
+
+		klog.V(4).Infof("creating manifestwork %s/%s", requiredWork.Namespace, requiredWork.Name)
+		return a.workClient.ManifestWorks(requiredWork.Namespace).Create(ctx, requiredWork, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if existingWork.Annotations[SpecHashAnnotationKey] == requiredWork.Annotations[SpecHashAnnotationKey] {
+		klog.V(4).Infof("manifestwork %s/%s is not changed, skip updating", requiredWork.Namespace, requiredWork.Name)
+		return existingWork.DeepCopy(), nil
+	}
+
+	requiredWork.Annotations[common.CloudEventsGenerationAnnotationKey] = strconv.FormatInt(existingWork.Generation+1, 10)
+
+	// keep the original source label that is added by the cloudevents work client
+	if source, ok := existingWork.Labels[common.CloudEventsOriginalSourceLabelKey]; ok {
+		if requiredWork.Labels == nil {
+			requiredWork.Labels = map[string]string{}
+		}
+		requiredWork.Labels[common.CloudEventsOriginalSourceLabelKey] = source
+	}
+
+	patch, err := mergePatch(existingWork, requiredWork)
+	if err != nil {
+		return nil, err
+	}
+
+	klog.V(4).Infof("patching manifestwork %s/%s with %s", requiredWork.Namespace, requiredWork.Name, string(patch))
+	return a.workClient.ManifestWorks(requiredWork.Namespace).Patch(
+		ctx, requiredWork.Name, kubetypes.MergePatchType, patch, metav1.PatchOptions{})
+}
+
+// ApplyAndWait applies the given manifestwork and waits until the manifestwork of the applied generation is applied
+// on the cluster, it returns an error if the Applied condition is not true before the timeout.
+func (a *WorkApplier) ApplyAndWait(ctx context.Context,
+	manifestWork *workv1.ManifestWork, timeout time.Duration) (*workv1.ManifestWork, error) {
+	appliedWork, err := a.Apply(ctx, manifestWork)
+	if err != nil {
+		return nil, err
+	}
+
+	generation := appliedWork.Generation
+	if err := wait.PollUntilContextTimeout(ctx, a.checkInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		appliedWork, err = a.workLister.ManifestWorks(manifestWork.Namespace).Get(manifestWork.Name)
+		if err != nil {
+			return false, err
+		}
+
+		return IsApplied(appliedWork, generation), nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to wait for manifestwork %s/%s to be applied, %v",
+			manifestWork.Namespace, manifestWork.Name, err)
+	}
+
+	return appliedWork.DeepCopy(), nil
+}
+
+// IsApplied returns true if the Applied condition of the given manifestwork is true and the condition is observed
+// with the given generation or a later generation.
+func IsApplied(manifestWork *workv1.ManifestWork, generation int64) bool {
+	return conditions.IsObservedTrue(manifestWork.Status.Conditions, workv1.WorkApplied, generation)
+}
+
+// SpecHash returns the hash of the spec, the labels and the annotations of the given manifestwork, the annotations
+// that are maintained by the WorkApplier are ignored.
+func SpecHash(manifestWork *workv1.ManifestWork) (string, error) {
+	annotations := map[string]string{}
+	for key, value := range manifestWork.Annotations {
+		if key == SpecHashAnnotationKey || key == common.CloudEventsGenerationAnnotationKey {
+			continue
+		}
+		annotations[key] = value
+	}
+
+	data, err := json.Marshal(&workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      manifestWork.Labels,
+			Annotations: annotations,
+		},
+		Spec: manifestWork.Spec,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal manifestwork %s/%s, %v", manifestWork.Namespace, manifestWork.Name, err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+func (a *WorkApplier) setAnnotations(manifestWork *workv1.ManifestWork) error {
+	if manifestWork.Annotations == nil {
+		manifestWork.Annotations = map[string]string{}
+	}
+
+	if _, ok := manifestWork.Annotations[common.CloudEventsDataTypeAnnotationKey]; !ok {
+		manifestWork.Annotations[common.CloudEventsDataTypeAnnotationKey] = a.dataType.String()
+	}
+
+	manifestWork.Annotations[SourceAnnotationKey] = a.sourceID
+	if len(a.owner) != 0 {
+		manifestWork.Annotations[OwnerAnnotationKey] = a.owner
+	}
+
+	specHash, err := SpecHash(manifestWork)
+	if err != nil {
+		return err
+	}
+
+	manifestWork.Annotations[SpecHashAnnotationKey] = specHash
+	return nil
+}
+
+// mergePatch creates a merge patch from the existing manifestwork to the required manifestwork, only the labels, the
+// annotations and the spec are patched.
+func mergePatch(existingWork, requiredWork *workv1.ManifestWork) ([]byte, error) {
+	oldData, err := json.Marshal(&workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      existingWork.Labels,
+			Annotations: existingWork.Annotations,
+		},
+		Spec: existingWork.Spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newData, err := json.Marshal(&workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      requiredWork.Labels,
+			Annotations: requiredWork.Annotations,
+		},
+		Spec: requiredWork.Spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonpatch.CreateMergePatch(oldData, newData)
+}
@@ -0,0 +1,203 @@
+package workapplier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	fakework "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workv1lister "open-cluster-management.io/api/client/work/listers/work/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/common"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
+)
+
+func TestApply(t *testing.T) {
+	cases := []struct {
+		name               string
+		existingWork       func() *workv1.ManifestWork
+		requiredWork       *workv1.ManifestWork
+		expectedActions    []string
+		expectedGeneration string
+	}{
+		{
+			name:               "create work",
+			existingWork:       func() *workv1.ManifestWork { return nil },
+			requiredWork:       newWork("test", "{\"data\":\"test\"}"),
+			expectedActions:    []string{"create"},
+			expectedGeneration: "1",
+		},
+		{
+			name: "the work is not changed",
+			existingWork: func() *workv1.ManifestWork {
+				return appliedWork(t, newWork("test", "{\"data\":\"test\"}"), 1)
+			},
+			requiredWork:       newWork("test", "{\"data\":\"test\"}"),
+			expectedActions:    []string{},
+			expectedGeneration: "1",
+		},
+		{
+			name: "the work is changed",
+			existingWork: func() *workv1.ManifestWork {
+				return appliedWork(t, newWork("test", "{\"data\":\"test\"}"), 1)
+			},
+			requiredWork:       newWork("test", "{\"data\":\"test-updated\"}"),
+			expectedActions:    []string{"patch"},
+			expectedGeneration: "2",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			objects := []runtime.Object{}
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if existingWork := c.existingWork(); existingWork != nil {
+				objects = append(objects, existingWork)
+				if err := indexer.Add(existingWork); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			workClient := fakework.NewSimpleClientset(objects...)
+			applier := NewWorkApplier("test-source", workClient.WorkV1(), workv1lister.NewManifestWorkLister(indexer)).
+				WithOwner("test-owner")
+
+			work, err := applier.Apply(context.TODO(), c.requiredWork)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			actions := workClient.Actions()
+			if len(actions) != len(c.expectedActions) {
+				t.Fatalf("expected %v actions, but got %v", c.expectedActions, actions)
+			}
+			for i, action := range actions {
+				if action.GetVerb() != c.expectedActions[i] {
+					t.Errorf("expected %s, but got %s", c.expectedActions[i], action.GetVerb())
+				}
+			}
+
+			expectedAnnotations := map[string]string{
+				common.CloudEventsDataTypeAnnotationKey:   payload.ManifestBundleEventDataType.String(),
+				common.CloudEventsGenerationAnnotationKey: c.expectedGeneration,
+				OwnerAnnotationKey:                        "test-owner",
+				SourceAnnotationKey:                       "test-source",
+			}
+			for key, value := range expectedAnnotations {
+				if work.Annotations[key] != value {
+					t.Errorf("expected %s=%s, but got %s", key, value, work.Annotations[key])
+				}
+			}
+		})
+	}
+}
+
+func TestApplyAndWait(t *testing.T) {
+	existingWork := appliedWork(t, newWork("test", "{\"data\":\"test\"}"), 1)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(existingWork); err != nil {
+		t.Fatal(err)
+	}
+
+	applier := NewWorkApplier("test-source",
+		fakework.NewSimpleClientset(existingWork).WorkV1(), workv1lister.NewManifestWorkLister(indexer)).
+		WithOwner("test-owner").WithAppliedCheckInterval(10 * time.Millisecond)
+
+	// the work is not applied yet
+	if _, err := applier.ApplyAndWait(context.TODO(), newWork("test", "{\"data\":\"test\"}"), 50*time.Millisecond); err == nil {
+		t.Errorf("expected error, but got nil")
+	}
+
+	appliedWork := existingWork.DeepCopy()
+	appliedWork.Status.Conditions = []metav1.Condition{{
+		Type:               workv1.WorkApplied,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: 1,
+	}}
+	if err := indexer.Update(appliedWork); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := applier.ApplyAndWait(context.TODO(), newWork("test", "{\"data\":\"test\"}"), time.Second); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestIsApplied(t *testing.T) {
+	cases := []struct {
+		name       string
+		conditions []metav1.Condition
+		generation int64
+		expected   bool
+	}{
+		{
+			name:       "no applied condition",
+			generation: 1,
+			expected:   false,
+		},
+		{
+			name: "the applied condition is observed with an old generation",
+			conditions: []metav1.Condition{
+				{Type: workv1.WorkApplied, Status: metav1.ConditionTrue, ObservedGeneration: 1},
+			},
+			generation: 2,
+			expected:   false,
+		},
+		{
+			name: "the applied condition is false",
+			conditions: []metav1.Condition{
+				{Type: workv1.WorkApplied, Status: metav1.ConditionFalse, ObservedGeneration: 2},
+			},
+			generation: 2,
+			expected:   false,
+		},
+		{
+			name: "the work is applied",
+			conditions: []metav1.Condition{
+				{Type: workv1.WorkApplied, Status: metav1.ConditionTrue, ObservedGeneration: 2},
+			},
+			generation: 2,
+			expected:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work := &workv1.ManifestWork{Status: workv1.ManifestWorkStatus{Conditions: c.conditions}}
+			if applied := IsApplied(work, c.generation); applied != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, applied)
+			}
+		})
+	}
+}
+
+func newWork(name, data string) *workv1.ManifestWork {
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "cluster1",
+		},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: []byte(data)}}},
+			},
+		},
+	}
+}
+
+// appliedWork returns the work that is applied by the WorkApplier with the given generation
+func appliedWork(t *testing.T, work *workv1.ManifestWork, generation int64) *workv1.ManifestWork {
+	applier := NewWorkApplier("test-source", nil, nil).WithOwner("test-owner")
+	if err := applier.setAnnotations(work); err != nil {
+		t.Fatal(err)
+	}
+
+	work.Generation = generation
+	work.Annotations[common.CloudEventsGenerationAnnotationKey] = "1"
+	return work
+}
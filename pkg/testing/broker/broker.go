@@ -0,0 +1,17 @@
+// Package broker provides the embedded MQTT and gRPC brokers for the integration tests of the cloudevents sources and
+// agents, e.g.
+//
+//	mqttBroker := broker.StartMQTTBroker(t)
+//	sourceClient, err := generic.NewCloudEventSourceClient(ctx, mqttBroker.SourceOptions("source1"), ...)
+//	agentClient, err := generic.NewCloudEventAgentClient(ctx, mqttBroker.AgentOptions("source1", "cluster1"), ...)
+//
+// The brokers listen on the random local ports, so the tests can run in parallel.
+package broker
+
+// TestingT is the subset of the testing.TB that is used to start the brokers, it is implemented by the *testing.T and
+// the ginkgo.GinkgoT().
+type TestingT interface {
+	Helper()
+	Fatalf(format string, args ...any)
+	Cleanup(func())
+}
@@ -0,0 +1,110 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+func TestBrokers(t *testing.T) {
+	mqttBroker := StartMQTTBroker(t)
+	grpcBroker := StartGRPCBroker(t)
+
+	cases := []struct {
+		name          string
+		sourceOptions *options.CloudEventsSourceOptions
+		agentOptions  *options.CloudEventsAgentOptions
+	}{
+		{
+			name:          "mqtt",
+			sourceOptions: mqttBroker.SourceOptions("source1"),
+			agentOptions:  mqttBroker.AgentOptions("source1", "cluster1"),
+		},
+		{
+			name:          "grpc",
+			sourceOptions: grpcBroker.SourceOptions("source1"),
+			agentOptions:  grpcBroker.AgentOptions("cluster1"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			agentClient, err := c.agentOptions.CloudEventsOptions.Client(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			received := make(chan cloudevents.Event, 10)
+			go func() {
+				_ = agentClient.StartReceiver(ctx, func(evt cloudevents.Event) {
+					received <- evt
+				})
+			}()
+
+			sourceClient, err := c.sourceOptions.CloudEventsOptions.Client(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			evt := types.NewEventBuilder("source1", types.CloudEventsType{
+				CloudEventsDataType: types.CloudEventsDataType{Group: "test", Version: "v1", Resource: "tests"},
+				SubResource:         types.SubResourceSpec,
+				Action:              "create_request",
+			}).WithResourceID("test").WithClusterName("cluster1").NewEvent()
+
+			sendingCtx, err := c.sourceOptions.CloudEventsOptions.WithContext(ctx, evt.Context)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			// the agent may not subscribe the topics yet, send the event until it is received
+			err = wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 10*time.Second, true,
+				func(ctx context.Context) (bool, error) {
+					if result := sourceClient.Send(sendingCtx, evt); cloudevents.IsUndelivered(result) {
+						return false, nil
+					}
+
+					select {
+					case actual := <-received:
+						return actual.ID() == evt.ID(), nil
+					case <-time.After(100 * time.Millisecond):
+						return false, nil
+					}
+				})
+			if err != nil {
+				t.Errorf("expected the event %s is received, but got %v", evt.ID(), err)
+			}
+		})
+	}
+}
+
+func TestTopicMatches(t *testing.T) {
+	cases := []struct {
+		subscribed string
+		published  string
+		expected   bool
+	}{
+		{"sources/+/clusters/cluster1/spec", "sources/source1/clusters/cluster1/spec", true},
+		{"sources/+/clusters/cluster1/spec", "sources/source1/clusters/cluster2/spec", false},
+		{"sources/source1/clusters/+/status", "sources/source1/clusters/cluster1/status", true},
+		{"sources/clusters/+/specresync", "sources/clusters/cluster1/specresync", true},
+		{"sources/#", "sources/source1/clusters/cluster1/status", true},
+		{"sources/+", "sources/source1/clusters", false},
+		{"sources/+/clusters", "sources/source1", false},
+	}
+
+	for _, c := range cases {
+		if actual := topicMatches(c.subscribed, c.published); actual != c.expected {
+			t.Errorf("expected %v for %s and %s, but got %v", c.expected, c.subscribed, c.published, actual)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	grpcoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
+	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
+)
+
+// GRPCBroker is an embedded gRPC broker that implements the CloudEventService, the published events are forwarded to
+// all the subscriptions whose topics match the published topics. The `+` of a subscribed topic matches a single topic
+// level and the `#` matches the remaining topic levels.
+type GRPCBroker struct {
+	pbv1.UnimplementedCloudEventServiceServer
+
+	mu            sync.RWMutex
+	subscriptions map[*subscription]struct{}
+	server        *grpc.Server
+
+	// Host is the address of the broker, e.g. 127.0.0.1:8881.
+	Host string
+}
+
+type subscription struct {
+	topic  string
+	events chan *pbv1.CloudEvent
+	done   chan struct{}
+}
+
+// StartGRPCBroker starts an embedded gRPC broker on a random local port, the broker is stopped when the test finishes.
+func StartGRPCBroker(t TestingT) *GRPCBroker {
+	t.Helper()
+
+	b, err := NewGRPCBroker("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start the gRPC broker, %v", err)
+	}
+	t.Cleanup(b.Close)
+
+	return b
+}
+
+// NewGRPCBroker starts an embedded gRPC broker on the given address, the port of the address can be 0 to use a random
+// port, the broker must be closed by the caller.
+func NewGRPCBroker(addr string) (*GRPCBroker, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &GRPCBroker{
+		subscriptions: map[*subscription]struct{}{},
+		server:        grpc.NewServer(),
+		Host:          lis.Addr().String(),
+	}
+	pbv1.RegisterCloudEventServiceServer(b.server, b)
+
+	go func() {
+		_ = b.server.Serve(lis)
+	}()
+
+	return b, nil
+}
+
+// Close stops the broker and closes all the subscriptions.
+func (b *GRPCBroker) Close() {
+	b.server.Stop()
+}
+
+// Options returns the gRPC options to connect the broker.
+func (b *GRPCBroker) Options() *grpcoptions.GRPCOptions {
+	grpcOptions := grpcoptions.NewGRPCOptions()
+	grpcOptions.URL = b.Host
+	return grpcOptions
+}
+
+// SourceOptions returns the cloudevents options of a source that connects the broker.
+func (b *GRPCBroker) SourceOptions(sourceID string) *options.CloudEventsSourceOptions {
+	return grpcoptions.NewSourceOptions(b.Options(), sourceID)
+}
+
+// AgentOptions returns the cloudevents options of an agent that connects the broker.
+func (b *GRPCBroker) AgentOptions(clusterName string) *options.CloudEventsAgentOptions {
+	return grpcoptions.NewAgentOptions(b.Options(), clusterName, fmt.Sprintf("%s-agent", clusterName))
+}
+
+func (b *GRPCBroker) Publish(ctx context.Context, pubReq *pbv1.PublishRequest) (*emptypb.Empty, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscriptions {
+		if !topicMatches(sub.topic, pubReq.Topic) {
+			continue
+		}
+
+		select {
+		case sub.events <- pubReq.Event:
+		case <-sub.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (b *GRPCBroker) Subscribe(subReq *pbv1.SubscriptionRequest, subServer pbv1.CloudEventService_SubscribeServer) error {
+	sub := &subscription{
+		topic:  subReq.Topic,
+		events: make(chan *pbv1.CloudEvent, 100),
+		done:   make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	b.subscriptions[sub] = struct{}{}
+	b.mu.Unlock()
+
+	defer func() {
+		close(sub.done)
+
+		b.mu.Lock()
+		delete(b.subscriptions, sub)
+		b.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-subServer.Context().Done():
+			return nil
+		case evt := <-sub.events:
+			if err := subServer.Send(evt); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// topicMatches returns true if the published topic matches the subscribed topic.
+func topicMatches(subscribed, published string) bool {
+	subscribedLevels := strings.Split(subscribed, "/")
+	publishedLevels := strings.Split(published, "/")
+
+	for i, level := range subscribedLevels {
+		if level == "#" {
+			return true
+		}
+
+		if i >= len(publishedLevels) {
+			return false
+		}
+
+		if level != "+" && level != publishedLevels[i] {
+			return false
+		}
+	}
+
+	return len(subscribedLevels) == len(publishedLevels)
+}
@@ -0,0 +1,101 @@
+package broker
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	mochimqtt "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// MQTTBroker is an embedded MQTT broker that allows all the connections.
+type MQTTBroker struct {
+	server *mochimqtt.Server
+
+	// Host is the address of the broker, e.g. 127.0.0.1:1883.
+	Host string
+}
+
+// StartMQTTBroker starts an embedded MQTT broker on a random local port, the broker is closed when the test finishes.
+func StartMQTTBroker(t TestingT) *MQTTBroker {
+	t.Helper()
+
+	b, err := NewMQTTBroker("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start the MQTT broker, %v", err)
+	}
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	return b
+}
+
+// NewMQTTBroker starts an embedded MQTT broker on the given address, the port of the address can be 0 to use a random
+// port, the broker must be closed by the caller.
+func NewMQTTBroker(addr string) (*MQTTBroker, error) {
+	server := mochimqtt.New(&mochimqtt.Options{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// allow all connections.
+	if err := server.AddHook(new(auth.AllowHook), nil); err != nil {
+		return nil, err
+	}
+
+	listener := listeners.NewTCP("mqtt-test-broker", addr, nil)
+	if err := server.AddListener(listener); err != nil {
+		return nil, err
+	}
+
+	if err := server.Serve(); err != nil {
+		return nil, err
+	}
+
+	return &MQTTBroker{server: server, Host: listener.Address()}, nil
+}
+
+// Close stops the broker and disconnects all the clients.
+func (b *MQTTBroker) Close() error {
+	return b.server.Close()
+}
+
+// Options returns the MQTT options to connect the broker with the given topics.
+func (b *MQTTBroker) Options(topics types.Topics) *mqtt.MQTTOptions {
+	return &mqtt.MQTTOptions{
+		BrokerHost:  b.Host,
+		KeepAlive:   60,
+		PubQoS:      1,
+		SubQoS:      1,
+		DialTimeout: 5 * time.Second,
+		Topics:      topics,
+	}
+}
+
+// SourceOptions returns the cloudevents options of a source that connects the broker with the Topics of the source.
+func (b *MQTTBroker) SourceOptions(sourceID string) *options.CloudEventsSourceOptions {
+	return mqtt.NewSourceOptions(b.Options(Topics(sourceID)), fmt.Sprintf("%s-client", sourceID), sourceID)
+}
+
+// AgentOptions returns the cloudevents options of an agent that connects the broker with the Topics of the source.
+func (b *MQTTBroker) AgentOptions(sourceID, clusterName string) *options.CloudEventsAgentOptions {
+	return mqtt.NewAgentOptions(b.Options(Topics(sourceID)), clusterName, fmt.Sprintf("%s-agent", clusterName))
+}
+
+// Topics returns the MQTT topics that a source and its agents use to exchange the events, including the broadcast
+// topics of the resync requests.
+func Topics(sourceID string) types.Topics {
+	return types.Topics{
+		SourceEvents:    fmt.Sprintf("sources/%s/consumers/+/sourceevents", sourceID),
+		AgentEvents:     fmt.Sprintf("sources/%s/consumers/+/agentevents", sourceID),
+		SourceBroadcast: "sources/+/sourcebroadcast",
+		AgentBroadcast:  "clusters/+/agentbroadcast",
+	}
+}
@@ -142,6 +142,47 @@ var _ = ginkgo.Describe("ManifestWork source client test", func() {
 				}, 10*time.Second, 1*time.Second).Should(gomega.Succeed())
 			})
 
+			ginkgo.By("source update the work with the work clientset", func() {
+				workClient := sourceClientHolder.WorkInterface().WorkV1().ManifestWorks(clusterName)
+				gomega.Eventually(func() error {
+					work, err := workClient.Get(context.TODO(), workName, metav1.GetOptions{})
+					if err != nil {
+						return err
+					}
+
+					// ensure the resource status is synced
+					if !meta.IsStatusConditionTrue(work.Status.Conditions, "Updated") {
+						return fmt.Errorf("unexpected status %v", work.Status.Conditions)
+					}
+
+					// update the work without increasing the generation annotation
+					work.Spec.Workload.Manifests = append(work.Spec.Workload.Manifests, newManifest("test3"))
+					updatedWork, err := workClient.Update(context.TODO(), work, metav1.UpdateOptions{})
+					gomega.Expect(err).ToNot(gomega.HaveOccurred())
+					gomega.Expect(updatedWork.Generation).To(gomega.Equal(int64(3)))
+
+					works, err := workClient.List(context.TODO(), metav1.ListOptions{})
+					gomega.Expect(err).ToNot(gomega.HaveOccurred())
+					gomega.Expect(len(works.Items)).To(gomega.Equal(1))
+
+					return nil
+				}, 10*time.Second, 1*time.Second).Should(gomega.Succeed())
+
+				gomega.Eventually(func() error {
+					workID := utils.UID(sourceID, clusterName, workName)
+					work, err := agentClientHolder.ManifestWorks(clusterName).Get(context.TODO(), workID, metav1.GetOptions{})
+					if err != nil {
+						return err
+					}
+
+					if len(work.Spec.Workload.Manifests) != 3 {
+						return fmt.Errorf("unexpected work spec %v", work.Spec.Workload.Manifests)
+					}
+
+					return nil
+				}, 10*time.Second, 1*time.Second).Should(gomega.Succeed())
+			})
+
 			ginkgo.By("source mark the work is deleting", func() {
 				gomega.Eventually(func() error {
 					work, err := sourceClientHolder.ManifestWorks(clusterName).Get(context.TODO(), workName, metav1.GetOptions{})
@@ -172,7 +172,7 @@ func StartGRPCResourceSourceClient(ctx context.Context, config *grpc.GRPCOptions
 		return nil, err
 	}
 
-	client.Subscribe(context.TODO(), func(action types.ResourceAction, resource *Resource) error {
+	client.Subscribe(ctx, func(action types.ResourceAction, resource *Resource) error {
 		return consumerStore.UpdateStatus(resource)
 	})
 
@@ -4,11 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
-	"time"
 
-	mochimqtt "github.com/mochi-mqtt/server/v2"
-	"github.com/mochi-mqtt/server/v2/hooks/auth"
-	"github.com/mochi-mqtt/server/v2/listeners"
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
 
@@ -16,14 +12,14 @@ import (
 	grpcoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/testing/broker"
 	"open-cluster-management.io/sdk-go/test/integration/cloudevents/source"
 )
 
-const mqttBrokerHost = "127.0.0.1:1883"
 const grpcServerHost = "127.0.0.1:8881"
 const sourceID = "integration-test"
 
-var mqttBroker *mochimqtt.Server
+var mqttBroker *broker.MQTTBroker
 var mqttOptions *mqtt.MQTTOptions
 var mqttSourceCloudEventsClient generic.CloudEventsClient[*source.Resource]
 var grpcServer *source.GRPCServer
@@ -43,18 +39,7 @@ var _ = ginkgo.BeforeSuite(func(done ginkgo.Done) {
 	ctx := context.TODO()
 
 	// start a MQTT broker
-	mqttBroker = mochimqtt.New(&mochimqtt.Options{})
-	// allow all connections.
-	err := mqttBroker.AddHook(new(auth.AllowHook), nil)
-	gomega.Expect(err).ToNot(gomega.HaveOccurred())
-
-	err = mqttBroker.AddListener(listeners.NewTCP("mqtt-test-broker", mqttBrokerHost, nil))
-	gomega.Expect(err).ToNot(gomega.HaveOccurred())
-
-	go func() {
-		err := mqttBroker.Serve()
-		gomega.Expect(err).ToNot(gomega.HaveOccurred())
-	}()
+	mqttBroker = broker.StartMQTTBroker(ginkgo.GinkgoT())
 
 	ginkgo.By("init the event hub")
 	eventHub = source.NewEventHub()
@@ -75,6 +60,7 @@ var _ = ginkgo.BeforeSuite(func(done ginkgo.Done) {
 	ginkgo.By("start the resource grpc source client")
 	grpcOptions = grpcoptions.NewGRPCOptions()
 	grpcOptions.URL = grpcServerHost
+	var err error
 	grpcSourceCloudEventsClient, err = source.StartGRPCResourceSourceClient(ctx, grpcOptions)
 	gomega.Expect(err).ToNot(gomega.HaveOccurred())
 
@@ -98,12 +84,5 @@ var _ = ginkgo.AfterSuite(func() {
 })
 
 func newMQTTOptions(topics types.Topics) *mqtt.MQTTOptions {
-	return &mqtt.MQTTOptions{
-		BrokerHost:  mqttBrokerHost,
-		KeepAlive:   60,
-		PubQoS:      1,
-		SubQoS:      1,
-		DialTimeout: 5 * time.Second,
-		Topics:      topics,
-	}
+	return mqttBroker.Options(topics)
 }